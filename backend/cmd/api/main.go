@@ -36,6 +36,7 @@ ARCHITECTURE:
     main() → LoadConfig → SetupLogger → ConnectDB → InitServices → StartServer
                                                                         ↓
     ShutdownServer ← WaitForSignal ← ListenAndServe ← setupRouter()
+                                                      ↖ SchedulerService.Start (periodic jobs)
 
 DEPENDENCIES:
     External:
@@ -137,7 +138,7 @@ import (
 
     // Initialize services
     authService := services.NewAuthService(db, cfg)
-    employeeService := services.NewEmployeeService(db)
+    employeeService := services.NewEmployeeService(db, cfg)
     payrollService := services.NewPayrollService(db, cfg)
     payrollPeriodService := services.NewPayrollPeriodService(db)
 
@@ -151,6 +152,19 @@ import (
 
     // Setup router
     router := setupRouter(cfg, db, appLogger, authService, employeeService, payrollService)
+
+    // Start the job scheduler (escalation, SLA reminders, ledger runs,
+    // salary campaigns, assignments and reporting lines that came due, ...);
+    // replicas elect a leader through the database so jobs run once. With
+    // SCHEDULER_ENABLED=false on every replica none of these jobs run.
+    schedulerCtx, stopScheduler := context.WithCancel(context.Background())
+    defer stopScheduler()
+    if cfg.SchedulerEnabled {
+        scheduler := services.NewSchedulerService(db, cfg.SchedulerInstanceID)
+        scheduler.Register(services.DefaultScheduledJobs(db, cfg)...)
+        scheduler.Start(schedulerCtx)
+        appLogger.Infof("Job scheduler started as %s", cfg.SchedulerInstanceID)
+    }
     
     // Create server
     srv := &http.Server{
@@ -175,6 +189,7 @@ import (
     <-quit
     
     appLogger.Info("Shutting down server...")
+    stopScheduler()
     
     // Give outstanding requests 30 seconds to complete
     ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
@@ -0,0 +1,191 @@
+/*
+Package client - Attendance Terminal API Client
+
+==============================================================================
+FILE: cmd/attendance-tui/client/client.go
+==============================================================================
+
+DESCRIPTION:
+    HTTP client the terminal uses to talk to the backend: logs in with the
+    terminal's service account, refreshes the token when it expires, posts
+    swipe batches and enrolls cards with HR credentials.
+
+USER PERSPECTIVE:
+    - The terminal keeps working when the server is unreachable; swipes are
+      queued and the status bar shows "SIN CONEXIÓN"
+
+DEVELOPER GUIDELINES:
+    ✅  OK to modify: Timeouts, retry policy
+    ⚠️  CAUTION: ErrOffline means "try again later" (network error or 5xx);
+        an *APIError means the server refused the request and retrying the
+        same payload will not help
+    📝  baseURL includes the API prefix, e.g. http://server:8080/api/v1
+
+==============================================================================
+*/
+package client
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"backend/internal/dtos"
+	"backend/internal/models"
+)
+
+// ErrOffline is returned when the backend cannot be reached or is failing
+var ErrOffline = errors.New("sin conexión con el servidor")
+
+// APIError is a request the backend refused (4xx)
+type APIError struct {
+	StatusCode int
+	Message    string
+}
+
+func (e *APIError) Error() string {
+	return fmt.Sprintf("servidor respondió %d: %s", e.StatusCode, e.Message)
+}
+
+// Client is an authenticated backend client
+type Client struct {
+	baseURL  string
+	email    string
+	password string
+	http     *http.Client
+
+	mu           sync.Mutex
+	accessToken  string
+	refreshToken string
+}
+
+// New creates a client that authenticates as the given account
+func New(baseURL, email, password string) *Client {
+	return &Client{
+		baseURL:  strings.TrimRight(baseURL, "/"),
+		email:    email,
+		password: password,
+		http:     &http.Client{Timeout: 5 * time.Second},
+	}
+}
+
+// PostSwipes sends a batch of swipes and returns one result per swipe
+func (c *Client) PostSwipes(terminalID string, swipes []dtos.TerminalSwipeRequest) ([]dtos.TerminalSwipeResult, error) {
+	var resp dtos.TerminalSwipeBatchResponse
+	req := dtos.TerminalSwipeBatchRequest{TerminalID: terminalID, Swipes: swipes}
+	if err := c.authorized(http.MethodPost, "/attendance/terminal/swipes", req, &resp); err != nil {
+		return nil, err
+	}
+	if len(resp.Results) != len(swipes) {
+		return nil, fmt.Errorf("respuesta con %d resultados para %d registros", len(resp.Results), len(swipes))
+	}
+	return resp.Results, nil
+}
+
+// EnrollCard enrolls a card using a one-off HR session (the terminal
+// account cannot enroll cards)
+func (c *Client) EnrollCard(hrEmail, hrPassword string, req dtos.AttendanceCardRequest) (*models.AttendanceCard, error) {
+	var login dtos.LoginResponse
+	if err := c.do(http.MethodPost, "/auth/login", "", dtos.LoginRequest{Email: hrEmail, Password: hrPassword}, &login); err != nil {
+		return nil, err
+	}
+	var card models.AttendanceCard
+	if err := c.do(http.MethodPost, "/attendance/terminal/cards", login.AccessToken, req, &card); err != nil {
+		return nil, err
+	}
+	return &card, nil
+}
+
+// authorized runs a request with the terminal's token, refreshing or
+// logging in again once on 401
+func (c *Client) authorized(method, path string, body, out interface{}) error {
+	token, err := c.token(false)
+	if err != nil {
+		return err
+	}
+	err = c.do(method, path, token, body, out)
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusUnauthorized {
+		return err
+	}
+	if token, err = c.token(true); err != nil {
+		return err
+	}
+	return c.do(method, path, token, body, out)
+}
+
+// token returns the current access token, renewing it when asked to
+func (c *Client) token(renew bool) (string, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.accessToken != "" && !renew {
+		return c.accessToken, nil
+	}
+
+	var resp dtos.LoginResponse
+	if c.refreshToken != "" {
+		err := c.do(http.MethodPost, "/auth/refresh", "", dtos.RefreshTokenRequest{RefreshToken: c.refreshToken}, &resp)
+		if err == nil && resp.AccessToken != "" {
+			c.accessToken, c.refreshToken = resp.AccessToken, resp.RefreshToken
+			return c.accessToken, nil
+		}
+		if errors.Is(err, ErrOffline) {
+			return "", err
+		}
+	}
+	if err := c.do(http.MethodPost, "/auth/login", "", dtos.LoginRequest{Email: c.email, Password: c.password}, &resp); err != nil {
+		c.accessToken, c.refreshToken = "", ""
+		return "", err
+	}
+	c.accessToken, c.refreshToken = resp.AccessToken, resp.RefreshToken
+	return c.accessToken, nil
+}
+
+// do sends one JSON request and decodes the JSON response into out
+func (c *Client) do(method, path, token string, body, out interface{}) error {
+	payload, err := json.Marshal(body)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequest(method, c.baseURL+path, bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrOffline, err)
+	}
+	defer resp.Body.Close()
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrOffline, err)
+	}
+
+	switch {
+	case resp.StatusCode >= 500:
+		return fmt.Errorf("%w: servidor respondió %d", ErrOffline, resp.StatusCode)
+	case resp.StatusCode >= 400:
+		var body struct {
+			Error string `json:"error"`
+		}
+		if json.Unmarshal(data, &body) != nil || body.Error == "" {
+			body.Error = http.StatusText(resp.StatusCode)
+		}
+		return &APIError{StatusCode: resp.StatusCode, Message: body.Error}
+	}
+	if out == nil {
+		return nil
+	}
+	return json.Unmarshal(data, out)
+}
@@ -0,0 +1,104 @@
+/*
+Package main - IRIS Attendance Terminal
+
+==============================================================================
+FILE: cmd/attendance-tui/main.go
+==============================================================================
+
+DESCRIPTION:
+    Entry point of the NFC attendance terminal: a full-screen program that
+    reads cards from an ACR122U, posts each swipe to the backend as a
+    ClockRecord (source "terminal") and buffers swipes in a local SQLite
+    queue while the backend is unreachable.
+
+USER PERSPECTIVE:
+    - Runs unattended on the terminal PC, one instance per reader
+    - IT configures the backend URL, terminal ID and terminal account
+
+DEVELOPER GUIDELINES:
+    ✅  OK to modify: Flags, defaults
+    ⚠️  CAUTION: The terminal account needs the "terminal" role only
+    📝  Every flag falls back to an environment variable:
+          --api-url     ATTENDANCE_API_URL       (http://localhost:8080/api/v1)
+          --terminal-id ATTENDANCE_TERMINAL_ID   (hostname)
+          --email       ATTENDANCE_TERMINAL_EMAIL
+          --password    ATTENDANCE_TERMINAL_PASSWORD
+          --queue       ATTENDANCE_QUEUE_PATH    (attendance-queue.db)
+    📝  --fake replaces the reader with typed-in UIDs; build with
+        -tags nopcsc on machines without libpcsclite
+
+SYNTAX EXPLANATION:
+    - go run ./cmd/attendance-tui --fake --email terminal@empresa.mx ...
+
+==============================================================================
+*/
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"backend/cmd/attendance-tui/client"
+	"backend/cmd/attendance-tui/nfc"
+	"backend/cmd/attendance-tui/queue"
+	"backend/cmd/attendance-tui/service"
+	"backend/cmd/attendance-tui/ui"
+)
+
+func main() {
+	hostname, _ := os.Hostname()
+	apiURL := flag.String("api-url", env("ATTENDANCE_API_URL", "http://localhost:8080/api/v1"), "backend API base URL")
+	terminalID := flag.String("terminal-id", env("ATTENDANCE_TERMINAL_ID", hostname), "ID reported with every swipe")
+	email := flag.String("email", env("ATTENDANCE_TERMINAL_EMAIL", ""), "terminal account email")
+	password := flag.String("password", env("ATTENDANCE_TERMINAL_PASSWORD", ""), "terminal account password")
+	queuePath := flag.String("queue", env("ATTENDANCE_QUEUE_PATH", "attendance-queue.db"), "local offline queue database")
+	fake := flag.Bool("fake", false, "simulate the card reader (type UIDs)")
+	flag.Parse()
+
+	if *email == "" || *password == "" {
+		fail("se requieren --email y --password (o ATTENDANCE_TERMINAL_EMAIL / ATTENDANCE_TERMINAL_PASSWORD)")
+	}
+	if *terminalID == "" {
+		fail("se requiere --terminal-id")
+	}
+
+	var reader nfc.CardReader
+	if *fake {
+		reader = nfc.NewFakeReader()
+	} else {
+		hw, err := nfc.NewReader()
+		if err != nil {
+			fail(err.Error())
+		}
+		reader = hw
+	}
+	defer reader.Close()
+
+	swipeQueue, err := queue.Open(*queuePath)
+	if err != nil {
+		fail("no se pudo abrir la cola local: " + err.Error())
+	}
+	defer swipeQueue.Close()
+
+	svc := service.NewService(client.New(*apiURL, *email, *password), swipeQueue, *terminalID)
+	program := tea.NewProgram(ui.New(svc, reader), tea.WithAltScreen())
+	if _, err := program.Run(); err != nil {
+		fail(err.Error())
+	}
+}
+
+// env returns the environment variable or a default
+func env(key, fallback string) string {
+	if value := os.Getenv(key); value != "" {
+		return value
+	}
+	return fallback
+}
+
+func fail(message string) {
+	fmt.Fprintln(os.Stderr, "attendance-tui:", message)
+	os.Exit(1)
+}
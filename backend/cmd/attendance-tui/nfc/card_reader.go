@@ -0,0 +1,37 @@
+/*
+Package nfc - Attendance Terminal Card Readers
+
+==============================================================================
+FILE: cmd/attendance-tui/nfc/card_reader.go
+==============================================================================
+
+DESCRIPTION:
+    CardReader is what the terminal needs from a reader: block until a card
+    is presented and return its UID. Implementations are the ACR122U over
+    PC/SC (reader.go), a stub for builds without PC/SC (reader_stub.go) and
+    FakeReader for tests and demos without hardware (fake.go).
+
+USER PERSPECTIVE:
+    - A card held on the reader counts as one tap, not a stream of taps
+    - "--fake" runs the terminal with typed-in UIDs instead of a reader
+
+DEVELOPER GUIDELINES:
+    ✅  OK to modify: Add readers (keyboard-wedge, serial)
+    ⚠️  CAUTION: WaitForCard must return ErrClosed after Close so the read
+        loop can exit cleanly
+    📝  UIDs are upper-case hex without separators (backend NormalizeCardUID)
+
+==============================================================================
+*/
+package nfc
+
+import "errors"
+
+// ErrClosed is returned by WaitForCard once the reader has been closed
+var ErrClosed = errors.New("lector cerrado")
+
+// CardReader blocks until a card is presented and returns its UID
+type CardReader interface {
+	WaitForCard() (string, error)
+	Close() error
+}
@@ -0,0 +1,62 @@
+/*
+Package nfc - Attendance Terminal Card Readers
+
+==============================================================================
+FILE: cmd/attendance-tui/nfc/fake.go
+==============================================================================
+
+DESCRIPTION:
+    FakeReader simulates a card reader: every Tap is returned by the next
+    WaitForCard. Used by tests and by "--fake" to demo the terminal
+    without an ACR122U.
+
+DEVELOPER GUIDELINES:
+    ✅  OK to modify: Add scripted tap sequences
+    📝  Tap blocks until a WaitForCard takes the UID (or the reader closes)
+
+==============================================================================
+*/
+package nfc
+
+import (
+	"strings"
+	"sync"
+)
+
+// FakeReader is an in-memory CardReader
+type FakeReader struct {
+	taps chan string
+	done chan struct{}
+	once sync.Once
+}
+
+// NewFakeReader creates a fake reader
+func NewFakeReader() *FakeReader {
+	return &FakeReader{taps: make(chan string), done: make(chan struct{})}
+}
+
+// Tap presents a card with the given UID
+func (f *FakeReader) Tap(uid string) error {
+	select {
+	case f.taps <- strings.ToUpper(strings.TrimSpace(uid)):
+		return nil
+	case <-f.done:
+		return ErrClosed
+	}
+}
+
+// WaitForCard blocks until Tap is called or the reader is closed
+func (f *FakeReader) WaitForCard() (string, error) {
+	select {
+	case uid := <-f.taps:
+		return uid, nil
+	case <-f.done:
+		return "", ErrClosed
+	}
+}
+
+// Close unblocks pending WaitForCard and Tap calls
+func (f *FakeReader) Close() error {
+	f.once.Do(func() { close(f.done) })
+	return nil
+}
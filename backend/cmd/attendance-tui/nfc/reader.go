@@ -1,24 +1,54 @@
+//go:build !nopcsc
+
+/*
+Package nfc - Attendance Terminal Card Readers
+
+==============================================================================
+FILE: cmd/attendance-tui/nfc/reader.go
+==============================================================================
+
+DESCRIPTION:
+    ACR122U reader over PC/SC (libpcsclite / pcscd). WaitForCard blocks on
+    the reader's status until a card is placed, then reads its UID with the
+    GET DATA APDU. A card left on the reader is not read again until it is
+    removed.
+
+DEVELOPER GUIDELINES:
+    ⚠️  CAUTION: Requires cgo and libpcsclite; build with -tags nopcsc where
+        they are missing
+    📝  Close cancels a blocked WaitForCard (SCardCancel)
+
+SYNTAX EXPLANATION:
+    - FF CA 00 00 00: PC/SC pseudo-APDU returning the card UID + SW 90 00
+
+==============================================================================
+*/
 package nfc
 
 import (
 	"errors"
 	"fmt"
-	"github.com/ebfe/scard"
 	"strings"
+
+	"github.com/ebfe/scard"
 )
 
+// Reader is an ACR122U attached through PC/SC
 type Reader struct {
 	ctx        *scard.Context
 	readerName string
+	states     []scard.ReaderState
 }
 
+// NewReader connects to the first ACR122U found
 func NewReader() (*Reader, error) {
 	ctx, err := scard.EstablishContext()
 	if err != nil {
-		return nil, fmt.Errorf("Eror %v", err)
+		return nil, fmt.Errorf("Error iniciando PC/SC: %v", err)
 	}
 	readers, err := ctx.ListReaders()
 	if err != nil {
+		ctx.Release()
 		return nil, fmt.Errorf("No se encontraron lectores conectados: %v", err)
 	}
 	var readerName string
@@ -29,12 +59,36 @@ func NewReader() (*Reader, error) {
 		}
 	}
 	if readerName == "" {
+		ctx.Release()
 		return nil, errors.New("ACR122U no encontrado")
 	}
-	return &Reader{ctx: ctx, readerName: readerName}, nil
+	return &Reader{
+		ctx:        ctx,
+		readerName: readerName,
+		states:     []scard.ReaderState{{Reader: readerName, CurrentState: scard.StateUnaware}},
+	}, nil
 }
 
+// WaitForCard blocks until a card is placed on the reader and returns its UID
 func (r *Reader) WaitForCard() (string, error) {
+	for {
+		if err := r.ctx.GetStatusChange(r.states, -1); err != nil {
+			if errors.Is(err, scard.ErrCancelled) {
+				return "", ErrClosed
+			}
+			return "", fmt.Errorf("Error esperando tarjeta: %v", err)
+		}
+		wasPresent := r.states[0].CurrentState&scard.StatePresent != 0
+		present := r.states[0].EventState&scard.StatePresent != 0
+		r.states[0].CurrentState = r.states[0].EventState &^ scard.StateChanged
+		if present && !wasPresent {
+			return r.readUID()
+		}
+	}
+}
+
+// readUID reads the UID of the card currently on the reader
+func (r *Reader) readUID() (string, error) {
 	card, err := r.ctx.Connect(r.readerName, scard.ShareShared, scard.ProtocolAny)
 	if err != nil {
 		return "", fmt.Errorf("Error conectando: %v", err)
@@ -63,8 +117,10 @@ func (r *Reader) WaitForCard() (string, error) {
 	return uidhex, nil
 }
 
+// Close cancels a pending WaitForCard and releases the PC/SC context
 func (r *Reader) Close() error {
 	if r.ctx != nil {
+		r.ctx.Cancel()
 		return r.ctx.Release()
 	}
 	return nil
@@ -0,0 +1,36 @@
+//go:build nopcsc
+
+/*
+Package nfc - Attendance Terminal Card Readers
+
+==============================================================================
+FILE: cmd/attendance-tui/nfc/reader_stub.go
+==============================================================================
+
+DESCRIPTION:
+    Stand-in for the PC/SC reader in builds tagged "nopcsc" (machines
+    without libpcsclite, CI). NewReader always fails; use FakeReader.
+
+==============================================================================
+*/
+package nfc
+
+import "errors"
+
+// Reader is unavailable without PC/SC support
+type Reader struct{}
+
+// NewReader reports that this build has no PC/SC support
+func NewReader() (*Reader, error) {
+	return nil, errors.New("compilado sin soporte PC/SC (tag nopcsc); use --fake")
+}
+
+// WaitForCard always fails
+func (r *Reader) WaitForCard() (string, error) {
+	return "", ErrClosed
+}
+
+// Close does nothing
+func (r *Reader) Close() error {
+	return nil
+}
@@ -0,0 +1,130 @@
+/*
+Package queue - Attendance Terminal Offline Queue
+
+==============================================================================
+FILE: cmd/attendance-tui/queue/queue.go
+==============================================================================
+
+DESCRIPTION:
+    Local SQLite store of every swipe the terminal reads. A swipe is written
+    here before it is sent, so nothing is lost if the network or the
+    backend is down; the sync loop sends pending swipes oldest first and
+    stores the server's answer.
+
+USER PERSPECTIVE:
+    - Swipes made without network are kept on the terminal and sent later
+    - The status bar shows how many swipes are still pending
+
+DEVELOPER GUIDELINES:
+    ✅  OK to modify: Retention of synced rows, extra diagnostics
+    ⚠️  CAUTION: SwipeID is generated once, here, and resent unchanged on
+        every retry; the backend deduplicates by it
+    ❌  DO NOT modify: SwipedAt after enqueueing (it is the punch time)
+
+==============================================================================
+*/
+package queue
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+
+	"backend/internal/dtos"
+)
+
+// QueuedSwipe is a swipe read by this terminal
+type QueuedSwipe struct {
+	ID        uint      `gorm:"primaryKey"`
+	SwipeID   uuid.UUID `gorm:"type:text;uniqueIndex;not null"`
+	CardUID   string    `gorm:"not null"`
+	SwipedAt  time.Time `gorm:"not null;index"`
+	Attempts  int       `gorm:"default:0"`
+	LastError string
+	SyncedAt  *time.Time `gorm:"index"`
+	Status    string     // Server result: accepted, ignored, rejected
+	Direction string
+	Message   string
+	CreatedAt time.Time
+}
+
+// Request converts the queued swipe to the API payload
+func (q QueuedSwipe) Request() dtos.TerminalSwipeRequest {
+	return dtos.TerminalSwipeRequest{
+		SwipeID:  q.SwipeID,
+		CardUID:  q.CardUID,
+		SwipedAt: q.SwipedAt,
+		Offline:  q.Attempts > 0,
+	}
+}
+
+// Queue is the terminal's local swipe store
+type Queue struct {
+	db *gorm.DB
+}
+
+// Open opens (or creates) the queue database at path
+func Open(path string) (*Queue, error) {
+	db, err := gorm.Open(sqlite.Open(path), &gorm.Config{Logger: logger.Default.LogMode(logger.Silent)})
+	if err != nil {
+		return nil, err
+	}
+	if err := db.AutoMigrate(&QueuedSwipe{}); err != nil {
+		return nil, err
+	}
+	return &Queue{db: db}, nil
+}
+
+// Enqueue stores a new swipe with a fresh SwipeID
+func (q *Queue) Enqueue(cardUID string, swipedAt time.Time) (*QueuedSwipe, error) {
+	swipe := &QueuedSwipe{SwipeID: uuid.New(), CardUID: cardUID, SwipedAt: swipedAt}
+	if err := q.db.Create(swipe).Error; err != nil {
+		return nil, err
+	}
+	return swipe, nil
+}
+
+// Pending returns up to limit unsynced swipes, oldest first
+func (q *Queue) Pending(limit int) ([]QueuedSwipe, error) {
+	var swipes []QueuedSwipe
+	err := q.db.Where("synced_at IS NULL").Order("swiped_at, id").Limit(limit).Find(&swipes).Error
+	return swipes, err
+}
+
+// PendingCount returns how many swipes are waiting to be sent
+func (q *Queue) PendingCount() (int64, error) {
+	var count int64
+	err := q.db.Model(&QueuedSwipe{}).Where("synced_at IS NULL").Count(&count).Error
+	return count, err
+}
+
+// MarkSynced stores the server's result for a swipe
+func (q *Queue) MarkSynced(result dtos.TerminalSwipeResult) error {
+	now := time.Now()
+	return q.db.Model(&QueuedSwipe{}).Where("swipe_id = ?", result.SwipeID).Updates(map[string]interface{}{
+		"synced_at": &now,
+		"status":    result.Status,
+		"direction": result.Direction,
+		"message":   result.Message,
+	}).Error
+}
+
+// MarkFailed records a failed send attempt for the given swipes
+func (q *Queue) MarkFailed(swipeIDs []uuid.UUID, cause error) error {
+	return q.db.Model(&QueuedSwipe{}).Where("swipe_id IN ?", swipeIDs).Updates(map[string]interface{}{
+		"attempts":   gorm.Expr("attempts + 1"),
+		"last_error": cause.Error(),
+	}).Error
+}
+
+// Close closes the queue database
+func (q *Queue) Close() error {
+	sqlDB, err := q.db.DB()
+	if err != nil {
+		return err
+	}
+	return sqlDB.Close()
+}
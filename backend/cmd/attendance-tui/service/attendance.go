@@ -1,86 +1,249 @@
+/*
+Package service - Attendance Terminal Logic
+
+==============================================================================
+FILE: cmd/attendance-tui/service/attendance.go
+==============================================================================
+
+DESCRIPTION:
+    What happens when a card is read: the swipe is stored in the local
+    queue, then everything pending is sent to the backend oldest first.
+    The backend decides entrada/salida and creates the ClockRecord; when it
+    cannot be reached the swipe stays queued and the background sync sends
+    it later with its original time. Card enrollment goes straight to the
+    backend with HR credentials.
+
+USER PERSPECTIVE:
+    - ENTRADA / SALIDA / INICIO or FIN DE DESCANSO when the server answered
+      (the server infers the punch type from the employee's shift)
+    - PENDIENTE when offline: the punch is saved and sent on reconnection
+    - RECHAZADA for unknown or deactivated cards
+
+DEVELOPER GUIDELINES:
+    ✅  OK to modify: Screen texts, batch size
+    ⚠️  CAUTION: Always enqueue before sending; a swipe must survive a crash
+        or a power cut between the read and the server's answer
+    📝  Sends are serialized (mu) so the live swipe and the background sync
+        never post the same pending swipes concurrently
+
+==============================================================================
+*/
 package service
 
 import (
-	"backend/cmd/attendance-tui/models"
-	"github.com/google/uuid"
-	"gorm.io/gorm"
 	"errors"
+	"net/http"
+	"strings"
+	"sync"
+	"sync/atomic"
 	"time"
+
+	"github.com/google/uuid"
+
+	"backend/cmd/attendance-tui/client"
+	"backend/cmd/attendance-tui/queue"
+	"backend/internal/dtos"
+	"backend/internal/models"
+)
+
+// syncBatchSize is the number of queued swipes sent per request
+const syncBatchSize = 100
+
+// Swipe result types shown on screen
+const (
+	TypeIn         = "ENTRADA"
+	TypeOut        = "SALIDA"
+	TypeBreakStart = "INICIO DE DESCANSO"
+	TypeBreakEnd   = "FIN DE DESCANSO"
+	TypePending    = "PENDIENTE"
+	TypeRepeated   = "REPETIDO"
+	TypeRejected   = "RECHAZADA"
 )
 
+// serverMessages translates backend swipe messages for the screen
+var serverMessages = map[string]string{
+	"card not enrolled":      "Tarjeta no registrada",
+	"employee is not active": "Empleado inactivo",
+	"repeated tap":           "Registro repetido, ya quedó guardado",
+}
+
+// Service processes swipes for one terminal
 type Service struct {
-	db *gorm.DB
+	client     *client.Client
+	queue      *queue.Queue
+	terminalID string
+
+	mu     sync.Mutex
+	online atomic.Bool
 }
 
+// SwipeResult is what the terminal shows after a swipe
 type SwipeResult struct {
 	EmployeeName string
 	Type         string
+	Message      string
 	Time         time.Time
 }
 
-func NewService(db *gorm.DB) *Service {
-	return &Service{db: db}
+// NewService creates the terminal service
+func NewService(apiClient *client.Client, swipeQueue *queue.Queue, terminalID string) *Service {
+	return &Service{client: apiClient, queue: swipeQueue, terminalID: terminalID}
 }
 
-func (s *Service) RegisterCard(cardUID string, employeeID uuid.UUID) error {
-	card := models.AttendanceCard{
-		ID:         uuid.New(),
-		CardUID:    cardUID,
-		EmployeeID: employeeID,
-		IsActive:   true,
-	}
-	return s.db.Create(&card).Error
+// TerminalID returns the ID this terminal reports to the backend
+func (s *Service) TerminalID() string {
+	return s.terminalID
+}
+
+// Online reports whether the last request reached the backend
+func (s *Service) Online() bool {
+	return s.online.Load()
+}
+
+// PendingCount returns the swipes still waiting to be sent
+func (s *Service) PendingCount() int64 {
+	count, _ := s.queue.PendingCount()
+	return count
 }
 
+// ProcessSwipe queues a swipe and tries to send it right away
 func (s *Service) ProcessSwipe(cardUID string) (*SwipeResult, error) {
-	var card models.AttendanceCard
-	err := s.db.Preload("Employee").Where("card_uid = ?", cardUID).First(&card).Error
+	item, err := s.queue.Enqueue(cardUID, time.Now())
 	if err != nil {
-		return nil, errors.New("Tarjeta no registrada")
+		return nil, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	synced, err := s.sync()
+	if result, ok := synced[item.SwipeID]; ok {
+		return swipeResult(result), nil
 	}
 
-	if !card.IsActive {
-		return nil, errors.New("tarjeta desactivada")
+	message := "Sin conexión: el registro se enviará al reconectar"
+	if err != nil && !errors.Is(err, client.ErrOffline) {
+		message = "Registro guardado, pendiente de envío: " + err.Error()
 	}
-	now := time.Now()
-	today := time.Date(now.Year(), now.Month(), now.Day(), 0,0,0,0,now.Location())
-
-	var record models.AttendanceRecord
-	err = s.db.Where("employee_id = ? AND date = ?", card.EmployeeID, today).First(&record).Error
-	if errors.Is(err, gorm.ErrRecordNotFound) {
-		record = models.AttendanceRecord{
-			ID:	uuid.New(),
-			EmployeeID: card.EmployeeID,
-			CardUID: cardUID,
-			Date: today,
-			CheckIn: time.Now(),
-			Employee: card.Employee,
-			
+	return &SwipeResult{Type: TypePending, Message: message, Time: item.SwipedAt}, nil
+}
+
+// SyncPending sends every queued swipe and returns how many were synced
+func (s *Service) SyncPending() (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	synced, err := s.sync()
+	return len(synced), err
+}
+
+// sync sends pending swipes in batches until the queue is empty or a send fails
+func (s *Service) sync() (map[uuid.UUID]dtos.TerminalSwipeResult, error) {
+	synced := make(map[uuid.UUID]dtos.TerminalSwipeResult)
+	for {
+		pending, err := s.queue.Pending(syncBatchSize)
+		if err != nil || len(pending) == 0 {
+			return synced, err
+		}
+		results, err := s.send(pending)
+		for _, result := range results {
+			if markErr := s.queue.MarkSynced(result); markErr != nil {
+				return synced, markErr
+			}
+			synced[result.SwipeID] = result
+		}
+		if err != nil || len(pending) < syncBatchSize {
+			return synced, err
 		}
-		err = s.db.Create(&record).Error
+	}
+}
+
+// send posts a batch; if the backend refuses the batch, swipes are sent one
+// by one so a single bad swipe cannot hold back the rest of the queue
+func (s *Service) send(pending []queue.QueuedSwipe) ([]dtos.TerminalSwipeResult, error) {
+	requests := make([]dtos.TerminalSwipeRequest, len(pending))
+	ids := make([]uuid.UUID, len(pending))
+	for i, swipe := range pending {
+		requests[i], ids[i] = swipe.Request(), swipe.SwipeID
+	}
+
+	results, err := s.client.PostSwipes(s.terminalID, requests)
+	s.online.Store(!errors.Is(err, client.ErrOffline))
+	if err == nil {
+		return results, nil
+	}
+	var apiErr *client.APIError
+	if !errors.As(err, &apiErr) || keepQueued(apiErr.StatusCode) {
+		s.queue.MarkFailed(ids, err)
+		return nil, err
+	}
+	if len(pending) == 1 {
+		return []dtos.TerminalSwipeResult{{
+			SwipeID:  pending[0].SwipeID,
+			Status:   models.SwipeStatusRejected,
+			Message:  apiErr.Message,
+			SwipedAt: pending[0].SwipedAt,
+		}}, nil
+	}
+
+	results = make([]dtos.TerminalSwipeResult, 0, len(pending))
+	for _, swipe := range pending {
+		one, err := s.send([]queue.QueuedSwipe{swipe})
+		results = append(results, one...)
 		if err != nil {
-			return nil, err
+			return results, err
 		}
-		return &SwipeResult{
-			EmployeeName: card.Employee.FirstName + "" + card.Employee.LastName,
-			Type: "ENTRADA",
-			Time: now,
-		}, nil
-	} else if err != nil {
-		return nil, err 
 	}
-	if record.CheckOut != nil {
-		return nil, errors.New("Ya completo su jornada")
+	return results, nil
+}
+
+// keepQueued reports refusals caused by the terminal's setup (credentials,
+// role, backend version) rather than by the swipes; those are retried later
+func keepQueued(status int) bool {
+	return status == http.StatusUnauthorized || status == http.StatusForbidden || status == http.StatusNotFound
+}
+
+// swipeResult turns the backend result into what the screen shows
+func swipeResult(result dtos.TerminalSwipeResult) *SwipeResult {
+	screen := &SwipeResult{EmployeeName: result.EmployeeName, Time: result.SwipedAt, Message: result.Message}
+	if translated, ok := serverMessages[result.Message]; ok {
+		screen.Message = translated
+	}
+	switch {
+	case result.Status == models.SwipeStatusAccepted && result.Direction == models.SwipeDirectionOut:
+		screen.Type = TypeOut
+	case result.Status == models.SwipeStatusAccepted && result.Direction == models.SwipeDirectionBreakStart:
+		screen.Type = TypeBreakStart
+	case result.Status == models.SwipeStatusAccepted && result.Direction == models.SwipeDirectionBreakEnd:
+		screen.Type = TypeBreakEnd
+	case result.Status == models.SwipeStatusAccepted:
+		screen.Type = TypeIn
+	case result.Status == models.SwipeStatusIgnored:
+		screen.Type = TypeRepeated
+	default:
+		screen.Type = TypeRejected
+	}
+	return screen
+}
+
+// RegisterCard enrolls a card to an employee using HR credentials and
+// returns the employee's name
+func (s *Service) RegisterCard(hrEmail, hrPassword, employeeNumber, cardUID string) (string, error) {
+	card, err := s.client.EnrollCard(hrEmail, hrPassword, dtos.AttendanceCardRequest{
+		CardUID:        cardUID,
+		EmployeeNumber: employeeNumber,
+		TerminalID:     s.terminalID,
+	})
+	if errors.Is(err, client.ErrOffline) {
+		return "", errors.New("sin conexión: no se puede registrar la tarjeta")
+	}
+	var apiErr *client.APIError
+	if errors.As(err, &apiErr) {
+		return "", errors.New(apiErr.Message)
 	}
-	record.CheckOut = &now
-	err = s.db.Save(&record).Error
 	if err != nil {
-		return nil, err
+		return "", err
+	}
+	if card.Employee == nil {
+		return employeeNumber, nil
 	}
-	return &SwipeResult{
-		
-			EmployeeName: card.Employee.FirstName + " " + card.Employee.LastName,
-			Type: "SALIDA",
-			Time: now,
-	}, nil
+	return strings.TrimSpace(card.Employee.FirstName + " " + card.Employee.LastName), nil
 }
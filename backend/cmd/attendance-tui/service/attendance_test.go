@@ -0,0 +1,130 @@
+package service
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"backend/cmd/attendance-tui/client"
+	"backend/cmd/attendance-tui/nfc"
+	"backend/cmd/attendance-tui/queue"
+	"backend/internal/dtos"
+	"backend/internal/models"
+)
+
+// fakeBackend answers like the swipe endpoint: it toggles in/out per card
+// and deduplicates by swipe_id
+type fakeBackend struct {
+	mu          sync.Mutex
+	unavailable bool
+	logins      int
+	received    []dtos.TerminalSwipeRequest
+	results     map[uuid.UUID]dtos.TerminalSwipeResult
+	clockedIn   map[string]bool
+}
+
+func (b *fakeBackend) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.unavailable {
+		w.WriteHeader(http.StatusServiceUnavailable)
+		return
+	}
+	switch r.URL.Path {
+	case "/auth/login":
+		b.logins++
+		json.NewEncoder(w).Encode(dtos.LoginResponse{AccessToken: "token", RefreshToken: "refresh"})
+	case "/attendance/terminal/swipes":
+		if r.Header.Get("Authorization") != "Bearer token" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		var req dtos.TerminalSwipeBatchRequest
+		json.NewDecoder(r.Body).Decode(&req)
+		var resp dtos.TerminalSwipeBatchResponse
+		for _, swipe := range req.Swipes {
+			result, seen := b.results[swipe.SwipeID]
+			if seen {
+				result.Duplicate = true
+			} else {
+				b.received = append(b.received, swipe)
+				direction := models.SwipeDirectionIn
+				if b.clockedIn[swipe.CardUID] {
+					direction = models.SwipeDirectionOut
+				}
+				b.clockedIn[swipe.CardUID] = !b.clockedIn[swipe.CardUID]
+				result = dtos.TerminalSwipeResult{SwipeID: swipe.SwipeID, Status: models.SwipeStatusAccepted,
+					Direction: direction, EmployeeName: "Ana López", SwipedAt: swipe.SwipedAt}
+				b.results[swipe.SwipeID] = result
+			}
+			resp.Results = append(resp.Results, result)
+		}
+		json.NewEncoder(w).Encode(resp)
+	default:
+		w.WriteHeader(http.StatusNotFound)
+	}
+}
+
+func TestService_QueuesOfflineAndSyncs(t *testing.T) {
+	backend := &fakeBackend{results: map[uuid.UUID]dtos.TerminalSwipeResult{}, clockedIn: map[string]bool{}}
+	server := httptest.NewServer(backend)
+	defer server.Close()
+
+	swipeQueue, err := queue.Open(filepath.Join(t.TempDir(), "queue.db"))
+	require.NoError(t, err)
+	defer swipeQueue.Close()
+	svc := NewService(client.New(server.URL, "terminal@test.mx", "secret123"), swipeQueue, "LOBBY-1")
+
+	// Cards come from the fake reader, as they would from the ACR122U
+	reader := nfc.NewFakeReader()
+	defer reader.Close()
+	tap := func(uid string) *SwipeResult {
+		go reader.Tap(uid)
+		read, err := reader.WaitForCard()
+		require.NoError(t, err)
+		result, err := svc.ProcessSwipe(read)
+		require.NoError(t, err)
+		return result
+	}
+
+	result := tap("04a1b2c3")
+	assert.Equal(t, TypeIn, result.Type)
+	assert.Equal(t, "Ana López", result.EmployeeName)
+	assert.True(t, svc.Online())
+
+	// The backend goes away: the swipe is kept locally
+	backend.mu.Lock()
+	backend.unavailable = true
+	backend.mu.Unlock()
+	result = tap("04A1B2C3")
+	assert.Equal(t, TypePending, result.Type)
+	assert.False(t, svc.Online())
+	assert.Equal(t, int64(1), svc.PendingCount())
+
+	// Back online: the background sync sends it, flagged as offline
+	backend.mu.Lock()
+	backend.unavailable = false
+	backend.mu.Unlock()
+	synced, err := svc.SyncPending()
+	require.NoError(t, err)
+	assert.Equal(t, 1, synced)
+	assert.Equal(t, int64(0), svc.PendingCount())
+	require.Len(t, backend.received, 2)
+	assert.False(t, backend.received[0].Offline)
+	assert.True(t, backend.received[1].Offline)
+	assert.True(t, backend.received[0].SwipedAt.Before(backend.received[1].SwipedAt))
+	assert.Equal(t, 1, backend.logins)
+
+	// Nothing left to resend
+	synced, err = svc.SyncPending()
+	require.NoError(t, err)
+	assert.Equal(t, 0, synced)
+	assert.Len(t, backend.received, 2)
+}
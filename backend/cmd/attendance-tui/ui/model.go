@@ -0,0 +1,417 @@
+/*
+Package ui - Attendance Terminal Screen
+
+==============================================================================
+FILE: cmd/attendance-tui/ui/model.go
+==============================================================================
+
+DESCRIPTION:
+    Bubble Tea model of the terminal screen: clock and "acerque su tarjeta"
+    while idle, the swipe result for a few seconds after each read, card
+    enrollment steps, and a status bar with connection state, pending
+    swipes and the terminal ID. Also drives the background sync.
+
+USER PERSPECTIVE:
+    - Employees only tap; the screen goes back to idle by itself
+    - Ctrl+E starts card enrollment: HR email, password, employee number,
+      then the card to enroll; Esc cancels
+    - With --fake, type a UID and press Enter to simulate a tap
+
+DEVELOPER GUIDELINES:
+    ✅  OK to modify: Texts, colors, layout
+    ⚠️  CAUTION: Never block in Update; reads, swipes and syncs run as
+        tea.Cmd and come back as messages
+    📝  HR credentials live only in the model during one enrollment and are
+        cleared afterwards
+
+==============================================================================
+*/
+package ui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"backend/cmd/attendance-tui/nfc"
+	"backend/cmd/attendance-tui/service"
+)
+
+const (
+	resultDuration = 4 * time.Second
+	syncEvery      = 30 // seconds
+)
+
+type mode int
+
+const (
+	modeIdle mode = iota
+	modeResult
+	modeEnrollEmail
+	modeEnrollPassword
+	modeEnrollEmployee
+	modeEnrollCard
+	modeEnrollBusy
+)
+
+type (
+	cardMsg      string
+	readerErrMsg struct{ err error }
+	closedMsg    struct{}
+	tickMsg      time.Time
+	syncMsg      struct{}
+	swipeMsg     struct {
+		result *service.SwipeResult
+		err    error
+	}
+	enrollMsg struct {
+		name string
+		err  error
+	}
+)
+
+var weekdays = [...]string{"Domingo", "Lunes", "Martes", "Miércoles", "Jueves", "Viernes", "Sábado"}
+
+var (
+	titleStyle   = lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("39"))
+	clockStyle   = lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("255"))
+	mutedStyle   = lipgloss.NewStyle().Foreground(lipgloss.Color("244"))
+	boxStyle     = lipgloss.NewStyle().Border(lipgloss.RoundedBorder()).Padding(1, 4).Align(lipgloss.Center)
+	okStyle      = lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("42"))
+	warnStyle    = lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("214"))
+	errStyle     = lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("196"))
+	statusStyle  = lipgloss.NewStyle().Foreground(lipgloss.Color("250")).Background(lipgloss.Color("236")).Padding(0, 1)
+	resultColors = map[string]lipgloss.Style{
+		service.TypeIn:         okStyle,
+		service.TypeOut:        okStyle.Foreground(lipgloss.Color("45")),
+		service.TypeBreakStart: okStyle.Foreground(lipgloss.Color("141")),
+		service.TypeBreakEnd:   okStyle.Foreground(lipgloss.Color("141")),
+		service.TypePending:    warnStyle,
+		service.TypeRepeated:   warnStyle,
+		service.TypeRejected:   errStyle,
+	}
+)
+
+// Model is the terminal screen
+type Model struct {
+	svc    *service.Service
+	reader nfc.CardReader
+	fake   *nfc.FakeReader // Set when running without hardware
+
+	mode       mode
+	input      textinput.Model
+	processing int
+	now        time.Time
+	ticks      int
+	width      int
+	pending    int64
+	online     bool
+
+	result      *service.SwipeResult
+	notice      string // Enrollment outcome or reader error shown instead of a swipe
+	noticeErr   bool
+	resultUntil time.Time
+
+	hrEmail        string
+	hrPassword     string
+	employeeNumber string
+}
+
+// New creates the screen model
+func New(svc *service.Service, reader nfc.CardReader) Model {
+	m := Model{svc: svc, reader: reader, input: textinput.New(), now: time.Now()}
+	m.fake, _ = reader.(*nfc.FakeReader)
+	m.resetInput()
+	return m
+}
+
+// Init starts the card reader, the clock and the first sync
+func (m Model) Init() tea.Cmd {
+	return tea.Batch(waitForCard(m.reader, 0), tick(), syncCmd(m.svc), textinput.Blink)
+}
+
+// Update handles keys, reads and background results
+func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.width = msg.Width
+		return m, nil
+
+	case tea.KeyMsg:
+		return m.handleKey(msg)
+
+	case cardMsg:
+		next := waitForCard(m.reader, 0)
+		switch m.mode {
+		case modeEnrollCard:
+			m.mode = modeEnrollBusy
+			return m, tea.Batch(next, enrollCmd(m.svc, m.hrEmail, m.hrPassword, m.employeeNumber, string(msg)))
+		case modeEnrollBusy, modeEnrollEmail, modeEnrollPassword, modeEnrollEmployee:
+			return m, next // Enrollment in progress: taps are not punches
+		}
+		m.processing++
+		return m, tea.Batch(next, swipeCmd(m.svc, string(msg)))
+
+	case readerErrMsg:
+		m.showNotice("Error del lector: "+msg.err.Error(), true)
+		return m, waitForCard(m.reader, time.Second)
+
+	case swipeMsg:
+		m.processing--
+		if msg.err != nil {
+			m.showNotice("No se pudo guardar el registro: "+msg.err.Error(), true)
+		} else {
+			m.result, m.notice = msg.result, ""
+			m.mode, m.resultUntil = modeResult, time.Now().Add(resultDuration)
+		}
+		m.refreshStatus()
+		return m, nil
+
+	case enrollMsg:
+		m.hrEmail, m.hrPassword, m.employeeNumber = "", "", ""
+		if msg.err != nil {
+			m.showNotice("No se registró la tarjeta: "+msg.err.Error(), true)
+		} else {
+			m.showNotice("Tarjeta registrada a "+msg.name, false)
+		}
+		m.resetInput()
+		return m, nil
+
+	case tickMsg:
+		m.now = time.Time(msg)
+		m.ticks++
+		if m.mode == modeResult && m.now.After(m.resultUntil) {
+			m.mode, m.result, m.notice = modeIdle, nil, ""
+		}
+		m.refreshStatus()
+		cmds := []tea.Cmd{tick()}
+		if m.ticks%syncEvery == 0 && m.pending > 0 {
+			cmds = append(cmds, syncCmd(m.svc))
+		}
+		return m, tea.Batch(cmds...)
+
+	case syncMsg:
+		m.refreshStatus()
+		return m, nil
+	}
+
+	var cmd tea.Cmd
+	m.input, cmd = m.input.Update(msg)
+	return m, cmd
+}
+
+// handleKey handles keyboard input for every mode
+func (m Model) handleKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "ctrl+c":
+		return m, tea.Quit
+	case "ctrl+e":
+		if m.mode == modeIdle || m.mode == modeResult {
+			m.mode, m.result, m.notice = modeEnrollEmail, nil, ""
+			m.resetInput()
+			return m, nil
+		}
+	case "esc":
+		if m.mode >= modeEnrollEmail && m.mode != modeEnrollBusy {
+			m.hrEmail, m.hrPassword, m.employeeNumber = "", "", ""
+			m.mode = modeIdle
+			m.resetInput()
+			return m, nil
+		}
+	case "enter":
+		value := strings.TrimSpace(m.input.Value())
+		switch m.mode {
+		case modeIdle, modeResult:
+			if m.fake != nil && value != "" {
+				m.resetInput()
+				return m, tapCmd(m.fake, value)
+			}
+			return m, nil
+		case modeEnrollEmail:
+			if value != "" {
+				m.hrEmail, m.mode = value, modeEnrollPassword
+				m.resetInput()
+			}
+			return m, nil
+		case modeEnrollPassword:
+			if value != "" {
+				m.hrPassword, m.mode = m.input.Value(), modeEnrollEmployee
+				m.resetInput()
+			}
+			return m, nil
+		case modeEnrollEmployee:
+			if value != "" {
+				m.employeeNumber, m.mode = value, modeEnrollCard
+				m.resetInput()
+			}
+			return m, nil
+		}
+	}
+
+	if m.input.Focused() {
+		var cmd tea.Cmd
+		m.input, cmd = m.input.Update(msg)
+		return m, cmd
+	}
+	return m, nil
+}
+
+// resetInput prepares the text input for the current mode
+func (m *Model) resetInput() {
+	m.input.Reset()
+	m.input.EchoMode = textinput.EchoNormal
+	m.input.Blur()
+	switch m.mode {
+	case modeEnrollEmail:
+		m.input.Placeholder = "correo de RH"
+	case modeEnrollPassword:
+		m.input.Placeholder = "contraseña"
+		m.input.EchoMode = textinput.EchoPassword
+	case modeEnrollEmployee:
+		m.input.Placeholder = "número de empleado"
+	case modeIdle, modeResult:
+		if m.fake == nil {
+			return
+		}
+		m.input.Placeholder = "UID de tarjeta simulada"
+	default:
+		return
+	}
+	m.input.Focus()
+}
+
+// showNotice shows a message in the result area for a few seconds
+func (m *Model) showNotice(text string, isErr bool) {
+	m.result, m.notice, m.noticeErr = nil, text, isErr
+	m.mode, m.resultUntil = modeResult, time.Now().Add(resultDuration)
+}
+
+// refreshStatus reads the queue size and connection state
+func (m *Model) refreshStatus() {
+	m.pending = m.svc.PendingCount()
+	m.online = m.svc.Online()
+}
+
+// View renders the screen
+func (m Model) View() string {
+	var b strings.Builder
+	b.WriteString(titleStyle.Render("IRIS · Control de asistencia") + "\n\n")
+	b.WriteString(clockStyle.Render(m.now.Format("15:04:05")) + "  ")
+	b.WriteString(mutedStyle.Render(fmt.Sprintf("%s %s", weekdays[m.now.Weekday()], m.now.Format("02/01/2006"))) + "\n\n")
+
+	switch m.mode {
+	case modeResult:
+		b.WriteString(m.resultView())
+	case modeEnrollEmail, modeEnrollPassword, modeEnrollEmployee:
+		b.WriteString(titleStyle.Render("Registro de tarjeta") + "\n\n" + m.input.View() + "\n\n")
+		b.WriteString(mutedStyle.Render("Enter para continuar · Esc para cancelar"))
+	case modeEnrollCard:
+		b.WriteString(titleStyle.Render("Registro de tarjeta") + "\n\n")
+		b.WriteString(fmt.Sprintf("Acerque la tarjeta para el empleado %s\n\n", m.employeeNumber))
+		b.WriteString(mutedStyle.Render("Esc para cancelar"))
+	case modeEnrollBusy:
+		b.WriteString("Registrando tarjeta...")
+	default:
+		if m.processing > 0 {
+			b.WriteString("Procesando...")
+		} else {
+			b.WriteString(boxStyle.Render("Acerque su tarjeta al lector"))
+		}
+		if m.fake != nil {
+			b.WriteString("\n\n" + mutedStyle.Render("Lector simulado: ") + m.input.View())
+		}
+	}
+
+	b.WriteString("\n\n" + m.statusView())
+	return b.String()
+}
+
+// resultView renders the last swipe or notice
+func (m Model) resultView() string {
+	if m.result == nil {
+		style := okStyle
+		if m.noticeErr {
+			style = errStyle
+		}
+		return boxStyle.Render(style.Render(m.notice))
+	}
+	style, ok := resultColors[m.result.Type]
+	if !ok {
+		style = warnStyle
+	}
+	lines := []string{style.Render(m.result.Type)}
+	if m.result.EmployeeName != "" {
+		lines = append(lines, m.result.EmployeeName)
+	}
+	lines = append(lines, m.result.Time.Local().Format("15:04:05"))
+	if m.result.Message != "" {
+		lines = append(lines, mutedStyle.Render(m.result.Message))
+	}
+	return boxStyle.Render(strings.Join(lines, "\n"))
+}
+
+// statusView renders the connection / queue / terminal status bar
+func (m Model) statusView() string {
+	connection := okStyle.Render("● EN LÍNEA")
+	if !m.online {
+		connection = errStyle.Render("● SIN CONEXIÓN")
+	}
+	parts := []string{
+		connection,
+		fmt.Sprintf("Pendientes: %d", m.pending),
+		"Terminal: " + m.svc.TerminalID(),
+		"Ctrl+E registrar tarjeta · Ctrl+C salir",
+	}
+	return statusStyle.Width(m.width).Render(strings.Join(parts, "  │  "))
+}
+
+// waitForCard blocks on the reader (after an optional delay) and reports one read
+func waitForCard(reader nfc.CardReader, delay time.Duration) tea.Cmd {
+	return func() tea.Msg {
+		time.Sleep(delay)
+		uid, err := reader.WaitForCard()
+		switch {
+		case err == nfc.ErrClosed:
+			return closedMsg{}
+		case err != nil:
+			return readerErrMsg{err: err}
+		}
+		return cardMsg(uid)
+	}
+}
+
+func tapCmd(fake *nfc.FakeReader, uid string) tea.Cmd {
+	return func() tea.Msg {
+		fake.Tap(uid)
+		return nil
+	}
+}
+
+func swipeCmd(svc *service.Service, uid string) tea.Cmd {
+	return func() tea.Msg {
+		result, err := svc.ProcessSwipe(uid)
+		return swipeMsg{result: result, err: err}
+	}
+}
+
+func enrollCmd(svc *service.Service, email, password, employeeNumber, uid string) tea.Cmd {
+	return func() tea.Msg {
+		name, err := svc.RegisterCard(email, password, employeeNumber, uid)
+		return enrollMsg{name: name, err: err}
+	}
+}
+
+func syncCmd(svc *service.Service) tea.Cmd {
+	return func() tea.Msg {
+		svc.SyncPending()
+		return syncMsg{}
+	}
+}
+
+func tick() tea.Cmd {
+	return tea.Tick(time.Second, func(t time.Time) tea.Msg { return tickMsg(t) })
+}
@@ -11,12 +11,30 @@ DESCRIPTION:
 
 ENDPOINTS:
     POST   /absence-requests              - Create new request
+                                            (start_time/end_time "HH:MM" file it in
+                                            hours within the shift and total_days
+                                            is derived; 400 on invalid times or an
+                                            insufficient time bank)
     GET    /absence-requests/my-requests  - Get user's own requests
     GET    /absence-requests/pending/:stage - Get pending by stage
+                                            (stage "delegated": requests of users who
+                                            delegated their approvals to the caller)
     POST   /absence-requests/:id/approve  - Approve/decline request
+                                            (creation and approval answer 409 with
+                                            "coverage" when a blocking staffing
+                                            rule or blackout is broken; warnings
+                                            come back in "coverage" on success)
+    POST   /absence-requests/:id/cancel   - Request cancellation of an approved request
+                                            (reason; routed for approval like a new
+                                            request, its approval reverses payroll)
+    POST   /absence-requests/:id/modify   - Request new dates for an approved request
+                                            (start_date, end_date, total_days,
+                                            start_time, end_time, reason)
     DELETE /absence-requests/:id          - Delete request
     PATCH  /absence-requests/:id/archive  - Archive request
     GET    /absence-requests/overlapping  - Check overlapping absences
+    GET    /absence-requests/coverage-check - Preview staffing/blackout issues
+                                            (?request_type=&start_date=&end_date=)
     GET    /absence-requests/counts       - Get pending counts
 
 ==============================================================================
@@ -24,6 +42,7 @@ ENDPOINTS:
 package api
 
 import (
+	"errors"
 	"net/http"
 	"time"
 
@@ -54,9 +73,12 @@ func (h *AbsenceRequestHandler) RegisterRoutes(rg *gin.RouterGroup) {
 		requests.GET("/my-requests", h.GetMyRequests)
 		requests.GET("/pending/:stage", h.GetPendingByStage)
 		requests.POST("/:id/approve", h.Approve)
+		requests.POST("/:id/cancel", h.Cancel)
+		requests.POST("/:id/modify", h.Modify)
 		requests.DELETE("/:id", h.Delete)
 		requests.PATCH("/:id/archive", h.Archive)
 		requests.GET("/overlapping", h.GetOverlapping)
+		requests.GET("/coverage-check", h.CheckCoverage)
 		requests.GET("/counts", h.GetCounts)
 		requests.GET("/approved", h.GetApproved)
 		requests.GET("/export", h.ExportApproved)
@@ -69,7 +91,7 @@ type CreateAbsenceRequestDTO struct {
 	RequestType    string   `json:"request_type" binding:"required"`
 	StartDate      string   `json:"start_date" binding:"required"`
 	EndDate        string   `json:"end_date" binding:"required"`
-	TotalDays      float64  `json:"total_days" binding:"required"`
+	TotalDays      float64  `json:"total_days"` // Derived for requests in hours
 	Reason         string   `json:"reason" binding:"required"`
 	HoursPerDay    *float64 `json:"hours_per_day"`
 	PaidDays       *float64 `json:"paid_days"`
@@ -77,6 +99,9 @@ type CreateAbsenceRequestDTO struct {
 	UnpaidComments string   `json:"unpaid_comments"`
 	ShiftDetails   string   `json:"shift_details"`
 	NewShiftID     *string  `json:"new_shift_id"` // For SHIFT_CHANGE requests - the target shift
+	StartTime      string   `json:"start_time"`   // "HH:MM" for requests in hours
+	EndTime        string   `json:"end_time"`
+	LeavePolicyID  *string  `json:"leave_policy_id"` // For LEAVE requests - the catalog leave taken
 }
 
 // Create handles POST /absence-requests
@@ -116,6 +141,17 @@ func (h *AbsenceRequestHandler) Create(c *gin.Context) {
 		newShiftID = &shiftID
 	}
 
+	// Parse leave_policy_id if provided
+	var leavePolicyID *uuid.UUID
+	if dto.LeavePolicyID != nil && *dto.LeavePolicyID != "" {
+		policyID, err := uuid.Parse(*dto.LeavePolicyID)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid leave_policy_id"})
+			return
+		}
+		leavePolicyID = &policyID
+	}
+
 	input := services.CreateAbsenceRequestInput{
 		EmployeeID:     employeeID,
 		RequestType:    models.RequestType(dto.RequestType),
@@ -129,11 +165,14 @@ func (h *AbsenceRequestHandler) Create(c *gin.Context) {
 		UnpaidComments: dto.UnpaidComments,
 		ShiftDetails:   dto.ShiftDetails,
 		NewShiftID:     newShiftID,
+		StartTime:      dto.StartTime,
+		EndTime:        dto.EndTime,
+		LeavePolicyID:  leavePolicyID,
 	}
 
 	result, err := h.service.CreateAbsenceRequest(input)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondAbsenceError(c, err)
 		return
 	}
 
@@ -141,9 +180,25 @@ func (h *AbsenceRequestHandler) Create(c *gin.Context) {
 		"success":     true,
 		"requestId":   result.Request.ID,
 		"incidenceId": result.IncidenceID, // NEW: Return incidence ID for evidence upload
+		"coverage":    result.Coverage,
 	})
 }
 
+// respondAbsenceError answers 409 with the coverage issues when a request
+// breaks a blocking staffing rule or blackout, and 400 when it is invalid
+func respondAbsenceError(c *gin.Context, err error) {
+	var blocked *services.CoverageBlockedError
+	if errors.As(err, &blocked) {
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error(), "coverage": blocked.Check})
+		return
+	}
+	if errors.Is(err, services.ErrInvalidAbsenceRequest) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+}
+
 // GetMyRequests handles GET /absence-requests/my-requests
 func (h *AbsenceRequestHandler) GetMyRequests(c *gin.Context) {
 	userID, exists := c.Get("userID")
@@ -182,6 +237,8 @@ func (h *AbsenceRequestHandler) GetPendingByStage(c *gin.Context) {
 		requests, err = h.service.GetPendingRequestsForHR(userID.(uuid.UUID))
 	case "hr_blue_gray", "HR_BLUE_GRAY":
 		requests, err = h.service.GetPendingRequestsForHRBlueGray(userID.(uuid.UUID))
+	case "delegated", "DELEGATED":
+		requests, err = h.service.GetDelegatedPendingRequests(userID.(uuid.UUID))
 	default:
 		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid stage"})
 		return
@@ -230,12 +287,114 @@ func (h *AbsenceRequestHandler) Approve(c *gin.Context) {
 		Comments:   dto.Comments,
 	}
 
-	if err := h.service.ApproveRequest(input); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	coverage, err := h.service.ApproveRequest(input)
+	if err != nil {
+		respondAbsenceError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"success": true, "coverage": coverage})
+}
+
+// CancelRequestDTO is the request body for cancelling an approved request
+type CancelRequestDTO struct {
+	Reason string `json:"reason" binding:"required"`
+}
+
+// Cancel handles POST /absence-requests/:id/cancel
+func (h *AbsenceRequestHandler) Cancel(c *gin.Context) {
+	requestID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid request ID"})
+		return
+	}
+
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not found in context"})
+		return
+	}
+
+	var dto CancelRequestDTO
+	if err := c.ShouldBindJSON(&dto); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	result, err := h.service.RequestCancellation(requestID, userID.(uuid.UUID), dto.Reason)
+	if err != nil {
+		respondAbsenceError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"success":   true,
+		"requestId": result.Request.ID,
+		"coverage":  result.Coverage,
+	})
+}
+
+// ModifyRequestDTO is the request body for changing the dates of an approved request
+type ModifyRequestDTO struct {
+	StartDate string  `json:"start_date" binding:"required"`
+	EndDate   string  `json:"end_date" binding:"required"`
+	TotalDays float64 `json:"total_days"` // Derived for requests in hours
+	StartTime string  `json:"start_time"` // "HH:MM" for requests in hours
+	EndTime   string  `json:"end_time"`
+	Reason    string  `json:"reason" binding:"required"`
+}
+
+// Modify handles POST /absence-requests/:id/modify
+func (h *AbsenceRequestHandler) Modify(c *gin.Context) {
+	requestID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid request ID"})
+		return
+	}
+
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not found in context"})
+		return
+	}
+
+	var dto ModifyRequestDTO
+	if err := c.ShouldBindJSON(&dto); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	startDate, err := time.Parse("2006-01-02", dto.StartDate)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid start_date format (use YYYY-MM-DD)"})
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{"success": true})
+	endDate, err := time.Parse("2006-01-02", dto.EndDate)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid end_date format (use YYYY-MM-DD)"})
+		return
+	}
+
+	result, err := h.service.RequestModification(requestID, userID.(uuid.UUID), services.ModifyAbsenceRequestInput{
+		StartDate: startDate,
+		EndDate:   endDate,
+		TotalDays: dto.TotalDays,
+		StartTime: dto.StartTime,
+		EndTime:   dto.EndTime,
+		Reason:    dto.Reason,
+	})
+	if err != nil {
+		respondAbsenceError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"success":     true,
+		"requestId":   result.Request.ID,
+		"incidenceId": result.IncidenceID,
+		"coverage":    result.Coverage,
+	})
 }
 
 // Delete handles DELETE /absence-requests/:id
@@ -328,6 +487,41 @@ func (h *AbsenceRequestHandler) GetOverlapping(c *gin.Context) {
 	c.JSON(http.StatusOK, overlapping)
 }
 
+// CheckCoverage handles GET /absence-requests/coverage-check
+func (h *AbsenceRequestHandler) CheckCoverage(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not found in context"})
+		return
+	}
+
+	requestType := c.Query("request_type")
+	if requestType == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "request_type is required"})
+		return
+	}
+
+	start, err := time.Parse("2006-01-02", c.Query("start_date"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid start_date format (use YYYY-MM-DD)"})
+		return
+	}
+
+	end, err := time.Parse("2006-01-02", c.Query("end_date"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid end_date format (use YYYY-MM-DD)"})
+		return
+	}
+
+	check, err := h.service.CheckCoverage(userID.(uuid.UUID), models.RequestType(requestType), start, end)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, check)
+}
+
 // GetCounts handles GET /absence-requests/counts
 func (h *AbsenceRequestHandler) GetCounts(c *gin.Context) {
 	userID, exists := c.Get("userID")
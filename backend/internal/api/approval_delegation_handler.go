@@ -0,0 +1,177 @@
+/*
+Package api - IRIS Payroll System HTTP API Handlers
+
+==============================================================================
+FILE: internal/api/approval_delegation_handler.go
+==============================================================================
+
+DESCRIPTION:
+    Endpoints for out-of-office substitutes: approvers delegate their
+    approval authority for a date range, confirm or dismiss the substitute
+    suggested when their own absence is approved, and HR oversees every
+    delegation of the company. The delegate works the requests from
+    GET /absence-requests/pending/delegated.
+
+USER PERSPECTIVE:
+    - "Who approves while I'm away?" is answered before leaving
+    - HR can fix a missing substitute for someone already on vacation
+
+DEVELOPER GUIDELINES:
+    ✅  OK to modify: Add filters to the company list
+    ⚠️  CAUTION: Only HR may create or revoke a delegation for someone else
+
+ENDPOINTS:
+    GET  /approval-delegations              - Delegations I granted or hold
+    POST /approval-delegations              - Delegate my approvals (HR: delegator_id)
+    POST /approval-delegations/:id/accept   - Confirm a suggested substitute
+    POST /approval-delegations/:id/revoke   - End a delegation / dismiss a suggestion
+    GET  /approval-delegations/company      - Every delegation of the company (HR, ?status=)
+
+==============================================================================
+*/
+package api
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+
+	"backend/internal/dtos"
+	"backend/internal/middleware"
+	"backend/internal/services"
+)
+
+// ApprovalDelegationHandler handles approval delegation endpoints
+type ApprovalDelegationHandler struct {
+	service *services.ApprovalDelegationService
+}
+
+// NewApprovalDelegationHandler creates a new approval delegation handler
+func NewApprovalDelegationHandler(service *services.ApprovalDelegationService) *ApprovalDelegationHandler {
+	return &ApprovalDelegationHandler{service: service}
+}
+
+// RegisterRoutes registers approval delegation routes
+func (h *ApprovalDelegationHandler) RegisterRoutes(router *gin.RouterGroup, authMiddleware *middleware.AuthMiddleware) {
+	delegations := router.Group("/approval-delegations")
+	delegations.GET("", h.ListMine)
+	delegations.POST("", h.Create)
+	delegations.POST("/:id/accept", h.Accept)
+	delegations.POST("/:id/revoke", h.Revoke)
+
+	hr := delegations.Group("")
+	hr.Use(authMiddleware.RequireRole("admin", "hr", "hr_and_pr", "hr_blue_gray", "hr_white"))
+	hr.GET("/company", h.ListCompany)
+}
+
+// delegationErrorStatus maps delegation errors to HTTP statuses
+func delegationErrorStatus(err error) int {
+	if errors.Is(err, services.ErrDelegationNotAllowed) {
+		return http.StatusForbidden
+	}
+	return organizationErrorStatus(err)
+}
+
+// ListMine handles GET /approval-delegations
+func (h *ApprovalDelegationHandler) ListMine(c *gin.Context) {
+	userID, _, companyID, err := middleware.GetUserFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not found in context"})
+		return
+	}
+
+	delegations, err := h.service.ListMine(companyID, userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, delegations)
+}
+
+// ListCompany handles GET /approval-delegations/company
+func (h *ApprovalDelegationHandler) ListCompany(c *gin.Context) {
+	_, _, companyID, err := middleware.GetUserFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not found in context"})
+		return
+	}
+
+	delegations, err := h.service.ListCompany(companyID, c.Query("status"))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, delegations)
+}
+
+// Create handles POST /approval-delegations
+func (h *ApprovalDelegationHandler) Create(c *gin.Context) {
+	userID, _, companyID, err := middleware.GetUserFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not found in context"})
+		return
+	}
+	var req dtos.ApprovalDelegationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	delegation, err := h.service.Create(companyID, userID, req)
+	if err != nil {
+		c.JSON(delegationErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusCreated, delegation)
+}
+
+// Accept handles POST /approval-delegations/:id/accept
+func (h *ApprovalDelegationHandler) Accept(c *gin.Context) {
+	userID, _, companyID, err := middleware.GetUserFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not found in context"})
+		return
+	}
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid delegation ID"})
+		return
+	}
+	var req dtos.AcceptDelegationRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+	}
+
+	delegation, err := h.service.Accept(companyID, userID, id, req)
+	if err != nil {
+		c.JSON(delegationErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, delegation)
+}
+
+// Revoke handles POST /approval-delegations/:id/revoke
+func (h *ApprovalDelegationHandler) Revoke(c *gin.Context) {
+	userID, _, companyID, err := middleware.GetUserFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not found in context"})
+		return
+	}
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid delegation ID"})
+		return
+	}
+
+	delegation, err := h.service.Revoke(companyID, userID, id)
+	if err != nil {
+		c.JSON(delegationErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, delegation)
+}
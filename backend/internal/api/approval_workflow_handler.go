@@ -0,0 +1,233 @@
+/*
+Package api - IRIS Payroll System HTTP API Handlers
+
+==============================================================================
+FILE: internal/api/approval_workflow_handler.go
+==============================================================================
+
+DESCRIPTION:
+    Endpoints for the configurable approval workflows: maintenance of the
+    company's workflow definitions, a preview of the route a request would
+    take, and the inbox of requests waiting for the caller in any workflow
+    stage (including stages the fixed pending/:stage queues do not know).
+
+USER PERSPECTIVE:
+    - HR adds a medical review for sick leave or lets short absences skip
+      the general manager
+    - A medical reviewer or a designated director sees their requests in
+      one inbox and approves them with POST /absence-requests/:id/approve
+
+DEVELOPER GUIDELINES:
+    ✅  OK to modify: Add inbox filters
+    ⚠️  CAUTION: The default workflow can be edited but not deleted
+    📝  The preview defaults to a one-day VACATION request
+
+ENDPOINTS:
+    GET    /approval-workflows/inbox    - Requests waiting for the caller
+    GET    /approval-workflows          - List workflows (seeds the default)
+    GET    /approval-workflows/preview  - Route for employee_id, request_type, total_days[, incidence_type_id]
+    GET    /approval-workflows/:id      - Get a workflow
+    POST   /approval-workflows          - Create a workflow
+    PUT    /approval-workflows/:id      - Update a workflow (replaces its steps)
+    DELETE /approval-workflows/:id      - Delete a workflow
+
+==============================================================================
+*/
+package api
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+
+	"backend/internal/dtos"
+	"backend/internal/middleware"
+	"backend/internal/models"
+	"backend/internal/services"
+)
+
+// ApprovalWorkflowHandler handles approval workflow endpoints
+type ApprovalWorkflowHandler struct {
+	service *services.ApprovalWorkflowService
+}
+
+// NewApprovalWorkflowHandler creates a new approval workflow handler
+func NewApprovalWorkflowHandler(service *services.ApprovalWorkflowService) *ApprovalWorkflowHandler {
+	return &ApprovalWorkflowHandler{service: service}
+}
+
+// RegisterRoutes registers approval workflow routes
+func (h *ApprovalWorkflowHandler) RegisterRoutes(router *gin.RouterGroup, authMiddleware *middleware.AuthMiddleware) {
+	workflows := router.Group("/approval-workflows")
+	workflows.GET("/inbox", h.Inbox)
+
+	hr := workflows.Group("")
+	hr.Use(authMiddleware.RequireRole("admin", "hr", "hr_and_pr", "hr_blue_gray", "hr_white"))
+	{
+		hr.GET("", h.List)
+		hr.GET("/preview", h.Preview)
+		hr.GET("/:id", h.Get)
+		hr.POST("", h.Create)
+		hr.PUT("/:id", h.Update)
+		hr.DELETE("/:id", h.Delete)
+	}
+}
+
+// Inbox handles GET /approval-workflows/inbox
+func (h *ApprovalWorkflowHandler) Inbox(c *gin.Context) {
+	userID, _, _, err := middleware.GetUserFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not found in context"})
+		return
+	}
+
+	requests, err := h.service.PendingFor(userID)
+	if err != nil {
+		c.JSON(organizationErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"requests": requests})
+}
+
+// List handles GET /approval-workflows
+func (h *ApprovalWorkflowHandler) List(c *gin.Context) {
+	_, _, companyID, err := middleware.GetUserFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not found in context"})
+		return
+	}
+
+	workflows, err := h.service.ListWorkflows(companyID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, workflows)
+}
+
+// Preview handles GET /approval-workflows/preview
+func (h *ApprovalWorkflowHandler) Preview(c *gin.Context) {
+	_, _, companyID, err := middleware.GetUserFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not found in context"})
+		return
+	}
+	employeeID, err := uuid.Parse(c.Query("employee_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid employee_id"})
+		return
+	}
+	totalDays, err := strconv.ParseFloat(c.DefaultQuery("total_days", "1"), 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid total_days"})
+		return
+	}
+	var incidenceTypeID *uuid.UUID
+	if raw := c.Query("incidence_type_id"); raw != "" {
+		parsed, err := uuid.Parse(raw)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid incidence_type_id"})
+			return
+		}
+		incidenceTypeID = &parsed
+	}
+	requestType := models.RequestType(strings.ToUpper(c.DefaultQuery("request_type", string(models.RequestTypeVacation))))
+
+	preview, err := h.service.Preview(companyID, employeeID, requestType, totalDays, incidenceTypeID)
+	if err != nil {
+		c.JSON(organizationErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, preview)
+}
+
+// Get handles GET /approval-workflows/:id
+func (h *ApprovalWorkflowHandler) Get(c *gin.Context) {
+	_, _, companyID, err := middleware.GetUserFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not found in context"})
+		return
+	}
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid workflow ID"})
+		return
+	}
+
+	workflow, err := h.service.GetWorkflow(companyID, id)
+	if err != nil {
+		c.JSON(organizationErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, workflow)
+}
+
+// Create handles POST /approval-workflows
+func (h *ApprovalWorkflowHandler) Create(c *gin.Context) {
+	_, _, companyID, err := middleware.GetUserFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not found in context"})
+		return
+	}
+	var req dtos.ApprovalWorkflowRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	workflow, err := h.service.CreateWorkflow(companyID, req)
+	if err != nil {
+		c.JSON(organizationErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusCreated, workflow)
+}
+
+// Update handles PUT /approval-workflows/:id
+func (h *ApprovalWorkflowHandler) Update(c *gin.Context) {
+	_, _, companyID, err := middleware.GetUserFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not found in context"})
+		return
+	}
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid workflow ID"})
+		return
+	}
+	var req dtos.ApprovalWorkflowRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	workflow, err := h.service.UpdateWorkflow(companyID, id, req)
+	if err != nil {
+		c.JSON(organizationErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, workflow)
+}
+
+// Delete handles DELETE /approval-workflows/:id
+func (h *ApprovalWorkflowHandler) Delete(c *gin.Context) {
+	_, _, companyID, err := middleware.GetUserFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not found in context"})
+		return
+	}
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid workflow ID"})
+		return
+	}
+
+	if err := h.service.DeleteWorkflow(companyID, id); err != nil {
+		c.JSON(organizationErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "approval workflow deleted"})
+}
@@ -0,0 +1,183 @@
+/*
+Package api - IRIS Payroll System HTTP API Handlers
+
+==============================================================================
+FILE: internal/api/attendance_handler.go
+==============================================================================
+
+DESCRIPTION:
+    Handles the daily attendance evaluation: evaluating a period or date
+    range, reviewing the per-day audit trail and HR overrides.
+
+USER PERSPECTIVE:
+    - HR evaluates attendance before calculating the prenómina
+    - HR and payroll staff review each day (schedule, punches, result)
+    - HR corrects a day with a reason, or drops the correction
+
+DEVELOPER GUIDELINES:
+    ✅  OK to modify: Add filters (status, only exceptions)
+    ⚠️  CAUTION: Overrides change the prenómina; keep them HR-only
+    📝  ?period_id= wins over start_date/end_date on the days endpoint
+
+ENDPOINTS:
+    POST   /attendance/evaluate                    - Evaluate a period or range
+    GET    /attendance/employees/:id/days          - Days and summary (?start_date=&end_date= or ?period_id=)
+    PUT    /attendance/days/:id/override           - Override a day
+    DELETE /attendance/days/:id/override           - Drop the override and re-evaluate
+
+==============================================================================
+*/
+package api
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+
+	"backend/internal/dtos"
+	"backend/internal/middleware"
+	"backend/internal/services"
+)
+
+// AttendanceHandler handles attendance evaluation endpoints
+type AttendanceHandler struct {
+	service *services.AttendanceEvaluationService
+}
+
+// NewAttendanceHandler creates a new attendance handler
+func NewAttendanceHandler(service *services.AttendanceEvaluationService) *AttendanceHandler {
+	return &AttendanceHandler{service: service}
+}
+
+// RegisterRoutes registers attendance routes
+func (h *AttendanceHandler) RegisterRoutes(router *gin.RouterGroup, authMiddleware *middleware.AuthMiddleware) {
+	attendance := router.Group("/attendance")
+	attendance.Use(authMiddleware.RequireRole("admin", "hr", "hr_and_pr", "payroll_staff"))
+	{
+		attendance.POST("/evaluate", h.Evaluate)
+		attendance.GET("/employees/:id/days", h.ListDays)
+	}
+
+	manage := router.Group("/attendance")
+	manage.Use(authMiddleware.RequireRole("admin", "hr", "hr_and_pr"))
+	{
+		manage.PUT("/days/:id/override", h.OverrideDay)
+		manage.DELETE("/days/:id/override", h.ClearOverride)
+	}
+}
+
+// Evaluate handles POST /attendance/evaluate
+func (h *AttendanceHandler) Evaluate(c *gin.Context) {
+	_, _, companyID, err := middleware.GetUserFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not found in context"})
+		return
+	}
+	var req dtos.AttendanceEvaluateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	summaries, err := h.service.EvaluateCompany(companyID, req)
+	if err != nil {
+		c.JSON(organizationErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"summaries": summaries, "count": len(summaries)})
+}
+
+// ListDays handles GET /attendance/employees/:id/days
+func (h *AttendanceHandler) ListDays(c *gin.Context) {
+	_, _, companyID, err := middleware.GetUserFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not found in context"})
+		return
+	}
+	employeeID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid employee ID"})
+		return
+	}
+
+	var start, end time.Time
+	if raw := c.Query("period_id"); raw != "" {
+		periodID, err := uuid.Parse(raw)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid period ID"})
+			return
+		}
+		if start, end, err = h.service.PeriodRange(periodID); err != nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+	} else {
+		if start, err = time.Parse("2006-01-02", c.Query("start_date")); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid start_date, expected YYYY-MM-DD"})
+			return
+		}
+		if end, err = time.Parse("2006-01-02", c.Query("end_date")); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid end_date, expected YYYY-MM-DD"})
+			return
+		}
+	}
+
+	days, err := h.service.ListDays(companyID, employeeID, start, end)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"days":    days,
+		"summary": h.service.Summarize(employeeID, start, end, days),
+	})
+}
+
+// OverrideDay handles PUT /attendance/days/:id/override
+func (h *AttendanceHandler) OverrideDay(c *gin.Context) {
+	userID, _, companyID, err := middleware.GetUserFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not found in context"})
+		return
+	}
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid attendance day ID"})
+		return
+	}
+	var req dtos.AttendanceOverrideRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	day, err := h.service.OverrideDay(companyID, id, req, userID)
+	if err != nil {
+		c.JSON(organizationErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, day)
+}
+
+// ClearOverride handles DELETE /attendance/days/:id/override
+func (h *AttendanceHandler) ClearOverride(c *gin.Context) {
+	_, _, companyID, err := middleware.GetUserFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not found in context"})
+		return
+	}
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid attendance day ID"})
+		return
+	}
+
+	day, err := h.service.ClearOverride(companyID, id)
+	if err != nil {
+		c.JSON(organizationErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, day)
+}
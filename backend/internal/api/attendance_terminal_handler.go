@@ -0,0 +1,198 @@
+/*
+Package api - IRIS Payroll System HTTP API Handlers
+
+==============================================================================
+FILE: internal/api/attendance_terminal_handler.go
+==============================================================================
+
+DESCRIPTION:
+    Endpoints used by the NFC attendance terminals (cmd/attendance-tui):
+    posting card swipes, live or synced from the offline queue, and
+    enrolling cards to employees.
+
+USER PERSPECTIVE:
+    - The terminal posts every tap and shows the result (entrada/salida)
+    - HR enrolls a card at the terminal, or deactivates a lost one
+    - HR reviews an employee's punch sequence (entradas, descansos, salidas)
+
+DEVELOPER GUIDELINES:
+    ✅  OK to modify: Add card filters, swipe audit listings
+    ⚠️  CAUTION: Terminals log in with a "terminal" service account; keep it
+        out of every other route group
+    📝  A batch answers 200 with one result per swipe; a rejected swipe does
+        not fail the rest of the batch
+
+ENDPOINTS:
+    POST   /attendance/terminal/swipes             - Post a batch of swipes (idempotent by swipe_id)
+    POST   /attendance/terminal/cards              - Enroll a card
+    GET    /attendance/terminal/cards              - List cards (?employee_id=&include_inactive=true)
+    DELETE /attendance/terminal/cards/:id          - Deactivate a card
+    GET    /attendance/terminal/employees/:id/punches - Accepted punches by workday (?start_date=&end_date=)
+
+==============================================================================
+*/
+package api
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+
+	"backend/internal/dtos"
+	"backend/internal/middleware"
+	"backend/internal/services"
+)
+
+// AttendanceTerminalHandler handles NFC terminal endpoints
+type AttendanceTerminalHandler struct {
+	service *services.AttendanceTerminalService
+}
+
+// NewAttendanceTerminalHandler creates a new attendance terminal handler
+func NewAttendanceTerminalHandler(service *services.AttendanceTerminalService) *AttendanceTerminalHandler {
+	return &AttendanceTerminalHandler{service: service}
+}
+
+// RegisterRoutes registers attendance terminal routes
+func (h *AttendanceTerminalHandler) RegisterRoutes(router *gin.RouterGroup, authMiddleware *middleware.AuthMiddleware) {
+	swipes := router.Group("/attendance/terminal")
+	swipes.Use(authMiddleware.RequireRole("terminal", "admin", "hr", "hr_and_pr"))
+	{
+		swipes.POST("/swipes", h.RecordSwipes)
+	}
+
+	cards := router.Group("/attendance/terminal/cards")
+	cards.Use(authMiddleware.RequireRole("admin", "hr", "hr_and_pr"))
+	{
+		cards.POST("", h.EnrollCard)
+		cards.GET("", h.ListCards)
+		cards.DELETE("/:id", h.DeactivateCard)
+	}
+
+	punches := router.Group("/attendance/terminal/employees")
+	punches.Use(authMiddleware.RequireRole("admin", "hr", "hr_and_pr", "payroll_staff"))
+	{
+		punches.GET("/:id/punches", h.ListPunches)
+	}
+}
+
+// RecordSwipes handles POST /attendance/terminal/swipes
+func (h *AttendanceTerminalHandler) RecordSwipes(c *gin.Context) {
+	_, _, companyID, err := middleware.GetUserFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not found in context"})
+		return
+	}
+	var req dtos.TerminalSwipeBatchRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	results, err := h.service.RecordSwipes(companyID, req)
+	if err != nil {
+		c.JSON(organizationErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, dtos.TerminalSwipeBatchResponse{Results: results})
+}
+
+// EnrollCard handles POST /attendance/terminal/cards
+func (h *AttendanceTerminalHandler) EnrollCard(c *gin.Context) {
+	userID, _, companyID, err := middleware.GetUserFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not found in context"})
+		return
+	}
+	var req dtos.AttendanceCardRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	card, err := h.service.EnrollCard(companyID, req, userID)
+	if err != nil {
+		c.JSON(organizationErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusCreated, card)
+}
+
+// ListCards handles GET /attendance/terminal/cards
+func (h *AttendanceTerminalHandler) ListCards(c *gin.Context) {
+	_, _, companyID, err := middleware.GetUserFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not found in context"})
+		return
+	}
+	var employeeID *uuid.UUID
+	if raw := c.Query("employee_id"); raw != "" {
+		id, err := uuid.Parse(raw)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid employee ID"})
+			return
+		}
+		employeeID = &id
+	}
+
+	cards, err := h.service.ListCards(companyID, employeeID, c.Query("include_inactive") == "true")
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"cards": cards, "count": len(cards)})
+}
+
+// DeactivateCard handles DELETE /attendance/terminal/cards/:id
+func (h *AttendanceTerminalHandler) DeactivateCard(c *gin.Context) {
+	_, _, companyID, err := middleware.GetUserFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not found in context"})
+		return
+	}
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid card ID"})
+		return
+	}
+
+	card, err := h.service.DeactivateCard(companyID, id)
+	if err != nil {
+		c.JSON(organizationErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, card)
+}
+
+// ListPunches handles GET /attendance/terminal/employees/:id/punches
+func (h *AttendanceTerminalHandler) ListPunches(c *gin.Context) {
+	_, _, companyID, err := middleware.GetUserFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not found in context"})
+		return
+	}
+	employeeID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid employee ID"})
+		return
+	}
+	start, err := time.Parse("2006-01-02", c.Query("start_date"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid start_date, expected YYYY-MM-DD"})
+		return
+	}
+	end, err := time.Parse("2006-01-02", c.Query("end_date"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid end_date, expected YYYY-MM-DD"})
+		return
+	}
+
+	punches, err := h.service.ListPunches(companyID, employeeID, start, end)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"punches": punches, "count": len(punches)})
+}
@@ -23,7 +23,9 @@ import (
 	"github.com/gin-gonic/gin"
 
 	"backend/internal/dtos"
+	"backend/internal/middleware"
 	"backend/internal/services"
+	"backend/internal/utils"
 )
 
 // CalendarHandler handles HTTP requests for calendar
@@ -57,6 +59,7 @@ func (h *CalendarHandler) RegisterRoutes(rg *gin.RouterGroup) {
 // @Param collar_types[] query []string false "Filter by collar types (white_collar, blue_collar, gray_collar)"
 // @Param event_types[] query []string false "Filter by event types (absence, incidence, shift_change)"
 // @Param department_id query string false "Filter by department ID"
+// @Param manager_id query string false "Filter by manager's team (employee ID)"
 // @Param status query string false "Filter by status (pending, approved, declined)"
 // @Success 200 {object} dtos.CalendarEventsResponse
 // @Failure 400 {object} map[string]string
@@ -71,8 +74,12 @@ func (h *CalendarHandler) GetEvents(c *gin.Context) {
 		CollarTypes:  c.QueryArray("collar_types[]"),
 		EventTypes:   c.QueryArray("event_types[]"),
 		DepartmentID: c.Query("department_id"),
+		ManagerID:    c.Query("manager_id"),
 		Status:       c.Query("status"),
 	}
+	if req.ManagerID == "" {
+		req.ManagerID = h.teamScope(c)
+	}
 
 	// Also support non-array format for single values
 	if len(req.EmployeeIDs) == 0 {
@@ -119,12 +126,17 @@ func (h *CalendarHandler) GetEvents(c *gin.Context) {
 // @Produce json
 // @Param collar_types[] query []string false "Filter by collar types"
 // @Param department_id query string false "Filter by department ID"
+// @Param manager_id query string false "Filter by manager's team (employee ID)"
 // @Success 200 {object} dtos.CalendarEmployeesResponse
 // @Failure 500 {object} map[string]string
 // @Router /calendar/employees [get]
 func (h *CalendarHandler) GetEmployees(c *gin.Context) {
 	collarTypes := c.QueryArray("collar_types[]")
 	departmentID := c.Query("department_id")
+	managerID := c.Query("manager_id")
+	if managerID == "" {
+		managerID = h.teamScope(c)
+	}
 
 	// Also support non-array format
 	if len(collarTypes) == 0 {
@@ -133,7 +145,7 @@ func (h *CalendarHandler) GetEmployees(c *gin.Context) {
 		}
 	}
 
-	response, err := h.service.GetEmployeesForCalendar(collarTypes, departmentID)
+	response, err := h.service.GetEmployeesForCalendar(collarTypes, departmentID, managerID)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{
 			"error": err.Error(),
@@ -143,3 +155,15 @@ func (h *CalendarHandler) GetEmployees(c *gin.Context) {
 
 	c.JSON(http.StatusOK, response)
 }
+
+// teamScope limits supervisors and managers to their own team
+func (h *CalendarHandler) teamScope(c *gin.Context) string {
+	if !utils.IsManagerRole(middleware.GetUserRoleFromContext(c)) {
+		return ""
+	}
+	userID, _, _, err := middleware.GetUserFromContext(c)
+	if err != nil {
+		return ""
+	}
+	return h.service.ManagerScopeForUser(userID)
+}
@@ -50,6 +50,7 @@ IMPORT FILE FORMAT:
 package api
 
 import (
+	"errors"
 	"net/http"
 	"strings"
 
@@ -259,7 +260,8 @@ func (h *EmployeeHandler) CreateEmployee(c *gin.Context) {
         status := http.StatusInternalServerError
         if strings.Contains(err.Error(), "already exists") {
             status = http.StatusConflict
-        } else if strings.Contains(err.Error(), "validation") {
+        } else if strings.Contains(err.Error(), "validation") || errors.Is(err, services.ErrBelowMinimumWage) ||
+            errors.Is(err, services.ErrReportingCycle) {
             status = http.StatusBadRequest
         }
         
@@ -331,8 +333,11 @@ func (h *EmployeeHandler) UpdateEmployee(c *gin.Context) {
             status = http.StatusNotFound
         } else if strings.Contains(err.Error(), "already exists") {
             status = http.StatusConflict
-        } else if strings.Contains(err.Error(), "validation") {
+        } else if strings.Contains(err.Error(), "validation") || errors.Is(err, services.ErrBelowMinimumWage) ||
+            errors.Is(err, services.ErrReportingCycle) {
             status = http.StatusBadRequest
+        } else if errors.Is(err, services.ErrOutsidePayBand) {
+            status = http.StatusUnprocessableEntity
         }
         
         c.JSON(status, gin.H{
@@ -493,25 +498,35 @@ func (h *EmployeeHandler) UpdateSalary(c *gin.Context) {
         return
     }
     
-    err = h.employeeService.UpdateEmployeeSalary(id, req, userID)
+    bandCheck, err := h.employeeService.UpdateEmployeeSalary(id, req, userID)
     if err != nil {
         status := http.StatusInternalServerError
         if err.Error() == "employee not found" {
             status = http.StatusNotFound
-        } else if strings.Contains(err.Error(), "must be positive") {
+        } else if strings.Contains(err.Error(), "must be positive") || errors.Is(err, services.ErrBelowMinimumWage) {
             status = http.StatusBadRequest
+        } else if errors.Is(err, services.ErrOutsidePayBand) {
+            status = http.StatusUnprocessableEntity
         }
         
         c.JSON(status, gin.H{
-            "error":   "Salary Update Failed",
-            "message": err.Error(),
+            "error":    "Salary Update Failed",
+            "message":  err.Error(),
+            "pay_band": bandCheck,
         })
         return
     }
     
-    c.JSON(http.StatusOK, gin.H{
+    response := gin.H{
         "message": "Salary updated successfully",
-    })
+    }
+    if bandCheck != nil {
+        response["pay_band"] = bandCheck
+        if bandCheck.Warning != "" {
+            response["warning"] = bandCheck.Warning
+        }
+    }
+    c.JSON(http.StatusOK, response)
 }
 
 // GetEmployeeStats handles employee statistics
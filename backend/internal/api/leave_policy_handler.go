@@ -0,0 +1,198 @@
+/*
+Package api - IRIS Payroll System HTTP API Handlers
+
+==============================================================================
+FILE: internal/api/leave_policy_handler.go
+==============================================================================
+
+DESCRIPTION:
+    Endpoints for the leave policy catalog (maternity, paternity,
+    bereavement, marriage, union commissions, ...) and the leave balances
+    of employees. Leaves are requested through POST /absence-requests with
+    a leave_policy_id.
+
+USER PERSPECTIVE:
+    - Employees see the leaves they can request, the days left and the
+      documents to attach
+    - HR adjusts entitlements, eligibility and who pays each leave
+    - HR checks an employee's leave balances
+
+DEVELOPER GUIDELINES:
+    ✅  OK to modify: Add catalog filters
+    ⚠️  CAUTION: Changing a policy does not change requests already filed
+    📝  Employees read their balances at /me; the catalog is HR/payroll only
+
+ENDPOINTS:
+    GET    /leave-policies/me                       - Own leave balances
+    GET    /leave-policies                          - List policies (seeds the defaults)
+    GET    /leave-policies/employees/:id/balances   - Leave balances of an employee
+    POST   /leave-policies                          - Create a policy
+    PUT    /leave-policies/:id                      - Update a policy
+    DELETE /leave-policies/:id                      - Delete a policy
+
+==============================================================================
+*/
+package api
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+
+	"backend/internal/dtos"
+	"backend/internal/middleware"
+	"backend/internal/services"
+)
+
+// LeavePolicyHandler handles leave policy endpoints
+type LeavePolicyHandler struct {
+	service *services.LeavePolicyService
+}
+
+// NewLeavePolicyHandler creates a new leave policy handler
+func NewLeavePolicyHandler(service *services.LeavePolicyService) *LeavePolicyHandler {
+	return &LeavePolicyHandler{service: service}
+}
+
+// RegisterRoutes registers leave policy routes
+func (h *LeavePolicyHandler) RegisterRoutes(router *gin.RouterGroup, authMiddleware *middleware.AuthMiddleware) {
+	leaves := router.Group("/leave-policies")
+	leaves.GET("/me", h.MyBalances)
+
+	read := leaves.Group("")
+	read.Use(authMiddleware.RequireRole("admin", "hr", "hr_and_pr", "hr_blue_gray", "hr_white", "payroll_staff"))
+	{
+		read.GET("", h.ListPolicies)
+		read.GET("/employees/:id/balances", h.EmployeeBalances)
+	}
+
+	manage := leaves.Group("")
+	manage.Use(authMiddleware.RequireRole("admin", "hr", "hr_and_pr"))
+	{
+		manage.POST("", h.CreatePolicy)
+		manage.PUT("/:id", h.UpdatePolicy)
+		manage.DELETE("/:id", h.DeletePolicy)
+	}
+}
+
+// MyBalances handles GET /leave-policies/me
+func (h *LeavePolicyHandler) MyBalances(c *gin.Context) {
+	userID, _, companyID, err := middleware.GetUserFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not found in context"})
+		return
+	}
+
+	balances, err := h.service.BalancesForUser(companyID, userID, time.Now())
+	if err != nil {
+		c.JSON(organizationErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, balances)
+}
+
+// ListPolicies handles GET /leave-policies
+func (h *LeavePolicyHandler) ListPolicies(c *gin.Context) {
+	_, _, companyID, err := middleware.GetUserFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not found in context"})
+		return
+	}
+
+	policies, err := h.service.ListPolicies(companyID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, policies)
+}
+
+// EmployeeBalances handles GET /leave-policies/employees/:id/balances
+func (h *LeavePolicyHandler) EmployeeBalances(c *gin.Context) {
+	_, _, companyID, err := middleware.GetUserFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not found in context"})
+		return
+	}
+	employeeID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid employee ID"})
+		return
+	}
+
+	balances, err := h.service.EmployeeBalances(companyID, employeeID, time.Now())
+	if err != nil {
+		c.JSON(organizationErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, balances)
+}
+
+// CreatePolicy handles POST /leave-policies
+func (h *LeavePolicyHandler) CreatePolicy(c *gin.Context) {
+	_, _, companyID, err := middleware.GetUserFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not found in context"})
+		return
+	}
+	var req dtos.LeavePolicyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	policy, err := h.service.CreatePolicy(companyID, req)
+	if err != nil {
+		c.JSON(organizationErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusCreated, policy)
+}
+
+// UpdatePolicy handles PUT /leave-policies/:id
+func (h *LeavePolicyHandler) UpdatePolicy(c *gin.Context) {
+	_, _, companyID, err := middleware.GetUserFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not found in context"})
+		return
+	}
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid leave policy ID"})
+		return
+	}
+	var req dtos.LeavePolicyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	policy, err := h.service.UpdatePolicy(id, companyID, req)
+	if err != nil {
+		c.JSON(organizationErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, policy)
+}
+
+// DeletePolicy handles DELETE /leave-policies/:id
+func (h *LeavePolicyHandler) DeletePolicy(c *gin.Context) {
+	_, _, companyID, err := middleware.GetUserFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not found in context"})
+		return
+	}
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid leave policy ID"})
+		return
+	}
+
+	if err := h.service.DeletePolicy(id, companyID); err != nil {
+		c.JSON(organizationErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "leave policy deleted"})
+}
@@ -0,0 +1,90 @@
+/*
+Package api - IRIS Payroll System HTTP API Handlers
+
+==============================================================================
+FILE: internal/api/minimum_wage_handler.go
+==============================================================================
+
+DESCRIPTION:
+    Exposes the configured minimum wages (general, ZLFN and professional) and
+    the annual bulk raise that lifts employees to the new minimum wage.
+
+USER PERSPECTIVE:
+    - HR checks which minimum wage applies to each zone
+    - In January HR previews (dry_run) and then applies the minimum wage raise
+
+DEVELOPER GUIDELINES:
+    ✅  OK to modify: Add filters to the raise (department, collar type)
+    ⚠️  CAUTION: The raise is restricted to payroll/HR roles
+    📝  The amounts come from OfficialValues in the configuration, not from
+        the database
+
+ENDPOINTS:
+    GET  /minimum-wage             - Configured minimum wages
+    POST /minimum-wage/bulk-raise  - Preview or apply the minimum wage raise
+
+==============================================================================
+*/
+package api
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"backend/internal/dtos"
+	"backend/internal/middleware"
+	"backend/internal/services"
+)
+
+// MinimumWageHandler handles minimum wage endpoints
+type MinimumWageHandler struct {
+	service *services.MinimumWageService
+}
+
+// NewMinimumWageHandler creates a new minimum wage handler
+func NewMinimumWageHandler(service *services.MinimumWageService) *MinimumWageHandler {
+	return &MinimumWageHandler{service: service}
+}
+
+// RegisterRoutes registers minimum wage routes
+func (h *MinimumWageHandler) RegisterRoutes(router *gin.RouterGroup, authMiddleware *middleware.AuthMiddleware) {
+	minimumWage := router.Group("/minimum-wage")
+	{
+		minimumWage.GET("", h.GetInfo)
+
+		admin := minimumWage.Group("")
+		admin.Use(authMiddleware.RequireRole("admin", "hr", "hr_and_pr", "payroll_staff"))
+		{
+			admin.POST("/bulk-raise", h.BulkRaise)
+		}
+	}
+}
+
+// GetInfo handles GET /minimum-wage
+func (h *MinimumWageHandler) GetInfo(c *gin.Context) {
+	c.JSON(http.StatusOK, h.service.GetInfo())
+}
+
+// BulkRaise handles POST /minimum-wage/bulk-raise
+func (h *MinimumWageHandler) BulkRaise(c *gin.Context) {
+	userID, _, companyID, err := middleware.GetUserFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not found in context"})
+		return
+	}
+
+	var req dtos.MinimumWageBulkRaiseRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	result, err := h.service.BulkRaise(companyID, req, &userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
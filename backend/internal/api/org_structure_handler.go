@@ -0,0 +1,195 @@
+/*
+Package api - IRIS Payroll System HTTP API Handlers
+
+==============================================================================
+FILE: internal/api/org_structure_handler.go
+==============================================================================
+
+DESCRIPTION:
+    Handles reporting lines, the org chart and approver resolution.
+
+USER PERSPECTIVE:
+    - Everyone can browse the org chart and see who approves for them
+    - HR changes managers (effective dated) and reviews the history
+
+DEVELOPER GUIDELINES:
+    ✅  OK to modify: Add chart filters (department, depth)
+    ⚠️  CAUTION: Manager changes and rebuilds are restricted to HR roles
+    📝  A manager change that would close a reporting cycle answers 409
+
+ENDPOINTS:
+    GET  /organization/chart                            - Nested org chart (?root_id=)
+    GET  /organization/employees/:id/subordinates       - Team (?direct=true)
+    GET  /organization/employees/:id/approver           - Resolved approver (?date=)
+    GET  /organization/employees/:id/reporting-lines    - Manager history
+    POST /organization/reporting-lines                  - Change manager (effective dated)
+    POST /organization/hierarchy/rebuild                - Rebuild closure table
+
+==============================================================================
+*/
+package api
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+
+	"backend/internal/dtos"
+	"backend/internal/middleware"
+	"backend/internal/services"
+)
+
+// OrgStructureHandler handles org chart and reporting line endpoints
+type OrgStructureHandler struct {
+	service *services.OrgStructureService
+}
+
+// NewOrgStructureHandler creates a new org structure handler
+func NewOrgStructureHandler(service *services.OrgStructureService) *OrgStructureHandler {
+	return &OrgStructureHandler{service: service}
+}
+
+// RegisterRoutes registers org structure routes
+func (h *OrgStructureHandler) RegisterRoutes(router *gin.RouterGroup, authMiddleware *middleware.AuthMiddleware) {
+	org := router.Group("/organization")
+	org.GET("/chart", h.GetOrgChart)
+	org.GET("/employees/:id/subordinates", h.GetSubordinates)
+	org.GET("/employees/:id/approver", h.GetApprover)
+
+	manage := org.Group("")
+	manage.Use(authMiddleware.RequireRole("admin", "hr", "hr_and_pr"))
+	{
+		manage.GET("/employees/:id/reporting-lines", h.ListReportingLines)
+		manage.POST("/reporting-lines", h.SetManager)
+		manage.POST("/hierarchy/rebuild", h.RebuildHierarchy)
+	}
+}
+
+// GetOrgChart handles GET /organization/chart
+func (h *OrgStructureHandler) GetOrgChart(c *gin.Context) {
+	_, _, companyID, err := middleware.GetUserFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not found in context"})
+		return
+	}
+	var rootID *uuid.UUID
+	if raw := c.Query("root_id"); raw != "" {
+		id, err := uuid.Parse(raw)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid root ID"})
+			return
+		}
+		rootID = &id
+	}
+
+	chart, err := h.service.GetOrgChart(companyID, rootID)
+	if err != nil {
+		c.JSON(organizationErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"chart": chart})
+}
+
+// GetSubordinates handles GET /organization/employees/:id/subordinates
+func (h *OrgStructureHandler) GetSubordinates(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid employee ID"})
+		return
+	}
+
+	ids, err := h.service.SubordinateIDs(id, c.Query("direct") == "true")
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"employee_ids": ids, "count": len(ids)})
+}
+
+// GetApprover handles GET /organization/employees/:id/approver
+func (h *OrgStructureHandler) GetApprover(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid employee ID"})
+		return
+	}
+	date := time.Now()
+	if raw := c.Query("date"); raw != "" {
+		if date, err = time.Parse("2006-01-02", raw); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid date, expected YYYY-MM-DD"})
+			return
+		}
+	}
+
+	resolution, err := h.service.ResolveApprover(id, date)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, resolution)
+}
+
+// ListReportingLines handles GET /organization/employees/:id/reporting-lines
+func (h *OrgStructureHandler) ListReportingLines(c *gin.Context) {
+	_, _, companyID, err := middleware.GetUserFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not found in context"})
+		return
+	}
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid employee ID"})
+		return
+	}
+
+	lines, err := h.service.ListReportingLines(id, companyID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"reporting_lines": lines, "count": len(lines)})
+}
+
+// SetManager handles POST /organization/reporting-lines
+func (h *OrgStructureHandler) SetManager(c *gin.Context) {
+	userID, _, companyID, err := middleware.GetUserFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not found in context"})
+		return
+	}
+	var req dtos.ReportingLineRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	line, err := h.service.SetManager(companyID, req, userID)
+	if err != nil {
+		status := organizationErrorStatus(err)
+		if errors.Is(err, services.ErrReportingCycle) {
+			status = http.StatusConflict
+		}
+		c.JSON(status, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusCreated, line)
+}
+
+// RebuildHierarchy handles POST /organization/hierarchy/rebuild
+func (h *OrgStructureHandler) RebuildHierarchy(c *gin.Context) {
+	_, _, companyID, err := middleware.GetUserFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not found in context"})
+		return
+	}
+
+	rows, err := h.service.RebuildHierarchy(companyID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "hierarchy rebuilt", "rows": rows})
+}
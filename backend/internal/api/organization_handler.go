@@ -0,0 +1,398 @@
+/*
+Package api - IRIS Payroll System HTTP API Handlers
+
+==============================================================================
+FILE: internal/api/organization_handler.go
+==============================================================================
+
+DESCRIPTION:
+    Handles the organizational catalog (departments, salary grades,
+    positions), effective-dated employee assignments and the compa-ratio
+    pay-equity report.
+
+USER PERSPECTIVE:
+    - Any authenticated user can browse departments and positions
+    - HR maintains the catalog and moves employees between positions
+    - Compensation reviews the compa-ratio report by department
+
+DEVELOPER GUIDELINES:
+    ✅  OK to modify: Add report filters
+    ⚠️  CAUTION: Catalog changes and reports are restricted to HR/payroll roles
+    📝  Most HR handlers map their errors through organizationErrorStatus;
+        a new phrase there changes their status codes too
+
+ENDPOINTS:
+    GET    /organization/departments                 - Department tree
+    POST   /organization/departments                 - Create department
+    PUT    /organization/departments/:id             - Update department
+    DELETE /organization/departments/:id             - Delete empty department
+    GET    /organization/positions                   - Positions (?department_id=)
+    POST   /organization/positions                   - Create position
+    PUT    /organization/positions/:id               - Update position
+    DELETE /organization/positions/:id               - Delete vacant position
+    GET    /organization/grades                      - Salary grades and bands
+    POST   /organization/grades                      - Create grade
+    PUT    /organization/grades/:id                  - Update grade
+    DELETE /organization/grades/:id                  - Delete unused grade
+    POST   /organization/assignments                 - Move employee (effective dated)
+    GET    /organization/employees/:id/assignments   - Assignment history
+    GET    /organization/reports/compa-ratio         - Compa-ratio by department
+
+==============================================================================
+*/
+package api
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+
+	"backend/internal/dtos"
+	"backend/internal/middleware"
+	"backend/internal/services"
+)
+
+// OrganizationHandler handles organizational catalog endpoints
+type OrganizationHandler struct {
+	service *services.OrganizationService
+}
+
+// NewOrganizationHandler creates a new organization handler
+func NewOrganizationHandler(service *services.OrganizationService) *OrganizationHandler {
+	return &OrganizationHandler{service: service}
+}
+
+// RegisterRoutes registers organization routes
+func (h *OrganizationHandler) RegisterRoutes(router *gin.RouterGroup, authMiddleware *middleware.AuthMiddleware) {
+	org := router.Group("/organization")
+	org.GET("/departments", h.ListDepartments)
+	org.GET("/positions", h.ListPositions)
+
+	manage := org.Group("")
+	manage.Use(authMiddleware.RequireRole("admin", "hr", "hr_and_pr", "payroll_staff"))
+	{
+		manage.POST("/departments", h.CreateDepartment)
+		manage.PUT("/departments/:id", h.UpdateDepartment)
+		manage.DELETE("/departments/:id", h.DeleteDepartment)
+		manage.POST("/positions", h.CreatePosition)
+		manage.PUT("/positions/:id", h.UpdatePosition)
+		manage.DELETE("/positions/:id", h.DeletePosition)
+		manage.GET("/grades", h.ListGrades)
+		manage.POST("/grades", h.CreateGrade)
+		manage.PUT("/grades/:id", h.UpdateGrade)
+		manage.DELETE("/grades/:id", h.DeleteGrade)
+		manage.POST("/assignments", h.AssignEmployee)
+		manage.GET("/employees/:id/assignments", h.ListAssignments)
+		manage.GET("/reports/compa-ratio", h.CompaRatioReport)
+	}
+}
+
+// ListDepartments handles GET /organization/departments
+func (h *OrganizationHandler) ListDepartments(c *gin.Context) {
+	_, _, companyID, err := middleware.GetUserFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not found in context"})
+		return
+	}
+
+	departments, err := h.service.ListDepartmentTree(companyID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"departments": departments})
+}
+
+// CreateDepartment handles POST /organization/departments
+func (h *OrganizationHandler) CreateDepartment(c *gin.Context) {
+	_, _, companyID, err := middleware.GetUserFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not found in context"})
+		return
+	}
+	var req dtos.DepartmentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	department, err := h.service.CreateDepartment(companyID, req)
+	if err != nil {
+		c.JSON(organizationErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusCreated, department)
+}
+
+// UpdateDepartment handles PUT /organization/departments/:id
+func (h *OrganizationHandler) UpdateDepartment(c *gin.Context) {
+	_, _, companyID, err := middleware.GetUserFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not found in context"})
+		return
+	}
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid department ID"})
+		return
+	}
+	var req dtos.DepartmentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	department, err := h.service.UpdateDepartment(id, companyID, req)
+	if err != nil {
+		c.JSON(organizationErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, department)
+}
+
+// DeleteDepartment handles DELETE /organization/departments/:id
+func (h *OrganizationHandler) DeleteDepartment(c *gin.Context) {
+	h.deleteByID(c, "invalid department ID", h.service.DeleteDepartment)
+}
+
+// ListPositions handles GET /organization/positions
+func (h *OrganizationHandler) ListPositions(c *gin.Context) {
+	_, _, companyID, err := middleware.GetUserFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not found in context"})
+		return
+	}
+	var departmentID *uuid.UUID
+	if raw := c.Query("department_id"); raw != "" {
+		id, err := uuid.Parse(raw)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid department ID"})
+			return
+		}
+		departmentID = &id
+	}
+
+	positions, err := h.service.ListPositions(companyID, departmentID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"positions": positions, "count": len(positions)})
+}
+
+// CreatePosition handles POST /organization/positions
+func (h *OrganizationHandler) CreatePosition(c *gin.Context) {
+	_, _, companyID, err := middleware.GetUserFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not found in context"})
+		return
+	}
+	var req dtos.PositionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	position, err := h.service.CreatePosition(companyID, req)
+	if err != nil {
+		c.JSON(organizationErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusCreated, position)
+}
+
+// UpdatePosition handles PUT /organization/positions/:id
+func (h *OrganizationHandler) UpdatePosition(c *gin.Context) {
+	_, _, companyID, err := middleware.GetUserFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not found in context"})
+		return
+	}
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid position ID"})
+		return
+	}
+	var req dtos.PositionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	position, err := h.service.UpdatePosition(id, companyID, req)
+	if err != nil {
+		c.JSON(organizationErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, position)
+}
+
+// DeletePosition handles DELETE /organization/positions/:id
+func (h *OrganizationHandler) DeletePosition(c *gin.Context) {
+	h.deleteByID(c, "invalid position ID", h.service.DeletePosition)
+}
+
+// ListGrades handles GET /organization/grades
+func (h *OrganizationHandler) ListGrades(c *gin.Context) {
+	_, _, companyID, err := middleware.GetUserFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not found in context"})
+		return
+	}
+
+	grades, err := h.service.ListGrades(companyID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"grades": grades, "count": len(grades)})
+}
+
+// CreateGrade handles POST /organization/grades
+func (h *OrganizationHandler) CreateGrade(c *gin.Context) {
+	_, _, companyID, err := middleware.GetUserFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not found in context"})
+		return
+	}
+	var req dtos.SalaryGradeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	grade, err := h.service.CreateGrade(companyID, req)
+	if err != nil {
+		c.JSON(organizationErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusCreated, grade)
+}
+
+// UpdateGrade handles PUT /organization/grades/:id
+func (h *OrganizationHandler) UpdateGrade(c *gin.Context) {
+	_, _, companyID, err := middleware.GetUserFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not found in context"})
+		return
+	}
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid grade ID"})
+		return
+	}
+	var req dtos.SalaryGradeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	grade, err := h.service.UpdateGrade(id, companyID, req)
+	if err != nil {
+		c.JSON(organizationErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, grade)
+}
+
+// DeleteGrade handles DELETE /organization/grades/:id
+func (h *OrganizationHandler) DeleteGrade(c *gin.Context) {
+	h.deleteByID(c, "invalid grade ID", h.service.DeleteGrade)
+}
+
+// AssignEmployee handles POST /organization/assignments
+func (h *OrganizationHandler) AssignEmployee(c *gin.Context) {
+	userID, _, companyID, err := middleware.GetUserFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not found in context"})
+		return
+	}
+	var req dtos.EmployeeAssignmentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	assignment, err := h.service.AssignEmployee(companyID, req, userID)
+	if err != nil {
+		c.JSON(organizationErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusCreated, assignment)
+}
+
+// ListAssignments handles GET /organization/employees/:id/assignments
+func (h *OrganizationHandler) ListAssignments(c *gin.Context) {
+	_, _, companyID, err := middleware.GetUserFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not found in context"})
+		return
+	}
+	employeeID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid employee ID"})
+		return
+	}
+
+	assignments, err := h.service.ListAssignments(employeeID, companyID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"assignments": assignments, "count": len(assignments)})
+}
+
+// CompaRatioReport handles GET /organization/reports/compa-ratio
+func (h *OrganizationHandler) CompaRatioReport(c *gin.Context) {
+	_, _, companyID, err := middleware.GetUserFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not found in context"})
+		return
+	}
+
+	report, err := h.service.CompaRatioReport(companyID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, report)
+}
+
+// deleteByID runs a delete action for the :id path parameter
+func (h *OrganizationHandler) deleteByID(c *gin.Context, invalidMsg string, action func(id, companyID uuid.UUID) error) {
+	_, _, companyID, err := middleware.GetUserFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not found in context"})
+		return
+	}
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": invalidMsg})
+		return
+	}
+
+	if err := action(id, companyID); err != nil {
+		c.JSON(organizationErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "deleted"})
+}
+
+// organizationErrorStatus maps service errors to HTTP status codes
+func organizationErrorStatus(err error) int {
+	msg := err.Error()
+	switch {
+	case strings.Contains(msg, "not found"):
+		return http.StatusNotFound
+	case strings.Contains(msg, "already exists"), strings.Contains(msg, "cannot be deleted"),
+		strings.Contains(msg, "no vacant seats"):
+		return http.StatusConflict
+	case strings.Contains(msg, "cannot be"), strings.Contains(msg, "must"), strings.Contains(msg, "required"),
+		strings.Contains(msg, "not active"):
+		return http.StatusBadRequest
+	default:
+		return http.StatusInternalServerError
+	}
+}
@@ -0,0 +1,247 @@
+/*
+Package api - IRIS Payroll System HTTP API Handlers
+
+==============================================================================
+FILE: internal/api/overtime_handler.go
+==============================================================================
+
+DESCRIPTION:
+    Endpoints for overtime pre-authorization: filing a request before the
+    overtime is worked, the approval chain, cancellation and the period-close
+    reconciliation of authorized against worked overtime.
+
+USER PERSPECTIVE:
+    - Employees request overtime for themselves; supervisors for their team
+    - Approvers see the requests waiting at their stage
+    - Payroll reconciles a period and reviews overtime worked without approval
+
+DEVELOPER GUIDELINES:
+    ✅  OK to modify: Add list filters
+    ⚠️  CAUTION: Who may file or approve is decided by OvertimeService, not by
+        the route roles (a supervisor acts only on their reporting chain)
+    📝  ErrOvertimeNotAllowed answers 403; other errors map like the
+        organization endpoints
+
+ENDPOINTS:
+    POST   /overtime-requests               - Request overtime
+    GET    /overtime-requests/mine          - Requests for or filed by the current user
+    GET    /overtime-requests/pending       - Requests waiting at the current user's stage
+    GET    /overtime-requests               - List requests (?employee_id=&status=&start_date=&end_date=)
+    POST   /overtime-requests/:id/approve   - Approve or decline at the current stage
+    POST   /overtime-requests/:id/cancel    - Cancel a request
+    POST   /overtime-requests/reconcile     - Reconcile a payroll period
+
+==============================================================================
+*/
+package api
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+
+	"backend/internal/dtos"
+	"backend/internal/middleware"
+	"backend/internal/services"
+)
+
+// OvertimeHandler handles overtime request endpoints
+type OvertimeHandler struct {
+	service *services.OvertimeService
+}
+
+// NewOvertimeHandler creates a new overtime handler
+func NewOvertimeHandler(service *services.OvertimeService) *OvertimeHandler {
+	return &OvertimeHandler{service: service}
+}
+
+// RegisterRoutes registers overtime request routes
+func (h *OvertimeHandler) RegisterRoutes(router *gin.RouterGroup, authMiddleware *middleware.AuthMiddleware) {
+	overtime := router.Group("/overtime-requests")
+	{
+		overtime.POST("", h.CreateRequest)
+		overtime.GET("/mine", h.MyRequests)
+		overtime.POST("/:id/cancel", h.Cancel)
+	}
+
+	approvers := router.Group("/overtime-requests")
+	approvers.Use(authMiddleware.RequireRole("admin", "hr", "hr_and_pr", "hr_blue_gray", "payroll_staff", "supervisor", "manager", "sup_and_gm"))
+	{
+		approvers.GET("/pending", h.PendingForApprover)
+		approvers.POST("/:id/approve", h.Act)
+	}
+
+	payroll := router.Group("/overtime-requests")
+	payroll.Use(authMiddleware.RequireRole("admin", "hr", "hr_and_pr", "payroll_staff"))
+	{
+		payroll.GET("", h.ListRequests)
+		payroll.POST("/reconcile", h.Reconcile)
+	}
+}
+
+// overtimeErrorStatus maps overtime service errors to HTTP statuses
+func overtimeErrorStatus(err error) int {
+	if errors.Is(err, services.ErrOvertimeNotAllowed) {
+		return http.StatusForbidden
+	}
+	return organizationErrorStatus(err)
+}
+
+// CreateRequest handles POST /overtime-requests
+func (h *OvertimeHandler) CreateRequest(c *gin.Context) {
+	userID, _, companyID, err := middleware.GetUserFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not found in context"})
+		return
+	}
+	var req dtos.OvertimeRequestInput
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	request, err := h.service.CreateRequest(companyID, userID, req)
+	if err != nil {
+		c.JSON(overtimeErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusCreated, request)
+}
+
+// MyRequests handles GET /overtime-requests/mine
+func (h *OvertimeHandler) MyRequests(c *gin.Context) {
+	userID, _, companyID, err := middleware.GetUserFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not found in context"})
+		return
+	}
+
+	requests, err := h.service.MyRequests(companyID, userID)
+	if err != nil {
+		c.JSON(overtimeErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"requests": requests, "count": len(requests)})
+}
+
+// PendingForApprover handles GET /overtime-requests/pending
+func (h *OvertimeHandler) PendingForApprover(c *gin.Context) {
+	userID, _, companyID, err := middleware.GetUserFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not found in context"})
+		return
+	}
+
+	requests, err := h.service.PendingForApprover(companyID, userID)
+	if err != nil {
+		c.JSON(overtimeErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"requests": requests, "count": len(requests)})
+}
+
+// ListRequests handles GET /overtime-requests
+func (h *OvertimeHandler) ListRequests(c *gin.Context) {
+	_, _, companyID, err := middleware.GetUserFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not found in context"})
+		return
+	}
+	filter := dtos.OvertimeRequestFilter{Status: c.Query("status")}
+	if raw := c.Query("employee_id"); raw != "" {
+		id, err := uuid.Parse(raw)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid employee ID"})
+			return
+		}
+		filter.EmployeeID = &id
+	}
+	for param, target := range map[string]**time.Time{"start_date": &filter.StartDate, "end_date": &filter.EndDate} {
+		if raw := c.Query(param); raw != "" {
+			date, err := time.Parse("2006-01-02", raw)
+			if err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "invalid " + param + ", expected YYYY-MM-DD"})
+				return
+			}
+			*target = &date
+		}
+	}
+
+	requests, err := h.service.ListRequests(companyID, filter)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"requests": requests, "count": len(requests)})
+}
+
+// Act handles POST /overtime-requests/:id/approve
+func (h *OvertimeHandler) Act(c *gin.Context) {
+	userID, _, companyID, err := middleware.GetUserFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not found in context"})
+		return
+	}
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid overtime request ID"})
+		return
+	}
+	var req dtos.OvertimeActionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	request, err := h.service.Act(id, companyID, userID, req)
+	if err != nil {
+		c.JSON(overtimeErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, request)
+}
+
+// Cancel handles POST /overtime-requests/:id/cancel
+func (h *OvertimeHandler) Cancel(c *gin.Context) {
+	userID, _, companyID, err := middleware.GetUserFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not found in context"})
+		return
+	}
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid overtime request ID"})
+		return
+	}
+
+	request, err := h.service.Cancel(id, companyID, userID)
+	if err != nil {
+		c.JSON(overtimeErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, request)
+}
+
+// Reconcile handles POST /overtime-requests/reconcile
+func (h *OvertimeHandler) Reconcile(c *gin.Context) {
+	_, _, companyID, err := middleware.GetUserFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not found in context"})
+		return
+	}
+	var req dtos.OvertimeReconcileRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	report, err := h.service.Reconcile(companyID, req)
+	if err != nil {
+		c.JSON(overtimeErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, report)
+}
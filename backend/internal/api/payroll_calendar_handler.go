@@ -0,0 +1,217 @@
+/*
+Package api - IRIS Payroll System HTTP API Handlers
+
+==============================================================================
+FILE: internal/api/payroll_calendar_handler.go
+==============================================================================
+
+DESCRIPTION:
+    Handles the annual payroll calendar generator and the merged holiday
+    catalog used to compute payment and cut-off dates.
+
+USER PERSPECTIVE:
+    - Generate (or preview) every period of a fiscal year for a pay group
+    - Review which payment dates were moved because of holidays/weekends
+    - See federal, state and company holidays in one list
+    - See which days of a range an employee works (shifts and holidays),
+      i.e. how many days a vacation would take
+
+DEVELOPER GUIDELINES:
+    ✅  OK to modify: Add filters to the holiday catalog
+    ⚠️  CAUTION: Generation is restricted to payroll/HR roles
+    📝  Generate answers 201 only when it created periods; a dry run or a
+        year already generated answers 200
+
+ENDPOINTS:
+    GET  /payroll/calendar/holidays                - Merged holiday catalog (?year= or ?start_date=&end_date=)
+    GET  /payroll/calendar/work-days               - Own work days (?start_date=&end_date=)
+    GET  /payroll/calendar/employees/:id/work-days - Work days of an employee (?start_date=&end_date=)
+    POST /payroll/calendar/generate                - Generate fiscal year periods
+
+==============================================================================
+*/
+package api
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+
+	"backend/internal/dtos"
+	"backend/internal/middleware"
+	"backend/internal/services"
+)
+
+// PayrollCalendarHandler handles payroll calendar endpoints
+type PayrollCalendarHandler struct {
+	service *services.PayrollCalendarService
+}
+
+// NewPayrollCalendarHandler creates a new payroll calendar handler
+func NewPayrollCalendarHandler(service *services.PayrollCalendarService) *PayrollCalendarHandler {
+	return &PayrollCalendarHandler{service: service}
+}
+
+// RegisterRoutes registers payroll calendar routes
+func (h *PayrollCalendarHandler) RegisterRoutes(router *gin.RouterGroup, authMiddleware *middleware.AuthMiddleware) {
+	calendar := router.Group("/payroll/calendar")
+	{
+		calendar.GET("/holidays", h.GetHolidays)
+		calendar.GET("/work-days", h.GetMyWorkDays)
+
+		admin := calendar.Group("")
+		admin.Use(authMiddleware.RequireRole("admin", "hr", "hr_and_pr", "payroll_staff"))
+		{
+			admin.POST("/generate", h.GenerateFiscalYear)
+		}
+
+		hr := calendar.Group("")
+		hr.Use(authMiddleware.RequireRole("admin", "hr", "hr_and_pr", "hr_blue_gray", "hr_white", "payroll_staff"))
+		{
+			hr.GET("/employees/:id/work-days", h.GetEmployeeWorkDays)
+		}
+	}
+}
+
+// GetHolidays handles GET /payroll/calendar/holidays
+func (h *PayrollCalendarHandler) GetHolidays(c *gin.Context) {
+	_, _, companyID, err := middleware.GetUserFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not found in context"})
+		return
+	}
+
+	var start, end time.Time
+	if c.Query("start_date") != "" || c.Query("end_date") != "" {
+		start, err = time.ParseInLocation("2006-01-02", c.Query("start_date"), time.Local)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid start_date format (use YYYY-MM-DD)"})
+			return
+		}
+		end, err = time.ParseInLocation("2006-01-02", c.Query("end_date"), time.Local)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid end_date format (use YYYY-MM-DD)"})
+			return
+		}
+	} else {
+		year, err := strconv.Atoi(c.DefaultQuery("year", strconv.Itoa(time.Now().Year())))
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid year"})
+			return
+		}
+		start = time.Date(year, 1, 1, 0, 0, 0, 0, time.Local)
+		end = time.Date(year, 12, 31, 0, 0, 0, 0, time.Local)
+	}
+
+	if end.Before(start) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "end_date cannot be before start_date"})
+		return
+	}
+
+	holidays, err := h.service.GetHolidayCatalog(companyID, start, end)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"holidays": holidays, "count": len(holidays)})
+}
+
+// GetMyWorkDays handles GET /payroll/calendar/work-days
+func (h *PayrollCalendarHandler) GetMyWorkDays(c *gin.Context) {
+	userID, _, companyID, err := middleware.GetUserFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not found in context"})
+		return
+	}
+	start, end, ok := workDaysRange(c)
+	if !ok {
+		return
+	}
+
+	calendar, err := h.service.GetWorkCalendarForUser(companyID, userID, start, end)
+	if err != nil {
+		c.JSON(organizationErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, calendar)
+}
+
+// GetEmployeeWorkDays handles GET /payroll/calendar/employees/:id/work-days
+func (h *PayrollCalendarHandler) GetEmployeeWorkDays(c *gin.Context) {
+	_, _, companyID, err := middleware.GetUserFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not found in context"})
+		return
+	}
+	employeeID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid employee ID"})
+		return
+	}
+	start, end, ok := workDaysRange(c)
+	if !ok {
+		return
+	}
+
+	calendar, err := h.service.GetWorkCalendar(companyID, employeeID, start, end)
+	if err != nil {
+		c.JSON(organizationErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, calendar)
+}
+
+// workDaysRange parses the required start_date/end_date of the work-days
+// endpoints (at most a year), writing the error response when invalid
+func workDaysRange(c *gin.Context) (time.Time, time.Time, bool) {
+	start, err := time.ParseInLocation("2006-01-02", c.Query("start_date"), time.Local)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid start_date format (use YYYY-MM-DD)"})
+		return start, start, false
+	}
+	end, err := time.ParseInLocation("2006-01-02", c.Query("end_date"), time.Local)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid end_date format (use YYYY-MM-DD)"})
+		return start, start, false
+	}
+	if end.Before(start) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "end_date cannot be before start_date"})
+		return start, end, false
+	}
+	if end.After(start.AddDate(1, 0, 0)) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "the range cannot exceed one year"})
+		return start, end, false
+	}
+	return start, end, true
+}
+
+// GenerateFiscalYear handles POST /payroll/calendar/generate
+func (h *PayrollCalendarHandler) GenerateFiscalYear(c *gin.Context) {
+	userID, _, companyID, err := middleware.GetUserFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not found in context"})
+		return
+	}
+
+	var req dtos.GeneratePayrollCalendarRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	result, err := h.service.GenerateFiscalYear(companyID, &userID, req)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	status := http.StatusCreated
+	if req.DryRun || result.Created == 0 {
+		status = http.StatusOK
+	}
+	c.JSON(status, result)
+}
@@ -0,0 +1,224 @@
+/*
+Package api - IRIS Payroll System HTTP API Handlers
+
+==============================================================================
+FILE: internal/api/project_cost_handler.go
+==============================================================================
+
+DESCRIPTION:
+    Endpoints for project budget tracking and payroll cost allocation:
+    budget vs. actual per project and task, task budgets, budget alerts,
+    the monthly allocation of payroll cost to projects and cost centers and
+    its export as the accounting póliza.
+
+USER PERSPECTIVE:
+    - Project managers follow the burn of their projects and tasks
+    - Finance closes the month with the cost allocation and posts the póliza
+
+DEVELOPER GUIDELINES:
+    ✅  OK to modify: Add report filters
+    ⚠️  CAUTION: The payroll basis and the allocation expose salary cost;
+        keep them on finance and payroll roles
+    📝  ?basis= defaults to rate when omitted
+
+ENDPOINTS:
+    GET  /project-costs/projects/:id/budget               - Budget vs. actual (?basis=rate|payroll)
+    PUT  /project-costs/projects/:id/tasks/:taskId/budget - Set a task's hours and amount budget
+    GET  /project-costs/projects/:id/alerts               - Budget alert history
+    POST /project-costs/alerts/check                      - Raise crossed thresholds (?basis=rate|payroll)
+    GET  /project-costs/allocation                        - Monthly payroll cost allocation (?year=&month=)
+    GET  /project-costs/allocation/poliza                 - Allocation as an accounting póliza (Excel)
+
+==============================================================================
+*/
+package api
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+
+	"backend/internal/dtos"
+	"backend/internal/middleware"
+	"backend/internal/models"
+	"backend/internal/services"
+)
+
+// ProjectCostHandler handles project budget and cost allocation endpoints
+type ProjectCostHandler struct {
+	service *services.ProjectCostService
+}
+
+// NewProjectCostHandler creates a new project cost handler
+func NewProjectCostHandler(service *services.ProjectCostService) *ProjectCostHandler {
+	return &ProjectCostHandler{service: service}
+}
+
+// RegisterRoutes registers project cost routes
+func (h *ProjectCostHandler) RegisterRoutes(router *gin.RouterGroup, authMiddleware *middleware.AuthMiddleware) {
+	projects := router.Group("/project-costs/projects")
+	projects.Use(authMiddleware.RequireRole("admin", "hr", "hr_and_pr", "payroll_staff", "accountant", "manager", "sup_and_gm"))
+	{
+		projects.GET("/:id/budget", h.ProjectBudget)
+		projects.PUT("/:id/tasks/:taskId/budget", h.SetTaskBudget)
+		projects.GET("/:id/alerts", h.ListAlerts)
+	}
+
+	finance := router.Group("/project-costs")
+	finance.Use(authMiddleware.RequireRole("admin", "hr_and_pr", "payroll_staff", "accountant"))
+	{
+		finance.POST("/alerts/check", h.CheckAlerts)
+		finance.GET("/allocation", h.Allocation)
+		finance.GET("/allocation/poliza", h.ExportPoliza)
+	}
+}
+
+// ProjectBudget handles GET /project-costs/projects/:id/budget
+func (h *ProjectCostHandler) ProjectBudget(c *gin.Context) {
+	_, _, companyID, err := middleware.GetUserFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not found in context"})
+		return
+	}
+	projectID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid project ID"})
+		return
+	}
+
+	report, err := h.service.ProjectBudget(companyID, projectID, c.DefaultQuery("basis", models.CostBasisRate))
+	if err != nil {
+		c.JSON(organizationErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, report)
+}
+
+// SetTaskBudget handles PUT /project-costs/projects/:id/tasks/:taskId/budget
+func (h *ProjectCostHandler) SetTaskBudget(c *gin.Context) {
+	_, _, companyID, err := middleware.GetUserFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not found in context"})
+		return
+	}
+	projectID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid project ID"})
+		return
+	}
+	taskID, err := uuid.Parse(c.Param("taskId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid task ID"})
+		return
+	}
+	var req dtos.TaskBudgetRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	task, err := h.service.SetTaskBudget(companyID, projectID, taskID, req)
+	if err != nil {
+		c.JSON(organizationErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, task)
+}
+
+// ListAlerts handles GET /project-costs/projects/:id/alerts
+func (h *ProjectCostHandler) ListAlerts(c *gin.Context) {
+	_, _, companyID, err := middleware.GetUserFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not found in context"})
+		return
+	}
+	projectID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid project ID"})
+		return
+	}
+
+	alerts, err := h.service.ListBudgetAlerts(companyID, projectID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"alerts": alerts, "count": len(alerts)})
+}
+
+// CheckAlerts handles POST /project-costs/alerts/check
+func (h *ProjectCostHandler) CheckAlerts(c *gin.Context) {
+	userID, _, companyID, err := middleware.GetUserFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not found in context"})
+		return
+	}
+
+	result, err := h.service.CheckBudgetAlerts(companyID, userID, c.DefaultQuery("basis", models.CostBasisRate))
+	if err != nil {
+		c.JSON(organizationErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, result)
+}
+
+// allocationMonth reads ?year=&month= for the allocation endpoints
+func allocationMonth(c *gin.Context) (int, int, error) {
+	year, err := strconv.Atoi(c.Query("year"))
+	if err != nil || year < 2000 {
+		return 0, 0, errors.New("year is required")
+	}
+	month, err := strconv.Atoi(c.Query("month"))
+	if err != nil || month < 1 || month > 12 {
+		return 0, 0, errors.New("month must be between 1 and 12")
+	}
+	return year, month, nil
+}
+
+// Allocation handles GET /project-costs/allocation
+func (h *ProjectCostHandler) Allocation(c *gin.Context) {
+	_, _, companyID, err := middleware.GetUserFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not found in context"})
+		return
+	}
+	year, month, err := allocationMonth(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	report, err := h.service.MonthlyAllocation(companyID, year, month)
+	if err != nil {
+		c.JSON(organizationErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, report)
+}
+
+// ExportPoliza handles GET /project-costs/allocation/poliza
+func (h *ProjectCostHandler) ExportPoliza(c *gin.Context) {
+	_, _, companyID, err := middleware.GetUserFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not found in context"})
+		return
+	}
+	year, month, err := allocationMonth(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	file, err := h.service.ExportPoliza(companyID, year, month)
+	if err != nil {
+		c.JSON(organizationErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	filename := fmt.Sprintf("poliza_costo_nomina_%d%02d.xlsx", year, month)
+	c.Header("Content-Disposition", "attachment; filename="+filename)
+	c.Data(http.StatusOK, "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet", file)
+}
@@ -0,0 +1,650 @@
+/*
+Package api - IRIS Payroll System HTTP API Handlers
+
+==============================================================================
+FILE: internal/api/roster_handler.go
+==============================================================================
+
+DESCRIPTION:
+    Endpoints for rotating shift schedules: rotation patterns and their
+    assignment to teams, minimum-staffing rules and leave blackouts
+    (inventory week, December peak), the generated roster with
+    its coverage gaps, the Excel export, shift swaps and the swap
+    marketplace where employees offer shifts to colleagues.
+
+USER PERSPECTIVE:
+    - HR defines rotations (4x3, 6x1, morning/afternoon/night) and assigns
+      them to teams
+    - Planners review the roster of any range and where staffing falls short
+    - Employees and supervisors request swaps; planners approve them
+    - Employees offer a shift, an eligible colleague takes it and the
+      supervisor approves it through the same review endpoint
+
+DEVELOPER GUIDELINES:
+    ✅  OK to modify: Add roster filters
+    ⚠️  CAUTION: Approving a swap writes ShiftExceptions that the attendance
+        evaluation picks up on its next run
+    📝  Employees may only request swaps they take part in; planners may
+        request any (ErrRosterNotAllowed answers 403)
+
+ENDPOINTS:
+    GET    /roster                       - Roster and coverage gaps (?start_date=&end_date=&team=&production_area=&employee_id=)
+    GET    /roster/export                - Roster as Excel (same filters)
+    GET    /roster/patterns              - List rotation patterns
+    POST   /roster/patterns              - Create a rotation pattern
+    PUT    /roster/patterns/:id          - Update a rotation pattern
+    DELETE /roster/patterns/:id          - Delete a rotation pattern
+    GET    /roster/assignments           - List rotation assignments
+    POST   /roster/assignments           - Put a team or employee on a rotation
+    DELETE /roster/assignments/:id       - Delete an assignment
+    GET    /roster/staffing-rules        - List minimum-staffing rules
+    POST   /roster/staffing-rules        - Create a staffing rule
+    PUT    /roster/staffing-rules/:id    - Update a staffing rule
+    DELETE /roster/staffing-rules/:id    - Delete a staffing rule
+    GET    /roster/blackouts             - List leave blackouts
+    POST   /roster/blackouts             - Create a blackout
+    PUT    /roster/blackouts/:id         - Update a blackout
+    DELETE /roster/blackouts/:id         - Delete a blackout
+    POST   /roster/swaps                 - Request a swap
+    GET    /roster/swaps                 - List swaps (?status=)
+    POST   /roster/swaps/:id/review      - Approve or reject a swap or accepted offer
+    POST   /roster/offers                - Offer own shift of a day
+    GET    /roster/offers                - Upcoming offers visible to the user
+    POST   /roster/offers/:id/accept     - Take an open offer
+    POST   /roster/offers/:id/cancel     - Withdraw an offer before review
+
+==============================================================================
+*/
+package api
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+
+	"backend/internal/dtos"
+	"backend/internal/middleware"
+	"backend/internal/models"
+	"backend/internal/services"
+)
+
+// RosterHandler handles roster endpoints
+type RosterHandler struct {
+	service *services.RosterService
+}
+
+// NewRosterHandler creates a new roster handler
+func NewRosterHandler(service *services.RosterService) *RosterHandler {
+	return &RosterHandler{service: service}
+}
+
+// RegisterRoutes registers roster routes
+func (h *RosterHandler) RegisterRoutes(router *gin.RouterGroup, authMiddleware *middleware.AuthMiddleware) {
+	swaps := router.Group("/roster/swaps")
+	{
+		swaps.POST("", h.RequestSwap)
+	}
+
+	offers := router.Group("/roster/offers")
+	{
+		offers.POST("", h.OfferShift)
+		offers.GET("", h.ListOffers)
+		offers.POST("/:id/accept", h.AcceptOffer)
+		offers.POST("/:id/cancel", h.CancelOffer)
+	}
+
+	planners := router.Group("/roster")
+	planners.Use(authMiddleware.RequireRole("admin", "hr", "hr_and_pr", "hr_blue_gray", "hr_white", "supervisor", "manager", "sup_and_gm", "payroll_staff"))
+	{
+		planners.GET("", h.GetRoster)
+		planners.GET("/export", h.ExportRoster)
+		planners.GET("/patterns", h.ListPatterns)
+		planners.GET("/assignments", h.ListAssignments)
+		planners.GET("/staffing-rules", h.ListStaffingRules)
+		planners.GET("/blackouts", h.ListBlackouts)
+		planners.GET("/swaps", h.ListSwaps)
+	}
+
+	reviewers := router.Group("/roster")
+	reviewers.Use(authMiddleware.RequireRole("admin", "hr", "hr_and_pr", "hr_blue_gray", "hr_white", "supervisor", "manager", "sup_and_gm"))
+	{
+		reviewers.POST("/swaps/:id/review", h.ReviewSwap)
+	}
+
+	manage := router.Group("/roster")
+	manage.Use(authMiddleware.RequireRole("admin", "hr", "hr_and_pr", "hr_blue_gray"))
+	{
+		manage.POST("/patterns", h.CreatePattern)
+		manage.PUT("/patterns/:id", h.UpdatePattern)
+		manage.DELETE("/patterns/:id", h.DeletePattern)
+		manage.POST("/assignments", h.CreateAssignment)
+		manage.DELETE("/assignments/:id", h.DeleteAssignment)
+		manage.POST("/staffing-rules", h.CreateStaffingRule)
+		manage.PUT("/staffing-rules/:id", h.UpdateStaffingRule)
+		manage.DELETE("/staffing-rules/:id", h.DeleteStaffingRule)
+		manage.POST("/blackouts", h.CreateBlackout)
+		manage.PUT("/blackouts/:id", h.UpdateBlackout)
+		manage.DELETE("/blackouts/:id", h.DeleteBlackout)
+	}
+}
+
+// rosterErrorStatus maps roster service errors to HTTP statuses
+func rosterErrorStatus(err error) int {
+	if errors.Is(err, services.ErrRosterNotAllowed) {
+		return http.StatusForbidden
+	}
+	return organizationErrorStatus(err)
+}
+
+// rosterFilter reads the roster filters; the range defaults to the next 28 days
+func rosterFilter(c *gin.Context) (dtos.RosterFilter, error) {
+	start := time.Now().UTC().Truncate(24 * time.Hour)
+	filter := dtos.RosterFilter{
+		StartDate:      start,
+		EndDate:        start.AddDate(0, 0, 27),
+		TeamName:       c.Query("team"),
+		ProductionArea: c.Query("production_area"),
+	}
+	for param, target := range map[string]*time.Time{"start_date": &filter.StartDate, "end_date": &filter.EndDate} {
+		if raw := c.Query(param); raw != "" {
+			date, err := time.Parse("2006-01-02", raw)
+			if err != nil {
+				return filter, fmt.Errorf("invalid %s, expected YYYY-MM-DD", param)
+			}
+			*target = date
+		}
+	}
+	if raw := c.Query("employee_id"); raw != "" {
+		id, err := uuid.Parse(raw)
+		if err != nil {
+			return filter, errors.New("invalid employee ID")
+		}
+		filter.EmployeeID = &id
+	}
+	return filter, nil
+}
+
+// GetRoster handles GET /roster
+func (h *RosterHandler) GetRoster(c *gin.Context) {
+	_, _, companyID, err := middleware.GetUserFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not found in context"})
+		return
+	}
+	filter, err := rosterFilter(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	roster, err := h.service.GenerateRoster(companyID, filter)
+	if err != nil {
+		c.JSON(rosterErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, roster)
+}
+
+// ExportRoster handles GET /roster/export
+func (h *RosterHandler) ExportRoster(c *gin.Context) {
+	_, _, companyID, err := middleware.GetUserFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not found in context"})
+		return
+	}
+	filter, err := rosterFilter(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	file, err := h.service.ExportRoster(companyID, filter)
+	if err != nil {
+		c.JSON(rosterErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	filename := fmt.Sprintf("rol_turnos_%s_%s.xlsx", filter.StartDate.Format("20060102"), filter.EndDate.Format("20060102"))
+	c.Header("Content-Disposition", "attachment; filename="+filename)
+	c.Data(http.StatusOK, "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet", file)
+}
+
+// ListPatterns handles GET /roster/patterns
+func (h *RosterHandler) ListPatterns(c *gin.Context) {
+	_, _, companyID, err := middleware.GetUserFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not found in context"})
+		return
+	}
+
+	patterns, err := h.service.ListPatterns(companyID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"patterns": patterns, "count": len(patterns)})
+}
+
+// CreatePattern handles POST /roster/patterns
+func (h *RosterHandler) CreatePattern(c *gin.Context) {
+	userID, _, companyID, err := middleware.GetUserFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not found in context"})
+		return
+	}
+	var req dtos.RotationPatternRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	pattern, err := h.service.CreatePattern(companyID, req, userID)
+	if err != nil {
+		c.JSON(rosterErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusCreated, pattern)
+}
+
+// UpdatePattern handles PUT /roster/patterns/:id
+func (h *RosterHandler) UpdatePattern(c *gin.Context) {
+	_, _, companyID, err := middleware.GetUserFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not found in context"})
+		return
+	}
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid rotation pattern ID"})
+		return
+	}
+	var req dtos.RotationPatternRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	pattern, err := h.service.UpdatePattern(id, companyID, req)
+	if err != nil {
+		c.JSON(rosterErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, pattern)
+}
+
+// DeletePattern handles DELETE /roster/patterns/:id
+func (h *RosterHandler) DeletePattern(c *gin.Context) {
+	_, _, companyID, err := middleware.GetUserFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not found in context"})
+		return
+	}
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid rotation pattern ID"})
+		return
+	}
+
+	if err := h.service.DeletePattern(id, companyID); err != nil {
+		c.JSON(rosterErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "rotation pattern deleted"})
+}
+
+// ListAssignments handles GET /roster/assignments
+func (h *RosterHandler) ListAssignments(c *gin.Context) {
+	_, _, companyID, err := middleware.GetUserFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not found in context"})
+		return
+	}
+
+	assignments, err := h.service.ListAssignments(companyID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"assignments": assignments, "count": len(assignments)})
+}
+
+// CreateAssignment handles POST /roster/assignments
+func (h *RosterHandler) CreateAssignment(c *gin.Context) {
+	userID, _, companyID, err := middleware.GetUserFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not found in context"})
+		return
+	}
+	var req dtos.RotationAssignmentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	assignment, err := h.service.CreateAssignment(companyID, req, userID)
+	if err != nil {
+		c.JSON(rosterErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusCreated, assignment)
+}
+
+// DeleteAssignment handles DELETE /roster/assignments/:id
+func (h *RosterHandler) DeleteAssignment(c *gin.Context) {
+	_, _, companyID, err := middleware.GetUserFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not found in context"})
+		return
+	}
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid rotation assignment ID"})
+		return
+	}
+
+	if err := h.service.DeleteAssignment(id, companyID); err != nil {
+		c.JSON(rosterErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "rotation assignment deleted"})
+}
+
+// ListStaffingRules handles GET /roster/staffing-rules
+func (h *RosterHandler) ListStaffingRules(c *gin.Context) {
+	_, _, companyID, err := middleware.GetUserFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not found in context"})
+		return
+	}
+
+	rules, err := h.service.ListStaffingRules(companyID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"rules": rules, "count": len(rules)})
+}
+
+// CreateStaffingRule handles POST /roster/staffing-rules
+func (h *RosterHandler) CreateStaffingRule(c *gin.Context) {
+	_, _, companyID, err := middleware.GetUserFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not found in context"})
+		return
+	}
+	var req dtos.StaffingRuleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	rule, err := h.service.CreateStaffingRule(companyID, req)
+	if err != nil {
+		c.JSON(rosterErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusCreated, rule)
+}
+
+// UpdateStaffingRule handles PUT /roster/staffing-rules/:id
+func (h *RosterHandler) UpdateStaffingRule(c *gin.Context) {
+	_, _, companyID, err := middleware.GetUserFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not found in context"})
+		return
+	}
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid staffing rule ID"})
+		return
+	}
+	var req dtos.StaffingRuleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	rule, err := h.service.UpdateStaffingRule(id, companyID, req)
+	if err != nil {
+		c.JSON(rosterErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, rule)
+}
+
+// DeleteStaffingRule handles DELETE /roster/staffing-rules/:id
+func (h *RosterHandler) DeleteStaffingRule(c *gin.Context) {
+	_, _, companyID, err := middleware.GetUserFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not found in context"})
+		return
+	}
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid staffing rule ID"})
+		return
+	}
+
+	if err := h.service.DeleteStaffingRule(id, companyID); err != nil {
+		c.JSON(rosterErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "staffing rule deleted"})
+}
+
+// ListBlackouts handles GET /roster/blackouts
+func (h *RosterHandler) ListBlackouts(c *gin.Context) {
+	_, _, companyID, err := middleware.GetUserFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not found in context"})
+		return
+	}
+
+	blackouts, err := h.service.ListBlackouts(companyID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, blackouts)
+}
+
+// CreateBlackout handles POST /roster/blackouts
+func (h *RosterHandler) CreateBlackout(c *gin.Context) {
+	userID, _, companyID, err := middleware.GetUserFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not found in context"})
+		return
+	}
+	var req dtos.BlackoutPeriodRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	blackout, err := h.service.CreateBlackout(companyID, req, userID)
+	if err != nil {
+		c.JSON(rosterErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusCreated, blackout)
+}
+
+// UpdateBlackout handles PUT /roster/blackouts/:id
+func (h *RosterHandler) UpdateBlackout(c *gin.Context) {
+	_, _, companyID, err := middleware.GetUserFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not found in context"})
+		return
+	}
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid blackout ID"})
+		return
+	}
+	var req dtos.BlackoutPeriodRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	blackout, err := h.service.UpdateBlackout(id, companyID, req)
+	if err != nil {
+		c.JSON(rosterErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, blackout)
+}
+
+// DeleteBlackout handles DELETE /roster/blackouts/:id
+func (h *RosterHandler) DeleteBlackout(c *gin.Context) {
+	_, _, companyID, err := middleware.GetUserFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not found in context"})
+		return
+	}
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid blackout ID"})
+		return
+	}
+
+	if err := h.service.DeleteBlackout(id, companyID); err != nil {
+		c.JSON(rosterErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "blackout deleted"})
+}
+
+// RequestSwap handles POST /roster/swaps
+func (h *RosterHandler) RequestSwap(c *gin.Context) {
+	userID, _, companyID, err := middleware.GetUserFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not found in context"})
+		return
+	}
+	var req dtos.RosterSwapRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	swap, err := h.service.RequestSwap(companyID, userID, req)
+	if err != nil {
+		c.JSON(rosterErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusCreated, swap)
+}
+
+// ListSwaps handles GET /roster/swaps
+func (h *RosterHandler) ListSwaps(c *gin.Context) {
+	_, _, companyID, err := middleware.GetUserFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not found in context"})
+		return
+	}
+
+	swaps, err := h.service.ListSwaps(companyID, c.Query("status"))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"swaps": swaps, "count": len(swaps)})
+}
+
+// ReviewSwap handles POST /roster/swaps/:id/review
+func (h *RosterHandler) ReviewSwap(c *gin.Context) {
+	userID, _, companyID, err := middleware.GetUserFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not found in context"})
+		return
+	}
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid swap request ID"})
+		return
+	}
+	var req dtos.RosterSwapReviewRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	swap, err := h.service.ReviewSwap(id, companyID, userID, req)
+	if err != nil {
+		c.JSON(rosterErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, swap)
+}
+
+// OfferShift handles POST /roster/offers
+func (h *RosterHandler) OfferShift(c *gin.Context) {
+	userID, _, companyID, err := middleware.GetUserFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not found in context"})
+		return
+	}
+	var req dtos.ShiftOfferRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	offer, err := h.service.OfferShift(companyID, userID, req)
+	if err != nil {
+		c.JSON(rosterErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusCreated, offer)
+}
+
+// ListOffers handles GET /roster/offers
+func (h *RosterHandler) ListOffers(c *gin.Context) {
+	userID, _, companyID, err := middleware.GetUserFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not found in context"})
+		return
+	}
+
+	offers, err := h.service.ListOffers(companyID, userID)
+	if err != nil {
+		c.JSON(rosterErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"offers": offers, "count": len(offers)})
+}
+
+// AcceptOffer handles POST /roster/offers/:id/accept
+func (h *RosterHandler) AcceptOffer(c *gin.Context) {
+	h.offerAction(c, h.service.AcceptOffer)
+}
+
+// CancelOffer handles POST /roster/offers/:id/cancel
+func (h *RosterHandler) CancelOffer(c *gin.Context) {
+	h.offerAction(c, h.service.CancelOffer)
+}
+
+// offerAction runs an accept or cancel on the offer of the path
+func (h *RosterHandler) offerAction(c *gin.Context, action func(id, companyID, userID uuid.UUID) (*models.RosterSwap, error)) {
+	userID, _, companyID, err := middleware.GetUserFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not found in context"})
+		return
+	}
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid shift offer ID"})
+		return
+	}
+
+	offer, err := action(id, companyID, userID)
+	if err != nil {
+		c.JSON(rosterErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, offer)
+}
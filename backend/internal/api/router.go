@@ -108,6 +108,107 @@ func (r *Router) Setup(routerGroup *gin.RouterGroup) { // Changed return type to
             payrollPeriodHandler := NewPayrollPeriodHandler(payrollPeriodService)
             payrollPeriodHandler.RegisterRoutes(protected)
 
+            // Payroll Calendar Routes (annual period generator + holiday catalog)
+            payrollCalendarService := services.NewPayrollCalendarService(r.db, r.appConfig)
+            payrollCalendarHandler := NewPayrollCalendarHandler(payrollCalendarService)
+            payrollCalendarHandler.RegisterRoutes(protected, middleware.NewAuthMiddleware(r.authService))
+
+            // Minimum Wage Routes (zones, professional minimums, annual bulk raise)
+            minimumWageService := services.NewMinimumWageService(r.db, r.appConfig)
+            minimumWageHandler := NewMinimumWageHandler(minimumWageService)
+            minimumWageHandler.RegisterRoutes(protected, middleware.NewAuthMiddleware(r.authService))
+
+            // Salary Campaign Routes (mass increases, approval, retroactive pay)
+            salaryCampaignService := services.NewSalaryCampaignService(r.db, r.appConfig)
+            salaryCampaignHandler := NewSalaryCampaignHandler(salaryCampaignService)
+            salaryCampaignHandler.RegisterRoutes(protected, middleware.NewAuthMiddleware(r.authService))
+
+            // Organization Routes (departments, positions, pay bands, compa-ratio)
+            organizationService := services.NewOrganizationService(r.db)
+            organizationHandler := NewOrganizationHandler(organizationService)
+            organizationHandler.RegisterRoutes(protected, middleware.NewAuthMiddleware(r.authService))
+
+            // Org Structure Routes (reporting lines, org chart, approver resolution)
+            orgStructureService := services.NewOrgStructureService(r.db)
+            orgStructureHandler := NewOrgStructureHandler(orgStructureService)
+            orgStructureHandler.RegisterRoutes(protected, middleware.NewAuthMiddleware(r.authService))
+
+            // Attendance Routes (punches vs. shifts, per-day audit trail for prenómina)
+            attendanceService := services.NewAttendanceEvaluationService(r.db, r.appConfig)
+            attendanceHandler := NewAttendanceHandler(attendanceService)
+            attendanceHandler.RegisterRoutes(protected, middleware.NewAuthMiddleware(r.authService))
+
+            // Attendance Terminal Routes (NFC swipes, offline sync, card enrollment)
+            terminalService := services.NewAttendanceTerminalService(r.db, r.appConfig)
+            terminalHandler := NewAttendanceTerminalHandler(terminalService)
+            terminalHandler.RegisterRoutes(protected, middleware.NewAuthMiddleware(r.authService))
+
+            // Time Policy Routes (rounding, overtime split, violations dashboard)
+            timePolicyService := services.NewTimePolicyService(r.db)
+            timePolicyHandler := NewTimePolicyHandler(timePolicyService)
+            timePolicyHandler.RegisterRoutes(protected, middleware.NewAuthMiddleware(r.authService))
+
+            // Overtime Pre-authorization Routes (approval chain, period reconciliation)
+            overtimeService := services.NewOvertimeService(r.db)
+            overtimeHandler := NewOvertimeHandler(overtimeService)
+            overtimeHandler.RegisterRoutes(protected, middleware.NewAuthMiddleware(r.authService))
+
+            // Roster Routes (rotating shifts, staffing coverage, swaps)
+            rosterService := services.NewRosterService(r.db)
+            rosterHandler := NewRosterHandler(rosterService)
+            rosterHandler.RegisterRoutes(protected, middleware.NewAuthMiddleware(r.authService))
+
+            // Work Site Routes (geofenced web/app clock-in, trusted devices, punch review)
+            workSiteService := services.NewWorkSiteService(r.db)
+            workSiteHandler := NewWorkSiteHandler(workSiteService)
+            workSiteHandler.RegisterRoutes(protected, middleware.NewAuthMiddleware(r.authService))
+
+            // Timesheet Period Routes (generation per payroll period, reminders, utilization, prenómina export)
+            timesheetPeriodService := services.NewTimesheetPeriodService(r.db)
+            timesheetPeriodHandler := NewTimesheetPeriodHandler(timesheetPeriodService)
+            timesheetPeriodHandler.RegisterRoutes(protected, middleware.NewAuthMiddleware(r.authService))
+
+            // Project Cost Routes (budget vs. actual, budget alerts, payroll cost allocation and póliza)
+            projectCostService := services.NewProjectCostService(r.db)
+            projectCostHandler := NewProjectCostHandler(projectCostService)
+            projectCostHandler.RegisterRoutes(protected, middleware.NewAuthMiddleware(r.authService))
+
+            // Vacation Ledger Routes (anniversary grants, consumption, carry-over, expiry, adjustments)
+            vacationLedgerService := services.NewVacationLedgerService(r.db, r.appConfig)
+            vacationLedgerHandler := NewVacationLedgerHandler(vacationLedgerService)
+            vacationLedgerHandler.RegisterRoutes(protected, middleware.NewAuthMiddleware(r.authService))
+
+            // Time Bank Routes (banked overtime, time-for-time consumption, expiry, adjustments)
+            timeBankService := services.NewTimeBankService(r.db)
+            timeBankHandler := NewTimeBankHandler(timeBankService)
+            timeBankHandler.RegisterRoutes(protected, middleware.NewAuthMiddleware(r.authService))
+
+            // Leave Policy Routes (maternity, paternity, bereavement, union leave catalog and balances)
+            leavePolicyService := services.NewLeavePolicyService(r.db)
+            leavePolicyHandler := NewLeavePolicyHandler(leavePolicyService)
+            leavePolicyHandler.RegisterRoutes(protected, middleware.NewAuthMiddleware(r.authService))
+
+            // Approval Workflow Routes (configurable approval steps per company and incidence type, approver inbox)
+            approvalWorkflowService := services.NewApprovalWorkflowService(r.db)
+            approvalWorkflowHandler := NewApprovalWorkflowHandler(approvalWorkflowService)
+            approvalWorkflowHandler.RegisterRoutes(protected, middleware.NewAuthMiddleware(r.authService))
+
+            // Approval Delegation Routes (out-of-office substitutes for every approval queue)
+            approvalDelegationService := services.NewApprovalDelegationService(r.db)
+            approvalDelegationHandler := NewApprovalDelegationHandler(approvalDelegationService)
+            approvalDelegationHandler.RegisterRoutes(protected, middleware.NewAuthMiddleware(r.authService))
+
+            // Sick Leave Routes (IMSS incapacidades, company/IMSS day split, open list and summary)
+            sickLeaveService := services.NewSickLeaveService(r.db)
+            sickLeaveHandler := NewSickLeaveHandler(sickLeaveService)
+            sickLeaveHandler.RegisterRoutes(protected, middleware.NewAuthMiddleware(r.authService))
+
+            // Scheduler Routes (periodic job status, run history, manual runs)
+            schedulerService := services.NewSchedulerService(r.db, r.appConfig.SchedulerInstanceID)
+            schedulerService.Register(services.DefaultScheduledJobs(r.db, r.appConfig)...)
+            schedulerHandler := NewSchedulerHandler(schedulerService)
+            schedulerHandler.RegisterRoutes(protected, middleware.NewAuthMiddleware(r.authService))
+
             // Catalog Routes
             catalogService := services.NewCatalogService(r.db)
             catalogHandler := NewCatalogHandler(catalogService)
@@ -119,7 +220,7 @@ func (r *Router) Setup(routerGroup *gin.RouterGroup) { // Changed return type to
             reportHandler.RegisterRoutes(protected)
 
             // Employee Routes
-            employeeService := services.NewEmployeeService(r.db)
+            employeeService := services.NewEmployeeService(r.db, r.appConfig)
             employeeHandler := NewEmployeeHandler(employeeService)
             employeeHandler.RegisterRoutes(protected)
 
@@ -129,7 +230,7 @@ func (r *Router) Setup(routerGroup *gin.RouterGroup) { // Changed return type to
             payrollHandler.RegisterRoutes(protected)
 
             // Payroll Export Routes (Dual Excel export for payroll processing)
-            excelExportService := services.NewExcelExportService(r.db)
+            excelExportService := services.NewExcelExportService(r.db, r.appConfig)
             payrollExportHandler := NewPayrollExportHandler(excelExportService)
             payrollExportHandler.RegisterRoutes(protected)
 
@@ -149,7 +250,7 @@ func (r *Router) Setup(routerGroup *gin.RouterGroup) { // Changed return type to
             categoryHandler.RegisterRoutes(protected)
 
             // Incidence Routes (for HR tracking absences, vacations, etc.)
-            incidenceService := services.NewIncidenceService(r.db)
+            incidenceService := services.NewIncidenceService(r.db, r.appConfig)
             incidenceHandler := NewIncidenceHandler(incidenceService)
             incidenceHandler.RegisterRoutes(protected)
 
@@ -164,7 +265,7 @@ func (r *Router) Setup(routerGroup *gin.RouterGroup) { // Changed return type to
             notificationHandler.RegisterRoutes(protected)
 
             // Absence Request Approval Workflow Routes
-            absenceRequestService := services.NewAbsenceRequestService(r.db)
+            absenceRequestService := services.NewAbsenceRequestService(r.db, r.appConfig)
             absenceRequestHandler := NewAbsenceRequestHandler(absenceRequestService)
             absenceRequestHandler.RegisterRoutes(protected)
 
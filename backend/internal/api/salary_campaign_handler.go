@@ -0,0 +1,236 @@
+/*
+Package api - IRIS Payroll System HTTP API Handlers
+
+==============================================================================
+FILE: internal/api/salary_campaign_handler.go
+==============================================================================
+
+DESCRIPTION:
+    Handles salary review campaigns: mass salary increases with approval,
+    scheduling and retroactive pay.
+
+USER PERSPECTIVE:
+    - HR creates a campaign, reviews the per-employee preview and submits it
+    - A payroll manager approves or rejects it
+    - Approved campaigns apply on their effective date
+
+DEVELOPER GUIDELINES:
+    ✅  OK to modify: Add export of the preview
+    ⚠️  CAUTION: All endpoints are restricted to payroll/HR roles
+    📝  campaignErrorStatus maps service errors by their wording; phrase new
+        errors the same way (not found, cannot be, must be, required)
+
+ENDPOINTS:
+    GET    /salary-campaigns              - List campaigns (?status=)
+    POST   /salary-campaigns              - Create campaign (draft + preview)
+    GET    /salary-campaigns/:id          - Campaign with items
+    PUT    /salary-campaigns/:id          - Update draft/rejected campaign
+    POST   /salary-campaigns/:id/submit   - Send for approval
+    POST   /salary-campaigns/:id/approve  - Approve (applies if date reached)
+    POST   /salary-campaigns/:id/reject   - Reject with reason
+    POST   /salary-campaigns/:id/cancel   - Cancel a not-applied campaign
+
+==============================================================================
+*/
+package api
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+
+	"backend/internal/dtos"
+	"backend/internal/middleware"
+	"backend/internal/services"
+)
+
+// SalaryCampaignHandler handles salary campaign endpoints
+type SalaryCampaignHandler struct {
+	service *services.SalaryCampaignService
+}
+
+// NewSalaryCampaignHandler creates a new salary campaign handler
+func NewSalaryCampaignHandler(service *services.SalaryCampaignService) *SalaryCampaignHandler {
+	return &SalaryCampaignHandler{service: service}
+}
+
+// RegisterRoutes registers salary campaign routes
+func (h *SalaryCampaignHandler) RegisterRoutes(router *gin.RouterGroup, authMiddleware *middleware.AuthMiddleware) {
+	campaigns := router.Group("/salary-campaigns")
+	campaigns.Use(authMiddleware.RequireRole("admin", "hr", "hr_and_pr", "payroll_staff"))
+	{
+		campaigns.GET("", h.ListCampaigns)
+		campaigns.POST("", h.CreateCampaign)
+		campaigns.GET("/:id", h.GetCampaign)
+		campaigns.PUT("/:id", h.UpdateCampaign)
+		campaigns.POST("/:id/submit", h.SubmitCampaign)
+		campaigns.POST("/:id/approve", h.ApproveCampaign)
+		campaigns.POST("/:id/reject", h.RejectCampaign)
+		campaigns.POST("/:id/cancel", h.CancelCampaign)
+	}
+}
+
+// ListCampaigns handles GET /salary-campaigns
+func (h *SalaryCampaignHandler) ListCampaigns(c *gin.Context) {
+	_, _, companyID, err := middleware.GetUserFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not found in context"})
+		return
+	}
+
+	campaigns, err := h.service.ListCampaigns(companyID, c.Query("status"))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"campaigns": campaigns, "count": len(campaigns)})
+}
+
+// CreateCampaign handles POST /salary-campaigns
+func (h *SalaryCampaignHandler) CreateCampaign(c *gin.Context) {
+	userID, _, companyID, err := middleware.GetUserFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not found in context"})
+		return
+	}
+
+	var req dtos.SalaryCampaignRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	campaign, err := h.service.CreateCampaign(companyID, userID, req)
+	if err != nil {
+		c.JSON(campaignErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusCreated, campaign)
+}
+
+// GetCampaign handles GET /salary-campaigns/:id
+func (h *SalaryCampaignHandler) GetCampaign(c *gin.Context) {
+	_, _, companyID, err := middleware.GetUserFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not found in context"})
+		return
+	}
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid campaign ID"})
+		return
+	}
+
+	campaign, err := h.service.GetCampaign(id, companyID)
+	if err != nil {
+		c.JSON(campaignErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, campaign)
+}
+
+// UpdateCampaign handles PUT /salary-campaigns/:id
+func (h *SalaryCampaignHandler) UpdateCampaign(c *gin.Context) {
+	_, _, companyID, err := middleware.GetUserFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not found in context"})
+		return
+	}
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid campaign ID"})
+		return
+	}
+
+	var req dtos.SalaryCampaignRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	campaign, err := h.service.UpdateCampaign(id, companyID, req)
+	if err != nil {
+		c.JSON(campaignErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, campaign)
+}
+
+// SubmitCampaign handles POST /salary-campaigns/:id/submit
+func (h *SalaryCampaignHandler) SubmitCampaign(c *gin.Context) {
+	h.transition(c, h.service.SubmitCampaign)
+}
+
+// ApproveCampaign handles POST /salary-campaigns/:id/approve
+func (h *SalaryCampaignHandler) ApproveCampaign(c *gin.Context) {
+	h.transition(c, h.service.ApproveCampaign)
+}
+
+// RejectCampaign handles POST /salary-campaigns/:id/reject
+func (h *SalaryCampaignHandler) RejectCampaign(c *gin.Context) {
+	var req dtos.SalaryCampaignRejectRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	h.transition(c, func(id, companyID, userID uuid.UUID) (*dtos.SalaryCampaignResponse, error) {
+		return h.service.RejectCampaign(id, companyID, userID, req.Reason)
+	})
+}
+
+// CancelCampaign handles POST /salary-campaigns/:id/cancel
+func (h *SalaryCampaignHandler) CancelCampaign(c *gin.Context) {
+	_, _, companyID, err := middleware.GetUserFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not found in context"})
+		return
+	}
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid campaign ID"})
+		return
+	}
+
+	if err := h.service.CancelCampaign(id, companyID); err != nil {
+		c.JSON(campaignErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Campaign cancelled"})
+}
+
+// transition runs a workflow action that takes (campaign, company, user)
+func (h *SalaryCampaignHandler) transition(c *gin.Context, action func(id, companyID, userID uuid.UUID) (*dtos.SalaryCampaignResponse, error)) {
+	userID, _, companyID, err := middleware.GetUserFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not found in context"})
+		return
+	}
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid campaign ID"})
+		return
+	}
+
+	campaign, err := action(id, companyID, userID)
+	if err != nil {
+		c.JSON(campaignErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, campaign)
+}
+
+// campaignErrorStatus maps service errors to HTTP status codes
+func campaignErrorStatus(err error) int {
+	msg := err.Error()
+	switch {
+	case strings.Contains(msg, "not found"):
+		return http.StatusNotFound
+	case strings.Contains(msg, "cannot be"), strings.Contains(msg, "must be"), strings.Contains(msg, "invalid"),
+		strings.Contains(msg, "required"), strings.Contains(msg, "no employees"):
+		return http.StatusBadRequest
+	default:
+		return http.StatusInternalServerError
+	}
+}
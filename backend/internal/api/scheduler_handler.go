@@ -0,0 +1,102 @@
+/*
+Package api - IRIS Payroll System HTTP API Handlers
+
+==============================================================================
+FILE: internal/api/scheduler_handler.go
+==============================================================================
+
+DESCRIPTION:
+    Endpoints of the in-process job scheduler: the registered jobs with
+    their last run and next due time, the run history with durations and
+    errors, and running a job on demand.
+
+USER PERSPECTIVE:
+    - Admins confirm escalations and reminders actually ran last night
+    - After fixing a failure an admin re-runs the job without waiting
+
+DEVELOPER GUIDELINES:
+    ✅  OK to modify: Add history filters
+    ⚠️  CAUTION: Jobs are system-wide; keep these endpoints admin-only
+    📝  Manual runs execute on the replica that receives the request
+
+ENDPOINTS:
+    GET  /scheduler/jobs            - Jobs, last run, next run and current leader
+    GET  /scheduler/runs            - Run history (?job=, ?limit=, newest first)
+    POST /scheduler/jobs/:name/run  - Run a job now
+
+==============================================================================
+*/
+package api
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"backend/internal/middleware"
+	"backend/internal/services"
+)
+
+// SchedulerHandler handles job scheduler endpoints
+type SchedulerHandler struct {
+	service *services.SchedulerService
+}
+
+// NewSchedulerHandler creates a new scheduler handler
+func NewSchedulerHandler(service *services.SchedulerService) *SchedulerHandler {
+	return &SchedulerHandler{service: service}
+}
+
+// RegisterRoutes registers scheduler routes
+func (h *SchedulerHandler) RegisterRoutes(router *gin.RouterGroup, authMiddleware *middleware.AuthMiddleware) {
+	scheduler := router.Group("/scheduler")
+	scheduler.Use(authMiddleware.RequireRole("admin"))
+	{
+		scheduler.GET("/jobs", h.ListJobs)
+		scheduler.GET("/runs", h.ListRuns)
+		scheduler.POST("/jobs/:name/run", h.RunJob)
+	}
+}
+
+// ListJobs handles GET /scheduler/jobs
+func (h *SchedulerHandler) ListJobs(c *gin.Context) {
+	status, err := h.service.Status()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, status)
+}
+
+// ListRuns handles GET /scheduler/runs
+func (h *SchedulerHandler) ListRuns(c *gin.Context) {
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "100"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid limit"})
+		return
+	}
+
+	runs, err := h.service.ListRuns(c.Query("job"), limit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, runs)
+}
+
+// RunJob handles POST /scheduler/jobs/:name/run
+func (h *SchedulerHandler) RunJob(c *gin.Context) {
+	userID, _, _, err := middleware.GetUserFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not found in context"})
+		return
+	}
+
+	run, err := h.service.RunJob(c.Param("name"), userID)
+	if err != nil {
+		c.JSON(organizationErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, run)
+}
@@ -0,0 +1,347 @@
+/*
+Package api - IRIS Payroll System HTTP API Handlers
+
+==============================================================================
+FILE: internal/api/sick_leave_handler.go
+==============================================================================
+
+DESCRIPTION:
+    Endpoints for IMSS incapacidades: HR registers the certificates with
+    their scan, records the alta and follows the open incapacidades and the
+    days accumulated per employee. Payroll can read everything; employees
+    see their own certificates.
+
+USER PERSPECTIVE:
+    - HR captures the certificate the employee brings from the IMSS
+    - Payroll checks the days the company pays before closing the period
+    - Employees see the incapacidades on file for them
+
+DEVELOPER GUIDELINES:
+    ✅  OK to modify: Add filters to the list
+    ⚠️  CAUTION: The scan holds medical data; it is served to HR and
+        payroll only
+    📝  Scan uploads answer 413 when too large and 415 for other file types
+
+ENDPOINTS:
+    GET  /sick-leave-certificates/my            - My incapacidades
+    GET  /sick-leave-certificates               - Certificates (?employee_id=&status=&branch=)
+    GET  /sick-leave-certificates/open          - Open incapacidades with the days of their chain
+    GET  /sick-leave-certificates/summary       - Days per employee (?from=&to=)
+    GET  /sick-leave-certificates/:id           - One certificate
+    GET  /sick-leave-certificates/:id/document  - Download the scan
+    POST /sick-leave-certificates               - Register a certificate (HR)
+    PUT  /sick-leave-certificates/:id           - Correct an open certificate (HR)
+    POST /sick-leave-certificates/:id/close     - Record the alta (HR)
+    POST /sick-leave-certificates/:id/cancel    - Cancel a certificate captured by mistake (HR)
+    POST /sick-leave-certificates/:id/document  - Upload the scan, multipart "file" (HR)
+
+==============================================================================
+*/
+package api
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+
+	"backend/internal/dtos"
+	"backend/internal/middleware"
+	"backend/internal/services"
+)
+
+// SickLeaveHandler handles IMSS incapacidad endpoints
+type SickLeaveHandler struct {
+	service *services.SickLeaveService
+}
+
+// NewSickLeaveHandler creates a new sick leave handler
+func NewSickLeaveHandler(service *services.SickLeaveService) *SickLeaveHandler {
+	return &SickLeaveHandler{service: service}
+}
+
+// RegisterRoutes registers sick leave routes
+func (h *SickLeaveHandler) RegisterRoutes(router *gin.RouterGroup, authMiddleware *middleware.AuthMiddleware) {
+	certificates := router.Group("/sick-leave-certificates")
+	certificates.GET("/my", h.ListMine)
+
+	readers := certificates.Group("")
+	readers.Use(authMiddleware.RequireRole("admin", "hr", "hr_and_pr", "hr_blue_gray", "hr_white", "payroll_staff"))
+	readers.GET("", h.List)
+	readers.GET("/open", h.ListOpen)
+	readers.GET("/summary", h.Summary)
+	readers.GET("/:id", h.Get)
+	readers.GET("/:id/document", h.DownloadDocument)
+
+	hr := certificates.Group("")
+	hr.Use(authMiddleware.RequireRole("admin", "hr", "hr_and_pr", "hr_blue_gray", "hr_white"))
+	hr.POST("", h.Create)
+	hr.PUT("/:id", h.Update)
+	hr.POST("/:id/close", h.Close)
+	hr.POST("/:id/cancel", h.Cancel)
+	hr.POST("/:id/document", h.UploadDocument)
+}
+
+// sickLeaveErrorStatus maps incapacidad errors to HTTP statuses
+func sickLeaveErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, services.ErrFileTooLarge):
+		return http.StatusRequestEntityTooLarge
+	case errors.Is(err, services.ErrInvalidFileType), errors.Is(err, services.ErrInvalidExtension):
+		return http.StatusUnsupportedMediaType
+	case errors.Is(err, services.ErrNoFile):
+		return http.StatusBadRequest
+	}
+	return organizationErrorStatus(err)
+}
+
+// ListMine handles GET /sick-leave-certificates/my
+func (h *SickLeaveHandler) ListMine(c *gin.Context) {
+	userID, _, _, err := middleware.GetUserFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not found in context"})
+		return
+	}
+
+	certificates, err := h.service.ListForUser(userID)
+	if err != nil {
+		c.JSON(sickLeaveErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, certificates)
+}
+
+// List handles GET /sick-leave-certificates
+func (h *SickLeaveHandler) List(c *gin.Context) {
+	_, _, companyID, err := middleware.GetUserFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not found in context"})
+		return
+	}
+	filter := dtos.SickLeaveFilter{Status: c.Query("status"), Branch: c.Query("branch")}
+	if raw := c.Query("employee_id"); raw != "" {
+		employeeID, err := uuid.Parse(raw)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid employee ID"})
+			return
+		}
+		filter.EmployeeID = &employeeID
+	}
+
+	certificates, err := h.service.List(companyID, filter)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, certificates)
+}
+
+// ListOpen handles GET /sick-leave-certificates/open
+func (h *SickLeaveHandler) ListOpen(c *gin.Context) {
+	_, _, companyID, err := middleware.GetUserFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not found in context"})
+		return
+	}
+
+	open, err := h.service.OpenCertificates(companyID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, open)
+}
+
+// Summary handles GET /sick-leave-certificates/summary
+func (h *SickLeaveHandler) Summary(c *gin.Context) {
+	_, _, companyID, err := middleware.GetUserFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not found in context"})
+		return
+	}
+	from, err := time.ParseInLocation("2006-01-02", c.Query("from"), time.Local)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "from must be a date (YYYY-MM-DD)"})
+		return
+	}
+	to, err := time.ParseInLocation("2006-01-02", c.Query("to"), time.Local)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "to must be a date (YYYY-MM-DD)"})
+		return
+	}
+
+	summary, err := h.service.Summary(companyID, from, to)
+	if err != nil {
+		c.JSON(sickLeaveErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, summary)
+}
+
+// Get handles GET /sick-leave-certificates/:id
+func (h *SickLeaveHandler) Get(c *gin.Context) {
+	_, _, companyID, err := middleware.GetUserFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not found in context"})
+		return
+	}
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid incapacidad ID"})
+		return
+	}
+
+	cert, err := h.service.Get(companyID, id)
+	if err != nil {
+		c.JSON(sickLeaveErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, cert)
+}
+
+// Create handles POST /sick-leave-certificates
+func (h *SickLeaveHandler) Create(c *gin.Context) {
+	userID, _, companyID, err := middleware.GetUserFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not found in context"})
+		return
+	}
+	var req dtos.SickLeaveCertificateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	cert, err := h.service.Create(companyID, userID, req)
+	if err != nil {
+		c.JSON(sickLeaveErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusCreated, cert)
+}
+
+// Update handles PUT /sick-leave-certificates/:id
+func (h *SickLeaveHandler) Update(c *gin.Context) {
+	_, _, companyID, err := middleware.GetUserFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not found in context"})
+		return
+	}
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid incapacidad ID"})
+		return
+	}
+	var req dtos.SickLeaveCertificateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	cert, err := h.service.Update(companyID, id, req)
+	if err != nil {
+		c.JSON(sickLeaveErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, cert)
+}
+
+// Close handles POST /sick-leave-certificates/:id/close
+func (h *SickLeaveHandler) Close(c *gin.Context) {
+	_, _, companyID, err := middleware.GetUserFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not found in context"})
+		return
+	}
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid incapacidad ID"})
+		return
+	}
+	var req dtos.SickLeaveCloseRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	cert, err := h.service.Close(companyID, id, req)
+	if err != nil {
+		c.JSON(sickLeaveErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, cert)
+}
+
+// Cancel handles POST /sick-leave-certificates/:id/cancel
+func (h *SickLeaveHandler) Cancel(c *gin.Context) {
+	_, _, companyID, err := middleware.GetUserFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not found in context"})
+		return
+	}
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid incapacidad ID"})
+		return
+	}
+
+	cert, err := h.service.Cancel(companyID, id)
+	if err != nil {
+		c.JSON(sickLeaveErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, cert)
+}
+
+// UploadDocument handles POST /sick-leave-certificates/:id/document
+func (h *SickLeaveHandler) UploadDocument(c *gin.Context) {
+	_, _, companyID, err := middleware.GetUserFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not found in context"})
+		return
+	}
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid incapacidad ID"})
+		return
+	}
+	file, err := c.FormFile("file")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "please provide the certificate in the 'file' form field"})
+		return
+	}
+
+	cert, err := h.service.UploadDocument(companyID, id, file)
+	if err != nil {
+		c.JSON(sickLeaveErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, cert)
+}
+
+// DownloadDocument handles GET /sick-leave-certificates/:id/document
+func (h *SickLeaveHandler) DownloadDocument(c *gin.Context) {
+	_, _, companyID, err := middleware.GetUserFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not found in context"})
+		return
+	}
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid incapacidad ID"})
+		return
+	}
+
+	cert, err := h.service.Get(companyID, id)
+	if err != nil {
+		c.JSON(sickLeaveErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	if cert.DocumentPath == "" {
+		c.JSON(http.StatusNotFound, gin.H{"error": "certificate document not found"})
+		return
+	}
+	c.FileAttachment(cert.DocumentPath, cert.DocumentName)
+}
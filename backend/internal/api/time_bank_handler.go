@@ -0,0 +1,150 @@
+/*
+Package api - IRIS Payroll System HTTP API Handlers
+
+==============================================================================
+FILE: internal/api/time_bank_handler.go
+==============================================================================
+
+DESCRIPTION:
+    Endpoints for the time-for-time bank: the statement of an employee
+    (every deposit of banked overtime with what is left of it, and every
+    consumption, reversal, expiry and adjustment with its running balance),
+    manual adjustments with a reason, and the company-wide expiry run.
+
+USER PERSPECTIVE:
+    - Employees check the hours they banked and when they expire before
+      filing a TIME_FOR_TIME request
+    - HR explains and corrects a balance without editing it by hand
+    - The expiry run can be repeated safely (it is idempotent)
+
+DEVELOPER GUIDELINES:
+    ✅  OK to modify: Add statement filters
+    ⚠️  CAUTION: Adjustments require a reason; a negative one cannot take
+        more hours than the employee can use today
+    📝  The time_bank_expiry job expires deposits daily; POST /expiry/run is
+        the manual trigger
+
+ENDPOINTS:
+    GET  /time-bank/me                          - Own statement
+    GET  /time-bank/employees/:id/statement     - Statement of an employee
+    POST /time-bank/employees/:id/adjustments   - Manual adjustment (hours, reason)
+    POST /time-bank/expiry/run                  - Expire deposits past their expiry date
+
+==============================================================================
+*/
+package api
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+
+	"backend/internal/dtos"
+	"backend/internal/middleware"
+	"backend/internal/services"
+)
+
+// TimeBankHandler handles time-for-time bank endpoints
+type TimeBankHandler struct {
+	service *services.TimeBankService
+}
+
+// NewTimeBankHandler creates a new time bank handler
+func NewTimeBankHandler(service *services.TimeBankService) *TimeBankHandler {
+	return &TimeBankHandler{service: service}
+}
+
+// RegisterRoutes registers time bank routes
+func (h *TimeBankHandler) RegisterRoutes(router *gin.RouterGroup, authMiddleware *middleware.AuthMiddleware) {
+	bank := router.Group("/time-bank")
+	bank.GET("/me", h.MyStatement)
+
+	hr := bank.Group("")
+	hr.Use(authMiddleware.RequireRole("admin", "hr", "hr_and_pr", "hr_blue_gray", "hr_white", "payroll_staff"))
+	{
+		hr.GET("/employees/:id/statement", h.Statement)
+		hr.POST("/employees/:id/adjustments", h.Adjust)
+		hr.POST("/expiry/run", h.RunExpiry)
+	}
+}
+
+// MyStatement handles GET /time-bank/me
+func (h *TimeBankHandler) MyStatement(c *gin.Context) {
+	userID, _, companyID, err := middleware.GetUserFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not found in context"})
+		return
+	}
+
+	statement, err := h.service.StatementForUser(companyID, userID, time.Now())
+	if err != nil {
+		c.JSON(organizationErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, statement)
+}
+
+// Statement handles GET /time-bank/employees/:id/statement
+func (h *TimeBankHandler) Statement(c *gin.Context) {
+	_, _, companyID, err := middleware.GetUserFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not found in context"})
+		return
+	}
+	employeeID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid employee ID"})
+		return
+	}
+
+	statement, err := h.service.Statement(companyID, employeeID, time.Now())
+	if err != nil {
+		c.JSON(organizationErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, statement)
+}
+
+// Adjust handles POST /time-bank/employees/:id/adjustments
+func (h *TimeBankHandler) Adjust(c *gin.Context) {
+	userID, _, companyID, err := middleware.GetUserFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not found in context"})
+		return
+	}
+	employeeID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid employee ID"})
+		return
+	}
+	var req dtos.TimeBankAdjustmentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	statement, err := h.service.Adjust(companyID, employeeID, userID, req, time.Now())
+	if err != nil {
+		c.JSON(organizationErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusCreated, statement)
+}
+
+// RunExpiry handles POST /time-bank/expiry/run
+func (h *TimeBankHandler) RunExpiry(c *gin.Context) {
+	_, _, companyID, err := middleware.GetUserFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not found in context"})
+		return
+	}
+
+	result, err := h.service.ExpireBalances(companyID, time.Now())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, result)
+}
@@ -0,0 +1,279 @@
+/*
+Package api - IRIS Payroll System HTTP API Handlers
+
+==============================================================================
+FILE: internal/api/time_policy_handler.go
+==============================================================================
+
+DESCRIPTION:
+    Endpoints for the time policy catalog (rounding, overtime thresholds,
+    break rules by collar type or role), the policy in effect for an
+    employee and the dashboard of policy violations.
+
+USER PERSPECTIVE:
+    - HR creates a policy per collar type and/or role, or a company default
+    - HR checks which policy applies to an employee
+    - HR reviews missing breaks and overtime limits breached, and resolves
+      them with a note
+
+DEVELOPER GUIDELINES:
+    ✅  OK to modify: Add dashboard filters
+    ⚠️  CAUTION: Changing a policy does not reclassify closed clock records
+    📝  The violations dashboard covers the last 30 days unless dates are given
+
+ENDPOINTS:
+    GET    /time-policies                          - List policies
+    POST   /time-policies                          - Create a policy
+    PUT    /time-policies/:id                      - Update a policy
+    DELETE /time-policies/:id                      - Delete a policy
+    GET    /time-policies/employees/:id/effective  - Policy in effect for an employee
+    GET    /time-policies/violations               - List violations (?employee_id=&type=&resolved=&start_date=&end_date=)
+    GET    /time-policies/violations/dashboard     - Violations summary (?start_date=&end_date=)
+    POST   /time-policies/violations/:id/resolve   - Resolve a violation
+
+==============================================================================
+*/
+package api
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+
+	"backend/internal/dtos"
+	"backend/internal/middleware"
+	"backend/internal/services"
+)
+
+// TimePolicyHandler handles time policy endpoints
+type TimePolicyHandler struct {
+	service *services.TimePolicyService
+}
+
+// NewTimePolicyHandler creates a new time policy handler
+func NewTimePolicyHandler(service *services.TimePolicyService) *TimePolicyHandler {
+	return &TimePolicyHandler{service: service}
+}
+
+// RegisterRoutes registers time policy routes
+func (h *TimePolicyHandler) RegisterRoutes(router *gin.RouterGroup, authMiddleware *middleware.AuthMiddleware) {
+	read := router.Group("/time-policies")
+	read.Use(authMiddleware.RequireRole("admin", "hr", "hr_and_pr", "payroll_staff"))
+	{
+		read.GET("", h.ListPolicies)
+		read.GET("/employees/:id/effective", h.EffectivePolicy)
+		read.GET("/violations", h.ListViolations)
+		read.GET("/violations/dashboard", h.Dashboard)
+	}
+
+	manage := router.Group("/time-policies")
+	manage.Use(authMiddleware.RequireRole("admin", "hr", "hr_and_pr"))
+	{
+		manage.POST("", h.CreatePolicy)
+		manage.PUT("/:id", h.UpdatePolicy)
+		manage.DELETE("/:id", h.DeletePolicy)
+		manage.POST("/violations/:id/resolve", h.ResolveViolation)
+	}
+}
+
+// ListPolicies handles GET /time-policies
+func (h *TimePolicyHandler) ListPolicies(c *gin.Context) {
+	_, _, companyID, err := middleware.GetUserFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not found in context"})
+		return
+	}
+
+	policies, err := h.service.ListPolicies(companyID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"policies": policies, "count": len(policies)})
+}
+
+// CreatePolicy handles POST /time-policies
+func (h *TimePolicyHandler) CreatePolicy(c *gin.Context) {
+	userID, _, companyID, err := middleware.GetUserFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not found in context"})
+		return
+	}
+	var req dtos.TimePolicyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	policy, err := h.service.CreatePolicy(companyID, req, userID)
+	if err != nil {
+		c.JSON(organizationErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusCreated, policy)
+}
+
+// UpdatePolicy handles PUT /time-policies/:id
+func (h *TimePolicyHandler) UpdatePolicy(c *gin.Context) {
+	_, _, companyID, err := middleware.GetUserFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not found in context"})
+		return
+	}
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid time policy ID"})
+		return
+	}
+	var req dtos.TimePolicyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	policy, err := h.service.UpdatePolicy(id, companyID, req)
+	if err != nil {
+		c.JSON(organizationErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, policy)
+}
+
+// DeletePolicy handles DELETE /time-policies/:id
+func (h *TimePolicyHandler) DeletePolicy(c *gin.Context) {
+	_, _, companyID, err := middleware.GetUserFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not found in context"})
+		return
+	}
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid time policy ID"})
+		return
+	}
+
+	if err := h.service.DeletePolicy(id, companyID); err != nil {
+		c.JSON(organizationErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "time policy deleted"})
+}
+
+// EffectivePolicy handles GET /time-policies/employees/:id/effective
+func (h *TimePolicyHandler) EffectivePolicy(c *gin.Context) {
+	_, _, companyID, err := middleware.GetUserFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not found in context"})
+		return
+	}
+	employeeID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid employee ID"})
+		return
+	}
+
+	effective, err := h.service.EffectivePolicy(companyID, employeeID)
+	if err != nil {
+		c.JSON(organizationErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, effective)
+}
+
+// ListViolations handles GET /time-policies/violations
+func (h *TimePolicyHandler) ListViolations(c *gin.Context) {
+	_, _, companyID, err := middleware.GetUserFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not found in context"})
+		return
+	}
+	filter := dtos.TimePolicyViolationFilter{Type: c.Query("type")}
+	if raw := c.Query("employee_id"); raw != "" {
+		id, err := uuid.Parse(raw)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid employee ID"})
+			return
+		}
+		filter.EmployeeID = &id
+	}
+	if raw := c.Query("resolved"); raw != "" {
+		resolved := raw == "true"
+		filter.Resolved = &resolved
+	}
+	for param, target := range map[string]**time.Time{"start_date": &filter.StartDate, "end_date": &filter.EndDate} {
+		if raw := c.Query(param); raw != "" {
+			date, err := time.Parse("2006-01-02", raw)
+			if err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "invalid " + param + ", expected YYYY-MM-DD"})
+				return
+			}
+			*target = &date
+		}
+	}
+
+	violations, err := h.service.ListViolations(companyID, filter)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"violations": violations, "count": len(violations)})
+}
+
+// Dashboard handles GET /time-policies/violations/dashboard
+func (h *TimePolicyHandler) Dashboard(c *gin.Context) {
+	_, _, companyID, err := middleware.GetUserFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not found in context"})
+		return
+	}
+	// Defaults to the last 30 days
+	end := time.Now().UTC().Truncate(24 * time.Hour)
+	start := end.AddDate(0, 0, -29)
+	if raw := c.Query("start_date"); raw != "" {
+		if start, err = time.Parse("2006-01-02", raw); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid start_date, expected YYYY-MM-DD"})
+			return
+		}
+	}
+	if raw := c.Query("end_date"); raw != "" {
+		if end, err = time.Parse("2006-01-02", raw); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid end_date, expected YYYY-MM-DD"})
+			return
+		}
+	}
+
+	dashboard, err := h.service.Dashboard(companyID, start, end)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, dashboard)
+}
+
+// ResolveViolation handles POST /time-policies/violations/:id/resolve
+func (h *TimePolicyHandler) ResolveViolation(c *gin.Context) {
+	userID, _, companyID, err := middleware.GetUserFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not found in context"})
+		return
+	}
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid violation ID"})
+		return
+	}
+	var req dtos.ResolveTimePolicyViolationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	violation, err := h.service.ResolveViolation(id, companyID, req.Notes, userID)
+	if err != nil {
+		c.JSON(organizationErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, violation)
+}
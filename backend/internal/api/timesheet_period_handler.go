@@ -0,0 +1,167 @@
+/*
+Package api - IRIS Payroll System HTTP API Handlers
+
+==============================================================================
+FILE: internal/api/timesheet_period_handler.go
+==============================================================================
+
+DESCRIPTION:
+    Endpoints for the timesheet cycle on the payroll calendar: generating a
+    period's timesheets, deadline reminders, billable utilization per
+    project and member, and the export of approved hours to prenómina.
+
+USER PERSPECTIVE:
+    - Payroll generates the timesheets when a period opens (or a daily job does)
+    - Reminders go out to employees whose timesheet is still a draft
+    - Project managers review billable vs. non-billable hours and amounts
+    - Payroll sends approved hours of hourly employees to prenómina
+
+DEVELOPER GUIDELINES:
+    ✅  OK to modify: Add report filters
+    ⚠️  CAUTION: Exporting changes what the next prenómina calculation pays
+    📝  Utilization counts every timesheet unless approved_only=true limits it
+        to approved and exported ones
+
+ENDPOINTS:
+    POST /timesheet-periods/generate         - Create the timesheets of open payroll periods
+    POST /timesheet-periods/reminders        - Remind employees of drafts due within 2 days
+    GET  /timesheet-periods/utilization      - Billable utilization (?start_date=&end_date=&project_id=&approved_only=true)
+    POST /timesheet-periods/export-prenomina - Send approved hours of a period to prenómina
+
+==============================================================================
+*/
+package api
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+
+	"backend/internal/dtos"
+	"backend/internal/middleware"
+	"backend/internal/services"
+)
+
+// TimesheetPeriodHandler handles timesheet generation, reminder, utilization and export endpoints
+type TimesheetPeriodHandler struct {
+	service *services.TimesheetPeriodService
+}
+
+// NewTimesheetPeriodHandler creates a new timesheet period handler
+func NewTimesheetPeriodHandler(service *services.TimesheetPeriodService) *TimesheetPeriodHandler {
+	return &TimesheetPeriodHandler{service: service}
+}
+
+// RegisterRoutes registers timesheet period routes
+func (h *TimesheetPeriodHandler) RegisterRoutes(router *gin.RouterGroup, authMiddleware *middleware.AuthMiddleware) {
+	reports := router.Group("/timesheet-periods")
+	reports.Use(authMiddleware.RequireRole("admin", "hr", "hr_and_pr", "payroll_staff", "manager", "supervisor", "sup_and_gm"))
+	{
+		reports.GET("/utilization", h.Utilization)
+	}
+
+	payroll := router.Group("/timesheet-periods")
+	payroll.Use(authMiddleware.RequireRole("admin", "hr", "hr_and_pr", "payroll_staff"))
+	{
+		payroll.POST("/generate", h.Generate)
+		payroll.POST("/reminders", h.SendReminders)
+		payroll.POST("/export-prenomina", h.ExportToPrenomina)
+	}
+}
+
+// Generate handles POST /timesheet-periods/generate
+func (h *TimesheetPeriodHandler) Generate(c *gin.Context) {
+	_, _, companyID, err := middleware.GetUserFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not found in context"})
+		return
+	}
+	var req dtos.TimesheetGenerateRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+	}
+
+	result, err := h.service.GenerateTimesheets(companyID, req)
+	if err != nil {
+		c.JSON(organizationErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, result)
+}
+
+// SendReminders handles POST /timesheet-periods/reminders
+func (h *TimesheetPeriodHandler) SendReminders(c *gin.Context) {
+	userID, _, companyID, err := middleware.GetUserFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not found in context"})
+		return
+	}
+
+	result, err := h.service.SendReminders(companyID, userID, time.Now())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, result)
+}
+
+// Utilization handles GET /timesheet-periods/utilization
+func (h *TimesheetPeriodHandler) Utilization(c *gin.Context) {
+	_, _, companyID, err := middleware.GetUserFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not found in context"})
+		return
+	}
+	start, err := time.Parse("2006-01-02", c.Query("start_date"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "start_date is required (YYYY-MM-DD)"})
+		return
+	}
+	end, err := time.Parse("2006-01-02", c.Query("end_date"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "end_date is required (YYYY-MM-DD)"})
+		return
+	}
+	filter := dtos.UtilizationFilter{StartDate: start, EndDate: end, ApprovedOnly: c.Query("approved_only") == "true"}
+	if raw := c.Query("project_id"); raw != "" {
+		projectID, err := uuid.Parse(raw)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid project_id"})
+			return
+		}
+		filter.ProjectID = &projectID
+	}
+
+	report, err := h.service.UtilizationReport(companyID, filter)
+	if err != nil {
+		c.JSON(organizationErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, report)
+}
+
+// ExportToPrenomina handles POST /timesheet-periods/export-prenomina
+func (h *TimesheetPeriodHandler) ExportToPrenomina(c *gin.Context) {
+	_, _, companyID, err := middleware.GetUserFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not found in context"})
+		return
+	}
+	var req dtos.TimesheetExportRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	result, err := h.service.ExportToPrenomina(companyID, req)
+	if err != nil {
+		c.JSON(organizationErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, result)
+}
@@ -1,8 +1,11 @@
 package api
 
 import (
+	"errors"
+	"io"
 	"net/http"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/gin-gonic/gin"
@@ -238,6 +241,10 @@ func (h *TimeTrackingHandler) CreateTimeEntry(c *gin.Context) {
 		return
 	}
 	entry, err := h.timeTrackingService.CreateTimeEntry(dto)
+	if errors.Is(err, services.ErrTimesheetLocked) {
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		return
+	}
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
@@ -280,6 +287,50 @@ func (h *TimeTrackingHandler) GetEmployeeTimeEntries(c *gin.Context) {
 }
 
 // Clock In/Out handlers
+
+// clockPunchRequest is the body of clock-in and clock-out
+type clockPunchRequest struct {
+	Source    string   `json:"source"`
+	Location  string   `json:"location"`
+	Notes     string   `json:"notes"`
+	Latitude  *float64 `json:"latitude" binding:"omitempty,gte=-90,lte=90"`
+	Longitude *float64 `json:"longitude" binding:"omitempty,gte=-180,lte=180"`
+	DeviceID  string   `json:"device_id"`
+	Photo     string   `json:"photo"` // Base64 JPEG/PNG, a data: URL is accepted
+}
+
+// bindClockPunch reads the punch body; the device id may also come in the X-Device-ID header
+func bindClockPunch(c *gin.Context) (services.ClockPunchDTO, error) {
+	var req clockPunchRequest
+	if err := c.ShouldBindJSON(&req); err != nil && !errors.Is(err, io.EOF) {
+		return services.ClockPunchDTO{}, err
+	}
+	if req.DeviceID == "" {
+		req.DeviceID = c.GetHeader("X-Device-ID")
+	}
+	return services.ClockPunchDTO{
+		Source:    req.Source,
+		Location:  req.Location,
+		IP:        c.ClientIP(),
+		Notes:     req.Notes,
+		Latitude:  req.Latitude,
+		Longitude: req.Longitude,
+		DeviceID:  req.DeviceID,
+		Photo:     req.Photo,
+	}, nil
+}
+
+// clockPunchErrorStatus maps punch errors to HTTP statuses
+func clockPunchErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, services.ErrPunchOutOfZone):
+		return http.StatusForbidden
+	case errors.Is(err, services.ErrPunchPhotoRequired), strings.HasPrefix(err.Error(), "photo must"):
+		return http.StatusBadRequest
+	}
+	return http.StatusInternalServerError
+}
+
 func (h *TimeTrackingHandler) ClockIn(c *gin.Context) {
 	companyID, _ := c.Get("company_id")
 	employeeID, err := uuid.Parse(c.Param("employeeId"))
@@ -287,15 +338,14 @@ func (h *TimeTrackingHandler) ClockIn(c *gin.Context) {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid employee ID"})
 		return
 	}
-	var req struct {
-		Source   string `json:"source"`
-		Location string `json:"location"`
-		Notes    string `json:"notes"`
+	punch, err := bindClockPunch(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
 	}
-	c.ShouldBindJSON(&req)
-	record, err := h.timeTrackingService.ClockIn(companyID.(uuid.UUID), employeeID, req.Source, req.Location, c.ClientIP(), req.Notes)
+	record, err := h.timeTrackingService.ClockIn(companyID.(uuid.UUID), employeeID, punch)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		c.JSON(clockPunchErrorStatus(err), gin.H{"error": err.Error()})
 		return
 	}
 	c.JSON(http.StatusCreated, record)
@@ -307,15 +357,14 @@ func (h *TimeTrackingHandler) ClockOut(c *gin.Context) {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid employee ID"})
 		return
 	}
-	var req struct {
-		Source   string `json:"source"`
-		Location string `json:"location"`
-		Notes    string `json:"notes"`
+	punch, err := bindClockPunch(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
 	}
-	c.ShouldBindJSON(&req)
-	record, err := h.timeTrackingService.ClockOut(employeeID, req.Source, req.Location, c.ClientIP(), req.Notes)
+	record, err := h.timeTrackingService.ClockOut(employeeID, punch)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		c.JSON(clockPunchErrorStatus(err), gin.H{"error": err.Error()})
 		return
 	}
 	c.JSON(http.StatusOK, record)
@@ -396,6 +445,10 @@ func (h *TimeTrackingHandler) UpdateTimeOffBalance(c *gin.Context) {
 		return
 	}
 	balance, err := h.timeTrackingService.UpdateTimeOffBalance(employeeID, req.Year, req.BalanceType, req.Hours, req.IsUsage)
+	if errors.Is(err, services.ErrVacationBalanceManaged) {
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		return
+	}
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
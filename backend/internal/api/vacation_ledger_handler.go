@@ -0,0 +1,166 @@
+/*
+Package api - IRIS Payroll System HTTP API Handlers
+
+==============================================================================
+FILE: internal/api/vacation_ledger_handler.go
+==============================================================================
+
+DESCRIPTION:
+    Endpoints for the vacation ledger: the statement of an employee (every
+    grant, consumption, reversal, expiry and adjustment with its running
+    balance), manual adjustments with a reason, and the company-wide runs
+    that post anniversary grants and expire prescribed days.
+
+USER PERSPECTIVE:
+    - Employees check their own days, carry-over and expiry dates
+    - HR explains and corrects a balance without editing it by hand
+    - The grant and expiry runs can be repeated safely (they are idempotent)
+
+DEVELOPER GUIDELINES:
+    ✅  OK to modify: Add statement filters
+    ⚠️  CAUTION: Adjustments require a reason; they are the only manual entry
+    📝  The daily vacation_ledger job makes the same runs; the endpoints let
+        HR catch up without waiting for it
+
+ENDPOINTS:
+    GET  /vacation-ledger/me                          - Own statement
+    GET  /vacation-ledger/employees/:id/statement     - Statement of an employee
+    POST /vacation-ledger/employees/:id/adjustments   - Manual adjustment (days, service_year, reason)
+    POST /vacation-ledger/grants/run                  - Post anniversary grants due today
+    POST /vacation-ledger/expiry/run                  - Expire days past their prescription
+
+==============================================================================
+*/
+package api
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+
+	"backend/internal/dtos"
+	"backend/internal/middleware"
+	"backend/internal/services"
+)
+
+// VacationLedgerHandler handles vacation ledger endpoints
+type VacationLedgerHandler struct {
+	service *services.VacationLedgerService
+}
+
+// NewVacationLedgerHandler creates a new vacation ledger handler
+func NewVacationLedgerHandler(service *services.VacationLedgerService) *VacationLedgerHandler {
+	return &VacationLedgerHandler{service: service}
+}
+
+// RegisterRoutes registers vacation ledger routes
+func (h *VacationLedgerHandler) RegisterRoutes(router *gin.RouterGroup, authMiddleware *middleware.AuthMiddleware) {
+	ledger := router.Group("/vacation-ledger")
+	ledger.GET("/me", h.MyStatement)
+
+	hr := ledger.Group("")
+	hr.Use(authMiddleware.RequireRole("admin", "hr", "hr_and_pr", "hr_blue_gray", "hr_white", "payroll_staff"))
+	{
+		hr.GET("/employees/:id/statement", h.Statement)
+		hr.POST("/employees/:id/adjustments", h.Adjust)
+		hr.POST("/grants/run", h.RunGrants)
+		hr.POST("/expiry/run", h.RunExpiry)
+	}
+}
+
+// MyStatement handles GET /vacation-ledger/me
+func (h *VacationLedgerHandler) MyStatement(c *gin.Context) {
+	userID, _, companyID, err := middleware.GetUserFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not found in context"})
+		return
+	}
+
+	statement, err := h.service.StatementForUser(companyID, userID, time.Now())
+	if err != nil {
+		c.JSON(organizationErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, statement)
+}
+
+// Statement handles GET /vacation-ledger/employees/:id/statement
+func (h *VacationLedgerHandler) Statement(c *gin.Context) {
+	_, _, companyID, err := middleware.GetUserFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not found in context"})
+		return
+	}
+	employeeID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid employee ID"})
+		return
+	}
+
+	statement, err := h.service.Statement(companyID, employeeID, time.Now())
+	if err != nil {
+		c.JSON(organizationErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, statement)
+}
+
+// Adjust handles POST /vacation-ledger/employees/:id/adjustments
+func (h *VacationLedgerHandler) Adjust(c *gin.Context) {
+	userID, _, companyID, err := middleware.GetUserFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not found in context"})
+		return
+	}
+	employeeID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid employee ID"})
+		return
+	}
+	var req dtos.VacationAdjustmentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	statement, err := h.service.Adjust(companyID, employeeID, userID, req, time.Now())
+	if err != nil {
+		c.JSON(organizationErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusCreated, statement)
+}
+
+// RunGrants handles POST /vacation-ledger/grants/run
+func (h *VacationLedgerHandler) RunGrants(c *gin.Context) {
+	_, _, companyID, err := middleware.GetUserFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not found in context"})
+		return
+	}
+
+	result, err := h.service.PostAnniversaryGrants(companyID, time.Now())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, result)
+}
+
+// RunExpiry handles POST /vacation-ledger/expiry/run
+func (h *VacationLedgerHandler) RunExpiry(c *gin.Context) {
+	_, _, companyID, err := middleware.GetUserFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not found in context"})
+		return
+	}
+
+	result, err := h.service.ExpireBalances(companyID, time.Now())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, result)
+}
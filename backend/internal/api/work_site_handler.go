@@ -0,0 +1,387 @@
+/*
+Package api - IRIS Payroll System HTTP API Handlers
+
+==============================================================================
+FILE: internal/api/work_site_handler.go
+==============================================================================
+
+DESCRIPTION:
+    Endpoints for geofenced web/app clock-in: work sites and their
+    employees, trusted devices, the review queue of flagged punches and the
+    selfies taken at clock-in and clock-out.
+
+USER PERSPECTIVE:
+    - HR draws the sites, lists their networks and assigns employees
+    - Employees register the phone they clock in from
+    - Supervisors accept or reject out-of-zone and unknown-device punches
+
+DEVELOPER GUIDELINES:
+    ✅  OK to modify: Add site filters
+    ⚠️  CAUTION: Rejected punches drop out of the attendance evaluation and
+        the prenómina on their next run
+    📝  Nobody reviews their own punches; supervisors review only employees
+        below them in the org chart (ErrClockNotAllowed answers 403)
+
+ENDPOINTS:
+    GET    /work-sites                           - List work sites
+    POST   /work-sites                           - Create a work site
+    PUT    /work-sites/:id                       - Update a work site
+    DELETE /work-sites/:id                       - Delete a work site
+    GET    /work-sites/:id/employees             - Employees assigned to a site
+    POST   /work-sites/:id/employees             - Assign employees to a site
+    DELETE /work-sites/:id/employees/:employeeId - Remove an employee from a site
+    POST   /clock-devices                        - Register a trusted device (own, or any for HR)
+    GET    /clock-devices                        - List devices (?employee_id=&active=true)
+    POST   /clock-devices/:id/revoke             - Revoke a trusted device
+    GET    /clock-reviews                        - Flagged punches to review (?status=pending|accepted|rejected)
+    POST   /clock-reviews/:id                    - Accept or reject a flagged punch
+    GET    /clock-reviews/:id/photo              - Selfie of a punch (?side=in|out)
+
+==============================================================================
+*/
+package api
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+
+	"backend/internal/dtos"
+	"backend/internal/middleware"
+	"backend/internal/services"
+)
+
+// WorkSiteHandler handles work site, device and punch review endpoints
+type WorkSiteHandler struct {
+	service *services.WorkSiteService
+}
+
+// NewWorkSiteHandler creates a new work site handler
+func NewWorkSiteHandler(service *services.WorkSiteService) *WorkSiteHandler {
+	return &WorkSiteHandler{service: service}
+}
+
+// RegisterRoutes registers work site routes
+func (h *WorkSiteHandler) RegisterRoutes(router *gin.RouterGroup, authMiddleware *middleware.AuthMiddleware) {
+	devices := router.Group("/clock-devices")
+	{
+		devices.POST("", h.RegisterDevice)
+	}
+
+	reviews := router.Group("/clock-reviews")
+	reviews.Use(authMiddleware.RequireRole("admin", "hr", "hr_and_pr", "hr_blue_gray", "hr_white", "supervisor", "manager", "sup_and_gm"))
+	{
+		reviews.GET("", h.ListFlagged)
+		reviews.POST("/:id", h.ReviewPunch)
+		reviews.GET("/:id/photo", h.GetPunchPhoto)
+	}
+
+	manage := router.Group("")
+	manage.Use(authMiddleware.RequireRole("admin", "hr", "hr_and_pr", "hr_blue_gray", "hr_white"))
+	{
+		manage.GET("/work-sites", h.ListSites)
+		manage.POST("/work-sites", h.CreateSite)
+		manage.PUT("/work-sites/:id", h.UpdateSite)
+		manage.DELETE("/work-sites/:id", h.DeleteSite)
+		manage.GET("/work-sites/:id/employees", h.ListSiteEmployees)
+		manage.POST("/work-sites/:id/employees", h.AssignEmployees)
+		manage.DELETE("/work-sites/:id/employees/:employeeId", h.UnassignEmployee)
+		manage.GET("/clock-devices", h.ListDevices)
+		manage.POST("/clock-devices/:id/revoke", h.RevokeDevice)
+	}
+}
+
+// workSiteErrorStatus maps work site service errors to HTTP statuses
+func workSiteErrorStatus(err error) int {
+	if errors.Is(err, services.ErrClockNotAllowed) {
+		return http.StatusForbidden
+	}
+	return organizationErrorStatus(err)
+}
+
+// ListSites handles GET /work-sites
+func (h *WorkSiteHandler) ListSites(c *gin.Context) {
+	_, _, companyID, err := middleware.GetUserFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not found in context"})
+		return
+	}
+
+	sites, err := h.service.ListSites(companyID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"work_sites": sites, "count": len(sites)})
+}
+
+// CreateSite handles POST /work-sites
+func (h *WorkSiteHandler) CreateSite(c *gin.Context) {
+	_, _, companyID, err := middleware.GetUserFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not found in context"})
+		return
+	}
+	var req dtos.WorkSiteRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	site, err := h.service.CreateSite(companyID, req)
+	if err != nil {
+		c.JSON(workSiteErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusCreated, site)
+}
+
+// UpdateSite handles PUT /work-sites/:id
+func (h *WorkSiteHandler) UpdateSite(c *gin.Context) {
+	_, _, companyID, err := middleware.GetUserFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not found in context"})
+		return
+	}
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid work site ID"})
+		return
+	}
+	var req dtos.WorkSiteRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	site, err := h.service.UpdateSite(id, companyID, req)
+	if err != nil {
+		c.JSON(workSiteErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, site)
+}
+
+// DeleteSite handles DELETE /work-sites/:id
+func (h *WorkSiteHandler) DeleteSite(c *gin.Context) {
+	_, _, companyID, err := middleware.GetUserFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not found in context"})
+		return
+	}
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid work site ID"})
+		return
+	}
+
+	if err := h.service.DeleteSite(id, companyID); err != nil {
+		c.JSON(workSiteErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "work site deleted"})
+}
+
+// ListSiteEmployees handles GET /work-sites/:id/employees
+func (h *WorkSiteHandler) ListSiteEmployees(c *gin.Context) {
+	_, _, companyID, err := middleware.GetUserFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not found in context"})
+		return
+	}
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid work site ID"})
+		return
+	}
+
+	assignments, err := h.service.ListSiteEmployees(id, companyID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"assignments": assignments, "count": len(assignments)})
+}
+
+// AssignEmployees handles POST /work-sites/:id/employees
+func (h *WorkSiteHandler) AssignEmployees(c *gin.Context) {
+	userID, _, companyID, err := middleware.GetUserFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not found in context"})
+		return
+	}
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid work site ID"})
+		return
+	}
+	var req dtos.WorkSiteAssignRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	assigned, err := h.service.AssignEmployees(id, companyID, req, userID)
+	if err != nil {
+		c.JSON(workSiteErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"assigned": assigned})
+}
+
+// UnassignEmployee handles DELETE /work-sites/:id/employees/:employeeId
+func (h *WorkSiteHandler) UnassignEmployee(c *gin.Context) {
+	_, _, companyID, err := middleware.GetUserFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not found in context"})
+		return
+	}
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid work site ID"})
+		return
+	}
+	employeeID, err := uuid.Parse(c.Param("employeeId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid employee ID"})
+		return
+	}
+
+	if err := h.service.UnassignEmployee(id, employeeID, companyID); err != nil {
+		c.JSON(workSiteErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "site assignment removed"})
+}
+
+// RegisterDevice handles POST /clock-devices
+func (h *WorkSiteHandler) RegisterDevice(c *gin.Context) {
+	userID, _, companyID, err := middleware.GetUserFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not found in context"})
+		return
+	}
+	var req dtos.TrustedDeviceRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	device, err := h.service.RegisterDevice(companyID, userID, req)
+	if err != nil {
+		c.JSON(workSiteErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusCreated, device)
+}
+
+// ListDevices handles GET /clock-devices
+func (h *WorkSiteHandler) ListDevices(c *gin.Context) {
+	_, _, companyID, err := middleware.GetUserFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not found in context"})
+		return
+	}
+	var employeeID *uuid.UUID
+	if raw := c.Query("employee_id"); raw != "" {
+		id, err := uuid.Parse(raw)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid employee ID"})
+			return
+		}
+		employeeID = &id
+	}
+
+	devices, err := h.service.ListDevices(companyID, employeeID, c.Query("active") == "true")
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"devices": devices, "count": len(devices)})
+}
+
+// RevokeDevice handles POST /clock-devices/:id/revoke
+func (h *WorkSiteHandler) RevokeDevice(c *gin.Context) {
+	userID, _, companyID, err := middleware.GetUserFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not found in context"})
+		return
+	}
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid device ID"})
+		return
+	}
+
+	device, err := h.service.RevokeDevice(id, companyID, userID)
+	if err != nil {
+		c.JSON(workSiteErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, device)
+}
+
+// ListFlagged handles GET /clock-reviews
+func (h *WorkSiteHandler) ListFlagged(c *gin.Context) {
+	userID, _, companyID, err := middleware.GetUserFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not found in context"})
+		return
+	}
+
+	records, err := h.service.ListFlagged(companyID, userID, c.Query("status"))
+	if err != nil {
+		c.JSON(workSiteErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"records": records, "count": len(records)})
+}
+
+// ReviewPunch handles POST /clock-reviews/:id
+func (h *WorkSiteHandler) ReviewPunch(c *gin.Context) {
+	userID, _, companyID, err := middleware.GetUserFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not found in context"})
+		return
+	}
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid clock record ID"})
+		return
+	}
+	var req dtos.ClockReviewRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	record, err := h.service.ReviewPunch(id, companyID, userID, req)
+	if err != nil {
+		c.JSON(workSiteErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, record)
+}
+
+// GetPunchPhoto handles GET /clock-reviews/:id/photo
+func (h *WorkSiteHandler) GetPunchPhoto(c *gin.Context) {
+	_, _, companyID, err := middleware.GetUserFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not found in context"})
+		return
+	}
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid clock record ID"})
+		return
+	}
+
+	path, err := h.service.PunchPhotoPath(id, companyID, c.DefaultQuery("side", "in"))
+	if err != nil {
+		c.JSON(workSiteErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.File(path)
+}
@@ -86,6 +86,13 @@ type AppConfig struct {
 	SMTPPassword string `mapstructure:"SMTP_PASSWORD"`
 	EmailFrom    string `mapstructure:"EMAIL_FROM"`
 
+	// Job scheduler: replicas elect a leader through the database, so it can
+	// stay enabled everywhere; InstanceID identifies this replica (hostname-pid).
+	// Scheduled changes (salary campaigns, assignments, reporting lines) only
+	// run in the scheduler: with it disabled on every replica nothing applies them
+	SchedulerEnabled    bool   `mapstructure:"SCHEDULER_ENABLED"`
+	SchedulerInstanceID string `mapstructure:"SCHEDULER_INSTANCE_ID"`
+
 	// Payroll configuration (loaded from JSON)
 	PayrollConfig *payroll.PayrollConfig
 
@@ -113,6 +120,7 @@ func DefaultAppConfig() *AppConfig {
 		SMTPUsername:               "",
 		SMTPPassword:               "",
 		EmailFrom:                  "noreply@iristalent.com",
+		SchedulerEnabled:           true,
 		PayrollConfig:              nil,
 	}
 }
@@ -164,6 +172,16 @@ func LoadAppConfig(configDir string) (*AppConfig, error) {
 	if emailFrom := os.Getenv("EMAIL_FROM"); emailFrom != "" {
 		config.EmailFrom = emailFrom
 	}
+	if schedulerEnabled := os.Getenv("SCHEDULER_ENABLED"); schedulerEnabled != "" {
+		if enabled, err := strconv.ParseBool(schedulerEnabled); err == nil {
+			config.SchedulerEnabled = enabled
+		}
+	}
+	config.SchedulerInstanceID = os.Getenv("SCHEDULER_INSTANCE_ID")
+	if config.SchedulerInstanceID == "" {
+		hostname, _ := os.Hostname()
+		config.SchedulerInstanceID = fmt.Sprintf("%s-%d", hostname, os.Getpid())
+	}
 
 	// Load secrets from Vault if configured
 	if os.Getenv("VAULT_ADDR") != "" {
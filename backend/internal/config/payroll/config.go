@@ -24,7 +24,7 @@ DEVELOPER GUIDELINES:
 
 SYNTAX EXPLANATION:
     - PayrollConfig struct: Aggregates all config types
-    - GetVacationDaysForYears(): Implements LFT vacation table
+    - GetVacationDaysForYears(): Implements LFT vacation table (VacationTable)
     - CalculateIMSSEmployerContribution(): Sum of all IMSS employer rates
 
 MEXICAN LAW REFERENCES:
@@ -48,6 +48,7 @@ type PayrollConfig struct {
 	LaborConcepts     types.LaborConcepts     `json:"labor_concepts"`
 	CalculationTables types.CalculationTables `json:"calculation_tables"`
 	MexicanTaxConfig  MexicanTaxConfig        `yaml:"mexican_tax_config"`
+	VacationTable     types.VacationTable     `json:"vacation_table"` // tables.vacations in main.json
 }
 
 // NewPayrollConfig creates a new empty payroll configuration
@@ -84,6 +85,32 @@ func (pc *PayrollConfig) GetSMGForZone(zone string) (float64, error) {
     return pc.GetDefaultSMG()
 }
 
+// GetProfessionalSMG returns the professional minimum wage for an occupation code in a zone
+func (pc *PayrollConfig) GetProfessionalSMG(zone, occupation string) (float64, bool) {
+    if occupation == "" {
+        return 0, false
+    }
+    wages := pc.OfficialValues.MinimumWages.General.ProfessionalDaily
+    if zone == "northern_border_free_zone" {
+        wages = pc.OfficialValues.MinimumWages.NorthernBorderFreeZone.ProfessionalDaily
+    }
+    value, ok := wages[occupation]
+    return value, ok && value > 0
+}
+
+// GetMinimumWage returns the salary floor for an employee: the zone minimum wage,
+// or the professional minimum wage of the occupation when it is higher
+func (pc *PayrollConfig) GetMinimumWage(zone, occupation string) (float64, error) {
+    smg, err := pc.GetSMGForZone(zone)
+    if err != nil {
+        return 0, err
+    }
+    if professional, ok := pc.GetProfessionalSMG(zone, occupation); ok && professional > smg {
+        return professional, nil
+    }
+    return smg, nil
+}
+
 // CalculateIMSSEmployerContribution calculates employer IMSS contribution
 func (pc *PayrollConfig) CalculateIMSSEmployerContribution(baseSalary float64) float64 {
     // Sickness and maternity (cash benefits)
@@ -134,36 +161,50 @@ func (pc *PayrollConfig) GetIMSSEmployeeTotalRate() float64 {
     return rates.DiseaseMaternityInsurance + rates.DisabilityLife + rates.Retirement
 }
 
-// GetVacationDaysForYears returns vacation days based on years of service
+// GetVacationDaysForYears returns the vacation days earned at the given work
+// anniversary, from VacationTable or, when it was not loaded, DefaultVacationTable
 func (pc *PayrollConfig) GetVacationDaysForYears(yearsOfService int) int {
-    if yearsOfService == 1 {
-        return pc.LaborConcepts.Vacations.FirstYearDays
+    if yearsOfService < 1 {
+        return 0
+    }
+    
+    table := pc.VacationTable
+    if len(table.Brackets) == 0 {
+        table = DefaultVacationTable()
     }
     
-    // Default calculation if table is not loaded
-    if yearsOfService >= 2 && yearsOfService <= 5 {
-        return 14
-    } else if yearsOfService >= 6 && yearsOfService <= 10 {
-        return 16
-    } else if yearsOfService >= 11 && yearsOfService <= 15 {
-        return 18
-    } else if yearsOfService >= 16 && yearsOfService <= 20 {
-        return 20
-    } else if yearsOfService >= 21 && yearsOfService <= 25 {
-        return 22
-    } else if yearsOfService >= 26 && yearsOfService <= 30 {
-        return 24
-    } else if yearsOfService >= 31 && yearsOfService <= 35 {
-        return 26
-    } else if yearsOfService >= 36 && yearsOfService <= 40 {
-        return 28
-    } else if yearsOfService >= 41 && yearsOfService <= 45 {
-        return 30
-    } else if yearsOfService >= 46 && yearsOfService <= 50 {
-        return 32
-    } else if yearsOfService >= 51 {
-        return 34
+    for _, bracket := range table.Brackets {
+        if yearsOfService >= bracket.MinYears && yearsOfService <= bracket.MaxYears {
+            return bracket.Days
+        }
     }
     
+    // Beyond the last bracket the last entitlement applies
+    last := table.Brackets[len(table.Brackets)-1]
+    if yearsOfService > last.MaxYears {
+        return last.Days
+    }
     return pc.LaborConcepts.Vacations.FirstYearDays
 }
+
+// DefaultVacationTable is the LFT Art. 76 table as amended in 2023: 12 days
+// the first year, +2 per year up to 20 days at year 5, then +2 every 5 years
+func DefaultVacationTable() types.VacationTable {
+    brackets := []types.VacationTableBracket{
+        {MinYears: 1, MaxYears: 1, Days: 12},
+        {MinYears: 2, MaxYears: 2, Days: 14},
+        {MinYears: 3, MaxYears: 3, Days: 16},
+        {MinYears: 4, MaxYears: 4, Days: 18},
+        {MinYears: 5, MaxYears: 5, Days: 20},
+    }
+    for minYears, days := 6, 22; minYears <= 46; minYears, days = minYears+5, days+2 {
+        brackets = append(brackets, types.VacationTableBracket{MinYears: minYears, MaxYears: minYears + 4, Days: days})
+    }
+    brackets = append(brackets, types.VacationTableBracket{MinYears: 51, MaxYears: 99, Days: 40})
+    
+    return types.VacationTable{
+        Year:        2023,
+        Description: "LFT Art. 76 (reform 2023)",
+        Brackets:    brackets,
+    }
+}
@@ -36,6 +36,10 @@ CONFIG DIRECTORY STRUCTURE:
         ├── contribution_rates.json
         ├── labor_concepts.json
         └── calculation_tables.json
+    └── holidays/
+        └── slp_2025.json       (state/municipal holidays, see HolidayCalendar)
+    └── tables/
+        └── vacations_2025.json (LFT vacation days by seniority, see VacationTable)
 
 ==============================================================================
 */
@@ -47,7 +51,7 @@ import (
     "os"
     "path/filepath"
 
-
+    "backend/internal/config/payroll/types"
 )
 
 // PayrollConfigLoader handles loading payroll configuration
@@ -142,6 +146,62 @@ func (pcl *PayrollConfigLoader) loadAllConfigurations() error {
         return err
     }
     
+    // Load local holiday calendars (merged into Regional.LocalHolidays)
+    if err := pcl.loadHolidayCalendars(); err != nil {
+        return err
+    }
+    
+    // Load the vacation days table referenced under "tables"
+    if err := pcl.loadVacationTable(); err != nil {
+        return err
+    }
+    
+    return nil
+}
+
+// loadVacationTable loads tables.vacations into VacationTable; without it the
+// built-in LFT table (DefaultVacationTable) is used
+func (pcl *PayrollConfigLoader) loadVacationTable() error {
+    filePath, ok := pcl.master.Tables["vacations"].(string)
+    if !ok || filePath == "" {
+        return nil
+    }
+    if !filepath.IsAbs(filePath) {
+        filePath = filepath.Join(pcl.configDir, filePath)
+    }
+    
+    data, err := os.ReadFile(filePath)
+    if err != nil {
+        return fmt.Errorf("error reading vacation table %s: %w", filePath, err)
+    }
+    
+    if err := json.Unmarshal(data, &pcl.config.VacationTable); err != nil {
+        return fmt.Errorf("error parsing vacation table %s: %w", filePath, err)
+    }
+    
+    return nil
+}
+
+// loadHolidayCalendars loads every holiday file listed under "holidays" in the master config
+func (pcl *PayrollConfigLoader) loadHolidayCalendars() error {
+    for name, filePath := range pcl.master.Holidays {
+        if !filepath.IsAbs(filePath) {
+            filePath = filepath.Join(pcl.configDir, filePath)
+        }
+        
+        data, err := os.ReadFile(filePath)
+        if err != nil {
+            return fmt.Errorf("error reading holiday calendar %s: %w", name, err)
+        }
+        
+        var calendar types.HolidayCalendar
+        if err := json.Unmarshal(data, &calendar); err != nil {
+            return fmt.Errorf("error parsing holiday calendar %s: %w", name, err)
+        }
+        
+        pcl.config.Regional.LocalHolidays = append(pcl.config.Regional.LocalHolidays, calendar.Holidays...)
+    }
+    
     return nil
 }
 
@@ -64,6 +64,8 @@ type MinimumWages struct {
 // MinimumWageZone defines minimum wage values for a specific zone.
 type MinimumWageZone struct {
 	DailyValue        float64 `json:"daily_value"`
+	Year              int     `json:"year,omitempty"`
+	EffectiveDate     string  `json:"effective_date,omitempty"`
 	ProfessionalDaily map[string]float64 `json:"professional_daily,omitempty"`
 }
 
@@ -96,6 +98,7 @@ type RegionalConfig struct {
 	State           StateConfig          `json:"state"`
 	StatePayrollTax StatePayrollTaxConfig `json:"state_payroll_tax"`
 	LocalHolidays   []LocalHoliday       `json:"local_holidays"`
+	StateHolidays   []LocalHoliday       `json:"state_holidays"`
 }
 
 // StateConfig defines basic state information.
@@ -114,10 +117,20 @@ type StatePayrollTaxConfig struct {
 // LocalHoliday defines a local holiday.
 type LocalHoliday struct {
 	Date        string `json:"date"`
+	Name        string `json:"name,omitempty"`
 	Description string `json:"description"`
+	Type        string `json:"type,omitempty"`       // e.g., "state", "municipal"
+	Paid        bool   `json:"paid,omitempty"`
 	AppliesTo   string `json:"applies_to"` // e.g., "all", "state", "municipality"
 }
 
+// HolidayCalendar is the structure of the files under configs/holidays/.
+type HolidayCalendar struct {
+	Year     int            `json:"year"`
+	State    string         `json:"state"`
+	Holidays []LocalHoliday `json:"holidays"`
+}
+
 
 // ContributionRates holds all social security and other contribution rates.
 type ContributionRates struct {
@@ -186,6 +199,7 @@ type LaborConcepts struct {
 	WorkSchedule   WorkSchedule   `json:"work_schedule"`
 	Overtime       Overtime       `json:"overtime"`
 	SundayPremium  SundayPremium  `json:"sunday_premium"`
+	Attendance     AttendanceRules `json:"attendance"`
 	SavingsFund    SavingsFund    `json:"savings_fund"`
 	FoodVouchers   FoodVouchers   `json:"food_vouchers"`
 }
@@ -204,6 +218,22 @@ type VacationRulesConfig struct {
 	FirstYearDays       int     `json:"first_year_days"`
 	VacationBonusPercentage float64 `json:"vacation_bonus_percentage"`
 	TaxExemptUMALimit   float64 `json:"tax_exempt_uma_limit"` // In UMAs
+	PrescriptionMonths  int     `json:"prescription_months"`  // Months after each anniversary grant before unused days expire (LFT Art. 81 + 516)
+	MaxCarryOverDays    float64 `json:"max_carry_over_days"`  // Days of earlier grants kept at an anniversary; 0 = no cap
+}
+
+// VacationTable is the vacation days table by years of service (tables/vacations_*.json).
+type VacationTable struct {
+	Year        int                    `json:"year"`
+	Description string                 `json:"description"`
+	Brackets    []VacationTableBracket `json:"brackets"`
+}
+
+// VacationTableBracket grants Days for MinYears..MaxYears of service (inclusive).
+type VacationTableBracket struct {
+	MinYears int `json:"min_years"`
+	MaxYears int `json:"max_years"`
+	Days     int `json:"days"`
 }
 
 // VacationDaysBracket defines vacation days per years of service.
@@ -234,6 +264,19 @@ type SundayPremium struct {
 	TaxExemptUMALimit float64 `json:"tax_exempt_uma_limit"` // In UMAs
 }
 
+// AttendanceRules defines how punches are compared against the scheduled shift.
+type AttendanceRules struct {
+	LateToleranceMinutes         int     `json:"late_tolerance_minutes"`         // Minutes after shift start before a delay counts
+	EarlyExitToleranceMinutes    int     `json:"early_exit_tolerance_minutes"`   // Minutes before shift end tolerated on exit
+	OvertimeMinimumMinutes       int     `json:"overtime_minimum_minutes"`       // Extra minutes after shift end before overtime counts
+	OvertimeBlockMinutes         int     `json:"overtime_block_minutes"`         // Overtime is rounded down to blocks of this size
+	PunchWindowHours             float64 `json:"punch_window_hours"`             // How early before shift start a clock-in still belongs to that shift
+	WeeklyDoubleTimeHours        float64 `json:"weekly_double_time_hours"`       // LFT Art. 67-68: first 9 weekly hours paid double, rest triple
+	RestDayPremiumFactor         float64 `json:"rest_day_premium_factor"`        // LFT Art. 73/75: extra daily salaries for a worked rest day or holiday
+	SwipeDebounceSeconds         int     `json:"swipe_debounce_seconds"`         // A second card tap this close to an accepted one is ignored
+	RequireOvertimeAuthorization bool    `json:"require_overtime_authorization"` // Only overtime pre-authorized by an OvertimeRequest is paid
+}
+
 // SavingsFund defines rules for savings fund.
 type SavingsFund struct {
 	EmployerContributionPercentage float64 `json:"employer_contribution_percentage"`
@@ -190,5 +190,9 @@ func (v *Validator) validateLaborConcepts(lc types.LaborConcepts) error {
         return fmt.Errorf("sunday premium percentage cannot be negative")
     }
     
+    if lc.Attendance.LateToleranceMinutes < 0 || lc.Attendance.EarlyExitToleranceMinutes < 0 || lc.Attendance.SwipeDebounceSeconds < 0 {
+        return fmt.Errorf("attendance tolerances cannot be negative")
+    }
+    
     return nil
 }
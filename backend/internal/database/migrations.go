@@ -41,6 +41,23 @@ MODEL LIST (in migration order):
     - PayrollConcept: Configurable payroll concepts
     - SalaryHistory: Salary change tracking
     - Notification/NotificationRead: User notifications
+    - IMSSMovement: Affiliate movements pending IDSE submission
+    - SalaryCampaign/SalaryCampaignItem: Mass salary increase campaigns
+    - Department/SalaryGrade/Position/EmployeeAssignment: Org catalog and pay bands
+    - ReportingLine/EmployeeHierarchy: Manager history and closure table
+    - AttendanceDay: Daily punches vs. shift evaluation (prenómina audit trail)
+    - AttendanceCard/TerminalSwipe: NFC cards and the idempotent swipe log
+    - TimePolicyViolation: Breaks and overtime limits breached (policy dashboard)
+    - OvertimeApproval: Approval history of overtime pre-authorizations
+    - RotationPattern/RotationAssignment/RosterSwap/StaffingRule/BlackoutPeriod: Rotating rosters, coverage and leave blackouts
+    - WorkSite/EmployeeWorkSite/TrustedDevice: Geofenced and device-bound web/app clock-in
+    - ProjectBudgetAlert: Project and task budget thresholds crossed
+    - TimeOffBalance/TimeOffAccrual: Vacation ledger (grants, consumption, expiry) and its balance
+    - ApprovalWorkflow/ApprovalWorkflowStep: Configurable approval routes of absence requests
+    - ApprovalDelegation: Out-of-office substitutes acting on an approver's behalf
+    - SchedulerLock/JobRun: Job scheduler leadership lease and run history
+    - SickLeaveCertificate: IMSS incapacidades and their company/IMSS day split
+    - LeavePolicy: Leave catalog beyond vacation (maternity, paternity, union leave, ...)
 
 ==============================================================================
 */
@@ -151,6 +168,7 @@ func Migrate(db *gorm.DB) error {
 		&models.TimeEntry{},
 		&models.ClockRecord{},
 		&models.OvertimeRequest{},
+		&models.OvertimeApproval{},
 		&models.TimeOffBalance{},
 		&models.TimeOffAccrual{},
 		&models.TimePolicy{},
@@ -175,5 +193,50 @@ func Migrate(db *gorm.DB) error {
 		&models.DocumentRequirement{},
 		&models.EmployeeDocument{},
 		&models.SharedDocument{},
+		// IMSS affiliate movements (salary modifications for IDSE)
+		&models.IMSSMovement{},
+		// Salary review campaigns
+		&models.SalaryCampaign{},
+		&models.SalaryCampaignItem{},
+		// Organizational catalog (departments, grades, positions, assignments)
+		&models.Department{},
+		&models.SalaryGrade{},
+		&models.Position{},
+		&models.EmployeeAssignment{},
+		// Reporting lines and org hierarchy (closure table)
+		&models.ReportingLine{},
+		&models.EmployeeHierarchy{},
+		// Daily attendance evaluation
+		&models.AttendanceDay{},
+		// NFC attendance terminals
+		&models.AttendanceCard{},
+		&models.TerminalSwipe{},
+		// Time policy violations
+		&models.TimePolicyViolation{},
+		// Rotating rosters
+		&models.RotationPattern{},
+		&models.RotationPatternDay{},
+		&models.RotationAssignment{},
+		&models.RosterSwap{},
+		&models.StaffingRule{},
+		&models.BlackoutPeriod{},
+		// Web/app clock-in sites and devices
+		&models.WorkSite{},
+		&models.EmployeeWorkSite{},
+		&models.TrustedDevice{},
+		// Project budget tracking
+		&models.ProjectBudgetAlert{},
+		// Configurable approval workflows
+		&models.ApprovalWorkflow{},
+		&models.ApprovalWorkflowStep{},
+		// Out-of-office approval substitutes
+		&models.ApprovalDelegation{},
+		// In-process job scheduler
+		&models.SchedulerLock{},
+		&models.JobRun{},
+		// IMSS incapacidades
+		&models.SickLeaveCertificate{},
+		// Leave policy catalog
+		&models.LeavePolicy{},
 	)
 }
@@ -0,0 +1,41 @@
+/*
+Package dtos - Approval Delegation Data Transfer Objects
+
+==============================================================================
+FILE: internal/dtos/approval_delegation.go
+==============================================================================
+
+DESCRIPTION:
+    Request structures for out-of-office substitutes: creating a
+    delegation of approval authority and confirming a suggested one.
+
+USER PERSPECTIVE:
+    - An approver names a substitute for their vacation
+    - A suggested substitute can be confirmed as is or swapped for someone else
+
+DEVELOPER GUIDELINES:
+    ✅  OK to modify: Add delegation scopes together with the model
+    📝  Dates are whole days (YYYY-MM-DD), both inclusive
+
+==============================================================================
+*/
+package dtos
+
+import (
+	"github.com/google/uuid"
+)
+
+// ApprovalDelegationRequest delegates approval authority for a date range
+type ApprovalDelegationRequest struct {
+	DelegatorID  *uuid.UUID `json:"delegator_id,omitempty"` // HR setting one up for someone else; defaults to the caller
+	DelegateID   uuid.UUID  `json:"delegate_id" binding:"required"`
+	StartDate    Date       `json:"start_date" binding:"required"`
+	EndDate      Date       `json:"end_date" binding:"required"`
+	RequestTypes []string   `json:"request_types,omitempty"` // Empty = every request type
+	Reason       string     `json:"reason,omitempty"`
+}
+
+// AcceptDelegationRequest confirms a suggested delegation
+type AcceptDelegationRequest struct {
+	DelegateID *uuid.UUID `json:"delegate_id,omitempty"` // Another substitute than the suggested one
+}
@@ -0,0 +1,61 @@
+/*
+Package dtos - Approval Workflow Data Transfer Objects
+
+==============================================================================
+FILE: internal/dtos/approval_workflow.go
+==============================================================================
+
+DESCRIPTION:
+    Request structures for maintaining the approval workflows of a company
+    (their steps, approver rules, conditions and escalation timeouts) and
+    the preview of the route a request would take.
+
+USER PERSPECTIVE:
+    - HR edits a workflow as a list of steps
+    - Before saving, HR can see which steps an example request would go through
+
+DEVELOPER GUIDELINES:
+    ✅  OK to modify: Add step conditions together with the model
+    📝  Steps are replaced as a whole on update; requests in flight keep
+        matching steps by stage code
+
+==============================================================================
+*/
+package dtos
+
+import (
+	"github.com/google/uuid"
+)
+
+// ApprovalWorkflowRequest creates or updates an approval workflow
+type ApprovalWorkflowRequest struct {
+	IncidenceTypeID *uuid.UUID                    `json:"incidence_type_id,omitempty"` // Bind to one incidence type
+	Code            string                        `json:"code,omitempty"`              // IncidenceType.ApprovalFlow it serves
+	Name            string                        `json:"name" binding:"required"`
+	Description     string                        `json:"description,omitempty"`
+	IsDefault       bool                          `json:"is_default"`
+	IsActive        *bool                         `json:"is_active,omitempty"`
+	Steps           []ApprovalWorkflowStepRequest `json:"steps" binding:"required,min=1,dive"`
+}
+
+// ApprovalWorkflowStepRequest is one step of a workflow
+type ApprovalWorkflowStepRequest struct {
+	StepOrder       int        `json:"step_order" binding:"required,min=1"`
+	Stage           string     `json:"stage" binding:"required"`
+	Name            string     `json:"name,omitempty"`
+	ApproverRule    string     `json:"approver_rule" binding:"required,oneof=supervisor role user hr_by_collar"`
+	ApproverRoles   []string   `json:"approver_roles,omitempty"`
+	ApproverUserID  *uuid.UUID `json:"approver_user_id,omitempty"`
+	MinDays         float64    `json:"min_days" binding:"gte=0"`
+	RequestTypes    []string   `json:"request_types,omitempty"`
+	CollarScope     string     `json:"collar_scope,omitempty" binding:"omitempty,oneof=white blue_gray"`
+	EscalationHours int        `json:"escalation_hours" binding:"gte=0"`
+	ReminderHours   int        `json:"reminder_hours" binding:"gte=0"`
+}
+
+// ApprovalRoutePreview is the route a request would follow
+type ApprovalRoutePreview struct {
+	WorkflowID   uuid.UUID  `json:"workflow_id"`
+	WorkflowName string     `json:"workflow_name"`
+	Groups       [][]string `json:"groups"` // Stages in order; stages of one group run in parallel
+}
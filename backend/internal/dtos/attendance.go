@@ -0,0 +1,123 @@
+/*
+Package dtos - Attendance Evaluation Data Transfer Objects
+
+==============================================================================
+FILE: internal/dtos/attendance.go
+==============================================================================
+
+DESCRIPTION:
+    Request and response structures for the daily attendance evaluation
+    that feeds the prenómina (delays, absences, overtime, worked rest days).
+
+USER PERSPECTIVE:
+    - HR evaluates a payroll period or a date range before the prenómina
+    - HR corrects a single day with a mandatory reason
+    - The summary shows the same totals that land in PrenominaMetric
+    - NFC terminals send swipe batches and enroll cards
+
+DEVELOPER GUIDELINES:
+    ✅  OK to modify: Add summary counters
+    📝  Override fields are optional; nil keeps the evaluated value
+    📝  OvertimeDoubleHours/OvertimeTripleHours follow the LFT weekly 9-hour split
+    📝  TerminalSwipeRequest.SwipeID is the terminal's idempotency key
+
+==============================================================================
+*/
+package dtos
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// AttendanceEvaluateRequest evaluates attendance for a period or a date range
+type AttendanceEvaluateRequest struct {
+	PayrollPeriodID *uuid.UUID  `json:"payroll_period_id,omitempty"`
+	StartDate       *Date       `json:"start_date,omitempty"` // Required without payroll_period_id
+	EndDate         *Date       `json:"end_date,omitempty"`
+	EmployeeIDs     []uuid.UUID `json:"employee_ids,omitempty"` // Empty: every active employee of the company
+}
+
+// AttendanceOverrideRequest is an HR correction of one evaluated day
+type AttendanceOverrideRequest struct {
+	Status           string     `json:"status" binding:"required,oneof=present late incomplete absent justified rest_day holiday worked_rest_day worked_holiday"`
+	ActualIn         *time.Time `json:"actual_in,omitempty"`
+	ActualOut        *time.Time `json:"actual_out,omitempty"`
+	WorkedHours      *float64   `json:"worked_hours,omitempty" binding:"omitempty,gte=0,lte=24"`
+	LateMinutes      *float64   `json:"late_minutes,omitempty" binding:"omitempty,gte=0"`
+	EarlyExitMinutes *float64   `json:"early_exit_minutes,omitempty" binding:"omitempty,gte=0"`
+	OvertimeHours    *float64   `json:"overtime_hours,omitempty" binding:"omitempty,gte=0,lte=24"`
+	Justification    string     `json:"justification,omitempty"`
+	Reason           string     `json:"reason" binding:"required"`
+}
+
+// AttendanceSummary totals evaluated days the way the prenómina consumes them
+type AttendanceSummary struct {
+	EmployeeID                uuid.UUID `json:"employee_id"`
+	StartDate                 time.Time `json:"start_date"`
+	EndDate                   time.Time `json:"end_date"`
+	EvaluatedDays             int       `json:"evaluated_days"`
+	ScheduledDays             int       `json:"scheduled_days"`
+	PresentDays               int       `json:"present_days"`
+	AbsentDays                int       `json:"absent_days"` // Unjustified only
+	JustifiedDays             int       `json:"justified_days"`
+	IncompleteDays            int       `json:"incomplete_days"`
+	RestDaysWorked            int       `json:"rest_days_worked"` // Rest days and holidays with punches
+	SundaysWorked             int       `json:"sundays_worked"`
+	LateCount                 int       `json:"late_count"`
+	LateMinutes               float64   `json:"late_minutes"`
+	EarlyExitCount            int       `json:"early_exit_count"`
+	EarlyExitMinutes          float64   `json:"early_exit_minutes"`
+	WorkedHours               float64   `json:"worked_hours"`
+	OvertimeHours             float64   `json:"overtime_hours"`
+	OvertimeDoubleHours       float64   `json:"overtime_double_hours"`
+	OvertimeTripleHours       float64   `json:"overtime_triple_hours"`
+	UnauthorizedOvertimeHours float64   `json:"unauthorized_overtime_hours"` // Worked past the shift without an approved OvertimeRequest
+	UnauthorizedOvertimeDays  int       `json:"unauthorized_overtime_days"`
+	BankedOvertimeHours       float64   `json:"banked_overtime_hours"` // Goes to the time-for-time bank, not paid
+	OverriddenDays            int       `json:"overridden_days"`
+	HasAttendanceData         bool      `json:"has_attendance_data"` // False when there is no schedule and no punch
+}
+
+// TerminalSwipeRequest is one card swipe captured by a terminal
+type TerminalSwipeRequest struct {
+	SwipeID  uuid.UUID `json:"swipe_id" binding:"required"`
+	CardUID  string    `json:"card_uid" binding:"required,max=32"`
+	SwipedAt time.Time `json:"swiped_at" binding:"required"`
+	Offline  bool      `json:"offline,omitempty"` // Buffered while the terminal had no network
+}
+
+// TerminalSwipeBatchRequest sends one or more swipes, oldest first
+type TerminalSwipeBatchRequest struct {
+	TerminalID string                 `json:"terminal_id" binding:"required,max=100"`
+	Swipes     []TerminalSwipeRequest `json:"swipes" binding:"required,min=1,max=500,dive"`
+}
+
+// TerminalSwipeResult is the outcome of one swipe
+type TerminalSwipeResult struct {
+	SwipeID        uuid.UUID  `json:"swipe_id"`
+	Status         string     `json:"status"`              // accepted, ignored, rejected
+	Direction      string     `json:"direction,omitempty"` // in, break_start, break_end, out
+	WorkDate       *time.Time `json:"work_date,omitempty"` // Workday of the shift the punch belongs to
+	ClockRecordID  *uuid.UUID `json:"clock_record_id,omitempty"`
+	EmployeeID     *uuid.UUID `json:"employee_id,omitempty"`
+	EmployeeName   string     `json:"employee_name,omitempty"`
+	EmployeeNumber string     `json:"employee_number,omitempty"`
+	Message        string     `json:"message,omitempty"`
+	SwipedAt       time.Time  `json:"swiped_at"`
+	Duplicate      bool       `json:"duplicate"` // Already received before; nothing changed
+}
+
+// TerminalSwipeBatchResponse returns one result per swipe, in request order
+type TerminalSwipeBatchResponse struct {
+	Results []TerminalSwipeResult `json:"results"`
+}
+
+// AttendanceCardRequest enrolls a card to an employee (by ID or employee number)
+type AttendanceCardRequest struct {
+	CardUID        string     `json:"card_uid" binding:"required,max=32"`
+	EmployeeID     *uuid.UUID `json:"employee_id,omitempty"`
+	EmployeeNumber string     `json:"employee_number,omitempty"`
+	TerminalID     string     `json:"terminal_id,omitempty"`
+}
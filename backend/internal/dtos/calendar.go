@@ -49,6 +49,7 @@ type CalendarEventRequest struct {
 	CollarTypes  []string `form:"collar_types[]"`                // white_collar, blue_collar, gray_collar
 	EventTypes   []string `form:"event_types[]"`                 // absence, incidence, shift_change
 	DepartmentID string   `form:"department_id"`                 // Filter by department UUID
+	ManagerID    string   `form:"manager_id"`                    // Filter by manager's team (employee UUID, whole subtree)
 	Status       string   `form:"status"`                        // pending, approved, declined, etc.
 }
 
@@ -78,6 +78,8 @@ type EmployeeRequest struct {
 	IsSindicalizado       bool       `json:"is_sindicalizado"`
 	DailySalary           float64    `json:"daily_salary" binding:"required,gt=0"`
 	IntegratedDailySalary float64    `json:"integrated_daily_salary"`
+	MinimumWageZone       string     `json:"minimum_wage_zone,omitempty" binding:"omitempty,oneof=general northern_border_free_zone"`
+	ProfessionalOccupation string    `json:"professional_occupation,omitempty"`
 	PaymentMethod         string     `json:"payment_method,omitempty" binding:"omitempty,oneof=bank_transfer cash check"`
 	BankName              string     `json:"bank_name,omitempty"`
 	BankAccount           string     `json:"bank_account,omitempty"`
@@ -129,6 +131,8 @@ type EmployeeResponse struct {
 	IsSindicalizado       bool       `json:"is_sindicalizado"`
 	DailySalary           float64    `json:"daily_salary"`
 	IntegratedDailySalary float64    `json:"integrated_daily_salary"`
+	MinimumWageZone       string     `json:"minimum_wage_zone"`
+	ProfessionalOccupation string    `json:"professional_occupation,omitempty"`
 	PaymentMethod         string     `json:"payment_method"`
 	BankName              string     `json:"bank_name,omitempty"`
 	BankAccount           string     `json:"bank_account,omitempty"`
@@ -177,6 +181,7 @@ type EmployeeTerminationRequest struct {
 type EmployeeSalaryUpdateRequest struct {
 	NewDailySalary float64 `json:"new_daily_salary" binding:"required,gt=0"`
 	EffectiveDate  Date    `json:"effective_date" binding:"required"`
+	Reason         string  `json:"reason,omitempty"`
 }
 
 // =========================================================================
@@ -0,0 +1,74 @@
+/*
+Package dtos - Leave Policy Data Transfer Objects
+
+==============================================================================
+FILE: internal/dtos/leave_policy.go
+==============================================================================
+
+DESCRIPTION:
+    Request and response structures for the leave policy catalog
+    (maternity, paternity, bereavement, marriage, union leave, ...) and the
+    leave balances of an employee.
+
+USER PERSPECTIVE:
+    - HR defines who may take a leave, how many days and who pays them
+    - Employees see the leaves they can request and the days left
+
+DEVELOPER GUIDELINES:
+    ✅  OK to modify: Add balance details
+    ⚠️  CAUTION: LeavePolicyRequest replaces every field
+    📝  Balances are per calendar year of the date asked about
+
+==============================================================================
+*/
+package dtos
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// LeavePolicyRequest creates or updates a leave policy
+type LeavePolicyRequest struct {
+	Code              string   `json:"code" binding:"required"`
+	Name              string   `json:"name" binding:"required"`
+	Description       string   `json:"description,omitempty"`
+	LegalReference    string   `json:"legal_reference,omitempty"`
+	EntitlementDays   float64  `json:"entitlement_days" binding:"required,gt=0"`
+	EntitlementBasis  string   `json:"entitlement_basis" binding:"required,oneof=per_event per_year"`
+	MaxEventsPerYear  int      `json:"max_events_per_year" binding:"gte=0"`
+	CountsWorkDays    bool     `json:"counts_work_days"`
+	Genders           []string `json:"genders,omitempty" binding:"omitempty,dive,oneof=male female other"`
+	MinServiceMonths  int      `json:"min_service_months" binding:"gte=0"`
+	UnionStatus       string   `json:"union_status,omitempty" binding:"omitempty,oneof=any union non_union"`
+	RequiredDocuments []string `json:"required_documents,omitempty"`
+	PaidBy            string   `json:"paid_by" binding:"required,oneof=company imss unpaid"`
+	IsActive          *bool    `json:"is_active,omitempty"`
+}
+
+// LeaveBalance is what an employee has of one leave in a calendar year
+type LeaveBalance struct {
+	PolicyID          uuid.UUID `json:"policy_id"`
+	Code              string    `json:"code"`
+	Name              string    `json:"name"`
+	EntitlementBasis  string    `json:"entitlement_basis"`
+	EntitlementDays   float64   `json:"entitlement_days"`
+	MaxEventsPerYear  int       `json:"max_events_per_year"`
+	PaidBy            string    `json:"paid_by"`
+	RequiredDocuments []string  `json:"required_documents"`
+	Eligible          bool      `json:"eligible"`
+	IneligibleReason  string    `json:"ineligible_reason,omitempty"`
+	Year              int       `json:"year"`
+	UsedDays          float64   `json:"used_days"`      // Approved requests
+	PendingDays       float64   `json:"pending_days"`   // Requests waiting for approval
+	Events            int       `json:"events"`         // Approved and pending requests
+	RemainingDays     float64   `json:"remaining_days"` // per_event: days of the next request
+}
+
+// LeaveBalances lists the leaves of an employee
+type LeaveBalances struct {
+	EmployeeID uuid.UUID      `json:"employee_id"`
+	AsOf       time.Time      `json:"as_of"`
+	Leaves     []LeaveBalance `json:"leaves"`
+}
@@ -0,0 +1,75 @@
+/*
+Package dtos - Minimum Wage Data Transfer Objects
+
+==============================================================================
+FILE: internal/dtos/minimum_wage.go
+==============================================================================
+
+DESCRIPTION:
+    Request and response structures for minimum wage zones and the annual
+    bulk raise that brings every employee up to the new minimum wage.
+
+USER PERSPECTIVE:
+    - HR checks the current general and frontier-zone (ZLFN) minimum wages
+    - Before January payroll, HR previews which employees fall below the new
+      minimum and applies the raise in one step
+
+DEVELOPER GUIDELINES:
+    ✅  OK to modify: Add fields to the raise preview
+    ⚠️  CAUTION: Zone values must match models.MinimumWageZone* constants
+    📝  Leave the new minimum values empty to use official_values.json
+
+==============================================================================
+*/
+package dtos
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// MinimumWageInfo shows the configured minimum wages
+type MinimumWageInfo struct {
+	DefaultZone            string             `json:"default_zone"`
+	General                float64            `json:"general"`
+	NorthernBorderFreeZone float64            `json:"northern_border_free_zone"`
+	Year                   int                `json:"year,omitempty"`
+	EffectiveDate          string             `json:"effective_date,omitempty"`
+	ProfessionalGeneral    map[string]float64 `json:"professional_general,omitempty"`
+	ProfessionalFrontier   map[string]float64 `json:"professional_frontier,omitempty"`
+}
+
+// MinimumWageBulkRaiseRequest raises every active employee below the new minimum wage
+type MinimumWageBulkRaiseRequest struct {
+	EffectiveDate          Date    `json:"effective_date" binding:"required"`
+	GeneralDailyValue      float64 `json:"general_daily_value,omitempty" binding:"omitempty,gt=0"`
+	NorthernBorderFreeZone float64 `json:"northern_border_free_zone_daily_value,omitempty" binding:"omitempty,gt=0"`
+	DryRun                 bool    `json:"dry_run"`
+}
+
+// MinimumWageRaiseItem is one employee affected by the bulk raise
+type MinimumWageRaiseItem struct {
+	EmployeeID             uuid.UUID  `json:"employee_id"`
+	EmployeeNumber         string     `json:"employee_number"`
+	FullName               string     `json:"full_name"`
+	MinimumWageZone        string     `json:"minimum_wage_zone"`
+	ProfessionalOccupation string     `json:"professional_occupation,omitempty"`
+	OldDailySalary         float64    `json:"old_daily_salary"`
+	NewDailySalary         float64    `json:"new_daily_salary"`
+	OldSDI                 float64    `json:"old_sdi"`
+	NewSDI                 float64    `json:"new_sdi"`
+	SalaryHistoryID        *uuid.UUID `json:"salary_history_id,omitempty"`
+	IMSSMovementID         *uuid.UUID `json:"imss_movement_id,omitempty"`
+}
+
+// MinimumWageBulkRaiseResponse summarizes the bulk raise
+type MinimumWageBulkRaiseResponse struct {
+	EffectiveDate          time.Time              `json:"effective_date"`
+	GeneralMinimum         float64                `json:"general_minimum"`
+	NorthernBorderFreeZone float64                `json:"northern_border_free_zone_minimum"`
+	EmployeesEvaluated     int                    `json:"employees_evaluated"`
+	EmployeesRaised        int                    `json:"employees_raised"`
+	Applied                bool                   `json:"applied"`
+	Raises                 []MinimumWageRaiseItem `json:"raises"`
+}
@@ -0,0 +1,59 @@
+/*
+Package dtos - Org Structure Data Transfer Objects
+
+==============================================================================
+FILE: internal/dtos/org_structure.go
+==============================================================================
+
+DESCRIPTION:
+    Request and response structures for reporting lines, the org chart and
+    approver resolution.
+
+USER PERSPECTIVE:
+    - HR assigns a manager from a date
+    - The org chart shows each person with direct reports and total headcount
+    - When a manager is on leave, the approver shown is the next manager up
+
+DEVELOPER GUIDELINES:
+    ✅  OK to modify: Add node attributes (photo, location)
+    📝  Headcount counts every active employee below the node, not the node
+
+==============================================================================
+*/
+package dtos
+
+import (
+	"github.com/google/uuid"
+)
+
+// ReportingLineRequest sets an employee's manager from a date
+type ReportingLineRequest struct {
+	EmployeeID    uuid.UUID  `json:"employee_id" binding:"required"`
+	ManagerID     *uuid.UUID `json:"manager_id,omitempty"` // nil makes the employee a root
+	EffectiveFrom Date       `json:"effective_from" binding:"required"`
+	Reason        string     `json:"reason,omitempty"`
+}
+
+// OrgChartNode is one employee in the org chart
+type OrgChartNode struct {
+	EmployeeID     uuid.UUID      `json:"employee_id"`
+	EmployeeNumber string         `json:"employee_number"`
+	FullName       string         `json:"full_name"`
+	PositionID     *uuid.UUID     `json:"position_id,omitempty"`
+	PositionTitle  string         `json:"position_title,omitempty"`
+	DepartmentID   *uuid.UUID     `json:"department_id,omitempty"`
+	DepartmentName string         `json:"department_name,omitempty"`
+	DirectReports  int            `json:"direct_reports"`
+	Headcount      int            `json:"headcount"`
+	Children       []OrgChartNode `json:"children,omitempty"`
+}
+
+// ApproverResolution is who approves for an employee on a given date
+type ApproverResolution struct {
+	EmployeeID         uuid.UUID   `json:"employee_id"`
+	DirectManagerID    *uuid.UUID  `json:"direct_manager_id,omitempty"`
+	ApproverEmployeeID *uuid.UUID  `json:"approver_employee_id,omitempty"`
+	ApproverUserID     *uuid.UUID  `json:"approver_user_id,omitempty"`
+	Delegated          bool        `json:"delegated"`
+	SkippedManagerIDs  []uuid.UUID `json:"skipped_manager_ids,omitempty"` // on leave or without a user account
+}
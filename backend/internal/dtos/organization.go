@@ -0,0 +1,142 @@
+/*
+Package dtos - Organization Data Transfer Objects
+
+==============================================================================
+FILE: internal/dtos/organization.go
+==============================================================================
+
+DESCRIPTION:
+    Request and response structures for the organizational catalog
+    (departments, salary grades, positions), employee assignments and the
+    compa-ratio / range penetration report.
+
+USER PERSPECTIVE:
+    - HR edits departments, grades and positions from the catalog screens
+    - The pay-equity report groups employees by department with their
+      position in the band
+
+DEVELOPER GUIDELINES:
+    ✅  OK to modify: Add report columns
+    ⚠️  CAUTION: Band values are daily salaries
+    📝  BandPolicy: "warn" or "block"
+
+==============================================================================
+*/
+package dtos
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// DepartmentRequest creates or updates a department
+type DepartmentRequest struct {
+	Code         string     `json:"code" binding:"required,max=30"`
+	Name         string     `json:"name" binding:"required"`
+	Description  string     `json:"description,omitempty"`
+	ParentID     *uuid.UUID `json:"parent_id,omitempty"`
+	CostCenterID *uuid.UUID `json:"cost_center_id,omitempty"`
+	ManagerID    *uuid.UUID `json:"manager_id,omitempty"`
+	IsActive     *bool      `json:"is_active,omitempty"`
+}
+
+// DepartmentNode is a department with its sub-departments
+type DepartmentNode struct {
+	ID            uuid.UUID        `json:"id"`
+	Code          string           `json:"code"`
+	Name          string           `json:"name"`
+	Description   string           `json:"description,omitempty"`
+	ParentID      *uuid.UUID       `json:"parent_id,omitempty"`
+	CostCenterID  *uuid.UUID       `json:"cost_center_id,omitempty"`
+	ManagerID     *uuid.UUID       `json:"manager_id,omitempty"`
+	IsActive      bool             `json:"is_active"`
+	EmployeeCount int              `json:"employee_count"`
+	Children      []DepartmentNode `json:"children,omitempty"`
+}
+
+// SalaryGradeRequest creates or updates a salary grade
+type SalaryGradeRequest struct {
+	Code           string  `json:"code" binding:"required,max=20"`
+	Name           string  `json:"name,omitempty"`
+	Level          int     `json:"level"`
+	MinDailySalary float64 `json:"min_daily_salary" binding:"required,gt=0"`
+	MidDailySalary float64 `json:"mid_daily_salary,omitempty" binding:"omitempty,gt=0"`
+	MaxDailySalary float64 `json:"max_daily_salary" binding:"required,gt=0"`
+	BandPolicy     string  `json:"band_policy,omitempty" binding:"omitempty,oneof=warn block"`
+	IsActive       *bool   `json:"is_active,omitempty"`
+}
+
+// PositionRequest creates or updates a position
+type PositionRequest struct {
+	JobCode       string     `json:"job_code" binding:"required,max=30"`
+	Title         string     `json:"title" binding:"required"`
+	Description   string     `json:"description,omitempty"`
+	DepartmentID  *uuid.UUID `json:"department_id,omitempty"`
+	SalaryGradeID *uuid.UUID `json:"salary_grade_id,omitempty"`
+	CollarType    string     `json:"collar_type,omitempty" binding:"omitempty,oneof=white_collar blue_collar gray_collar"`
+	Headcount     int        `json:"headcount" binding:"gte=0"`
+	IsActive      *bool      `json:"is_active,omitempty"`
+}
+
+// EmployeeAssignmentRequest moves an employee to a department/position
+type EmployeeAssignmentRequest struct {
+	EmployeeID    uuid.UUID  `json:"employee_id" binding:"required"`
+	DepartmentID  *uuid.UUID `json:"department_id,omitempty"`
+	PositionID    *uuid.UUID `json:"position_id,omitempty"`
+	EffectiveFrom Date       `json:"effective_from" binding:"required"`
+	Reason        string     `json:"reason,omitempty"`
+}
+
+// PayBandCheck is the result of comparing a daily salary with the position's band
+type PayBandCheck struct {
+	PositionID       *uuid.UUID `json:"position_id,omitempty"`
+	GradeCode        string     `json:"grade_code,omitempty"`
+	MinDailySalary   float64    `json:"min_daily_salary"`
+	MidDailySalary   float64    `json:"mid_daily_salary"`
+	MaxDailySalary   float64    `json:"max_daily_salary"`
+	DailySalary      float64    `json:"daily_salary"`
+	CompaRatio       float64    `json:"compa_ratio"`
+	RangePenetration float64    `json:"range_penetration"`
+	WithinBand       bool       `json:"within_band"`
+	Policy           string     `json:"policy"`
+	Warning          string     `json:"warning,omitempty"`
+}
+
+// CompaRatioEmployee is one employee line of the compa-ratio report
+type CompaRatioEmployee struct {
+	EmployeeID       uuid.UUID `json:"employee_id"`
+	EmployeeNumber   string    `json:"employee_number"`
+	FullName         string    `json:"full_name"`
+	JobCode          string    `json:"job_code"`
+	PositionTitle    string    `json:"position_title"`
+	GradeCode        string    `json:"grade_code"`
+	Gender           string    `json:"gender,omitempty"`
+	DailySalary      float64   `json:"daily_salary"`
+	CompaRatio       float64   `json:"compa_ratio"`
+	RangePenetration float64   `json:"range_penetration"`
+	BandStatus       string    `json:"band_status"` // below, within, above
+}
+
+// CompaRatioDepartment aggregates the report for one department
+type CompaRatioDepartment struct {
+	DepartmentID            *uuid.UUID           `json:"department_id,omitempty"`
+	DepartmentName          string               `json:"department_name"`
+	EmployeeCount           int                  `json:"employee_count"`
+	BelowBand               int                  `json:"below_band"`
+	WithinBand              int                  `json:"within_band"`
+	AboveBand               int                  `json:"above_band"`
+	AverageCompaRatio       float64              `json:"average_compa_ratio"`
+	AverageRangePenetration float64              `json:"average_range_penetration"`
+	Employees               []CompaRatioEmployee `json:"employees,omitempty"`
+}
+
+// CompaRatioReport is the pay-equity report by department
+type CompaRatioReport struct {
+	GeneratedAt             time.Time              `json:"generated_at"`
+	EmployeeCount           int                    `json:"employee_count"`
+	UngradedCount           int                    `json:"ungraded_count"` // active employees without a graded position
+	AverageCompaRatio       float64                `json:"average_compa_ratio"`
+	AverageRangePenetration float64                `json:"average_range_penetration"`
+	Departments             []CompaRatioDepartment `json:"departments"`
+}
@@ -0,0 +1,101 @@
+/*
+Package dtos - Overtime Request Data Transfer Objects
+
+==============================================================================
+FILE: internal/dtos/overtime.go
+==============================================================================
+
+DESCRIPTION:
+    Request and response structures for overtime pre-authorization: the
+    request an employee or supervisor files, approval actions and the
+    reconciliation of authorized against worked overtime at period close.
+
+USER PERSPECTIVE:
+    - A supervisor requests overtime for a team member before it is worked
+    - Approvers approve or decline it at their stage
+    - Payroll reconciles the period and reviews unauthorized overtime
+    - Overtime can be requested for the time-for-time bank instead of pay
+
+DEVELOPER GUIDELINES:
+    ✅  OK to modify: Add reconciliation columns
+    ⚠️  CAUTION: StartTime/EndTime are "HH:MM" in the company's clock; an
+        end at or before the start ends the next day
+    📝  Hours are decimal hours
+
+==============================================================================
+*/
+package dtos
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+
+	"backend/internal/models"
+)
+
+// OvertimeRequestInput files an overtime request
+type OvertimeRequestInput struct {
+	EmployeeID *uuid.UUID `json:"employee_id,omitempty"` // Omitted: the requester's own employee record
+	Date       Date       `json:"date" binding:"required"`
+	StartTime  string     `json:"start_time" binding:"required"` // HH:MM
+	EndTime    string     `json:"end_time" binding:"required"`   // HH:MM
+	Reason     string     `json:"reason" binding:"required"`
+	ProjectID  *uuid.UUID `json:"project_id,omitempty"`
+	// pay (default) or bank: deposit the hours in the time-for-time bank instead of paying them
+	Compensation string `json:"compensation,omitempty" binding:"omitempty,oneof=pay bank"`
+}
+
+// OvertimeActionRequest approves or declines an overtime request at its current stage
+type OvertimeActionRequest struct {
+	Action   models.ApprovalAction `json:"action" binding:"required,oneof=APPROVED DECLINED"`
+	Comments string                `json:"comments,omitempty"`
+}
+
+// OvertimeRequestFilter filters the overtime request list
+type OvertimeRequestFilter struct {
+	EmployeeID *uuid.UUID
+	Status     string
+	StartDate  *time.Time
+	EndDate    *time.Time
+}
+
+// OvertimeReconcileRequest selects the period to reconcile
+type OvertimeReconcileRequest struct {
+	PayrollPeriodID *uuid.UUID `json:"payroll_period_id,omitempty"`
+	StartDate       *Date      `json:"start_date,omitempty"`
+	EndDate         *Date      `json:"end_date,omitempty"`
+}
+
+// OvertimeReconciliationLine compares one authorized request with the hours worked
+type OvertimeReconciliationLine struct {
+	RequestID       uuid.UUID `json:"request_id"`
+	EmployeeID      uuid.UUID `json:"employee_id"`
+	EmployeeNumber  string    `json:"employee_number"`
+	EmployeeName    string    `json:"employee_name"`
+	Date            time.Time `json:"date"`
+	AuthorizedHours float64   `json:"authorized_hours"`
+	ActualHours     float64   `json:"actual_hours"` // Overtime on the day's closed clock records
+	ExcessHours     float64   `json:"excess_hours"` // Worked beyond the authorization
+	UnusedHours     float64   `json:"unused_hours"` // Authorized but not worked
+	BankedHours     float64   `json:"banked_hours"` // Deposited in the time bank (compensation "bank")
+}
+
+// UnauthorizedOvertimeDay is overtime worked without an approved request
+type UnauthorizedOvertimeDay struct {
+	AttendanceDayID uuid.UUID `json:"attendance_day_id"`
+	EmployeeID      uuid.UUID `json:"employee_id"`
+	EmployeeNumber  string    `json:"employee_number"`
+	EmployeeName    string    `json:"employee_name"`
+	Date            time.Time `json:"date"`
+	Hours           float64   `json:"hours"`
+}
+
+// OvertimeReconciliation is the period-close report of overtime
+type OvertimeReconciliation struct {
+	StartDate    time.Time                    `json:"start_date"`
+	EndDate      time.Time                    `json:"end_date"`
+	Reconciled   int                          `json:"reconciled"`
+	Lines        []OvertimeReconciliationLine `json:"lines"`
+	Unauthorized []UnauthorizedOvertimeDay    `json:"unauthorized"` // From the attendance evaluation, pending HR review
+}
@@ -96,6 +96,10 @@ type PayrollCalculationResponse struct {
 	TotalDeductions       float64                `json:"total_deductions"`
 	TotalNetPay           float64                `json:"total_net_pay"`
 
+	// Minimum wage protection
+	MinimumWageEarner     bool                   `json:"minimum_wage_earner"`
+	DeferredDeductions    float64                `json:"deferred_deductions,omitempty"`
+
 	// Employer contributions
 	EmployerContributions EmployerContributionResponse `json:"employer_contributions"`
 
@@ -0,0 +1,102 @@
+/*
+Package dtos - Payroll Calendar Data Transfer Objects
+
+==============================================================================
+FILE: internal/dtos/payroll_calendar.go
+==============================================================================
+
+DESCRIPTION:
+    Request and response structures for the annual payroll calendar generator,
+    the merged holiday catalog (federal + state + company holidays) and the
+    work calendar of an employee (the days that count for absences).
+
+USER PERSPECTIVE:
+    - Payroll staff generate every period of a fiscal year in one step
+    - Each generated period shows its prenómina cut-off and payment date
+    - Payment dates that were moved because of a weekend/holiday are flagged
+
+DEVELOPER GUIDELINES:
+    ✅  OK to modify: Add new fields to the calendar preview
+    ⚠️  CAUTION: Changing field names (breaks frontend)
+    📝  Holiday sources: federal (LFT Art. 74), state (regional config), company
+
+==============================================================================
+*/
+package dtos
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// HolidaySource identifies where a holiday in the catalog comes from
+type HolidaySource string
+
+const (
+	HolidaySourceFederal HolidaySource = "federal"
+	HolidaySourceState   HolidaySource = "state"
+	HolidaySourceCompany HolidaySource = "company"
+)
+
+// HolidayCatalogEntry is one non-working day in the merged holiday catalog
+type HolidayCatalogEntry struct {
+	Date   time.Time     `json:"date"`
+	Name   string        `json:"name"`
+	Source HolidaySource `json:"source"`
+	IsPaid bool          `json:"is_paid"`
+}
+
+// GeneratePayrollCalendarRequest requests all periods of a fiscal year for a pay group
+type GeneratePayrollCalendarRequest struct {
+	Year               int    `json:"year" binding:"required,gte=2020,lte=2100"`
+	Frequency          string `json:"frequency" binding:"required,oneof=weekly biweekly monthly"`
+	PaymentWeekday     *int   `json:"payment_weekday"`      // Weekly only: 0=Sunday ... 6=Saturday (default Friday)
+	CutoffBusinessDays int    `json:"cutoff_business_days"` // Business days between prenómina cut-off and payment (default 2)
+	DryRun             bool   `json:"dry_run"`              // Preview without saving
+}
+
+// PayrollCalendarPeriod is one period of a generated calendar
+type PayrollCalendarPeriod struct {
+	PeriodID            *uuid.UUID `json:"period_id,omitempty"`
+	PeriodCode          string     `json:"period_code"`
+	PeriodNumber        int        `json:"period_number"`
+	StartDate           time.Time  `json:"start_date"`
+	EndDate             time.Time  `json:"end_date"`
+	NominalPaymentDate  time.Time  `json:"nominal_payment_date"`
+	PaymentDate         time.Time  `json:"payment_date"`
+	PrenominaCutoffDate time.Time  `json:"prenomina_cutoff_date"`
+	PaymentMoved        bool       `json:"payment_moved"`
+	MoveReason          string     `json:"move_reason,omitempty"`
+	Status              string     `json:"status"` // created, existing, overlap, preview
+}
+
+// PayrollCalendarResponse is the result of generating a fiscal year calendar
+type PayrollCalendarResponse struct {
+	Year         int                     `json:"year"`
+	Frequency    string                  `json:"frequency"`
+	TotalPeriods int                     `json:"total_periods"`
+	Created      int                     `json:"created"`
+	Skipped      int                     `json:"skipped"`
+	Periods      []PayrollCalendarPeriod `json:"periods"`
+	Holidays     []HolidayCatalogEntry   `json:"holidays"`
+}
+
+// WorkCalendarDay is one date of an employee's work calendar
+type WorkCalendarDay struct {
+	Date           time.Time     `json:"date"`
+	IsWorkDay      bool          `json:"is_work_day"`
+	IsRestDay      bool          `json:"is_rest_day"`       // Rest day of the employee's shift
+	Holiday        string        `json:"holiday,omitempty"` // Holiday name when the date is a holiday
+	HolidaySource  HolidaySource `json:"holiday_source,omitempty"`
+	ScheduleSource string        `json:"schedule_source,omitempty"` // exception, rotation, weekly, default or none
+}
+
+// WorkCalendar lists the days an employee works between two dates
+type WorkCalendar struct {
+	EmployeeID uuid.UUID         `json:"employee_id"`
+	StartDate  time.Time         `json:"start_date"`
+	EndDate    time.Time         `json:"end_date"`
+	WorkDays   float64           `json:"work_days"`
+	Days       []WorkCalendarDay `json:"days"`
+}
@@ -57,24 +57,33 @@ type PrenominaMetricResponse struct {
 	OvertimeHours         float64    `json:"overtime_hours"`
 	DoubleOvertimeHours   float64    `json:"double_overtime_hours"`
 	TripleOvertimeHours   float64    `json:"triple_overtime_hours"`
+	UnauthorizedOvertimeHours float64 `json:"unauthorized_overtime_hours"`
+	PaidPermitHours       float64    `json:"paid_permit_hours"`
 
 	// Leave metrics
 	AbsenceDays           float64    `json:"absence_days"`
 	SickDays              float64    `json:"sick_days"`
 	VacationDays          float64    `json:"vacation_days"`
 	UnpaidLeaveDays       float64    `json:"unpaid_leave_days"`
+	CompanyPaidSickDays   float64    `json:"company_paid_sick_days"`
+	SubsidizedSickDays    float64    `json:"subsidized_sick_days"`
+	ContributionDays      float64    `json:"contribution_days"`
 
 	// Other metrics
 	DelaysCount           int        `json:"delays_count"`
 	DelayMinutes          float64    `json:"delay_minutes"`
 	DelayDeduction        float64    `json:"delay_deduction"`
 	EarlyDeparturesCount  int        `json:"early_departures_count"`
+	SundaysWorked         int        `json:"sundays_worked"`
+	RestDaysWorked        int        `json:"rest_days_worked"`
 
 	// Monetary amounts
 	RegularSalary         float64    `json:"regular_salary"`
 	OvertimeAmount        float64    `json:"overtime_amount"`
 	DoubleOvertimeAmount  float64    `json:"double_overtime_amount"`
 	TripleOvertimeAmount  float64    `json:"triple_overtime_amount"`
+	SundayPremiumAmount   float64    `json:"sunday_premium_amount"`
+	RestDayWorkedAmount   float64    `json:"rest_day_worked_amount"`
 	BonusAmount           float64    `json:"bonus_amount"`
 	CommissionAmount      float64    `json:"commission_amount"`
 	OtherExtraAmount      float64    `json:"other_extra_amount"`
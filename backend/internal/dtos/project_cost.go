@@ -0,0 +1,96 @@
+/*
+Package dtos - Project Cost Data Transfer Objects
+
+==============================================================================
+FILE: internal/dtos/project_cost.go
+==============================================================================
+
+DESCRIPTION:
+    Request and response structures for project budget tracking (budget vs.
+    actual per project and task) and for the monthly allocation of payroll
+    cost to the projects and cost centers employees logged time to.
+
+USER PERSPECTIVE:
+    - Project managers follow the burn of each task against its budget
+    - Finance allocates the month's payroll cost and posts the póliza
+
+DEVELOPER GUIDELINES:
+    ✅  OK to modify: Add report columns
+    📝  Percentages are 0-100 (and above, once over budget)
+
+==============================================================================
+*/
+package dtos
+
+import (
+	"github.com/google/uuid"
+)
+
+// TaskBudgetRequest sets the budget of a project task
+type TaskBudgetRequest struct {
+	BudgetHours  float64 `json:"budget_hours" binding:"gte=0"`
+	BudgetAmount float64 `json:"budget_amount" binding:"gte=0"`
+}
+
+// BudgetLine is the budget vs. actual of a project or one of its tasks
+type BudgetLine struct {
+	TaskID       *uuid.UUID `json:"task_id,omitempty"`
+	Code         string     `json:"code"`
+	Name         string     `json:"name"`
+	BudgetHours  float64    `json:"budget_hours"`
+	BudgetAmount float64    `json:"budget_amount"`
+	ActualHours  float64    `json:"actual_hours"`
+	ActualCost   float64    `json:"actual_cost"`
+	HoursUsed    float64    `json:"hours_used"`  // Percent of BudgetHours
+	AmountUsed   float64    `json:"amount_used"` // Percent of BudgetAmount
+	Status       string     `json:"status"`      // on_track, warning, exceeded, no_budget
+}
+
+// ProjectBudgetReport is a project's budget vs. actual, in total and per task
+type ProjectBudgetReport struct {
+	ProjectID  uuid.UUID    `json:"project_id"`
+	Basis      string       `json:"basis"` // rate or payroll
+	Project    BudgetLine   `json:"project"`
+	Tasks      []BudgetLine `json:"tasks"`
+	Unassigned BudgetLine   `json:"unassigned"` // Hours logged without a task
+}
+
+// BudgetAlertResult summarizes a budget alert check
+type BudgetAlertResult struct {
+	Projects int `json:"projects"`
+	Raised   int `json:"raised"`
+}
+
+// CostAllocationLine is the share of one employee's payroll cost charged to a project / cost center
+type CostAllocationLine struct {
+	EmployeeID     uuid.UUID  `json:"employee_id"`
+	EmployeeNumber string     `json:"employee_number"`
+	EmployeeName   string     `json:"employee_name"`
+	ProjectID      *uuid.UUID `json:"project_id,omitempty"`
+	ProjectCode    string     `json:"project_code,omitempty"`
+	CostCenterID   *uuid.UUID `json:"cost_center_id,omitempty"`
+	CostCenterName string     `json:"cost_center_name,omitempty"`
+	Hours          float64    `json:"hours"`
+	Share          float64    `json:"share"` // Percent of the employee's logged hours
+	Amount         float64    `json:"amount"`
+}
+
+// CostCenterAllocation totals the allocation of one cost center and project
+type CostCenterAllocation struct {
+	CostCenterID   *uuid.UUID `json:"cost_center_id,omitempty"`
+	CostCenterName string     `json:"cost_center_name"`
+	ProjectID      *uuid.UUID `json:"project_id,omitempty"`
+	ProjectCode    string     `json:"project_code,omitempty"`
+	Hours          float64    `json:"hours"`
+	Amount         float64    `json:"amount"`
+}
+
+// CostAllocationReport is a month's payroll cost spread across projects and cost centers
+type CostAllocationReport struct {
+	Year         int                    `json:"year"`
+	Month        int                    `json:"month"`
+	PayrollCost  float64                `json:"payroll_cost"`
+	Allocated    float64                `json:"allocated"`
+	Lines        []CostAllocationLine   `json:"lines"`
+	ByCostCenter []CostCenterAllocation `json:"by_cost_center"`
+}
@@ -0,0 +1,193 @@
+/*
+Package dtos - Roster Data Transfer Objects
+
+==============================================================================
+FILE: internal/dtos/roster.go
+==============================================================================
+
+DESCRIPTION:
+    Request and response structures for rotating shift schedules: rotation
+    patterns and their assignment to teams, minimum-staffing rules and
+    leave blackouts, shift swaps, the generated day-by-day roster with its
+    coverage gaps, and the coverage check of an absence request.
+
+USER PERSPECTIVE:
+    - HR defines a rotation as the list of shifts of each cycle day
+    - Planners generate the roster for any date range and see the gaps
+    - Employees and supervisors request a swap for a day
+    - Employees offer a shift on the marketplace; colleagues take it
+    - Employees and approvers see which coverage rules or blackouts an
+      absence request breaks before it is filed or approved
+
+DEVELOPER GUIDELINES:
+    ✅  OK to modify: Add roster columns
+    ⚠️  CAUTION: RotationPatternRequest.Days is the whole cycle in order;
+        its length is the cycle length
+    📝  DayOfWeek is 0=Monday ... 6=Sunday
+
+==============================================================================
+*/
+package dtos
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// RotationDayInput is one day of a rotation cycle; no shift means a rest day
+type RotationDayInput struct {
+	ShiftID *uuid.UUID `json:"shift_id,omitempty"`
+}
+
+// RotationPatternRequest creates or updates a rotation pattern
+type RotationPatternRequest struct {
+	Name        string             `json:"name" binding:"required"`
+	Code        string             `json:"code" binding:"required"`
+	Description string             `json:"description,omitempty"`
+	AnchorDate  Date               `json:"anchor_date" binding:"required"`
+	Days        []RotationDayInput `json:"days" binding:"required,min=1,max=56"`
+	IsActive    *bool              `json:"is_active,omitempty"`
+}
+
+// RotationAssignmentRequest puts a team or an employee on a rotation
+type RotationAssignmentRequest struct {
+	PatternID     uuid.UUID  `json:"pattern_id" binding:"required"`
+	TeamName      string     `json:"team_name,omitempty"`
+	EmployeeID    *uuid.UUID `json:"employee_id,omitempty"`
+	OffsetDays    int        `json:"offset_days" binding:"gte=0"`
+	EffectiveFrom Date       `json:"effective_from" binding:"required"`
+	EffectiveTo   *Date      `json:"effective_to,omitempty"`
+}
+
+// StaffingRuleRequest creates or updates a minimum-staffing rule; it needs
+// an area or a department, and a minimum headcount or a maximum percentage
+type StaffingRuleRequest struct {
+	ProductionArea   string     `json:"production_area,omitempty"`
+	DepartmentID     *uuid.UUID `json:"department_id,omitempty"`
+	ShiftID          *uuid.UUID `json:"shift_id,omitempty"`
+	DayOfWeek        *int       `json:"day_of_week,omitempty" binding:"omitempty,gte=0,lte=6"`
+	MinEmployees     int        `json:"min_employees" binding:"gte=0"`
+	MaxAbsentPercent float64    `json:"max_absent_percent" binding:"gte=0,lte=100"`
+	Enforcement      string     `json:"enforcement,omitempty" binding:"omitempty,oneof=warn block"` // Default warn
+	IsActive         *bool      `json:"is_active,omitempty"`
+}
+
+// BlackoutPeriodRequest creates or updates a leave blackout
+type BlackoutPeriodRequest struct {
+	Name           string     `json:"name" binding:"required"`
+	StartDate      Date       `json:"start_date" binding:"required"`
+	EndDate        Date       `json:"end_date" binding:"required"`
+	ProductionArea string     `json:"production_area,omitempty"`
+	DepartmentID   *uuid.UUID `json:"department_id,omitempty"`
+	RequestTypes   []string   `json:"request_types,omitempty"`                                    // Empty = every leave type but sick leave
+	Enforcement    string     `json:"enforcement,omitempty" binding:"omitempty,oneof=warn block"` // Default block
+	Reason         string     `json:"reason,omitempty"`
+	IsActive       *bool      `json:"is_active,omitempty"`
+}
+
+// RosterSwapRequest asks for a shift change on one day
+type RosterSwapRequest struct {
+	EmployeeID    uuid.UUID  `json:"employee_id" binding:"required"`
+	Date          Date       `json:"date" binding:"required"`
+	CounterpartID *uuid.UUID `json:"counterpart_id,omitempty"` // Trade the day with a colleague
+	NewShiftID    *uuid.UUID `json:"new_shift_id,omitempty"`   // Or move to another shift
+	Reason        string     `json:"reason" binding:"required"`
+}
+
+// ShiftOfferRequest offers the requester's shift of a day on the swap marketplace
+type ShiftOfferRequest struct {
+	Date   Date   `json:"date" binding:"required"`
+	Reason string `json:"reason,omitempty"`
+}
+
+// RosterSwapReviewRequest approves or rejects a swap
+type RosterSwapReviewRequest struct {
+	Action string `json:"action" binding:"required,oneof=approve reject"`
+	Notes  string `json:"notes,omitempty"`
+}
+
+// RosterFilter selects the roster to generate
+type RosterFilter struct {
+	StartDate      time.Time
+	EndDate        time.Time
+	TeamName       string
+	ProductionArea string
+	EmployeeID     *uuid.UUID
+}
+
+// RosterEntry is one employee's schedule on one day
+type RosterEntry struct {
+	EmployeeID     uuid.UUID  `json:"employee_id"`
+	EmployeeNumber string     `json:"employee_number"`
+	EmployeeName   string     `json:"employee_name"`
+	TeamName       string     `json:"team_name,omitempty"`
+	ProductionArea string     `json:"production_area,omitempty"`
+	DepartmentID   *uuid.UUID `json:"department_id,omitempty"`
+	ShiftID        *uuid.UUID `json:"shift_id,omitempty"`
+	ShiftCode      string     `json:"shift_code,omitempty"`
+	ShiftName      string     `json:"shift_name,omitempty"`
+	StartTime      string     `json:"start_time,omitempty"`
+	EndTime        string     `json:"end_time,omitempty"`
+	IsRestDay      bool       `json:"is_rest_day"`
+	Leave          string     `json:"leave,omitempty"` // Approved absence covering the day
+	Source         string     `json:"source"`          // exception, rotation, weekly, default, none
+}
+
+// RosterDay is the roster of one date
+type RosterDay struct {
+	Date    time.Time     `json:"date"`
+	Entries []RosterEntry `json:"entries"`
+}
+
+// CoverageGap is a day where a production area is under its minimum staffing
+type CoverageGap struct {
+	Date           time.Time  `json:"date"`
+	ProductionArea string     `json:"production_area"`
+	DepartmentID   *uuid.UUID `json:"department_id,omitempty"`
+	ShiftID        *uuid.UUID `json:"shift_id,omitempty"`
+	ShiftCode      string     `json:"shift_code,omitempty"`
+	Required       int        `json:"required"`
+	Scheduled      int        `json:"scheduled"` // Working that day, leave excluded
+	Missing        int        `json:"missing"`
+}
+
+// Roster is the generated schedule of a date range
+type Roster struct {
+	StartDate time.Time     `json:"start_date"`
+	EndDate   time.Time     `json:"end_date"`
+	Days      []RosterDay   `json:"days"`
+	Gaps      []CoverageGap `json:"gaps"`
+}
+
+// Coverage issue kinds
+const (
+	CoverageIssueStaffing = "staffing"
+	CoverageIssueBlackout = "blackout"
+)
+
+// CoverageIssue is a staffing rule or blackout an absence request breaks
+type CoverageIssue struct {
+	Kind             string     `json:"kind"`        // staffing, blackout
+	Enforcement      string     `json:"enforcement"` // warn, block
+	Date             time.Time  `json:"date"`        // First affected day
+	Days             int        `json:"days"`        // Affected days of the request
+	StaffingRuleID   *uuid.UUID `json:"staffing_rule_id,omitempty"`
+	BlackoutID       *uuid.UUID `json:"blackout_id,omitempty"`
+	Name             string     `json:"name,omitempty"` // Blackout name
+	ProductionArea   string     `json:"production_area,omitempty"`
+	DepartmentID     *uuid.UUID `json:"department_id,omitempty"`
+	ShiftCode        string     `json:"shift_code,omitempty"`
+	Required         int        `json:"required,omitempty"`
+	Available        int        `json:"available,omitempty"` // Still working on Date if the request is approved
+	AbsentPercent    float64    `json:"absent_percent,omitempty"`
+	MaxAbsentPercent float64    `json:"max_absent_percent,omitempty"`
+	Message          string     `json:"message"`
+}
+
+// CoverageCheck is the result of checking an absence request against the
+// staffing rules and blackouts of the employee's area
+type CoverageCheck struct {
+	Blocked bool            `json:"blocked"`
+	Issues  []CoverageIssue `json:"issues"`
+}
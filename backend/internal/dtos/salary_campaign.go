@@ -0,0 +1,91 @@
+/*
+Package dtos - Salary Campaign Data Transfer Objects
+
+==============================================================================
+FILE: internal/dtos/salary_campaign.go
+==============================================================================
+
+DESCRIPTION:
+    Request and response structures for salary review campaigns (mass salary
+    increases with approval and retroactive pay).
+
+USER PERSPECTIVE:
+    - HR fills the campaign form: population filters, adjustment, date
+    - The response lists every affected employee with old/new salary and
+      the retroactive amount that will be paid
+
+DEVELOPER GUIDELINES:
+    ✅  OK to modify: Add filters
+    ⚠️  CAUTION: AdjustmentValue is a percentage for "percentage" and MXN per
+        day for "fixed_amount"
+    📝  GradeMatrix keys: position ID, grade code, collar type,
+        or "default"
+
+==============================================================================
+*/
+package dtos
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// SalaryCampaignRequest creates or updates a salary campaign
+type SalaryCampaignRequest struct {
+	Name            string             `json:"name" binding:"required"`
+	Description     string             `json:"description,omitempty"`
+	CampaignType    string             `json:"campaign_type,omitempty" binding:"omitempty,oneof=annual_increase union_revision merit other"`
+	AdjustmentType  string             `json:"adjustment_type" binding:"required,oneof=percentage fixed_amount grade_matrix"`
+	AdjustmentValue float64            `json:"adjustment_value"`
+	GradeMatrix     map[string]float64 `json:"grade_matrix,omitempty"`
+	EffectiveDate   Date               `json:"effective_date" binding:"required"`
+	DepartmentIDs   []uuid.UUID        `json:"department_ids,omitempty"`
+	PositionIDs     []uuid.UUID        `json:"position_ids,omitempty"`
+	CollarTypes     []string           `json:"collar_types,omitempty"`
+	IsSindicalizado *bool              `json:"is_sindicalizado,omitempty"`
+}
+
+// SalaryCampaignRejectRequest rejects a campaign
+type SalaryCampaignRejectRequest struct {
+	Reason string `json:"reason" binding:"required"`
+}
+
+// SalaryCampaignItemResponse is one affected employee
+type SalaryCampaignItemResponse struct {
+	ID                 uuid.UUID  `json:"id"`
+	EmployeeID         uuid.UUID  `json:"employee_id"`
+	EmployeeNumber     string     `json:"employee_number"`
+	EmployeeName       string     `json:"employee_name"`
+	CollarType         string     `json:"collar_type"`
+	OldDailySalary     float64    `json:"old_daily_salary"`
+	NewDailySalary     float64    `json:"new_daily_salary"`
+	IncreasePercent    float64    `json:"increase_percent"`
+	RetroactiveAmount  float64    `json:"retroactive_amount"`
+	RetroactivePeriods int        `json:"retroactive_periods"`
+	RetroIncidenceID   *uuid.UUID `json:"retro_incidence_id,omitempty"`
+	Status             string     `json:"status"`
+	SkipReason         string     `json:"skip_reason,omitempty"`
+}
+
+// SalaryCampaignResponse is a campaign with its items
+type SalaryCampaignResponse struct {
+	ID                     uuid.UUID                    `json:"id"`
+	Name                   string                       `json:"name"`
+	Description            string                       `json:"description,omitempty"`
+	CampaignType           string                       `json:"campaign_type"`
+	AdjustmentType         string                       `json:"adjustment_type"`
+	AdjustmentValue        float64                      `json:"adjustment_value"`
+	GradeMatrix            map[string]float64           `json:"grade_matrix,omitempty"`
+	EffectiveDate          time.Time                    `json:"effective_date"`
+	Status                 string                       `json:"status"`
+	EmployeeCount          int                          `json:"employee_count"`
+	TotalDailyIncrease     float64                      `json:"total_daily_increase"`
+	TotalRetroactiveAmount float64                      `json:"total_retroactive_amount"`
+	RejectionReason        string                       `json:"rejection_reason,omitempty"`
+	SubmittedAt            *time.Time                   `json:"submitted_at,omitempty"`
+	ApprovedAt             *time.Time                   `json:"approved_at,omitempty"`
+	AppliedAt              *time.Time                   `json:"applied_at,omitempty"`
+	CreatedAt              time.Time                    `json:"created_at"`
+	Items                  []SalaryCampaignItemResponse `json:"items,omitempty"`
+}
@@ -0,0 +1,44 @@
+/*
+Package dtos - Job Scheduler Data Transfer Objects
+
+==============================================================================
+FILE: internal/dtos/scheduler.go
+==============================================================================
+
+DESCRIPTION:
+    Response structures of the job scheduler: the registered jobs with
+    their last run and the time they are due again.
+
+USER PERSPECTIVE:
+    - Admins check at a glance whether escalation and the other periodic
+      jobs are running and when they run next
+
+DEVELOPER GUIDELINES:
+    ✅  OK to modify: Add job statistics (failure streak, average duration)
+    📝  Run history itself is returned as models.JobRun
+
+==============================================================================
+*/
+package dtos
+
+import (
+	"time"
+
+	"backend/internal/models"
+)
+
+// ScheduledJobStatus describes a registered job and its last run
+type ScheduledJobStatus struct {
+	Name            string         `json:"name"`
+	Description     string         `json:"description"`
+	IntervalMinutes int            `json:"interval_minutes"`
+	LastRun         *models.JobRun `json:"last_run,omitempty"`
+	NextRunAt       time.Time      `json:"next_run_at"` // Earliest tick the leader runs it again
+}
+
+// SchedulerStatus is the state of the scheduler across replicas
+type SchedulerStatus struct {
+	Leader         string               `json:"leader,omitempty"` // Instance holding the lease; empty when none
+	LeaseExpiresAt *time.Time           `json:"lease_expires_at,omitempty"`
+	Jobs           []ScheduledJobStatus `json:"jobs"`
+}
@@ -0,0 +1,78 @@
+/*
+Package dtos - Sick Leave (Incapacidades) Data Transfer Objects
+
+==============================================================================
+FILE: internal/dtos/sick_leave.go
+==============================================================================
+
+DESCRIPTION:
+    Request and response structures of IMSS incapacidades: registering and
+    closing a certificate, the open incapacidades and the days accumulated
+    per employee.
+
+USER PERSPECTIVE:
+    - HR captures a certificate as printed by the IMSS
+    - HR sees who is on incapacidad today and for how long the chain runs
+    - Payroll reviews days paid by the company vs subsidized by the IMSS
+
+DEVELOPER GUIDELINES:
+    ✅  OK to modify: Add summary columns
+    📝  Dates are whole days (YYYY-MM-DD); the end date is derived from
+        StartDate and DaysAuthorized
+
+==============================================================================
+*/
+package dtos
+
+import (
+	"github.com/google/uuid"
+
+	"backend/internal/models"
+)
+
+// SickLeaveCertificateRequest registers or corrects an IMSS certificate
+type SickLeaveCertificateRequest struct {
+	EmployeeID       uuid.UUID  `json:"employee_id" binding:"required"`
+	Folio            string     `json:"folio" binding:"required"`
+	Branch           string     `json:"branch" binding:"required,oneof=general_illness work_risk maternity"`
+	Type             string     `json:"type" binding:"required,oneof=initial subsequent relapse"`
+	PreviousID       *uuid.UUID `json:"previous_id,omitempty"` // Required for subsequent and relapse
+	AbsenceRequestID *uuid.UUID `json:"absence_request_id,omitempty"`
+	StartDate        Date       `json:"start_date" binding:"required"`
+	DaysAuthorized   int        `json:"days_authorized" binding:"required,gt=0,lte=364"`
+	Diagnosis        string     `json:"diagnosis,omitempty"`
+	Notes            string     `json:"notes,omitempty"`
+}
+
+// SickLeaveCloseRequest records the discharge (alta) of an incapacidad
+type SickLeaveCloseRequest struct {
+	ClosedOn Date   `json:"closed_on" binding:"required"`
+	Notes    string `json:"notes,omitempty"`
+}
+
+// SickLeaveFilter selects certificates
+type SickLeaveFilter struct {
+	EmployeeID *uuid.UUID
+	Status     string
+	Branch     string
+}
+
+// OpenSickLeave is an open incapacidad with the days its chain has run
+type OpenSickLeave struct {
+	models.SickLeaveCertificate
+	ChainDays int `json:"chain_days"` // Days authorized since the initial certificate
+}
+
+// SickLeaveEmployeeSummary is the incapacidad days of an employee in a range
+type SickLeaveEmployeeSummary struct {
+	EmployeeID       uuid.UUID      `json:"employee_id"`
+	EmployeeNumber   string         `json:"employee_number"`
+	EmployeeName     string         `json:"employee_name"`
+	Certificates     int            `json:"certificates"`
+	OpenCertificates int            `json:"open_certificates"`
+	TotalDays        int            `json:"total_days"`
+	CompanyPaidDays  int            `json:"company_paid_days"`
+	SubsidizedDays   int            `json:"subsidized_days"`
+	DaysByBranch     map[string]int `json:"days_by_branch"`
+	EstimatedSubsidy float64        `json:"estimated_subsidy"`
+}
@@ -0,0 +1,85 @@
+/*
+Package dtos - Time Bank Data Transfer Objects
+
+==============================================================================
+FILE: internal/dtos/time_bank.go
+==============================================================================
+
+DESCRIPTION:
+    Request and response structures for the time-for-time bank: manual
+    adjustments, the statement of an employee (one bucket per deposit plus
+    every movement with its running balance) and the result of the expiry
+    run.
+
+USER PERSPECTIVE:
+    - Employees see the overtime hours they banked and when they expire
+    - HR explains and corrects a balance movement by movement
+
+DEVELOPER GUIDELINES:
+    ✅  OK to modify: Add statement columns
+    📝  Hours are signed: deposits, reversals and positive adjustments add,
+        consumption and expiry subtract
+
+==============================================================================
+*/
+package dtos
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// TimeBankAdjustmentRequest posts a manual correction to an employee's time bank
+type TimeBankAdjustmentRequest struct {
+	Hours  float64 `json:"hours" binding:"required"`  // Signed: positive adds hours (a new deposit)
+	Reason string  `json:"reason" binding:"required"` // Why, shown on the statement
+}
+
+// TimeBankDeposit is what is left of one deposit
+type TimeBankDeposit struct {
+	ID                uuid.UUID  `json:"id"`
+	DepositedOn       time.Time  `json:"deposited_on"`
+	ExpiresOn         *time.Time `json:"expires_on,omitempty"`
+	OvertimeRequestID *uuid.UUID `json:"overtime_request_id,omitempty"`
+	Deposited         float64    `json:"deposited"`
+	Used              float64    `json:"used"` // Consumption net of reversals
+	Expired           float64    `json:"expired"`
+	Remaining         float64    `json:"remaining"`
+}
+
+// TimeBankMovement is one ledger entry with the balance after it
+type TimeBankMovement struct {
+	ID                uuid.UUID  `json:"id"`
+	Date              time.Time  `json:"date"`
+	Type              string     `json:"type"` // bank_deposit, consumption, reversal, expiry, adjustment
+	Hours             float64    `json:"hours"`
+	Balance           float64    `json:"balance"`
+	DepositID         *uuid.UUID `json:"deposit_id,omitempty"`
+	OvertimeRequestID *uuid.UUID `json:"overtime_request_id,omitempty"`
+	AbsenceRequestID  *uuid.UUID `json:"absence_request_id,omitempty"`
+	Notes             string     `json:"notes,omitempty"`
+	IsManual          bool       `json:"is_manual"`
+	CreatedByID       *uuid.UUID `json:"created_by_id,omitempty"`
+}
+
+// TimeBankStatement is an employee's time-for-time bank
+type TimeBankStatement struct {
+	EmployeeID     uuid.UUID          `json:"employee_id"`
+	EmployeeNumber string             `json:"employee_number"`
+	EmployeeName   string             `json:"employee_name"`
+	MaxHours       float64            `json:"max_hours"`   // Balance cap of the employee's time policy (0 = no banking)
+	ExpiryDays     int                `json:"expiry_days"` // 0 = deposits do not expire
+	Balance        float64            `json:"balance"`
+	PendingHours   float64            `json:"pending_hours"` // TIME_FOR_TIME requests not approved yet
+	Available      float64            `json:"available"`     // Balance - PendingHours
+	Deposits       []TimeBankDeposit  `json:"deposits"`
+	Movements      []TimeBankMovement `json:"movements"`
+}
+
+// TimeBankRunResult summarizes an expiry run over a company
+type TimeBankRunResult struct {
+	Employees    int     `json:"employees"`
+	Expiries     int     `json:"expiries"`
+	HoursExpired float64 `json:"hours_expired"`
+}
@@ -0,0 +1,104 @@
+/*
+Package dtos - Time Policy Data Transfer Objects
+
+==============================================================================
+FILE: internal/dtos/time_policy.go
+==============================================================================
+
+DESCRIPTION:
+    Request and response structures for the time policy catalog (rounding,
+    overtime thresholds, break rules, time bank limits), the policy in
+    effect for an employee and the violations dashboard.
+
+USER PERSPECTIVE:
+    - HR defines policies per collar type and/or user role
+    - HR checks which policy applies to an employee and why
+    - The dashboard shows breaks and overtime limits breached in a period
+
+DEVELOPER GUIDELINES:
+    ✅  OK to modify: Add dashboard breakdowns
+    ⚠️  CAUTION: TimePolicyRequest replaces every field; zero values are kept
+        (rounding 0 = no rounding, MaxOvertimeDaysPerWeek 0 = no limit,
+        TimeBankMaxHours 0 = no banking, TimeBankExpiryDays 0 = no expiry)
+    📝  Hours are decimal hours; break and rounding values are minutes
+
+==============================================================================
+*/
+package dtos
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+
+	"backend/internal/models"
+)
+
+// TimePolicyRequest creates or updates a time policy
+type TimePolicyRequest struct {
+	Name                    string   `json:"name" binding:"required"`
+	Description             string   `json:"description,omitempty"`
+	StandardWeeklyHours     float64  `json:"standard_weekly_hours" binding:"required,gt=0"`
+	StandardDailyHours      float64  `json:"standard_daily_hours" binding:"required,gt=0"`
+	OvertimeThresholdDaily  float64  `json:"overtime_threshold_daily" binding:"required,gt=0"`
+	OvertimeThresholdWeekly float64  `json:"overtime_threshold_weekly" binding:"required,gt=0"`
+	OvertimeMultiplier      float64  `json:"overtime_multiplier" binding:"required,gt=0"`
+	TripleTimeMultiplier    float64  `json:"triple_time_multiplier" binding:"required,gt=0"`
+	WeeklyDoubleTimeHours   float64  `json:"weekly_double_time_hours" binding:"gte=0"`
+	MaxDailyOvertimeHours   float64  `json:"max_daily_overtime_hours" binding:"gte=0"`
+	MaxOvertimeDaysPerWeek  int      `json:"max_overtime_days_per_week" binding:"gte=0,lte=7"`
+	RequiredBreakAfterHours float64  `json:"required_break_after_hours" binding:"gte=0"`
+	MinBreakMinutes         int      `json:"min_break_minutes" binding:"gte=0"`
+	DisableAutoBreak        bool     `json:"disable_auto_break"`
+	RoundingIntervalMinutes int      `json:"rounding_interval_minutes" binding:"gte=0,lte=60"`
+	TimeBankMaxHours        float64  `json:"time_bank_max_hours" binding:"gte=0"`
+	TimeBankExpiryDays      int      `json:"time_bank_expiry_days" binding:"gte=0"`
+	ApplicableRoles         []string `json:"applicable_roles,omitempty"`
+	ApplicableCollarTypes   []string `json:"applicable_collar_types,omitempty" binding:"omitempty,dive,oneof=white_collar blue_collar gray_collar"`
+	IsDefault               bool     `json:"is_default"`
+	IsActive                *bool    `json:"is_active,omitempty"`
+}
+
+// EffectiveTimePolicyResponse is the policy applied to an employee
+type EffectiveTimePolicyResponse struct {
+	EmployeeID uuid.UUID          `json:"employee_id"`
+	CollarType string             `json:"collar_type"`
+	Role       string             `json:"role,omitempty"`
+	MatchedBy  string             `json:"matched_by"` // collar_and_role, collar_type, role, default, built_in
+	Policy     *models.TimePolicy `json:"policy"`
+}
+
+// TimePolicyViolationFilter filters the violations list
+type TimePolicyViolationFilter struct {
+	EmployeeID *uuid.UUID
+	Type       string
+	Resolved   *bool
+	StartDate  *time.Time
+	EndDate    *time.Time
+}
+
+// ResolveTimePolicyViolationRequest closes a violation
+type ResolveTimePolicyViolationRequest struct {
+	Notes string `json:"notes" binding:"required"`
+}
+
+// TimePolicyEmployeeViolations counts an employee's violations
+type TimePolicyEmployeeViolations struct {
+	EmployeeID     uuid.UUID `json:"employee_id"`
+	EmployeeNumber string    `json:"employee_number"`
+	EmployeeName   string    `json:"employee_name"`
+	Violations     int       `json:"violations"`
+	Unresolved     int       `json:"unresolved"`
+}
+
+// TimePolicyDashboard summarizes violations in a period
+type TimePolicyDashboard struct {
+	StartDate    time.Time                      `json:"start_date"`
+	EndDate      time.Time                      `json:"end_date"`
+	Total        int                            `json:"total"`
+	Unresolved   int                            `json:"unresolved"`
+	ByType       map[string]int                 `json:"by_type"`
+	BySeverity   map[string]int                 `json:"by_severity"`
+	TopEmployees []TimePolicyEmployeeViolations `json:"top_employees"`
+	Recent       []models.TimePolicyViolation   `json:"recent"`
+}
@@ -0,0 +1,121 @@
+/*
+Package dtos - Timesheet Period Data Transfer Objects
+
+==============================================================================
+FILE: internal/dtos/timesheet.go
+==============================================================================
+
+DESCRIPTION:
+    Request and response structures for the timesheet cycle: generating a
+    payroll period's timesheets, deadline reminders, billable utilization per
+    project and member, and the export of approved hours to prenómina.
+
+USER PERSPECTIVE:
+    - Payroll opens a period and every project-tracked employee gets a timesheet
+    - Project managers compare billable and non-billable hours and amounts
+    - Payroll sends the approved hours of hourly employees to prenómina
+
+DEVELOPER GUIDELINES:
+    ✅  OK to modify: Add report columns
+    📝  Utilization = billable hours / total hours, 0-100
+
+==============================================================================
+*/
+package dtos
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// TimesheetGenerateRequest selects the payroll periods to generate timesheets for
+type TimesheetGenerateRequest struct {
+	PayrollPeriodID *uuid.UUID `json:"payroll_period_id,omitempty"` // Defaults to the open periods covering Date
+	Date            *Date      `json:"date,omitempty"`              // Defaults to today
+}
+
+// TimesheetGenerationResult summarizes a generation run
+type TimesheetGenerationResult struct {
+	Periods  int `json:"periods"`
+	Created  int `json:"created"`
+	Existing int `json:"existing"`
+	Entries  int `json:"entries"` // Unassigned time entries attached to the new timesheets
+}
+
+// TimesheetReminderResult summarizes a reminder run
+type TimesheetReminderResult struct {
+	Reminded    int `json:"reminded"`
+	WithoutUser int `json:"without_user"` // Employees with no portal account to notify
+}
+
+// UtilizationFilter selects the entries of a utilization report
+type UtilizationFilter struct {
+	StartDate    time.Time
+	EndDate      time.Time
+	ProjectID    *uuid.UUID
+	ApprovedOnly bool // Only entries of approved or exported timesheets
+}
+
+// MemberUtilization is one member's hours on a project
+type MemberUtilization struct {
+	EmployeeID       uuid.UUID `json:"employee_id"`
+	EmployeeNumber   string    `json:"employee_number"`
+	EmployeeName     string    `json:"employee_name"`
+	Role             string    `json:"role,omitempty"`
+	HourlyRate       float64   `json:"hourly_rate"`
+	BillableHours    float64   `json:"billable_hours"`
+	NonBillableHours float64   `json:"non_billable_hours"`
+	Utilization      float64   `json:"utilization"`
+	BillableAmount   float64   `json:"billable_amount"`
+}
+
+// ProjectUtilization is a project's billable and non-billable hours
+type ProjectUtilization struct {
+	ProjectID        uuid.UUID           `json:"project_id"`
+	Code             string              `json:"code"`
+	Name             string              `json:"name"`
+	ClientName       string              `json:"client_name,omitempty"`
+	BudgetHours      float64             `json:"budget_hours"`
+	BudgetAmount     float64             `json:"budget_amount"`
+	BillableHours    float64             `json:"billable_hours"`
+	NonBillableHours float64             `json:"non_billable_hours"`
+	TotalHours       float64             `json:"total_hours"`
+	Utilization      float64             `json:"utilization"`
+	BillableAmount   float64             `json:"billable_amount"`
+	Members          []MemberUtilization `json:"members"`
+}
+
+// UtilizationReport is the utilization of every project in a date range
+type UtilizationReport struct {
+	StartDate        time.Time            `json:"start_date"`
+	EndDate          time.Time            `json:"end_date"`
+	Projects         []ProjectUtilization `json:"projects"`
+	BillableHours    float64              `json:"billable_hours"`
+	NonBillableHours float64              `json:"non_billable_hours"`
+	Utilization      float64              `json:"utilization"`
+	BillableAmount   float64              `json:"billable_amount"`
+}
+
+// TimesheetExportRequest selects the payroll period to export
+type TimesheetExportRequest struct {
+	PayrollPeriodID uuid.UUID `json:"payroll_period_id" binding:"required"`
+}
+
+// TimesheetExportLine is one timesheet of an export run
+type TimesheetExportLine struct {
+	TimesheetID    uuid.UUID `json:"timesheet_id"`
+	EmployeeID     uuid.UUID `json:"employee_id"`
+	EmployeeNumber string    `json:"employee_number"`
+	EmployeeName   string    `json:"employee_name"`
+	RegularHours   float64   `json:"regular_hours"`
+	OvertimeHours  float64   `json:"overtime_hours"`
+	Reason         string    `json:"reason,omitempty"` // Why a timesheet was skipped
+}
+
+// TimesheetExportResult lists the timesheets sent to prenómina and the ones left out
+type TimesheetExportResult struct {
+	PayrollPeriodID uuid.UUID             `json:"payroll_period_id"`
+	Exported        []TimesheetExportLine `json:"exported"`
+	Skipped         []TimesheetExportLine `json:"skipped"`
+}
@@ -0,0 +1,91 @@
+/*
+Package dtos - Vacation Ledger Data Transfer Objects
+
+==============================================================================
+FILE: internal/dtos/vacation_ledger.go
+==============================================================================
+
+DESCRIPTION:
+    Request and response structures for the vacation ledger: manual
+    adjustments, the statement of an employee (one bucket per work
+    anniversary plus every movement with its running balance) and the
+    result of the grant / expiry runs.
+
+USER PERSPECTIVE:
+    - HR explains a balance movement by movement
+    - Employees see how many days they carry over and when they expire
+
+DEVELOPER GUIDELINES:
+    ✅  OK to modify: Add statement columns
+    📝  Days are signed: grants and reversals add, consumption and expiry subtract
+
+==============================================================================
+*/
+package dtos
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// VacationAdjustmentRequest posts a manual correction to an employee's vacation ledger
+type VacationAdjustmentRequest struct {
+	Days        float64 `json:"days" binding:"required"`   // Signed: positive adds days
+	ServiceYear int     `json:"service_year"`              // Grant to adjust; 0 = current
+	Reason      string  `json:"reason" binding:"required"` // Why, shown on the statement
+}
+
+// VacationBucket is what is left of the days granted on one work anniversary
+type VacationBucket struct {
+	ServiceYear int        `json:"service_year"`
+	GrantedOn   *time.Time `json:"granted_on,omitempty"`
+	ExpiresOn   *time.Time `json:"expires_on,omitempty"`
+	Granted     float64    `json:"granted"`
+	Used        float64    `json:"used"` // Consumption net of reversals
+	Expired     float64    `json:"expired"`
+	Adjusted    float64    `json:"adjusted"`
+	Remaining   float64    `json:"remaining"`
+	Status      string     `json:"status"` // current, carried_over, expired, exhausted, advance
+}
+
+// VacationMovement is one ledger entry with the balance after it
+type VacationMovement struct {
+	ID          uuid.UUID  `json:"id"`
+	Date        time.Time  `json:"date"`
+	Type        string     `json:"type"` // annual_grant, consumption, reversal, expiry, adjustment
+	ServiceYear int        `json:"service_year"`
+	Days        float64    `json:"days"`
+	Balance     float64    `json:"balance"`
+	IncidenceID *uuid.UUID `json:"incidence_id,omitempty"`
+	Notes       string     `json:"notes,omitempty"`
+	IsManual    bool       `json:"is_manual"`
+	CreatedByID *uuid.UUID `json:"created_by_id,omitempty"`
+}
+
+// VacationStatement is an employee's vacation ledger
+type VacationStatement struct {
+	EmployeeID      uuid.UUID          `json:"employee_id"`
+	EmployeeNumber  string             `json:"employee_number"`
+	EmployeeName    string             `json:"employee_name"`
+	HireDate        time.Time          `json:"hire_date"`
+	YearsOfService  int                `json:"years_of_service"`
+	NextAnniversary time.Time          `json:"next_anniversary"`
+	NextGrantDays   int                `json:"next_grant_days"`
+	CurrentGrant    float64            `json:"current_grant"` // Days of the latest anniversary
+	CarriedOver     float64            `json:"carried_over"`  // Days left from earlier anniversaries
+	Balance         float64            `json:"balance"`
+	PendingDays     float64            `json:"pending_days"` // Requested, not approved yet
+	Available       float64            `json:"available"`    // Balance - PendingDays
+	Buckets         []VacationBucket   `json:"buckets"`
+	Movements       []VacationMovement `json:"movements"`
+}
+
+// VacationLedgerRunResult summarizes a grant or expiry run over a company
+type VacationLedgerRunResult struct {
+	Employees   int     `json:"employees"`
+	Grants      int     `json:"grants"`
+	DaysGranted float64 `json:"days_granted"`
+	Expiries    int     `json:"expiries"`
+	DaysExpired float64 `json:"days_expired"`
+}
@@ -0,0 +1,62 @@
+/*
+Package dtos - Work Site Data Transfer Objects
+
+==============================================================================
+FILE: internal/dtos/work_site.go
+==============================================================================
+
+DESCRIPTION:
+    Request structures for geofenced web/app clock-in: work sites, the
+    assignment of employees to them, trusted devices and the review of
+    flagged punches.
+
+USER PERSPECTIVE:
+    - HR creates the sites and assigns employees
+    - Employees register the phone they clock in from
+    - Supervisors accept or reject out-of-zone and unknown-device punches
+
+DEVELOPER GUIDELINES:
+    ✅  OK to modify: Add site attributes
+    📝  Coordinates are decimal degrees (WGS84), as browsers report them
+
+==============================================================================
+*/
+package dtos
+
+import (
+	"github.com/google/uuid"
+)
+
+// WorkSiteRequest creates or updates a work site
+type WorkSiteRequest struct {
+	Name                 string   `json:"name" binding:"required"`
+	Code                 string   `json:"code" binding:"required"`
+	Address              string   `json:"address,omitempty"`
+	Latitude             float64  `json:"latitude" binding:"gte=-90,lte=90"`
+	Longitude            float64  `json:"longitude" binding:"gte=-180,lte=180"`
+	RadiusMeters         float64  `json:"radius_meters" binding:"required,gt=0,lte=50000"`
+	AllowedIPRanges      []string `json:"allowed_ip_ranges,omitempty"`
+	BlockOutOfZone       bool     `json:"block_out_of_zone"`
+	RequireTrustedDevice bool     `json:"require_trusted_device"`
+	RequirePhoto         bool     `json:"require_photo"`
+	IsActive             *bool    `json:"is_active,omitempty"`
+}
+
+// WorkSiteAssignRequest assigns employees to a site (moving them from their previous one)
+type WorkSiteAssignRequest struct {
+	EmployeeIDs []uuid.UUID `json:"employee_ids" binding:"required,min=1"`
+}
+
+// TrustedDeviceRequest registers the device an employee clocks in from
+type TrustedDeviceRequest struct {
+	EmployeeID *uuid.UUID `json:"employee_id,omitempty"` // HR registering for someone else; defaults to the caller
+	DeviceID   string     `json:"device_id" binding:"required,min=8,max=128"`
+	Name       string     `json:"name,omitempty"`
+	Platform   string     `json:"platform,omitempty"`
+}
+
+// ClockReviewRequest accepts or rejects a flagged punch
+type ClockReviewRequest struct {
+	Action string `json:"action" binding:"required,oneof=accept reject"`
+	Notes  string `json:"notes,omitempty"`
+}
@@ -26,6 +26,8 @@ APPROVAL FLOW:
     - Normal employees: SUPERVISOR → MANAGER → HR (if blue collar) → COMPLETED
     - SUPANDGM supervisor: Auto-skip SUPERVISOR and MANAGER, start at HR
     - White collar: Skip HR stage after manager approval
+    - Requests created since approval workflows exist follow their
+      ApprovalWorkflow (see approval_workflow.go); WorkflowID records which
 
 REQUEST TYPES:
     - PAID_LEAVE: Permiso con goce de sueldo
@@ -37,11 +39,40 @@ REQUEST TYPES:
     - TIME_FOR_TIME: Tiempo por tiempo
     - SICK_LEAVE: Incapacidad por enfermedad
 
+PARTIAL-DAY REQUESTS:
+    - StartTime/EndTime (HH:MM) turn a one-day request into a permit by the
+      hour; the window must fall inside the employee's shift that day
+    - LATE_ENTRY starts at the shift start and EARLY_EXIT ends at the shift
+      end; both always carry a window
+    - TotalHours is the window; TotalDays its share of the shift's hours,
+      which is what the incidence pays or deducts
+    - TIME_FOR_TIME charges TotalHours to the time bank (TimeBankService)
+
+LEAVE POLICIES:
+    - LEAVE requests name a LeavePolicy (LeavePolicyID); its eligibility,
+      entitlement and required documents are checked when filed and
+      approved (LeavePolicyService) and the incidence takes the policy's
+      IncidenceType
+
+CHANGES AFTER APPROVAL:
+    - An approved request is cancelled or modified through a change
+      request: a new AbsenceRequest with AmendsID pointing at it and
+      ChangeType CANCELLATION or MODIFICATION, routed like any request
+    - When the change is fully approved the original becomes CANCELLED or
+      MODIFIED and its incidences, vacation days, time bank hours and shift
+      exceptions are reversed; a MODIFICATION then takes effect as a
+      regular approved request with its new dates
+    - An approved CANCELLATION is not time off: queries of absences exclude
+      it (timeOffRequests in the services package)
+    - The original's ApprovalHistory records CHANGE_REQUESTED,
+      CHANGE_DECLINED and CANCELLED/MODIFIED entries at stage COMPLETED
+
 ==============================================================================
 */
 package models
 
 import (
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
@@ -63,16 +94,25 @@ const (
 	RequestTypeSickLeave    RequestType = "SICK_LEAVE"
 	RequestTypePersonal     RequestType = "PERSONAL"
 	RequestTypeOther        RequestType = "OTHER"
+	RequestTypeLeave        RequestType = "LEAVE" // Leave of the catalog (LeavePolicyID): maternity, paternity, ...
 )
 
 // RequestStatus represents the status of an absence request
 type RequestStatus string
 
 const (
-	RequestStatusPending  RequestStatus = "PENDING"
-	RequestStatusApproved RequestStatus = "APPROVED"
-	RequestStatusDeclined RequestStatus = "DECLINED"
-	RequestStatusArchived RequestStatus = "ARCHIVED"
+	RequestStatusPending   RequestStatus = "PENDING"
+	RequestStatusApproved  RequestStatus = "APPROVED"
+	RequestStatusDeclined  RequestStatus = "DECLINED"
+	RequestStatusArchived  RequestStatus = "ARCHIVED"
+	RequestStatusCancelled RequestStatus = "CANCELLED" // Cancelled after approval by an approved change
+	RequestStatusModified  RequestStatus = "MODIFIED"  // Replaced after approval by an approved change
+)
+
+// Change request types (AbsenceRequest.ChangeType)
+const (
+	RequestChangeCancellation = "CANCELLATION"
+	RequestChangeModification = "MODIFICATION"
 )
 
 // ApprovalStage represents the current stage in the approval workflow
@@ -94,6 +134,12 @@ type ApprovalAction string
 const (
 	ApprovalActionApproved ApprovalAction = "APPROVED"
 	ApprovalActionDeclined ApprovalAction = "DECLINED"
+
+	// Recorded on an approved request when it is changed
+	ApprovalActionChangeRequested ApprovalAction = "CHANGE_REQUESTED"
+	ApprovalActionChangeDeclined  ApprovalAction = "CHANGE_DECLINED"
+	ApprovalActionCancelled       ApprovalAction = "CANCELLED"
+	ApprovalActionModified        ApprovalAction = "MODIFIED"
 )
 
 // AbsenceRequest represents an employee's request for time off
@@ -109,6 +155,9 @@ type AbsenceRequest struct {
 	Status                RequestStatus `gorm:"type:varchar(50);default:'PENDING'" json:"status"`
 	CurrentApprovalStage  ApprovalStage `gorm:"type:varchar(50);default:'SUPERVISOR'" json:"current_approval_stage"`
 	CustomFields          datatypes.JSON `gorm:"type:jsonb" json:"custom_fields,omitempty"`                  // New: Stores dynamic form field values
+	WorkflowID            *uuid.UUID    `gorm:"type:text;index" json:"workflow_id,omitempty"`                // ApprovalWorkflow the request follows (nil = built-in chain)
+	PendingStages         string        `gorm:"type:varchar(255)" json:"pending_stages,omitempty"`            // Parallel stages still waiting, as "|HR|MEDICAL_REVIEW|"
+	DelegatedFromID       *uuid.UUID    `gorm:"-" json:"delegated_from_id,omitempty"`                         // Set in the delegated queue: whose approval this is
 
 	// Optional fields for specific request types (legacy - kept for backward compatibility)
 	HoursPerDay           *float64      `gorm:"type:decimal(4,2)" json:"hours_per_day,omitempty"`
@@ -117,12 +166,25 @@ type AbsenceRequest struct {
 	UnpaidComments        string        `gorm:"type:text" json:"unpaid_comments,omitempty"`
 	ShiftDetails          string        `gorm:"type:text" json:"shift_details,omitempty"`
 
+	// Partial-day permits: a window of the shift on StartDate (= EndDate)
+	StartTime             string        `gorm:"type:varchar(5)" json:"start_time,omitempty"` // HH:MM
+	EndTime               string        `gorm:"type:varchar(5)" json:"end_time,omitempty"`   // HH:MM
+	TotalHours            float64       `gorm:"type:decimal(6,2);default:0" json:"total_hours"` // Window hours; TIME_FOR_TIME: hours charged to the time bank
+
+	// LEAVE requests: the catalog leave taken
+	LeavePolicyID         *uuid.UUID    `gorm:"type:text;index" json:"leave_policy_id,omitempty"`
+
+	// Changes after approval: this request cancels or modifies AmendsID
+	AmendsID              *uuid.UUID    `gorm:"type:text;index" json:"amends_id,omitempty"`
+	ChangeType            string        `gorm:"type:varchar(20);default:''" json:"change_type,omitempty"` // CANCELLATION, MODIFICATION
+
 	// Shift change specific fields
 	NewShiftID            *uuid.UUID    `gorm:"type:text" json:"new_shift_id,omitempty"`
 	NewShift              *Shift        `gorm:"foreignKey:NewShiftID" json:"new_shift,omitempty"`
 
 	// Escalation and Payroll Export Fields (NEW for dual Excel export system)
 	LastActionAt          time.Time     `gorm:"type:timestamp;default:CURRENT_TIMESTAMP;index" json:"last_action_at"`           // For 24-hour escalation tracking
+	ReminderSentAt        *time.Time    `json:"reminder_sent_at,omitempty"`                                                     // Pre-SLA reminder of the current stage (sent when after LastActionAt)
 	EscalationCount       int           `gorm:"default:0" json:"escalation_count"`                                               // Number of times escalated
 	IsEscalated           bool          `gorm:"default:false;index:idx_absence_escalation,priority:1" json:"is_escalated"`      // Has this been auto-escalated?
 	LateApprovalFlag      bool          `gorm:"default:false" json:"late_approval_flag"`                                         // Approved after payroll cutoff
@@ -132,6 +194,7 @@ type AbsenceRequest struct {
 	// Relations
 	Employee              *Employee         `gorm:"foreignKey:EmployeeID" json:"employee,omitempty"`
 	IncidenceType         *IncidenceType    `gorm:"foreignKey:IncidenceTypeID" json:"incidence_type,omitempty"` // New: Relationship to incidence type
+	LeavePolicy           *LeavePolicy      `gorm:"foreignKey:LeavePolicyID" json:"leave_policy,omitempty"`
 	ApprovalHistory       []ApprovalHistory `gorm:"foreignKey:RequestID" json:"approval_history,omitempty"`
 	EscalationLogs        []EscalationLog   `gorm:"foreignKey:AbsenceRequestID" json:"escalation_logs,omitempty"` // NEW: Escalation audit trail
 }
@@ -155,6 +218,26 @@ func (ar *AbsenceRequest) BeforeCreate(tx *gorm.DB) (err error) {
 	return nil
 }
 
+// AwaitsStage reports whether the request is waiting for an approval at stage,
+// either as its current stage or as one of its parallel stages
+func (ar *AbsenceRequest) AwaitsStage(stage ApprovalStage) bool {
+	if ar.CurrentApprovalStage == stage {
+		return true
+	}
+	return strings.Contains(ar.PendingStages, "|"+string(stage)+"|")
+}
+
+// IsPartialDay reports whether the request covers a window of one shift
+// instead of whole days
+func (ar *AbsenceRequest) IsPartialDay() bool {
+	return ar.StartTime != ""
+}
+
+// IsChange reports whether the request cancels or modifies an approved one
+func (ar *AbsenceRequest) IsChange() bool {
+	return ar.AmendsID != nil
+}
+
 // ApprovalHistory records each approval/decline action in the workflow
 type ApprovalHistory struct {
 	BaseModel
@@ -163,10 +246,12 @@ type ApprovalHistory struct {
 	ApprovalStage ApprovalStage  `gorm:"type:varchar(50);not null" json:"approval_stage"`
 	Action        ApprovalAction `gorm:"type:varchar(50);not null" json:"action"`
 	Comments      string         `gorm:"type:text" json:"comments,omitempty"`
+	OnBehalfOfID  *uuid.UUID     `gorm:"type:text" json:"on_behalf_of_id,omitempty"` // Delegator when a substitute acted (see ApprovalDelegation)
 
 	// Relations
 	Request       *AbsenceRequest `gorm:"foreignKey:RequestID" json:"request,omitempty"`
 	Approver      *User           `gorm:"foreignKey:ApproverID" json:"approver,omitempty"`
+	OnBehalfOf    *User           `gorm:"foreignKey:OnBehalfOfID" json:"on_behalf_of,omitempty"`
 }
 
 // TableName specifies the table name
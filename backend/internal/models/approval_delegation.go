@@ -0,0 +1,88 @@
+/*
+Package models - IRIS Payroll System Data Models
+
+==============================================================================
+FILE: internal/models/approval_delegation.go
+==============================================================================
+
+DESCRIPTION:
+    Out-of-office substitutes for approvers. An ApprovalDelegation lends a
+    user's approval authority (DelegatorID) to another user (DelegateID)
+    for a date range, optionally only for some request types. While it is
+    active the delegate sees the delegator's pending requests and acts on
+    them; ApprovalHistory records the delegate as approver and the
+    delegator in OnBehalfOfID.
+
+USER PERSPECTIVE:
+    - A supervisor going on vacation hands their approvals to a colleague
+      instead of letting requests wait for the 24-hour escalation
+    - When an approver's own vacation is approved, the system suggests a
+      substitute; the approver confirms it (or picks someone else)
+    - HR can set up a delegation for someone who left without doing it
+
+DEVELOPER GUIDELINES:
+    ✅  OK to modify: Add delegation scopes (stages, employees)
+    ⚠️  CAUTION: Delegations are not transitive; a delegate never passes on
+        authority they only hold through a delegation
+    📝  StartDate/EndDate are whole days, both inclusive
+
+SYNTAX EXPLANATION:
+    - Status: suggested (proposed on an approved absence, not in force yet),
+      active, revoked
+    - RequestTypes: empty = every request type
+
+==============================================================================
+*/
+package models
+
+import (
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+// Approval delegation statuses
+const (
+	DelegationSuggested = "suggested"
+	DelegationActive    = "active"
+	DelegationRevoked   = "revoked"
+)
+
+// ApprovalDelegation lends an approver's authority to a substitute for a date range
+type ApprovalDelegation struct {
+	BaseModel
+	CompanyID        uuid.UUID      `gorm:"type:text;not null;index" json:"company_id"`
+	DelegatorID      uuid.UUID      `gorm:"type:text;not null;index" json:"delegator_id"`
+	DelegateID       uuid.UUID      `gorm:"type:text;not null;index" json:"delegate_id"`
+	StartDate        time.Time      `gorm:"type:date;not null" json:"start_date"`
+	EndDate          time.Time      `gorm:"type:date;not null" json:"end_date"`
+	RequestTypes     pq.StringArray `gorm:"type:text[]" json:"request_types,omitempty"`
+	Reason           string         `gorm:"type:text" json:"reason,omitempty"`
+	Status           string         `gorm:"type:varchar(20);not null;default:'active';index" json:"status"`
+	AbsenceRequestID *uuid.UUID     `gorm:"type:text" json:"absence_request_id,omitempty"` // Absence the suggestion came from
+	CreatedByID      uuid.UUID      `gorm:"type:text;not null" json:"created_by_id"`
+	RevokedByID      *uuid.UUID     `gorm:"type:text" json:"revoked_by_id,omitempty"`
+	RevokedAt        *time.Time     `json:"revoked_at,omitempty"`
+	Delegator        *User          `gorm:"foreignKey:DelegatorID" json:"delegator,omitempty"`
+	Delegate         *User          `gorm:"foreignKey:DelegateID" json:"delegate,omitempty"`
+}
+
+// TableName specifies the table name
+func (ApprovalDelegation) TableName() string {
+	return "approval_delegations"
+}
+
+// Covers reports whether the delegation applies to a request type
+func (d *ApprovalDelegation) Covers(requestType RequestType) bool {
+	if len(d.RequestTypes) == 0 {
+		return true
+	}
+	for _, covered := range d.RequestTypes {
+		if strings.EqualFold(covered, string(requestType)) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,147 @@
+/*
+Package models - IRIS Payroll System Data Models
+
+==============================================================================
+FILE: internal/models/approval_workflow.go
+==============================================================================
+
+DESCRIPTION:
+    Configurable approval workflows for absence requests. An
+    ApprovalWorkflow belongs to a company and applies either to one
+    IncidenceType or to every type whose ApprovalFlow matches its Code.
+    Its ApprovalWorkflowSteps are the stages a request goes through, each
+    with the rule that decides who may approve it, the conditions under
+    which it applies and how long it may wait before escalating.
+
+USER PERSPECTIVE:
+    - HR keeps the usual chain (supervisor, manager, HR, general manager,
+      payroll) and adjusts it per request type without a deploy
+    - Short absences can skip the general manager; sick leave can add a
+      medical review
+    - Two approvers can work on a request at the same time (parallel steps)
+
+DEVELOPER GUIDELINES:
+    ✅  OK to modify: Add step conditions (department, amount, ...)
+    ⚠️  CAUTION: Stage codes are stored on AbsenceRequest.CurrentApprovalStage
+        and in ApprovalHistory; renaming a stage strands requests in flight
+    ⚠️  CAUTION: A request keeps the workflow it started with (WorkflowID);
+        requests created before workflows existed follow the built-in chain
+    📝  Every company gets the "standard" workflow seeded as its default
+
+SYNTAX EXPLANATION:
+    - StepOrder: steps sharing an order run in parallel; all of them must
+      approve before the request moves on
+    - ApproverRule: supervisor (the employee's direct supervisor or whoever
+      covers for them), role (any user with one of ApproverRoles), user
+      (ApproverUserID), hr_by_collar (HR for white collar, HR_BLUE_GRAY for
+      blue/gray collar and unionized employees)
+    - Conditions: MinDays (applies from that many days on), RequestTypes
+      (only these request types), CollarScope (white / blue_gray)
+    - EscalationHours: waiting time (SLA) before the request escalates to
+      the next step; 0 never escalates. Requests created before workflows
+      existed take the SLA of the same stage in the company's default workflow
+    - ReminderHours: how long before the SLA runs out the approvers get a
+      reminder; 0 sends none
+
+==============================================================================
+*/
+package models
+
+import (
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+// DefaultApprovalFlow is the IncidenceType.ApprovalFlow of the seeded workflow
+const DefaultApprovalFlow = "standard"
+
+// Approver rules of a workflow step
+const (
+	ApproverRuleSupervisor = "supervisor"
+	ApproverRuleRole       = "role"
+	ApproverRuleUser       = "user"
+	ApproverRuleHRByCollar = "hr_by_collar"
+)
+
+// Collar scopes of a workflow step
+const (
+	CollarScopeWhite    = "white"
+	CollarScopeBlueGray = "blue_gray"
+)
+
+// ApprovalWorkflow is an ordered set of approval steps
+type ApprovalWorkflow struct {
+	BaseModel
+	CompanyID       uuid.UUID              `gorm:"type:text;not null;index" json:"company_id"`
+	IncidenceTypeID *uuid.UUID             `gorm:"type:text;index" json:"incidence_type_id,omitempty"` // nil = matched by Code
+	Code            string                 `gorm:"type:varchar(50);not null" json:"code"`              // Matched against IncidenceType.ApprovalFlow
+	Name            string                 `gorm:"type:varchar(150);not null" json:"name"`
+	Description     string                 `gorm:"type:text" json:"description,omitempty"`
+	IsDefault       bool                   `gorm:"default:false" json:"is_default"` // Used when nothing else matches
+	IsActive        bool                   `gorm:"default:true" json:"is_active"`
+	Steps           []ApprovalWorkflowStep `gorm:"foreignKey:WorkflowID" json:"steps,omitempty"`
+	IncidenceType   *IncidenceType         `gorm:"foreignKey:IncidenceTypeID" json:"incidence_type,omitempty"`
+}
+
+// TableName specifies the table name
+func (ApprovalWorkflow) TableName() string {
+	return "approval_workflows"
+}
+
+// ApprovalWorkflowStep is one stage of a workflow
+type ApprovalWorkflowStep struct {
+	BaseModel
+	WorkflowID      uuid.UUID      `gorm:"type:text;not null;index" json:"workflow_id"`
+	StepOrder       int            `gorm:"not null" json:"step_order"`
+	Stage           ApprovalStage  `gorm:"type:varchar(50);not null" json:"stage"`
+	Name            string         `gorm:"type:varchar(100)" json:"name"`
+	ApproverRule    string         `gorm:"type:varchar(30);not null" json:"approver_rule"`
+	ApproverRoles   pq.StringArray `gorm:"type:text[]" json:"approver_roles,omitempty"`
+	ApproverUserID  *uuid.UUID     `gorm:"type:text" json:"approver_user_id,omitempty"`
+	MinDays         float64        `gorm:"type:decimal(5,2);default:0" json:"min_days"`
+	RequestTypes    pq.StringArray `gorm:"type:text[]" json:"request_types,omitempty"`
+	CollarScope     string         `gorm:"type:varchar(20)" json:"collar_scope,omitempty"`
+	EscalationHours int            `gorm:"default:0" json:"escalation_hours"`
+	ReminderHours   int            `gorm:"default:0" json:"reminder_hours"`
+}
+
+// TableName specifies the table name
+func (ApprovalWorkflowStep) TableName() string {
+	return "approval_workflow_steps"
+}
+
+// StageFor returns the stage code a request waits in for this step
+func (s *ApprovalWorkflowStep) StageFor(blueOrGrayCollar bool) ApprovalStage {
+	if s.ApproverRule == ApproverRuleHRByCollar && blueOrGrayCollar {
+		return ApprovalStageHRBlueGray
+	}
+	return s.Stage
+}
+
+// AppliesTo reports whether the step's conditions hold for a request
+func (s *ApprovalWorkflowStep) AppliesTo(request *AbsenceRequest, blueOrGrayCollar bool) bool {
+	if s.MinDays > 0 && request.TotalDays < s.MinDays {
+		return false
+	}
+	if len(s.RequestTypes) > 0 {
+		matched := false
+		for _, requestType := range s.RequestTypes {
+			if strings.EqualFold(requestType, string(request.RequestType)) {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			return false
+		}
+	}
+	switch s.CollarScope {
+	case CollarScopeWhite:
+		return !blueOrGrayCollar
+	case CollarScopeBlueGray:
+		return blueOrGrayCollar
+	}
+	return true
+}
@@ -0,0 +1,129 @@
+/*
+Package models - IRIS Payroll System Data Models
+
+==============================================================================
+FILE: internal/models/attendance.go
+==============================================================================
+
+DESCRIPTION:
+    Daily attendance evaluation. One AttendanceDay row per employee and
+    date compares the scheduled shift (ShiftException, rotation roster,
+    EmployeeShiftBase or the employee's default Shift) against the real
+    punches (ClockRecord and the NFC terminal's attendance_records) and
+    keeps the result as the audit trail behind the prenómina metrics.
+
+USER PERSPECTIVE:
+    - HR sees, day by day, why an employee has a delay, an absence or overtime
+    - HR can correct a day (forgotten punch, justified delay) with a reason
+    - Corrected days survive every later recalculation
+
+DEVELOPER GUIDELINES:
+    ✅  OK to modify: Add statuses or derived columns
+    ⚠️  CAUTION: Rows are written by AttendanceEvaluationService; only the
+        override endpoints edit them by hand
+    ❌  DO NOT modify: Overridden rows from the evaluator (IsOverridden wins)
+    📝  OriginalValues keeps the evaluator's numbers when HR overrides a day
+
+SYNTAX EXPLANATION:
+    - ScheduleSource: exception | rotation | weekly | default | none
+    - ScheduledEnd may fall on the next calendar day (night shifts)
+    - LateMinutes / EarlyExitMinutes are only set once the tolerance is exceeded
+    - An approved partial-day permit moves the expected arrival (or exit)
+      to the end (or start) of its window
+
+==============================================================================
+*/
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/datatypes"
+)
+
+// Attendance day statuses
+const (
+	AttendanceStatusPresent       = "present"
+	AttendanceStatusLate          = "late"
+	AttendanceStatusIncomplete    = "incomplete" // Clock-in without clock-out
+	AttendanceStatusAbsent        = "absent"     // Scheduled, no punches, nothing justifies it
+	AttendanceStatusJustified     = "justified"  // Covered by an approved request or incidence
+	AttendanceStatusRestDay       = "rest_day"
+	AttendanceStatusHoliday       = "holiday"
+	AttendanceStatusWorkedRestDay = "worked_rest_day"
+	AttendanceStatusWorkedHoliday = "worked_holiday"
+	AttendanceStatusUnscheduled   = "unscheduled" // No shift configured for the date
+	AttendanceStatusPending       = "pending"     // Future date, not evaluated yet
+)
+
+// Attendance schedule sources
+const (
+	ScheduleSourceException = "exception"
+	ScheduleSourceRotation  = "rotation"
+	ScheduleSourceWeekly    = "weekly"
+	ScheduleSourceDefault   = "default"
+	ScheduleSourceNone      = "none"
+)
+
+// AttendanceDay is the evaluated attendance of one employee on one date
+type AttendanceDay struct {
+	BaseModel
+	CompanyID       uuid.UUID  `gorm:"type:text;not null;index" json:"company_id"`
+	EmployeeID      uuid.UUID  `gorm:"type:text;not null;uniqueIndex:idx_attendance_employee_date" json:"employee_id"`
+	WorkDate        time.Time  `gorm:"type:date;not null;uniqueIndex:idx_attendance_employee_date" json:"work_date"`
+	PayrollPeriodID *uuid.UUID `gorm:"type:text;index" json:"payroll_period_id,omitempty"`
+
+	// Schedule
+	ShiftID        *uuid.UUID `gorm:"type:text" json:"shift_id,omitempty"`
+	ScheduleSource string     `gorm:"type:varchar(20);default:'none'" json:"schedule_source"`
+	ScheduledStart *time.Time `json:"scheduled_start,omitempty"`
+	ScheduledEnd   *time.Time `json:"scheduled_end,omitempty"`
+	ScheduledHours float64    `gorm:"type:decimal(5,2);default:0" json:"scheduled_hours"`
+	IsRestDay      bool       `gorm:"default:false" json:"is_rest_day"`
+	IsHoliday      bool       `gorm:"default:false" json:"is_holiday"`
+
+	// Punches
+	ActualIn    *time.Time `json:"actual_in,omitempty"`
+	ActualOut   *time.Time `json:"actual_out,omitempty"`
+	PunchSource string     `gorm:"type:varchar(50)" json:"punch_source,omitempty"` // clock, terminal
+	PunchCount  int        `gorm:"default:0" json:"punch_count"`
+
+	// Result
+	Status                    string    `gorm:"type:varchar(30);not null;index" json:"status"`
+	WorkedHours               float64   `gorm:"type:decimal(5,2);default:0" json:"worked_hours"`
+	LateMinutes               float64   `gorm:"type:decimal(6,2);default:0" json:"late_minutes"`
+	EarlyExitMinutes          float64   `gorm:"type:decimal(6,2);default:0" json:"early_exit_minutes"`
+	OvertimeHours             float64   `gorm:"type:decimal(5,2);default:0" json:"overtime_hours"`
+	UnauthorizedOvertimeHours float64   `gorm:"type:decimal(5,2);default:0" json:"unauthorized_overtime_hours"` // Not pre-authorized; not paid until HR overrides the day
+	BankedOvertimeHours       float64   `gorm:"type:decimal(5,2);default:0" json:"banked_overtime_hours"`       // Authorized to the time bank instead of paid
+	IsSunday                  bool      `gorm:"default:false" json:"is_sunday"`
+	Justification             string    `gorm:"type:varchar(255)" json:"justification,omitempty"`
+	EvaluatedAt               time.Time `json:"evaluated_at"`
+
+	// HR override
+	IsOverridden   bool           `gorm:"default:false" json:"is_overridden"`
+	OverrideReason string         `gorm:"type:text" json:"override_reason,omitempty"`
+	OverriddenBy   *uuid.UUID     `gorm:"type:text" json:"overridden_by,omitempty"`
+	OverriddenAt   *time.Time     `json:"overridden_at,omitempty"`
+	OriginalValues datatypes.JSON `gorm:"type:jsonb" json:"original_values,omitempty"`
+
+	// Relations
+	Employee *Employee `gorm:"foreignKey:EmployeeID" json:"employee,omitempty"`
+	Shift    *Shift    `gorm:"foreignKey:ShiftID" json:"shift,omitempty"`
+}
+
+// TableName specifies the table name
+func (AttendanceDay) TableName() string {
+	return "attendance_days"
+}
+
+// Worked reports whether the employee punched on the day
+func (d *AttendanceDay) Worked() bool {
+	return d.PunchCount > 0
+}
+
+// CountsAsAbsence reports whether the day is an unjustified absence
+func (d *AttendanceDay) CountsAsAbsence() bool {
+	return d.Status == AttendanceStatusAbsent
+}
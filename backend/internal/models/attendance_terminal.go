@@ -0,0 +1,104 @@
+/*
+Package models - IRIS Payroll System Data Models
+
+==============================================================================
+FILE: internal/models/attendance_terminal.go
+==============================================================================
+
+DESCRIPTION:
+    NFC attendance terminals. AttendanceCard links a card UID to an
+    employee; TerminalSwipe is the idempotent log of every swipe a terminal
+    sends (including swipes buffered offline and synced later). Accepted
+    swipes become ClockRecords with ClockInSource/ClockOutSource "terminal":
+    clock-in, break start, break end or clock-out, any number per workday.
+
+USER PERSPECTIVE:
+    - Employees tap their card; the terminal works out whether it is an
+      entrada, salida or the meal break from the scheduled shift
+    - Split shifts and night shifts keep every punch on the right workday
+    - HR enrolls cards at the terminal itself (with HR credentials)
+    - Swipes made while the network was down arrive later, in order
+
+DEVELOPER GUIDELINES:
+    ✅  OK to modify: Add card attributes (label, expiry)
+    ⚠️  CAUTION: SwipeID is generated by the terminal and is the idempotency
+        key; never regenerate it on the server
+    ❌  DO NOT modify: Processed swipes (they are the audit log)
+    📝  CardUID is stored upper-case hex, as the reader returns it
+
+SYNTAX EXPLANATION:
+    - Direction: in | break_start | break_end | out (empty when the swipe
+      was not accepted)
+    - WorkDate: the workday of the shift the punch belongs to, in the
+      company's time zone (a night shift's exit keeps the previous date)
+    - Status: accepted | ignored (repeated tap) | rejected (unknown card)
+
+==============================================================================
+*/
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Terminal swipe statuses
+const (
+	SwipeStatusAccepted = "accepted"
+	SwipeStatusIgnored  = "ignored"
+	SwipeStatusRejected = "rejected"
+)
+
+// Terminal swipe directions
+const (
+	SwipeDirectionIn         = "in"
+	SwipeDirectionOut        = "out"
+	SwipeDirectionBreakStart = "break_start"
+	SwipeDirectionBreakEnd   = "break_end"
+)
+
+// ClockSourceTerminal is the ClockRecord source for NFC terminal punches
+const ClockSourceTerminal = "terminal"
+
+// AttendanceCard is an NFC card enrolled to an employee
+type AttendanceCard struct {
+	BaseModel
+	CompanyID  uuid.UUID  `gorm:"type:text;not null;index" json:"company_id"`
+	CardUID    string     `gorm:"type:varchar(32);not null;uniqueIndex" json:"card_uid"`
+	EmployeeID uuid.UUID  `gorm:"type:text;not null;index" json:"employee_id"`
+	IsActive   bool       `gorm:"default:true" json:"is_active"`
+	TerminalID string     `gorm:"type:varchar(100)" json:"terminal_id,omitempty"` // Where it was enrolled
+	EnrolledBy *uuid.UUID `gorm:"type:text" json:"enrolled_by,omitempty"`
+	EnrolledAt time.Time  `json:"enrolled_at"`
+	Employee   *Employee  `gorm:"foreignKey:EmployeeID" json:"employee,omitempty"`
+}
+
+// TableName specifies the table name
+func (AttendanceCard) TableName() string {
+	return "attendance_cards"
+}
+
+// TerminalSwipe is one card swipe received from a terminal
+type TerminalSwipe struct {
+	BaseModel
+	CompanyID     uuid.UUID  `gorm:"type:text;not null;index" json:"company_id"`
+	SwipeID       uuid.UUID  `gorm:"type:text;not null;uniqueIndex" json:"swipe_id"`
+	TerminalID    string     `gorm:"type:varchar(100);not null;index" json:"terminal_id"`
+	CardUID       string     `gorm:"type:varchar(32);not null" json:"card_uid"`
+	EmployeeID    *uuid.UUID `gorm:"type:text;index" json:"employee_id,omitempty"`
+	SwipedAt      time.Time  `gorm:"not null;index" json:"swiped_at"`
+	WorkDate      *time.Time `gorm:"type:date;index" json:"work_date,omitempty"` // Set on accepted swipes
+	ReceivedAt    time.Time  `gorm:"not null" json:"received_at"`
+	Offline       bool       `gorm:"default:false" json:"offline"` // Buffered on the terminal before sync
+	Direction     string     `gorm:"type:varchar(20)" json:"direction,omitempty"`
+	Status        string     `gorm:"type:varchar(20);not null" json:"status"`
+	Message       string     `gorm:"type:varchar(255)" json:"message,omitempty"`
+	ClockRecordID *uuid.UUID `gorm:"type:text" json:"clock_record_id,omitempty"`
+	Employee      *Employee  `gorm:"foreignKey:EmployeeID" json:"employee,omitempty"`
+}
+
+// TableName specifies the table name
+func (TerminalSwipe) TableName() string {
+	return "terminal_swipes"
+}
@@ -24,6 +24,8 @@ DEVELOPER GUIDELINES:
 
 SYNTAX EXPLANATION:
     - ActivatedAt/DeactivatedAt: Track company status changes
+    - Timezone: IANA zone used to read shift times and attribute punches
+      to workdays (default America/Mexico_City)
     - BeforeCreate/BeforeUpdate: GORM hooks for automatic timestamp management
     - foreignKey:CompanyID: All users/employees reference this company
 
@@ -39,6 +41,7 @@ package models
 
 import (
 	"time"
+	_ "time/tzdata" // Company time zones must load on hosts without a zoneinfo database
 
 	"github.com/google/uuid"
 	"gorm.io/gorm"
@@ -55,6 +58,7 @@ type Company struct {
 	Email        string         `gorm:"type:varchar(255)" json:"email,omitempty"`
 	Website      string         `gorm:"type:varchar(255)" json:"website,omitempty"`
 	IsActive     bool           `gorm:"default:true" json:"is_active"`
+	Timezone     string         `gorm:"type:varchar(64);default:'America/Mexico_City'" json:"timezone"`
 	Users        []User         `gorm:"foreignKey:CompanyID" json:"users,omitempty"`
 	Employees    []Employee     `gorm:"foreignKey:CompanyID" json:"employees,omitempty"`
 	CreatedBy    *uuid.UUID     `gorm:"type:text" json:"created_by,omitempty"`
@@ -70,6 +74,18 @@ func (Company) TableName() string {
 	return "companies"
 }
 
+// Location returns the company's time zone, or the server's when it is unset or unknown
+func (c *Company) Location() *time.Location {
+	if c.Timezone == "" {
+		return time.Local
+	}
+	loc, err := time.LoadLocation(c.Timezone)
+	if err != nil {
+		return time.Local
+	}
+	return loc
+}
+
 // BeforeCreate hook to generate UUID and set ActivatedAt for new active companies.
 func (c *Company) BeforeCreate(tx *gorm.DB) (err error) {
 	// Generate UUID if not set (important since BaseModel's hook is overridden)
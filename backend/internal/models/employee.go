@@ -42,6 +42,8 @@ VALIDATION RULES:
     - CURP: 18 characters (Mexican unique population registry)
     - NSS: 11 digits (Social Security number)
     - DailySalary: Must be positive
+    - MinimumWageZone: general or northern_border_free_zone (the salary floor
+      itself is enforced by services.MinimumWageService, which knows the config)
 
 BUSINESS LOGIC:
     - GetVacationDays(): Returns vacation days based on Mexican labor law
@@ -154,6 +156,8 @@ type Employee struct {
     // Financial Information
     DailySalary      float64   `gorm:"type:decimal(12,2);not null" json:"daily_salary"`
     IntegratedDailySalary float64 `gorm:"type:decimal(12,2)" json:"integrated_daily_salary"`
+    MinimumWageZone  string    `gorm:"type:varchar(30);default:'general'" json:"minimum_wage_zone"` // general, northern_border_free_zone (ZLFN)
+    ProfessionalOccupation string `gorm:"type:varchar(20)" json:"professional_occupation,omitempty"` // CONASAMI professional minimum wage code
     PaymentMethod    string    `gorm:"type:varchar(50);default:'bank_transfer'" json:"payment_method"`
     BankName         string    `gorm:"type:varchar(100)" json:"bank_name,omitempty"`
     BankAccount      string    `gorm:"type:varchar(50)" json:"bank_account,omitempty"`
@@ -230,6 +234,9 @@ func (e *Employee) Validate() error {
     if e.DailySalary <= 0 {
         validationErrors = append(validationErrors, "daily salary must be positive")
     }
+    if e.MinimumWageZone != "" && !IsValidMinimumWageZone(e.MinimumWageZone) {
+        validationErrors = append(validationErrors, "invalid minimum wage zone")
+    }
     
     if len(validationErrors) > 0 {
         return errors.New(strings.Join(validationErrors, "; "))
@@ -326,6 +333,17 @@ func (e *Employee) BeforeSave(tx *gorm.DB) error {
     return nil
 }
 
+// Minimum wage zones (CONASAMI)
+const (
+    MinimumWageZoneGeneral                = "general"
+    MinimumWageZoneNorthernBorderFreeZone = "northern_border_free_zone"
+)
+
+// IsValidMinimumWageZone reports whether zone is a current minimum wage zone
+func IsValidMinimumWageZone(zone string) bool {
+    return zone == MinimumWageZoneGeneral || zone == MinimumWageZoneNorthernBorderFreeZone
+}
+
 // Helper validation functions
 func ValidateRFC(rfc string) bool {
     // RFC validation regex (12 or 13 characters)
@@ -63,6 +63,7 @@ const (
 	RoleSupAndGM     UserRole = "sup_and_gm"     // Supervisor + General Manager combined
 	RoleHRBlueGray   UserRole = "hr_blue_gray"   // HR for blue_collar and gray_collar employees only
 	RoleHRWhite      UserRole = "hr_white"       // HR for white_collar employees only
+	RoleTerminal     UserRole = "terminal"       // Service account of an NFC attendance terminal
 )
 
 // IsValid checks if the user role is valid.
@@ -70,7 +71,7 @@ func (ur UserRole) IsValid() bool {
 	switch ur {
 	case RoleAdmin, RoleHR, RoleAccountant, RolePayrollStaff, RoleViewer,
 		RoleSupervisor, RoleManager, RoleEmployee, RoleHRAndPR, RoleSupAndGM,
-		RoleHRBlueGray, RoleHRWhite:
+		RoleHRBlueGray, RoleHRWhite, RoleTerminal:
 		return true
 	}
 	return false
@@ -114,6 +115,8 @@ func (ur *UserRole) UnmarshalText(text []byte) error {
 		*ur = RoleHRBlueGray
 	case "hr_white":
 		*ur = RoleHRWhite
+	case "terminal":
+		*ur = RoleTerminal
 	default:
 		*ur = "" // Invalid role
 	}
@@ -0,0 +1,77 @@
+/*
+Package models - IRIS Payroll System Data Models
+
+==============================================================================
+FILE: internal/models/imss_movement.go
+==============================================================================
+
+DESCRIPTION:
+    Affiliate movements (movimientos afiliatorios) that must be reported to
+    IMSS through IDSE/SUA: registrations, salary modifications and
+    terminations. Each record is one movement waiting to be (or already)
+    submitted.
+
+USER PERSPECTIVE:
+    - HR sees the list of pending "Modificaciones de salario" to upload to IDSE
+    - Every salary change that alters the SDI produces one movement
+    - Once the IDSE acknowledgement is received the movement is marked submitted
+
+DEVELOPER GUIDELINES:
+    ✅  OK to modify: Add tracking fields (batch number, acknowledgement file)
+    ⚠️  CAUTION: Movement codes must match the IDSE catalog
+    ❌  DO NOT modify: Submitted movements (create a new one instead)
+    📝  IMSS requires salary modifications within 5 business days (LSS Art. 34)
+
+SYNTAX EXPLANATION:
+    - MovementType: registration (08), salary_modification (07), termination (02)
+    - PreviousSDI/NewSDI: Salario Diario Integrado before and after the change
+    - SalaryHistoryID: Link to the salary change that originated the movement
+
+==============================================================================
+*/
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// IMSS affiliate movement types
+const (
+	IMSSMovementRegistration       = "registration"
+	IMSSMovementSalaryModification = "salary_modification"
+	IMSSMovementTermination        = "termination"
+)
+
+// IMSSMovementCodes maps movement types to the IDSE movement code
+var IMSSMovementCodes = map[string]string{
+	IMSSMovementRegistration:       "08",
+	IMSSMovementSalaryModification: "07",
+	IMSSMovementTermination:        "02",
+}
+
+// IMSSMovement is an affiliate movement to be reported to IMSS
+type IMSSMovement struct {
+	BaseModel
+	EmployeeID          uuid.UUID  `gorm:"type:text;not null;index" json:"employee_id"`
+	CompanyID           uuid.UUID  `gorm:"type:text;index" json:"company_id"`
+	MovementType        string     `gorm:"type:varchar(30);not null" json:"movement_type"`
+	MovementCode        string     `gorm:"type:varchar(2);not null" json:"movement_code"`
+	EffectiveDate       time.Time  `gorm:"type:date;not null" json:"effective_date"`
+	PreviousDailySalary float64    `gorm:"type:decimal(12,2)" json:"previous_daily_salary"`
+	NewDailySalary      float64    `gorm:"type:decimal(12,2)" json:"new_daily_salary"`
+	PreviousSDI         float64    `gorm:"type:decimal(12,2)" json:"previous_sdi"`
+	NewSDI              float64    `gorm:"type:decimal(12,2)" json:"new_sdi"`
+	Reason              string     `gorm:"type:text" json:"reason,omitempty"`
+	Status              string     `gorm:"type:varchar(20);default:'pending'" json:"status"` // pending, submitted, rejected
+	SubmittedAt         *time.Time `json:"submitted_at,omitempty"`
+	SalaryHistoryID     *uuid.UUID `gorm:"type:text" json:"salary_history_id,omitempty"`
+	CreatedBy           *uuid.UUID `gorm:"type:text" json:"created_by,omitempty"`
+	Employee            *Employee  `gorm:"foreignKey:EmployeeID" json:"employee,omitempty"`
+}
+
+// TableName specifies the table name
+func (IMSSMovement) TableName() string {
+	return "imss_movements"
+}
@@ -24,6 +24,9 @@ DEVELOPER GUIDELINES:
 
 SYNTAX EXPLANATION:
     - Status workflow: pending → approved → processed (or rejected)
+    - Corrections: an incidence already paid is never edited; a corrective
+      incidence with the opposite quantity is posted in the next open
+      period instead (CorrectsIncidenceID)
     - EffectType: 'positive' (adds to pay), 'negative' (deducts), 'neutral'
     - CalculationMethod: How quantity converts to money
         * daily_rate: quantity × daily salary
@@ -77,6 +80,10 @@ type Incidence struct {
 	// Link back to the original absence request (if created from one)
 	AbsenceRequestID *uuid.UUID `gorm:"type:text" json:"absence_request_id,omitempty"`
 
+	// Reverses a processed incidence in a later period (negated Quantity and
+	// CalculatedAmount) when its absence request is cancelled or modified
+	CorrectsIncidenceID *uuid.UUID `gorm:"type:text;index" json:"corrects_incidence_id,omitempty"`
+
 	// Payroll Export Fields (NEW for dual Excel export system)
 	LateApprovalFlag     bool       `gorm:"default:false;index:idx_incidence_export,priority:1" json:"late_approval_flag"`     // Approved after payroll cutoff
 	ExcludedFromPayroll  bool       `gorm:"default:false;index:idx_incidence_export,priority:2" json:"excluded_from_payroll"` // HR/GM rejected, exclude from export
@@ -136,7 +143,7 @@ type IncidenceType struct {
 	Description       string         `gorm:"type:text" json:"description,omitempty"`
 	FormFields        datatypes.JSON `gorm:"type:jsonb" json:"form_fields,omitempty"`                                                             // Custom form field definitions (new)
 	IsRequestable     bool           `gorm:"default:false" json:"is_requestable"`                                                                 // Can employees request this type? (new)
-	ApprovalFlow      string         `gorm:"type:varchar(50);default:'standard'" json:"approval_flow"`                                            // Approval workflow type (new)
+	ApprovalFlow      string         `gorm:"type:varchar(50);default:'standard'" json:"approval_flow"`                                            // Code of the ApprovalWorkflow its requests follow
 	DisplayOrder      int            `gorm:"default:0" json:"display_order"`                                                                      // Order in UI (new)
 
 	// Relations
@@ -0,0 +1,106 @@
+/*
+Package models - IRIS Payroll System Data Models
+
+==============================================================================
+FILE: internal/models/leave_policy.go
+==============================================================================
+
+DESCRIPTION:
+    LeavePolicy is a company-defined leave beyond vacation: maternity,
+    paternity, bereavement, marriage, union commissions and any other leave
+    the law or the collective contract grants. It says who may take it
+    (gender, seniority, union status), how many days (per event or per
+    calendar year), the documents that prove it and who pays the days.
+    Employees file it as an absence request of type LEAVE with the policy.
+
+USER PERSPECTIVE:
+    - HR adds or adjusts leaves without a deploy; every company starts
+      with the leaves of the LFT and the usual contract clauses
+    - Employees only see the leaves they are eligible for, with the days
+      they have left
+    - A request over the entitlement, or approved without its documents,
+      is refused
+
+DEVELOPER GUIDELINES:
+    ✅  OK to modify: Add eligibility rules
+    ⚠️  CAUTION: Each policy owns an IncidenceType ("Licencia: <name>") that
+        its requests post to payroll; PaidBy decides its effect
+    ⚠️  CAUTION: Maternity days paid by the IMSS are also registered as a
+        maternity SickLeaveCertificate; the certificate drives the subsidy
+    📝  Code is unique per company
+
+SYNTAX EXPLANATION:
+    - EntitlementBasis: per_event (EntitlementDays per request, at most
+      MaxEventsPerYear requests a calendar year; 0 = no limit) or per_year
+      (EntitlementDays per calendar year, in as many requests as needed)
+    - Genders: male / female / other; empty = anyone
+    - CountsWorkDays: the days of a request are the employee's work days
+      in its range (holidays and rest days excluded), e.g. paternity's
+      "días laborables"; otherwise calendar days, e.g. maternity
+    - MinServiceMonths: completed months since HireDate on the first day
+    - UnionStatus: any / union (IsSindicalizado) / non_union
+    - PaidBy: company (paid, no deduction), imss (not paid by the company,
+      deducted), unpaid (deducted)
+    - RequiredDocuments: names of the documents attached as evidence to
+      the request's incidence before it can be approved
+
+==============================================================================
+*/
+package models
+
+import (
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+// Leave entitlement bases
+const (
+	LeaveEntitlementPerEvent = "per_event"
+	LeaveEntitlementPerYear  = "per_year"
+)
+
+// Leave union eligibility
+const (
+	LeaveUnionAny      = "any"
+	LeaveUnionOnly     = "union"
+	LeaveUnionNonUnion = "non_union"
+)
+
+// Who pays the days of a leave
+const (
+	LeavePaidByCompany = "company"
+	LeavePaidByIMSS    = "imss"
+	LeaveUnpaid        = "unpaid"
+)
+
+// LeavePolicy is a configurable leave with its eligibility and entitlement
+type LeavePolicy struct {
+	BaseModel
+	CompanyID         uuid.UUID      `gorm:"type:text;not null;index" json:"company_id"`
+	Code              string         `gorm:"type:varchar(50);not null" json:"code"`
+	Name              string         `gorm:"type:varchar(150);not null" json:"name"`
+	Description       string         `gorm:"type:text" json:"description,omitempty"`
+	LegalReference    string         `gorm:"type:varchar(255)" json:"legal_reference,omitempty"` // e.g. "LFT Art. 132 XXVII Bis"
+	EntitlementDays   float64        `gorm:"type:decimal(6,2);not null" json:"entitlement_days"`
+	EntitlementBasis  string         `gorm:"type:varchar(20);not null;default:'per_event'" json:"entitlement_basis"`
+	MaxEventsPerYear  int            `gorm:"default:0" json:"max_events_per_year"`  // per_event only; 0 = no limit
+	CountsWorkDays    bool           `gorm:"default:false" json:"counts_work_days"` // Days are work days, not calendar days
+	Genders           pq.StringArray `gorm:"type:text[]" json:"genders,omitempty"`
+	MinServiceMonths  int            `gorm:"default:0" json:"min_service_months"`
+	UnionStatus       string         `gorm:"type:varchar(20);not null;default:'any'" json:"union_status"`
+	RequiredDocuments pq.StringArray `gorm:"type:text[]" json:"required_documents,omitempty"`
+	PaidBy            string         `gorm:"type:varchar(20);not null;default:'company'" json:"paid_by"`
+	IncidenceTypeID   *uuid.UUID     `gorm:"type:text" json:"incidence_type_id,omitempty"`
+	IsActive          bool           `gorm:"default:true" json:"is_active"`
+	IncidenceType     *IncidenceType `gorm:"foreignKey:IncidenceTypeID" json:"incidence_type,omitempty"`
+}
+
+// TableName specifies the table name
+func (LeavePolicy) TableName() string {
+	return "leave_policies"
+}
+
+// IsPaid reports whether the company pays the days of the leave
+func (p *LeavePolicy) IsPaid() bool {
+	return p.PaidBy == LeavePaidByCompany
+}
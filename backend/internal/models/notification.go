@@ -37,6 +37,8 @@ NOTIFICATION TYPES:
     - incidence_rejected: Incidence rejected
     - payroll_calculated: Payroll calculation completed
     - period_created: New payroll period created
+    - timesheet_reminder: Timesheet not yet submitted, deadline approaching
+    - project_budget_alert: Project or task crossed a budget threshold
 
 ==============================================================================
 */
@@ -61,6 +63,8 @@ const (
 	NotificationPayrollCalculated NotificationType = "payroll_calculated"
 	NotificationPeriodCreated     NotificationType = "period_created"
 	NotificationUserCreated       NotificationType = "user_created"
+	NotificationTimesheetReminder NotificationType = "timesheet_reminder"
+	NotificationProjectBudget     NotificationType = "project_budget_alert"
 )
 
 // Notification represents an alert or update for company users
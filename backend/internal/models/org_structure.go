@@ -0,0 +1,72 @@
+/*
+Package models - IRIS Payroll System Data Models
+
+==============================================================================
+FILE: internal/models/org_structure.go
+==============================================================================
+
+DESCRIPTION:
+    Reporting lines between employees. ReportingLine keeps the
+    effective-dated history of who each employee reports to, and
+    EmployeeHierarchy is a closure table (one row per ancestor/descendant
+    pair) so a whole subtree can be read with a single query.
+
+USER PERSPECTIVE:
+    - HR changes an employee's manager, today or from a future date
+    - Managers see their whole team without slow, level-by-level lookups
+    - The org chart shows every manager with the headcount below them
+
+DEVELOPER GUIDELINES:
+    ✅  OK to modify: Add attributes to ReportingLine (dotted lines, matrix)
+    ⚠️  CAUTION: EmployeeHierarchy is derived data; only OrgStructureService
+        writes it (RebuildHierarchy regenerates it from Employee.SupervisorID)
+    ❌  DO NOT modify: Closed reporting lines (historical record)
+    📝  Employee.SupervisorID mirrors the current reporting line
+
+SYNTAX EXPLANATION:
+    - Depth 0: the employee itself; 1: direct report; 2+: indirect report
+    - ManagerID nil on a ReportingLine: the employee is a root (no manager)
+    - Applied: false while a future-dated line waits for its date
+
+==============================================================================
+*/
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ReportingLine is one effective-dated "employee reports to manager" record
+type ReportingLine struct {
+	BaseModel
+	CompanyID     uuid.UUID  `gorm:"type:text;not null;index" json:"company_id"`
+	EmployeeID    uuid.UUID  `gorm:"type:text;not null;index" json:"employee_id"`
+	ManagerID     *uuid.UUID `gorm:"type:text;index" json:"manager_id,omitempty"`
+	EffectiveFrom time.Time  `gorm:"type:date;not null" json:"effective_from"`
+	EffectiveTo   *time.Time `gorm:"type:date" json:"effective_to,omitempty"`
+	Applied       bool       `gorm:"default:false" json:"applied"`
+	Reason        string     `gorm:"type:text" json:"reason,omitempty"`
+	CreatedBy     *uuid.UUID `gorm:"type:text" json:"created_by,omitempty"`
+	Employee      *Employee  `gorm:"foreignKey:EmployeeID" json:"employee,omitempty"`
+	Manager       *Employee  `gorm:"foreignKey:ManagerID" json:"manager,omitempty"`
+}
+
+// TableName specifies the table name
+func (ReportingLine) TableName() string {
+	return "reporting_lines"
+}
+
+// EmployeeHierarchy is a closure-table row: Descendant is Depth levels below Ancestor
+type EmployeeHierarchy struct {
+	AncestorID   uuid.UUID `gorm:"type:text;primaryKey" json:"ancestor_id"`
+	DescendantID uuid.UUID `gorm:"type:text;primaryKey;index" json:"descendant_id"`
+	Depth        int       `gorm:"not null" json:"depth"`
+	CompanyID    uuid.UUID `gorm:"type:text;not null;index" json:"company_id"`
+}
+
+// TableName specifies the table name
+func (EmployeeHierarchy) TableName() string {
+	return "employee_hierarchy"
+}
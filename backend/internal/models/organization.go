@@ -0,0 +1,178 @@
+/*
+Package models - IRIS Payroll System Data Models
+
+==============================================================================
+FILE: internal/models/organization.go
+==============================================================================
+
+DESCRIPTION:
+    Organizational catalog: departments (a tree per company), salary grades
+    with min/mid/max pay bands, positions (job codes) that belong to a
+    department and a grade, and the effective-dated history of each
+    employee's department/position assignment.
+
+USER PERSPECTIVE:
+    - HR maintains the department tree and the position catalog
+    - Compensation defines the pay band of each grade
+    - Moving an employee to another position keeps the previous one in history
+    - Salary updates outside the position's band are warned about or blocked
+
+DEVELOPER GUIDELINES:
+    ✅  OK to modify: Add catalog attributes (headcount budget, location)
+    ⚠️  CAUTION: Employee.DepartmentID/PositionID mirror the current assignment
+    ❌  DO NOT modify: Closed assignments (they are the historical record)
+    📝  Band values are daily salaries (MXN), like Employee.DailySalary
+
+SYNTAX EXPLANATION:
+    - ParentID: nil for root departments
+    - BandPolicy: "warn" (default) allows the update, "block" rejects it
+    - Compa-ratio: salary / midpoint (1.00 = at midpoint)
+    - Range penetration: (salary - min) / (max - min) (0 = min, 1 = max)
+    - EffectiveTo: nil means the assignment is current (or pending if future)
+
+MULTI-TENANCY:
+    - Every catalog is company-specific; always filter by CompanyID
+
+==============================================================================
+*/
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// Pay band policies for salaries outside a grade's range
+const (
+	PayBandPolicyWarn  = "warn"
+	PayBandPolicyBlock = "block"
+)
+
+// Department is a node of the company's organizational tree
+type Department struct {
+	BaseModel
+	CompanyID    uuid.UUID    `gorm:"type:text;not null;index" json:"company_id"`
+	ParentID     *uuid.UUID   `gorm:"type:text;index" json:"parent_id,omitempty"`
+	Code         string       `gorm:"type:varchar(30);not null" json:"code"`
+	Name         string       `gorm:"type:varchar(255);not null" json:"name"`
+	Description  string       `gorm:"type:text" json:"description,omitempty"`
+	CostCenterID *uuid.UUID   `gorm:"type:text" json:"cost_center_id,omitempty"`
+	ManagerID    *uuid.UUID   `gorm:"type:text" json:"manager_id,omitempty"`
+	IsActive     bool         `gorm:"default:true" json:"is_active"`
+	Parent       *Department  `gorm:"foreignKey:ParentID" json:"parent,omitempty"`
+	Children     []Department `gorm:"foreignKey:ParentID" json:"children,omitempty"`
+	Manager      *Employee    `gorm:"foreignKey:ManagerID" json:"manager,omitempty"`
+}
+
+// TableName specifies the table name
+func (Department) TableName() string {
+	return "departments"
+}
+
+// BeforeCreate hook to validate department data before creation.
+func (d *Department) BeforeCreate(tx *gorm.DB) (err error) {
+	if err = d.BaseModel.BeforeCreate(tx); err != nil {
+		return
+	}
+	if d.Name == "" {
+		return ErrNameRequired
+	}
+	if d.CompanyID == uuid.Nil {
+		return ErrCompanyIDRequired
+	}
+	return
+}
+
+// SalaryGrade is a pay grade with its min/mid/max daily salary band
+type SalaryGrade struct {
+	BaseModel
+	CompanyID      uuid.UUID `gorm:"type:text;not null;index" json:"company_id"`
+	Code           string    `gorm:"type:varchar(20);not null" json:"code"`
+	Name           string    `gorm:"type:varchar(255)" json:"name,omitempty"`
+	Level          int       `gorm:"default:0" json:"level"`
+	MinDailySalary float64   `gorm:"type:decimal(12,2);not null" json:"min_daily_salary"`
+	MidDailySalary float64   `gorm:"type:decimal(12,2);not null" json:"mid_daily_salary"`
+	MaxDailySalary float64   `gorm:"type:decimal(12,2);not null" json:"max_daily_salary"`
+	BandPolicy     string    `gorm:"type:varchar(10);default:'warn'" json:"band_policy"`
+	IsActive       bool      `gorm:"default:true" json:"is_active"`
+}
+
+// TableName specifies the table name
+func (SalaryGrade) TableName() string {
+	return "salary_grades"
+}
+
+// Contains reports whether a daily salary is inside the band
+func (g *SalaryGrade) Contains(dailySalary float64) bool {
+	return dailySalary >= g.MinDailySalary && dailySalary <= g.MaxDailySalary
+}
+
+// CompaRatio returns salary / midpoint (0 when the grade has no midpoint)
+func (g *SalaryGrade) CompaRatio(dailySalary float64) float64 {
+	if g.MidDailySalary <= 0 {
+		return 0
+	}
+	return dailySalary / g.MidDailySalary
+}
+
+// RangePenetration returns where the salary sits in the band (0 = min, 1 = max)
+func (g *SalaryGrade) RangePenetration(dailySalary float64) float64 {
+	spread := g.MaxDailySalary - g.MinDailySalary
+	if spread <= 0 {
+		return 0
+	}
+	return (dailySalary - g.MinDailySalary) / spread
+}
+
+// Position is a job in the catalog, identified by its job code
+type Position struct {
+	BaseModel
+	CompanyID     uuid.UUID    `gorm:"type:text;not null;index" json:"company_id"`
+	JobCode       string       `gorm:"type:varchar(30);not null" json:"job_code"`
+	Title         string       `gorm:"type:varchar(255);not null" json:"title"`
+	Description   string       `gorm:"type:text" json:"description,omitempty"`
+	DepartmentID  *uuid.UUID   `gorm:"type:text;index" json:"department_id,omitempty"`
+	SalaryGradeID *uuid.UUID   `gorm:"type:text;index" json:"salary_grade_id,omitempty"`
+	CollarType    string       `gorm:"type:varchar(20)" json:"collar_type,omitempty"`
+	Headcount     int          `gorm:"default:0" json:"headcount"` // authorized seats, 0 = unlimited
+	IsActive      bool         `gorm:"default:true" json:"is_active"`
+	Department    *Department  `gorm:"foreignKey:DepartmentID" json:"department,omitempty"`
+	SalaryGrade   *SalaryGrade `gorm:"foreignKey:SalaryGradeID" json:"salary_grade,omitempty"`
+}
+
+// TableName specifies the table name
+func (Position) TableName() string {
+	return "positions"
+}
+
+// EmployeeAssignment is one effective-dated department/position assignment
+type EmployeeAssignment struct {
+	BaseModel
+	CompanyID     uuid.UUID   `gorm:"type:text;not null;index" json:"company_id"`
+	EmployeeID    uuid.UUID   `gorm:"type:text;not null;index" json:"employee_id"`
+	DepartmentID  *uuid.UUID  `gorm:"type:text" json:"department_id,omitempty"`
+	PositionID    *uuid.UUID  `gorm:"type:text" json:"position_id,omitempty"`
+	EffectiveFrom time.Time   `gorm:"type:date;not null" json:"effective_from"`
+	EffectiveTo   *time.Time  `gorm:"type:date" json:"effective_to,omitempty"`
+	Applied       bool        `gorm:"default:false" json:"applied"` // copied to the employee record
+	Reason        string      `gorm:"type:text" json:"reason,omitempty"`
+	CreatedBy     *uuid.UUID  `gorm:"type:text" json:"created_by,omitempty"`
+	Employee      *Employee   `gorm:"foreignKey:EmployeeID" json:"employee,omitempty"`
+	Department    *Department `gorm:"foreignKey:DepartmentID" json:"department,omitempty"`
+	Position      *Position   `gorm:"foreignKey:PositionID" json:"position,omitempty"`
+}
+
+// TableName specifies the table name
+func (EmployeeAssignment) TableName() string {
+	return "employee_assignments"
+}
+
+// IsActiveOn reports whether the assignment is in effect on the given date
+func (a *EmployeeAssignment) IsActiveOn(date time.Time) bool {
+	if date.Before(a.EffectiveFrom) {
+		return false
+	}
+	return a.EffectiveTo == nil || !date.After(*a.EffectiveTo)
+}
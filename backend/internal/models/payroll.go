@@ -90,6 +90,10 @@ type PayrollCalculation struct {
 	SavingsFund        float64 `gorm:"type:decimal(15,2);default:0" json:"savings_fund"`
 	EmploymentSubsidy  float64 `gorm:"type:decimal(15,2);default:0" json:"employment_subsidy"` // ISR Employment Subsidy
 
+	// Minimum wage protection (LISR Art. 96, LFT Art. 97)
+	MinimumWageEarner  bool    `gorm:"default:false" json:"minimum_wage_earner"`                 // No ISR withheld on the minimum wage
+	DeferredDeductions float64 `gorm:"type:decimal(15,2);default:0" json:"deferred_deductions"` // Discretionary deductions not applied to keep net pay at the minimum wage
+
 	// Totals
 	TotalGrossIncome   float64 `gorm:"type:decimal(15,2);default:0" json:"total_gross_income"`
 	TotalStatutoryDeductions float64 `gorm:"type:decimal(15,2);default:0" json:"total_statutory_deductions"`
@@ -147,23 +151,32 @@ type PrenominaMetric struct {
 	OvertimeHours        float64 `gorm:"type:decimal(5,2);default:0" json:"overtime_hours"`
 	DoubleOvertimeHours  float64 `gorm:"type:decimal(5,2);default:0" json:"double_overtime_hours"`
 	TripleOvertimeHours  float64 `gorm:"type:decimal(5,2);default:0" json:"triple_overtime_hours"`
+	UnauthorizedOvertimeHours float64 `gorm:"type:decimal(5,2);default:0" json:"unauthorized_overtime_hours"` // Worked without pre-authorization; for review, not paid
+	PaidPermitHours      float64 `gorm:"type:decimal(5,2);default:0" json:"paid_permit_hours"` // Approved paid hourly permits, included in RegularHours
 
 	// Leave metrics
 	AbsenceDays          float64 `gorm:"type:decimal(5,2);default:0" json:"absence_days"`
 	SickDays             float64 `gorm:"type:decimal(5,2);default:0" json:"sick_days"`
 	VacationDays         float64 `gorm:"type:decimal(5,2);default:0" json:"vacation_days"`
 	UnpaidLeaveDays      float64 `gorm:"type:decimal(5,2);default:0" json:"unpaid_leave_days"`
+	CompanyPaidSickDays  float64 `gorm:"type:decimal(5,2);default:0" json:"company_paid_sick_days"` // IMSS incapacidad days paid by the company (waiting days)
+	SubsidizedSickDays   float64 `gorm:"type:decimal(5,2);default:0" json:"subsidized_sick_days"`   // IMSS incapacidad days subsidized by the IMSS
+	ContributionDays     float64 `gorm:"type:decimal(5,2);default:0" json:"contribution_days"`      // Period days minus incapacidad days (IMSS cotización)
 
 	// Other metrics
 	DelaysCount          int     `gorm:"default:0" json:"delays_count"`
 	DelayMinutes         float64 `gorm:"type:decimal(5,2);default:0" json:"delay_minutes"`
 	EarlyDeparturesCount int     `gorm:"default:0" json:"early_departures_count"`
+	SundaysWorked        int     `gorm:"default:0" json:"sundays_worked"`
+	RestDaysWorked       int     `gorm:"default:0" json:"rest_days_worked"` // Rest days and holidays worked
 
 	// Monetary amounts (pre-calculated)
 	RegularSalary        float64 `gorm:"type:decimal(15,2);default:0" json:"regular_salary"`
 	OvertimeAmount       float64 `gorm:"type:decimal(15,2);default:0" json:"overtime_amount"`
 	DoubleOvertimeAmount float64 `gorm:"type:decimal(15,2);default:0" json:"double_overtime_amount"`
 	TripleOvertimeAmount float64 `gorm:"type:decimal(15,2);default:0" json:"triple_overtime_amount"`
+	SundayPremiumAmount  float64 `gorm:"type:decimal(15,2);default:0" json:"sunday_premium_amount"`
+	RestDayWorkedAmount  float64 `gorm:"type:decimal(15,2);default:0" json:"rest_day_worked_amount"`
 	BonusAmount          float64 `gorm:"type:decimal(15,2);default:0" json:"bonus_amount"`
 	CommissionAmount     float64 `gorm:"type:decimal(15,2);default:0" json:"commission_amount"`
 	OtherExtraAmount     float64 `gorm:"type:decimal(15,2);default:0" json:"other_extra_amount"`
@@ -38,7 +38,8 @@ STATUS WORKFLOW:
 BUSINESS RULES:
     - Period can only be closed after being paid
     - StartDate must be before EndDate
-    - EndDate must be before PaymentDate
+    - EndDate must be before PaymentDate, except when the payment date was
+      moved back to the previous business day (weekend/holiday)
     - IsFiscalClosing marks periods for tax reporting
 
 ==============================================================================
@@ -86,6 +87,8 @@ type PayrollPeriod struct {
 
     PaymentDate time.Time `gorm:"type:date;not null" json:"payment_date"`
 
+    PrenominaCutoffDate *time.Time `gorm:"type:date" json:"prenomina_cutoff_date,omitempty"` // Last day to capture incidences
+
     
 
     // Status
@@ -171,8 +174,10 @@ func (pp *PayrollPeriod) Validate() error {
     if !pp.StartDate.Before(pp.EndDate) && !pp.StartDate.Equal(pp.EndDate) {
         validationErrors = append(validationErrors, "start date must be before or equal to end date")
     }
-    if pp.EndDate.After(pp.PaymentDate) {
-        validationErrors = append(validationErrors, "end date must be before or equal to payment date")
+    // Payment may precede EndDate when the due date (e.g. the 15th) falls on a
+    // weekend or holiday and is moved to the previous business day
+    if pp.StartDate.After(pp.PaymentDate) {
+        validationErrors = append(validationErrors, "payment date cannot be before start date")
     }
     
     // Period type validation
@@ -0,0 +1,75 @@
+/*
+Package models - IRIS Payroll System Data Models
+
+==============================================================================
+FILE: internal/models/project_cost.go
+==============================================================================
+
+DESCRIPTION:
+    Budget alerts of time-tracking projects. Project and ProjectTask carry a
+    budget in hours and money; ProjectCostService compares it with the cost
+    of the approved time entries and records a ProjectBudgetAlert the first
+    time a project or task crosses each threshold, so the project manager is
+    notified once per threshold.
+
+USER PERSPECTIVE:
+    - The project manager is told when a project or task reaches 80% of its
+      budget and again when it is exceeded
+    - Finance reviews the alert history of a project
+
+DEVELOPER GUIDELINES:
+    ✅  OK to modify: Add alert metrics
+    ⚠️  CAUTION: Alerts are never re-raised for the same threshold; deleting
+        one makes the next check raise it again
+    📝  TaskID is nil for project-level alerts
+
+SYNTAX EXPLANATION:
+    - Metric: "hours" (actual vs. BudgetHours) or "amount" (cost vs. BudgetAmount)
+    - Threshold: percent of the budget (80, 100)
+    - Basis: how the cost was measured, "rate" (member rate) or "payroll"
+      (employee's loaded payroll cost)
+
+==============================================================================
+*/
+package models
+
+import (
+	"github.com/google/uuid"
+)
+
+// Budget alert metrics
+const (
+	BudgetMetricHours  = "hours"
+	BudgetMetricAmount = "amount"
+)
+
+// Project cost bases
+const (
+	CostBasisRate    = "rate"
+	CostBasisPayroll = "payroll"
+)
+
+// ProjectBudgetAlert records a project or task crossing a budget threshold
+type ProjectBudgetAlert struct {
+	BaseModel
+	CompanyID   uuid.UUID  `gorm:"type:text;not null;index" json:"company_id"`
+	ProjectID   uuid.UUID  `gorm:"type:text;not null;index" json:"project_id"`
+	TaskID      *uuid.UUID `gorm:"type:text;index" json:"task_id,omitempty"`
+	Metric      string     `gorm:"type:varchar(20);not null" json:"metric"`
+	Threshold   int        `gorm:"not null" json:"threshold"`
+	Basis       string     `gorm:"type:varchar(20);not null" json:"basis"`
+	Budget      float64    `gorm:"type:decimal(15,2);default:0" json:"budget"`
+	Actual      float64    `gorm:"type:decimal(15,2);default:0" json:"actual"`
+	PercentUsed float64    `gorm:"type:decimal(7,2);default:0" json:"percent_used"`
+
+	NotifiedUserID *uuid.UUID `gorm:"type:text" json:"notified_user_id,omitempty"`
+
+	// Relationships
+	Project *Project     `gorm:"foreignKey:ProjectID;constraint:OnDelete:CASCADE" json:"project,omitempty"`
+	Task    *ProjectTask `gorm:"foreignKey:TaskID" json:"task,omitempty"`
+}
+
+// TableName specifies the table name
+func (ProjectBudgetAlert) TableName() string {
+	return "project_budget_alerts"
+}
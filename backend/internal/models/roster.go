@@ -0,0 +1,204 @@
+/*
+Package models - IRIS Payroll System Data Models
+
+==============================================================================
+FILE: internal/models/roster.go
+==============================================================================
+
+DESCRIPTION:
+    Rotating shift schedules. A RotationPattern is an N-day cycle of shifts
+    and rest days (4x3, 6x1, morning/afternoon/night) anchored on a date;
+    RotationAssignment puts a team (Employee.TeamName) or a single employee
+    on a pattern from a date. RosterSwap is a change of one day's shift,
+    filed directly or offered by an employee on the swap marketplace, and
+    applied as ShiftExceptions once approved. StaffingRule sets the minimum
+    headcount (or the maximum share absent) of a production area,
+    department or shift that the roster and new absence requests are
+    checked against; BlackoutPeriod closes dates to leave requests.
+
+USER PERSPECTIVE:
+    - HR defines "4x3" once and puts teams A and B on it, one starting four
+      days after the other
+    - Planners see who works each day and where staffing falls short
+    - Two operators trade a day; once approved, the roster and the
+      attendance evaluation follow the trade
+    - An operator offers Saturday's shift; an eligible colleague takes it
+      and the supervisor approves
+    - A vacation request that would leave line 3 below four operators, or
+      that falls in inventory week, is flagged or refused when filed
+
+DEVELOPER GUIDELINES:
+    ✅  OK to modify: Add pattern attributes, staffing rule dimensions
+    ⚠️  CAUTION: Changing a pattern's days or anchor reshapes every roster
+        generated from it, past dates included
+    ⚠️  CAUTION: Precedence is ShiftException > employee assignment > team
+        assignment > EmployeeShiftBase > Employee.ShiftID
+    📝  The roster itself is not stored; RosterService generates it on demand
+
+SYNTAX EXPLANATION:
+    - Cycle day of a date = (days since AnchorDate + Offset) mod CycleLengthDays
+    - RotationPatternDay.ShiftID nil: rest day
+    - StaffingRule.ShiftID / DayOfWeek nil: any shift / every day
+      (DayOfWeek 0=Monday, as in EmployeeShiftBase)
+    - StaffingRule / BlackoutPeriod ProductionArea empty, DepartmentID nil:
+      any area / any department
+    - StaffingRule.MinEmployees 0: no minimum (percentage-only rule, not
+      shown as a roster gap); MaxAbsentPercent 0: no percentage limit
+    - Enforcement: warn (the request goes through with a warning) or block
+    - BlackoutPeriod.RequestTypes empty: every leave type but sick leave
+    - RosterSwap with CounterpartID: both employees trade their shifts for
+      the day; without it the employee moves to NewShiftID
+    - Marketplace (IsOffer): open → accepted (a colleague takes the shift)
+      → approved / rejected by the supervisor; cancelled by the offerer.
+      Direct swaps go pending → approved / rejected
+
+==============================================================================
+*/
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+// Roster swap statuses
+const (
+	RosterSwapOpen      = "open"     // Offered on the marketplace, nobody took it yet
+	RosterSwapAccepted  = "accepted" // A colleague took the offer; waits for the supervisor
+	RosterSwapPending   = "pending"
+	RosterSwapApproved  = "approved"
+	RosterSwapRejected  = "rejected"
+	RosterSwapCancelled = "cancelled"
+)
+
+// Enforcement of staffing rules and blackout periods on absence requests
+const (
+	CoverageWarn  = "warn"
+	CoverageBlock = "block"
+)
+
+// RotationPattern is an N-day cycle of shifts and rest days
+type RotationPattern struct {
+	BaseModel
+	CompanyID       uuid.UUID            `gorm:"type:text;not null;index" json:"company_id"`
+	Name            string               `gorm:"type:varchar(100);not null" json:"name"`
+	Code            string               `gorm:"type:varchar(20);not null" json:"code"`
+	Description     string               `gorm:"type:varchar(255)" json:"description,omitempty"`
+	CycleLengthDays int                  `gorm:"not null" json:"cycle_length_days"`
+	AnchorDate      time.Time            `gorm:"type:date;not null" json:"anchor_date"` // Day 0 of the cycle
+	IsActive        bool                 `gorm:"default:true" json:"is_active"`
+	CreatedBy       *uuid.UUID           `gorm:"type:text" json:"created_by,omitempty"`
+	Days            []RotationPatternDay `gorm:"foreignKey:PatternID" json:"days,omitempty"`
+}
+
+// TableName specifies the table name
+func (RotationPattern) TableName() string {
+	return "rotation_patterns"
+}
+
+// RotationPatternDay is the shift of one day of a rotation cycle
+type RotationPatternDay struct {
+	BaseModel
+	PatternID uuid.UUID  `gorm:"type:text;not null;uniqueIndex:idx_rotation_day" json:"pattern_id"`
+	DayIndex  int        `gorm:"not null;uniqueIndex:idx_rotation_day" json:"day_index"`
+	ShiftID   *uuid.UUID `gorm:"type:text" json:"shift_id,omitempty"` // Nil: rest day
+	Shift     *Shift     `gorm:"foreignKey:ShiftID" json:"shift,omitempty"`
+}
+
+// TableName specifies the table name
+func (RotationPatternDay) TableName() string {
+	return "rotation_pattern_days"
+}
+
+// RotationAssignment puts a team or an employee on a rotation pattern
+type RotationAssignment struct {
+	BaseModel
+	CompanyID     uuid.UUID        `gorm:"type:text;not null;index" json:"company_id"`
+	PatternID     uuid.UUID        `gorm:"type:text;not null;index" json:"pattern_id"`
+	TeamName      string           `gorm:"type:varchar(100);index" json:"team_name,omitempty"` // Matches Employee.TeamName
+	EmployeeID    *uuid.UUID       `gorm:"type:text;index" json:"employee_id,omitempty"`       // Overrides the team's assignment
+	OffsetDays    int              `gorm:"default:0" json:"offset_days"`                       // Shifts the cycle, e.g. team B 4 days after team A
+	EffectiveFrom time.Time        `gorm:"type:date;not null" json:"effective_from"`
+	EffectiveTo   *time.Time       `gorm:"type:date" json:"effective_to,omitempty"`
+	CreatedBy     *uuid.UUID       `gorm:"type:text" json:"created_by,omitempty"`
+	Pattern       *RotationPattern `gorm:"foreignKey:PatternID" json:"pattern,omitempty"`
+	Employee      *Employee        `gorm:"foreignKey:EmployeeID" json:"employee,omitempty"`
+}
+
+// TableName specifies the table name
+func (RotationAssignment) TableName() string {
+	return "rotation_assignments"
+}
+
+// RosterSwap is a requested change of one employee's shift on one day
+type RosterSwap struct {
+	BaseModel
+	CompanyID      uuid.UUID  `gorm:"type:text;not null;index" json:"company_id"`
+	EmployeeID     uuid.UUID  `gorm:"type:text;not null;index" json:"employee_id"`
+	CounterpartID  *uuid.UUID `gorm:"type:text;index" json:"counterpart_id,omitempty"` // Colleague trading the day
+	Date           time.Time  `gorm:"type:date;not null;index" json:"date"`
+	NewShiftID     *uuid.UUID `gorm:"type:text" json:"new_shift_id,omitempty"` // Without a counterpart
+	IsOffer        bool       `gorm:"default:false;index" json:"is_offer"`
+	OfferedShiftID *uuid.UUID `gorm:"type:text" json:"offered_shift_id,omitempty"` // Shift the offerer had when offering
+	AcceptedAt     *time.Time `json:"accepted_at,omitempty"`
+	Reason         string     `gorm:"type:text" json:"reason,omitempty"`
+	Status         string     `gorm:"type:varchar(20);not null;default:'pending';index" json:"status"`
+	RequestedBy    uuid.UUID  `gorm:"type:text;not null" json:"requested_by"`
+	ReviewedBy     *uuid.UUID `gorm:"type:text" json:"reviewed_by,omitempty"`
+	ReviewedAt     *time.Time `json:"reviewed_at,omitempty"`
+	ReviewNotes    string     `gorm:"type:text" json:"review_notes,omitempty"`
+	Employee       *Employee  `gorm:"foreignKey:EmployeeID" json:"employee,omitempty"`
+	Counterpart    *Employee  `gorm:"foreignKey:CounterpartID" json:"counterpart,omitempty"`
+	NewShift       *Shift     `gorm:"foreignKey:NewShiftID" json:"new_shift,omitempty"`
+	OfferedShift   *Shift     `gorm:"foreignKey:OfferedShiftID" json:"offered_shift,omitempty"`
+}
+
+// TableName specifies the table name
+func (RosterSwap) TableName() string {
+	return "roster_swaps"
+}
+
+// StaffingRule is the minimum coverage of a production area, department or shift
+type StaffingRule struct {
+	BaseModel
+	CompanyID        uuid.UUID   `gorm:"type:text;not null;index" json:"company_id"`
+	ProductionArea   string      `gorm:"type:varchar(100);not null" json:"production_area"` // Matches Employee.ProductionArea; empty = any
+	DepartmentID     *uuid.UUID  `gorm:"type:text;index" json:"department_id,omitempty"`
+	ShiftID          *uuid.UUID  `gorm:"type:text" json:"shift_id,omitempty"`
+	DayOfWeek        *int        `json:"day_of_week,omitempty"` // 0=Monday
+	MinEmployees     int         `gorm:"not null" json:"min_employees"`
+	MaxAbsentPercent float64     `gorm:"type:decimal(5,2);default:0" json:"max_absent_percent"`
+	Enforcement      string      `gorm:"type:varchar(10);default:'warn'" json:"enforcement"`
+	IsActive         bool        `gorm:"default:true" json:"is_active"`
+	Shift            *Shift      `gorm:"foreignKey:ShiftID" json:"shift,omitempty"`
+	Department       *Department `gorm:"foreignKey:DepartmentID" json:"department,omitempty"`
+}
+
+// TableName specifies the table name
+func (StaffingRule) TableName() string {
+	return "staffing_rules"
+}
+
+// BlackoutPeriod is a date range closed to leave requests
+type BlackoutPeriod struct {
+	BaseModel
+	CompanyID      uuid.UUID      `gorm:"type:text;not null;index" json:"company_id"`
+	Name           string         `gorm:"type:varchar(100);not null" json:"name"`
+	StartDate      time.Time      `gorm:"type:date;not null;index" json:"start_date"`
+	EndDate        time.Time      `gorm:"type:date;not null;index" json:"end_date"`
+	ProductionArea string         `gorm:"type:varchar(100)" json:"production_area,omitempty"` // Empty = any
+	DepartmentID   *uuid.UUID     `gorm:"type:text" json:"department_id,omitempty"`
+	RequestTypes   pq.StringArray `gorm:"type:text[]" json:"request_types,omitempty"`
+	Enforcement    string         `gorm:"type:varchar(10);default:'block'" json:"enforcement"`
+	Reason         string         `gorm:"type:text" json:"reason,omitempty"`
+	IsActive       bool           `gorm:"default:true" json:"is_active"`
+	CreatedBy      *uuid.UUID     `gorm:"type:text" json:"created_by,omitempty"`
+	Department     *Department    `gorm:"foreignKey:DepartmentID" json:"department,omitempty"`
+}
+
+// TableName specifies the table name
+func (BlackoutPeriod) TableName() string {
+	return "blackout_periods"
+}
@@ -0,0 +1,138 @@
+/*
+Package models - IRIS Payroll System Data Models
+
+==============================================================================
+FILE: internal/models/salary_campaign.go
+==============================================================================
+
+DESCRIPTION:
+    Salary review campaigns: mass salary increases (annual increase, union
+    contract revision) applied to a filtered population of employees, routed
+    for approval and applied on an effective date. When the effective date is
+    in the past, the difference for already-paid periods (retroactivo) is
+    paid in the next open period.
+
+USER PERSPECTIVE:
+    - HR defines who is included (department, collar type, union, position)
+      and how much (percentage, fixed amount or per-grade matrix)
+    - Each affected employee appears as one line with old and new salary
+    - A payroll manager approves; the increase is applied on its date
+
+DEVELOPER GUIDELINES:
+    ✅  OK to modify: Add filters or adjustment types
+    ⚠️  CAUTION: Applied campaigns have written SalaryHistory records
+    ❌  DO NOT modify: Items of applied campaigns (audit data)
+    📝  Status flow: draft → pending_approval → approved/scheduled → applied
+        (rejected and cancelled are terminal)
+
+SYNTAX EXPLANATION:
+    - AdjustmentValue: percentage (5 = 5%) or MXN per day depending on type
+    - GradeMatrix: JSON object {key: percentage}; key is a position ID,
+      a salary grade code, a collar type or "default"
+    - Filter*: JSON arrays; empty means "no filter"
+
+==============================================================================
+*/
+package models
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/datatypes"
+)
+
+// Salary campaign statuses
+const (
+	SalaryCampaignDraft           = "draft"
+	SalaryCampaignPendingApproval = "pending_approval"
+	SalaryCampaignScheduled       = "scheduled"
+	SalaryCampaignApplied         = "applied"
+	SalaryCampaignRejected        = "rejected"
+	SalaryCampaignCancelled       = "cancelled"
+)
+
+// Salary campaign adjustment types
+const (
+	SalaryAdjustmentPercentage  = "percentage"
+	SalaryAdjustmentFixedAmount = "fixed_amount"
+	SalaryAdjustmentGradeMatrix = "grade_matrix"
+)
+
+// SalaryCampaign is a mass salary increase campaign
+type SalaryCampaign struct {
+	BaseModel
+	CompanyID       uuid.UUID      `gorm:"type:text;not null;index" json:"company_id"`
+	Name            string         `gorm:"type:varchar(255);not null" json:"name"`
+	Description     string         `gorm:"type:text" json:"description,omitempty"`
+	CampaignType    string         `gorm:"type:varchar(30);default:'annual_increase'" json:"campaign_type"` // annual_increase, union_revision, merit, other
+	AdjustmentType  string         `gorm:"type:varchar(20);not null" json:"adjustment_type"`
+	AdjustmentValue float64        `gorm:"type:decimal(12,4);default:0" json:"adjustment_value"`
+	GradeMatrix     datatypes.JSON `gorm:"type:jsonb" json:"grade_matrix,omitempty"`
+	EffectiveDate   time.Time      `gorm:"type:date;not null" json:"effective_date"`
+	Status          string         `gorm:"type:varchar(20);default:'draft'" json:"status"`
+
+	// Population filters
+	FilterDepartmentIDs datatypes.JSON `gorm:"type:jsonb" json:"filter_department_ids,omitempty"`
+	FilterPositionIDs   datatypes.JSON `gorm:"type:jsonb" json:"filter_position_ids,omitempty"`
+	FilterCollarTypes   datatypes.JSON `gorm:"type:jsonb" json:"filter_collar_types,omitempty"`
+	FilterSindicalizado *bool          `json:"filter_sindicalizado,omitempty"`
+
+	// Totals (daily salary deltas and retroactive pay)
+	EmployeeCount          int     `gorm:"default:0" json:"employee_count"`
+	TotalDailyIncrease     float64 `gorm:"type:decimal(15,2);default:0" json:"total_daily_increase"`
+	TotalRetroactiveAmount float64 `gorm:"type:decimal(15,2);default:0" json:"total_retroactive_amount"`
+
+	// Workflow
+	CreatedBy       *uuid.UUID `gorm:"type:text" json:"created_by,omitempty"`
+	SubmittedBy     *uuid.UUID `gorm:"type:text" json:"submitted_by,omitempty"`
+	SubmittedAt     *time.Time `json:"submitted_at,omitempty"`
+	ApprovedBy      *uuid.UUID `gorm:"type:text" json:"approved_by,omitempty"`
+	ApprovedAt      *time.Time `json:"approved_at,omitempty"`
+	RejectionReason string     `gorm:"type:text" json:"rejection_reason,omitempty"`
+	AppliedAt       *time.Time `json:"applied_at,omitempty"`
+
+	Items []SalaryCampaignItem `gorm:"foreignKey:CampaignID" json:"items,omitempty"`
+}
+
+// TableName specifies the table name
+func (SalaryCampaign) TableName() string {
+	return "salary_campaigns"
+}
+
+// IsEditable reports whether the campaign can still be changed
+func (c *SalaryCampaign) IsEditable() bool {
+	return c.Status == SalaryCampaignDraft || c.Status == SalaryCampaignRejected
+}
+
+// GetGradeMatrix decodes the per-grade percentage matrix
+func (c *SalaryCampaign) GetGradeMatrix() map[string]float64 {
+	matrix := map[string]float64{}
+	if len(c.GradeMatrix) > 0 {
+		_ = json.Unmarshal(c.GradeMatrix, &matrix)
+	}
+	return matrix
+}
+
+// SalaryCampaignItem is one employee included in a campaign
+type SalaryCampaignItem struct {
+	BaseModel
+	CampaignID         uuid.UUID  `gorm:"type:text;not null;index" json:"campaign_id"`
+	EmployeeID         uuid.UUID  `gorm:"type:text;not null;index" json:"employee_id"`
+	OldDailySalary     float64    `gorm:"type:decimal(12,2)" json:"old_daily_salary"`
+	NewDailySalary     float64    `gorm:"type:decimal(12,2)" json:"new_daily_salary"`
+	IncreasePercent    float64    `gorm:"type:decimal(8,4)" json:"increase_percent"`
+	RetroactiveAmount  float64    `gorm:"type:decimal(15,2);default:0" json:"retroactive_amount"`
+	RetroactivePeriods int        `gorm:"default:0" json:"retroactive_periods"`
+	RetroIncidenceID   *uuid.UUID `gorm:"type:text" json:"retro_incidence_id,omitempty"`
+	SalaryHistoryID    *uuid.UUID `gorm:"type:text" json:"salary_history_id,omitempty"`
+	Status             string     `gorm:"type:varchar(20);default:'pending'" json:"status"` // pending, applied, skipped
+	SkipReason         string     `gorm:"type:text" json:"skip_reason,omitempty"`
+	Employee           *Employee  `gorm:"foreignKey:EmployeeID" json:"employee,omitempty"`
+}
+
+// TableName specifies the table name
+func (SalaryCampaignItem) TableName() string {
+	return "salary_campaign_items"
+}
@@ -0,0 +1,85 @@
+/*
+Package models - IRIS Payroll System Data Models
+
+==============================================================================
+FILE: internal/models/scheduled_job.go
+==============================================================================
+
+DESCRIPTION:
+    Persistence of the in-process job scheduler. SchedulerLock is the lease
+    that elects one API replica as leader: only the instance holding an
+    unexpired lease runs the periodic jobs. JobRun records every execution
+    (scheduled or manual) with its duration, summary and error, and is also
+    how the scheduler knows when a job is due again.
+
+USER PERSPECTIVE:
+    - Admins see when escalation, reminders, vacation grants and the other
+      periodic jobs last ran, how long they took and why they failed
+    - Running several API replicas never sends the same reminder twice
+
+DEVELOPER GUIDELINES:
+    ✅  OK to modify: Add run metadata (rows affected, company)
+    ⚠️  CAUTION: The lease is renewed on every tick; a leader that stops
+        ticking loses it after LeaseExpiresAt and another replica takes over
+    📝  Job runs are system-wide, not per company
+
+SYNTAX EXPLANATION:
+    - JobRun.Status: running, succeeded, failed
+    - JobRun.Trigger: schedule (the leader's tick) or manual (an admin)
+
+==============================================================================
+*/
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Job run statuses
+const (
+	JobRunRunning   = "running"
+	JobRunSucceeded = "succeeded"
+	JobRunFailed    = "failed"
+)
+
+// Job run triggers
+const (
+	JobTriggerSchedule = "schedule"
+	JobTriggerManual   = "manual"
+)
+
+// SchedulerLock is the leadership lease of the job scheduler
+type SchedulerLock struct {
+	BaseModel
+	Name           string    `gorm:"type:varchar(100);not null;uniqueIndex" json:"name"`
+	Holder         string    `gorm:"type:varchar(255);not null" json:"holder"` // Instance ID of the leader
+	AcquiredAt     time.Time `gorm:"not null" json:"acquired_at"`
+	LeaseExpiresAt time.Time `gorm:"not null;index" json:"lease_expires_at"`
+}
+
+// TableName specifies the table name
+func (SchedulerLock) TableName() string {
+	return "scheduler_locks"
+}
+
+// JobRun is one execution of a scheduled job
+type JobRun struct {
+	BaseModel
+	JobName     string     `gorm:"type:varchar(100);not null;index" json:"job_name"`
+	Trigger     string     `gorm:"type:varchar(20);not null" json:"trigger"`
+	Instance    string     `gorm:"type:varchar(255)" json:"instance"`
+	StartedAt   time.Time  `gorm:"not null;index" json:"started_at"`
+	FinishedAt  *time.Time `json:"finished_at,omitempty"`
+	DurationMs  int64      `json:"duration_ms"`
+	Status      string     `gorm:"type:varchar(20);not null;index" json:"status"`
+	Summary     string     `gorm:"type:text" json:"summary,omitempty"`
+	Error       string     `gorm:"type:text" json:"error,omitempty"`
+	TriggeredBy *uuid.UUID `gorm:"type:text" json:"triggered_by,omitempty"` // Admin who ran it manually
+}
+
+// TableName specifies the table name
+func (JobRun) TableName() string {
+	return "job_runs"
+}
@@ -0,0 +1,117 @@
+/*
+Package models - IRIS Payroll System Data Models
+
+==============================================================================
+FILE: internal/models/sick_leave.go
+==============================================================================
+
+DESCRIPTION:
+    IMSS incapacidades. A SickLeaveCertificate is one certificate issued by
+    the IMSS: its folio, branch (ramo: enfermedad general, riesgo de
+    trabajo, maternidad), type (inicial, subsecuente, recaída), the days it
+    authorizes and the scanned certificate. When it is registered the days
+    are split between the ones the company pays (the waiting days of
+    enfermedad general) and the ones the IMSS subsidizes, which the
+    prenómina, the IMSS contribution days and the CFDI Incapacidades node
+    take from here.
+
+USER PERSPECTIVE:
+    - HR captures the folio and days of the certificate the employee brings
+      and attaches the scan
+    - Payroll sees which days the company pays and which the IMSS does
+    - HR follows the open incapacidades and the days accumulated per
+      employee
+
+DEVELOPER GUIDELINES:
+    ✅  OK to modify: Add branches from the SAT c_TipoIncapacidad catalog
+    ⚠️  CAUTION: CompanyPaidDays are always the first days of the
+        certificate; the prenómina splits a certificate across periods
+        relying on it
+    📝  StartDate/EndDate are whole days, both inclusive
+        (EndDate = StartDate + DaysAuthorized - 1)
+
+SYNTAX EXPLANATION:
+    - Branch: general_illness (SAT 02), work_risk (SAT 01), maternity (SAT 03)
+    - Type: initial, subsequent (continues PreviousID), relapse (recaída of
+      PreviousID)
+    - Status: open (until the employee is discharged), closed (discharged
+      or continued by a subsequent certificate), cancelled
+    - SubsidyRate: share of DailyBaseSalary the IMSS pays for each
+      subsidized day (0.60 general illness, 1.00 work risk and maternity)
+
+==============================================================================
+*/
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Incapacidad branches (ramos del seguro)
+const (
+	SickLeaveBranchGeneralIllness = "general_illness"
+	SickLeaveBranchWorkRisk       = "work_risk"
+	SickLeaveBranchMaternity      = "maternity"
+)
+
+// Incapacidad types
+const (
+	SickLeaveTypeInitial    = "initial"
+	SickLeaveTypeSubsequent = "subsequent"
+	SickLeaveTypeRelapse    = "relapse"
+)
+
+// Incapacidad statuses
+const (
+	SickLeaveOpen      = "open"
+	SickLeaveClosed    = "closed"
+	SickLeaveCancelled = "cancelled"
+)
+
+// SickLeaveCertificate is an IMSS incapacidad certificate
+type SickLeaveCertificate struct {
+	BaseModel
+	CompanyID        uuid.UUID  `gorm:"type:text;not null;uniqueIndex:idx_sick_leave_folio" json:"company_id"`
+	EmployeeID       uuid.UUID  `gorm:"type:text;not null;index" json:"employee_id"`
+	Folio            string     `gorm:"type:varchar(20);not null;uniqueIndex:idx_sick_leave_folio" json:"folio"`
+	Branch           string     `gorm:"type:varchar(20);not null;index" json:"branch"`
+	Type             string     `gorm:"type:varchar(20);not null" json:"type"`
+	PreviousID       *uuid.UUID `gorm:"type:text" json:"previous_id,omitempty"`        // Certificate a subsequent or relapse continues
+	AbsenceRequestID *uuid.UUID `gorm:"type:text" json:"absence_request_id,omitempty"` // SICK_LEAVE request it documents
+	StartDate        time.Time  `gorm:"type:date;not null;index" json:"start_date"`
+	EndDate          time.Time  `gorm:"type:date;not null;index" json:"end_date"`
+	DaysAuthorized   int        `gorm:"not null" json:"days_authorized"`
+	CompanyPaidDays  int        `gorm:"not null;default:0" json:"company_paid_days"`
+	SubsidizedDays   int        `gorm:"not null;default:0" json:"subsidized_days"`
+	DailyBaseSalary  float64    `gorm:"type:decimal(15,2);default:0" json:"daily_base_salary"` // SBC when issued
+	SubsidyRate      float64    `gorm:"type:decimal(5,2);default:0" json:"subsidy_rate"`
+	EstimatedSubsidy float64    `gorm:"type:decimal(15,2);default:0" json:"estimated_subsidy"` // Paid by the IMSS to the employee
+	Diagnosis        string     `gorm:"type:varchar(255)" json:"diagnosis,omitempty"`
+	Notes            string     `gorm:"type:text" json:"notes,omitempty"`
+	Status           string     `gorm:"type:varchar(20);not null;default:'open';index" json:"status"`
+	ClosedOn         *time.Time `gorm:"type:date" json:"closed_on,omitempty"` // Alta date
+	DocumentName     string     `gorm:"type:varchar(255)" json:"document_name,omitempty"`
+	DocumentPath     string     `gorm:"type:varchar(500)" json:"-"`
+	DocumentType     string     `gorm:"type:varchar(100)" json:"document_type,omitempty"`
+	CreatedBy        *uuid.UUID `gorm:"type:text" json:"created_by,omitempty"`
+	Employee         *Employee  `gorm:"foreignKey:EmployeeID" json:"employee,omitempty"`
+}
+
+// TableName specifies the table name
+func (SickLeaveCertificate) TableName() string {
+	return "sick_leave_certificates"
+}
+
+// SATIncapacityType returns the SAT c_TipoIncapacidad code of the branch
+func (c *SickLeaveCertificate) SATIncapacityType() string {
+	switch c.Branch {
+	case SickLeaveBranchWorkRisk:
+		return "01"
+	case SickLeaveBranchMaternity:
+		return "03"
+	default:
+		return "02"
+	}
+}
@@ -0,0 +1,78 @@
+/*
+Package models - IRIS Payroll System Data Models
+
+==============================================================================
+FILE: internal/models/time_policy.go
+==============================================================================
+
+DESCRIPTION:
+    TimePolicyViolation records a breach of the employee's TimePolicy found
+    when a clock record or timesheet is closed: a missing required break,
+    too much overtime in a day, overtime on too many days of the week, or
+    overtime beyond the weekly double-time hours (paid triple).
+
+USER PERSPECTIVE:
+    - HR sees violations on the time policy dashboard
+    - HR resolves a violation with a note once it has been addressed
+
+DEVELOPER GUIDELINES:
+    ✅  OK to modify: Add violation types
+    ⚠️  CAUTION: Re-applying the policy to a record replaces its unresolved
+        violations; resolved ones are kept as history
+    📝  Exactly one of ClockRecordID / TimesheetID is set
+
+SYNTAX EXPLANATION:
+    - Type: missing_break | daily_overtime_limit | overtime_days_limit |
+      weekly_overtime_limit
+    - Severity: info | warning | critical
+    - Value: the measured amount (hours of overtime, days, minutes of break)
+
+==============================================================================
+*/
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Time policy violation types
+const (
+	ViolationMissingBreak        = "missing_break"
+	ViolationDailyOvertimeLimit  = "daily_overtime_limit"
+	ViolationOvertimeDaysLimit   = "overtime_days_limit"
+	ViolationWeeklyOvertimeLimit = "weekly_overtime_limit"
+)
+
+// Time policy violation severities
+const (
+	ViolationSeverityInfo     = "info"
+	ViolationSeverityWarning  = "warning"
+	ViolationSeverityCritical = "critical"
+)
+
+// TimePolicyViolation is a breach of a time policy
+type TimePolicyViolation struct {
+	BaseModel
+	CompanyID       uuid.UUID  `gorm:"type:text;not null;index" json:"company_id"`
+	EmployeeID      uuid.UUID  `gorm:"type:text;not null;index" json:"employee_id"`
+	TimePolicyID    *uuid.UUID `gorm:"type:text" json:"time_policy_id,omitempty"` // Nil for the built-in policy
+	ClockRecordID   *uuid.UUID `gorm:"type:text;index" json:"clock_record_id,omitempty"`
+	TimesheetID     *uuid.UUID `gorm:"type:text;index" json:"timesheet_id,omitempty"`
+	WorkDate        time.Time  `gorm:"type:date;not null;index" json:"work_date"`
+	Type            string     `gorm:"type:varchar(50);not null;index" json:"type"`
+	Severity        string     `gorm:"type:varchar(20);not null" json:"severity"`
+	Message         string     `gorm:"type:varchar(255)" json:"message"`
+	Value           float64    `json:"value"`
+	IsResolved      bool       `gorm:"default:false;index" json:"is_resolved"`
+	ResolvedBy      *uuid.UUID `gorm:"type:text" json:"resolved_by,omitempty"`
+	ResolvedAt      *time.Time `json:"resolved_at,omitempty"`
+	ResolutionNotes string     `gorm:"type:text" json:"resolution_notes,omitempty"`
+	Employee        *Employee  `gorm:"foreignKey:EmployeeID" json:"employee,omitempty"`
+}
+
+// TableName specifies the table name
+func (TimePolicyViolation) TableName() string {
+	return "time_policy_violations"
+}
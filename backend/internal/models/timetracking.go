@@ -9,6 +9,38 @@ DESCRIPTION:
     Data models for Time Tracking including timesheets, time entries,
     projects, and time-off tracking.
 
+    TimePolicy is applied by TimePolicyService when a ClockRecord is closed
+    and when a timesheet's totals are calculated: punch rounding, required
+    break deduction and the regular / double / triple split. Of the
+    overtime fields, the Mexican classification uses OvertimeThresholdDaily,
+    OvertimeThresholdWeekly and WeeklyDoubleTimeHours; DoubleTimeThreshold
+    is the daily-hours model of other jurisdictions and is not used by it.
+
+    Timesheets follow the payroll calendar (TimesheetPeriodService): one per
+    project-tracked employee and payroll period, reminded before the
+    prenómina cutoff and locked once approved (no new time entries).
+
+    Project and ProjectTask budgets (hours and amount) are compared with the
+    cost of approved time entries by ProjectCostService, which raises
+    ProjectBudgetAlert records (models/project_cost.go) at 80% and 100%.
+
+    Web and app punches are checked against the employee's WorkSite
+    (models/work_site.go): ClockRecord keeps the reported coordinates, the
+    device and the selfie, and OutOfZone / UnknownDevice flags that put the
+    record in the supervisors' review queue (ReviewStatus pending).
+
+    Vacation days are kept as a ledger of TimeOffAccrual movements
+    (VacationLedgerService): a grant on each work anniversary, consumption
+    when a vacation incidence is approved, its reversal on cancellation,
+    expiry of the days left 18 months after their grant and manual
+    adjustments. TimeOffBalance's vacation fields are refreshed from it.
+
+    The time-for-time bank (TimeBankService) uses the same ledger with
+    time_off_type "time_for_time" and signed Hours: overtime requested as
+    "bank" is deposited once reconciled instead of paid, TIME_FOR_TIME
+    absence requests consume it oldest deposit first, and deposits expire
+    after the TimePolicy's TimeBankExpiryDays.
+
 ==============================================================================
 */
 package models
@@ -104,6 +136,7 @@ type ProjectTask struct {
 
 	// Budget
 	BudgetHours  float64    `gorm:"default:0" json:"budget_hours"`
+	BudgetAmount float64    `gorm:"default:0" json:"budget_amount"`
 
 	// Status
 	IsActive     bool       `gorm:"default:true" json:"is_active"`
@@ -158,7 +191,8 @@ type Timesheet struct {
 	// Hours Summary
 	TotalRegularHours   float64 `gorm:"default:0" json:"total_regular_hours"`
 	TotalOvertimeHours  float64 `gorm:"default:0" json:"total_overtime_hours"`
-	TotalDoubleTimeHours float64 `gorm:"default:0" json:"total_double_time_hours"`
+	TotalDoubleTimeHours float64 `gorm:"default:0" json:"total_double_time_hours"` // Part of TotalOvertimeHours paid double
+	TotalTripleTimeHours float64 `gorm:"default:0" json:"total_triple_time_hours"` // Part of TotalOvertimeHours paid triple
 	TotalPTOHours       float64 `gorm:"default:0" json:"total_pto_hours"`
 	TotalHolidayHours   float64 `gorm:"default:0" json:"total_holiday_hours"`
 	TotalHours          float64 `gorm:"default:0" json:"total_hours"`
@@ -181,9 +215,14 @@ type Timesheet struct {
 	RejectedAt   *time.Time      `json:"rejected_at,omitempty"`
 	RejectionReason string       `gorm:"type:text" json:"rejection_reason"`
 
-	// Processing
+	// Processing (exported to prenómina)
 	ProcessedAt  *time.Time      `json:"processed_at,omitempty"`
 
+	// Period cycle (auto-generated timesheets)
+	AutoGenerated      bool       `gorm:"default:false" json:"auto_generated"`
+	SubmissionDeadline *time.Time `json:"submission_deadline,omitempty"` // Prenómina cutoff of the payroll period
+	ReminderSentAt     *time.Time `json:"reminder_sent_at,omitempty"`
+
 	// Relationships
 	Employee     *Employee       `gorm:"foreignKey:EmployeeID" json:"employee,omitempty"`
 	Entries      []TimeEntry     `gorm:"foreignKey:TimesheetID" json:"entries,omitempty"`
@@ -268,6 +307,25 @@ type ClockRecord struct {
 	ClockOutNotes string    `gorm:"type:text" json:"clock_out_notes"`
 	ClockOutIP   string     `gorm:"size:50" json:"clock_out_ip"`
 
+	// Geofence and device checks (web / app punches)
+	WorkSiteID        *uuid.UUID `gorm:"type:text;index" json:"work_site_id,omitempty"`
+	ClockInLatitude   *float64   `json:"clock_in_latitude,omitempty"`
+	ClockInLongitude  *float64   `json:"clock_in_longitude,omitempty"`
+	ClockInDistanceM  *float64   `json:"clock_in_distance_m,omitempty"` // From the site center
+	ClockInDeviceID   string     `gorm:"size:128" json:"clock_in_device_id,omitempty"`
+	ClockInPhoto      string     `gorm:"size:255" json:"clock_in_photo,omitempty"` // File name under ClockPhotoDir
+	ClockOutLatitude  *float64   `json:"clock_out_latitude,omitempty"`
+	ClockOutLongitude *float64   `json:"clock_out_longitude,omitempty"`
+	ClockOutDistanceM *float64   `json:"clock_out_distance_m,omitempty"`
+	ClockOutDeviceID  string     `gorm:"size:128" json:"clock_out_device_id,omitempty"`
+	ClockOutPhoto     string     `gorm:"size:255" json:"clock_out_photo,omitempty"`
+	OutOfZone         bool       `gorm:"default:false" json:"out_of_zone"`
+	UnknownDevice     bool       `gorm:"default:false" json:"unknown_device"`
+	ReviewStatus      string     `gorm:"size:20;index" json:"review_status,omitempty"` // pending, accepted, rejected; empty when not flagged
+	ReviewedByID      *uuid.UUID `gorm:"type:text" json:"reviewed_by_id,omitempty"`
+	ReviewedAt        *time.Time `json:"reviewed_at,omitempty"`
+	ReviewNotes       string     `gorm:"type:text" json:"review_notes,omitempty"`
+
 	// Breaks
 	BreakMinutes int        `gorm:"default:0" json:"break_minutes"`
 	BreakStart   *time.Time `json:"break_start,omitempty"`
@@ -277,6 +335,15 @@ type ClockRecord struct {
 	WorkedHours  float64    `gorm:"default:0" json:"worked_hours"`
 	IsComplete   bool       `gorm:"default:false" json:"is_complete"`
 
+	// Time policy result (set when the record is closed)
+	TimePolicyID     *uuid.UUID `gorm:"type:text" json:"time_policy_id,omitempty"`
+	RoundedClockIn   *time.Time `json:"rounded_clock_in,omitempty"`
+	RoundedClockOut  *time.Time `json:"rounded_clock_out,omitempty"`
+	AutoBreakMinutes int        `gorm:"default:0" json:"auto_break_minutes"` // Deducted for a missing required break
+	RegularHours     float64    `gorm:"default:0" json:"regular_hours"`
+	DoubleTimeHours  float64    `gorm:"default:0" json:"double_time_hours"`
+	TripleTimeHours  float64    `gorm:"default:0" json:"triple_time_hours"`
+
 	// Status
 	Status       string     `gorm:"size:50;default:'active'" json:"status"` // active, completed, adjusted
 	IsAdjusted   bool       `gorm:"default:false" json:"is_adjusted"`
@@ -294,11 +361,27 @@ func (ClockRecord) TableName() string {
 	return "time_clock_records"
 }
 
+// Overtime request statuses
+const (
+	OvertimeStatusPending   = "pending"
+	OvertimeStatusApproved  = "approved"
+	OvertimeStatusRejected  = "rejected"
+	OvertimeStatusCompleted = "completed" // Reconciled against the clock records
+	OvertimeStatusCancelled = "cancelled"
+)
+
+// Overtime compensation (OvertimeRequest.Compensation)
+const (
+	OvertimeCompensationPay  = "pay"  // Paid double/triple in the prenómina
+	OvertimeCompensationBank = "bank" // Deposited in the time-for-time bank instead
+)
+
 // OvertimeRequest represents an overtime request
 type OvertimeRequest struct {
 	BaseModel
 	CompanyID    uuid.UUID  `gorm:"type:text;not null;index" json:"company_id"`
 	EmployeeID   uuid.UUID  `gorm:"type:text;not null;index" json:"employee_id"`
+	RequestedByID *uuid.UUID `gorm:"type:text" json:"requested_by_id,omitempty"` // User: the employee or their supervisor
 
 	// Request Details
 	RequestDate  time.Time  `gorm:"not null" json:"request_date"`
@@ -306,12 +389,14 @@ type OvertimeRequest struct {
 	EndTime      time.Time  `gorm:"not null" json:"end_time"`
 	EstimatedHours float64  `gorm:"default:0" json:"estimated_hours"`
 	Reason       string     `gorm:"type:text;not null" json:"reason"`
+	Compensation string     `gorm:"size:10;default:'pay'" json:"compensation"` // pay, bank
 
 	// Project (if applicable)
 	ProjectID    *uuid.UUID `gorm:"type:text" json:"project_id,omitempty"`
 
 	// Status
-	Status       string     `gorm:"size:50;default:'pending'" json:"status"` // pending, approved, rejected, completed
+	Status       string     `gorm:"size:50;default:'pending'" json:"status"` // pending, approved, rejected, completed, cancelled
+	CurrentApprovalStage ApprovalStage `gorm:"type:varchar(50)" json:"current_approval_stage"` // Same chain as absence requests
 
 	// Approval
 	ApprovedByID *uuid.UUID `gorm:"type:text" json:"approved_by_id,omitempty"`
@@ -323,6 +408,9 @@ type OvertimeRequest struct {
 	RejectedAt   *time.Time `json:"rejected_at,omitempty"`
 	RejectionReason string  `gorm:"size:255" json:"rejection_reason"`
 
+	// Authorized Hours (EstimatedHours once fully approved; caps paid overtime that day)
+	AuthorizedHours float64 `gorm:"default:0" json:"authorized_hours"`
+
 	// Actual Hours
 	ActualHours  float64    `gorm:"default:0" json:"actual_hours"`
 	CompletedAt  *time.Time `json:"completed_at,omitempty"`
@@ -330,12 +418,28 @@ type OvertimeRequest struct {
 	// Relationships
 	Employee     *Employee  `gorm:"foreignKey:EmployeeID" json:"employee,omitempty"`
 	Project      *Project   `gorm:"foreignKey:ProjectID" json:"project,omitempty"`
+	Approvals    []OvertimeApproval `gorm:"foreignKey:RequestID" json:"approvals,omitempty"`
 }
 
 func (OvertimeRequest) TableName() string {
 	return "time_overtime_requests"
 }
 
+// OvertimeApproval records each approval/decline of an overtime request
+type OvertimeApproval struct {
+	BaseModel
+	RequestID     uuid.UUID      `gorm:"type:text;not null;index" json:"request_id"`
+	ApproverID    uuid.UUID      `gorm:"type:text;not null" json:"approver_id"`
+	ApprovalStage ApprovalStage  `gorm:"type:varchar(50);not null" json:"approval_stage"`
+	Action        ApprovalAction `gorm:"type:varchar(50);not null" json:"action"`
+	Comments      string         `gorm:"type:text" json:"comments,omitempty"`
+	Approver      *User          `gorm:"foreignKey:ApproverID" json:"approver,omitempty"`
+}
+
+func (OvertimeApproval) TableName() string {
+	return "time_overtime_approvals"
+}
+
 // TimeOffBalance represents an employee's time-off balances
 type TimeOffBalance struct {
 	BaseModel
@@ -379,6 +483,16 @@ func (TimeOffBalance) TableName() string {
 	return "time_off_balances"
 }
 
+// Vacation ledger movements (TimeOffAccrual.Reason for time_off_type "vacation")
+const (
+	AccrualAnnualGrant = "annual_grant" // Days earned on a work anniversary
+	AccrualConsumption = "consumption"  // Days taken by an approved vacation incidence
+	AccrualReversal    = "reversal"     // Consumption given back when the incidence is cancelled
+	AccrualExpiry      = "expiry"       // Unused days of a grant past its prescription
+	AccrualAdjustment  = "adjustment"   // Manual correction, Notes holds why
+	AccrualBankDeposit = "bank_deposit" // Time bank: reconciled overtime banked instead of paid
+)
+
 // TimeOffAccrual represents an accrual of time-off
 type TimeOffAccrual struct {
 	BaseModel
@@ -389,7 +503,18 @@ type TimeOffAccrual struct {
 	AccrualDate  time.Time  `gorm:"not null" json:"accrual_date"`
 	TimeOffType  string     `gorm:"size:50;not null" json:"time_off_type"` // vacation, sick, personal
 	Hours        float64    `gorm:"not null" json:"hours"`
-	Reason       string     `gorm:"size:255" json:"reason"` // monthly_accrual, annual_grant, adjustment, etc.
+	Reason       string     `gorm:"size:255" json:"reason"` // monthly_accrual, annual_grant, consumption, reversal, expiry, adjustment
+
+	// Vacation ledger: signed days against the grant of one service year
+	Days         float64    `gorm:"type:decimal(8,2);default:0" json:"days"`
+	ServiceYear  int        `gorm:"default:0;index" json:"service_year"` // Anniversary the days belong to (1 = first year)
+	ExpiresOn    *time.Time `gorm:"type:date" json:"expires_on,omitempty"` // Grants only
+	IncidenceID  *uuid.UUID `gorm:"type:text;index" json:"incidence_id,omitempty"`
+	ReversesID   *uuid.UUID `gorm:"type:text" json:"reverses_id,omitempty"` // Consumption a reversal gives back
+	DepositID    *uuid.UUID `gorm:"type:text;index" json:"deposit_id,omitempty"` // Time bank: deposit the hours are charged to
+	OvertimeRequestID *uuid.UUID `gorm:"type:text;index" json:"overtime_request_id,omitempty"` // Time bank deposits
+	AbsenceRequestID  *uuid.UUID `gorm:"type:text;index" json:"absence_request_id,omitempty"`  // Time bank consumption
+	Notes        string     `gorm:"type:text" json:"notes,omitempty"`
 
 	// Source
 	PayrollPeriodID *uuid.UUID `gorm:"type:text" json:"payroll_period_id,omitempty"`
@@ -423,9 +548,17 @@ type TimePolicy struct {
 	DoubleTimeThreshold    float64 `gorm:"default:12" json:"double_time_threshold"`
 	DoubleTimeMultiplier   float64 `gorm:"default:2" json:"double_time_multiplier"`
 
+	// Mexican overtime limits (LFT Art. 66-68): the first WeeklyDoubleTimeHours
+	// of overtime in a week are paid double, the rest triple
+	WeeklyDoubleTimeHours  float64 `gorm:"default:9" json:"weekly_double_time_hours"`
+	TripleTimeMultiplier   float64 `gorm:"default:3" json:"triple_time_multiplier"`
+	MaxDailyOvertimeHours  float64 `gorm:"default:3" json:"max_daily_overtime_hours"`
+	MaxOvertimeDaysPerWeek int     `gorm:"default:3" json:"max_overtime_days_per_week"`
+
 	// Breaks
 	RequiredBreakAfterHours float64 `gorm:"default:6" json:"required_break_after_hours"`
 	MinBreakMinutes        int     `gorm:"default:30" json:"min_break_minutes"`
+	DisableAutoBreak       bool    `gorm:"default:false" json:"disable_auto_break"` // Only flag a missing break, do not deduct it
 
 	// Rounding
 	RoundingIntervalMinutes int    `gorm:"default:15" json:"rounding_interval_minutes"` // 0 = no rounding
@@ -439,6 +572,10 @@ type TimePolicy struct {
 	SickAccrualRate       float64 `gorm:"default:0" json:"sick_accrual_rate"`
 	SickMaxAccrual        float64 `gorm:"default:0" json:"sick_max_accrual"`
 
+	// Time-for-time bank
+	TimeBankMaxHours   float64 `gorm:"default:0" json:"time_bank_max_hours"`   // Balance cap (0 = overtime cannot be banked)
+	TimeBankExpiryDays int     `gorm:"default:0" json:"time_bank_expiry_days"` // Days a deposit can be used (0 = no expiry)
+
 	// Applicability
 	ApplicableRoles      pq.StringArray `gorm:"type:text[]" json:"applicable_roles"`
 	ApplicableCollarTypes pq.StringArray `gorm:"type:text[]" json:"applicable_collar_types"`
@@ -0,0 +1,114 @@
+/*
+Package models - IRIS Payroll System Data Models
+
+==============================================================================
+FILE: internal/models/work_site.go
+==============================================================================
+
+DESCRIPTION:
+    Work sites for web and app clock-in. A WorkSite is a GPS geofence (center
+    and radius) plus the IP ranges of its network; EmployeeWorkSite assigns
+    an employee to the site their punches are checked against. TrustedDevice
+    binds one phone or browser to an employee. Punches outside the site or
+    from another device are still recorded, flagged on the ClockRecord for a
+    supervisor to review.
+
+USER PERSPECTIVE:
+    - HR draws the plant or the client's warehouse as a circle on the map
+      and lists the office network ranges
+    - Field staff clock in from the portal; the phone's GPS places the punch
+    - An employee registers their phone once; punches from another device
+      are flagged
+    - Supervisors accept or reject flagged punches
+
+DEVELOPER GUIDELINES:
+    ✅  OK to modify: Add site attributes (address, time zone)
+    ⚠️  CAUTION: A punch is in the zone when its coordinates are inside the
+        radius OR it comes from an allowed IP range; shrinking a radius does
+        not re-flag past punches
+    ⚠️  CAUTION: DeviceID is generated by the client and only identifies a
+        browser or app install; it is a deterrent, not authentication
+    📝  An employee has at most one site and one active trusted device
+
+SYNTAX EXPLANATION:
+    - AllowedIPRanges: CIDR blocks ("201.150.10.0/24"); a bare IP is a /32
+    - BlockOutOfZone: reject out-of-zone punches instead of flagging them
+    - RequireTrustedDevice: flag punches of employees with no registered device
+    - RequirePhoto: punches must carry a selfie
+
+==============================================================================
+*/
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+// Clock review statuses of a flagged ClockRecord
+const (
+	ClockReviewPending  = "pending"
+	ClockReviewAccepted = "accepted"
+	ClockReviewRejected = "rejected"
+)
+
+// WorkSite is a place employees clock in from: a geofence and its networks
+type WorkSite struct {
+	BaseModel
+	CompanyID            uuid.UUID      `gorm:"type:text;not null;index" json:"company_id"`
+	Name                 string         `gorm:"type:varchar(100);not null" json:"name"`
+	Code                 string         `gorm:"type:varchar(20);not null" json:"code"`
+	Address              string         `gorm:"type:varchar(255)" json:"address,omitempty"`
+	Latitude             float64        `json:"latitude"`
+	Longitude            float64        `json:"longitude"`
+	RadiusMeters         float64        `gorm:"default:200" json:"radius_meters"`
+	AllowedIPRanges      pq.StringArray `gorm:"type:text[]" json:"allowed_ip_ranges"`
+	BlockOutOfZone       bool           `gorm:"default:false" json:"block_out_of_zone"`
+	RequireTrustedDevice bool           `gorm:"default:false" json:"require_trusted_device"`
+	RequirePhoto         bool           `gorm:"default:false" json:"require_photo"`
+	IsActive             bool           `gorm:"default:true" json:"is_active"`
+}
+
+// TableName specifies the table name
+func (WorkSite) TableName() string {
+	return "work_sites"
+}
+
+// EmployeeWorkSite assigns an employee to the site their punches are checked against
+type EmployeeWorkSite struct {
+	BaseModel
+	CompanyID  uuid.UUID  `gorm:"type:text;not null;index" json:"company_id"`
+	EmployeeID uuid.UUID  `gorm:"type:text;not null;uniqueIndex" json:"employee_id"`
+	WorkSiteID uuid.UUID  `gorm:"type:text;not null;index" json:"work_site_id"`
+	AssignedBy *uuid.UUID `gorm:"type:text" json:"assigned_by,omitempty"`
+	WorkSite   *WorkSite  `gorm:"foreignKey:WorkSiteID" json:"work_site,omitempty"`
+	Employee   *Employee  `gorm:"foreignKey:EmployeeID" json:"employee,omitempty"`
+}
+
+// TableName specifies the table name
+func (EmployeeWorkSite) TableName() string {
+	return "employee_work_sites"
+}
+
+// TrustedDevice is the phone or browser an employee clocks in from
+type TrustedDevice struct {
+	BaseModel
+	CompanyID    uuid.UUID  `gorm:"type:text;not null;index" json:"company_id"`
+	EmployeeID   uuid.UUID  `gorm:"type:text;not null;index" json:"employee_id"`
+	DeviceID     string     `gorm:"type:varchar(128);not null;index" json:"device_id"` // Generated by the client on install
+	Name         string     `gorm:"type:varchar(100)" json:"name,omitempty"`
+	Platform     string     `gorm:"type:varchar(50)" json:"platform,omitempty"` // android, ios, web
+	IsActive     bool       `gorm:"default:true" json:"is_active"`
+	RegisteredBy uuid.UUID  `gorm:"type:text;not null" json:"registered_by"`
+	LastUsedAt   *time.Time `json:"last_used_at,omitempty"`
+	RevokedBy    *uuid.UUID `gorm:"type:text" json:"revoked_by,omitempty"`
+	RevokedAt    *time.Time `json:"revoked_at,omitempty"`
+	Employee     *Employee  `gorm:"foreignKey:EmployeeID" json:"employee,omitempty"`
+}
+
+// TableName specifies the table name
+func (TrustedDevice) TableName() string {
+	return "trusted_devices"
+}
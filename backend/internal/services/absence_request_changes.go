@@ -0,0 +1,300 @@
+/*
+Package services - Absence Request Changes
+
+==============================================================================
+FILE: internal/services/absence_request_changes.go
+==============================================================================
+
+DESCRIPTION:
+    Cancellation and modification of absence requests after their approval.
+    The employee files a change request (an AbsenceRequest with AmendsID and
+    ChangeType) that is routed and approved like any other request. Its
+    final approval reverses the original: the linked incidences are
+    rejected, or corrected in the next open payroll period when their
+    period was already paid; vacation days and time bank hours are given
+    back and the shift exceptions of a shift change are removed.
+
+USER PERSPECTIVE:
+    - Employees cancel an approved vacation or move its dates without
+      asking HR to edit anything by hand
+    - Approvers see the change in their usual queue
+    - Payroll sees a corrective incidence in the current period instead of
+      a paid one changing under them
+
+DEVELOPER GUIDELINES:
+    ✅  OK to modify: Comments written to the history
+    ⚠️  CAUTION: A paid incidence is never edited; it keeps its status and
+        a corrective incidence (CorrectsIncidenceID) reverses it
+    ⚠️  CAUTION: applyChange runs inside the approval transaction; every
+        service it calls must use withDB(tx)
+    📝  Only one change may be pending per request
+
+SYNTAX EXPLANATION:
+    - Paid period: status paid or closed
+    - Next open period: status open, same frequency, starting after the
+      paid period ends (earliest first)
+    - Correction: same type and dates, negated Quantity and
+      CalculatedAmount, already approved, linked to the change request;
+      corrections are never reversed again by a later change
+
+==============================================================================
+*/
+package services
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"backend/internal/models"
+)
+
+// ModifyAbsenceRequestInput holds the new dates of an approved request
+type ModifyAbsenceRequestInput struct {
+	StartDate time.Time
+	EndDate   time.Time
+	TotalDays float64
+	StartTime string // "HH:MM" for requests in hours, empty for whole days
+	EndTime   string
+	Reason    string
+}
+
+// RequestCancellation files the cancellation of an approved request of the
+// user; the request stays approved until the cancellation is approved
+func (s *AbsenceRequestService) RequestCancellation(requestID, userID uuid.UUID, reason string) (*CreateAbsenceRequestResult, error) {
+	original, err := s.amendable(requestID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if reason == "" {
+		return nil, fmt.Errorf("%w: a reason is required", ErrInvalidAbsenceRequest)
+	}
+
+	return s.CreateAbsenceRequest(CreateAbsenceRequestInput{
+		EmployeeID:    userID,
+		RequestType:   original.RequestType,
+		StartDate:     original.StartDate,
+		EndDate:       original.EndDate,
+		TotalDays:     original.TotalDays,
+		Reason:        reason,
+		HoursPerDay:   original.HoursPerDay,
+		ShiftDetails:  original.ShiftDetails,
+		NewShiftID:    original.NewShiftID,
+		LeavePolicyID: original.LeavePolicyID,
+		amends:        original,
+		changeType:    models.RequestChangeCancellation,
+	})
+}
+
+// RequestModification files new dates for an approved request of the user;
+// once approved the modification replaces the original
+func (s *AbsenceRequestService) RequestModification(requestID, userID uuid.UUID, input ModifyAbsenceRequestInput) (*CreateAbsenceRequestResult, error) {
+	original, err := s.amendable(requestID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if input.Reason == "" {
+		return nil, fmt.Errorf("%w: a reason is required", ErrInvalidAbsenceRequest)
+	}
+	if input.EndDate.Before(input.StartDate) {
+		return nil, fmt.Errorf("%w: end date must be on or after start date", ErrInvalidAbsenceRequest)
+	}
+	if input.StartDate.Equal(original.StartDate) && input.EndDate.Equal(original.EndDate) &&
+		input.TotalDays == original.TotalDays && input.StartTime == original.StartTime && input.EndTime == original.EndTime {
+		return nil, fmt.Errorf("%w: the modification must change the dates, days or times", ErrInvalidAbsenceRequest)
+	}
+
+	return s.CreateAbsenceRequest(CreateAbsenceRequestInput{
+		EmployeeID:     userID,
+		RequestType:    original.RequestType,
+		StartDate:      input.StartDate,
+		EndDate:        input.EndDate,
+		TotalDays:      input.TotalDays,
+		Reason:         input.Reason,
+		HoursPerDay:    original.HoursPerDay,
+		UnpaidComments: original.UnpaidComments,
+		ShiftDetails:   original.ShiftDetails,
+		NewShiftID:     original.NewShiftID,
+		StartTime:      input.StartTime,
+		EndTime:        input.EndTime,
+		LeavePolicyID:  original.LeavePolicyID,
+		amends:         original,
+		changeType:     models.RequestChangeModification,
+	})
+}
+
+// amendable loads an approved request of the user that can be changed
+func (s *AbsenceRequestService) amendable(requestID, userID uuid.UUID) (*models.AbsenceRequest, error) {
+	var original models.AbsenceRequest
+	if err := s.db.First(&original, "id = ?", requestID).Error; err != nil {
+		return nil, errors.New("request not found")
+	}
+	if original.EmployeeID != userID {
+		return nil, fmt.Errorf("%w: can only change your own requests", ErrInvalidAbsenceRequest)
+	}
+	if original.Status != models.RequestStatusApproved {
+		return nil, fmt.Errorf("%w: only approved requests can be cancelled or modified", ErrInvalidAbsenceRequest)
+	}
+	if original.ChangeType == models.RequestChangeCancellation {
+		return nil, fmt.Errorf("%w: a cancellation cannot be changed", ErrInvalidAbsenceRequest)
+	}
+
+	var pending int64
+	if err := s.db.Model(&models.AbsenceRequest{}).
+		Where("amends_id = ? AND status = ?", original.ID, models.RequestStatusPending).
+		Count(&pending).Error; err != nil {
+		return nil, err
+	}
+	if pending > 0 {
+		return nil, fmt.Errorf("%w: a change of this request already exists and is pending", ErrInvalidAbsenceRequest)
+	}
+	return &original, nil
+}
+
+// changeLabel names a change type in the history comments
+func changeLabel(changeType string) string {
+	if changeType == models.RequestChangeCancellation {
+		return "Cancelación"
+	}
+	return "Modificación"
+}
+
+// recordChange adds a step of a change to the original request's history
+func (s *AbsenceRequestService) recordChange(tx *gorm.DB, originalID, actorID uuid.UUID, action models.ApprovalAction, comments string) error {
+	return tx.Create(&models.ApprovalHistory{
+		RequestID:     originalID,
+		ApproverID:    actorID,
+		ApprovalStage: models.ApprovalStageCompleted,
+		Action:        action,
+		Comments:      comments,
+	}).Error
+}
+
+// applyChange reverses the request an approved change cancels or modifies
+func (s *AbsenceRequestService) applyChange(tx *gorm.DB, change *models.AbsenceRequest, approverID uuid.UUID, now time.Time) error {
+	var original models.AbsenceRequest
+	if err := tx.First(&original, "id = ?", *change.AmendsID).Error; err != nil {
+		return errors.New("amended request not found")
+	}
+	if original.Status != models.RequestStatusApproved {
+		return fmt.Errorf("%w: the amended request is no longer approved", ErrInvalidAbsenceRequest)
+	}
+
+	// Incidences of periods already paid are corrected in the next open
+	// period; the rest are rejected and left out of payroll
+	var incidences []models.Incidence
+	if err := tx.Preload("IncidenceType").Preload("PayrollPeriod").
+		Where("absence_request_id = ? AND status IN ?", original.ID, []string{"pending", "approved", "processed"}).
+		Where("corrects_incidence_id IS NULL").
+		Find(&incidences).Error; err != nil {
+		return err
+	}
+	label := changeLabel(change.ChangeType)
+	for i := range incidences {
+		incidence := &incidences[i]
+		if incidence.Status != "pending" {
+			if err := s.vacationLedger.withDB(tx).ReverseConsumption(incidence, &approverID, now); err != nil {
+				return fmt.Errorf("failed to reverse vacation consumption: %w", err)
+			}
+		}
+		if periodPaid(incidence.PayrollPeriod) {
+			if err := s.postCorrection(tx, incidence, change, approverID, now); err != nil {
+				return err
+			}
+			continue
+		}
+		incidence.Status = "rejected"
+		incidence.ExcludedFromPayroll = true
+		incidence.Comments = fmt.Sprintf("%s (%s aprobada)", incidence.Comments, label)
+		if err := tx.Omit("IncidenceType", "PayrollPeriod").Save(incidence).Error; err != nil {
+			return err
+		}
+	}
+
+	if original.RequestType == models.RequestTypeTimeForTime {
+		employee, err := s.requestEmployee(tx, &original)
+		if err != nil {
+			return err
+		}
+		if err := s.timeBank.withDB(tx).ReverseConsumption(&original, employee, &approverID, now); err != nil {
+			return fmt.Errorf("failed to reverse time bank consumption: %w", err)
+		}
+	}
+
+	if original.RequestType == models.RequestTypeShiftChange {
+		employee, err := s.requestEmployee(tx, &original)
+		if err != nil {
+			return err
+		}
+		query := tx.Where("employee_id = ? AND date >= ? AND date <= ?", employee.ID, original.StartDate, original.EndDate)
+		if original.NewShiftID != nil {
+			query = query.Where("shift_id = ?", *original.NewShiftID)
+		}
+		if err := query.Delete(&models.ShiftException{}).Error; err != nil {
+			return fmt.Errorf("failed to remove shift exceptions: %w", err)
+		}
+	}
+
+	action := models.ApprovalActionModified
+	original.Status = models.RequestStatusModified
+	if change.ChangeType == models.RequestChangeCancellation {
+		action = models.ApprovalActionCancelled
+		original.Status = models.RequestStatusCancelled
+	}
+	original.LastActionAt = now
+	if err := tx.Save(&original).Error; err != nil {
+		return err
+	}
+	return s.recordChange(tx, original.ID, approverID, action,
+		fmt.Sprintf("%s aprobada (solicitud %s)", label, change.ID))
+}
+
+// periodPaid reports whether the period's payroll was already paid
+func periodPaid(period *models.PayrollPeriod) bool {
+	return period != nil && (period.Status == "paid" || period.Status == "closed")
+}
+
+// postCorrection reverses a paid incidence in the next open period of the
+// same frequency; an incidence already corrected is left alone
+func (s *AbsenceRequestService) postCorrection(tx *gorm.DB, incidence *models.Incidence, change *models.AbsenceRequest, approverID uuid.UUID, now time.Time) error {
+	var corrections int64
+	if err := tx.Model(&models.Incidence{}).Where("corrects_incidence_id = ?", incidence.ID).Count(&corrections).Error; err != nil {
+		return err
+	}
+	if corrections > 0 {
+		return nil
+	}
+
+	var next models.PayrollPeriod
+	if err := tx.Where("status = ? AND frequency = ? AND start_date > ?", "open", incidence.PayrollPeriod.Frequency, incidence.PayrollPeriod.EndDate).
+		Order("start_date").Limit(1).Find(&next).Error; err != nil {
+		return err
+	}
+	if next.ID == uuid.Nil {
+		return fmt.Errorf("%w: no open payroll period after %s to post the correction",
+			ErrInvalidAbsenceRequest, incidence.PayrollPeriod.PeriodCode)
+	}
+
+	correction := &models.Incidence{
+		EmployeeID:          incidence.EmployeeID,
+		PayrollPeriodID:     next.ID,
+		IncidenceTypeID:     incidence.IncidenceTypeID,
+		StartDate:           incidence.StartDate,
+		EndDate:             incidence.EndDate,
+		Quantity:            -incidence.Quantity,
+		CalculatedAmount:    -incidence.CalculatedAmount,
+		Comments:            fmt.Sprintf("%s de la solicitud: corrige el periodo %s", changeLabel(change.ChangeType), incidence.PayrollPeriod.PeriodCode),
+		Status:              "approved",
+		ApprovedBy:          &approverID,
+		ApprovedAt:          &now,
+		AbsenceRequestID:    &change.ID,
+		CorrectsIncidenceID: &incidence.ID,
+	}
+	if err := tx.Create(correction).Error; err != nil {
+		return fmt.Errorf("failed to create corrective incidence: %w", err)
+	}
+	return nil
+}
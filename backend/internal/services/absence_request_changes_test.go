@@ -0,0 +1,137 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+
+	"backend/internal/models"
+)
+
+func TestAbsenceRequestChanges_CancelAndModifyAfterApproval(t *testing.T) {
+	db := setupPayrollTestDB(t)
+	require.NoError(t, db.AutoMigrate(
+		&models.User{}, &models.AbsenceRequest{}, &models.ApprovalHistory{}, &models.IncidenceCategory{}, &models.IncidenceType{},
+		&models.Incidence{}, &models.TimeOffBalance{}, &models.TimeOffAccrual{}, &models.ShiftException{},
+	))
+	company := createPayrollTestCompany(t, db)
+	service := NewAbsenceRequestService(db, nil)
+	ledger := NewVacationLedgerService(db, nil)
+
+	// Hired 2022-03-01: 26 vacation days granted by 2024-03-01
+	employee := createFixtureEmployee(t, db, company.ID, 1, "general", 400)
+	user := createFixtureUser(t, db, employee, "employee")
+	hr := createFixtureUser(t, db, createFixtureEmployee(t, db, company.ID, 2, "general", 400), "hr")
+	day := func(m time.Month, d int) time.Time { return time.Date(2024, m, d, 0, 0, 0, 0, time.UTC) }
+	_, err := ledger.PostAnniversaryGrants(company.ID, day(6, 15))
+	require.NoError(t, err)
+	balance := func() float64 {
+		statement, err := ledger.Statement(company.ID, employee.ID, day(6, 20))
+		require.NoError(t, err)
+		return statement.Balance
+	}
+	before := balance()
+
+	period := func(code string, start, end time.Time, status string) *models.PayrollPeriod {
+		p := &models.PayrollPeriod{PeriodCode: code, Year: 2024, PeriodNumber: start.YearDay(), Frequency: "biweekly",
+			PeriodType: "biweekly", StartDate: start, EndDate: end, PaymentDate: end, Status: status}
+		require.NoError(t, db.Create(p).Error)
+		return p
+	}
+	paid := period("2024-BW13", day(7, 1), day(7, 15), "paid")
+	open := period("2024-BW14", day(7, 16), day(7, 31), "open")
+
+	vacation := &models.IncidenceType{Name: "Vacaciones", Category: "vacation", EffectType: "neutral"}
+	require.NoError(t, db.Create(vacation).Error)
+	approved := func(start time.Time, days int, p *models.PayrollPeriod, status string) (*models.AbsenceRequest, *models.Incidence) {
+		end := start.AddDate(0, 0, days-1)
+		request := &models.AbsenceRequest{EmployeeID: user.ID, RequestType: models.RequestTypeVacation, StartDate: start, EndDate: end,
+			TotalDays: float64(days), Reason: "Vacaciones", Status: models.RequestStatusApproved, IncidenceTypeID: &vacation.ID}
+		require.NoError(t, db.Create(request).Error)
+		incidence := &models.Incidence{EmployeeID: employee.ID, PayrollPeriodID: p.ID, IncidenceTypeID: vacation.ID,
+			StartDate: start, EndDate: end, Quantity: float64(days), CalculatedAmount: 400 * float64(days),
+			Status: status, AbsenceRequestID: &request.ID}
+		require.NoError(t, db.Create(incidence).Error)
+		require.NoError(t, ledger.PostConsumption(incidence, day(6, 20)))
+		return request, incidence
+	}
+	change := func(original *models.AbsenceRequest, changeType string) *models.AbsenceRequest {
+		request := &models.AbsenceRequest{EmployeeID: user.ID, RequestType: original.RequestType, StartDate: original.StartDate,
+			EndDate: original.EndDate, TotalDays: original.TotalDays, Reason: "Cambio de planes", Status: models.RequestStatusApproved,
+			AmendsID: &original.ID, ChangeType: changeType}
+		require.NoError(t, db.Create(request).Error)
+		return request
+	}
+
+	// Only the employee's own approved requests can be changed
+	july, julyIncidence := approved(day(7, 22), 2, open, "approved")
+	assert.Equal(t, before-2, balance())
+	_, err = service.amendable(july.ID, hr.ID)
+	assert.ErrorContains(t, err, "can only change your own requests")
+
+	// Cancelling a vacation of an open period rejects its incidence and
+	// gives the days back
+	cancellation := change(july, models.RequestChangeCancellation)
+	require.NoError(t, db.Transaction(func(tx *gorm.DB) error {
+		return service.applyChange(tx, cancellation, hr.ID, day(6, 20))
+	}))
+	require.NoError(t, db.First(julyIncidence, "id = ?", julyIncidence.ID).Error)
+	assert.Equal(t, "rejected", julyIncidence.Status)
+	assert.True(t, julyIncidence.ExcludedFromPayroll)
+	assert.Equal(t, before, balance())
+	require.NoError(t, db.First(july, "id = ?", july.ID).Error)
+	assert.Equal(t, models.RequestStatusCancelled, july.Status)
+	_, err = service.amendable(july.ID, user.ID)
+	assert.ErrorContains(t, err, "only approved requests")
+
+	// An approved cancellation is not time off
+	var timeOff int64
+	require.NoError(t, db.Model(&models.AbsenceRequest{}).Where("status = ?", models.RequestStatusApproved).
+		Scopes(timeOffRequests).Count(&timeOff).Error)
+	assert.Zero(t, timeOff)
+
+	// A paid incidence is corrected in the next open period, once
+	early, earlyIncidence := approved(day(7, 3), 3, paid, "processed")
+	modification := change(early, models.RequestChangeModification)
+	_, err = service.amendable(early.ID, user.ID)
+	assert.NoError(t, err)
+	require.NoError(t, db.Model(modification).Update("status", models.RequestStatusPending).Error)
+	_, err = service.amendable(early.ID, user.ID)
+	assert.ErrorContains(t, err, "already exists")
+	require.NoError(t, db.Transaction(func(tx *gorm.DB) error {
+		if err := service.applyChange(tx, modification, hr.ID, day(6, 20)); err != nil {
+			return err
+		}
+		return service.postCorrection(tx, earlyIncidence, modification, hr.ID, day(6, 20))
+	}))
+	var corrections []models.Incidence
+	require.NoError(t, db.Where("corrects_incidence_id = ?", earlyIncidence.ID).Find(&corrections).Error)
+	require.Len(t, corrections, 1)
+	assert.Equal(t, open.ID, corrections[0].PayrollPeriodID)
+	assert.Equal(t, -3.0, corrections[0].Quantity)
+	assert.Equal(t, -1200.0, corrections[0].CalculatedAmount)
+	assert.Equal(t, "approved", corrections[0].Status)
+	assert.Equal(t, modification.ID, *corrections[0].AbsenceRequestID)
+	require.NoError(t, db.First(earlyIncidence, "id = ?", earlyIncidence.ID).Error)
+	assert.Equal(t, "processed", earlyIncidence.Status)
+	assert.Equal(t, before, balance())
+
+	// The original's history records the outcome of the change
+	var history []models.ApprovalHistory
+	require.NoError(t, db.Where("request_id = ?", early.ID).Find(&history).Error)
+	require.Len(t, history, 1)
+	assert.Equal(t, models.ApprovalActionModified, history[0].Action)
+	assert.Equal(t, models.ApprovalStageCompleted, history[0].ApprovalStage)
+
+	// Without an open period after the paid one the change cannot be applied
+	require.NoError(t, db.Model(open).Update("status", "calculated").Error)
+	late, _ := approved(day(7, 10), 1, paid, "processed")
+	lateCancellation := change(late, models.RequestChangeCancellation)
+	err = db.Transaction(func(tx *gorm.DB) error {
+		return service.applyChange(tx, lateCancellation, hr.ID, day(6, 20))
+	})
+	assert.ErrorContains(t, err, "no open payroll period after 2024-BW13")
+}
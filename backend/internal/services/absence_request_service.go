@@ -10,9 +10,52 @@ DESCRIPTION:
     Manages request creation, multi-stage approval, and notifications.
 
 APPROVAL FLOW:
-    - Normal: SUPERVISOR → MANAGER → HR (blue collar) → COMPLETED
-    - SUPANDGM supervisor: Auto-skip to HR stage
-    - White collar: Skip HR after manager approval
+    - Each new request follows the ApprovalWorkflow of its incidence type
+      (ApprovalWorkflowService); the seeded default is the chain below
+    - White collar: SUPERVISOR → MANAGER → HR → GENERAL_MANAGER → PAYROLL
+    - Blue/gray collar: HR_BLUE_GRAY → GENERAL_MANAGER → PAYROLL
+    - SUPANDGM supervisor: SUPERVISOR and MANAGER steps at the head of the
+      route are auto-approved
+    - Requests without a WorkflowID keep the built-in chain (getNextStage)
+    - Substitutes (ApprovalDelegation) act on the delegator's stages; the
+      history records them with OnBehalfOfID
+    - Leave requests are checked against staffing rules and blackouts
+      (RosterService.CheckAbsenceCoverage) when filed and on every
+      approval: warnings are returned, blocking issues reject the action
+
+PARTIAL-DAY REQUESTS:
+    - LATE_ENTRY, EARLY_EXIT, PAID_LEAVE, UNPAID_LEAVE, PERSONAL, OTHER and
+      TIME_FOR_TIME may be filed in hours: StartTime/EndTime on a single
+      day, inside the employee's shift (a late entry starts when the shift
+      starts, an early exit ends when it ends). TotalHours is the window
+      and TotalDays its share of the shift.
+    - TIME_FOR_TIME requests are paid from the time bank (TimeBankService):
+      the hours are checked against the balance when filed and approved
+      and charged at the final approval
+
+CATALOG LEAVES:
+    - LEAVE requests name a LeavePolicy (LeavePolicyService): eligibility
+      and the days left are checked when filed and on every approval, and
+      the policy's documents must be attached before an approval
+    - Their incidences use the policy's IncidenceType
+
+DAY COUNTING:
+    - Whole-day VACATION, PAID_LEAVE, UNPAID_LEAVE, PERSONAL and OTHER
+      requests, and LEAVE requests of policies with CountsWorkDays, take
+      the work days of the range (WorkCalendarService): holidays and the
+      employee's rest days are not counted, whatever the client sent;
+      paid_days / unpaid_days are split again over the counted days
+    - Whole-day TIME_FOR_TIME requests skip holidays the same way
+
+CHANGES AFTER APPROVAL:
+    - RequestCancellation / RequestModification (absence_request_changes.go)
+      file a change request that follows the same route as any request
+    - Its final approval reverses the original (applyChange): incidences
+      are rejected, or corrected in the next open period once paid;
+      vacation days and time bank hours are given back and shift
+      exceptions removed
+    - A cancellation has no incidence, coverage check or time bank charge
+      of its own; a modification is then a regular approved request
 
 ==============================================================================
 */
@@ -21,24 +64,65 @@ package services
 import (
 	"errors"
 	"fmt"
+	"math"
 	"time"
 
 	"github.com/google/uuid"
 	"github.com/xuri/excelize/v2"
 	"gorm.io/gorm"
 
+	"backend/internal/config"
+	"backend/internal/dtos"
 	"backend/internal/models"
 	"backend/internal/models/enums"
 )
 
 // AbsenceRequestService handles absence request operations
 type AbsenceRequestService struct {
-	db *gorm.DB
+	db             *gorm.DB
+	orgStructure   *OrgStructureService
+	vacationLedger *VacationLedgerService
+	workflows      *ApprovalWorkflowService
+	delegations    *ApprovalDelegationService
+	roster         *RosterService
+	timeBank       *TimeBankService
+	attendance     *AttendanceEvaluationService
+	leavePolicies  *LeavePolicyService
+	calendar       *WorkCalendarService
 }
 
 // NewAbsenceRequestService creates a new AbsenceRequestService
-func NewAbsenceRequestService(db *gorm.DB) *AbsenceRequestService {
-	return &AbsenceRequestService{db: db}
+func NewAbsenceRequestService(db *gorm.DB, appConfig *config.AppConfig) *AbsenceRequestService {
+	return &AbsenceRequestService{
+		db:             db,
+		orgStructure:   NewOrgStructureService(db),
+		vacationLedger: NewVacationLedgerService(db, nil),
+		workflows:      NewApprovalWorkflowService(db),
+		delegations:    NewApprovalDelegationService(db),
+		roster:         NewRosterService(db),
+		timeBank:       NewTimeBankService(db),
+		attendance:     NewAttendanceEvaluationService(db, appConfig),
+		leavePolicies:  NewLeavePolicyService(db),
+		calendar:       NewWorkCalendarService(db, appConfig),
+	}
+}
+
+// ErrInvalidAbsenceRequest wraps the validation errors of a new request
+var ErrInvalidAbsenceRequest = errors.New("invalid absence request")
+
+// CoverageBlockedError is returned when an absence request breaks a
+// blocking staffing rule or blackout; Check lists every issue found
+type CoverageBlockedError struct {
+	Check *dtos.CoverageCheck
+}
+
+func (e *CoverageBlockedError) Error() string {
+	for _, issue := range e.Check.Issues {
+		if issue.Enforcement == models.CoverageBlock {
+			return "absence request breaks coverage rules: " + issue.Message
+		}
+	}
+	return "absence request breaks coverage rules"
 }
 
 // CreateAbsenceRequestInput holds the input data for creating an absence request
@@ -55,12 +139,20 @@ type CreateAbsenceRequestInput struct {
 	UnpaidComments string
 	ShiftDetails   string
 	NewShiftID     *uuid.UUID // For SHIFT_CHANGE requests - the target shift
+	StartTime      string     // "HH:MM" for requests in hours, empty for whole days
+	EndTime        string
+	LeavePolicyID  *uuid.UUID // For LEAVE requests - the catalog leave taken
+
+	// Set by RequestCancellation and RequestModification
+	amends     *models.AbsenceRequest
+	changeType string
 }
 
 // CreateAbsenceRequestResult holds the result of creating an absence request
 type CreateAbsenceRequestResult struct {
 	Request     *models.AbsenceRequest
 	IncidenceID uuid.UUID
+	Coverage    *dtos.CoverageCheck // Staffing and blackout warnings
 }
 
 func (s *AbsenceRequestService) CreateAbsenceRequest(input CreateAbsenceRequestInput) (*CreateAbsenceRequestResult, error) {
@@ -70,8 +162,77 @@ func (s *AbsenceRequestService) CreateAbsenceRequest(input CreateAbsenceRequestI
 		return nil, errors.New("employee not found")
 	}
 
+	// Requests in hours are validated against the shift; TIME_FOR_TIME
+	// requests must fit in the time bank. A cancellation keeps the window of
+	// the request it cancels and a modification only needs the extra hours.
+	cancellation := input.changeType == models.RequestChangeCancellation
+	window := requestWindow{}
+	if cancellation {
+		window = requestWindow{startTime: input.amends.StartTime, endTime: input.amends.EndTime, hours: input.amends.TotalHours}
+	} else {
+		var err error
+		if window, err = s.resolveRequestTime(employee.Employee, &input); err != nil {
+			return nil, err
+		}
+	}
+	if input.LeavePolicyID != nil {
+		input.RequestType = models.RequestTypeLeave
+	}
+	if input.RequestType == models.RequestTypeTimeForTime && !cancellation {
+		hours := window.hours
+		if input.amends != nil {
+			hours -= input.amends.TotalHours
+		}
+		if err := s.timeBank.CheckConsumption(employee.Employee, hours, input.StartDate, nil); err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidAbsenceRequest, err)
+		}
+	}
+
+	// LEAVE requests name an active policy of the catalog
+	var leavePolicy *models.LeavePolicy
+	if input.RequestType == models.RequestTypeLeave && !cancellation {
+		if input.LeavePolicyID == nil {
+			return nil, fmt.Errorf("%w: leave policy is required", ErrInvalidAbsenceRequest)
+		}
+		if employee.Employee == nil {
+			return nil, errors.New("employee not found")
+		}
+		var err error
+		if leavePolicy, err = s.leavePolicies.activePolicy(employee.Employee.CompanyID, *input.LeavePolicyID); err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidAbsenceRequest, err)
+		}
+	}
+
+	// Whole-day absences take the employee's work days only: the holidays
+	// and rest days they span are not counted
+	if !cancellation && window.hours == 0 && countsWorkDays(input.RequestType, leavePolicy) {
+		if err := s.countRequestDays(employee.Employee, &input); err != nil {
+			return nil, err
+		}
+	}
+
+	// LEAVE requests must be allowed by their policy: eligibility and the
+	// days left of the entitlement, without the request a modification replaces
+	if leavePolicy != nil {
+		var exclude []uuid.UUID
+		if input.amends != nil {
+			exclude = append(exclude, input.amends.ID)
+		}
+		if err := s.leavePolicies.CheckRequest(leavePolicy, employee.Employee, input.StartDate, input.TotalDays, exclude...); err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidAbsenceRequest, err)
+		}
+	}
+
+	// Resolve the approver through the reporting hierarchy; when the direct
+	// manager is on leave the request goes to the next manager up
+	supervisorID := employee.SupervisorID
+	if resolution, err := s.orgStructure.ResolveApproverForUser(employee.ID, time.Now()); err == nil &&
+		resolution != nil && resolution.ApproverUserID != nil {
+		supervisorID = resolution.ApproverUserID
+	}
+
 	// Validate supervisor configuration
-	if employee.SupervisorID == nil {
+	if supervisorID == nil {
 		return nil, errors.New("employee has no assigned supervisor - please contact HR to configure your supervisor")
 	}
 
@@ -82,7 +243,7 @@ func (s *AbsenceRequestService) CreateAbsenceRequest(input CreateAbsenceRequestI
 
 	// Verify supervisor exists
 	var supervisor models.User
-	if err := s.db.First(&supervisor, "id = ?", employee.SupervisorID).Error; err != nil {
+	if err := s.db.First(&supervisor, "id = ?", *supervisorID).Error; err != nil {
 		return nil, errors.New("assigned supervisor not found in system - please contact HR")
 	}
 
@@ -92,24 +253,21 @@ func (s *AbsenceRequestService) CreateAbsenceRequest(input CreateAbsenceRequestI
 		return nil, errors.New("assigned general manager not found in system - please contact HR")
 	}
 
-	// Determine initial approval stage based on collar type
-	var emp models.Employee
-	isBlueOrGrayCollar := false
-	if employee.EmployeeID != nil {
-		s.db.First(&emp, "id = ?", *employee.EmployeeID)
-		isBlueOrGrayCollar = emp.CollarType == "blue_collar" ||
-			emp.CollarType == "gray_collar" ||
-			emp.IsSindicalizado
-	}
+	isSupervisorSUPANDGM := supervisor.Role == enums.RoleSupAndGM
 
-	// Initial stage: HR_BLUE_GRAY for blue/gray collar, SUPERVISOR for white collar
-	initialStage := models.ApprovalStageSupervisor
-	if isBlueOrGrayCollar {
-		initialStage = models.ApprovalStageHRBlueGray
+	// Check the staffing rules and blackouts of the employee's area; a
+	// cancellation only gives staff back
+	var coverage *dtos.CoverageCheck
+	if !cancellation {
+		var err error
+		if coverage, err = s.checkCoverage(input.EmployeeID, input.RequestType, input.StartDate, input.EndDate); err != nil {
+			return nil, err
+		}
+		if coverage.Blocked {
+			return nil, &CoverageBlockedError{Check: coverage}
+		}
 	}
 
-	isSupervisorSUPANDGM := supervisor.Role == enums.RoleSupAndGM
-
 	// Create the request
 	now := time.Now()
 	cutoff := s.calculatePayrollCutoff(&models.AbsenceRequest{
@@ -125,16 +283,27 @@ func (s *AbsenceRequestService) CreateAbsenceRequest(input CreateAbsenceRequestI
 		TotalDays:            input.TotalDays,
 		Reason:               input.Reason,
 		Status:               models.RequestStatusPending,
-		CurrentApprovalStage: initialStage,
 		HoursPerDay:          input.HoursPerDay,
 		PaidDays:             input.PaidDays,
 		UnpaidDays:           input.UnpaidDays,
 		UnpaidComments:       input.UnpaidComments,
 		ShiftDetails:         input.ShiftDetails,
 		NewShiftID:           input.NewShiftID,
+		StartTime:            window.startTime,
+		EndTime:              window.endTime,
+		TotalHours:           window.hours,
+		ChangeType:           input.changeType,
+		LeavePolicyID:        input.LeavePolicyID,
 		LastActionAt:         now,
 		PayrollCutoffDate:    &cutoff,
 	}
+	if leavePolicy != nil {
+		request.IncidenceTypeID = leavePolicy.IncidenceTypeID
+	}
+	if input.amends != nil {
+		request.AmendsID = &input.amends.ID
+		request.IncidenceTypeID = input.amends.IncidenceTypeID
+	}
 
 	// Start transaction
 	tx := s.db.Begin()
@@ -150,62 +319,89 @@ func (s *AbsenceRequestService) CreateAbsenceRequest(input CreateAbsenceRequestI
 		return nil, err
 	}
 
-	// Create corresponding incidence immediately for evidence upload
-	incidence, err := s.createInitialIncidence(tx, request, &employee)
-	if err != nil {
-		tx.Rollback()
-		return nil, fmt.Errorf("failed to create initial incidence: %w", err)
-	}
-
-	// Get request type display name
-	requestTypeName := string(input.RequestType)
-
-	// If supervisor is SUPANDGM, auto-approve supervisor and manager stages
-	if isSupervisorSUPANDGM {
-		// Add auto-approval for SUPERVISOR stage
-		supervisorApproval := &models.ApprovalHistory{
-			RequestID:     request.ID,
-			ApproverID:    *employee.SupervisorID,
-			ApprovalStage: models.ApprovalStageSupervisor,
-			Action:        models.ApprovalActionApproved,
-			Comments:      "Auto-approved (Supervisor is also General Manager)",
-		}
-		if err := tx.Create(supervisorApproval).Error; err != nil {
+	// Create corresponding incidence immediately for evidence upload; a
+	// cancellation has nothing to pay or deduct
+	incidenceID := uuid.Nil
+	if !cancellation {
+		incidence, err := s.createInitialIncidence(tx, request, &employee)
+		if err != nil {
 			tx.Rollback()
-			return nil, err
+			return nil, fmt.Errorf("failed to create initial incidence: %w", err)
 		}
-
-		// Add auto-approval for MANAGER stage
-		managerApproval := &models.ApprovalHistory{
-			RequestID:     request.ID,
-			ApproverID:    *employee.SupervisorID,
-			ApprovalStage: models.ApprovalStageManager,
-			Action:        models.ApprovalActionApproved,
-			Comments:      "Auto-approved (User is both Supervisor and General Manager)",
+		incidenceID = incidence.ID
+		if request.IncidenceTypeID == nil {
+			request.IncidenceTypeID = &incidence.IncidenceTypeID
 		}
-		if err := tx.Create(managerApproval).Error; err != nil {
+	}
+
+	// The original request's history shows the change was asked for
+	if input.amends != nil {
+		if err := s.recordChange(tx, input.amends.ID, input.EmployeeID, models.ApprovalActionChangeRequested,
+			fmt.Sprintf("%s solicitada: %s", changeLabel(input.changeType), input.Reason)); err != nil {
 			tx.Rollback()
 			return nil, err
 		}
+	}
 
-		// Update request to HR stage
-		request.CurrentApprovalStage = models.ApprovalStageHR
-		if err := tx.Save(request).Error; err != nil {
-			tx.Rollback()
-			return nil, err
+	// Get request type display name
+	requestTypeName := string(input.RequestType)
+
+	// Route the request through the approval workflow of its incidence type
+	workflow, subject, groups, err := s.workflows.withDB(tx).start(request)
+	if err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+	request.WorkflowID = &workflow.ID
+
+	// If supervisor is SUPANDGM, auto-approve the supervisor and manager
+	// stages at the head of the route
+	autoApproved := false
+	for isSupervisorSUPANDGM && len(groups) > 1 && supervisorOrManagerGroup(groups[0]) {
+		for _, stage := range groups[0].stages(subject) {
+			comments := "Auto-approved (User is both Supervisor and General Manager)"
+			if stage == models.ApprovalStageSupervisor {
+				comments = "Auto-approved (Supervisor is also General Manager)"
+			}
+			approval := &models.ApprovalHistory{
+				RequestID:     request.ID,
+				ApproverID:    *supervisorID,
+				ApprovalStage: stage,
+				Action:        models.ApprovalActionApproved,
+				Comments:      comments,
+			}
+			if err := tx.Create(approval).Error; err != nil {
+				tx.Rollback()
+				return nil, err
+			}
 		}
+		groups = groups[1:]
+		autoApproved = true
+	}
+
+	setAwaitingStages(request, groups[0].stages(subject))
+	if err := tx.Save(request).Error; err != nil {
+		tx.Rollback()
+		return nil, err
+	}
 
+	if autoApproved {
 		// Notify HR users (notification only, no inbox message)
 		s.notifyHRUsers(tx, request, &employee)
 	} else {
 		// Normal flow - notify supervisor with both notification AND inbox message
-		s.notifyApproverWithMessage(tx, *employee.SupervisorID, request.ID, employee.FullName, requestTypeName)
+		s.notifyApproverWithMessage(tx, *supervisorID, request.ID, employee.FullName, requestTypeName)
 
 		// Also notify general manager if configured (with both notification AND inbox message)
-		if employee.GeneralManagerID != nil && *employee.GeneralManagerID != *employee.SupervisorID {
+		if employee.GeneralManagerID != nil && *employee.GeneralManagerID != *supervisorID {
 			s.notifyApproverWithMessage(tx, *employee.GeneralManagerID, request.ID, employee.FullName, requestTypeName)
 		}
 	}
+	for _, stage := range awaitingStages(request) {
+		if !isBuiltInStage(stage) {
+			s.notifyNextApprovers(tx, request, stage)
+		}
+	}
 
 	if err := tx.Commit().Error; err != nil {
 		return nil, err
@@ -213,10 +409,33 @@ func (s *AbsenceRequestService) CreateAbsenceRequest(input CreateAbsenceRequestI
 
 	return &CreateAbsenceRequestResult{
 		Request:     request,
-		IncidenceID: incidence.ID,
+		IncidenceID: incidenceID,
+		Coverage:    coverage,
 	}, nil
 }
 
+// CheckCoverage previews the staffing and blackout issues of a request the
+// user is about to file
+func (s *AbsenceRequestService) CheckCoverage(userID uuid.UUID, requestType models.RequestType, startDate, endDate time.Time) (*dtos.CoverageCheck, error) {
+	if endDate.Before(startDate) {
+		return nil, errors.New("end date must be on or after start date")
+	}
+	return s.checkCoverage(userID, requestType, startDate, endDate)
+}
+
+// checkCoverage checks a request of the user against the staffing rules and
+// blackouts; users without an employee record have nothing to check
+func (s *AbsenceRequestService) checkCoverage(userID uuid.UUID, requestType models.RequestType, startDate, endDate time.Time) (*dtos.CoverageCheck, error) {
+	var user models.User
+	if err := s.db.Preload("Employee").First(&user, "id = ?", userID).Error; err != nil {
+		return nil, errors.New("employee not found")
+	}
+	if user.Employee == nil {
+		return &dtos.CoverageCheck{Issues: []dtos.CoverageIssue{}}, nil
+	}
+	return s.roster.CheckAbsenceCoverage(user.Employee, requestType, startDate, endDate)
+}
+
 // GetMyRequests returns all active requests for an employee
 func (s *AbsenceRequestService) GetMyRequests(employeeID uuid.UUID) ([]models.AbsenceRequest, error) {
 	var requests []models.AbsenceRequest
@@ -231,23 +450,34 @@ func (s *AbsenceRequestService) GetMyRequests(employeeID uuid.UUID) ([]models.Ab
 // GetPendingRequestsForSupervisor returns requests pending supervisor approval
 func (s *AbsenceRequestService) GetPendingRequestsForSupervisor(supervisorID uuid.UUID) ([]models.AbsenceRequest, error) {
 	var requests []models.AbsenceRequest
-	err := s.db.Preload("Employee").
+	query := s.db.Preload("Employee").
 		Preload("ApprovalHistory").
-		Joins("JOIN users ON users.id = absence_requests.employee_id").
-		Where("users.supervisor_id = ? AND absence_requests.status = ? AND absence_requests.current_approval_stage = ?",
-			supervisorID, models.RequestStatusPending, models.ApprovalStageSupervisor).
+		Joins("JOIN users ON users.id = absence_requests.employee_id")
+	err := s.supervisorScope(query, supervisorID).
+		Where("absence_requests.status = ?", models.RequestStatusPending).
+		Scopes(awaitingStage(models.ApprovalStageSupervisor)).
 		Order("absence_requests.created_at DESC").
 		Find(&requests).Error
 	return requests, err
 }
 
+// supervisorScope limits a users-joined query to the requests a supervisor
+// handles: direct reports plus the teams of managers below them on leave
+func (s *AbsenceRequestService) supervisorScope(query *gorm.DB, supervisorID uuid.UUID) *gorm.DB {
+	scope, _ := s.orgStructure.ApprovalScopeUserIDs(supervisorID, time.Now())
+	if len(scope) == 0 {
+		return query.Where("users.supervisor_id = ?", supervisorID)
+	}
+	return query.Where("(users.supervisor_id = ? OR absence_requests.employee_id IN ?)", supervisorID, scope)
+}
+
 // GetPendingRequestsForManager returns requests pending manager approval
 func (s *AbsenceRequestService) GetPendingRequestsForManager() ([]models.AbsenceRequest, error) {
 	var requests []models.AbsenceRequest
 	err := s.db.Preload("Employee").
 		Preload("ApprovalHistory").
-		Where("status = ? AND current_approval_stage = ?",
-			models.RequestStatusPending, models.ApprovalStageManager).
+		Where("status = ?", models.RequestStatusPending).
+		Scopes(awaitingStage(models.ApprovalStageManager)).
 		Order("created_at DESC").
 		Find(&requests).Error
 	return requests, err
@@ -267,8 +497,8 @@ func (s *AbsenceRequestService) GetPendingRequestsForHR(hrUserID uuid.UUID) ([]m
 	var requests []models.AbsenceRequest
 	query := s.db.Preload("Employee").
 		Preload("ApprovalHistory").
-		Where("status = ? AND current_approval_stage = ?",
-			models.RequestStatusPending, models.ApprovalStageHR)
+		Where("status = ?", models.RequestStatusPending).
+		Scopes(awaitingStage(models.ApprovalStageHR))
 
 	// Filter by employee types if HR has assignments
 	if len(employeeTypes) > 0 {
@@ -289,8 +519,8 @@ func (s *AbsenceRequestService) GetPendingRequestsForHRBlueGray(hrUserID uuid.UU
 		Preload("ApprovalHistory").
 		Joins("JOIN users ON users.id = absence_requests.employee_id").
 		Joins("LEFT JOIN employees ON employees.id = users.employee_id").
-		Where("absence_requests.status = ? AND absence_requests.current_approval_stage = ?",
-			models.RequestStatusPending, models.ApprovalStageHRBlueGray).
+		Where("absence_requests.status = ?", models.RequestStatusPending).
+		Scopes(awaitingStage(models.ApprovalStageHRBlueGray)).
 		Where("employees.collar_type IN ? OR employees.is_sindicalizado = ?",
 			[]string{"blue_collar", "gray_collar"}, true).
 		Order("absence_requests.created_at DESC").
@@ -303,8 +533,8 @@ func (s *AbsenceRequestService) GetPendingRequestsForGeneralManager() ([]models.
 	var requests []models.AbsenceRequest
 	err := s.db.Preload("Employee").
 		Preload("ApprovalHistory").
-		Where("status = ? AND current_approval_stage = ?",
-			models.RequestStatusPending, models.ApprovalStageGeneralManager).
+		Where("status = ?", models.RequestStatusPending).
+		Scopes(awaitingStage(models.ApprovalStageGeneralManager)).
 		Order("created_at DESC").
 		Find(&requests).Error
 	return requests, err
@@ -315,7 +545,8 @@ func (s *AbsenceRequestService) GetApprovedRequests(filters map[string]interface
 	query := s.db.Preload("Employee").
 		Preload("ApprovalHistory").
 		Preload("ApprovalHistory.Approver").
-		Where("status = ?", models.RequestStatusApproved)
+		Where("status = ?", models.RequestStatusApproved).
+		Scopes(timeOffRequests)
 
 	if periodID, ok := filters["period_id"].(string); ok && periodID != "" {
 		query = query.Joins("LEFT JOIN incidences ON incidences.absence_request_id = absence_requests.id").
@@ -336,7 +567,13 @@ func (s *AbsenceRequestService) GetApprovedRequests(filters map[string]interface
 	return requests, err
 }
 
-// ApproveRequest processes an approval or decline action
+// timeOffRequests leaves out approved cancellations: they share the dates of
+// the request they cancel but are not time off
+func timeOffRequests(db *gorm.DB) *gorm.DB {
+	return db.Where("absence_requests.change_type IS NULL OR absence_requests.change_type <> ?", models.RequestChangeCancellation)
+}
+
+// ApproveRequestInput holds the input data for an approval or decline action
 type ApproveRequestInput struct {
 	RequestID uuid.UUID
 	ApproverID uuid.UUID
@@ -345,30 +582,72 @@ type ApproveRequestInput struct {
 	Comments  string
 }
 
-func (s *AbsenceRequestService) ApproveRequest(input ApproveRequestInput) error {
+// ApproveRequest processes an approval or decline action; approvals return
+// the coverage warnings of the request and fail with CoverageBlockedError
+// when it breaks a blocking staffing rule or blackout
+func (s *AbsenceRequestService) ApproveRequest(input ApproveRequestInput) (*dtos.CoverageCheck, error) {
 	// Get the request
 	var request models.AbsenceRequest
 	if err := s.db.Preload("Employee").First(&request, "id = ?", input.RequestID).Error; err != nil {
-		return errors.New("request not found")
+		return nil, errors.New("request not found")
 	}
 
 	// Verify request is pending and at correct stage
 	if request.Status != models.RequestStatusPending {
-		return errors.New("request is not pending")
+		return nil, errors.New("request is not pending")
 	}
-	if request.CurrentApprovalStage != input.Stage {
-		return errors.New("request is not at the specified approval stage")
+	if !request.AwaitsStage(input.Stage) {
+		return nil, errors.New("request is not at the specified approval stage")
 	}
 
 	// Get approver for role verification
 	var approver models.User
 	if err := s.db.First(&approver, "id = ?", input.ApproverID).Error; err != nil {
-		return errors.New("approver not found")
+		return nil, errors.New("approver not found")
+	}
+
+	// Verify approver has permission for this stage, either their own or
+	// through a delegation
+	onBehalfOf, err := s.actingAuthority(&request, &approver, input.Stage)
+	if err != nil {
+		return nil, err
+	}
+
+	// Approvals are checked against the staffing rules and blackouts again:
+	// colleagues' leave may have been approved since the request was filed
+	var coverage *dtos.CoverageCheck
+	cancellation := request.ChangeType == models.RequestChangeCancellation
+	if input.Action == models.ApprovalActionApproved && !cancellation {
+		coverage, err = s.checkCoverage(request.EmployeeID, request.RequestType, request.StartDate, request.EndDate)
+		if err != nil {
+			return nil, err
+		}
+		if coverage.Blocked {
+			return nil, &CoverageBlockedError{Check: coverage}
+		}
 	}
 
-	// Verify approver has permission for this stage
-	if !s.canApproveStage(approver.Role, input.Stage) {
-		return errors.New("unauthorized for this approval stage")
+	// TIME_FOR_TIME requests are paid from the time bank; the balance may
+	// have been used since the request was filed. A modification is only
+	// charged once the original's hours are given back.
+	var bankEmployee *models.Employee
+	if request.RequestType == models.RequestTypeTimeForTime && request.TotalHours > 0 && !cancellation {
+		if bankEmployee, err = s.requestEmployee(s.db, &request); err != nil {
+			return nil, err
+		}
+		if input.Action == models.ApprovalActionApproved && !request.IsChange() {
+			if err := s.timeBank.CheckConsumption(bankEmployee, request.TotalHours, request.StartDate, &request.ID); err != nil {
+				return nil, fmt.Errorf("%w: %v", ErrInvalidAbsenceRequest, err)
+			}
+		}
+	}
+
+	// LEAVE requests are checked against their policy again and need its
+	// documents attached before they are approved
+	if request.LeavePolicyID != nil && input.Action == models.ApprovalActionApproved && !cancellation {
+		if err := s.checkLeave(&request); err != nil {
+			return nil, err
+		}
 	}
 
 	// Start transaction
@@ -386,10 +665,15 @@ func (s *AbsenceRequestService) ApproveRequest(input ApproveRequestInput) error
 		ApprovalStage: input.Stage,
 		Action:        input.Action,
 		Comments:      input.Comments,
+		OnBehalfOfID:  onBehalfOf,
 	}
 	if err := tx.Create(history).Error; err != nil {
 		tx.Rollback()
-		return err
+		return nil, err
+	}
+	if onBehalfOf != nil {
+		s.createNotification(tx, *onBehalfOf, request.ID,
+			fmt.Sprintf("%s atendió en tu nombre una solicitud de ausencia (%s)", approver.FullName, input.Action))
 	}
 
 	// Calculate payroll cutoff if not already set
@@ -405,7 +689,7 @@ func (s *AbsenceRequestService) ApproveRequest(input ApproveRequestInput) error
 	if input.Action == models.ApprovalActionDeclined {
 		// Decline the request
 		request.Status = models.RequestStatusDeclined
-		request.CurrentApprovalStage = models.ApprovalStageCompleted
+		setAwaitingStages(&request, nil)
 
 		// If rejected by HR or GM, exclude from payroll export
 		isHRorGM := (input.Stage == models.ApprovalStageHR ||
@@ -421,15 +705,26 @@ func (s *AbsenceRequestService) ApproveRequest(input ApproveRequestInput) error
 
 		if err := tx.Save(&request).Error; err != nil {
 			tx.Rollback()
-			return err
+			return nil, err
+		}
+		if request.IsChange() {
+			if err := s.recordChange(tx, *request.AmendsID, input.ApproverID, models.ApprovalActionChangeDeclined,
+				fmt.Sprintf("%s rechazada: %s", changeLabel(request.ChangeType), input.Comments)); err != nil {
+				tx.Rollback()
+				return nil, err
+			}
 		}
 
 		// Notify employee
 		s.createNotification(tx, request.EmployeeID, request.ID,
 			fmt.Sprintf("Tu solicitud de ausencia ha sido rechazada por %s", approver.FullName))
 	} else {
-		// Approved - determine next stage
-		nextStage := s.getNextStage(input.Stage, &request)
+		// Approved - determine next stage(s)
+		nextStages, advanced, err := s.nextStages(tx, input.Stage, &request)
+		if err != nil {
+			tx.Rollback()
+			return nil, err
+		}
 
 		// Check if this is a late approval (after payroll cutoff)
 		isLate := s.isLateApproval(&request, now)
@@ -440,10 +735,10 @@ func (s *AbsenceRequestService) ApproveRequest(input ApproveRequestInput) error
 				request.PayrollCutoffDate.Format("2006-01-02 15:04:05"))
 		}
 
-		if nextStage == "" {
+		if len(nextStages) == 0 {
 			// Final approval
 			request.Status = models.RequestStatusApproved
-			request.CurrentApprovalStage = models.ApprovalStageCompleted
+			setAwaitingStages(&request, nil)
 
 			// Update linked incidence with late approval flag
 			if err := s.updateLinkedIncidenceFlags(tx, &request, isLate, false); err != nil {
@@ -452,17 +747,36 @@ func (s *AbsenceRequestService) ApproveRequest(input ApproveRequestInput) error
 
 			if err := tx.Save(&request).Error; err != nil {
 				tx.Rollback()
-				return err
+				return nil, err
 			}
 
-			// Create incidence in payroll system
-			if err := s.createPayrollIncidence(tx, &request, input.ApproverID); err != nil {
-				// Log error but don't fail the approval
-				fmt.Printf("Warning: Failed to create payroll incidence for request %s: %v\n", request.ID, err)
+			// An approved change reverses the request it cancels or modifies
+			if request.IsChange() {
+				if err := s.applyChange(tx, &request, input.ApproverID, now); err != nil {
+					tx.Rollback()
+					return nil, err
+				}
+			}
+
+			// Charge the hours to the time bank
+			if bankEmployee != nil {
+				if err := s.timeBank.withDB(tx).Consume(&request, bankEmployee, &input.ApproverID); err != nil {
+					tx.Rollback()
+					return nil, fmt.Errorf("%w: %v", ErrInvalidAbsenceRequest, err)
+				}
+			}
+
+			// Create incidence in payroll system (a cancellation has nothing
+			// to pay or deduct)
+			if !cancellation {
+				if err := s.createPayrollIncidence(tx, &request, input.ApproverID); err != nil {
+					// Log error but don't fail the approval
+					fmt.Printf("Warning: Failed to create payroll incidence for request %s: %v\n", request.ID, err)
+				}
 			}
 
 			// Create shift exception for SHIFT_CHANGE requests (makes the change reflect on schedule)
-			if request.RequestType == models.RequestTypeShiftChange {
+			if request.RequestType == models.RequestTypeShiftChange && !cancellation {
 				if err := s.createShiftException(tx, &request, input.ApproverID); err != nil {
 					fmt.Printf("Warning: Failed to create shift exception for request %s: %v\n", request.ID, err)
 				}
@@ -474,9 +788,17 @@ func (s *AbsenceRequestService) ApproveRequest(input ApproveRequestInput) error
 
 			// Notify payroll
 			s.notifyPayrollUsers(tx, &request)
+
+			// An approver going on leave gets a substitute suggested
+			if !cancellation {
+				if suggestion, err := s.delegations.withDB(tx).SuggestForAbsence(&request); err == nil && suggestion != nil {
+					s.createNotification(tx, request.EmployeeID, suggestion.ID,
+						"Tu ausencia fue aprobada: confirma quién aprobará solicitudes en tu lugar durante esas fechas")
+				}
+			}
 		} else {
-			// Move to next stage
-			request.CurrentApprovalStage = nextStage
+			// Move to next stage (or wait for the rest of a parallel group)
+			setAwaitingStages(&request, nextStages)
 
 			// Update linked incidence with late approval flag (but not excluded)
 			if err := s.updateLinkedIncidenceFlags(tx, &request, isLate, false); err != nil {
@@ -485,15 +807,22 @@ func (s *AbsenceRequestService) ApproveRequest(input ApproveRequestInput) error
 
 			if err := tx.Save(&request).Error; err != nil {
 				tx.Rollback()
-				return err
+				return nil, err
 			}
 
 			// Notify next approvers
-			s.notifyNextApprovers(tx, &request, nextStage)
+			if advanced {
+				for _, nextStage := range nextStages {
+					s.notifyNextApprovers(tx, &request, nextStage)
+				}
+			}
 		}
 	}
 
-	return tx.Commit().Error
+	if err := tx.Commit().Error; err != nil {
+		return nil, err
+	}
+	return coverage, nil
 }
 
 // ArchiveRequest archives (soft deletes) a request
@@ -552,6 +881,7 @@ func (s *AbsenceRequestService) GetOverlappingAbsences(supervisorID uuid.UUID, s
 
 	query := s.db.Preload("Employee").
 		Where("employee_id IN ? AND status = ?", employeeIDs, models.RequestStatusApproved).
+		Scopes(timeOffRequests).
 		Where("(start_date <= ? AND end_date >= ?) OR (start_date <= ? AND end_date >= ?) OR (start_date >= ? AND end_date <= ?)",
 			endDate, startDate, endDate, startDate, startDate, endDate)
 
@@ -570,39 +900,50 @@ func (s *AbsenceRequestService) GetPendingCounts(userID uuid.UUID, role enums.Us
 
 	// Supervisor count
 	if role == enums.RoleSupervisor || role == enums.RoleSupAndGM || role == enums.RoleHRAndPR {
-		s.db.Model(&models.AbsenceRequest{}).
-			Joins("JOIN users ON users.id = absence_requests.employee_id").
-			Where("users.supervisor_id = ? AND absence_requests.status = ? AND absence_requests.current_approval_stage = ?",
-				userID, models.RequestStatusPending, models.ApprovalStageSupervisor).
+		query := s.db.Model(&models.AbsenceRequest{}).
+			Joins("JOIN users ON users.id = absence_requests.employee_id")
+		s.supervisorScope(query, userID).
+			Where("absence_requests.status = ?", models.RequestStatusPending).
+			Scopes(awaitingStage(models.ApprovalStageSupervisor)).
 			Count(&supervisorCount)
 	}
 
 	// Manager count
 	if role == enums.RoleManager || role == enums.RoleSupAndGM {
 		s.db.Model(&models.AbsenceRequest{}).
-			Where("status = ? AND current_approval_stage = ?",
-				models.RequestStatusPending, models.ApprovalStageManager).
+			Where("status = ?", models.RequestStatusPending).
+			Scopes(awaitingStage(models.ApprovalStageManager)).
 			Count(&managerCount)
 	}
 
 	// HR count
 	if role == enums.RoleHR || role == enums.RoleHRAndPR {
 		s.db.Model(&models.AbsenceRequest{}).
-			Where("status = ? AND current_approval_stage = ?",
-				models.RequestStatusPending, models.ApprovalStageHR).
+			Where("status = ?", models.RequestStatusPending).
+			Scopes(awaitingStage(models.ApprovalStageHR)).
 			Count(&hrCount)
 	}
 
+	// Requests waiting for users who delegated their approvals to this one
+	delegated, _ := s.GetDelegatedPendingRequests(userID)
+
 	return map[string]int64{
 		"supervisor": supervisorCount,
 		"manager":    managerCount,
 		"hr":         hrCount,
+		"delegated":  int64(len(delegated)),
 	}
 }
 
 // Helper functions
 
 func (s *AbsenceRequestService) canApproveStage(role enums.UserRole, stage models.ApprovalStage) bool {
+	return canApproveStage(role, stage)
+}
+
+// canApproveStage reports whether a role may act on an approval stage; shared
+// by every request that follows the absence approval chain
+func canApproveStage(role enums.UserRole, stage models.ApprovalStage) bool {
 	switch stage {
 	case models.ApprovalStageSupervisor:
 		return role == enums.RoleSupervisor || role == enums.RoleSupAndGM
@@ -616,6 +957,157 @@ func (s *AbsenceRequestService) canApproveStage(role enums.UserRole, stage model
 	return false
 }
 
+// actingAuthority checks that approver may act on the request at stage and
+// returns whose authority they use: nil for their own, the delegator's ID
+// when they act as a substitute
+func (s *AbsenceRequestService) actingAuthority(request *models.AbsenceRequest, approver *models.User, stage models.ApprovalStage) (*uuid.UUID, error) {
+	allowed, err := s.authorized(request, approver, stage, false)
+	if err != nil {
+		return nil, err
+	}
+	if allowed {
+		return nil, nil
+	}
+
+	delegations, err := s.delegations.ActiveFor(approver.ID, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	for _, delegation := range delegations {
+		if !delegation.Covers(request.RequestType) {
+			continue
+		}
+		var delegator models.User
+		if err := s.db.First(&delegator, "id = ? AND is_active = ?", delegation.DelegatorID, true).Error; err != nil {
+			continue
+		}
+		if allowed, _ := s.authorized(request, &delegator, stage, true); allowed {
+			return &delegator.ID, nil
+		}
+	}
+	return nil, errors.New("unauthorized for this approval stage")
+}
+
+// authorized reports whether user holds the authority for stage. Acting for
+// someone else, a supervisor's authority only reaches their own team
+func (s *AbsenceRequestService) authorized(request *models.AbsenceRequest, user *models.User, stage models.ApprovalStage, delegated bool) (bool, error) {
+	if request.WorkflowID != nil {
+		return s.workflows.CanApprove(request, user, stage)
+	}
+	if !s.canApproveStage(user.Role, stage) {
+		return false, nil
+	}
+	if !delegated || stage != models.ApprovalStageSupervisor {
+		return true, nil
+	}
+	var count int64
+	query := s.db.Model(&models.AbsenceRequest{}).
+		Joins("JOIN users ON users.id = absence_requests.employee_id").
+		Where("absence_requests.id = ?", request.ID)
+	if err := s.supervisorScope(query, user.ID).Count(&count).Error; err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
+
+// GetDelegatedPendingRequests returns the requests waiting for the users who
+// delegated their approvals to delegateID, tagged with DelegatedFromID
+func (s *AbsenceRequestService) GetDelegatedPendingRequests(delegateID uuid.UUID) ([]models.AbsenceRequest, error) {
+	delegations, err := s.delegations.ActiveFor(delegateID, time.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	seen := make(map[uuid.UUID]bool)
+	requests := make([]models.AbsenceRequest, 0)
+	for _, delegation := range delegations {
+		var delegator models.User
+		if err := s.db.First(&delegator, "id = ? AND is_active = ?", delegation.DelegatorID, true).Error; err != nil {
+			continue
+		}
+		queue, err := s.pendingForUser(&delegator)
+		if err != nil {
+			return nil, err
+		}
+		for i := range queue {
+			if seen[queue[i].ID] || !delegation.Covers(queue[i].RequestType) {
+				continue
+			}
+			seen[queue[i].ID] = true
+			queue[i].DelegatedFromID = &delegator.ID
+			requests = append(requests, queue[i])
+		}
+	}
+	return requests, nil
+}
+
+// pendingForUser gathers every queue a user's own authority reaches
+func (s *AbsenceRequestService) pendingForUser(user *models.User) ([]models.AbsenceRequest, error) {
+	var all []models.AbsenceRequest
+	add := func(requests []models.AbsenceRequest, err error) error {
+		all = append(all, requests...)
+		return err
+	}
+	if s.canApproveStage(user.Role, models.ApprovalStageSupervisor) {
+		if err := add(s.GetPendingRequestsForSupervisor(user.ID)); err != nil {
+			return nil, err
+		}
+	}
+	if s.canApproveStage(user.Role, models.ApprovalStageManager) {
+		if err := add(s.GetPendingRequestsForManager()); err != nil {
+			return nil, err
+		}
+		if err := add(s.GetPendingRequestsForGeneralManager()); err != nil {
+			return nil, err
+		}
+	}
+	if s.canApproveStage(user.Role, models.ApprovalStageHR) {
+		if err := add(s.GetPendingRequestsForHR(user.ID)); err != nil {
+			return nil, err
+		}
+		if err := add(s.GetPendingRequestsForHRBlueGray(user.ID)); err != nil {
+			return nil, err
+		}
+	}
+	if s.canApproveStage(user.Role, models.ApprovalStagePayroll) {
+		var payroll []models.AbsenceRequest
+		err := s.db.Preload("Employee").
+			Preload("ApprovalHistory").
+			Where("status = ?", models.RequestStatusPending).
+			Scopes(awaitingStage(models.ApprovalStagePayroll)).
+			Order("created_at DESC").
+			Find(&payroll).Error
+		if err := add(payroll, err); err != nil {
+			return nil, err
+		}
+	}
+	if err := add(s.workflows.PendingFor(user.ID)); err != nil {
+		return nil, err
+	}
+	return all, nil
+}
+
+// nextStages returns the stages a request waits in after an approval at
+// stage; requests without a workflow follow the built-in chain
+func (s *AbsenceRequestService) nextStages(tx *gorm.DB, stage models.ApprovalStage, request *models.AbsenceRequest) ([]models.ApprovalStage, bool, error) {
+	if request.WorkflowID != nil {
+		return s.workflows.withDB(tx).Next(request, stage)
+	}
+	if next := s.getNextStage(stage, request); next != "" {
+		return []models.ApprovalStage{next}, true, nil
+	}
+	return nil, true, nil
+}
+
+// awaitingStage scopes a query to requests waiting at stage, as their
+// current stage or as one of their parallel stages
+func awaitingStage(stage models.ApprovalStage) func(*gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		return db.Where("(absence_requests.current_approval_stage = ? OR absence_requests.pending_stages LIKE ?)",
+			stage, "%|"+string(stage)+"|%")
+	}
+}
+
 func (s *AbsenceRequestService) getNextStage(currentStage models.ApprovalStage, request *models.AbsenceRequest) models.ApprovalStage {
 	// 6-Level Workflow (NEW):
 	// Employee → SUPERVISOR → MANAGER → HR (role-based) → GENERAL_MANAGER → PAYROLL → COMPLETED
@@ -634,10 +1126,18 @@ func (s *AbsenceRequestService) getNextStage(currentStage models.ApprovalStage,
 		}
 	}
 
-	isBlueOrGrayCollar := employee.CollarType == "blue_collar" ||
+	return nextApprovalStage(currentStage, isBlueOrGrayCollar(&employee))
+}
+
+// isBlueOrGrayCollar reports whether HR_BLUE_GRAY handles the employee's requests
+func isBlueOrGrayCollar(employee *models.Employee) bool {
+	return employee.CollarType == "blue_collar" ||
 		employee.CollarType == "gray_collar" ||
 		employee.IsSindicalizado
+}
 
+// nextApprovalStage returns the stage after currentStage ("" once completed)
+func nextApprovalStage(currentStage models.ApprovalStage, isBlueOrGrayCollar bool) models.ApprovalStage {
 	switch currentStage {
 	case models.ApprovalStageSupervisor:
 		// SUPERVISOR → MANAGER
@@ -762,6 +1262,13 @@ func (s *AbsenceRequestService) notifyNextApprovers(tx *gorm.DB, request *models
 		roles = []enums.UserRole{enums.RoleHR, enums.RoleHRAndPR}
 	}
 
+	// Stages added by a workflow notify whoever its step designates
+	if len(roles) == 0 && request.WorkflowID != nil && !isBuiltInStage(nextStage) {
+		for _, approverID := range s.workflows.withDB(tx).ApproverIDs(request, nextStage) {
+			s.notifyApproverWithMessage(tx, approverID, request.ID, employeeName, requestTypeName)
+		}
+	}
+
 	if len(roles) > 0 {
 		var approvers []models.User
 		tx.Where("role IN ?", roles).Find(&approvers)
@@ -790,7 +1297,12 @@ func (s *AbsenceRequestService) createInitialIncidence(tx *gorm.DB, request *mod
 	var incidenceType models.IncidenceType
 	typeName := s.getIncidenceTypeName(request.RequestType)
 
-	err := tx.Where("name = ?", typeName).First(&incidenceType).Error
+	query := tx.Where("name = ?", typeName)
+	if request.LeavePolicyID != nil && request.IncidenceTypeID != nil {
+		// Catalog leaves post to the incidence type of their policy
+		query = tx.Where("id = ?", *request.IncidenceTypeID)
+	}
+	err := query.First(&incidenceType).Error
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			// Create the incidence type if it doesn't exist
@@ -889,7 +1401,12 @@ func (s *AbsenceRequestService) createPayrollIncidence(tx *gorm.DB, request *mod
 	var incidenceType models.IncidenceType
 	typeName := s.getIncidenceTypeName(request.RequestType)
 
-	err := tx.Where("name = ?", typeName).First(&incidenceType).Error
+	query := tx.Where("name = ?", typeName)
+	if request.LeavePolicyID != nil && request.IncidenceTypeID != nil {
+		// Catalog leaves post to the incidence type of their policy
+		query = tx.Where("id = ?", *request.IncidenceTypeID)
+	}
+	err := query.First(&incidenceType).Error
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			// Create the incidence type if it doesn't exist
@@ -969,12 +1486,200 @@ func (s *AbsenceRequestService) createPayrollIncidence(tx *gorm.DB, request *mod
 		return fmt.Errorf("failed to create incidence: %w", err)
 	}
 
+	// Approved vacations consume days from the vacation ledger
+	incidence.IncidenceType = &incidenceType
+	if err := s.vacationLedger.withDB(tx).PostConsumption(incidence, now); err != nil {
+		return fmt.Errorf("failed to post vacation consumption: %w", err)
+	}
+
 	fmt.Printf("Created payroll incidence %s for employee %s from absence request %s\n",
 		incidence.ID, employee.EmployeeNumber, request.ID)
 
 	return nil
 }
 
+// countsWorkDays reports whether a whole-day request is counted in work
+// days; sick leave keeps its calendar days (IMSS) and catalog leaves follow
+// their policy
+func countsWorkDays(requestType models.RequestType, policy *models.LeavePolicy) bool {
+	switch requestType {
+	case models.RequestTypeVacation, models.RequestTypePaidLeave, models.RequestTypeUnpaidLeave,
+		models.RequestTypePersonal, models.RequestTypeOther:
+		return true
+	case models.RequestTypeLeave:
+		return policy != nil && policy.CountsWorkDays
+	}
+	return false
+}
+
+// countRequestDays sets the TotalDays of a whole-day request to the work
+// days it covers (WorkCalendarService.WorkDayQuantity) and splits them
+// again between paid and unpaid days
+func (s *AbsenceRequestService) countRequestDays(employee *models.Employee, input *CreateAbsenceRequestInput) error {
+	if employee == nil {
+		return errors.New("user has no associated employee record")
+	}
+	days, err := s.calendar.WorkDayQuantity(employee, input.StartDate, input.EndDate, input.TotalDays)
+	if err != nil {
+		return err
+	}
+	if days == 0 {
+		return fmt.Errorf("%w: there are no work days between %s and %s", ErrInvalidAbsenceRequest,
+			input.StartDate.Format("2006-01-02"), input.EndDate.Format("2006-01-02"))
+	}
+	input.TotalDays = days
+	splitPaidDays(input)
+	return nil
+}
+
+// splitPaidDays keeps PaidDays + UnpaidDays equal to TotalDays: the paid
+// days sent (at most the total) stand and the unpaid days are the rest, or
+// the other way round when only unpaid days were sent
+func splitPaidDays(input *CreateAbsenceRequestInput) {
+	if input.PaidDays == nil && input.UnpaidDays == nil {
+		return
+	}
+	paid, unpaid := 0.0, 0.0
+	if input.PaidDays != nil {
+		paid = math.Min(math.Max(*input.PaidDays, 0), input.TotalDays)
+		unpaid = input.TotalDays - paid
+	} else {
+		unpaid = math.Min(math.Max(*input.UnpaidDays, 0), input.TotalDays)
+		paid = input.TotalDays - unpaid
+	}
+	input.PaidDays, input.UnpaidDays = &paid, &unpaid
+}
+
+// requestWindow is the resolved time span of a new absence request
+type requestWindow struct {
+	startTime string
+	endTime   string
+	hours     float64
+}
+
+// resolveRequestTime validates the times of a request and sets its
+// TotalDays. Requests in hours must lie within the employee's shift on a
+// single day; whole-day TIME_FOR_TIME requests take the scheduled hours of
+// the working days they cover from the time bank.
+func (s *AbsenceRequestService) resolveRequestTime(employee *models.Employee, input *CreateAbsenceRequestInput) (requestWindow, error) {
+	var window requestWindow
+	invalid := func(format string, args ...interface{}) (requestWindow, error) {
+		return window, fmt.Errorf("%w: %s", ErrInvalidAbsenceRequest, fmt.Sprintf(format, args...))
+	}
+	partial := input.StartTime != "" || input.EndTime != ""
+	if input.EndDate.Before(input.StartDate) {
+		return invalid("end date must be on or after start date")
+	}
+	if !partial {
+		if input.RequestType == models.RequestTypeLateEntry || input.RequestType == models.RequestTypeEarlyExit {
+			return invalid("start and end times are required for %s requests", input.RequestType)
+		}
+		if input.TotalDays <= 0 {
+			return invalid("total days must be greater than zero")
+		}
+		if input.RequestType != models.RequestTypeTimeForTime {
+			return window, nil
+		}
+	}
+	switch input.RequestType {
+	case models.RequestTypeVacation, models.RequestTypeSickLeave, models.RequestTypeShiftChange:
+		return invalid("%s requests cannot be filed in hours", input.RequestType)
+	}
+	if employee == nil {
+		return window, errors.New("user has no associated employee record")
+	}
+
+	start, end := attendanceDate(input.StartDate), attendanceDate(input.EndDate)
+	schedules, err := s.attendance.loadSchedules(employee, start, end)
+	if err != nil {
+		return window, err
+	}
+	if !partial {
+		// Holidays in the range take no hours from the bank
+		days, err := s.calendar.Days(employee, start, end)
+		if err != nil {
+			return window, err
+		}
+		for _, day := range days {
+			if sched := schedules(day.Date); !sched.start.IsZero() && day.Holiday == "" {
+				window.hours += sched.hours
+			}
+		}
+		window.hours = roundHours(window.hours)
+		if window.hours <= 0 {
+			return invalid("no shift is scheduled between %s and %s", start.Format("2006-01-02"), end.Format("2006-01-02"))
+		}
+		return window, nil
+	}
+
+	if input.StartTime == "" || input.EndTime == "" {
+		return invalid("start and end times are both required")
+	}
+	if !start.Equal(end) {
+		return invalid("a request in hours must start and end on the same day")
+	}
+	sched := schedules(start)
+	if sched.start.IsZero() {
+		return invalid("no shift is scheduled on %s", start.Format("2006-01-02"))
+	}
+	from, to, err := permitBounds(start, sched, input.StartTime, input.EndTime)
+	if err != nil {
+		return invalid("%v", err)
+	}
+	if from.Before(sched.start) || to.After(sched.end) {
+		return invalid("%s-%s must be within the shift (%s-%s)", from.Format("15:04"), to.Format("15:04"),
+			sched.start.Format("15:04"), sched.end.Format("15:04"))
+	}
+	if input.RequestType == models.RequestTypeLateEntry && !from.Equal(sched.start) {
+		return invalid("a late entry must start when the shift starts (%s)", sched.start.Format("15:04"))
+	}
+	if input.RequestType == models.RequestTypeEarlyExit && !to.Equal(sched.end) {
+		return invalid("an early exit must end when the shift ends (%s)", sched.end.Format("15:04"))
+	}
+
+	window.startTime, window.endTime = from.Format("15:04"), to.Format("15:04")
+	window.hours = roundHours(to.Sub(from).Hours())
+	shiftHours := sched.hours
+	if shiftHours <= 0 {
+		shiftHours = 8
+	}
+	input.TotalDays = math.Min(roundTo2(window.hours/shiftHours), 1)
+	return window, nil
+}
+
+// checkLeave checks a LEAVE request being approved against its policy
+func (s *AbsenceRequestService) checkLeave(request *models.AbsenceRequest) error {
+	employee, err := s.requestEmployee(s.db, request)
+	if err != nil {
+		return err
+	}
+	policy, err := s.leavePolicies.activePolicy(employee.CompanyID, *request.LeavePolicyID)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidAbsenceRequest, err)
+	}
+	exclude := []uuid.UUID{request.ID}
+	if request.AmendsID != nil {
+		exclude = append(exclude, *request.AmendsID)
+	}
+	if err := s.leavePolicies.CheckRequest(policy, employee, request.StartDate, request.TotalDays, exclude...); err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidAbsenceRequest, err)
+	}
+	if err := s.leavePolicies.CheckEvidence(policy, request); err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidAbsenceRequest, err)
+	}
+	return nil
+}
+
+// requestEmployee loads the employee record of the user who filed a request
+func (s *AbsenceRequestService) requestEmployee(db *gorm.DB, request *models.AbsenceRequest) (*models.Employee, error) {
+	var employee models.Employee
+	if err := db.Joins("JOIN users ON users.employee_id = employees.id").
+		Where("users.id = ?", request.EmployeeID).First(&employee).Error; err != nil {
+		return nil, errors.New("employee not found")
+	}
+	return &employee, nil
+}
+
 // mapRequestTypeToCategory maps absence request types to incidence categories
 func (s *AbsenceRequestService) mapRequestTypeToCategory(requestType models.RequestType) string {
 	switch requestType {
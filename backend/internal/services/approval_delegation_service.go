@@ -0,0 +1,401 @@
+/*
+Package services - Approval Delegation Service
+
+==============================================================================
+FILE: internal/services/approval_delegation_service.go
+==============================================================================
+
+DESCRIPTION:
+    Out-of-office substitutes for approvers. Maintains the delegations a
+    user grants (or HR grants for them), answers which delegations a user
+    holds on a date, and proposes a substitute when an approver's own
+    absence is fully approved. AbsenceRequestService uses the active
+    delegations to build the delegate's queue and to let them act on the
+    delegator's behalf.
+
+USER PERSPECTIVE:
+    - A supervisor on vacation names a colleague; requests keep moving
+      without waiting for the 24-hour escalation
+    - The colleague sees those requests in their "delegated" queue and the
+      approval history shows "on behalf of" the supervisor
+    - After an approver's vacation is approved they get a suggested
+      substitute to confirm
+
+DEVELOPER GUIDELINES:
+    ✅  OK to modify: How the suggested substitute is chosen
+    ⚠️  CAUTION: Only authority a user holds themselves is delegated;
+        delegations are never followed transitively
+    📝  A manual delegation supersedes the suggestions it overlaps
+
+SYNTAX EXPLANATION:
+    - Active on a date: status active and StartDate <= date <= EndDate
+    - Overlap: date ranges intersect and the request types intersect
+      (an empty list means every type)
+    - Suggested substitute: the approver's own supervisor, else another
+      active user of the company with the same role
+
+==============================================================================
+*/
+package services
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"gorm.io/gorm"
+
+	"backend/internal/dtos"
+	"backend/internal/models"
+	"backend/internal/models/enums"
+)
+
+// ErrDelegationNotAllowed is returned when the user may not manage the delegation
+var ErrDelegationNotAllowed = errors.New("not allowed to manage this delegation")
+
+// approverRoles hold approval authority of their own
+var approverRoles = []enums.UserRole{
+	enums.RoleSupervisor, enums.RoleManager, enums.RoleSupAndGM, enums.RoleHR, enums.RoleHRAndPR,
+	enums.RoleHRBlueGray, enums.RoleHRWhite, enums.RolePayrollStaff,
+}
+
+// delegationAdminRoles manage every delegation of the company
+var delegationAdminRoles = []enums.UserRole{
+	enums.RoleAdmin, enums.RoleHR, enums.RoleHRAndPR, enums.RoleHRBlueGray, enums.RoleHRWhite,
+}
+
+// hasRole reports whether role is one of roles
+func hasRole(role enums.UserRole, roles []enums.UserRole) bool {
+	for _, candidate := range roles {
+		if candidate == role {
+			return true
+		}
+	}
+	return false
+}
+
+// ApprovalDelegationService manages out-of-office substitutes for approvers
+type ApprovalDelegationService struct {
+	db *gorm.DB
+}
+
+// NewApprovalDelegationService creates a new approval delegation service
+func NewApprovalDelegationService(db *gorm.DB) *ApprovalDelegationService {
+	return &ApprovalDelegationService{db: db}
+}
+
+// withDB returns a copy of the service working on tx
+func (s *ApprovalDelegationService) withDB(tx *gorm.DB) *ApprovalDelegationService {
+	return &ApprovalDelegationService{db: tx}
+}
+
+// ListMine returns the delegations a user granted or holds
+func (s *ApprovalDelegationService) ListMine(companyID, userID uuid.UUID) ([]models.ApprovalDelegation, error) {
+	var delegations []models.ApprovalDelegation
+	if err := s.db.Preload("Delegator").Preload("Delegate").
+		Where("company_id = ? AND (delegator_id = ? OR delegate_id = ?)", companyID, userID, userID).
+		Order("start_date DESC").
+		Find(&delegations).Error; err != nil {
+		return nil, fmt.Errorf("error fetching delegations: %w", err)
+	}
+	return delegations, nil
+}
+
+// ListCompany returns the company's delegations, optionally by status
+func (s *ApprovalDelegationService) ListCompany(companyID uuid.UUID, status string) ([]models.ApprovalDelegation, error) {
+	query := s.db.Preload("Delegator").Preload("Delegate").Where("company_id = ?", companyID)
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+	var delegations []models.ApprovalDelegation
+	if err := query.Order("start_date DESC").Find(&delegations).Error; err != nil {
+		return nil, fmt.Errorf("error fetching delegations: %w", err)
+	}
+	return delegations, nil
+}
+
+// Create delegates the approval authority of the caller (or, for HR, of
+// req.DelegatorID) to a substitute
+func (s *ApprovalDelegationService) Create(companyID, actorID uuid.UUID, req dtos.ApprovalDelegationRequest) (*models.ApprovalDelegation, error) {
+	actor, err := s.companyUser(companyID, actorID)
+	if err != nil {
+		return nil, err
+	}
+	delegatorID := actorID
+	if req.DelegatorID != nil && *req.DelegatorID != actorID {
+		if !hasRole(actor.Role, delegationAdminRoles) {
+			return nil, ErrDelegationNotAllowed
+		}
+		delegatorID = *req.DelegatorID
+	}
+	if _, err := s.companyUser(companyID, delegatorID); err != nil {
+		return nil, err
+	}
+	if err := s.checkDelegate(companyID, delegatorID, req.DelegateID); err != nil {
+		return nil, err
+	}
+
+	delegation := &models.ApprovalDelegation{
+		CompanyID:   companyID,
+		DelegatorID: delegatorID,
+		DelegateID:  req.DelegateID,
+		StartDate:   truncateToDate(req.StartDate.Time),
+		EndDate:     truncateToDate(req.EndDate.Time),
+		Reason:      strings.TrimSpace(req.Reason),
+		Status:      models.DelegationActive,
+		CreatedByID: actorID,
+	}
+	if delegation.EndDate.Before(delegation.StartDate) {
+		return nil, errors.New("end date must not be before start date")
+	}
+	for _, requestType := range req.RequestTypes {
+		if requestType = strings.ToUpper(strings.TrimSpace(requestType)); requestType != "" {
+			delegation.RequestTypes = append(delegation.RequestTypes, requestType)
+		}
+	}
+
+	err = s.db.Transaction(func(tx *gorm.DB) error {
+		overlapping, err := s.withDB(tx).overlapping(delegation)
+		if err != nil {
+			return err
+		}
+		for _, other := range overlapping {
+			if other.Status == models.DelegationActive {
+				return errors.New("a delegation for these dates already exists")
+			}
+		}
+		// Suggestions for the same dates are superseded
+		for _, other := range overlapping {
+			if err := revokeDelegation(tx, &other, actorID); err != nil {
+				return err
+			}
+		}
+		return tx.Create(delegation).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return delegation, nil
+}
+
+// Accept puts a suggested delegation in force, optionally with another substitute
+func (s *ApprovalDelegationService) Accept(companyID, actorID, id uuid.UUID, req dtos.AcceptDelegationRequest) (*models.ApprovalDelegation, error) {
+	delegation, err := s.manageable(companyID, actorID, id)
+	if err != nil {
+		return nil, err
+	}
+	if delegation.Status != models.DelegationSuggested {
+		return nil, errors.New("only suggested delegations can be accepted")
+	}
+	if req.DelegateID != nil {
+		if err := s.checkDelegate(companyID, delegation.DelegatorID, *req.DelegateID); err != nil {
+			return nil, err
+		}
+		delegation.DelegateID = *req.DelegateID
+	}
+	overlapping, err := s.overlapping(delegation)
+	if err != nil {
+		return nil, err
+	}
+	for _, other := range overlapping {
+		if other.Status == models.DelegationActive {
+			return nil, errors.New("a delegation for these dates already exists")
+		}
+	}
+
+	delegation.Status = models.DelegationActive
+	delegation.Delegator, delegation.Delegate = nil, nil
+	if err := s.db.Save(delegation).Error; err != nil {
+		return nil, fmt.Errorf("error accepting delegation: %w", err)
+	}
+	return delegation, nil
+}
+
+// Revoke ends a delegation or dismisses a suggestion
+func (s *ApprovalDelegationService) Revoke(companyID, actorID, id uuid.UUID) (*models.ApprovalDelegation, error) {
+	delegation, err := s.manageable(companyID, actorID, id)
+	if err != nil {
+		return nil, err
+	}
+	if delegation.Status == models.DelegationRevoked {
+		return nil, errors.New("delegation is already revoked")
+	}
+	if err := revokeDelegation(s.db, delegation, actorID); err != nil {
+		return nil, fmt.Errorf("error revoking delegation: %w", err)
+	}
+	return delegation, nil
+}
+
+// ActiveFor returns the delegations a user holds on a date
+func (s *ApprovalDelegationService) ActiveFor(delegateID uuid.UUID, date time.Time) ([]models.ApprovalDelegation, error) {
+	day := truncateToDate(date)
+	var delegations []models.ApprovalDelegation
+	if err := s.db.Where("delegate_id = ? AND status = ? AND start_date < ? AND end_date >= ?",
+		delegateID, models.DelegationActive, day.AddDate(0, 0, 1), day).
+		Order("start_date").
+		Find(&delegations).Error; err != nil {
+		return nil, fmt.Errorf("error fetching delegations: %w", err)
+	}
+	return delegations, nil
+}
+
+// SuggestForAbsence proposes a substitute for an approver whose own absence
+// was approved; nil when none is needed or nobody fits
+func (s *ApprovalDelegationService) SuggestForAbsence(request *models.AbsenceRequest) (*models.ApprovalDelegation, error) {
+	isLeave := false
+	for _, requestType := range leaveRequestTypes {
+		if request.RequestType == requestType {
+			isLeave = true
+			break
+		}
+	}
+	if !isLeave || request.Status != models.RequestStatusApproved {
+		return nil, nil
+	}
+
+	var approver models.User
+	if err := s.db.First(&approver, "id = ?", request.EmployeeID).Error; err != nil {
+		return nil, nil
+	}
+	if !hasRole(approver.Role, approverRoles) {
+		var reports int64
+		if err := s.db.Model(&models.User{}).Where("supervisor_id = ? AND is_active = ?", approver.ID, true).Count(&reports).Error; err != nil {
+			return nil, err
+		}
+		if reports == 0 {
+			return nil, nil
+		}
+	}
+
+	suggestion := &models.ApprovalDelegation{
+		CompanyID:        approver.CompanyID,
+		DelegatorID:      approver.ID,
+		StartDate:        truncateToDate(request.StartDate),
+		EndDate:          truncateToDate(request.EndDate),
+		Reason:           fmt.Sprintf("Ausencia aprobada del %s al %s", request.StartDate.Format("2006-01-02"), request.EndDate.Format("2006-01-02")),
+		Status:           models.DelegationSuggested,
+		AbsenceRequestID: &request.ID,
+		CreatedByID:      approver.ID,
+	}
+	overlapping, err := s.overlapping(suggestion)
+	if err != nil || len(overlapping) > 0 {
+		return nil, err
+	}
+
+	delegateID := s.suggestDelegate(&approver)
+	if delegateID == nil {
+		return nil, nil
+	}
+	suggestion.DelegateID = *delegateID
+	if err := s.db.Create(suggestion).Error; err != nil {
+		return nil, fmt.Errorf("error suggesting delegation: %w", err)
+	}
+	return suggestion, nil
+}
+
+// suggestDelegate picks the approver's supervisor, else a peer with the same role
+func (s *ApprovalDelegationService) suggestDelegate(approver *models.User) *uuid.UUID {
+	if approver.SupervisorID != nil && *approver.SupervisorID != approver.ID {
+		var supervisor models.User
+		if s.db.Where("id = ? AND company_id = ? AND is_active = ?", *approver.SupervisorID, approver.CompanyID, true).
+			Limit(1).Find(&supervisor).Error == nil && supervisor.ID != uuid.Nil {
+			return &supervisor.ID
+		}
+	}
+	var peer models.User
+	s.db.Where("company_id = ? AND role = ? AND is_active = ? AND id <> ?", approver.CompanyID, approver.Role, true, approver.ID).
+		Order("full_name").Limit(1).Find(&peer)
+	if peer.ID == uuid.Nil {
+		return nil
+	}
+	return &peer.ID
+}
+
+// overlapping returns the delegator's other non-revoked delegations that
+// share dates and request types with d
+func (s *ApprovalDelegationService) overlapping(d *models.ApprovalDelegation) ([]models.ApprovalDelegation, error) {
+	var candidates []models.ApprovalDelegation
+	if err := s.db.Where("delegator_id = ? AND status <> ? AND id <> ? AND start_date < ? AND end_date >= ?",
+		d.DelegatorID, models.DelegationRevoked, d.ID, d.EndDate.AddDate(0, 0, 1), d.StartDate).
+		Find(&candidates).Error; err != nil {
+		return nil, fmt.Errorf("error fetching delegations: %w", err)
+	}
+	var result []models.ApprovalDelegation
+	for _, other := range candidates {
+		if requestTypesIntersect(d.RequestTypes, other.RequestTypes) {
+			result = append(result, other)
+		}
+	}
+	return result, nil
+}
+
+// requestTypesIntersect reports whether two request type lists share a type (empty = every type)
+func requestTypesIntersect(a, b pq.StringArray) bool {
+	if len(a) == 0 || len(b) == 0 {
+		return true
+	}
+	for _, x := range a {
+		for _, y := range b {
+			if strings.EqualFold(x, y) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// revokeDelegation marks a delegation revoked
+func revokeDelegation(tx *gorm.DB, delegation *models.ApprovalDelegation, actorID uuid.UUID) error {
+	now := time.Now()
+	delegation.Status = models.DelegationRevoked
+	delegation.RevokedByID = &actorID
+	delegation.RevokedAt = &now
+	return tx.Model(&models.ApprovalDelegation{}).Where("id = ?", delegation.ID).Updates(map[string]interface{}{
+		"status":        models.DelegationRevoked,
+		"revoked_by_id": actorID,
+		"revoked_at":    now,
+	}).Error
+}
+
+// manageable loads a delegation the actor may change: their own or, for HR, any of the company
+func (s *ApprovalDelegationService) manageable(companyID, actorID, id uuid.UUID) (*models.ApprovalDelegation, error) {
+	actor, err := s.companyUser(companyID, actorID)
+	if err != nil {
+		return nil, err
+	}
+	var delegation models.ApprovalDelegation
+	if err := s.db.First(&delegation, "id = ? AND company_id = ?", id, companyID).Error; err != nil {
+		return nil, errors.New("delegation not found")
+	}
+	if delegation.DelegatorID != actorID && !hasRole(actor.Role, delegationAdminRoles) {
+		return nil, ErrDelegationNotAllowed
+	}
+	return &delegation, nil
+}
+
+// checkDelegate validates the substitute of a delegation
+func (s *ApprovalDelegationService) checkDelegate(companyID, delegatorID, delegateID uuid.UUID) error {
+	if delegateID == delegatorID {
+		return errors.New("delegate must be someone other than the delegator")
+	}
+	delegate, err := s.companyUser(companyID, delegateID)
+	if err != nil {
+		return errors.New("delegate not found")
+	}
+	if !delegate.IsActive {
+		return errors.New("delegate must be an active user")
+	}
+	return nil
+}
+
+// companyUser loads a user of the company
+func (s *ApprovalDelegationService) companyUser(companyID, userID uuid.UUID) (*models.User, error) {
+	var user models.User
+	if err := s.db.First(&user, "id = ? AND company_id = ?", userID, companyID).Error; err != nil {
+		return nil, errors.New("user not found")
+	}
+	return &user, nil
+}
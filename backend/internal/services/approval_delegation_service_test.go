@@ -0,0 +1,154 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+
+	"backend/internal/dtos"
+	"backend/internal/models"
+	"backend/internal/models/enums"
+)
+
+// approvalDelegationTest is an employee whose supervisor reports to a
+// manager, with a backup, a second supervisor and an HR user
+type approvalDelegationTest struct {
+	db              *gorm.DB
+	delegations     *ApprovalDelegationService
+	requests        *AbsenceRequestService
+	employee        *models.User
+	manager         *models.User
+	supervisor      *models.User
+	otherSupervisor *models.User
+	backup          *models.User
+	hr              *models.User
+	today           time.Time
+	period          dtos.ApprovalDelegationRequest // Backup covers the supervisor from yesterday for 5 days
+}
+
+func setupApprovalDelegationTest(t *testing.T) *approvalDelegationTest {
+	db := setupTestDB(t)
+	require.NoError(t, db.AutoMigrate(
+		&models.ApprovalDelegation{}, &models.StaffingRule{}, &models.BlackoutPeriod{}, &models.ApprovalWorkflow{}, &models.ApprovalWorkflowStep{},
+		&models.Notification{}, &models.Message{},
+	))
+
+	_, employee := createTestEmployee(db, "white_collar")
+	user := func(email string, role enums.UserRole) *models.User {
+		u := &models.User{CompanyID: employee.CompanyID, Email: email, PasswordHash: "x", Role: role, FullName: email, IsActive: true}
+		require.NoError(t, db.Create(u).Error)
+		return u
+	}
+	f := &approvalDelegationTest{
+		db:              db,
+		delegations:     NewApprovalDelegationService(db),
+		requests:        NewAbsenceRequestService(db, nil),
+		employee:        employee,
+		manager:         user("manager@example.com", enums.RoleManager),
+		supervisor:      user("sup@example.com", enums.RoleSupervisor),
+		otherSupervisor: user("sup2@example.com", enums.RoleSupervisor),
+		backup:          user("backup@example.com", enums.RoleEmployee),
+		hr:              user("hr@example.com", enums.RoleHR),
+		today:           truncateToDate(time.Now()),
+	}
+	require.NoError(t, db.Model(employee).Update("supervisor_id", f.supervisor.ID).Error)
+	require.NoError(t, db.Model(f.supervisor).Update("supervisor_id", f.manager.ID).Error)
+	f.period = dtos.ApprovalDelegationRequest{DelegateID: f.backup.ID,
+		StartDate: dtos.Date{Time: f.today.AddDate(0, 0, -1)}, EndDate: dtos.Date{Time: f.today.AddDate(0, 0, 5)}}
+	return f
+}
+
+// delegate creates the supervisor's delegation to the backup
+func (f *approvalDelegationTest) delegate(t *testing.T) *models.ApprovalDelegation {
+	delegation, err := f.delegations.Create(f.employee.CompanyID, f.supervisor.ID, f.period)
+	require.NoError(t, err)
+	return delegation
+}
+
+// pendingRequest files a vacation day of the employee waiting for the supervisor
+func (f *approvalDelegationTest) pendingRequest(t *testing.T) *models.AbsenceRequest {
+	request := &models.AbsenceRequest{EmployeeID: f.employee.ID, RequestType: models.RequestTypeVacation, StartDate: f.today, EndDate: f.today,
+		TotalDays: 1, Reason: "Trámite", CurrentApprovalStage: models.ApprovalStageSupervisor, LastActionAt: time.Now()}
+	require.NoError(t, f.db.Create(request).Error)
+	return request
+}
+
+// approve approves the supervisor stage of a request as approver
+func (f *approvalDelegationTest) approve(request *models.AbsenceRequest, approver *models.User) error {
+	_, err := f.requests.ApproveRequest(ApproveRequestInput{RequestID: request.ID, ApproverID: approver.ID,
+		Stage: models.ApprovalStageSupervisor, Action: models.ApprovalActionApproved})
+	return err
+}
+
+func TestApprovalDelegation(t *testing.T) {
+	t.Run("only HR delegates someone else's approvals", func(t *testing.T) {
+		f := setupApprovalDelegationTest(t)
+		forOther := f.period
+		forOther.DelegatorID = &f.otherSupervisor.ID
+		_, err := f.delegations.Create(f.employee.CompanyID, f.supervisor.ID, forOther)
+		assert.ErrorIs(t, err, ErrDelegationNotAllowed)
+	})
+
+	t.Run("overlapping delegations are rejected", func(t *testing.T) {
+		f := setupApprovalDelegationTest(t)
+		delegation := f.delegate(t)
+		assert.Equal(t, models.DelegationActive, delegation.Status)
+		_, err := f.delegations.Create(f.employee.CompanyID, f.supervisor.ID, f.period)
+		assert.ErrorContains(t, err, "already exists")
+	})
+
+	t.Run("the delegate approves on behalf of the delegator", func(t *testing.T) {
+		f := setupApprovalDelegationTest(t)
+		f.delegate(t)
+		request := f.pendingRequest(t)
+
+		pending, err := f.requests.GetDelegatedPendingRequests(f.backup.ID)
+		require.NoError(t, err)
+		require.Len(t, pending, 1)
+		assert.Equal(t, f.supervisor.ID, *pending[0].DelegatedFromID)
+
+		assert.ErrorContains(t, f.approve(request, f.hr), "unauthorized")
+		require.NoError(t, f.approve(request, f.backup))
+		var history models.ApprovalHistory
+		require.NoError(t, f.db.Where("request_id = ?", request.ID).First(&history).Error)
+		assert.Equal(t, f.backup.ID, history.ApproverID)
+		require.NotNil(t, history.OnBehalfOfID)
+		assert.Equal(t, f.supervisor.ID, *history.OnBehalfOfID)
+	})
+
+	t.Run("delegations are not transitive and end when revoked", func(t *testing.T) {
+		f := setupApprovalDelegationTest(t)
+		delegation := f.delegate(t)
+		f.pendingRequest(t)
+
+		_, err := f.delegations.Revoke(f.employee.CompanyID, f.backup.ID, delegation.ID)
+		assert.ErrorIs(t, err, ErrDelegationNotAllowed)
+		_, err = f.delegations.Revoke(f.employee.CompanyID, f.supervisor.ID, delegation.ID)
+		require.NoError(t, err)
+		pending, err := f.requests.GetDelegatedPendingRequests(f.backup.ID)
+		require.NoError(t, err)
+		assert.Empty(t, pending)
+	})
+
+	t.Run("an approver's vacation suggests their supervisor as substitute", func(t *testing.T) {
+		f := setupApprovalDelegationTest(t)
+		vacation := &models.AbsenceRequest{EmployeeID: f.supervisor.ID, RequestType: models.RequestTypeVacation,
+			StartDate: f.today.AddDate(0, 0, 10), EndDate: f.today.AddDate(0, 0, 14), TotalDays: 5, Reason: "Vacaciones",
+			Status: models.RequestStatusApproved, CurrentApprovalStage: models.ApprovalStageCompleted, LastActionAt: time.Now()}
+		require.NoError(t, f.db.Create(vacation).Error)
+		suggestion, err := f.delegations.SuggestForAbsence(vacation)
+		require.NoError(t, err)
+		require.NotNil(t, suggestion)
+		assert.Equal(t, models.DelegationSuggested, suggestion.Status)
+		assert.Equal(t, f.manager.ID, suggestion.DelegateID)
+
+		accepted, err := f.delegations.Accept(f.employee.CompanyID, f.supervisor.ID, suggestion.ID,
+			dtos.AcceptDelegationRequest{DelegateID: &f.otherSupervisor.ID})
+		require.NoError(t, err)
+		assert.Equal(t, models.DelegationActive, accepted.Status)
+		assert.Equal(t, f.otherSupervisor.ID, accepted.DelegateID)
+	})
+}
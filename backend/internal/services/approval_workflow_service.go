@@ -0,0 +1,870 @@
+/*
+Package services - Approval Workflow Service
+
+==============================================================================
+FILE: internal/services/approval_workflow_service.go
+==============================================================================
+
+DESCRIPTION:
+    Engine behind the configurable approval workflows. Maintains the
+    workflow definitions of a company (seeding the "standard" one as its
+    default), resolves which workflow a request follows, and answers the
+    routing questions of AbsenceRequestService and EscalationService: the
+    first stages of a request, the stages after an approval, who may
+    approve a stage and how long a stage may wait.
+
+USER PERSPECTIVE:
+    - Requests follow the steps HR configured for their incidence type
+    - Approvers of custom stages (a medical review, a specific director)
+      find their requests in the workflow inbox
+    - A step left waiting longer than its timeout escalates on its own
+
+DEVELOPER GUIDELINES:
+    ✅  OK to modify: Approver rules and step conditions
+    ⚠️  CAUTION: The seeded default must reproduce the built-in chain
+        (nextApprovalStage); it is what every request follows unless HR
+        configures otherwise
+    ⚠️  CAUTION: Conditions are evaluated on every routing decision, so
+        editing a workflow affects requests already following it
+    📝  Requests without WorkflowID (created before workflows existed) keep
+        the built-in chain; this service is not consulted for them
+
+SYNTAX EXPLANATION:
+    - Resolution: workflow bound to the request's incidence type, else the
+      workflow whose Code matches IncidenceType.ApprovalFlow, else the
+      company default
+    - Route: the steps whose conditions hold, grouped by StepOrder; a group
+      is done when each of its stages has an APPROVED entry in the history
+    - PendingStages: "|A|B|" list of the group's stages still waiting; the
+      first of them is also the request's CurrentApprovalStage
+
+==============================================================================
+*/
+package services
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"gorm.io/gorm"
+
+	"backend/internal/dtos"
+	"backend/internal/models"
+	"backend/internal/models/enums"
+)
+
+var stageCodePattern = regexp.MustCompile(`^[A-Z][A-Z0-9_]{1,49}$`)
+
+// approvalSubject is what the route of a request depends on
+type approvalSubject struct {
+	request  *models.AbsenceRequest
+	user     models.User
+	blueGray bool
+}
+
+// approvalGroup is a set of steps a request waits in at the same time
+type approvalGroup struct {
+	order int
+	steps []models.ApprovalWorkflowStep
+}
+
+// stages returns the stage codes of the group for the subject
+func (g approvalGroup) stages(subject *approvalSubject) []models.ApprovalStage {
+	stages := make([]models.ApprovalStage, 0, len(g.steps))
+	for i := range g.steps {
+		stages = append(stages, g.steps[i].StageFor(subject.blueGray))
+	}
+	return stages
+}
+
+// sla returns the shortest escalation and reminder hours set in the group
+func (g approvalGroup) sla() (hours, reminderHours int) {
+	for _, step := range g.steps {
+		if step.EscalationHours > 0 && (hours == 0 || step.EscalationHours < hours) {
+			hours = step.EscalationHours
+		}
+		if step.ReminderHours > 0 && (reminderHours == 0 || step.ReminderHours < reminderHours) {
+			reminderHours = step.ReminderHours
+		}
+	}
+	return hours, reminderHours
+}
+
+// ApprovalWorkflowService maintains approval workflows and routes requests through them
+type ApprovalWorkflowService struct {
+	db           *gorm.DB
+	orgStructure *OrgStructureService
+}
+
+// NewApprovalWorkflowService creates a new approval workflow service
+func NewApprovalWorkflowService(db *gorm.DB) *ApprovalWorkflowService {
+	return &ApprovalWorkflowService{db: db, orgStructure: NewOrgStructureService(db)}
+}
+
+// withDB returns a copy of the service working on tx
+func (s *ApprovalWorkflowService) withDB(tx *gorm.DB) *ApprovalWorkflowService {
+	return &ApprovalWorkflowService{db: tx, orgStructure: NewOrgStructureService(tx)}
+}
+
+// defaultWorkflowSteps reproduces the built-in chain: white collar goes
+// SUPERVISOR → MANAGER → HR, blue/gray collar starts at HR_BLUE_GRAY, and
+// both finish with GENERAL_MANAGER → PAYROLL
+func defaultWorkflowSteps() []models.ApprovalWorkflowStep {
+	managers := pq.StringArray{string(enums.RoleManager), string(enums.RoleSupAndGM)}
+	return []models.ApprovalWorkflowStep{
+		{StepOrder: 1, Stage: models.ApprovalStageSupervisor, Name: "Supervisor directo",
+			ApproverRule: models.ApproverRuleSupervisor, CollarScope: models.CollarScopeWhite, EscalationHours: 24, ReminderHours: 4},
+		{StepOrder: 2, Stage: models.ApprovalStageManager, Name: "Gerente",
+			ApproverRule: models.ApproverRuleRole, ApproverRoles: managers, CollarScope: models.CollarScopeWhite, EscalationHours: 24, ReminderHours: 4},
+		{StepOrder: 3, Stage: models.ApprovalStageHR, Name: "Recursos Humanos",
+			ApproverRule: models.ApproverRuleHRByCollar, EscalationHours: 24, ReminderHours: 4},
+		{StepOrder: 4, Stage: models.ApprovalStageGeneralManager, Name: "Gerente General",
+			ApproverRule: models.ApproverRuleRole, ApproverRoles: managers, EscalationHours: 24, ReminderHours: 4},
+		{StepOrder: 5, Stage: models.ApprovalStagePayroll, Name: "Nómina",
+			ApproverRule:  models.ApproverRuleRole,
+			ApproverRoles: pq.StringArray{string(enums.RolePayrollStaff), string(enums.RoleHRAndPR)}, EscalationHours: 24, ReminderHours: 4},
+	}
+}
+
+// =========================================================================
+// Definitions
+// =========================================================================
+
+// SeedDefault creates the company's default "standard" workflow if it has none
+func (s *ApprovalWorkflowService) SeedDefault(companyID uuid.UUID) (*models.ApprovalWorkflow, error) {
+	var existing models.ApprovalWorkflow
+	if err := s.db.Where("company_id = ? AND is_default = ?", companyID, true).Limit(1).Find(&existing).Error; err != nil {
+		return nil, fmt.Errorf("error fetching default workflow: %w", err)
+	}
+	if existing.ID != uuid.Nil {
+		return s.loadWorkflow(existing.ID)
+	}
+
+	workflow := &models.ApprovalWorkflow{
+		CompanyID:   companyID,
+		Code:        models.DefaultApprovalFlow,
+		Name:        "Flujo estándar",
+		Description: "Supervisor, gerente, RH según tipo de colaborador, gerente general y nómina",
+		IsDefault:   true,
+		IsActive:    true,
+		Steps:       defaultWorkflowSteps(),
+	}
+	if err := s.db.Create(workflow).Error; err != nil {
+		return nil, fmt.Errorf("error seeding default workflow: %w", err)
+	}
+	return workflow, nil
+}
+
+// ListWorkflows returns the company's workflows, seeding the default on first use
+func (s *ApprovalWorkflowService) ListWorkflows(companyID uuid.UUID) ([]models.ApprovalWorkflow, error) {
+	if _, err := s.SeedDefault(companyID); err != nil {
+		return nil, err
+	}
+	var workflows []models.ApprovalWorkflow
+	if err := s.db.Preload("Steps", orderSteps).Preload("IncidenceType").
+		Where("company_id = ?", companyID).
+		Order("is_default DESC, name").
+		Find(&workflows).Error; err != nil {
+		return nil, fmt.Errorf("error fetching approval workflows: %w", err)
+	}
+	return workflows, nil
+}
+
+// GetWorkflow returns one workflow of the company
+func (s *ApprovalWorkflowService) GetWorkflow(companyID, id uuid.UUID) (*models.ApprovalWorkflow, error) {
+	workflow, err := s.loadWorkflow(id)
+	if err != nil || workflow.CompanyID != companyID {
+		return nil, errors.New("approval workflow not found")
+	}
+	return workflow, nil
+}
+
+// CreateWorkflow creates a workflow
+func (s *ApprovalWorkflowService) CreateWorkflow(companyID uuid.UUID, req dtos.ApprovalWorkflowRequest) (*models.ApprovalWorkflow, error) {
+	workflow := &models.ApprovalWorkflow{CompanyID: companyID, IsActive: true}
+	steps, err := s.applyWorkflow(workflow, req)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.checkUnique(workflow); err != nil {
+		return nil, err
+	}
+
+	err = s.db.Transaction(func(tx *gorm.DB) error {
+		if workflow.IsDefault {
+			if err := clearDefaultWorkflow(tx, companyID); err != nil {
+				return err
+			}
+		}
+		if err := tx.Create(workflow).Error; err != nil {
+			return err
+		}
+		// Zero values of defaulted columns are replaced on create
+		if err := tx.Model(workflow).Select("is_default", "is_active").Updates(workflow).Error; err != nil {
+			return err
+		}
+		return createWorkflowSteps(tx, workflow.ID, steps)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error creating approval workflow: %w", err)
+	}
+	return s.loadWorkflow(workflow.ID)
+}
+
+// UpdateWorkflow updates a workflow, replacing its steps
+func (s *ApprovalWorkflowService) UpdateWorkflow(companyID, id uuid.UUID, req dtos.ApprovalWorkflowRequest) (*models.ApprovalWorkflow, error) {
+	workflow, err := s.GetWorkflow(companyID, id)
+	if err != nil {
+		return nil, err
+	}
+	wasDefault := workflow.IsDefault
+	steps, err := s.applyWorkflow(workflow, req)
+	if err != nil {
+		return nil, err
+	}
+	if wasDefault && !workflow.IsDefault {
+		return nil, errors.New("the default workflow cannot be unset; mark another workflow as default instead")
+	}
+	if err := s.checkUnique(workflow); err != nil {
+		return nil, err
+	}
+
+	workflow.Steps = nil
+	workflow.IncidenceType = nil
+	err = s.db.Transaction(func(tx *gorm.DB) error {
+		if workflow.IsDefault && !wasDefault {
+			if err := clearDefaultWorkflow(tx, companyID); err != nil {
+				return err
+			}
+		}
+		if err := tx.Save(workflow).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("workflow_id = ?", workflow.ID).Delete(&models.ApprovalWorkflowStep{}).Error; err != nil {
+			return err
+		}
+		return createWorkflowSteps(tx, workflow.ID, steps)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error updating approval workflow: %w", err)
+	}
+	return s.loadWorkflow(workflow.ID)
+}
+
+// DeleteWorkflow deletes a workflow that is neither the default nor in use
+func (s *ApprovalWorkflowService) DeleteWorkflow(companyID, id uuid.UUID) error {
+	workflow, err := s.GetWorkflow(companyID, id)
+	if err != nil {
+		return err
+	}
+	if workflow.IsDefault {
+		return errors.New("the default workflow cannot be deleted")
+	}
+	var pending int64
+	if err := s.db.Model(&models.AbsenceRequest{}).
+		Where("workflow_id = ? AND status = ?", id, models.RequestStatusPending).
+		Count(&pending).Error; err != nil {
+		return err
+	}
+	if pending > 0 {
+		return errors.New("workflow cannot be deleted while requests are pending on it")
+	}
+	return s.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("workflow_id = ?", id).Delete(&models.ApprovalWorkflowStep{}).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&models.ApprovalWorkflow{}, "id = ?", id).Error
+	})
+}
+
+// applyWorkflow copies a request onto a workflow and returns its validated steps
+func (s *ApprovalWorkflowService) applyWorkflow(workflow *models.ApprovalWorkflow, req dtos.ApprovalWorkflowRequest) ([]models.ApprovalWorkflowStep, error) {
+	workflow.Name = strings.TrimSpace(req.Name)
+	if workflow.Name == "" {
+		return nil, errors.New("name is required")
+	}
+	workflow.Description = req.Description
+	workflow.IsDefault = req.IsDefault
+	if req.IsActive != nil {
+		workflow.IsActive = *req.IsActive
+	}
+	workflow.Code = strings.ToLower(strings.TrimSpace(req.Code))
+	workflow.IncidenceTypeID = req.IncidenceTypeID
+	if req.IncidenceTypeID != nil {
+		var incidenceType models.IncidenceType
+		if err := s.db.First(&incidenceType, "id = ?", *req.IncidenceTypeID).Error; err != nil {
+			return nil, errors.New("incidence type not found")
+		}
+		if workflow.Code == "" {
+			workflow.Code = incidenceType.ApprovalFlow
+		}
+		if workflow.IsDefault {
+			return nil, errors.New("a workflow bound to an incidence type cannot be the default")
+		}
+	}
+	if workflow.Code == "" {
+		workflow.Code = models.DefaultApprovalFlow
+	}
+	if workflow.IsDefault && !workflow.IsActive {
+		return nil, errors.New("the default workflow must be active")
+	}
+
+	steps := make([]models.ApprovalWorkflowStep, 0, len(req.Steps))
+	seen := map[models.ApprovalStage]bool{}
+	for _, item := range req.Steps {
+		step := models.ApprovalWorkflowStep{
+			StepOrder:       item.StepOrder,
+			Stage:           models.ApprovalStage(strings.ToUpper(strings.TrimSpace(item.Stage))),
+			Name:            strings.TrimSpace(item.Name),
+			ApproverRule:    item.ApproverRule,
+			MinDays:         item.MinDays,
+			CollarScope:     item.CollarScope,
+			EscalationHours: item.EscalationHours,
+			ReminderHours:   item.ReminderHours,
+		}
+		for _, requestType := range item.RequestTypes {
+			if requestType = strings.ToUpper(strings.TrimSpace(requestType)); requestType != "" {
+				step.RequestTypes = append(step.RequestTypes, requestType)
+			}
+		}
+		if step.ReminderHours > 0 && step.ReminderHours >= step.EscalationHours {
+			return nil, fmt.Errorf("step %s: reminder hours must be less than escalation hours", step.Stage)
+		}
+
+		switch step.ApproverRule {
+		case models.ApproverRuleSupervisor:
+		case models.ApproverRuleRole:
+			for _, role := range item.ApproverRoles {
+				if !enums.UserRole(role).IsValid() {
+					return nil, fmt.Errorf("step %s: role %q must be a valid user role", step.Stage, role)
+				}
+				step.ApproverRoles = append(step.ApproverRoles, role)
+			}
+			if len(step.ApproverRoles) == 0 {
+				return nil, fmt.Errorf("step %s: approver roles are required", step.Stage)
+			}
+		case models.ApproverRuleUser:
+			if item.ApproverUserID == nil {
+				return nil, fmt.Errorf("step %s: approver user is required", step.Stage)
+			}
+			var count int64
+			if err := s.db.Model(&models.User{}).Where("id = ? AND company_id = ?", *item.ApproverUserID, workflow.CompanyID).Count(&count).Error; err != nil {
+				return nil, err
+			}
+			if count == 0 {
+				return nil, fmt.Errorf("step %s: approver user not found", step.Stage)
+			}
+			step.ApproverUserID = item.ApproverUserID
+		case models.ApproverRuleHRByCollar:
+			// Resolves to HR or HR_BLUE_GRAY per employee
+			step.Stage = models.ApprovalStageHR
+		default:
+			return nil, fmt.Errorf("step %s: approver rule must be supervisor, role, user or hr_by_collar", step.Stage)
+		}
+
+		if !stageCodePattern.MatchString(string(step.Stage)) || step.Stage == models.ApprovalStageCompleted {
+			return nil, fmt.Errorf("stage %q must be an upper-case code other than COMPLETED", step.Stage)
+		}
+		stages := []models.ApprovalStage{step.Stage}
+		if step.ApproverRule == models.ApproverRuleHRByCollar {
+			stages = append(stages, models.ApprovalStageHRBlueGray)
+		}
+		for _, stage := range stages {
+			if seen[stage] {
+				return nil, fmt.Errorf("stage %s must appear only once in a workflow", stage)
+			}
+			seen[stage] = true
+		}
+		steps = append(steps, step)
+	}
+	return steps, nil
+}
+
+// checkUnique rejects a second workflow for the same incidence type or code
+func (s *ApprovalWorkflowService) checkUnique(workflow *models.ApprovalWorkflow) error {
+	query := s.db.Model(&models.ApprovalWorkflow{}).Where("company_id = ? AND id <> ?", workflow.CompanyID, workflow.ID)
+	if workflow.IncidenceTypeID != nil {
+		query = query.Where("incidence_type_id = ?", *workflow.IncidenceTypeID)
+	} else {
+		query = query.Where("incidence_type_id IS NULL AND code = ?", workflow.Code)
+	}
+	var count int64
+	if err := query.Count(&count).Error; err != nil {
+		return err
+	}
+	if count > 0 {
+		return errors.New("a workflow for this incidence type or code already exists")
+	}
+	return nil
+}
+
+// clearDefaultWorkflow unsets the company's current default
+func clearDefaultWorkflow(tx *gorm.DB, companyID uuid.UUID) error {
+	return tx.Model(&models.ApprovalWorkflow{}).
+		Where("company_id = ? AND is_default = ?", companyID, true).
+		Update("is_default", false).Error
+}
+
+// createWorkflowSteps inserts the steps of a workflow
+func createWorkflowSteps(tx *gorm.DB, workflowID uuid.UUID, steps []models.ApprovalWorkflowStep) error {
+	for i := range steps {
+		steps[i].WorkflowID = workflowID
+		if err := tx.Create(&steps[i]).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// orderSteps orders preloaded workflow steps
+func orderSteps(db *gorm.DB) *gorm.DB {
+	return db.Order("step_order, created_at")
+}
+
+// loadWorkflow loads a workflow with its steps in order
+func (s *ApprovalWorkflowService) loadWorkflow(id uuid.UUID) (*models.ApprovalWorkflow, error) {
+	var workflow models.ApprovalWorkflow
+	if err := s.db.Preload("Steps", orderSteps).First(&workflow, "id = ?", id).Error; err != nil {
+		return nil, errors.New("approval workflow not found")
+	}
+	return &workflow, nil
+}
+
+// =========================================================================
+// Routing
+// =========================================================================
+
+// Resolve returns the workflow a request of the incidence type follows in the company
+func (s *ApprovalWorkflowService) Resolve(companyID uuid.UUID, incidenceTypeID *uuid.UUID) (*models.ApprovalWorkflow, error) {
+	code := models.DefaultApprovalFlow
+	if incidenceTypeID != nil {
+		var bound models.ApprovalWorkflow
+		if err := s.db.Where("company_id = ? AND incidence_type_id = ? AND is_active = ?", companyID, *incidenceTypeID, true).
+			Limit(1).Find(&bound).Error; err != nil {
+			return nil, err
+		}
+		if bound.ID != uuid.Nil {
+			return s.loadWorkflow(bound.ID)
+		}
+		var incidenceType models.IncidenceType
+		if s.db.First(&incidenceType, "id = ?", *incidenceTypeID).Error == nil && incidenceType.ApprovalFlow != "" {
+			code = strings.ToLower(incidenceType.ApprovalFlow)
+		}
+	}
+
+	var byCode models.ApprovalWorkflow
+	if err := s.db.Where("company_id = ? AND incidence_type_id IS NULL AND code = ? AND is_active = ?", companyID, code, true).
+		Limit(1).Find(&byCode).Error; err != nil {
+		return nil, err
+	}
+	if byCode.ID != uuid.Nil {
+		return s.loadWorkflow(byCode.ID)
+	}
+	return s.SeedDefault(companyID)
+}
+
+// subject loads the employee a request belongs to
+func (s *ApprovalWorkflowService) subject(request *models.AbsenceRequest) (*approvalSubject, error) {
+	subject := &approvalSubject{request: request}
+	if err := s.db.First(&subject.user, "id = ?", request.EmployeeID).Error; err != nil {
+		return nil, errors.New("employee not found")
+	}
+	if subject.user.EmployeeID != nil {
+		var employee models.Employee
+		if s.db.First(&employee, "id = ?", *subject.user.EmployeeID).Error == nil {
+			subject.blueGray = isBlueOrGrayCollar(&employee)
+		}
+	}
+	return subject, nil
+}
+
+// route returns the groups of steps whose conditions hold for the subject
+func route(workflow *models.ApprovalWorkflow, subject *approvalSubject) []approvalGroup {
+	var groups []approvalGroup
+	for _, step := range workflow.Steps {
+		if !step.AppliesTo(subject.request, subject.blueGray) {
+			continue
+		}
+		if len(groups) == 0 || groups[len(groups)-1].order != step.StepOrder {
+			groups = append(groups, approvalGroup{order: step.StepOrder})
+		}
+		last := &groups[len(groups)-1]
+		last.steps = append(last.steps, step)
+	}
+	return groups
+}
+
+// requestRoute loads the workflow, subject and route of a request already following a workflow
+func (s *ApprovalWorkflowService) requestRoute(request *models.AbsenceRequest) (*models.ApprovalWorkflow, *approvalSubject, []approvalGroup, error) {
+	if request.WorkflowID == nil {
+		return nil, nil, nil, errors.New("request does not follow an approval workflow")
+	}
+	workflow, err := s.loadWorkflow(*request.WorkflowID)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	subject, err := s.subject(request)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	return workflow, subject, route(workflow, subject), nil
+}
+
+// groupIndex returns the position in the route of the group holding stage
+func groupIndex(groups []approvalGroup, subject *approvalSubject, stage models.ApprovalStage) int {
+	for i, group := range groups {
+		for _, candidate := range group.stages(subject) {
+			if candidate == stage {
+				return i
+			}
+		}
+	}
+	return -1
+}
+
+// start resolves the workflow of a new request and returns its route
+func (s *ApprovalWorkflowService) start(request *models.AbsenceRequest) (*models.ApprovalWorkflow, *approvalSubject, []approvalGroup, error) {
+	subject, err := s.subject(request)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	workflow, err := s.Resolve(subject.user.CompanyID, request.IncidenceTypeID)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	groups := route(workflow, subject)
+	if len(groups) == 0 {
+		return nil, nil, nil, fmt.Errorf("approval workflow %q has no step for this request", workflow.Name)
+	}
+	return workflow, subject, groups, nil
+}
+
+// Next returns the stages a request waits in after an approval at stage.
+// advanced is false while other stages of a parallel group are still
+// waiting; no stages with advanced true means the request is fully approved
+func (s *ApprovalWorkflowService) Next(request *models.AbsenceRequest, stage models.ApprovalStage) (stages []models.ApprovalStage, advanced bool, err error) {
+	_, subject, groups, err := s.requestRoute(request)
+	if err != nil {
+		return nil, false, err
+	}
+	index := groupIndex(groups, subject, stage)
+	if index < 0 {
+		return nil, false, fmt.Errorf("stage %s is not part of the request's approval workflow", stage)
+	}
+
+	var approved []models.ApprovalStage
+	if err := s.db.Model(&models.ApprovalHistory{}).
+		Where("request_id = ? AND action = ?", request.ID, models.ApprovalActionApproved).
+		Pluck("approval_stage", &approved).Error; err != nil {
+		return nil, false, err
+	}
+	done := map[models.ApprovalStage]bool{stage: true}
+	for _, approvedStage := range approved {
+		done[approvedStage] = true
+	}
+	for _, candidate := range groups[index].stages(subject) {
+		if !done[candidate] {
+			stages = append(stages, candidate)
+		}
+	}
+	if len(stages) > 0 {
+		return stages, false, nil
+	}
+	if index+1 < len(groups) {
+		return groups[index+1].stages(subject), true, nil
+	}
+	return nil, true, nil
+}
+
+// EscalationTarget returns the stages a waiting request escalates to and
+// the timeout of its current group (0 = the group never escalates)
+func (s *ApprovalWorkflowService) EscalationTarget(request *models.AbsenceRequest) ([]models.ApprovalStage, int, error) {
+	_, subject, groups, err := s.requestRoute(request)
+	if err != nil {
+		return nil, 0, err
+	}
+	index := groupIndex(groups, subject, request.CurrentApprovalStage)
+	if index < 0 {
+		return nil, 0, fmt.Errorf("stage %s is not part of the request's approval workflow", request.CurrentApprovalStage)
+	}
+	hours, _ := groups[index].sla()
+	if index+1 >= len(groups) {
+		return nil, hours, nil
+	}
+	return groups[index+1].stages(subject), hours, nil
+}
+
+// StageSLA returns the escalation and reminder hours of the request's
+// current stage. Requests created before workflows existed take them from
+// the same stage of the company's default workflow
+func (s *ApprovalWorkflowService) StageSLA(request *models.AbsenceRequest) (hours, reminderHours int, err error) {
+	routed, err := s.slaRequest(request)
+	if err != nil {
+		return 0, 0, err
+	}
+	_, subject, groups, err := s.requestRoute(routed)
+	if err != nil {
+		return 0, 0, err
+	}
+	index := groupIndex(groups, subject, request.CurrentApprovalStage)
+	if index < 0 {
+		return 0, 0, fmt.Errorf("stage %s is not part of the request's approval workflow", request.CurrentApprovalStage)
+	}
+	hours, reminderHours = groups[index].sla()
+	return hours, reminderHours, nil
+}
+
+// StageApproverIDs returns the users who may act on the request's awaiting
+// stages, resolving requests without a workflow against the company default
+func (s *ApprovalWorkflowService) StageApproverIDs(request *models.AbsenceRequest) []uuid.UUID {
+	routed, err := s.slaRequest(request)
+	if err != nil {
+		return nil
+	}
+	seen := map[uuid.UUID]bool{}
+	var ids []uuid.UUID
+	for _, stage := range awaitingStages(request) {
+		for _, id := range s.ApproverIDs(routed, stage) {
+			if !seen[id] {
+				seen[id] = true
+				ids = append(ids, id)
+			}
+		}
+	}
+	return ids
+}
+
+// slaRequest returns the request itself, or a copy bound to the company's
+// default workflow when it predates workflows
+func (s *ApprovalWorkflowService) slaRequest(request *models.AbsenceRequest) (*models.AbsenceRequest, error) {
+	if request.WorkflowID != nil {
+		return request, nil
+	}
+	subject, err := s.subject(request)
+	if err != nil {
+		return nil, err
+	}
+	workflow, err := s.Resolve(subject.user.CompanyID, nil)
+	if err != nil {
+		return nil, err
+	}
+	routed := *request
+	routed.WorkflowID = &workflow.ID
+	return &routed, nil
+}
+
+// step returns the step of the request's route waiting at stage
+func (s *ApprovalWorkflowService) step(groups []approvalGroup, subject *approvalSubject, stage models.ApprovalStage) *models.ApprovalWorkflowStep {
+	for _, group := range groups {
+		for i := range group.steps {
+			if group.steps[i].StageFor(subject.blueGray) == stage {
+				return &group.steps[i]
+			}
+		}
+	}
+	return nil
+}
+
+// CanApprove reports whether approver may act on the request at stage
+func (s *ApprovalWorkflowService) CanApprove(request *models.AbsenceRequest, approver *models.User, stage models.ApprovalStage) (bool, error) {
+	_, subject, groups, err := s.requestRoute(request)
+	if err != nil {
+		return false, err
+	}
+	step := s.step(groups, subject, stage)
+	if step == nil || approver.CompanyID != subject.user.CompanyID {
+		return false, nil
+	}
+	return s.canApproveStep(step, subject, approver), nil
+}
+
+// canApproveStep applies a step's approver rule
+func (s *ApprovalWorkflowService) canApproveStep(step *models.ApprovalWorkflowStep, subject *approvalSubject, approver *models.User) bool {
+	switch step.ApproverRule {
+	case models.ApproverRuleSupervisor:
+		if !canApproveStage(approver.Role, models.ApprovalStageSupervisor) {
+			return false
+		}
+		if subject.user.SupervisorID != nil && *subject.user.SupervisorID == approver.ID {
+			return true
+		}
+		// Whoever covers for a manager on leave
+		scope, _ := s.orgStructure.ApprovalScopeUserIDs(approver.ID, time.Now())
+		for _, userID := range scope {
+			if userID == subject.user.ID {
+				return true
+			}
+		}
+		return false
+	case models.ApproverRuleRole:
+		for _, role := range step.ApproverRoles {
+			if enums.UserRole(role) == approver.Role {
+				return true
+			}
+		}
+		return false
+	case models.ApproverRuleUser:
+		return step.ApproverUserID != nil && *step.ApproverUserID == approver.ID
+	case models.ApproverRuleHRByCollar:
+		return canApproveStage(approver.Role, step.StageFor(subject.blueGray))
+	}
+	return false
+}
+
+// ApproverIDs returns the users who may act on the request at stage, for notifications
+func (s *ApprovalWorkflowService) ApproverIDs(request *models.AbsenceRequest, stage models.ApprovalStage) []uuid.UUID {
+	_, subject, groups, err := s.requestRoute(request)
+	if err != nil {
+		return nil
+	}
+	step := s.step(groups, subject, stage)
+	if step == nil {
+		return nil
+	}
+
+	switch step.ApproverRule {
+	case models.ApproverRuleSupervisor:
+		if resolution, err := s.orgStructure.ResolveApproverForUser(subject.user.ID, time.Now()); err == nil &&
+			resolution != nil && resolution.ApproverUserID != nil {
+			return []uuid.UUID{*resolution.ApproverUserID}
+		}
+		if subject.user.SupervisorID != nil {
+			return []uuid.UUID{*subject.user.SupervisorID}
+		}
+		return nil
+	case models.ApproverRuleUser:
+		if step.ApproverUserID != nil {
+			return []uuid.UUID{*step.ApproverUserID}
+		}
+		return nil
+	}
+
+	roles := []string(step.ApproverRoles)
+	if step.ApproverRule == models.ApproverRuleHRByCollar {
+		roles = []string{string(enums.RoleHR), string(enums.RoleHRAndPR), string(enums.RoleHRBlueGray)}
+	}
+	var users []models.User
+	s.db.Where("company_id = ? AND role IN ? AND is_active = ?", subject.user.CompanyID, roles, true).Find(&users)
+	var ids []uuid.UUID
+	for i := range users {
+		if s.canApproveStep(step, subject, &users[i]) {
+			ids = append(ids, users[i].ID)
+		}
+	}
+	return ids
+}
+
+// PendingFor returns the workflow-routed requests waiting for the approver
+func (s *ApprovalWorkflowService) PendingFor(approverID uuid.UUID) ([]models.AbsenceRequest, error) {
+	var approver models.User
+	if err := s.db.First(&approver, "id = ?", approverID).Error; err != nil {
+		return nil, errors.New("user not found")
+	}
+
+	var requests []models.AbsenceRequest
+	if err := s.db.Preload("ApprovalHistory").
+		Joins("JOIN users ON users.id = absence_requests.employee_id").
+		Where("users.company_id = ? AND absence_requests.status = ? AND absence_requests.workflow_id IS NOT NULL",
+			approver.CompanyID, models.RequestStatusPending).
+		Order("absence_requests.created_at DESC").
+		Find(&requests).Error; err != nil {
+		return nil, fmt.Errorf("error fetching pending requests: %w", err)
+	}
+
+	pending := make([]models.AbsenceRequest, 0, len(requests))
+	for i := range requests {
+		for _, stage := range awaitingStages(&requests[i]) {
+			if ok, _ := s.CanApprove(&requests[i], &approver, stage); ok {
+				pending = append(pending, requests[i])
+				break
+			}
+		}
+	}
+	return pending, nil
+}
+
+// Preview returns the route a request of the employee would follow
+func (s *ApprovalWorkflowService) Preview(companyID, employeeUserID uuid.UUID, requestType models.RequestType, totalDays float64, incidenceTypeID *uuid.UUID) (*dtos.ApprovalRoutePreview, error) {
+	request := &models.AbsenceRequest{
+		EmployeeID:      employeeUserID,
+		RequestType:     requestType,
+		TotalDays:       totalDays,
+		IncidenceTypeID: incidenceTypeID,
+	}
+	subject, err := s.subject(request)
+	if err != nil || subject.user.CompanyID != companyID {
+		return nil, errors.New("employee not found")
+	}
+	workflow, err := s.Resolve(companyID, incidenceTypeID)
+	if err != nil {
+		return nil, err
+	}
+
+	preview := &dtos.ApprovalRoutePreview{WorkflowID: workflow.ID, WorkflowName: workflow.Name, Groups: [][]string{}}
+	for _, group := range route(workflow, subject) {
+		var stages []string
+		for _, stage := range group.stages(subject) {
+			stages = append(stages, string(stage))
+		}
+		preview.Groups = append(preview.Groups, stages)
+	}
+	return preview, nil
+}
+
+// isBuiltInStage reports whether stage belongs to the built-in chain
+func isBuiltInStage(stage models.ApprovalStage) bool {
+	switch stage {
+	case models.ApprovalStageSupervisor, models.ApprovalStageManager, models.ApprovalStageHR,
+		models.ApprovalStageHRBlueGray, models.ApprovalStageGeneralManager, models.ApprovalStagePayroll:
+		return true
+	}
+	return false
+}
+
+// supervisorOrManagerGroup reports whether a group only holds SUPERVISOR
+// and MANAGER steps, which a SUPANDGM supervisor approves on creation
+func supervisorOrManagerGroup(group approvalGroup) bool {
+	for _, step := range group.steps {
+		if step.Stage != models.ApprovalStageSupervisor && step.Stage != models.ApprovalStageManager {
+			return false
+		}
+	}
+	return true
+}
+
+// awaitingStages returns every stage a request is waiting in
+func awaitingStages(request *models.AbsenceRequest) []models.ApprovalStage {
+	if request.PendingStages == "" {
+		return []models.ApprovalStage{request.CurrentApprovalStage}
+	}
+	var stages []models.ApprovalStage
+	for _, stage := range strings.Split(strings.Trim(request.PendingStages, "|"), "|") {
+		if stage != "" {
+			stages = append(stages, models.ApprovalStage(stage))
+		}
+	}
+	return stages
+}
+
+// setAwaitingStages records the stages a request waits in
+func setAwaitingStages(request *models.AbsenceRequest, stages []models.ApprovalStage) {
+	if len(stages) == 0 {
+		request.CurrentApprovalStage = models.ApprovalStageCompleted
+		request.PendingStages = ""
+		return
+	}
+	request.CurrentApprovalStage = stages[0]
+	request.PendingStages = ""
+	if len(stages) > 1 {
+		codes := make([]string, len(stages))
+		for i, stage := range stages {
+			codes[i] = string(stage)
+		}
+		request.PendingStages = "|" + strings.Join(codes, "|") + "|"
+	}
+}
@@ -0,0 +1,132 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"backend/internal/dtos"
+	"backend/internal/models"
+	"backend/internal/models/enums"
+)
+
+func TestApprovalWorkflow_DefaultConditionalParallelAndEscalation(t *testing.T) {
+	db := setupTestDB(t)
+	require.NoError(t, db.AutoMigrate(
+		&models.ApprovalWorkflow{}, &models.ApprovalWorkflowStep{}, &models.IncidenceType{},
+		&models.Incidence{}, &models.Notification{}, &models.Message{}, &models.ApprovalDelegation{}, &models.StaffingRule{}, &models.BlackoutPeriod{},
+	))
+	workflows := NewApprovalWorkflowService(db)
+	requests := NewAbsenceRequestService(db, nil)
+
+	_, employee := createTestEmployee(db, "white_collar")
+	user := func(email string, role enums.UserRole) *models.User {
+		u := &models.User{CompanyID: employee.CompanyID, Email: email, PasswordHash: "x", Role: role, FullName: email, IsActive: true}
+		require.NoError(t, db.Create(u).Error)
+		return u
+	}
+	supervisor := user("sup@example.com", enums.RoleSupervisor)
+	otherSupervisor := user("sup2@example.com", enums.RoleSupervisor)
+	manager := user("manager@example.com", enums.RoleManager)
+	hr := user("hr@example.com", enums.RoleHR)
+	payroll := user("payroll@example.com", enums.RolePayrollStaff)
+	doctor := user("doctor@example.com", enums.RoleEmployee)
+	require.NoError(t, db.Model(employee).Update("supervisor_id", supervisor.ID).Error)
+
+	// The seeded default reproduces the built-in chain
+	preview, err := workflows.Preview(employee.CompanyID, employee.ID, models.RequestTypeVacation, 1, nil)
+	require.NoError(t, err)
+	assert.Equal(t, [][]string{{"SUPERVISOR"}, {"MANAGER"}, {"HR"}, {"GENERAL_MANAGER"}, {"PAYROLL"}}, preview.Groups)
+
+	standard, err := workflows.SeedDefault(employee.CompanyID)
+	require.NoError(t, err)
+	assert.Equal(t, standard.ID, preview.WorkflowID)
+	assert.ErrorContains(t, workflows.DeleteWorkflow(employee.CompanyID, standard.ID), "cannot be deleted")
+
+	// Skip the general manager under 2 days; sick leave adds a medical review next to HR
+	steps := []dtos.ApprovalWorkflowStepRequest{
+		{StepOrder: 1, Stage: "SUPERVISOR", ApproverRule: "supervisor", CollarScope: "white", EscalationHours: 24},
+		{StepOrder: 2, Stage: "MANAGER", ApproverRule: "role", ApproverRoles: []string{"manager", "sup_and_gm"}, CollarScope: "white"},
+		{StepOrder: 3, Stage: "HR", ApproverRule: "hr_by_collar"},
+		{StepOrder: 3, Stage: "medical_review", ApproverRule: "user", ApproverUserID: &doctor.ID, RequestTypes: []string{"SICK_LEAVE"}},
+		{StepOrder: 4, Stage: "GENERAL_MANAGER", ApproverRule: "role", ApproverRoles: []string{"manager"}, MinDays: 2},
+		{StepOrder: 5, Stage: "PAYROLL", ApproverRule: "role", ApproverRoles: []string{"payroll_staff"}},
+	}
+	_, err = workflows.UpdateWorkflow(employee.CompanyID, standard.ID, dtos.ApprovalWorkflowRequest{
+		Name: "Flujo estándar", IsDefault: true, Steps: append(steps, dtos.ApprovalWorkflowStepRequest{StepOrder: 6, Stage: "HR_BLUE_GRAY", ApproverRule: "supervisor"}),
+	})
+	assert.ErrorContains(t, err, "only once")
+	_, err = workflows.UpdateWorkflow(employee.CompanyID, standard.ID, dtos.ApprovalWorkflowRequest{Name: "Flujo estándar", IsDefault: true, Steps: steps})
+	require.NoError(t, err)
+
+	preview, err = workflows.Preview(employee.CompanyID, employee.ID, models.RequestTypeSickLeave, 1, nil)
+	require.NoError(t, err)
+	assert.Equal(t, [][]string{{"SUPERVISOR"}, {"MANAGER"}, {"HR", "MEDICAL_REVIEW"}, {"PAYROLL"}}, preview.Groups)
+
+	now := time.Now()
+	request := &models.AbsenceRequest{EmployeeID: employee.ID, RequestType: models.RequestTypeSickLeave, StartDate: now, EndDate: now,
+		TotalDays: 1, Reason: "Gripe", WorkflowID: &standard.ID, CurrentApprovalStage: models.ApprovalStageSupervisor, LastActionAt: now}
+	require.NoError(t, db.Create(request).Error)
+	approve := func(approver *models.User, stage models.ApprovalStage) error {
+		_, err := requests.ApproveRequest(ApproveRequestInput{RequestID: request.ID, ApproverID: approver.ID, Stage: stage, Action: models.ApprovalActionApproved})
+		return err
+	}
+	reload := func() *models.AbsenceRequest {
+		var current models.AbsenceRequest
+		require.NoError(t, db.First(&current, "id = ?", request.ID).Error)
+		return &current
+	}
+
+	// Only the employee's own supervisor approves the supervisor step
+	assert.ErrorContains(t, approve(otherSupervisor, models.ApprovalStageSupervisor), "unauthorized")
+	require.NoError(t, approve(supervisor, models.ApprovalStageSupervisor))
+	require.NoError(t, approve(manager, models.ApprovalStageManager))
+
+	// HR and the medical review run in parallel
+	current := reload()
+	assert.Equal(t, models.ApprovalStageHR, current.CurrentApprovalStage)
+	assert.Equal(t, "|HR|MEDICAL_REVIEW|", current.PendingStages)
+	inbox, err := workflows.PendingFor(doctor.ID)
+	require.NoError(t, err)
+	require.Len(t, inbox, 1)
+	assert.ErrorContains(t, approve(hr, "MEDICAL_REVIEW"), "unauthorized")
+	require.NoError(t, approve(doctor, "MEDICAL_REVIEW"))
+	current = reload()
+	assert.Equal(t, models.ApprovalStageHR, current.CurrentApprovalStage)
+	assert.Empty(t, current.PendingStages)
+
+	// One day: the general manager is skipped
+	require.NoError(t, approve(hr, models.ApprovalStageHR))
+	assert.Equal(t, models.ApprovalStagePayroll, reload().CurrentApprovalStage)
+	require.NoError(t, approve(payroll, models.ApprovalStagePayroll))
+	current = reload()
+	assert.Equal(t, models.RequestStatusApproved, current.Status)
+	assert.Equal(t, models.ApprovalStageCompleted, current.CurrentApprovalStage)
+
+	// Steps escalate after their own timeout; steps without one never do
+	stale := &models.AbsenceRequest{EmployeeID: employee.ID, RequestType: models.RequestTypeVacation, StartDate: now, EndDate: now,
+		TotalDays: 3, Reason: "Viaje", WorkflowID: &standard.ID, CurrentApprovalStage: models.ApprovalStageSupervisor,
+		LastActionAt: now.Add(-25 * time.Hour)}
+	require.NoError(t, db.Create(stale).Error)
+	escalations := NewEscalationService(db)
+	require.NoError(t, escalations.ProcessPendingEscalations())
+	require.NoError(t, db.Model(stale).Update("last_action_at", now.Add(-200*time.Hour)).Error)
+	require.NoError(t, escalations.ProcessPendingEscalations())
+	require.NoError(t, db.First(stale, "id = ?", stale.ID).Error)
+	assert.Equal(t, models.ApprovalStageManager, stale.CurrentApprovalStage)
+	assert.Equal(t, 1, stale.EscalationCount)
+	var logs []models.EscalationLog
+	db.Where("absence_request_id = ?", stale.ID).Find(&logs)
+	require.Len(t, logs, 1)
+	assert.Equal(t, "24 hours without approval", logs[0].EscalationReason)
+
+	// Blue collar starts at HR_BLUE_GRAY
+	require.NoError(t, db.Model(&models.Employee{}).Where("id = ?", *employee.EmployeeID).UpdateColumn("collar_type", "blue_collar").Error)
+	preview, err = workflows.Preview(employee.CompanyID, employee.ID, models.RequestTypeVacation, 3, nil)
+	require.NoError(t, err)
+	assert.Equal(t, [][]string{{"HR_BLUE_GRAY"}, {"GENERAL_MANAGER"}, {"PAYROLL"}}, preview.Groups)
+	assert.NotEqual(t, uuid.Nil, preview.WorkflowID)
+}
@@ -0,0 +1,996 @@
+/*
+Package services - Attendance Evaluation Service
+
+==============================================================================
+FILE: internal/services/attendance_evaluation_service.go
+==============================================================================
+
+DESCRIPTION:
+    Evaluates attendance day by day. For every date it resolves the
+    scheduled shift, collects the punches (web/app/NFC terminal ClockRecord
+    and the legacy attendance_records table), and derives late arrivals, early exits,
+    unjustified absences, worked rest days/holidays/Sundays and overtime.
+    The result is stored as AttendanceDay rows and summarised for the
+    prenómina.
+
+USER PERSPECTIVE:
+    - Delays and overtime come from real punches, not only manual incidences
+    - An absence covered by an approved request or incidence is not a "falta"
+    - HR overrides a day with a reason; recalculations keep the override
+
+DEVELOPER GUIDELINES:
+    ✅  OK to modify: Punch sources, status rules, tolerance handling
+    ⚠️  CAUTION: Schedule priority is ShiftException > RotationAssignment >
+        EmployeeShiftBase > Employee.ShiftID; changing it changes every
+        prenómina
+    ⚠️  CAUTION: Days already covered by a delay/overtime incidence drop the
+        evaluated delay/overtime so nothing is paid or deducted twice
+    ❌  DO NOT modify: Overridden days from the evaluator
+    📝  Tolerances live in configs/payroll/labor_concepts.json ("attendance")
+
+SYNTAX EXPLANATION:
+    - EmployeeShiftBase.DayOfWeek: 0=Monday ... 6=Sunday
+    - Shift.WorkDays: JSON array with 0=Sunday ... 6=Saturday
+    - Night shifts: EndTime <= StartTime means the shift ends the next day
+    - A clock-in belongs to the shift it falls in, from PunchWindowHours
+      before the scheduled start until the scheduled end; otherwise to the
+      calendar day it happened on
+    - Overtime: the first WeeklyDoubleTimeHours of each ISO week are double
+      time, the rest triple time (LFT Art. 67-68)
+    - RequireOvertimeAuthorization: overtime is capped per day at the hours
+      of approved OvertimeRequests; the excess is UnauthorizedOvertimeHours,
+      shown for review and paid only if HR overrides the day
+    - Overtime requested as "bank" is BankedOvertimeHours, never paid: it
+      is deposited in the time-for-time bank when reconciled
+    - Partial-day permits (AbsenceRequest.StartTime/EndTime) do not justify
+      the whole day; a window at the shift start moves the expected arrival
+      to its end, one at the shift end moves the expected exit to its start
+
+==============================================================================
+*/
+package services
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"backend/internal/config"
+	"backend/internal/config/payroll/types"
+	"backend/internal/dtos"
+	"backend/internal/models"
+)
+
+// attendanceDateKey is the map key format for a calendar date
+const attendanceDateKey = "2006-01-02"
+
+// terminalAttendanceTable is written by the NFC attendance terminal (cmd/attendance-tui)
+const terminalAttendanceTable = "attendance_records"
+
+// defaultAttendanceRules are used when the payroll config has no attendance section
+var defaultAttendanceRules = types.AttendanceRules{
+	LateToleranceMinutes:      10,
+	EarlyExitToleranceMinutes: 5,
+	OvertimeMinimumMinutes:    30,
+	OvertimeBlockMinutes:      30,
+	PunchWindowHours:          4,
+	WeeklyDoubleTimeHours:     9,
+	RestDayPremiumFactor:      2,
+	SwipeDebounceSeconds:      60,
+}
+
+// AttendanceEvaluationService compares punches against the scheduled shift
+type AttendanceEvaluationService struct {
+	db             *gorm.DB
+	holidayCatalog *HolidayCatalogService
+	rules          types.AttendanceRules
+}
+
+// NewAttendanceEvaluationService creates a new AttendanceEvaluationService
+func NewAttendanceEvaluationService(db *gorm.DB, appConfig *config.AppConfig) *AttendanceEvaluationService {
+	rules := defaultAttendanceRules
+	if appConfig != nil && appConfig.PayrollConfig != nil && appConfig.PayrollConfig.LaborConcepts.Attendance != (types.AttendanceRules{}) {
+		rules = appConfig.PayrollConfig.LaborConcepts.Attendance
+		if rules.PunchWindowHours <= 0 {
+			rules.PunchWindowHours = defaultAttendanceRules.PunchWindowHours
+		}
+		if rules.WeeklyDoubleTimeHours <= 0 {
+			rules.WeeklyDoubleTimeHours = defaultAttendanceRules.WeeklyDoubleTimeHours
+		}
+		if rules.RestDayPremiumFactor <= 0 {
+			rules.RestDayPremiumFactor = defaultAttendanceRules.RestDayPremiumFactor
+		}
+		if rules.SwipeDebounceSeconds <= 0 {
+			rules.SwipeDebounceSeconds = defaultAttendanceRules.SwipeDebounceSeconds
+		}
+	}
+	return &AttendanceEvaluationService{
+		db:             db,
+		holidayCatalog: NewHolidayCatalogService(db, appConfig),
+		rules:          rules,
+	}
+}
+
+// withDB returns a copy of the service that queries through db (e.g. a transaction)
+func (s *AttendanceEvaluationService) withDB(db *gorm.DB) *AttendanceEvaluationService {
+	clone := *s
+	clone.db = db
+	return &clone
+}
+
+// Rules returns the tolerances in use
+func (s *AttendanceEvaluationService) Rules() types.AttendanceRules {
+	return s.rules
+}
+
+// daySchedule is the resolved schedule of one date
+type daySchedule struct {
+	shift   *models.Shift
+	source  string
+	start   time.Time // zero on rest days and without a shift
+	end     time.Time
+	hours   float64
+	restDay bool
+}
+
+// punchPair is one clock-in with its (optional) clock-out
+type punchPair struct {
+	in           time.Time
+	out          *time.Time
+	breakMinutes float64
+	source       string
+	claimed      bool
+}
+
+// terminalPunch is one row of the NFC terminal's attendance_records table
+type terminalPunch struct {
+	CheckIn  time.Time
+	CheckOut *time.Time
+}
+
+// EvaluatePeriod evaluates every day of a payroll period and returns the summary
+func (s *AttendanceEvaluationService) EvaluatePeriod(employee *models.Employee, period *models.PayrollPeriod) (*dtos.AttendanceSummary, error) {
+	days, err := s.EvaluateRange(employee, period.StartDate, period.EndDate, &period.ID)
+	if err != nil {
+		return nil, err
+	}
+	summary := s.Summarize(employee.ID, attendanceDate(period.StartDate), attendanceDate(period.EndDate), days)
+	return &summary, nil
+}
+
+// EvaluateRange evaluates an employee between two dates (inclusive) and stores the days.
+// Overridden days are returned as they are.
+func (s *AttendanceEvaluationService) EvaluateRange(employee *models.Employee, start, end time.Time, periodID *uuid.UUID) ([]models.AttendanceDay, error) {
+	start, end = attendanceDate(start), attendanceDate(end)
+	if end.Before(start) {
+		return nil, errors.New("end date must be on or after start date")
+	}
+
+	schedule, err := s.loadSchedules(employee, start, end)
+	if err != nil {
+		return nil, err
+	}
+	holidays, err := s.holidayCatalog.GetHolidays(employee.CompanyID, start, end)
+	if err != nil {
+		return nil, err
+	}
+	holidayNames := make(map[string]string, len(holidays))
+	for _, h := range holidays {
+		holidayNames[h.Date.Format(attendanceDateKey)] = h.Name
+	}
+	pairs, err := s.loadPunches(employee.ID, start.AddDate(0, 0, -1), end.AddDate(0, 0, 2))
+	if err != nil {
+		return nil, err
+	}
+	leave, coverage, err := s.loadJustifications(employee, start, end)
+	if err != nil {
+		return nil, err
+	}
+	permits, err := s.loadPermits(employee, start, end)
+	if err != nil {
+		return nil, err
+	}
+	authorized, banked, err := s.loadOvertimeAuthorizations(employee, start, end)
+	if err != nil {
+		return nil, err
+	}
+
+	var existing []models.AttendanceDay
+	if err := s.db.Where("employee_id = ? AND work_date BETWEEN ? AND ?", employee.ID, start, end).
+		Find(&existing).Error; err != nil {
+		return nil, err
+	}
+	existingByDate := make(map[string]models.AttendanceDay, len(existing))
+	for _, day := range existing {
+		existingByDate[day.WorkDate.Format(attendanceDateKey)] = day
+	}
+
+	// Pass 1: punches inside a scheduled shift's window belong to that shift,
+	// so a night shift keeps the clock-out it makes after midnight
+	var dates []time.Time
+	for date := start; !date.After(end); date = date.AddDate(0, 0, 1) {
+		dates = append(dates, date)
+	}
+	schedules := make([]daySchedule, len(dates))
+	claimed := make([][]*punchPair, len(dates))
+	window := time.Duration(s.rules.PunchWindowHours * float64(time.Hour))
+	for i, date := range dates {
+		schedules[i] = schedule(date)
+		if schedules[i].start.IsZero() {
+			continue
+		}
+		from, to := schedules[i].start.Add(-window), schedules[i].end
+		for j := range pairs {
+			if !pairs[j].claimed && !pairs[j].in.Before(from) && pairs[j].in.Before(to) {
+				pairs[j].claimed = true
+				claimed[i] = append(claimed[i], &pairs[j])
+			}
+		}
+	}
+	// Pass 2: anything left belongs to the calendar day it happened on
+	for i, date := range dates {
+		for j := range pairs {
+			if !pairs[j].claimed && attendanceDate(pairs[j].in).Equal(date) {
+				pairs[j].claimed = true
+				claimed[i] = append(claimed[i], &pairs[j])
+			}
+		}
+	}
+
+	now := time.Now()
+	days := make([]models.AttendanceDay, 0, len(dates))
+	err = s.db.Transaction(func(tx *gorm.DB) error {
+		for i, date := range dates {
+			key := date.Format(attendanceDateKey)
+			previous, found := existingByDate[key]
+			if found && previous.IsOverridden {
+				if previous.PayrollPeriodID == nil && periodID != nil {
+					previous.PayrollPeriodID = periodID
+					if err := tx.Model(&previous).UpdateColumn("payroll_period_id", periodID).Error; err != nil {
+						return err
+					}
+				}
+				days = append(days, previous)
+				continue
+			}
+
+			overtimeLimit := math.Inf(1)
+			if authorized != nil {
+				overtimeLimit = authorized[key]
+			}
+			day := s.evaluateDay(employee, date, schedules[i], claimed[i], holidayNames[key], leave[key], coverage[key],
+				permits[key], overtimeLimit, banked[key], now)
+			day.PayrollPeriodID = periodID
+			if found {
+				day.ID = previous.ID
+				day.CreatedAt = previous.CreatedAt
+				if day.PayrollPeriodID == nil {
+					day.PayrollPeriodID = previous.PayrollPeriodID
+				}
+				if err := tx.Save(&day).Error; err != nil {
+					return err
+				}
+			} else if err := tx.Create(&day).Error; err != nil {
+				return err
+			}
+			days = append(days, day)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error saving attendance days: %w", err)
+	}
+	return days, nil
+}
+
+// evaluateDay compares one date's punches against its schedule
+func (s *AttendanceEvaluationService) evaluateDay(
+	employee *models.Employee,
+	date time.Time,
+	sched daySchedule,
+	pairs []*punchPair,
+	holidayName, leave string,
+	covered map[string]string,
+	permits []models.AbsenceRequest,
+	authorizedOvertime, bankedOvertime float64,
+	now time.Time,
+) models.AttendanceDay {
+	day := models.AttendanceDay{
+		CompanyID:      employee.CompanyID,
+		EmployeeID:     employee.ID,
+		WorkDate:       date,
+		ScheduleSource: sched.source,
+		ScheduledHours: sched.hours,
+		IsRestDay:      sched.restDay,
+		IsHoliday:      holidayName != "",
+		IsSunday:       date.Weekday() == time.Sunday,
+		EvaluatedAt:    now,
+	}
+	if sched.shift != nil {
+		day.ShiftID = &sched.shift.ID
+	}
+	if !sched.start.IsZero() {
+		scheduledStart, scheduledEnd := sched.start, sched.end
+		day.ScheduledStart, day.ScheduledEnd = &scheduledStart, &scheduledEnd
+	}
+
+	s.applyPunches(&day, sched, pairs)
+
+	// Approved partial-day permits move the expected arrival and exit
+	expectedIn, expectedOut := sched.start, sched.end
+	permitLabel := ""
+	if !sched.start.IsZero() && len(permits) > 0 {
+		for i := range permits {
+			from, to, err := permitBounds(date, sched, permits[i].StartTime, permits[i].EndTime)
+			if err != nil {
+				continue
+			}
+			permitLabel = string(permits[i].RequestType)
+			if !from.After(sched.start) && to.After(expectedIn) {
+				expectedIn = to
+			}
+			if !to.Before(sched.end) && from.Before(expectedOut) {
+				expectedOut = from
+			}
+		}
+		if !expectedIn.Before(expectedOut) {
+			// The permits cover the whole shift
+			leave = permitLabel
+		}
+	}
+
+	if day.PunchCount == 0 {
+		switch {
+		case day.IsHoliday:
+			day.Status = models.AttendanceStatusHoliday
+			day.Justification = holidayName
+		case sched.restDay:
+			day.Status = models.AttendanceStatusRestDay
+		case sched.source == models.ScheduleSourceNone:
+			day.Status = models.AttendanceStatusUnscheduled
+		case leave != "":
+			day.Status = models.AttendanceStatusJustified
+			day.Justification = leave
+		case date.After(attendanceDate(now)) || (!sched.end.IsZero() && sched.end.After(now)):
+			// The shift has not finished yet; do not call it a falta
+			day.Status = models.AttendanceStatusPending
+		default:
+			day.Status = models.AttendanceStatusAbsent
+		}
+		return day
+	}
+
+	switch {
+	case day.IsHoliday:
+		day.Status = models.AttendanceStatusWorkedHoliday
+		day.Justification = holidayName
+		return day
+	case sched.restDay:
+		day.Status = models.AttendanceStatusWorkedRestDay
+		return day
+	case sched.start.IsZero():
+		day.Status = models.AttendanceStatusUnscheduled
+		return day
+	}
+
+	day.Status = models.AttendanceStatusPresent
+	day.Justification = permitLabel
+	if late := day.ActualIn.Sub(expectedIn).Minutes(); late > float64(s.rules.LateToleranceMinutes) {
+		if name, ok := covered["delay"]; ok {
+			day.Justification = name
+		} else {
+			day.LateMinutes = math.Floor(late)
+			day.Status = models.AttendanceStatusLate
+		}
+	}
+
+	if day.ActualOut == nil {
+		day.Status = models.AttendanceStatusIncomplete
+		return day
+	}
+	if early := expectedOut.Sub(*day.ActualOut).Minutes(); early > float64(s.rules.EarlyExitToleranceMinutes) {
+		day.EarlyExitMinutes = math.Floor(early)
+	}
+	if extra := day.ActualOut.Sub(sched.end).Minutes(); extra >= float64(s.rules.OvertimeMinimumMinutes) && extra > 0 {
+		if block := float64(s.rules.OvertimeBlockMinutes); block > 0 {
+			extra = math.Floor(extra/block) * block
+		}
+		if name, ok := covered["overtime"]; ok {
+			// Already captured as an overtime incidence
+			day.Justification = name
+		} else {
+			// Banked hours go to the time bank first; of the rest only the
+			// pre-authorized hours are paid and the excess waits for HR review
+			hours := roundCurrency(extra / 60)
+			day.BankedOvertimeHours = math.Min(hours, bankedOvertime)
+			paid := roundCurrency(hours - day.BankedOvertimeHours)
+			day.OvertimeHours = math.Min(paid, authorizedOvertime)
+			day.UnauthorizedOvertimeHours = roundCurrency(paid - day.OvertimeHours)
+		}
+	}
+	return day
+}
+
+// permitBounds places a partial-day permit's HH:MM window on the shift of
+// date; on a night shift the times after midnight fall on the next morning
+func permitBounds(date time.Time, sched daySchedule, startTime, endTime string) (time.Time, time.Time, error) {
+	from, to, err := overtimeWindow(date, startTime, endTime)
+	if err != nil {
+		return from, to, err
+	}
+	if !sched.start.IsZero() && from.Before(sched.start) && from.AddDate(0, 0, 1).Before(sched.end) {
+		from, to = from.AddDate(0, 0, 1), to.AddDate(0, 0, 1)
+	}
+	return from, to, nil
+}
+
+// applyPunches fills actual in/out and worked hours; overlapping punches from
+// two sources (app and terminal) are counted once
+func (s *AttendanceEvaluationService) applyPunches(day *models.AttendanceDay, sched daySchedule, pairs []*punchPair) {
+	if len(pairs) == 0 {
+		return
+	}
+	sort.Slice(pairs, func(i, j int) bool { return pairs[i].in.Before(pairs[j].in) })
+
+	actualIn := pairs[0].in
+	day.ActualIn = &actualIn
+	day.PunchCount = len(pairs)
+
+	var lastOut, coveredUntil time.Time
+	var workedMinutes, breakMinutes float64
+	complete, terminalOnly := true, true
+	sources := make(map[string]bool)
+	for _, pair := range pairs {
+		sources[pair.source] = true
+		if pair.source != "terminal" {
+			terminalOnly = false
+		}
+		breakMinutes += pair.breakMinutes
+		if pair.out == nil {
+			complete = false
+			continue
+		}
+		if pair.out.After(lastOut) {
+			lastOut = *pair.out
+		}
+		from := pair.in
+		if coveredUntil.After(from) {
+			from = coveredUntil
+		}
+		if pair.out.After(from) {
+			workedMinutes += pair.out.Sub(from).Minutes()
+			coveredUntil = *pair.out
+		}
+	}
+	// A single terminal punch pair with no break recorded (legacy terminal,
+	// or an employee who did not tap for the meal): deduct the shift's break
+	if terminalOnly && len(pairs) == 1 && breakMinutes == 0 && sched.shift != nil {
+		breakMinutes = float64(sched.shift.BreakMinutes)
+	}
+	if complete && !lastOut.IsZero() {
+		day.ActualOut = &lastOut
+	}
+
+	names := make([]string, 0, len(sources))
+	for name := range sources {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	day.PunchSource = strings.Join(names, ",")
+	day.WorkedHours = roundCurrency(math.Max(workedMinutes-breakMinutes, 0) / 60)
+}
+
+// loadSchedules prepares the schedule lookup for an employee and date range
+func (s *AttendanceEvaluationService) loadSchedules(employee *models.Employee, start, end time.Time) (func(time.Time) daySchedule, error) {
+	rotations, err := loadRotations(s.db, employee.CompanyID)
+	if err != nil {
+		return nil, err
+	}
+	return s.loadSchedulesWith(employee, start, end, rotations)
+}
+
+// loadSchedulesWith is loadSchedules with the company's rotations already loaded
+func (s *AttendanceEvaluationService) loadSchedulesWith(employee *models.Employee, start, end time.Time, rotations *rotationResolver) (func(time.Time) daySchedule, error) {
+	var exceptions []models.ShiftException
+	if err := s.db.Where("employee_id = ? AND date BETWEEN ? AND ?", employee.ID, start, end).
+		Find(&exceptions).Error; err != nil {
+		return nil, err
+	}
+	var bases []models.EmployeeShiftBase
+	if err := s.db.Where("employee_id = ?", employee.ID).Find(&bases).Error; err != nil {
+		return nil, err
+	}
+
+	shiftIDs := make([]uuid.UUID, 0, len(exceptions)+len(bases)+1)
+	exceptionShift := make(map[string]uuid.UUID, len(exceptions))
+	for _, e := range exceptions {
+		exceptionShift[attendanceDate(e.Date).Format(attendanceDateKey)] = e.ShiftID
+		shiftIDs = append(shiftIDs, e.ShiftID)
+	}
+	weeklyShift := make(map[int]uuid.UUID, len(bases))
+	for _, b := range bases {
+		weeklyShift[b.DayOfWeek] = b.ShiftID
+		shiftIDs = append(shiftIDs, b.ShiftID)
+	}
+	if employee.ShiftID != nil {
+		shiftIDs = append(shiftIDs, *employee.ShiftID)
+	}
+
+	shifts := make(map[uuid.UUID]*models.Shift)
+	if len(shiftIDs) > 0 {
+		var rows []models.Shift
+		if err := s.db.Where("id IN ?", shiftIDs).Find(&rows).Error; err != nil {
+			return nil, err
+		}
+		for i := range rows {
+			shifts[rows[i].ID] = &rows[i]
+		}
+	}
+
+	return func(date time.Time) daySchedule {
+		if id, ok := exceptionShift[date.Format(attendanceDateKey)]; ok && shifts[id] != nil {
+			return buildDaySchedule(date, shifts[id], models.ScheduleSourceException, true)
+		}
+		if shift, ok := rotations.shiftFor(employee, date); ok {
+			if shift == nil {
+				return daySchedule{source: models.ScheduleSourceRotation, restDay: true}
+			}
+			return buildDaySchedule(date, shift, models.ScheduleSourceRotation, true)
+		}
+		// A weekly pattern defines the whole week: days without an entry are rest days
+		if len(weeklyShift) > 0 {
+			id, ok := weeklyShift[(int(date.Weekday())+6)%7]
+			if !ok || shifts[id] == nil {
+				return daySchedule{source: models.ScheduleSourceWeekly, restDay: true}
+			}
+			return buildDaySchedule(date, shifts[id], models.ScheduleSourceWeekly, true)
+		}
+		if employee.ShiftID != nil && shifts[*employee.ShiftID] != nil {
+			shift := shifts[*employee.ShiftID]
+			return buildDaySchedule(date, shift, models.ScheduleSourceDefault, shiftWorksOn(shift, date.Weekday()))
+		}
+		return daySchedule{source: models.ScheduleSourceNone}
+	}, nil
+}
+
+// buildDaySchedule turns a shift's HH:MM times into instants on the given date
+func buildDaySchedule(date time.Time, shift *models.Shift, source string, working bool) daySchedule {
+	sched := daySchedule{shift: shift, source: source}
+	if shift.IsRestDay || !working {
+		sched.restDay = true
+		return sched
+	}
+	startClock, errStart := time.Parse("15:04", shift.StartTime)
+	endClock, errEnd := time.Parse("15:04", shift.EndTime)
+	if errStart != nil || errEnd != nil {
+		return daySchedule{shift: shift, source: models.ScheduleSourceNone}
+	}
+
+	sched.start = time.Date(date.Year(), date.Month(), date.Day(), startClock.Hour(), startClock.Minute(), 0, 0, date.Location())
+	sched.end = time.Date(date.Year(), date.Month(), date.Day(), endClock.Hour(), endClock.Minute(), 0, 0, date.Location())
+	if !sched.end.After(sched.start) {
+		// Night shift: ends the next morning
+		sched.end = sched.end.AddDate(0, 0, 1)
+	}
+	sched.hours = shift.WorkHoursPerDay
+	if sched.hours <= 0 {
+		sched.hours = roundCurrency(sched.end.Sub(sched.start).Hours() - float64(shift.BreakMinutes)/60)
+	}
+	return sched
+}
+
+// shiftWorksOn reports whether a shift's WorkDays (0=Sunday) include the weekday
+func shiftWorksOn(shift *models.Shift, weekday time.Weekday) bool {
+	var workDays []int
+	if err := json.Unmarshal([]byte(shift.WorkDays), &workDays); err != nil || len(workDays) == 0 {
+		workDays = []int{1, 2, 3, 4, 5}
+	}
+	for _, d := range workDays {
+		if d == int(weekday) {
+			return true
+		}
+	}
+	return false
+}
+
+// loadPunches collects ClockRecords and NFC terminal records whose clock-in falls in [from, to)
+func (s *AttendanceEvaluationService) loadPunches(employeeID uuid.UUID, from, to time.Time) ([]punchPair, error) {
+	var records []models.ClockRecord
+	// Punches a supervisor rejected in the geofence review do not count
+	if err := s.db.Where("employee_id = ? AND clock_in_time >= ? AND clock_in_time < ?", employeeID, from, to).
+		Where("review_status IS NULL OR review_status <> ?", models.ClockReviewRejected).
+		Order("clock_in_time").Find(&records).Error; err != nil {
+		return nil, err
+	}
+	pairs := make([]punchPair, 0, len(records))
+	for _, r := range records {
+		source := "clock"
+		if r.ClockInSource == models.ClockSourceTerminal {
+			source = "terminal" // The shift break is deducted when no break was tapped
+		}
+		pairs = append(pairs, punchPair{in: r.ClockInTime, out: r.ClockOutTime, breakMinutes: float64(r.BreakMinutes), source: source})
+	}
+
+	// The terminal writes to its own table; it only exists where the terminal runs
+	if s.db.Migrator().HasTable(terminalAttendanceTable) {
+		var rows []terminalPunch
+		if err := s.db.Table(terminalAttendanceTable).Select("check_in, check_out").
+			Where("employee_id = ? AND check_in >= ? AND check_in < ?", employeeID, from, to).
+			Scan(&rows).Error; err != nil {
+			return nil, err
+		}
+		for _, r := range rows {
+			pairs = append(pairs, punchPair{in: r.CheckIn, out: r.CheckOut, source: "terminal"})
+		}
+	}
+	return pairs, nil
+}
+
+// loadJustifications returns, per date, the approved leave or IMSS incapacidad
+// that justifies an absence and the incidence categories (delay, overtime)
+// already captured by hand
+func (s *AttendanceEvaluationService) loadJustifications(employee *models.Employee, start, end time.Time) (map[string]string, map[string]map[string]string, error) {
+	leave := make(map[string]string)
+	coverage := make(map[string]map[string]string)
+	mark := func(from, to time.Time, fn func(key string)) {
+		from, to = attendanceDate(from), attendanceDate(to)
+		if from.Before(start) {
+			from = start
+		}
+		if to.After(end) {
+			to = end
+		}
+		for date := from; !date.After(to); date = date.AddDate(0, 0, 1) {
+			fn(date.Format(attendanceDateKey))
+		}
+	}
+
+	// AbsenceRequest.EmployeeID holds the requesting user's ID
+	var requests []models.AbsenceRequest
+	if err := s.db.Joins("JOIN users ON users.id = absence_requests.employee_id").
+		Where("users.employee_id = ? AND absence_requests.status = ? AND absence_requests.start_date <= ? AND absence_requests.end_date >= ?",
+			employee.ID, models.RequestStatusApproved, end, start).
+		Scopes(timeOffRequests).
+		Find(&requests).Error; err != nil {
+		return nil, nil, err
+	}
+	// Partial-day permits are windows of the shift (loadPermits), not leave
+	partial := make(map[uuid.UUID]bool)
+	for _, r := range requests {
+		if r.IsPartialDay() {
+			partial[r.ID] = true
+			continue
+		}
+		label := string(r.RequestType)
+		mark(r.StartDate, r.EndDate, func(key string) { leave[key] = label })
+	}
+
+	var certificates []models.SickLeaveCertificate
+	if err := s.db.Where("employee_id = ? AND status <> ? AND start_date <= ? AND end_date >= ?",
+		employee.ID, models.SickLeaveCancelled, end, start).
+		Find(&certificates).Error; err != nil {
+		return nil, nil, err
+	}
+	for i := range certificates {
+		// The days after the alta are not incapacidad
+		if taken := sickLeaveTakenDays(&certificates[i]); taken > 0 {
+			cert := certificates[i]
+			mark(cert.StartDate, cert.StartDate.AddDate(0, 0, taken-1), func(key string) {
+				leave[key] = string(models.RequestTypeSickLeave)
+			})
+		}
+	}
+
+	var incidences []models.Incidence
+	if err := s.db.Preload("IncidenceType").
+		Where("employee_id = ? AND status IN ? AND start_date <= ? AND end_date >= ?",
+			employee.ID, []string{"approved", "processed"}, end, start).
+		Find(&incidences).Error; err != nil {
+		return nil, nil, err
+	}
+	for _, inc := range incidences {
+		if inc.IncidenceType == nil || (inc.AbsenceRequestID != nil && partial[*inc.AbsenceRequestID]) {
+			continue
+		}
+		category, name := inc.IncidenceType.Category, inc.IncidenceType.Name
+		mark(inc.StartDate, inc.EndDate, func(key string) {
+			switch category {
+			case "delay", "overtime":
+				if coverage[key] == nil {
+					coverage[key] = make(map[string]string)
+				}
+				coverage[key][category] = name
+			case "absence", "sick", "vacation":
+				leave[key] = name
+			}
+		})
+	}
+	return leave, coverage, nil
+}
+
+// loadPermits returns, per date, the approved partial-day absence requests
+func (s *AttendanceEvaluationService) loadPermits(employee *models.Employee, start, end time.Time) (map[string][]models.AbsenceRequest, error) {
+	// AbsenceRequest.EmployeeID holds the requesting user's ID
+	var requests []models.AbsenceRequest
+	if err := s.db.Joins("JOIN users ON users.id = absence_requests.employee_id").
+		Where("users.employee_id = ? AND absence_requests.status = ? AND absence_requests.start_date <= ? AND absence_requests.end_date >= ?",
+			employee.ID, models.RequestStatusApproved, end, start).
+		Where("absence_requests.start_time IS NOT NULL AND absence_requests.start_time <> ''").
+		Scopes(timeOffRequests).
+		Find(&requests).Error; err != nil {
+		return nil, err
+	}
+	permits := make(map[string][]models.AbsenceRequest)
+	for _, r := range requests {
+		key := attendanceDate(r.StartDate).Format(attendanceDateKey)
+		permits[key] = append(permits[key], r)
+	}
+	return permits, nil
+}
+
+// loadOvertimeAuthorizations returns, per date, the overtime hours authorized
+// to be paid by approved OvertimeRequests (nil when the rules do not require
+// authorization) and the hours authorized to the time bank
+func (s *AttendanceEvaluationService) loadOvertimeAuthorizations(employee *models.Employee, start, end time.Time) (map[string]float64, map[string]float64, error) {
+	var requests []models.OvertimeRequest
+	if err := s.db.Where("employee_id = ? AND status IN ? AND request_date >= ? AND request_date < ?",
+		employee.ID, []string{models.OvertimeStatusApproved, models.OvertimeStatusCompleted}, start, end.AddDate(0, 0, 1)).
+		Find(&requests).Error; err != nil {
+		return nil, nil, err
+	}
+	var authorized map[string]float64
+	if s.rules.RequireOvertimeAuthorization {
+		authorized = make(map[string]float64)
+	}
+	banked := make(map[string]float64)
+	for _, r := range requests {
+		key := attendanceDate(r.RequestDate).Format(attendanceDateKey)
+		switch {
+		case r.Compensation == models.OvertimeCompensationBank:
+			banked[key] += r.AuthorizedHours
+		case authorized != nil:
+			authorized[key] += r.AuthorizedHours
+		}
+	}
+	return authorized, banked, nil
+}
+
+// Summarize totals evaluated days; overtime is split per ISO week into double and triple time
+func (s *AttendanceEvaluationService) Summarize(employeeID uuid.UUID, start, end time.Time, days []models.AttendanceDay) dtos.AttendanceSummary {
+	summary := dtos.AttendanceSummary{EmployeeID: employeeID, StartDate: start, EndDate: end}
+	weeklyOvertime := make(map[string]float64)
+	var weeks []string
+
+	for _, day := range days {
+		summary.EvaluatedDays++
+		if day.IsOverridden {
+			summary.OverriddenDays++
+		}
+		if day.ScheduleSource != models.ScheduleSourceNone || day.Worked() {
+			summary.HasAttendanceData = true
+		}
+		if day.ScheduledStart != nil {
+			summary.ScheduledDays++
+		}
+
+		switch day.Status {
+		case models.AttendanceStatusAbsent:
+			summary.AbsentDays++
+		case models.AttendanceStatusJustified:
+			summary.JustifiedDays++
+		case models.AttendanceStatusIncomplete:
+			summary.IncompleteDays++
+			summary.PresentDays++
+		case models.AttendanceStatusPresent, models.AttendanceStatusLate, models.AttendanceStatusUnscheduled:
+			if day.Worked() {
+				summary.PresentDays++
+			}
+		case models.AttendanceStatusWorkedRestDay, models.AttendanceStatusWorkedHoliday:
+			summary.PresentDays++
+			summary.RestDaysWorked++
+		}
+		if day.IsSunday && day.Worked() {
+			summary.SundaysWorked++
+		}
+		if day.LateMinutes > 0 {
+			summary.LateCount++
+			summary.LateMinutes += day.LateMinutes
+		}
+		if day.EarlyExitMinutes > 0 {
+			summary.EarlyExitCount++
+			summary.EarlyExitMinutes += day.EarlyExitMinutes
+		}
+		summary.WorkedHours += day.WorkedHours
+		if day.UnauthorizedOvertimeHours > 0 {
+			summary.UnauthorizedOvertimeDays++
+			summary.UnauthorizedOvertimeHours += day.UnauthorizedOvertimeHours
+		}
+		summary.BankedOvertimeHours += day.BankedOvertimeHours
+
+		if day.OvertimeHours > 0 {
+			year, week := day.WorkDate.ISOWeek()
+			key := fmt.Sprintf("%d-%02d", year, week)
+			if _, ok := weeklyOvertime[key]; !ok {
+				weeks = append(weeks, key)
+			}
+			weeklyOvertime[key] += day.OvertimeHours
+			summary.OvertimeHours += day.OvertimeHours
+		}
+	}
+
+	for _, week := range weeks {
+		hours := weeklyOvertime[week]
+		double := math.Min(hours, s.rules.WeeklyDoubleTimeHours)
+		summary.OvertimeDoubleHours += double
+		summary.OvertimeTripleHours += hours - double
+	}
+	summary.WorkedHours = roundCurrency(summary.WorkedHours)
+	summary.OvertimeHours = roundCurrency(summary.OvertimeHours)
+	summary.OvertimeDoubleHours = roundCurrency(summary.OvertimeDoubleHours)
+	summary.OvertimeTripleHours = roundCurrency(summary.OvertimeTripleHours)
+	summary.UnauthorizedOvertimeHours = roundCurrency(summary.UnauthorizedOvertimeHours)
+	summary.BankedOvertimeHours = roundCurrency(summary.BankedOvertimeHours)
+	return summary
+}
+
+// EvaluateCompany evaluates a period or date range for several employees
+func (s *AttendanceEvaluationService) EvaluateCompany(companyID uuid.UUID, req dtos.AttendanceEvaluateRequest) ([]dtos.AttendanceSummary, error) {
+	var start, end time.Time
+	switch {
+	case req.PayrollPeriodID != nil:
+		var err error
+		if start, end, err = s.PeriodRange(*req.PayrollPeriodID); err != nil {
+			return nil, err
+		}
+	case req.StartDate != nil && req.EndDate != nil:
+		start, end = req.StartDate.Time, req.EndDate.Time
+	default:
+		return nil, errors.New("payroll_period_id or start_date and end_date are required")
+	}
+
+	query := s.db.Where("company_id = ?", companyID)
+	if len(req.EmployeeIDs) > 0 {
+		query = query.Where("id IN ?", req.EmployeeIDs)
+	} else {
+		query = query.Where("employment_status IN ?", []string{"active", "on_leave"})
+	}
+	var employees []models.Employee
+	if err := query.Order("employee_number").Find(&employees).Error; err != nil {
+		return nil, err
+	}
+
+	summaries := make([]dtos.AttendanceSummary, 0, len(employees))
+	for i := range employees {
+		days, err := s.EvaluateRange(&employees[i], start, end, req.PayrollPeriodID)
+		if err != nil {
+			return summaries, fmt.Errorf("error evaluating employee %s: %w", employees[i].EmployeeNumber, err)
+		}
+		summaries = append(summaries, s.Summarize(employees[i].ID, attendanceDate(start), attendanceDate(end), days))
+	}
+	return summaries, nil
+}
+
+// PeriodRange returns the first and last date of a payroll period
+func (s *AttendanceEvaluationService) PeriodRange(periodID uuid.UUID) (time.Time, time.Time, error) {
+	var period models.PayrollPeriod
+	if err := s.db.First(&period, "id = ?", periodID).Error; err != nil {
+		return time.Time{}, time.Time{}, errors.New("payroll period not found")
+	}
+	return period.StartDate, period.EndDate, nil
+}
+
+// ListDays returns the stored days of an employee between two dates
+func (s *AttendanceEvaluationService) ListDays(companyID, employeeID uuid.UUID, start, end time.Time) ([]models.AttendanceDay, error) {
+	var days []models.AttendanceDay
+	err := s.db.Preload("Shift").
+		Where("company_id = ? AND employee_id = ? AND work_date BETWEEN ? AND ?", companyID, employeeID, attendanceDate(start), attendanceDate(end)).
+		Order("work_date").Find(&days).Error
+	return days, err
+}
+
+// OverrideDay applies an HR correction to one day; the evaluated values are kept in OriginalValues
+func (s *AttendanceEvaluationService) OverrideDay(companyID, dayID uuid.UUID, req dtos.AttendanceOverrideRequest, overriddenBy uuid.UUID) (*models.AttendanceDay, error) {
+	var day models.AttendanceDay
+	if err := s.db.First(&day, "id = ? AND company_id = ?", dayID, companyID).Error; err != nil {
+		return nil, errors.New("attendance day not found")
+	}
+	if req.ActualIn != nil && req.ActualOut != nil && !req.ActualOut.After(*req.ActualIn) {
+		return nil, errors.New("actual_out must be after actual_in")
+	}
+
+	if !day.IsOverridden {
+		day.OriginalValues = mustJSON(map[string]interface{}{
+			"status":                      day.Status,
+			"actual_in":                   day.ActualIn,
+			"actual_out":                  day.ActualOut,
+			"worked_hours":                day.WorkedHours,
+			"late_minutes":                day.LateMinutes,
+			"early_exit_minutes":          day.EarlyExitMinutes,
+			"overtime_hours":              day.OvertimeHours,
+			"unauthorized_overtime_hours": day.UnauthorizedOvertimeHours,
+			"justification":               day.Justification,
+		})
+	}
+
+	day.Status = req.Status
+	if req.ActualIn != nil {
+		day.ActualIn = req.ActualIn
+	}
+	if req.ActualOut != nil {
+		day.ActualOut = req.ActualOut
+	}
+	if day.ActualIn != nil && day.PunchCount == 0 {
+		day.PunchCount = 1
+	}
+	switch {
+	case req.WorkedHours != nil:
+		day.WorkedHours = *req.WorkedHours
+	case req.ActualIn != nil || req.ActualOut != nil:
+		if day.ActualIn != nil && day.ActualOut != nil {
+			day.WorkedHours = roundCurrency(day.ActualOut.Sub(*day.ActualIn).Hours())
+		}
+	}
+	if req.LateMinutes != nil {
+		day.LateMinutes = *req.LateMinutes
+	}
+	if req.EarlyExitMinutes != nil {
+		day.EarlyExitMinutes = *req.EarlyExitMinutes
+	}
+	if req.OvertimeHours != nil {
+		// HR decided how much overtime is paid; nothing is left for review
+		day.OvertimeHours = *req.OvertimeHours
+		day.UnauthorizedOvertimeHours = 0
+	}
+	if req.Justification != "" {
+		day.Justification = req.Justification
+	}
+	// Statuses without attendance carry no delay, exit or overtime
+	switch day.Status {
+	case models.AttendanceStatusAbsent, models.AttendanceStatusJustified, models.AttendanceStatusRestDay, models.AttendanceStatusHoliday:
+		day.LateMinutes, day.EarlyExitMinutes, day.OvertimeHours = 0, 0, 0
+		day.UnauthorizedOvertimeHours = 0
+	}
+
+	now := time.Now()
+	day.IsOverridden = true
+	day.OverrideReason = req.Reason
+	day.OverriddenBy = &overriddenBy
+	day.OverriddenAt = &now
+	if err := s.db.Save(&day).Error; err != nil {
+		return nil, err
+	}
+	return &day, nil
+}
+
+// ClearOverride drops an HR correction and evaluates the day again
+func (s *AttendanceEvaluationService) ClearOverride(companyID, dayID uuid.UUID) (*models.AttendanceDay, error) {
+	var day models.AttendanceDay
+	if err := s.db.First(&day, "id = ? AND company_id = ?", dayID, companyID).Error; err != nil {
+		return nil, errors.New("attendance day not found")
+	}
+	var employee models.Employee
+	if err := s.db.First(&employee, "id = ?", day.EmployeeID).Error; err != nil {
+		return nil, errors.New("employee not found")
+	}
+
+	if err := s.db.Model(&day).UpdateColumn("is_overridden", false).Error; err != nil {
+		return nil, err
+	}
+	days, err := s.EvaluateRange(&employee, day.WorkDate, day.WorkDate, day.PayrollPeriodID)
+	if err != nil {
+		return nil, err
+	}
+	return &days[0], nil
+}
+
+// attendanceDate normalises a date to local midnight so keys and stored dates agree
+func attendanceDate(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.Local)
+}
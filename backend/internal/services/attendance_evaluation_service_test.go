@@ -0,0 +1,127 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"backend/internal/config"
+	config_payroll "backend/internal/config/payroll"
+	"backend/internal/dtos"
+	"backend/internal/models"
+)
+
+func TestAttendanceEvaluation_PunchesAgainstShifts(t *testing.T) {
+	db := setupPayrollTestDB(t)
+	require.NoError(t, db.AutoMigrate(
+		&models.User{}, &models.CostCenter{}, &models.AbsenceRequest{}, &models.IncidenceType{}, &models.Incidence{}, &models.SickLeaveCertificate{},
+		&models.Holiday{}, &models.Shift{}, &models.EmployeeShiftBase{}, &models.ShiftException{},
+		&models.ClockRecord{}, &models.AttendanceDay{}, &models.RotationPattern{}, &models.RotationPatternDay{}, &models.RotationAssignment{},
+		&models.Timesheet{}, &models.OvertimeRequest{},
+	))
+	company := createPayrollTestCompany(t, db)
+	employee := createFixtureEmployee(t, db, company.ID, 1, "general", 400)
+	user := createFixtureUser(t, db, employee, "employee")
+	period := createPayrollTestPeriod(t, db, "weekly") // Mon 2025-01-06 .. Sun 2025-01-12
+
+	dayShift := &models.Shift{Name: "Matutino", Code: "MAT", StartTime: "09:00", EndTime: "18:00",
+		BreakMinutes: 60, WorkHoursPerDay: 8, WorkDays: "[1,2,3,4,5]", CompanyID: company.ID, IsActive: true}
+	nightShift := &models.Shift{Name: "Nocturno", Code: "NOC", StartTime: "22:00", EndTime: "06:00",
+		BreakMinutes: 30, WorkHoursPerDay: 7, WorkDays: "[1,2,3,4,5]", CompanyID: company.ID, IsActive: true, IsNightShift: true}
+	require.NoError(t, db.Create(dayShift).Error)
+	require.NoError(t, db.Create(nightShift).Error)
+	require.NoError(t, db.Model(employee).UpdateColumn("shift_id", dayShift.ID).Error)
+	employee.ShiftID = &dayShift.ID
+
+	at := func(day, hour, minute int) time.Time {
+		return time.Date(2025, 1, day, hour, minute, 0, 0, time.Local)
+	}
+	punch := func(in, out time.Time) {
+		require.NoError(t, db.Create(&models.ClockRecord{CompanyID: company.ID, EmployeeID: employee.ID,
+			ClockInTime: in, ClockOutTime: &out, ClockInSource: "web", IsComplete: true}).Error)
+	}
+
+	// Wednesday is swapped to the night shift, ending Thursday morning
+	require.NoError(t, db.Create(&models.ShiftException{EmployeeID: employee.ID, Date: at(8, 0, 0),
+		ShiftID: nightShift.ID, CreatedByID: user.ID}).Error)
+	// Thursday is approved vacation
+	require.NoError(t, db.Create(&models.AbsenceRequest{EmployeeID: user.ID, RequestType: models.RequestTypeVacation,
+		StartDate: at(9, 0, 0), EndDate: at(9, 0, 0), TotalDays: 1, Status: models.RequestStatusApproved}).Error)
+
+	punch(at(6, 9, 5), at(6, 17, 30))   // Mon: within tolerance, leaves 30 min early
+	punch(at(7, 9, 25), at(7, 18, 45))  // Tue: 25 min late, 45 min extra -> 0.5h overtime
+	punch(at(8, 21, 55), at(9, 6, 40))  // Wed night shift, out Thursday 06:40 -> 0.5h overtime
+	punch(at(12, 10, 0), at(12, 14, 0)) // Sun: rest day worked
+	// Fri: no punches and nothing justifies it; Sat: rest day
+
+	service := NewAttendanceEvaluationService(db, nil)
+	summary, err := service.EvaluatePeriod(employee, period)
+	require.NoError(t, err)
+
+	days, err := service.ListDays(company.ID, employee.ID, period.StartDate, period.EndDate)
+	require.NoError(t, err)
+	require.Len(t, days, 7)
+	statuses := make([]string, len(days))
+	for i, day := range days {
+		statuses[i] = day.Status
+	}
+	assert.Equal(t, []string{
+		models.AttendanceStatusPresent, models.AttendanceStatusLate, models.AttendanceStatusPresent,
+		models.AttendanceStatusJustified, models.AttendanceStatusAbsent, models.AttendanceStatusRestDay,
+		models.AttendanceStatusWorkedRestDay,
+	}, statuses)
+	assert.Equal(t, models.ScheduleSourceException, days[2].ScheduleSource)
+	assert.Equal(t, 8.75, days[2].WorkedHours) // 21:55 to 06:40, no break recorded
+	assert.Equal(t, string(models.RequestTypeVacation), days[3].Justification)
+
+	assert.Equal(t, 1, summary.AbsentDays)
+	assert.Equal(t, 1, summary.JustifiedDays)
+	assert.Equal(t, 1, summary.LateCount)
+	assert.Equal(t, 25.0, summary.LateMinutes)
+	assert.Equal(t, 1, summary.EarlyExitCount)
+	assert.Equal(t, 30.0, summary.EarlyExitMinutes)
+	assert.Equal(t, 1.0, summary.OvertimeDoubleHours)
+	assert.Equal(t, 0.0, summary.OvertimeTripleHours)
+	assert.Equal(t, 1, summary.SundaysWorked)
+	assert.Equal(t, 1, summary.RestDaysWorked)
+
+	// HR justifies Friday; recalculating keeps the override
+	friday := days[4]
+	overridden, err := service.OverrideDay(company.ID, friday.ID, dtos.AttendanceOverrideRequest{
+		Status: models.AttendanceStatusJustified, Justification: "Permiso con goce", Reason: "Acta firmada",
+	}, user.ID)
+	require.NoError(t, err)
+	assert.True(t, overridden.IsOverridden)
+	assert.Contains(t, string(overridden.OriginalValues), `"status":"absent"`)
+
+	summary, err = service.EvaluatePeriod(employee, period)
+	require.NoError(t, err)
+	assert.Equal(t, 0, summary.AbsentDays)
+	assert.Equal(t, 1, summary.OverriddenDays)
+
+	cleared, err := service.ClearOverride(company.ID, friday.ID)
+	require.NoError(t, err)
+	assert.False(t, cleared.IsOverridden)
+	assert.Equal(t, models.AttendanceStatusAbsent, cleared.Status)
+
+	// The prenómina takes the evaluation and stays the same when recalculated
+	payrollConfig := config_payroll.NewPayrollConfig()
+	payrollConfig.LaborConcepts.Overtime.DoubleTimePercentage = 1
+	payrollConfig.LaborConcepts.SundayPremium.Percentage = 0.25
+	prenomina := NewPrenominaService(db, &config.AppConfig{PayrollConfig: payrollConfig})
+	for i := 0; i < 2; i++ {
+		result, err := prenomina.CalculatePrenomina(employee.ID, period.ID, false, uuid.New())
+		require.NoError(t, err)
+		assert.Equal(t, 1.0, result.AbsenceDays)
+		assert.Equal(t, 6.0, result.WorkedDays)
+		assert.Equal(t, 1, result.DelaysCount)
+		assert.Equal(t, 1, result.EarlyDeparturesCount)
+		assert.Equal(t, 1.0, result.OvertimeHours)
+		assert.Equal(t, 1, result.SundaysWorked)
+		assert.InDelta(t, 100.0, result.SundayPremiumAmount, 0.001) // 400 * 25%
+		assert.InDelta(t, 800.0, result.RestDayWorkedAmount, 0.001) // 400 * 2
+	}
+}
@@ -0,0 +1,433 @@
+/*
+Package services - Attendance Terminal Service
+
+==============================================================================
+FILE: internal/services/attendance_terminal_service.go
+==============================================================================
+
+DESCRIPTION:
+    Receives card swipes from the NFC attendance terminals and turns them
+    into ClockRecords. Each swipe is logged once in terminal_swipes keyed by
+    the terminal-generated SwipeID, so a terminal can resend a batch after a
+    network failure without creating duplicate punches. The punch type
+    (entrada, inicio/fin de descanso, salida) is inferred from the open
+    ClockRecord and the employee's scheduled shift, and every punch is
+    attributed to the workday of the shift it belongs to. Also enrolls,
+    lists and deactivates attendance cards.
+
+USER PERSPECTIVE:
+    - First tap of the shift clocks the employee in
+    - A tap around the shift's meal time starts the break, the next tap
+      ends it; any other tap while working clocks out
+    - Split shifts: after a clock-out, the next tap clocks in again
+    - Tapping twice by accident is ignored, not a clock-out
+      (SwipeDebounceSeconds in labor_concepts.json)
+    - Unknown or deactivated cards are rejected and shown on the terminal
+    - Swipes buffered offline keep their original time when they sync
+
+DEVELOPER GUIDELINES:
+    ✅  OK to modify: Break window, open-shift span, rejection rules
+    ⚠️  CAUTION: A resent SwipeID must return the stored result unchanged;
+        never re-evaluate it (the clock record may have moved on)
+    ⚠️  CAUTION: Swipes in a batch are applied oldest first, whatever the
+        request order; results come back in request order
+    📝  Open ClockRecords older than terminalMaxShiftSpan are left for HR to
+        fix; the next tap starts a new clock-in instead of closing them
+    📝  Shift times are read in the company's time zone (Company.Timezone)
+
+SYNTAX EXPLANATION:
+    - Direction "in": new ClockRecord with ClockInSource "terminal" and
+      ClockInLocation = terminal ID
+    - Direction "break_start"/"break_end": BreakStart/BreakEnd on the open
+      ClockRecord; BreakMinutes accumulates the break taken
+    - Direction "out": closes the open ClockRecord with ClockOutSource
+      "terminal"; WorkedHours = span - BreakMinutes, then the employee's
+      TimePolicy (rounding, break deduction, overtime split), as
+      TimeTrackingService
+    - Break window: BreakStartTime ± terminalBreakSlack when the shift sets
+      it, otherwise the middle half of the shift; the break must fit before
+      the shift ends
+    - A break not ended within terminalMaxBreak was really the exit: the
+      record is closed at the break start and the tap clocks in again
+    - Workday: a punch within PunchWindowHours of yesterday's shift (a
+      night shift's exit) belongs to yesterday; otherwise to today
+
+==============================================================================
+*/
+package services
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"backend/internal/config"
+	"backend/internal/dtos"
+	"backend/internal/models"
+)
+
+const (
+	// terminalMaxShiftSpan is the longest clock-in a tap can still close
+	terminalMaxShiftSpan = 16 * time.Hour
+	// terminalMaxBreak is the longest break a tap can still end
+	terminalMaxBreak = 4 * time.Hour
+	// terminalBreakSlack is how far from the shift's BreakStartTime a tap still starts the break
+	terminalBreakSlack = time.Hour
+)
+
+// AttendanceTerminalService handles NFC terminal swipes and card enrollment
+type AttendanceTerminalService struct {
+	db         *gorm.DB
+	evaluation *AttendanceEvaluationService
+	policies   *TimePolicyService
+}
+
+// NewAttendanceTerminalService creates a new attendance terminal service
+func NewAttendanceTerminalService(db *gorm.DB, appConfig *config.AppConfig) *AttendanceTerminalService {
+	return &AttendanceTerminalService{db: db, evaluation: NewAttendanceEvaluationService(db, appConfig), policies: NewTimePolicyService(db)}
+}
+
+// NormalizeCardUID returns the card UID as stored: upper-case hex without separators
+func NormalizeCardUID(uid string) string {
+	replacer := strings.NewReplacer(":", "", "-", "", " ", "")
+	return strings.ToUpper(replacer.Replace(strings.TrimSpace(uid)))
+}
+
+// RecordSwipes applies a batch of swipes from one terminal
+func (s *AttendanceTerminalService) RecordSwipes(companyID uuid.UUID, req dtos.TerminalSwipeBatchRequest) ([]dtos.TerminalSwipeResult, error) {
+	terminalID := strings.TrimSpace(req.TerminalID)
+	if terminalID == "" {
+		return nil, errors.New("terminal_id is required")
+	}
+
+	order := make([]int, len(req.Swipes))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(a, b int) bool {
+		return req.Swipes[order[a]].SwipedAt.Before(req.Swipes[order[b]].SwipedAt)
+	})
+
+	var company models.Company
+	if err := s.db.Select("id", "timezone").Where("id = ?", companyID).First(&company).Error; err != nil {
+		return nil, fmt.Errorf("company not found: %w", err)
+	}
+	loc := company.Location()
+
+	results := make([]dtos.TerminalSwipeResult, len(req.Swipes))
+	for _, i := range order {
+		result, err := s.recordSwipe(companyID, terminalID, req.Swipes[i], loc)
+		if err != nil {
+			return nil, fmt.Errorf("swipe %s: %w", req.Swipes[i].SwipeID, err)
+		}
+		results[i] = *result
+	}
+	return results, nil
+}
+
+// recordSwipe logs one swipe and, when accepted, applies it to the employee's ClockRecords
+func (s *AttendanceTerminalService) recordSwipe(companyID uuid.UUID, terminalID string, swipe dtos.TerminalSwipeRequest, loc *time.Location) (*dtos.TerminalSwipeResult, error) {
+	var existing models.TerminalSwipe
+	err := s.db.Preload("Employee").Where("swipe_id = ?", swipe.SwipeID).First(&existing).Error
+	if err == nil {
+		if existing.CompanyID != companyID {
+			return nil, errors.New("swipe_id already exists for another company")
+		}
+		result := terminalSwipeResult(&existing)
+		result.Duplicate = true
+		return result, nil
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+
+	swipedAt := swipe.SwipedAt.In(time.Local)
+	debounce := time.Duration(s.evaluation.Rules().SwipeDebounceSeconds) * time.Second
+	record := models.TerminalSwipe{
+		CompanyID:  companyID,
+		SwipeID:    swipe.SwipeID,
+		TerminalID: terminalID,
+		CardUID:    NormalizeCardUID(swipe.CardUID),
+		SwipedAt:   swipedAt,
+		ReceivedAt: time.Now(),
+		Offline:    swipe.Offline,
+	}
+
+	err = s.db.Transaction(func(tx *gorm.DB) error {
+		var card models.AttendanceCard
+		err := tx.Preload("Employee").
+			Where("company_id = ? AND card_uid = ? AND is_active = ?", companyID, record.CardUID, true).
+			First(&card).Error
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			record.Status, record.Message = models.SwipeStatusRejected, "card not enrolled"
+			return tx.Omit("Employee").Create(&record).Error
+		}
+		if err != nil {
+			return err
+		}
+		record.EmployeeID, record.Employee = &card.EmployeeID, card.Employee
+		if card.Employee == nil || card.Employee.EmploymentStatus != "active" {
+			record.Status, record.Message = models.SwipeStatusRejected, "employee is not active"
+			return tx.Omit("Employee").Create(&record).Error
+		}
+
+		var repeated int64
+		if err := tx.Model(&models.TerminalSwipe{}).
+			Where("employee_id = ? AND status = ? AND swiped_at > ? AND swiped_at < ?",
+				card.EmployeeID, models.SwipeStatusAccepted, swipedAt.Add(-debounce), swipedAt.Add(debounce)).
+			Count(&repeated).Error; err != nil {
+			return err
+		}
+		if repeated > 0 {
+			record.Status, record.Message = models.SwipeStatusIgnored, "repeated tap"
+			return tx.Omit("Employee").Create(&record).Error
+		}
+
+		clock, direction, workDate, err := s.punch(tx, &card, terminalID, swipedAt, loc)
+		if err != nil {
+			return err
+		}
+		record.Status, record.Direction, record.ClockRecordID = models.SwipeStatusAccepted, direction, &clock.ID
+		record.WorkDate = &workDate
+		return tx.Omit("Employee").Create(&record).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return terminalSwipeResult(&record), nil
+}
+
+// punch applies a tap to the employee's open ClockRecord (break start/end or
+// clock-out) or opens a new one, and returns the workday it belongs to
+func (s *AttendanceTerminalService) punch(tx *gorm.DB, card *models.AttendanceCard, terminalID string, at time.Time, loc *time.Location) (*models.ClockRecord, string, time.Time, error) {
+	var open models.ClockRecord
+	err := tx.Where("employee_id = ? AND is_complete = ? AND clock_in_time < ? AND clock_in_time >= ?",
+		card.EmployeeID, false, at, at.Add(-terminalMaxShiftSpan)).
+		Order("clock_in_time DESC").First(&open).Error
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, "", time.Time{}, err
+	}
+
+	if err == nil {
+		workDate, sched, err := s.workday(tx, card.Employee, open.ClockInTime, loc)
+		if err != nil {
+			return nil, "", time.Time{}, err
+		}
+		onBreak := open.BreakStart != nil && open.BreakEnd == nil
+		direction := models.SwipeDirectionOut
+		switch {
+		case onBreak && at.Sub(*open.BreakStart) <= terminalMaxBreak:
+			open.BreakEnd = &at
+			open.BreakMinutes += int(at.Sub(*open.BreakStart).Minutes())
+			direction = models.SwipeDirectionBreakEnd
+		case onBreak:
+			// Never came back: the break start was the exit; this tap opens a new record below
+			closeTerminalClock(&open, *open.BreakStart, terminalID)
+			open.BreakStart = nil
+			open.ClockOutNotes = "Salida tomada del inicio de descanso sin regreso"
+			direction = ""
+		case open.BreakStart == nil && inBreakWindow(sched, at):
+			open.BreakStart = &at
+			direction = models.SwipeDirectionBreakStart
+		default:
+			closeTerminalClock(&open, at, terminalID)
+		}
+		if err := tx.Save(&open).Error; err != nil {
+			return nil, "", time.Time{}, err
+		}
+		if open.IsComplete {
+			if err := s.policies.withDB(tx).ApplyToClockRecord(&open); err != nil {
+				return nil, "", time.Time{}, err
+			}
+		}
+		if direction != "" {
+			return &open, direction, workDate, nil
+		}
+	}
+
+	workDate, _, err := s.workday(tx, card.Employee, at, loc)
+	if err != nil {
+		return nil, "", time.Time{}, err
+	}
+	clock := models.ClockRecord{
+		CompanyID:       card.CompanyID,
+		EmployeeID:      card.EmployeeID,
+		ClockInTime:     at,
+		ClockInSource:   models.ClockSourceTerminal,
+		ClockInLocation: terminalID,
+		Status:          "active",
+	}
+	if err := tx.Create(&clock).Error; err != nil {
+		return nil, "", time.Time{}, err
+	}
+	return &clock, models.SwipeDirectionIn, workDate, nil
+}
+
+// workday returns the workday (company-zone midnight) a punch at the given
+// time belongs to, with that day's schedule
+func (s *AttendanceTerminalService) workday(tx *gorm.DB, employee *models.Employee, at time.Time, loc *time.Location) (time.Time, daySchedule, error) {
+	local := at.In(loc)
+	today := time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, loc)
+	yesterday := today.AddDate(0, 0, -1)
+	schedule, err := s.evaluation.withDB(tx).loadSchedules(employee, yesterday, today)
+	if err != nil {
+		return time.Time{}, daySchedule{}, err
+	}
+
+	window := time.Duration(s.evaluation.Rules().PunchWindowHours * float64(time.Hour))
+	if sched := schedule(yesterday); !sched.start.IsZero() &&
+		!local.Before(sched.start.Add(-window)) && local.Before(sched.end.Add(window)) {
+		return yesterday, sched, nil
+	}
+	return today, schedule(today), nil
+}
+
+// inBreakWindow reports whether a tap while working starts the shift's meal break
+func inBreakWindow(sched daySchedule, at time.Time) bool {
+	shift := sched.shift
+	if shift == nil || shift.BreakMinutes <= 0 || sched.start.IsZero() {
+		return false
+	}
+	length := time.Duration(shift.BreakMinutes) * time.Minute
+	if !at.Add(length).Before(sched.end) {
+		return false // No time left to come back
+	}
+	if planned, err := time.Parse("15:04", shift.BreakStartTime); err == nil {
+		start := time.Date(sched.start.Year(), sched.start.Month(), sched.start.Day(),
+			planned.Hour(), planned.Minute(), 0, 0, sched.start.Location())
+		if start.Before(sched.start) {
+			start = start.AddDate(0, 0, 1) // Night shift: the break is after midnight
+		}
+		return !at.Before(start.Add(-terminalBreakSlack)) && !at.After(start.Add(terminalBreakSlack))
+	}
+	quarter := sched.end.Sub(sched.start) / 4
+	return at.After(sched.start.Add(quarter)) && at.Before(sched.end.Add(-quarter))
+}
+
+// closeTerminalClock clocks out an open record from a terminal
+func closeTerminalClock(clock *models.ClockRecord, at time.Time, terminalID string) {
+	clock.ClockOutTime = &at
+	clock.ClockOutSource = models.ClockSourceTerminal
+	clock.ClockOutLocation = terminalID
+	clock.IsComplete = true
+	clock.Status = "completed"
+	clock.WorkedHours = at.Sub(clock.ClockInTime).Hours() - float64(clock.BreakMinutes)/60
+}
+
+// terminalSwipeResult builds the response for a logged swipe
+func terminalSwipeResult(swipe *models.TerminalSwipe) *dtos.TerminalSwipeResult {
+	result := &dtos.TerminalSwipeResult{
+		SwipeID:       swipe.SwipeID,
+		Status:        swipe.Status,
+		Direction:     swipe.Direction,
+		EmployeeID:    swipe.EmployeeID,
+		WorkDate:      swipe.WorkDate,
+		ClockRecordID: swipe.ClockRecordID,
+		Message:       swipe.Message,
+		SwipedAt:      swipe.SwipedAt,
+	}
+	if swipe.Employee != nil {
+		result.EmployeeName = strings.TrimSpace(swipe.Employee.FirstName + " " + swipe.Employee.LastName)
+		result.EmployeeNumber = swipe.Employee.EmployeeNumber
+	}
+	return result
+}
+
+// EnrollCard assigns a card to an employee, reactivating it if it was deactivated
+func (s *AttendanceTerminalService) EnrollCard(companyID uuid.UUID, req dtos.AttendanceCardRequest, enrolledBy uuid.UUID) (*models.AttendanceCard, error) {
+	uid := NormalizeCardUID(req.CardUID)
+	if uid == "" {
+		return nil, errors.New("card_uid is required")
+	}
+
+	var employee models.Employee
+	query := s.db.Where("company_id = ?", companyID)
+	switch {
+	case req.EmployeeID != nil:
+		query = query.Where("id = ?", *req.EmployeeID)
+	case strings.TrimSpace(req.EmployeeNumber) != "":
+		query = query.Where("employee_number = ?", strings.TrimSpace(req.EmployeeNumber))
+	default:
+		return nil, errors.New("employee_id or employee_number is required")
+	}
+	if err := query.First(&employee).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("employee not found")
+		}
+		return nil, err
+	}
+	if employee.EmploymentStatus != "active" {
+		return nil, errors.New("employee must be active to enroll a card")
+	}
+
+	var card models.AttendanceCard
+	err := s.db.Where("card_uid = ?", uid).First(&card).Error
+	switch {
+	case err == nil:
+		if card.CompanyID != companyID || (card.IsActive && card.EmployeeID != employee.ID) {
+			return nil, fmt.Errorf("card %s already exists for another employee; deactivate it first", uid)
+		}
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		card = models.AttendanceCard{CompanyID: companyID, CardUID: uid}
+	default:
+		return nil, err
+	}
+
+	card.EmployeeID = employee.ID
+	card.IsActive = true
+	card.TerminalID = strings.TrimSpace(req.TerminalID)
+	card.EnrolledBy = &enrolledBy
+	card.EnrolledAt = time.Now()
+	if err := s.db.Save(&card).Error; err != nil {
+		return nil, err
+	}
+	card.Employee = &employee
+	return &card, nil
+}
+
+// ListCards lists the company's cards, optionally for one employee
+func (s *AttendanceTerminalService) ListCards(companyID uuid.UUID, employeeID *uuid.UUID, includeInactive bool) ([]models.AttendanceCard, error) {
+	query := s.db.Preload("Employee").Where("company_id = ?", companyID)
+	if employeeID != nil {
+		query = query.Where("employee_id = ?", *employeeID)
+	}
+	if !includeInactive {
+		query = query.Where("is_active = ?", true)
+	}
+
+	var cards []models.AttendanceCard
+	if err := query.Order("enrolled_at DESC").Find(&cards).Error; err != nil {
+		return nil, err
+	}
+	return cards, nil
+}
+
+// DeactivateCard stops a card from clocking (lost card, employee left)
+func (s *AttendanceTerminalService) DeactivateCard(companyID, id uuid.UUID) (*models.AttendanceCard, error) {
+	var card models.AttendanceCard
+	if err := s.db.Where("id = ? AND company_id = ?", id, companyID).First(&card).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("card not found")
+		}
+		return nil, err
+	}
+	if err := s.db.Model(&card).Update("is_active", false).Error; err != nil {
+		return nil, err
+	}
+	card.IsActive = false
+	return &card, nil
+}
+
+// ListPunches returns an employee's accepted punches for workdays in [start, end], in order
+func (s *AttendanceTerminalService) ListPunches(companyID, employeeID uuid.UUID, start, end time.Time) ([]models.TerminalSwipe, error) {
+	var punches []models.TerminalSwipe
+	err := s.db.Where("company_id = ? AND employee_id = ? AND status = ? AND work_date >= ? AND work_date < ?",
+		companyID, employeeID, models.SwipeStatusAccepted, truncateToDate(start), truncateToDate(end).AddDate(0, 0, 1)).
+		Order("swiped_at").Find(&punches).Error
+	return punches, err
+}
@@ -0,0 +1,154 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"backend/internal/dtos"
+	"backend/internal/models"
+)
+
+func TestAttendanceTerminal_IdempotentSwipes(t *testing.T) {
+	db := setupPayrollTestDB(t)
+	require.NoError(t, db.AutoMigrate(&models.User{}, &models.ClockRecord{}, &models.AttendanceCard{}, &models.TerminalSwipe{},
+		&models.Shift{}, &models.EmployeeShiftBase{}, &models.ShiftException{}, &models.TimePolicy{}, &models.TimePolicyViolation{},
+		&models.RotationPattern{}, &models.RotationPatternDay{}, &models.RotationAssignment{}))
+	company := createPayrollTestCompany(t, db)
+	employee := createFixtureEmployee(t, db, company.ID, 1, "general", 400)
+	hr := createFixtureUser(t, db, employee, "hr")
+
+	service := NewAttendanceTerminalService(db, nil)
+	card, err := service.EnrollCard(company.ID, dtos.AttendanceCardRequest{CardUID: "04:a1:b2:c3", EmployeeNumber: employee.EmployeeNumber}, hr.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "04A1B2C3", card.CardUID)
+
+	other := createFixtureEmployee(t, db, company.ID, 2, "general", 400)
+	_, err = service.EnrollCard(company.ID, dtos.AttendanceCardRequest{CardUID: "04A1B2C3", EmployeeID: &other.ID}, hr.ID)
+	assert.ErrorContains(t, err, "already exists")
+
+	at := func(hour, minute, second int) time.Time {
+		return time.Date(2025, 1, 6, hour, minute, second, 0, time.Local)
+	}
+	in := dtos.TerminalSwipeRequest{SwipeID: uuid.New(), CardUID: "04a1b2c3", SwipedAt: at(9, 0, 0)}
+	repeat := dtos.TerminalSwipeRequest{SwipeID: uuid.New(), CardUID: "04A1B2C3", SwipedAt: at(9, 0, 20)}
+	out := dtos.TerminalSwipeRequest{SwipeID: uuid.New(), CardUID: "04A1B2C3", SwipedAt: at(18, 0, 0), Offline: true}
+	unknown := dtos.TerminalSwipeRequest{SwipeID: uuid.New(), CardUID: "FFFFFFFF", SwipedAt: at(9, 5, 0)}
+
+	// Out of order on purpose: swipes are applied oldest first
+	batch := dtos.TerminalSwipeBatchRequest{TerminalID: "LOBBY-1", Swipes: []dtos.TerminalSwipeRequest{out, in, repeat, unknown}}
+	results, err := service.RecordSwipes(company.ID, batch)
+	require.NoError(t, err)
+	require.Len(t, results, 4)
+	assert.Equal(t, models.SwipeDirectionOut, results[0].Direction)
+	assert.Equal(t, models.SwipeDirectionIn, results[1].Direction)
+	assert.Equal(t, employee.EmployeeNumber, results[1].EmployeeNumber)
+	assert.Equal(t, models.SwipeStatusIgnored, results[2].Status)
+	assert.Equal(t, models.SwipeStatusRejected, results[3].Status)
+
+	var clocks []models.ClockRecord
+	require.NoError(t, db.Where("employee_id = ?", employee.ID).Find(&clocks).Error)
+	require.Len(t, clocks, 1)
+	assert.True(t, clocks[0].IsComplete)
+	assert.Equal(t, models.ClockSourceTerminal, clocks[0].ClockInSource)
+	assert.Equal(t, "LOBBY-1", clocks[0].ClockInLocation)
+	assert.Equal(t, 8.5, clocks[0].WorkedHours) // 9h with no break: 30 min deducted by the time policy
+	assert.Equal(t, 30, clocks[0].AutoBreakMinutes)
+
+	// The terminal resends the same batch after a timeout: nothing changes
+	results, err = service.RecordSwipes(company.ID, batch)
+	require.NoError(t, err)
+	for _, result := range results {
+		assert.True(t, result.Duplicate)
+	}
+	assert.Equal(t, models.SwipeDirectionOut, results[0].Direction)
+	var count int64
+	require.NoError(t, db.Model(&models.ClockRecord{}).Count(&count).Error)
+	assert.Equal(t, int64(1), count)
+	require.NoError(t, db.Model(&models.TerminalSwipe{}).Count(&count).Error)
+	assert.Equal(t, int64(4), count)
+
+	// A deactivated card is rejected
+	_, err = service.DeactivateCard(company.ID, card.ID)
+	require.NoError(t, err)
+	results, err = service.RecordSwipes(company.ID, dtos.TerminalSwipeBatchRequest{TerminalID: "LOBBY-1",
+		Swipes: []dtos.TerminalSwipeRequest{{SwipeID: uuid.New(), CardUID: "04A1B2C3", SwipedAt: at(20, 0, 0)}}})
+	require.NoError(t, err)
+	assert.Equal(t, models.SwipeStatusRejected, results[0].Status)
+}
+
+func TestAttendanceTerminal_BreaksSplitShiftsAndNightShifts(t *testing.T) {
+	db := setupPayrollTestDB(t)
+	require.NoError(t, db.AutoMigrate(&models.User{}, &models.ClockRecord{}, &models.AttendanceCard{}, &models.TerminalSwipe{},
+		&models.Shift{}, &models.EmployeeShiftBase{}, &models.ShiftException{}, &models.TimePolicy{}, &models.TimePolicyViolation{},
+		&models.RotationPattern{}, &models.RotationPatternDay{}, &models.RotationAssignment{}))
+	company := createPayrollTestCompany(t, db)
+	require.NoError(t, db.Model(company).UpdateColumn("timezone", "America/Mexico_City").Error)
+	employee := createFixtureEmployee(t, db, company.ID, 1, "general", 400)
+	hr := createFixtureUser(t, db, employee, "hr")
+
+	dayShift := &models.Shift{Name: "Matutino", Code: "MAT", StartTime: "09:00", EndTime: "18:00", BreakMinutes: 60,
+		BreakStartTime: "14:00", WorkHoursPerDay: 8, WorkDays: "[1,2,3,4,5]", CompanyID: company.ID, IsActive: true}
+	nightShift := &models.Shift{Name: "Nocturno", Code: "NOC", StartTime: "22:00", EndTime: "06:00", BreakMinutes: 30,
+		WorkHoursPerDay: 7, WorkDays: "[1,2,3,4,5]", CompanyID: company.ID, IsActive: true, IsNightShift: true}
+	require.NoError(t, db.Create(dayShift).Error)
+	require.NoError(t, db.Create(nightShift).Error)
+	require.NoError(t, db.Model(employee).UpdateColumn("shift_id", dayShift.ID).Error)
+	require.NoError(t, db.Create(&models.ShiftException{EmployeeID: employee.ID, Date: time.Date(2025, 1, 8, 0, 0, 0, 0, time.Local),
+		ShiftID: nightShift.ID, CreatedByID: hr.ID}).Error)
+
+	service := NewAttendanceTerminalService(db, nil)
+	_, err := service.EnrollCard(company.ID, dtos.AttendanceCardRequest{CardUID: "0A0B0C0D", EmployeeID: &employee.ID}, hr.ID)
+	require.NoError(t, err)
+
+	// Times are wall-clock in Mexico City; the terminal sends them in UTC
+	mexico, err := time.LoadLocation("America/Mexico_City")
+	require.NoError(t, err)
+	tap := func(day, hour, minute int) dtos.TerminalSwipeResult {
+		at := time.Date(2025, 1, day, hour, minute, 0, 0, mexico).UTC()
+		results, err := service.RecordSwipes(company.ID, dtos.TerminalSwipeBatchRequest{TerminalID: "PLANTA",
+			Swipes: []dtos.TerminalSwipeRequest{{SwipeID: uuid.New(), CardUID: "0A0B0C0D", SwipedAt: at}}})
+		require.NoError(t, err)
+		return results[0]
+	}
+	directions := func(results ...dtos.TerminalSwipeResult) []string {
+		out := make([]string, len(results))
+		for i, r := range results {
+			out[i] = r.Direction
+		}
+		return out
+	}
+
+	// Monday: meal break around 14:00; the 18:05 exit is already Tuesday in UTC
+	monday := []dtos.TerminalSwipeResult{tap(6, 8, 55), tap(6, 14, 2), tap(6, 14, 58), tap(6, 18, 5)}
+	assert.Equal(t, []string{models.SwipeDirectionIn, models.SwipeDirectionBreakStart, models.SwipeDirectionBreakEnd,
+		models.SwipeDirectionOut}, directions(monday...))
+	for _, r := range monday {
+		assert.Equal(t, "2025-01-06", r.WorkDate.Format("2006-01-02"))
+	}
+	var clock models.ClockRecord
+	require.NoError(t, db.Where("id = ?", *monday[0].ClockRecordID).First(&clock).Error)
+	assert.Equal(t, 56, clock.BreakMinutes)
+	assert.Equal(t, 8.23, clock.WorkedHours) // 9h10m less the 56-minute break
+
+	// Tuesday: split shift, leaving at noon is not the meal break
+	tuesday := []dtos.TerminalSwipeResult{tap(7, 9, 0), tap(7, 12, 0), tap(7, 16, 0), tap(7, 19, 0)}
+	assert.Equal(t, []string{models.SwipeDirectionIn, models.SwipeDirectionOut, models.SwipeDirectionIn,
+		models.SwipeDirectionOut}, directions(tuesday...))
+
+	// Wednesday night shift: the Thursday 06:10 exit belongs to Wednesday
+	wednesday := []dtos.TerminalSwipeResult{tap(8, 21, 50), tap(9, 6, 10)}
+	assert.Equal(t, []string{models.SwipeDirectionIn, models.SwipeDirectionOut}, directions(wednesday...))
+	assert.Equal(t, "2025-01-08", wednesday[1].WorkDate.Format("2006-01-02"))
+
+	var count int64
+	require.NoError(t, db.Model(&models.ClockRecord{}).Where("employee_id = ?", employee.ID).Count(&count).Error)
+	assert.Equal(t, int64(4), count)
+
+	punches, err := service.ListPunches(company.ID, employee.ID, time.Date(2025, 1, 8, 0, 0, 0, 0, time.UTC), time.Date(2025, 1, 8, 0, 0, 0, 0, time.UTC))
+	require.NoError(t, err)
+	assert.Len(t, punches, 2)
+}
@@ -8,7 +8,8 @@ FILE: internal/services/calendar_service.go
 DESCRIPTION:
     Aggregates calendar events from multiple sources (AbsenceRequests, Incidences,
     ShiftExceptions) into a unified calendar view for HR. Supports filtering by
-    collar type, employee, event type, and date range.
+    collar type, employee, department, manager's team, event type, and date
+    range.
 
 USER PERSPECTIVE:
     - HR users see all employee events on one calendar
@@ -16,6 +17,7 @@ USER PERSPECTIVE:
     - Can filter by collar type (white/blue/gray)
     - Can filter by specific employees
     - Can filter by event type (absence, incidence, shift)
+    - Supervisors and managers only see their own team (org hierarchy)
 
 DEVELOPER GUIDELINES:
     ✅  OK to modify: Add new event sources, filters
@@ -41,12 +43,23 @@ import (
 
 // CalendarService handles calendar event aggregation
 type CalendarService struct {
-	db *gorm.DB
+	db           *gorm.DB
+	orgStructure *OrgStructureService
 }
 
 // NewCalendarService creates a new CalendarService
 func NewCalendarService(db *gorm.DB) *CalendarService {
-	return &CalendarService{db: db}
+	return &CalendarService{db: db, orgStructure: NewOrgStructureService(db)}
+}
+
+// ManagerScopeForUser returns the employee ID whose team a supervisor/manager user
+// may see on the calendar ("" when the user has no employee record)
+func (s *CalendarService) ManagerScopeForUser(userID uuid.UUID) string {
+	var user models.User
+	if err := s.db.Select("id, employee_id").First(&user, "id = ?", userID).Error; err != nil || user.EmployeeID == nil {
+		return ""
+	}
+	return user.EmployeeID.String()
 }
 
 // GetCalendarEvents aggregates events from all sources within the date range
@@ -62,7 +75,7 @@ func (s *CalendarService) GetCalendarEvents(req dtos.CalendarEventRequest) (*dto
 	}
 
 	// Get filtered employee IDs based on collar types and explicit employee filter
-	employeeIDs, err := s.getFilteredEmployeeIDs(req.EmployeeIDs, req.CollarTypes, req.DepartmentID)
+	employeeIDs, err := s.getFilteredEmployeeIDs(req.EmployeeIDs, req.CollarTypes, req.DepartmentID, req.ManagerID)
 	if err != nil {
 		return nil, err
 	}
@@ -116,10 +129,19 @@ func (s *CalendarService) GetCalendarEvents(req dtos.CalendarEventRequest) (*dto
 }
 
 // GetEmployeesForCalendar returns employees with auto-assigned colors
-func (s *CalendarService) GetEmployeesForCalendar(collarTypes []string, departmentID string) (*dtos.CalendarEmployeesResponse, error) {
+func (s *CalendarService) GetEmployeesForCalendar(collarTypes []string, departmentID string, managerID string) (*dtos.CalendarEmployeesResponse, error) {
 	query := s.db.Model(&models.Employee{}).
 		Where("employment_status = ?", "active")
 
+	// Filter by manager's team
+	if managerID != "" {
+		teamIDs, err := s.teamEmployeeIDs(managerID)
+		if err != nil {
+			return nil, err
+		}
+		query = query.Where("id IN ?", teamIDs)
+	}
+
 	// Filter by collar types
 	if len(collarTypes) > 0 {
 		query = query.Where("collar_type IN ?", collarTypes)
@@ -164,23 +186,42 @@ func (s *CalendarService) GetEmployeesForCalendar(collarTypes []string, departme
 }
 
 // getFilteredEmployeeIDs returns employee IDs based on filters
-func (s *CalendarService) getFilteredEmployeeIDs(employeeIDs []string, collarTypes []string, departmentID string) ([]uuid.UUID, error) {
-	// If specific employee IDs provided, use them directly
+func (s *CalendarService) getFilteredEmployeeIDs(employeeIDs []string, collarTypes []string, departmentID string, managerID string) ([]uuid.UUID, error) {
+	var teamIDs []uuid.UUID
+	if managerID != "" {
+		var err error
+		if teamIDs, err = s.teamEmployeeIDs(managerID); err != nil {
+			return nil, err
+		}
+	}
+
+	// If specific employee IDs provided, use them directly (within the team, if scoped)
 	if len(employeeIDs) > 0 {
+		inTeam := make(map[uuid.UUID]bool, len(teamIDs))
+		for _, id := range teamIDs {
+			inTeam[id] = true
+		}
 		result := make([]uuid.UUID, 0, len(employeeIDs))
 		for _, idStr := range employeeIDs {
-			if id, err := uuid.Parse(idStr); err == nil {
+			if id, err := uuid.Parse(idStr); err == nil && (managerID == "" || inTeam[id]) {
 				result = append(result, id)
 			}
 		}
-		return result, nil
+		if managerID != "" && len(result) == 0 {
+			result = append(result, uuid.Nil) // nothing visible; avoid "no filter"
+		}
+		return s.withPortalUserIDs(result)
 	}
 
-	// Otherwise, query based on collar types and department
+	// Otherwise, query based on collar types, department and team
 	query := s.db.Model(&models.Employee{}).
 		Where("employment_status = ?", "active").
 		Select("id")
 
+	if managerID != "" {
+		query = query.Where("id IN ?", teamIDs)
+	}
+
 	if len(collarTypes) > 0 {
 		query = query.Where("collar_type IN ?", collarTypes)
 	}
@@ -195,10 +236,39 @@ func (s *CalendarService) getFilteredEmployeeIDs(employeeIDs []string, collarTyp
 	if err := query.Find(&ids).Error; err != nil {
 		return nil, err
 	}
+	if managerID != "" {
+		if len(ids) == 0 {
+			ids = append(ids, uuid.Nil) // empty team; avoid "no filter"
+		}
+		return s.withPortalUserIDs(ids)
+	}
 
 	return ids, nil
 }
 
+// teamEmployeeIDs returns the manager and everyone below them in the org hierarchy
+func (s *CalendarService) teamEmployeeIDs(managerID string) ([]uuid.UUID, error) {
+	managerUUID, err := uuid.Parse(managerID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid manager_id: %v", err)
+	}
+	ids, err := s.orgStructure.SubordinateIDs(managerUUID, false)
+	if err != nil {
+		return nil, err
+	}
+	return append(ids, managerUUID), nil
+}
+
+// withPortalUserIDs adds the portal user IDs of the employees, since absence
+// requests are keyed by user ID
+func (s *CalendarService) withPortalUserIDs(employeeIDs []uuid.UUID) ([]uuid.UUID, error) {
+	var userIDs []uuid.UUID
+	if err := s.db.Model(&models.User{}).Where("employee_id IN ?", employeeIDs).Pluck("id", &userIDs).Error; err != nil {
+		return nil, err
+	}
+	return append(employeeIDs, userIDs...), nil
+}
+
 // shouldFetchEventType determines if an event type should be fetched
 func (s *CalendarService) shouldFetchEventType(eventTypes []string, eventType string) bool {
 	if len(eventTypes) == 0 {
@@ -216,7 +286,8 @@ func (s *CalendarService) shouldFetchEventType(eventTypes []string, eventType st
 func (s *CalendarService) fetchAbsenceRequests(startDate, endDate time.Time, employeeIDs []uuid.UUID, status string) ([]dtos.CalendarEventResponse, error) {
 	query := s.db.Model(&models.AbsenceRequest{}).
 		Preload("Employee").
-		Where("(start_date <= ? AND end_date >= ?)", endDate, startDate) // Overlapping date range
+		Where("(start_date <= ? AND end_date >= ?)", endDate, startDate). // Overlapping date range
+		Scopes(timeOffRequests)
 
 	if len(employeeIDs) > 0 {
 		query = query.Where("employee_id IN ?", employeeIDs)
@@ -28,6 +28,8 @@ SYNTAX EXPLANATION:
     - Sello: Digital signature created with company's private key
     - Cadena Original: String to sign generated via SAT XSLT transformation
     - PeriodicidadPago: SAT codes (01=daily, 02=weekly, 04=biweekly, 05=monthly)
+    - Incapacidades: one node per c_TipoIncapacidad (01 riesgo de trabajo,
+      02 enfermedad general, 03 maternidad) from SickLeaveService
 
 ==============================================================================
 */
@@ -65,9 +67,13 @@ func NewCfdiService(certPath, keyPath, keyPass string) *CfdiService {
 	}
 }
 
-// GenerateCfdiXML creates a CFDI 4.0 XML for a given payroll calculation.
-func (s *CfdiService) GenerateCfdiXML(payroll *models.PayrollCalculation) ([]byte, error) {
+// GenerateCfdiXML creates a CFDI 4.0 XML for a given payroll calculation,
+// with the employee's IMSS incapacidades of the period.
+func (s *CfdiService) GenerateCfdiXML(payroll *models.PayrollCalculation, incapacidades []*models.Incapacidad) ([]byte, error) {
 	comprobante := s.buildComprobante(payroll)
+	if len(incapacidades) > 0 {
+		comprobante.Complemento.Nomina.Incapacidades = &models.Incapacidades{Incapacidad: incapacidades}
+	}
 
 	// In a real implementation, you would perform the following steps:
 	// 1. Generate the "cadena original" (original string) from the comprobante.
@@ -51,6 +51,7 @@ import (
     "github.com/xuri/excelize/v2"
     "gorm.io/gorm"
 
+    "backend/internal/config"
     "backend/internal/dtos"
     "backend/internal/models"
     "backend/internal/models/enums"
@@ -62,14 +63,20 @@ import (
 type EmployeeService struct {
     employeeRepo *repositories.EmployeeRepository
     userRepo     *repositories.UserRepository
+    minimumWage  *MinimumWageService
+    organization *OrganizationService
+    orgStructure *OrgStructureService
     db           *gorm.DB
 }
 
 // NewEmployeeService creates a new employee service
-func NewEmployeeService(db *gorm.DB) *EmployeeService {
+func NewEmployeeService(db *gorm.DB, appConfig *config.AppConfig) *EmployeeService {
     return &EmployeeService{
         employeeRepo: repositories.NewEmployeeRepository(db),
         userRepo:     repositories.NewUserRepository(db),
+        minimumWage:  NewMinimumWageService(db, appConfig),
+        organization: NewOrganizationService(db),
+        orgStructure: NewOrgStructureService(db),
         db:           db,
     }
 }
@@ -150,6 +157,8 @@ func (s *EmployeeService) CreateEmployee(req dtos.EmployeeRequest, createdBy uui
         IsSindicalizado:  req.IsSindicalizado,
         DailySalary:      req.DailySalary,
         IntegratedDailySalary: req.IntegratedDailySalary,
+        MinimumWageZone:  req.MinimumWageZone,
+        ProfessionalOccupation: strings.TrimSpace(req.ProfessionalOccupation),
         PaymentMethod:    req.PaymentMethod,
         BankName:         strings.TrimSpace(req.BankName),
         BankAccount:      strings.TrimSpace(req.BankAccount),
@@ -171,10 +180,19 @@ func (s *EmployeeService) CreateEmployee(req dtos.EmployeeRequest, createdBy uui
     if err := employee.Validate(); err != nil {
         return nil, fmt.Errorf("employee validation failed: %w", err)
     }
+    if err := s.minimumWage.ValidateEmployee(employee); err != nil {
+        return nil, err
+    }
     
-    // Create employee
-    if err := s.employeeRepo.Create(employee); err != nil {
-        return nil, fmt.Errorf("failed to create employee: %w", err)
+    // Create employee and place it in the reporting hierarchy
+    err = s.db.Transaction(func(tx *gorm.DB) error {
+        if err := repositories.NewEmployeeRepository(tx).Create(employee); err != nil {
+            return fmt.Errorf("failed to create employee: %w", err)
+        }
+        return s.orgStructure.SyncEmployeeManager(tx, employee, &createdBy)
+    })
+    if err != nil {
+        return nil, err
     }
     
     return employee, nil
@@ -240,6 +258,12 @@ func (s *EmployeeService) UpdateEmployee(id uuid.UUID, req dtos.EmployeeRequest,
         updateImssRegDate = req.IMSSRegistrationDate.Time
     }
 
+    oldSalary := employee.DailySalary
+    oldSDI := employee.IntegratedDailySalary
+    oldDepartmentID := employee.DepartmentID
+    oldPositionID := employee.PositionID
+    oldSupervisorID := employee.SupervisorID
+
     // Update employee fields
     employee.FirstName = strings.TrimSpace(req.FirstName)
     employee.LastName = strings.TrimSpace(req.LastName)
@@ -272,6 +296,10 @@ func (s *EmployeeService) UpdateEmployee(id uuid.UUID, req dtos.EmployeeRequest,
     employee.IsSindicalizado = req.IsSindicalizado
     employee.DailySalary = req.DailySalary
     employee.IntegratedDailySalary = req.IntegratedDailySalary
+    if req.MinimumWageZone != "" {
+        employee.MinimumWageZone = req.MinimumWageZone
+    }
+    employee.ProfessionalOccupation = strings.TrimSpace(req.ProfessionalOccupation)
     employee.PaymentMethod = req.PaymentMethod
     employee.BankName = strings.TrimSpace(req.BankName)
     employee.BankAccount = strings.TrimSpace(req.BankAccount)
@@ -292,10 +320,40 @@ func (s *EmployeeService) UpdateEmployee(id uuid.UUID, req dtos.EmployeeRequest,
     if err := employee.Validate(); err != nil {
         return nil, fmt.Errorf("employee validation failed: %w", err)
     }
+    if err := s.minimumWage.ValidateEmployee(employee); err != nil {
+        return nil, err
+    }
+    salaryChanged := roundCurrency(oldSalary) != roundCurrency(employee.DailySalary)
+    assignmentChanged := !sameUUID(oldDepartmentID, employee.DepartmentID) || !sameUUID(oldPositionID, employee.PositionID)
+    if salaryChanged || assignmentChanged {
+        if _, err := s.organization.CheckPayBand(employee, employee.DailySalary); err != nil {
+            return nil, err
+        }
+    }
     
-    // Update employee
-    if err := s.employeeRepo.Update(employee); err != nil {
-        return nil, fmt.Errorf("failed to update employee: %w", err)
+    // Update employee (and keep the salary and assignment audit trail)
+    err = s.db.Transaction(func(tx *gorm.DB) error {
+        if err := repositories.NewEmployeeRepository(tx).Update(employee); err != nil {
+            return fmt.Errorf("failed to update employee: %w", err)
+        }
+        if assignmentChanged {
+            if err := recordAssignmentChange(tx, employee, "Actualización de datos del empleado", &updatedBy); err != nil {
+                return err
+            }
+        }
+        if !sameUUID(oldSupervisorID, employee.SupervisorID) {
+            if err := s.orgStructure.SyncEmployeeManager(tx, employee, &updatedBy); err != nil {
+                return err
+            }
+        }
+        if !salaryChanged {
+            return nil
+        }
+        _, _, err := recordSalaryChange(tx, employee, oldSalary, oldSDI, time.Now(), "Actualización de datos del empleado", &updatedBy)
+        return err
+    })
+    if err != nil {
+        return nil, err
     }
     
     return s.ConvertToResponse(employee), nil
@@ -395,8 +453,8 @@ func (s *EmployeeService) ListEmployees(page, pageSize int, filters map[string]i
                 // Supervisors see only their direct reports
                 filters["supervisor_id"] = *user.EmployeeID
             } else if userRole == "manager" {
-                // Managers see their direct reports + all subordinates recursively
-                subordinateIDs := s.getAllSubordinateIDs(*user.EmployeeID)
+                // Managers see their direct reports + all subordinates (hierarchy closure table)
+                subordinateIDs, _ := s.orgStructure.SubordinateIDs(*user.EmployeeID, false)
                 if len(subordinateIDs) > 0 {
                     filters["subordinate_ids"] = subordinateIDs
                 } else {
@@ -432,27 +490,6 @@ func (s *EmployeeService) ListEmployees(page, pageSize int, filters map[string]i
     }, nil
 }
 
-// getAllSubordinateIDs recursively gets all subordinate employee IDs under a supervisor
-func (s *EmployeeService) getAllSubordinateIDs(supervisorID uuid.UUID) []uuid.UUID {
-    var subordinateIDs []uuid.UUID
-    var directReports []models.Employee
-
-    // Get direct reports
-    if err := s.db.Where("supervisor_id = ?", supervisorID).Find(&directReports).Error; err != nil {
-        return subordinateIDs
-    }
-
-    // Add direct report IDs and recursively get their subordinates
-    for _, emp := range directReports {
-        subordinateIDs = append(subordinateIDs, emp.ID)
-        // Recursively get subordinates of this employee
-        subSubordinates := s.getAllSubordinateIDs(emp.ID)
-        subordinateIDs = append(subordinateIDs, subSubordinates...)
-    }
-
-    return subordinateIDs
-}
-
 // TerminateEmployee terminates an employee
 func (s *EmployeeService) TerminateEmployee(id uuid.UUID, req dtos.EmployeeTerminationRequest) error {
     employee, err := s.employeeRepo.FindByID(id)
@@ -484,33 +521,38 @@ func (s *EmployeeService) TerminateEmployee(id uuid.UUID, req dtos.EmployeeTermi
     return s.employeeRepo.Update(employee)
 }
 
-// UpdateEmployeeSalary updates employee salary
-func (s *EmployeeService) UpdateEmployeeSalary(id uuid.UUID, req dtos.EmployeeSalaryUpdateRequest, updatedBy uuid.UUID) error {
+// UpdateEmployeeSalary updates employee salary. The returned pay band check
+// (nil when the position has no grade) carries a warning when the new salary
+// is outside the band.
+func (s *EmployeeService) UpdateEmployeeSalary(id uuid.UUID, req dtos.EmployeeSalaryUpdateRequest, updatedBy uuid.UUID) (*dtos.PayBandCheck, error) {
     employee, err := s.employeeRepo.FindByID(id)
     if err != nil {
         if errors.Is(err, gorm.ErrRecordNotFound) {
-            return errors.New("employee not found")
+            return nil, errors.New("employee not found")
         }
-        return fmt.Errorf("error fetching employee: %w", err)
+        return nil, fmt.Errorf("error fetching employee: %w", err)
     }
 
     // Validate new salary is positive
     if req.NewDailySalary <= 0 {
-        return errors.New("daily salary must be positive")
+        return nil, errors.New("daily salary must be positive")
+    }
+
+    // Validate salary is not below the employee's minimum wage (zone / professional)
+    if err := s.minimumWage.ValidateDailySalary(employee.MinimumWageZone, employee.ProfessionalOccupation, req.NewDailySalary); err != nil {
+        return nil, err
     }
 
-    // Validate salary is not unrealistically low (below Mexican minimum wage)
-    // As of 2025, minimum daily wage is approximately 248.93 MXN
-    const minDailySalary = 200.0 // Set slightly below to allow flexibility
-    if req.NewDailySalary < minDailySalary {
-        return fmt.Errorf("daily salary (%.2f) is below minimum wage threshold (%.2f MXN)",
-            req.NewDailySalary, minDailySalary)
+    // Compare with the position's pay band (blocks only when the grade says so)
+    bandCheck, err := s.organization.CheckPayBand(employee, req.NewDailySalary)
+    if err != nil {
+        return bandCheck, err
     }
 
     // Validate salary is not unrealistically high (prevent data entry errors)
     const maxDailySalary = 50000.0 // ~1.5M MXN monthly
     if req.NewDailySalary > maxDailySalary {
-        return fmt.Errorf("daily salary (%.2f) exceeds maximum threshold (%.2f MXN) - verify amount",
+        return nil, fmt.Errorf("daily salary (%.2f) exceeds maximum threshold (%.2f MXN) - verify amount",
             req.NewDailySalary, maxDailySalary)
     }
 
@@ -532,29 +574,40 @@ func (s *EmployeeService) UpdateEmployeeSalary(id uuid.UUID, req dtos.EmployeeSa
 
     if err == nil {
         if req.EffectiveDate.Before(latestClosedPeriod.EndDate) {
-            return fmt.Errorf("effective date (%s) cannot be before last closed payroll period end date (%s)",
+            return nil, fmt.Errorf("effective date (%s) cannot be before last closed payroll period end date (%s)",
                 req.EffectiveDate.Format("2006-01-02"),
                 latestClosedPeriod.EndDate.Format("2006-01-02"))
         }
     }
 
+    oldSalary := employee.DailySalary
+    oldSDI := employee.IntegratedDailySalary
+
     // Update employee salary
     employee.DailySalary = req.NewDailySalary
     employee.UpdatedBy = &updatedBy
 
-    // Recalculate integrated daily salary
+    // Recalculate integrated daily salary (reset so it is derived from the new salary)
+    employee.IntegratedDailySalary = 0
     employee.IntegratedDailySalary = employee.CalculateIntegratedDailySalary()
 
-    // TODO: Create salary history record for audit trail
-    // salaryHistory := &models.SalaryHistory{
-    //     EmployeeID: employee.ID,
-    //     OldSalary: employee.DailySalary,
-    //     NewSalary: req.NewDailySalary,
-    //     EffectiveDate: req.EffectiveDate,
-    //     UpdatedBy: updatedBy,
-    // }
+    reason := strings.TrimSpace(req.Reason)
+    if reason == "" {
+        reason = "Cambio de salario"
+    }
 
-    return s.employeeRepo.Update(employee)
+    // Salary history + IMSS salary modification for the audit trail
+    err = s.db.Transaction(func(tx *gorm.DB) error {
+        if err := repositories.NewEmployeeRepository(tx).Update(employee); err != nil {
+            return err
+        }
+        _, _, err := recordSalaryChange(tx, employee, oldSalary, oldSDI, req.EffectiveDate.ToTime(), reason, &updatedBy)
+        return err
+    })
+    if err != nil {
+        return nil, err
+    }
+    return bandCheck, nil
 }
 
 // GetActiveEmployees returns active employees
@@ -680,6 +733,8 @@ func (s *EmployeeService) ConvertToResponse(employee *models.Employee) *dtos.Emp
         IsSindicalizado:       employee.IsSindicalizado,
         DailySalary:           employee.DailySalary,
         IntegratedDailySalary: employee.IntegratedDailySalary,
+        MinimumWageZone:       employee.MinimumWageZone,
+        ProfessionalOccupation: employee.ProfessionalOccupation,
         InfonavitCredit:       employee.InfonavitCredit,
         PersonalEmail:         employee.PersonalEmail,
         PersonalPhone:         employee.PersonalPhone,
@@ -783,6 +838,9 @@ func (s *EmployeeService) ImportEmployeesFromFile(file multipart.File, filename
 
     for rowNum, row := range records[1:] {
         emp, err := s.rowToEmployee(row, headerMap, userID)
+        if err == nil {
+            err = s.minimumWage.ValidateEmployee(emp)
+        }
         if err != nil {
             errList := result["errors"].([]map[string]interface{})
             errList = append(errList, map[string]interface{}{
@@ -848,6 +906,7 @@ func (s *EmployeeService) ImportEmployeesFromFile(file multipart.File, filename
                 result["failed"] = result["failed"].(int) + 1
                 continue
             }
+            ensureNode(s.db, emp.CompanyID, emp.ID)
             result["created"] = result["created"].(int) + 1
         }
     }
@@ -1014,6 +1073,8 @@ func (s *EmployeeService) rowToEmployee(row []string, headerMap map[string]int,
         BankAccount:      strings.ReplaceAll(getValue("bank_account"), " ", ""),
         CLABE:            strings.ReplaceAll(getValue("clabe"), " ", ""),
         PaymentMethod:    normalizePaymentMethod(getValue("payment_method")),
+        MinimumWageZone:  normalizeMinimumWageZone(getValue("minimum_wage_zone")),
+        ProfessionalOccupation: getValue("professional_occupation"),
         CompanyID:        user.CompanyID,
         CreatedBy:        &userID,
     }
@@ -1033,6 +1094,27 @@ func (s *EmployeeService) rowToEmployee(row []string, headerMap map[string]int,
     return emp, nil
 }
 
+// sameUUID reports whether two optional IDs are equal
+func sameUUID(a, b *uuid.UUID) bool {
+    if a == nil || b == nil {
+        return a == nil && b == nil
+    }
+    return *a == *b
+}
+
+// normalizeMinimumWageZone maps import values (e.g. "ZLFN", "Frontera") to a minimum wage zone
+func normalizeMinimumWageZone(zone string) string {
+    z := strings.ToLower(strings.TrimSpace(zone))
+    switch {
+    case z == "":
+        return models.MinimumWageZoneGeneral
+    case z == "zlfn" || strings.Contains(z, "frontera") || strings.Contains(z, "border"):
+        return models.MinimumWageZoneNorthernBorderFreeZone
+    default:
+        return models.MinimumWageZoneGeneral
+    }
+}
+
 // normalizeName converts name to title case (first letter uppercase, rest lowercase)
 func normalizeName(name string) string {
     if name == "" {
@@ -1308,6 +1390,7 @@ func (s *EmployeeService) GenerateImportTemplate() ([]byte, error) {
         "hire_date", "daily_salary", "collar_type", "pay_frequency",
         "employment_status", "employee_type", "is_sindicalizado",
         "bank_name", "bank_account", "clabe", "payment_method",
+        "minimum_wage_zone", "professional_occupation",
     }
 
     // Set headers
@@ -1331,6 +1414,7 @@ func (s *EmployeeService) GenerateImportTemplate() ([]byte, error) {
         "2024-01-15", "500.00", "white_collar", "biweekly",
         "active", "permanent", "false",
         "BBVA", "1234567890", "012180001234567890", "bank_transfer",
+        "general", "",
     }
 
     for i, value := range exampleData {
@@ -1362,6 +1446,8 @@ func (s *EmployeeService) GenerateImportTemplate() ([]byte, error) {
         {"bank_account", "No", "Bank account number", "Text"},
         {"clabe", "No", "CLABE (18 digits)", "Text"},
         {"payment_method", "No", "Payment method", "bank_transfer, cash, check"},
+        {"minimum_wage_zone", "No", "Minimum wage zone (default general)", "general, northern_border_free_zone (ZLFN)"},
+        {"professional_occupation", "No", "CONASAMI professional minimum wage code", "Text"},
     }
 
     for i, row := range instructions {
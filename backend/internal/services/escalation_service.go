@@ -6,13 +6,16 @@ FILE: internal/services/escalation_service.go
 ==============================================================================
 
 DESCRIPTION:
-    Handles automatic escalation of pending absence requests after 24 hours.
-    This service is called by a scheduled worker (cron job) to find requests
-    that have been pending for more than 24 hours and automatically advance
-    them to the next approval stage in the workflow.
+    Handles automatic escalation of pending absence requests once their
+    current stage has waited past its SLA, and the reminders sent to the
+    approvers shortly before that happens. The in-process scheduler
+    (SchedulerService) runs both every hour as the "approval_escalation"
+    and "approval_reminders" jobs.
 
 USER PERSPECTIVE:
-    - If a manager doesn't approve within 24 hours, request auto-escalates
+    - If a manager doesn't approve within the stage SLA (24 hours unless HR
+      changes it in the approval workflow), request auto-escalates
+    - A few hours before that, the approvers get a reminder notification
     - Escalated requests notify the next level approver
     - Employees see escalation status in their request history
 
@@ -31,12 +34,24 @@ WORKFLOW STAGES:
     6. approved (Final state)
 
 ESCALATION LOGIC:
-    - Query: Find all requests where status = 'pending_*' AND last_action_at < NOW() - 24 hours
+    - Query: Find all requests where status = 'pending_*'; a request escalates once
+      NOW() - last_action_at reaches the SLA of its current stage
+    - SLA and reminder lead come from the step's EscalationHours/ReminderHours;
+      requests created before workflows existed use the same stage of the
+      company's default workflow, else 24 hours / 4 hours
+    - Requests following an ApprovalWorkflow use the timeout of their current
+      step (EscalationHours, 0 = never) and move to the next step of their
+      route; the last step never escalates
     - Determine next stage based on current stage and employee collar type
     - Update absence_request: status, escalation_count, is_escalated, last_action_at
     - Create escalation_log entry
     - Send notification to next approver(s)
 
+REMINDER LOGIC:
+    - Window: last_action_at + SLA - ReminderHours <= NOW() < last_action_at + SLA
+    - Sent once per stage: reminder_sent_at earlier than last_action_at means
+      the reminder belongs to a previous stage
+
 ==============================================================================
 */
 package services
@@ -53,37 +68,44 @@ import (
 
 // EscalationService handles automatic escalation of pending absence requests
 type EscalationService struct {
-	db *gorm.DB
+	db        *gorm.DB
+	workflows *ApprovalWorkflowService
 }
 
 // NewEscalationService creates a new escalation service instance
 func NewEscalationService(db *gorm.DB) *EscalationService {
-	return &EscalationService{db: db}
+	return &EscalationService{db: db, workflows: NewApprovalWorkflowService(db)}
 }
 
-// ProcessPendingEscalations finds and processes all requests pending for 24+ hours
-// This is called by the scheduled worker (hourly cron job)
-func (s *EscalationService) ProcessPendingEscalations() error {
-	log.Println("Starting escalation processing...")
+// defaultEscalationHours and defaultReminderHours apply to requests whose
+// stage has no SLA in any workflow (e.g. blue collar requests created at
+// SUPERVISOR before workflows existed)
+const (
+	defaultEscalationHours = 24
+	defaultReminderHours   = 4
+)
 
-	// Find all absence requests pending for more than 24 hours
-	cutoffTime := time.Now().Add(-24 * time.Hour)
+// ProcessPendingEscalations finds and processes all requests pending past their SLA
+// This is called by the scheduler ("approval_escalation" job, hourly)
+func (s *EscalationService) ProcessPendingEscalations() error {
+	_, err := s.EscalateDue(time.Now())
+	return err
+}
 
-	var pendingRequests []models.AbsenceRequest
-	err := s.db.
-		Where("status = ?", models.RequestStatusPending).
-		Where("current_approval_stage != ?", models.ApprovalStageCompleted).
-		Where("last_action_at < ?", cutoffTime).
-		Find(&pendingRequests).Error
+// EscalateDue escalates every pending request whose current stage has waited
+// past its SLA and returns how many were escalated
+func (s *EscalationService) EscalateDue(now time.Time) (int, error) {
+	log.Println("Starting escalation processing...")
 
+	pendingRequests, err := s.pendingRequests()
 	if err != nil {
 		log.Printf("Error querying pending requests: %v", err)
-		return err
+		return 0, err
 	}
 
-	log.Printf("Found %d requests to escalate", len(pendingRequests))
+	log.Printf("Found %d pending requests to check", len(pendingRequests))
 
-	// Process each request
+	escalated := 0
 	for i := range pendingRequests {
 		request := &pendingRequests[i]
 
@@ -107,19 +129,128 @@ func (s *EscalationService) ProcessPendingEscalations() error {
 
 		request.Employee = &emp
 
-		if err := s.escalateRequest(request); err != nil {
+		if request.WorkflowID != nil {
+			moved, err := s.escalateWorkflowRequest(request, now)
+			if err != nil {
+				log.Printf("Error escalating request %s: %v", request.ID, err)
+			} else if moved {
+				escalated++
+			}
+			continue
+		}
+
+		hours, _ := s.stageSLA(request)
+		if hours == 0 || now.Sub(request.LastActionAt) < time.Duration(hours)*time.Hour {
+			continue
+		}
+		if err := s.escalateRequest(request, hours); err != nil {
 			log.Printf("Error escalating request %s: %v", request.ID, err)
 			// Continue with other requests even if one fails
 			continue
 		}
+		escalated++
 	}
 
 	log.Println("Escalation processing completed")
-	return nil
+	return escalated, nil
+}
+
+// SendSLAReminders notifies the approvers of every pending request whose
+// current stage enters its reminder window (ReminderHours before the SLA runs
+// out), once per stage, and returns how many requests were reminded
+func (s *EscalationService) SendSLAReminders(now time.Time) (int, error) {
+	pendingRequests, err := s.pendingRequests()
+	if err != nil {
+		return 0, err
+	}
+
+	reminded := 0
+	for i := range pendingRequests {
+		request := &pendingRequests[i]
+		if request.ReminderSentAt != nil && !request.ReminderSentAt.Before(request.LastActionAt) {
+			continue
+		}
+		hours, reminderHours := s.stageSLA(request)
+		if hours == 0 || reminderHours == 0 {
+			continue
+		}
+		deadline := request.LastActionAt.Add(time.Duration(hours) * time.Hour)
+		if now.Before(deadline.Add(-time.Duration(reminderHours)*time.Hour)) || !now.Before(deadline) {
+			continue
+		}
+
+		approverIDs := s.workflows.StageApproverIDs(request)
+		if len(approverIDs) == 0 {
+			continue
+		}
+		if err := s.remindApprovers(request, approverIDs, deadline, now); err != nil {
+			log.Printf("Error reminding approvers of request %s: %v", request.ID, err)
+			continue
+		}
+		reminded++
+	}
+	return reminded, nil
+}
+
+// pendingRequests returns the requests still waiting for an approval stage
+func (s *EscalationService) pendingRequests() ([]models.AbsenceRequest, error) {
+	var pendingRequests []models.AbsenceRequest
+	err := s.db.
+		Where("status = ?", models.RequestStatusPending).
+		Where("current_approval_stage != ?", models.ApprovalStageCompleted).
+		Order("last_action_at").
+		Find(&pendingRequests).Error
+	return pendingRequests, err
+}
+
+// stageSLA returns the escalation and reminder hours of the request's current
+// stage, falling back to the built-in 24 hours when no workflow defines it
+func (s *EscalationService) stageSLA(request *models.AbsenceRequest) (hours, reminderHours int) {
+	hours, reminderHours, err := s.workflows.StageSLA(request)
+	if err != nil {
+		return defaultEscalationHours, defaultReminderHours
+	}
+	return hours, reminderHours
+}
+
+// remindApprovers sends the pre-SLA reminder and records it on the request
+func (s *EscalationService) remindApprovers(request *models.AbsenceRequest, approverIDs []uuid.UUID, deadline, now time.Time) error {
+	var employee models.User
+	if err := s.db.First(&employee, "id = ?", request.EmployeeID).Error; err != nil {
+		return err
+	}
+	message := fmt.Sprintf("La solicitud de %s de %s escalará el %s si no se atiende",
+		request.RequestType, employee.FullName, deadline.Format("02/01/2006 15:04"))
+
+	return s.db.Transaction(func(tx *gorm.DB) error {
+		for _, approverID := range approverIDs {
+			var approver models.User
+			if err := tx.Where("id = ? AND is_active = ?", approverID, true).Limit(1).Find(&approver).Error; err != nil {
+				return err
+			}
+			if approver.ID == uuid.Nil {
+				continue
+			}
+			targetID := approver.ID
+			if err := tx.Create(&models.Notification{
+				CompanyID:    approver.CompanyID,
+				ActorUserID:  targetID,
+				TargetUserID: &targetID,
+				Type:         "incidence_created",
+				Title:        "Recordatorio de aprobación",
+				Message:      message,
+				ResourceType: "absence_request",
+				ResourceID:   &request.ID,
+			}).Error; err != nil {
+				return err
+			}
+		}
+		return tx.Model(&models.AbsenceRequest{}).Where("id = ?", request.ID).Update("reminder_sent_at", now).Error
+	})
 }
 
 // escalateRequest escalates a single absence request to the next stage
-func (s *EscalationService) escalateRequest(request *models.AbsenceRequest) error {
+func (s *EscalationService) escalateRequest(request *models.AbsenceRequest, hours int) error {
 	// Ensure employee data is available
 	if request.Employee == nil {
 		return fmt.Errorf("employee data not loaded for request %s", request.ID)
@@ -170,7 +301,7 @@ func (s *EscalationService) escalateRequest(request *models.AbsenceRequest) erro
 		FromStage:        oldStage,
 		ToStage:          nextStage,
 		EscalatedAt:      now,
-		EscalationReason: "24 hours without approval",
+		EscalationReason: fmt.Sprintf("%d hours without approval", hours),
 	}
 
 	if err := tx.Create(&escalationLog).Error; err != nil {
@@ -191,6 +322,53 @@ func (s *EscalationService) escalateRequest(request *models.AbsenceRequest) erro
 	return nil
 }
 
+// escalateWorkflowRequest moves a request whose current step has waited past
+// its timeout to the next step of its approval workflow
+func (s *EscalationService) escalateWorkflowRequest(request *models.AbsenceRequest, now time.Time) (bool, error) {
+	nextStages, hours, err := s.workflows.EscalationTarget(request)
+	if err != nil {
+		return false, fmt.Errorf("cannot determine next stage: %w", err)
+	}
+	if hours == 0 || now.Sub(request.LastActionAt) < time.Duration(hours)*time.Hour {
+		return false, nil
+	}
+	if len(nextStages) == 0 {
+		log.Printf("Request %s is already at final stage, skipping", request.ID)
+		return false, nil
+	}
+
+	oldStage := string(request.CurrentApprovalStage)
+	setAwaitingStages(request, nextStages)
+	request.EscalationCount++
+	request.IsEscalated = true
+	request.LastActionAt = now
+
+	err = s.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&models.AbsenceRequest{}).Where("id = ?", request.ID).Updates(map[string]interface{}{
+			"current_approval_stage": request.CurrentApprovalStage,
+			"pending_stages":         request.PendingStages,
+			"escalation_count":       request.EscalationCount,
+			"is_escalated":           true,
+			"last_action_at":         now,
+		}).Error; err != nil {
+			return fmt.Errorf("failed to update absence request: %w", err)
+		}
+		return tx.Create(&models.EscalationLog{
+			AbsenceRequestID: request.ID,
+			FromStage:        oldStage,
+			ToStage:          string(request.CurrentApprovalStage),
+			EscalatedAt:      now,
+			EscalationReason: fmt.Sprintf("%d hours without approval", hours),
+		}).Error
+	})
+	if err != nil {
+		return false, err
+	}
+
+	log.Printf("✓ Escalated request %s from %s to %s", request.ID, oldStage, request.CurrentApprovalStage)
+	return true, nil
+}
+
 // determineNextStage determines the next approval stage based on current stage and collar type
 // Workflow: Employee → Supervisor → Manager → HR (role-based) → GM → Payroll → Approved
 func (s *EscalationService) determineNextStage(currentStage, collarType string) (string, error) {
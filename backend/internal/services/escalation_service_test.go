@@ -7,6 +7,7 @@ import (
 
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"gorm.io/driver/sqlite"
 	"gorm.io/gorm"
 )
@@ -333,3 +334,51 @@ func TestGetEscalationHistory(t *testing.T) {
 	assert.Equal(t, "pending_supervisor", logs[0].FromStage)
 	assert.Equal(t, "pending_manager", logs[1].FromStage)
 }
+
+func TestSendSLAReminders_RemindsOnceBeforeSLA(t *testing.T) {
+	db := setupTestDB(t)
+	require.NoError(t, db.AutoMigrate(&models.ApprovalWorkflow{}, &models.ApprovalWorkflowStep{}, &models.Notification{}))
+	service := NewEscalationService(db)
+
+	_, user := createTestEmployee(db, "white_collar")
+	supervisor := &models.User{CompanyID: user.CompanyID, Email: "sup@example.com", PasswordHash: "x",
+		Role: "supervisor", FullName: "Supervisor", IsActive: true}
+	require.NoError(t, db.Create(supervisor).Error)
+	require.NoError(t, db.Model(user).Update("supervisor_id", supervisor.ID).Error)
+
+	// Created before workflows: the default workflow gives SUPERVISOR 24h with a 4h reminder
+	now := time.Now()
+	request := &models.AbsenceRequest{
+		EmployeeID:           user.ID,
+		RequestType:          models.RequestTypeVacation,
+		StartDate:            now,
+		EndDate:              now,
+		TotalDays:            1.0,
+		Reason:               "Test vacation",
+		Status:               models.RequestStatusPending,
+		CurrentApprovalStage: models.ApprovalStageSupervisor,
+		LastActionAt:         now.Add(-10 * time.Hour),
+	}
+	require.NoError(t, db.Create(request).Error)
+
+	reminded, err := service.SendSLAReminders(now)
+	require.NoError(t, err)
+	assert.Equal(t, 0, reminded)
+
+	reminded, err = service.SendSLAReminders(now.Add(11 * time.Hour))
+	require.NoError(t, err)
+	assert.Equal(t, 1, reminded)
+	reminded, err = service.SendSLAReminders(now.Add(12 * time.Hour))
+	require.NoError(t, err)
+	assert.Equal(t, 0, reminded)
+
+	var notifications []models.Notification
+	db.Where("target_user_id = ?", supervisor.ID).Find(&notifications)
+	require.Len(t, notifications, 1)
+	assert.Equal(t, "Recordatorio de aprobación", notifications[0].Title)
+
+	// Past the SLA the request escalates instead
+	escalated, err := service.EscalateDue(now.Add(15 * time.Hour))
+	require.NoError(t, err)
+	assert.Equal(t, 1, escalated)
+}
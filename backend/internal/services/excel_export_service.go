@@ -12,7 +12,8 @@ DESCRIPTION:
 
 USER PERSPECTIVE:
     - Download ZIP file with both Excel templates for payroll import
-    - Multi-day absences appear as separate rows (one per day)
+    - Multi-day absences appear as separate rows (one per work day; sick
+      leave keeps every calendar day)
     - Late approvals flagged in Observaciones column
     - Rejected incidences automatically excluded
 
@@ -29,7 +30,8 @@ Vacaciones.xlsx (6 columns):
 - Fecha: Start date (YYYYMMDD format)
 - FechaRegreso: Return date (YYYYMMDD format)
 - Descrip: Description (optional)
-- DiasPago: Business days (decimal, excludes weekends/holidays)
+- DiasPago: Work days of the employee (decimal, excludes rest days and
+  holidays of the WorkCalendarService)
 - DiasPrima: Vacation premium (25% of DiasPago)
 
 Faltas_y_Extras.xlsx (7 columns):
@@ -47,6 +49,7 @@ package services
 
 import (
 	"archive/zip"
+	"backend/internal/config"
 	"backend/internal/models"
 	"backend/internal/utils"
 	"bytes"
@@ -60,12 +63,13 @@ import (
 
 // ExcelExportService handles dual Excel export for payroll
 type ExcelExportService struct {
-	db *gorm.DB
+	db       *gorm.DB
+	calendar *WorkCalendarService
 }
 
 // NewExcelExportService creates a new Excel export service
-func NewExcelExportService(db *gorm.DB) *ExcelExportService {
-	return &ExcelExportService{db: db}
+func NewExcelExportService(db *gorm.DB, appConfig *config.AppConfig) *ExcelExportService {
+	return &ExcelExportService{db: db, calendar: NewWorkCalendarService(db, appConfig)}
 }
 
 // ExportResult contains the generated Excel files
@@ -177,9 +181,7 @@ func (s *ExcelExportService) GenerateVacacionesExcel(incidences []models.Inciden
 	row := 2
 	for _, inc := range incidences {
 		// Calculate business days (DiasPago)
-		year := inc.StartDate.Year()
-		calc := utils.NewBusinessDayCalculator(year)
-		diasPago := calc.CalculateBusinessDays(inc.StartDate, inc.EndDate)
+		diasPago := float64(len(s.absenceDates(inc)))
 
 		// Calculate vacation premium (DiasPrima) - 25% of DiasPago
 		diasPrima := diasPago * 0.25
@@ -332,21 +334,68 @@ func (s *ExcelExportService) expandMultiDayAbsence(inc models.Incidence, mapping
 		return rows
 	}
 
-	// Multi-day: create one row per day
-	hoursPerDay := (inc.Quantity * mapping.HoursMultiplier) / days
-	currentDate := inc.StartDate
+	// Multi-day: create one row per work day (every day when none is)
+	dates := s.absenceDates(inc)
+	if len(dates) == 0 {
+		for date := inc.StartDate; !date.After(inc.EndDate); date = date.AddDate(0, 0, 1) {
+			dates = append(dates, date)
+		}
+	}
+	hoursPerDay := (inc.Quantity * mapping.HoursMultiplier) / float64(len(dates))
 
-	for i := 0; i < int(days); i++ {
+	for _, date := range dates {
 		rows = append(rows, ExpandedRow{
-			Date:  currentDate,
+			Date:  date,
 			Hours: hoursPerDay,
 		})
-		currentDate = currentDate.AddDate(0, 0, 1)
 	}
 
 	return rows
 }
 
+// absenceDates returns the days of an incidence the employee works
+// (WorkCalendarService); IMSS sick leave counts every calendar day
+func (s *ExcelExportService) absenceDates(inc models.Incidence) []time.Time {
+	var all, work []time.Time
+	for date := inc.StartDate; !date.After(inc.EndDate); date = date.AddDate(0, 0, 1) {
+		all = append(all, date)
+	}
+	if inc.IncidenceType != nil && inc.IncidenceType.Category == "sick" {
+		return all
+	}
+	if inc.Employee == nil {
+		return s.federalBusinessDays(all)
+	}
+
+	days, err := s.calendar.Days(inc.Employee, inc.StartDate, inc.EndDate)
+	if err != nil {
+		// The export still goes out with the federal calendar
+		return s.federalBusinessDays(all)
+	}
+	for i, day := range days {
+		if day.IsWorkDay {
+			work = append(work, all[i])
+		}
+	}
+	return work
+}
+
+// federalBusinessDays keeps the Monday-Friday dates that are not federal holidays
+func (s *ExcelExportService) federalBusinessDays(dates []time.Time) []time.Time {
+	if len(dates) == 0 {
+		return nil
+	}
+	calc := utils.NewBusinessDayCalculator(dates[0].Year())
+	calc.LoadYear(dates[len(dates)-1].Year())
+	var business []time.Time
+	for _, date := range dates {
+		if calc.IsBusinessDay(date) {
+			business = append(business, date)
+		}
+	}
+	return business
+}
+
 // GetExportPreview returns a preview of the export without generating files
 // Used for UI preview before download
 func (s *ExcelExportService) GetExportPreview(payrollPeriodID uuid.UUID) (map[string]interface{}, error) {
@@ -138,7 +138,7 @@ func createTestIncidence(db *gorm.DB, templateType string, startDate, endDate ti
 
 func TestGenerateVacacionesExcel_SingleRow(t *testing.T) {
 	db := setupExcelExportTestDB(t)
-	service := NewExcelExportService(db)
+	service := NewExcelExportService(db, nil)
 
 	// Create a single vacation incidence (5 days)
 	startDate := time.Date(2024, 1, 8, 0, 0, 0, 0, time.UTC)  // Monday
@@ -157,7 +157,7 @@ func TestGenerateVacacionesExcel_SingleRow(t *testing.T) {
 
 func TestGenerateFaltasExtrasExcel_SingleDay(t *testing.T) {
 	db := setupExcelExportTestDB(t)
-	service := NewExcelExportService(db)
+	service := NewExcelExportService(db, nil)
 
 	// Create a single-day absence
 	startDate := time.Date(2024, 1, 8, 0, 0, 0, 0, time.UTC)
@@ -176,7 +176,7 @@ func TestGenerateFaltasExtrasExcel_SingleDay(t *testing.T) {
 
 func TestExpandMultiDayAbsence_SingleDay(t *testing.T) {
 	db := setupExcelExportTestDB(t)
-	service := NewExcelExportService(db)
+	service := NewExcelExportService(db, nil)
 
 	// Single day incidence
 	startDate := time.Date(2024, 1, 8, 0, 0, 0, 0, time.UTC)
@@ -197,7 +197,7 @@ func TestExpandMultiDayAbsence_SingleDay(t *testing.T) {
 
 func TestExpandMultiDayAbsence_ThreeDays(t *testing.T) {
 	db := setupExcelExportTestDB(t)
-	service := NewExcelExportService(db)
+	service := NewExcelExportService(db, nil)
 
 	// Three-day sick leave
 	startDate := time.Date(2024, 1, 8, 0, 0, 0, 0, time.UTC)  // Monday
@@ -227,7 +227,7 @@ func TestExpandMultiDayAbsence_ThreeDays(t *testing.T) {
 
 func TestGenerateDualExport_EmptyPeriod(t *testing.T) {
 	db := setupExcelExportTestDB(t)
-	service := NewExcelExportService(db)
+	service := NewExcelExportService(db, nil)
 
 	// Create empty payroll period
 	company := &models.Company{Name: "Test Company"}
@@ -256,7 +256,7 @@ func TestGenerateDualExport_EmptyPeriod(t *testing.T) {
 
 func TestGetExportPreview_WithIncidences(t *testing.T) {
 	db := setupExcelExportTestDB(t)
-	service := NewExcelExportService(db)
+	service := NewExcelExportService(db, nil)
 
 	// Create 2 vacation incidences
 	startDate1 := time.Date(2024, 1, 8, 0, 0, 0, 0, time.UTC)
@@ -334,7 +334,7 @@ func TestGetExportPreview_WithIncidences(t *testing.T) {
 
 func TestGenerateFaltasExtrasExcel_LateApprovalFlag(t *testing.T) {
 	db := setupExcelExportTestDB(t)
-	service := NewExcelExportService(db)
+	service := NewExcelExportService(db, nil)
 
 	// Create incidence with late approval flag
 	startDate := time.Date(2024, 1, 8, 0, 0, 0, 0, time.UTC)
@@ -360,7 +360,7 @@ func TestGenerateFaltasExtrasExcel_LateApprovalFlag(t *testing.T) {
 
 func TestGenerateDualExport_ExcludesRejectedIncidences(t *testing.T) {
 	db := setupExcelExportTestDB(t)
-	service := NewExcelExportService(db)
+	service := NewExcelExportService(db, nil)
 
 	// Create approved incidence
 	startDate := time.Date(2024, 1, 8, 0, 0, 0, 0, time.UTC)
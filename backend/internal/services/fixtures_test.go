@@ -0,0 +1,58 @@
+package services
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+
+	"backend/internal/models"
+	"backend/internal/models/enums"
+)
+
+// ============================================================================
+// Shared Fixtures
+// ============================================================================
+
+// createFixtureEmployee creates employee n of a company with a unique number,
+// RFC and CURP (female, hired 2022-03-01, weekly blue collar)
+func createFixtureEmployee(t *testing.T, db *gorm.DB, companyID uuid.UUID, n int, zone string, dailySalary float64) *models.Employee {
+	employee := &models.Employee{
+		EmployeeNumber:   fmt.Sprintf("EMP-%03d", n),
+		FirstName:        "Maria",
+		LastName:         "Lopez",
+		RFC:              fmt.Sprintf("LOMA900101A%02d", n),
+		CURP:             fmt.Sprintf("LOMA900101MSLPRR%02d", n),
+		DateOfBirth:      time.Date(1990, 1, 1, 0, 0, 0, 0, time.UTC),
+		HireDate:         time.Date(2022, 3, 1, 0, 0, 0, 0, time.UTC),
+		DailySalary:      dailySalary,
+		MinimumWageZone:  zone,
+		EmploymentStatus: "active",
+		CollarType:       "blue_collar",
+		PayFrequency:     "weekly",
+		CompanyID:        companyID,
+		Gender:           "female",
+		EmployeeType:     "permanent",
+	}
+	employee.ID = uuid.New()
+	require.NoError(t, db.Create(employee).Error)
+	return employee
+}
+
+// createFixtureUser links a portal user to an employee
+func createFixtureUser(t *testing.T, db *gorm.DB, employee *models.Employee, role string) *models.User {
+	user := &models.User{
+		CompanyID:    employee.CompanyID,
+		EmployeeID:   &employee.ID,
+		Email:        fmt.Sprintf("%s@example.com", employee.EmployeeNumber),
+		PasswordHash: "hashedpassword123",
+		Role:         enums.UserRole(role),
+		FullName:     employee.FirstName,
+		IsActive:     true,
+	}
+	require.NoError(t, db.Create(user).Error)
+	return user
+}
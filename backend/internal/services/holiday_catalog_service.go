@@ -0,0 +1,153 @@
+/*
+Package services - Holiday Catalog Service
+
+==============================================================================
+FILE: internal/services/holiday_catalog_service.go
+==============================================================================
+
+DESCRIPTION:
+    Builds a single holiday catalog by merging three sources:
+        1. Federal mandatory rest days (LFT Art. 74, computed per year)
+        2. State/local holidays from the regional payroll config and the
+           files under configs/holidays/ (types.LocalHoliday)
+        3. Company holidays captured in time_holidays (models.Holiday)
+
+USER PERSPECTIVE:
+    - Payment dates and cut-offs skip every holiday the plant observes
+    - HR sees one list instead of checking three places
+
+DEVELOPER GUIDELINES:
+    ✅  OK to modify: Add new holiday sources
+    ⚠️  CAUTION: Source priority when two sources share a date
+    ❌  DO NOT modify: Federal holiday rules here (see utils.MexicanFederalHolidays)
+    📝  "optional" company holidays are informative and are NOT non-working days
+
+==============================================================================
+*/
+package services
+
+import (
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"backend/internal/config"
+	"backend/internal/config/payroll/types"
+	"backend/internal/dtos"
+	"backend/internal/models"
+	"backend/internal/utils"
+)
+
+// HolidayCatalogService merges federal, state and company holidays
+type HolidayCatalogService struct {
+	db            *gorm.DB
+	localHolidays []types.LocalHoliday
+}
+
+// NewHolidayCatalogService creates a new HolidayCatalogService
+func NewHolidayCatalogService(db *gorm.DB, appConfig *config.AppConfig) *HolidayCatalogService {
+	var localHolidays []types.LocalHoliday
+	if appConfig != nil && appConfig.PayrollConfig != nil {
+		regional := appConfig.PayrollConfig.Regional
+		localHolidays = append(localHolidays, regional.StateHolidays...)
+		localHolidays = append(localHolidays, regional.LocalHolidays...)
+	}
+	return &HolidayCatalogService{
+		db:            db,
+		localHolidays: localHolidays,
+	}
+}
+
+// GetHolidays returns the merged holiday catalog between start and end (inclusive), sorted by date.
+// When two sources share a date, federal wins over state and state over company.
+func (s *HolidayCatalogService) GetHolidays(companyID uuid.UUID, start, end time.Time) ([]dtos.HolidayCatalogEntry, error) {
+	start = truncateToDate(start)
+	end = truncateToDate(end)
+	byDate := make(map[string]dtos.HolidayCatalogEntry)
+
+	add := func(entry dtos.HolidayCatalogEntry) {
+		if entry.Date.Before(start) || entry.Date.After(end) {
+			return
+		}
+		key := entry.Date.Format("2006-01-02")
+		if _, exists := byDate[key]; !exists {
+			byDate[key] = entry
+		}
+	}
+
+	// 1. Federal holidays
+	for year := start.Year(); year <= end.Year(); year++ {
+		for dateStr, name := range utils.MexicanFederalHolidays(year) {
+			date, _ := time.ParseInLocation("2006-01-02", dateStr, start.Location())
+			add(dtos.HolidayCatalogEntry{Date: date, Name: name, Source: dtos.HolidaySourceFederal, IsPaid: true})
+		}
+	}
+
+	// 2. State / local holidays from config
+	for _, local := range s.localHolidays {
+		date, err := time.ParseInLocation("2006-01-02", local.Date, start.Location())
+		if err != nil {
+			continue
+		}
+		name := local.Name
+		if name == "" {
+			name = local.Description
+		}
+		add(dtos.HolidayCatalogEntry{Date: date, Name: name, Source: dtos.HolidaySourceState, IsPaid: local.Paid})
+	}
+
+	// 3. Company holidays
+	if companyID != uuid.Nil {
+		var holidays []models.Holiday
+		if err := s.db.Where("company_id = ? AND is_active = ? AND holiday_type <> ?", companyID, true, "optional").
+			Find(&holidays).Error; err != nil {
+			return nil, err
+		}
+		for _, h := range holidays {
+			if !h.IsRecurring {
+				add(dtos.HolidayCatalogEntry{Date: truncateToDate(h.Date), Name: h.Name, Source: dtos.HolidaySourceCompany, IsPaid: h.IsPaid})
+				continue
+			}
+			// Recurring holidays repeat on the same month/day every year
+			for year := start.Year(); year <= end.Year(); year++ {
+				date := time.Date(year, h.Date.Month(), h.Date.Day(), 0, 0, 0, 0, start.Location())
+				add(dtos.HolidayCatalogEntry{Date: date, Name: h.Name, Source: dtos.HolidaySourceCompany, IsPaid: h.IsPaid})
+			}
+		}
+	}
+
+	entries := make([]dtos.HolidayCatalogEntry, 0, len(byDate))
+	for _, entry := range byDate {
+		entries = append(entries, entry)
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].Date.Before(entries[j].Date)
+	})
+
+	return entries, nil
+}
+
+// BusinessDayCalculator returns a calculator loaded with every holiday in the catalog for the range
+func (s *HolidayCatalogService) BusinessDayCalculator(companyID uuid.UUID, start, end time.Time) (*utils.BusinessDayCalculator, error) {
+	calc := utils.NewBusinessDayCalculator(start.Year())
+	for year := start.Year() + 1; year <= end.Year(); year++ {
+		calc.LoadYear(year)
+	}
+
+	holidays, err := s.GetHolidays(companyID, start, end)
+	if err != nil {
+		return nil, err
+	}
+	for _, h := range holidays {
+		calc.AddCustomHoliday(h.Date.Format("2006-01-02"))
+	}
+
+	return calc, nil
+}
+
+// truncateToDate drops the time of day, keeping the location
+func truncateToDate(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+}
@@ -29,6 +29,10 @@ SYNTAX EXPLANATION:
     - CalculationMethod: daily_rate, hourly_rate, fixed_amount, percentage
     - Status flow: pending -> approved/rejected -> processed
     - Vacation days per Mexican law: Year 1=12, Year 2=14, increases with seniority
+    - Approving, un-approving or deleting a vacation incidence posts the
+      consumption or its reversal to the vacation ledger (VacationLedgerService)
+    - Vacation incidences take the work days of their range
+      (WorkCalendarService): holidays and rest days are not charged
 
 ==============================================================================
 */
@@ -36,22 +40,30 @@ package services
 
 import (
 	"errors"
+	"fmt"
 	"time"
 
 	"github.com/google/uuid"
 	"gorm.io/gorm"
 
+	"backend/internal/config"
 	"backend/internal/models"
 )
 
 // IncidenceService handles incidence business logic
 type IncidenceService struct {
-	db *gorm.DB
+	db             *gorm.DB
+	vacationLedger *VacationLedgerService
+	calendar       *WorkCalendarService
 }
 
 // NewIncidenceService creates a new incidence service
-func NewIncidenceService(db *gorm.DB) *IncidenceService {
-	return &IncidenceService{db: db}
+func NewIncidenceService(db *gorm.DB, appConfig *config.AppConfig) *IncidenceService {
+	return &IncidenceService{
+		db:             db,
+		vacationLedger: NewVacationLedgerService(db, appConfig),
+		calendar:       NewWorkCalendarService(db, appConfig),
+	}
 }
 
 // IncidenceTypeRequest represents request for creating/updating incidence types
@@ -407,6 +419,12 @@ func (s *IncidenceService) CreateIncidence(req CreateIncidenceRequest) (*models.
 		periodID = period.ID
 	}
 
+	quantity, err := s.vacationQuantity(&employee, &incidenceType, startDate, endDate, req.Quantity)
+	if err != nil {
+		return nil, err
+	}
+	req.Quantity = quantity
+
 	// Calculate amount based on incidence type
 	calculatedAmount := 0.0
 	if incidenceType.IsCalculated {
@@ -444,6 +462,22 @@ func (s *IncidenceService) CreateIncidence(req CreateIncidenceRequest) (*models.
 	return s.GetIncidenceByID(incidence.ID)
 }
 
+// vacationQuantity is the number of days a vacation incidence charges: the
+// work days of its range. Other incidences keep the quantity entered.
+func (s *IncidenceService) vacationQuantity(employee *models.Employee, incidenceType *models.IncidenceType, start, end time.Time, quantity float64) (float64, error) {
+	if employee == nil || incidenceType == nil || incidenceType.Category != "vacation" {
+		return quantity, nil
+	}
+	days, err := s.calendar.WorkDayQuantity(employee, start, end, quantity)
+	if err != nil {
+		return 0, err
+	}
+	if days == 0 {
+		return 0, fmt.Errorf("there are no work days between %s and %s", start.Format("2006-01-02"), end.Format("2006-01-02"))
+	}
+	return days, nil
+}
+
 // UpdateIncidence updates an incidence
 func (s *IncidenceService) UpdateIncidence(id uuid.UUID, req UpdateIncidenceRequest) (*models.Incidence, error) {
 	incidence, err := s.GetIncidenceByID(id)
@@ -475,9 +509,20 @@ func (s *IncidenceService) UpdateIncidence(id uuid.UUID, req UpdateIncidenceRequ
 		return nil, errors.New("end date must be after start date")
 	}
 
+	wasApproved := incidence.Status == "approved"
+	previousQuantity := incidence.Quantity
+	previousStart := incidence.StartDate
+
 	if req.Quantity > 0 {
 		incidence.Quantity = req.Quantity
 	}
+	if req.StartDate != "" || req.EndDate != "" || req.Quantity > 0 {
+		quantity, err := s.vacationQuantity(incidence.Employee, incidence.IncidenceType, incidence.StartDate, incidence.EndDate, incidence.Quantity)
+		if err != nil {
+			return nil, err
+		}
+		incidence.Quantity = quantity
+	}
 
 	if req.Comments != "" {
 		incidence.Comments = req.Comments
@@ -491,7 +536,22 @@ func (s *IncidenceService) UpdateIncidence(id uuid.UUID, req UpdateIncidenceRequ
 		incidence.Status = req.Status
 	}
 
-	if err := s.db.Save(incidence).Error; err != nil {
+	// A change to an approved vacation gives its days back and charges it again
+	recharge := wasApproved && (incidence.Status != "approved" ||
+		incidence.Quantity != previousQuantity || !incidence.StartDate.Equal(previousStart))
+	err = s.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Save(incidence).Error; err != nil {
+			return err
+		}
+		ledger := s.vacationLedger.withDB(tx)
+		if recharge {
+			if err := ledger.ReverseConsumption(incidence, nil, time.Now()); err != nil {
+				return err
+			}
+		}
+		return ledger.PostConsumption(incidence, time.Now())
+	})
+	if err != nil {
 		return nil, err
 	}
 
@@ -509,7 +569,14 @@ func (s *IncidenceService) DeleteIncidence(id uuid.UUID) error {
 		return errors.New("cannot delete a processed incidence")
 	}
 
-	return s.db.Delete(incidence).Error
+	return s.db.Transaction(func(tx *gorm.DB) error {
+		if incidence.Status == "approved" {
+			if err := s.vacationLedger.withDB(tx).ReverseConsumption(incidence, nil, time.Now()); err != nil {
+				return err
+			}
+		}
+		return tx.Delete(incidence).Error
+	})
 }
 
 // ApproveIncidence approves an incidence
@@ -528,7 +595,13 @@ func (s *IncidenceService) ApproveIncidence(id uuid.UUID, approverID uuid.UUID)
 	incidence.ApprovedBy = &approverID
 	incidence.ApprovedAt = &now
 
-	if err := s.db.Save(incidence).Error; err != nil {
+	err = s.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Save(incidence).Error; err != nil {
+			return err
+		}
+		return s.vacationLedger.withDB(tx).PostConsumption(incidence, now)
+	})
+	if err != nil {
 		return nil, err
 	}
 
@@ -558,77 +631,38 @@ func (s *IncidenceService) RejectIncidence(id uuid.UUID, approverID uuid.UUID) (
 	return s.GetIncidenceByID(id)
 }
 
-// GetEmployeeVacationBalance returns vacation statistics for an employee
+// GetEmployeeVacationBalance returns vacation statistics for an employee from
+// the vacation ledger: the current anniversary's grant, what was used of it,
+// the days carried over from earlier anniversaries and the pending requests
 func (s *IncidenceService) GetEmployeeVacationBalance(employeeID uuid.UUID) (map[string]interface{}, error) {
-	// Get employee to calculate vacation days based on seniority
 	var employee models.Employee
 	if err := s.db.First(&employee, "id = ?", employeeID).Error; err != nil {
 		return nil, errors.New("employee not found")
 	}
 
-	// Calculate years of service
-	yearsOfService := time.Since(employee.HireDate).Hours() / 24 / 365
-
-	// Mexican labor law vacation days based on seniority
-	var entitledDays int
-	switch {
-	case yearsOfService < 1:
-		entitledDays = int(yearsOfService * 12) // Prorated first year
-	case yearsOfService < 2:
-		entitledDays = 12
-	case yearsOfService < 3:
-		entitledDays = 14
-	case yearsOfService < 4:
-		entitledDays = 16
-	case yearsOfService < 5:
-		entitledDays = 18
-	case yearsOfService < 10:
-		entitledDays = 20
-	case yearsOfService < 15:
-		entitledDays = 22
-	case yearsOfService < 20:
-		entitledDays = 24
-	case yearsOfService < 25:
-		entitledDays = 26
-	case yearsOfService < 30:
-		entitledDays = 28
-	default:
-		entitledDays = 30
-	}
-
-	// Get current year used vacation days
-	currentYear := time.Now().Year()
-	startOfYear := time.Date(currentYear, 1, 1, 0, 0, 0, 0, time.UTC)
-	endOfYear := time.Date(currentYear, 12, 31, 23, 59, 59, 0, time.UTC)
-
-	var usedDays float64
-	s.db.Model(&models.Incidence{}).
-		Joins("JOIN incidence_types ON incidences.incidence_type_id = incidence_types.id").
-		Where("incidences.employee_id = ?", employeeID).
-		Where("incidence_types.category = ?", "vacation").
-		Where("incidences.status IN (?)", []string{"approved", "processed"}).
-		Where("incidences.start_date >= ? AND incidences.start_date <= ?", startOfYear, endOfYear).
-		Select("COALESCE(SUM(incidences.quantity), 0)").
-		Scan(&usedDays)
+	statement, err := s.vacationLedger.EmployeeStatement(&employee, time.Now())
+	if err != nil {
+		return nil, err
+	}
 
-	// Get pending vacation requests
-	var pendingDays float64
-	s.db.Model(&models.Incidence{}).
-		Joins("JOIN incidence_types ON incidences.incidence_type_id = incidence_types.id").
-		Where("incidences.employee_id = ?", employeeID).
-		Where("incidence_types.category = ?", "vacation").
-		Where("incidences.status = ?", "pending").
-		Select("COALESCE(SUM(incidences.quantity), 0)").
-		Scan(&pendingDays)
+	usedDays := 0.0
+	for _, bucket := range statement.Buckets {
+		if bucket.ServiceYear == statement.YearsOfService {
+			usedDays = bucket.Used
+		}
+	}
 
 	return map[string]interface{}{
 		"employee_id":      employeeID,
-		"years_of_service": yearsOfService,
-		"entitled_days":    entitledDays,
+		"years_of_service": statement.YearsOfService,
+		"entitled_days":    statement.CurrentGrant,
 		"used_days":        usedDays,
-		"pending_days":     pendingDays,
-		"available_days":   float64(entitledDays) - usedDays - pendingDays,
-		"year":             currentYear,
+		"carried_over":     statement.CarriedOver,
+		"balance_days":     statement.Balance,
+		"pending_days":     statement.PendingDays,
+		"available_days":   statement.Available,
+		"next_anniversary": statement.NextAnniversary,
+		"year":             statement.YearsOfService,
 	}, nil
 }
 
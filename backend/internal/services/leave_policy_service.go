@@ -0,0 +1,534 @@
+/*
+Package services - Leave Policy Service
+
+==============================================================================
+FILE: internal/services/leave_policy_service.go
+==============================================================================
+
+DESCRIPTION:
+    Maintains the leave policy catalog (maternity, paternity, bereavement,
+    marriage, union commissions, ...) and enforces it on LEAVE absence
+    requests: the employee must be eligible, the days must fit in the
+    entitlement and the required documents must be attached before the
+    request is approved. Also reports each employee's leave balances.
+
+USER PERSPECTIVE:
+    - A company starts with the leaves of the LFT and the usual contract
+      clauses; HR edits them or adds its own
+    - An employee asking for 6 days of paternity, or for union commission
+      days without being unionized, is told why right away
+    - Employees and HR see the days used and left of every leave
+
+DEVELOPER GUIDELINES:
+    ✅  OK to modify: Default catalog, eligibility rules
+    ⚠️  CAUTION: Every policy keeps its own IncidenceType in sync (name,
+        effect, evidence); requests post to payroll through it
+    ⚠️  CAUTION: Usage counts PENDING and APPROVED requests; pass the IDs
+        of the request being checked and of the request it amends so they
+        are not counted twice
+    📝  Usage is per calendar year of the request's start date
+    📝  Policies with CountsWorkDays count requests in work days
+        (WorkCalendarService), the rest in calendar days
+
+SYNTAX EXPLANATION:
+    - Eligibility on a date: gender in Genders (empty = any), completed
+      months since HireDate >= MinServiceMonths, IsSindicalizado matching
+      UnionStatus
+    - per_event: each request takes at most EntitlementDays, and at most
+      MaxEventsPerYear requests a year (0 = no limit)
+    - per_year: used + pending + requested <= EntitlementDays
+    - Evidence: any IncidenceEvidence on the incidences of the request (or
+      of the request it modifies)
+
+ENDPOINTS (leave_policy_handler.go):
+    GET    /leave-policies/me                       - Own leave balances
+    GET    /leave-policies                          - Catalog (seeds defaults)
+    GET    /leave-policies/employees/:id/balances   - Employee balances
+    POST   /leave-policies                          - Create policy
+    PUT    /leave-policies/:id                      - Update policy
+    DELETE /leave-policies/:id                      - Delete policy
+
+==============================================================================
+*/
+package services
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"backend/internal/dtos"
+	"backend/internal/models"
+)
+
+// defaultLeavePolicies are the leaves every company starts with
+func defaultLeavePolicies(companyID uuid.UUID) []models.LeavePolicy {
+	return []models.LeavePolicy{
+		{
+			CompanyID: companyID, Code: "MATERNITY", Name: "Maternidad",
+			Description:       "Descanso de seis semanas antes y seis después del parto, pagado por el IMSS",
+			LegalReference:    "LFT Art. 170",
+			EntitlementDays:   84,
+			EntitlementBasis:  models.LeaveEntitlementPerEvent,
+			Genders:           []string{"female"},
+			UnionStatus:       models.LeaveUnionAny,
+			RequiredDocuments: []string{"Certificado de incapacidad por maternidad (IMSS)"},
+			PaidBy:            models.LeavePaidByIMSS,
+		},
+		{
+			CompanyID: companyID, Code: "PATERNITY", Name: "Paternidad",
+			Description:       "Cinco días laborables con goce de sueldo por nacimiento o adopción",
+			LegalReference:    "LFT Art. 132 XXVII Bis",
+			EntitlementDays:   5,
+			EntitlementBasis:  models.LeaveEntitlementPerEvent,
+			Genders:           []string{"male"},
+			CountsWorkDays:    true,
+			UnionStatus:       models.LeaveUnionAny,
+			RequiredDocuments: []string{"Acta de nacimiento o constancia de adopción"},
+			PaidBy:            models.LeavePaidByCompany,
+		},
+		{
+			CompanyID: companyID, Code: "BEREAVEMENT", Name: "Luto",
+			Description:       "Fallecimiento de un familiar directo",
+			LegalReference:    "Contrato colectivo",
+			EntitlementDays:   3,
+			EntitlementBasis:  models.LeaveEntitlementPerEvent,
+			CountsWorkDays:    true,
+			UnionStatus:       models.LeaveUnionAny,
+			RequiredDocuments: []string{"Acta de defunción"},
+			PaidBy:            models.LeavePaidByCompany,
+		},
+		{
+			CompanyID: companyID, Code: "MARRIAGE", Name: "Matrimonio",
+			Description:       "Matrimonio civil del colaborador",
+			LegalReference:    "Contrato colectivo",
+			EntitlementDays:   3,
+			EntitlementBasis:  models.LeaveEntitlementPerEvent,
+			MaxEventsPerYear:  1,
+			CountsWorkDays:    true,
+			UnionStatus:       models.LeaveUnionAny,
+			RequiredDocuments: []string{"Acta de matrimonio"},
+			PaidBy:            models.LeavePaidByCompany,
+		},
+		{
+			CompanyID: companyID, Code: "UNION_COMMISSION", Name: "Comisión sindical",
+			Description:       "Días para comisiones del sindicato",
+			LegalReference:    "LFT Art. 132 X",
+			EntitlementDays:   10,
+			EntitlementBasis:  models.LeaveEntitlementPerYear,
+			CountsWorkDays:    true,
+			UnionStatus:       models.LeaveUnionOnly,
+			RequiredDocuments: []string{"Oficio de comisión sindical"},
+			PaidBy:            models.LeavePaidByCompany,
+		},
+	}
+}
+
+// LeavePolicyService manages the leave catalog and its balances
+type LeavePolicyService struct {
+	db *gorm.DB
+}
+
+// NewLeavePolicyService creates a new LeavePolicyService
+func NewLeavePolicyService(db *gorm.DB) *LeavePolicyService {
+	return &LeavePolicyService{db: db}
+}
+
+// =========================================================================
+// Catalog
+// =========================================================================
+
+// SeedDefaults creates the default leaves if the company has no policies
+func (s *LeavePolicyService) SeedDefaults(companyID uuid.UUID) error {
+	var count int64
+	if err := s.db.Model(&models.LeavePolicy{}).Where("company_id = ?", companyID).Count(&count).Error; err != nil {
+		return fmt.Errorf("error fetching leave policies: %w", err)
+	}
+	if count > 0 {
+		return nil
+	}
+
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		for _, policy := range defaultLeavePolicies(companyID) {
+			policy.IsActive = true
+			if err := ensureLeaveIncidenceType(tx, &policy); err != nil {
+				return err
+			}
+			if err := tx.Create(&policy).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("error seeding leave policies: %w", err)
+	}
+	return nil
+}
+
+// ListPolicies returns the company's leave policies, seeding the defaults on first use
+func (s *LeavePolicyService) ListPolicies(companyID uuid.UUID) ([]models.LeavePolicy, error) {
+	if err := s.SeedDefaults(companyID); err != nil {
+		return nil, err
+	}
+	var policies []models.LeavePolicy
+	if err := s.db.Where("company_id = ?", companyID).Order("name").Find(&policies).Error; err != nil {
+		return nil, fmt.Errorf("error fetching leave policies: %w", err)
+	}
+	return policies, nil
+}
+
+// CreatePolicy creates a leave policy and its incidence type
+func (s *LeavePolicyService) CreatePolicy(companyID uuid.UUID, req dtos.LeavePolicyRequest) (*models.LeavePolicy, error) {
+	policy := &models.LeavePolicy{CompanyID: companyID, IsActive: true}
+	applyLeavePolicyRequest(policy, req)
+	if err := s.checkUnique(policy); err != nil {
+		return nil, err
+	}
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		if err := ensureLeaveIncidenceType(tx, policy); err != nil {
+			return err
+		}
+		if err := tx.Create(policy).Error; err != nil {
+			return err
+		}
+		// Create replaces zero values (no event limit, inactive) with the
+		// column defaults; write the request back as sent
+		applyLeavePolicyRequest(policy, req)
+		return tx.Save(policy).Error
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error creating leave policy: %w", err)
+	}
+	return policy, nil
+}
+
+// UpdatePolicy updates a leave policy; requests already filed keep their days
+func (s *LeavePolicyService) UpdatePolicy(id, companyID uuid.UUID, req dtos.LeavePolicyRequest) (*models.LeavePolicy, error) {
+	var policy models.LeavePolicy
+	if err := s.db.Where("id = ? AND company_id = ?", id, companyID).First(&policy).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("leave policy not found")
+		}
+		return nil, err
+	}
+	applyLeavePolicyRequest(&policy, req)
+	if err := s.checkUnique(&policy); err != nil {
+		return nil, err
+	}
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		if err := ensureLeaveIncidenceType(tx, &policy); err != nil {
+			return err
+		}
+		return tx.Save(&policy).Error
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error updating leave policy: %w", err)
+	}
+	return &policy, nil
+}
+
+// DeletePolicy deletes a leave policy; its requests and incidences are kept
+func (s *LeavePolicyService) DeletePolicy(id, companyID uuid.UUID) error {
+	result := s.db.Where("id = ? AND company_id = ?", id, companyID).Delete(&models.LeavePolicy{})
+	if result.Error != nil {
+		return fmt.Errorf("error deleting leave policy: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return errors.New("leave policy not found")
+	}
+	return nil
+}
+
+func applyLeavePolicyRequest(policy *models.LeavePolicy, req dtos.LeavePolicyRequest) {
+	policy.Code = strings.ToUpper(strings.TrimSpace(req.Code))
+	policy.Name = strings.TrimSpace(req.Name)
+	policy.Description = req.Description
+	policy.LegalReference = req.LegalReference
+	policy.EntitlementDays = req.EntitlementDays
+	policy.EntitlementBasis = req.EntitlementBasis
+	policy.MaxEventsPerYear = req.MaxEventsPerYear
+	policy.CountsWorkDays = req.CountsWorkDays
+	policy.Genders = req.Genders
+	policy.MinServiceMonths = req.MinServiceMonths
+	policy.UnionStatus = req.UnionStatus
+	if policy.UnionStatus == "" {
+		policy.UnionStatus = models.LeaveUnionAny
+	}
+	policy.RequiredDocuments = req.RequiredDocuments
+	policy.PaidBy = req.PaidBy
+	if req.IsActive != nil {
+		policy.IsActive = *req.IsActive
+	}
+}
+
+// checkUnique rejects a second policy with the same code in the company
+func (s *LeavePolicyService) checkUnique(policy *models.LeavePolicy) error {
+	var count int64
+	if err := s.db.Model(&models.LeavePolicy{}).
+		Where("company_id = ? AND code = ? AND id <> ?", policy.CompanyID, policy.Code, policy.ID).
+		Count(&count).Error; err != nil {
+		return err
+	}
+	if count > 0 {
+		return fmt.Errorf("leave policy %s already exists", policy.Code)
+	}
+	return nil
+}
+
+// ensureLeaveIncidenceType creates or updates the incidence type the
+// policy's requests post to payroll
+func ensureLeaveIncidenceType(tx *gorm.DB, policy *models.LeavePolicy) error {
+	var incidenceType models.IncidenceType
+	if policy.IncidenceTypeID != nil {
+		if err := tx.Limit(1).Find(&incidenceType, "id = ?", *policy.IncidenceTypeID).Error; err != nil {
+			return err
+		}
+	}
+	incidenceType.Name = "Licencia: " + policy.Name
+	incidenceType.Category = "absence"
+	incidenceType.EffectType = "negative" // Days the company does not pay are deducted
+	if policy.IsPaid() {
+		incidenceType.EffectType = "neutral"
+	}
+	incidenceType.IsCalculated = true
+	incidenceType.CalculationMethod = "daily_rate"
+	incidenceType.DefaultValue = 1
+	incidenceType.RequiresEvidence = len(policy.RequiredDocuments) > 0
+	incidenceType.IsRequestable = true
+	incidenceType.Description = policy.Description
+	if err := tx.Save(&incidenceType).Error; err != nil {
+		return fmt.Errorf("error saving leave incidence type: %w", err)
+	}
+	policy.IncidenceTypeID = &incidenceType.ID
+	return nil
+}
+
+// =========================================================================
+// Enforcement
+// =========================================================================
+
+// activePolicy loads an active policy of the company
+func (s *LeavePolicyService) activePolicy(companyID, id uuid.UUID) (*models.LeavePolicy, error) {
+	var policy models.LeavePolicy
+	if err := s.db.Where("id = ? AND company_id = ? AND is_active = ?", id, companyID, true).
+		Limit(1).Find(&policy).Error; err != nil {
+		return nil, err
+	}
+	if policy.ID == uuid.Nil {
+		return nil, errors.New("leave policy not found")
+	}
+	return &policy, nil
+}
+
+// Eligibility returns why the employee cannot take the leave on the date,
+// or "" when they can
+func (s *LeavePolicyService) Eligibility(policy *models.LeavePolicy, employee *models.Employee, on time.Time) string {
+	if len(policy.Genders) > 0 && !containsString(policy.Genders, employee.Gender) {
+		return fmt.Sprintf("%s is only granted to %s employees", policy.Name, strings.Join(policy.Genders, " or "))
+	}
+	if months := serviceMonths(employee.HireDate, on); months < policy.MinServiceMonths {
+		return fmt.Sprintf("%s requires %d months of service (%d completed)", policy.Name, policy.MinServiceMonths, months)
+	}
+	switch policy.UnionStatus {
+	case models.LeaveUnionOnly:
+		if !employee.IsSindicalizado {
+			return fmt.Sprintf("%s is only granted to unionized employees", policy.Name)
+		}
+	case models.LeaveUnionNonUnion:
+		if employee.IsSindicalizado {
+			return fmt.Sprintf("%s is not granted to unionized employees", policy.Name)
+		}
+	}
+	return ""
+}
+
+// serviceMonths counts the months completed between hire and date
+func serviceMonths(hire, date time.Time) int {
+	if hire.IsZero() || date.Before(hire) {
+		return 0
+	}
+	months := (date.Year()-hire.Year())*12 + int(date.Month()-hire.Month())
+	if date.Day() < hire.Day() {
+		months--
+	}
+	return months
+}
+
+// leaveUsage is what an employee took of a leave in a calendar year
+type leaveUsage struct {
+	Used    float64 // Approved days
+	Pending float64 // Days waiting for approval
+	Events  int     // Approved and pending requests
+}
+
+// usage sums the employee's requests of the policy starting in the year
+func (s *LeavePolicyService) usage(policy *models.LeavePolicy, employeeID uuid.UUID, year int, exclude ...uuid.UUID) (leaveUsage, error) {
+	start := time.Date(year, 1, 1, 0, 0, 0, 0, time.UTC)
+	// AbsenceRequest.EmployeeID holds the requesting user's ID
+	query := s.db.Model(&models.AbsenceRequest{}).
+		Joins("JOIN users ON users.id = absence_requests.employee_id").
+		Where("users.employee_id = ? AND absence_requests.leave_policy_id = ? AND absence_requests.status IN ?",
+			employeeID, policy.ID, []models.RequestStatus{models.RequestStatusPending, models.RequestStatusApproved}).
+		Where("absence_requests.start_date >= ? AND absence_requests.start_date < ?", start, start.AddDate(1, 0, 0)).
+		Scopes(timeOffRequests)
+	if len(exclude) > 0 {
+		query = query.Where("absence_requests.id NOT IN ?", exclude)
+	}
+
+	var rows []struct {
+		Status models.RequestStatus
+		Days   float64
+		Events int
+	}
+	if err := query.Select("absence_requests.status AS status, COALESCE(SUM(absence_requests.total_days), 0) AS days, COUNT(*) AS events").
+		Group("absence_requests.status").Scan(&rows).Error; err != nil {
+		return leaveUsage{}, err
+	}
+	var usage leaveUsage
+	for _, row := range rows {
+		if row.Status == models.RequestStatusApproved {
+			usage.Used += row.Days
+		} else {
+			usage.Pending += row.Days
+		}
+		usage.Events += row.Events
+	}
+	usage.Used = roundTo2(usage.Used)
+	usage.Pending = roundTo2(usage.Pending)
+	return usage, nil
+}
+
+// CheckRequest refuses a request of the leave the employee is not eligible
+// for or that goes over the entitlement; exclude lists the request itself
+// and the request it amends
+func (s *LeavePolicyService) CheckRequest(policy *models.LeavePolicy, employee *models.Employee, start time.Time, days float64, exclude ...uuid.UUID) error {
+	if reason := s.Eligibility(policy, employee, start); reason != "" {
+		return errors.New(reason)
+	}
+	usage, err := s.usage(policy, employee.ID, start.Year(), exclude...)
+	if err != nil {
+		return err
+	}
+
+	if policy.EntitlementBasis == models.LeaveEntitlementPerYear {
+		if remaining := roundTo2(policy.EntitlementDays - usage.Used - usage.Pending); days > remaining {
+			return fmt.Errorf("%s allows %.2f days a year: %.2f days left", policy.Name, policy.EntitlementDays, remaining)
+		}
+		return nil
+	}
+	if days > policy.EntitlementDays {
+		return fmt.Errorf("%s allows %.2f days per event", policy.Name, policy.EntitlementDays)
+	}
+	if policy.MaxEventsPerYear > 0 && usage.Events >= policy.MaxEventsPerYear {
+		return fmt.Errorf("%s can be taken %d time(s) a year", policy.Name, policy.MaxEventsPerYear)
+	}
+	return nil
+}
+
+// CheckEvidence refuses to approve a request of a leave with required
+// documents when nothing was attached to its incidences
+func (s *LeavePolicyService) CheckEvidence(policy *models.LeavePolicy, request *models.AbsenceRequest) error {
+	if len(policy.RequiredDocuments) == 0 {
+		return nil
+	}
+	requestIDs := []uuid.UUID{request.ID}
+	if request.AmendsID != nil {
+		requestIDs = append(requestIDs, *request.AmendsID)
+	}
+	var count int64
+	if err := s.db.Model(&models.IncidenceEvidence{}).
+		Joins("JOIN incidences ON incidences.id = incidence_evidences.incidence_id").
+		Where("incidences.absence_request_id IN ?", requestIDs).
+		Count(&count).Error; err != nil {
+		return err
+	}
+	if count == 0 {
+		return fmt.Errorf("%s requires evidence: %s", policy.Name, strings.Join(policy.RequiredDocuments, ", "))
+	}
+	return nil
+}
+
+// =========================================================================
+// Balances
+// =========================================================================
+
+// Balances returns the employee's balance of every active leave of the
+// company in the calendar year of asOf
+func (s *LeavePolicyService) Balances(employee *models.Employee, asOf time.Time) (*dtos.LeaveBalances, error) {
+	policies, err := s.ListPolicies(employee.CompanyID)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &dtos.LeaveBalances{EmployeeID: employee.ID, AsOf: asOf, Leaves: []dtos.LeaveBalance{}}
+	for i := range policies {
+		policy := &policies[i]
+		if !policy.IsActive {
+			continue
+		}
+		usage, err := s.usage(policy, employee.ID, asOf.Year())
+		if err != nil {
+			return nil, err
+		}
+		reason := s.Eligibility(policy, employee, asOf)
+		balance := dtos.LeaveBalance{
+			PolicyID:          policy.ID,
+			Code:              policy.Code,
+			Name:              policy.Name,
+			EntitlementBasis:  policy.EntitlementBasis,
+			EntitlementDays:   policy.EntitlementDays,
+			MaxEventsPerYear:  policy.MaxEventsPerYear,
+			PaidBy:            policy.PaidBy,
+			RequiredDocuments: append([]string{}, policy.RequiredDocuments...),
+			Eligible:          reason == "",
+			IneligibleReason:  reason,
+			Year:              asOf.Year(),
+			UsedDays:          usage.Used,
+			PendingDays:       usage.Pending,
+			Events:            usage.Events,
+			RemainingDays:     policy.EntitlementDays,
+		}
+		switch {
+		case reason != "":
+			balance.RemainingDays = 0
+		case policy.EntitlementBasis == models.LeaveEntitlementPerYear:
+			balance.RemainingDays = roundTo2(policy.EntitlementDays - usage.Used - usage.Pending)
+		case policy.MaxEventsPerYear > 0 && usage.Events >= policy.MaxEventsPerYear:
+			balance.RemainingDays = 0
+		}
+		if balance.RemainingDays < 0 {
+			balance.RemainingDays = 0
+		}
+		result.Leaves = append(result.Leaves, balance)
+	}
+	return result, nil
+}
+
+// EmployeeBalances returns the leave balances of an employee of the company
+func (s *LeavePolicyService) EmployeeBalances(companyID, employeeID uuid.UUID, asOf time.Time) (*dtos.LeaveBalances, error) {
+	var employee models.Employee
+	if err := s.db.Where("id = ? AND company_id = ?", employeeID, companyID).Limit(1).Find(&employee).Error; err != nil {
+		return nil, err
+	}
+	if employee.ID == uuid.Nil {
+		return nil, errors.New("employee not found")
+	}
+	return s.Balances(&employee, asOf)
+}
+
+// BalancesForUser returns the leave balances of the employee linked to a user
+func (s *LeavePolicyService) BalancesForUser(companyID, userID uuid.UUID, asOf time.Time) (*dtos.LeaveBalances, error) {
+	var user models.User
+	if err := s.db.Select("id", "employee_id").Limit(1).Find(&user, "id = ?", userID).Error; err != nil {
+		return nil, err
+	}
+	if user.EmployeeID == nil {
+		return nil, errors.New("employee not found")
+	}
+	return s.EmployeeBalances(companyID, *user.EmployeeID, asOf)
+}
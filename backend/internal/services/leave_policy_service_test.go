@@ -0,0 +1,166 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+
+	"backend/internal/dtos"
+	"backend/internal/models"
+)
+
+// leavePolicyTest is the seeded leave catalog of a company and an employee
+// hired 2022-03-01 with a portal user
+type leavePolicyTest struct {
+	db       *gorm.DB
+	service  *LeavePolicyService
+	company  *models.Company
+	employee *models.Employee
+	user     *models.User
+	policies map[string]*models.LeavePolicy // By code
+}
+
+func setupLeavePolicyTest(t *testing.T) *leavePolicyTest {
+	db := setupPayrollTestDB(t)
+	require.NoError(t, db.AutoMigrate(
+		&models.User{}, &models.AbsenceRequest{}, &models.IncidenceCategory{}, &models.IncidenceType{},
+		&models.Incidence{}, &models.IncidenceEvidence{}, &models.LeavePolicy{},
+	))
+	company := createPayrollTestCompany(t, db)
+	employee := createFixtureEmployee(t, db, company.ID, 1, "general", 400)
+	f := &leavePolicyTest{
+		db:       db,
+		service:  NewLeavePolicyService(db),
+		company:  company,
+		employee: employee,
+		user:     createFixtureUser(t, db, employee, "employee"),
+		policies: map[string]*models.LeavePolicy{},
+	}
+	policies, err := f.service.ListPolicies(company.ID)
+	require.NoError(t, err)
+	for i := range policies {
+		f.policies[policies[i].Code] = &policies[i]
+	}
+	return f
+}
+
+// unionizedMale makes the employee a unionized man
+func (f *leavePolicyTest) unionizedMale(t *testing.T) {
+	f.employee.Gender = "male"
+	f.employee.IsSindicalizado = true
+	require.NoError(t, f.db.Save(f.employee).Error)
+}
+
+// request files a leave of the employee
+func (f *leavePolicyTest) request(t *testing.T, policy *models.LeavePolicy, start time.Time, days float64, status models.RequestStatus) *models.AbsenceRequest {
+	r := &models.AbsenceRequest{EmployeeID: f.user.ID, RequestType: models.RequestTypeLeave, StartDate: start,
+		EndDate: start.AddDate(0, 0, int(days)-1), TotalDays: days, Reason: policy.Name, Status: status,
+		LeavePolicyID: &policy.ID, IncidenceTypeID: policy.IncidenceTypeID}
+	require.NoError(t, f.db.Create(r).Error)
+	return r
+}
+
+func leaveDay(m time.Month, d int) time.Time {
+	return time.Date(2024, m, d, 0, 0, 0, 0, time.UTC)
+}
+
+func TestLeavePolicy(t *testing.T) {
+	t.Run("the catalog is seeded once with its incidence types", func(t *testing.T) {
+		f := setupLeavePolicyTest(t)
+		require.Len(t, f.policies, 5)
+		policies, err := f.service.ListPolicies(f.company.ID)
+		require.NoError(t, err)
+		require.Len(t, policies, 5)
+
+		var maternityType models.IncidenceType
+		require.NoError(t, f.db.First(&maternityType, "id = ?", *f.policies["MATERNITY"].IncidenceTypeID).Error)
+		assert.Equal(t, "Licencia: Maternidad", maternityType.Name)
+		assert.Equal(t, "negative", maternityType.EffectType)
+		assert.True(t, maternityType.RequiresEvidence)
+	})
+
+	t.Run("codes are unique per company", func(t *testing.T) {
+		f := setupLeavePolicyTest(t)
+		_, err := f.service.CreatePolicy(f.company.ID, dtos.LeavePolicyRequest{Code: "paternity", Name: "Otra", EntitlementDays: 1,
+			EntitlementBasis: models.LeaveEntitlementPerEvent, PaidBy: models.LeavePaidByCompany})
+		assert.ErrorContains(t, err, "already exists")
+	})
+
+	t.Run("eligibility by gender, union status and seniority", func(t *testing.T) {
+		f := setupLeavePolicyTest(t)
+		assert.Contains(t, f.service.Eligibility(f.policies["PATERNITY"], f.employee, leaveDay(6, 1)), "only granted to male")
+		assert.Empty(t, f.service.Eligibility(f.policies["MATERNITY"], f.employee, leaveDay(6, 1)))
+		assert.Contains(t, f.service.Eligibility(f.policies["UNION_COMMISSION"], f.employee, leaveDay(6, 1)), "unionized")
+
+		f.unionizedMale(t)
+		assert.Empty(t, f.service.Eligibility(f.policies["PATERNITY"], f.employee, leaveDay(6, 1)))
+		senior, err := f.service.CreatePolicy(f.company.ID, dtos.LeavePolicyRequest{Code: "SENIOR", Name: "Antigüedad", EntitlementDays: 2,
+			EntitlementBasis: models.LeaveEntitlementPerEvent, MinServiceMonths: 27, PaidBy: models.LeaveUnpaid})
+		require.NoError(t, err)
+		assert.Contains(t, f.service.Eligibility(senior, f.employee, leaveDay(5, 31)), "26 completed")
+		assert.Empty(t, f.service.Eligibility(senior, f.employee, leaveDay(6, 1)))
+	})
+
+	t.Run("per_event allows at most the entitlement per request", func(t *testing.T) {
+		f := setupLeavePolicyTest(t)
+		f.unionizedMale(t)
+		paternity := f.policies["PATERNITY"]
+		assert.ErrorContains(t, f.service.CheckRequest(paternity, f.employee, leaveDay(6, 3), 6), "5.00 days per event")
+		assert.NoError(t, f.service.CheckRequest(paternity, f.employee, leaveDay(6, 3), 5))
+	})
+
+	t.Run("per_year counts approved and pending days of the year", func(t *testing.T) {
+		f := setupLeavePolicyTest(t)
+		f.unionizedMale(t)
+		union := f.policies["UNION_COMMISSION"]
+		first := f.request(t, union, leaveDay(2, 5), 6, models.RequestStatusApproved)
+		second := f.request(t, union, leaveDay(3, 5), 3, models.RequestStatusPending)
+
+		assert.ErrorContains(t, f.service.CheckRequest(union, f.employee, leaveDay(4, 1), 2), "1.00 days left")
+		assert.NoError(t, f.service.CheckRequest(union, f.employee, leaveDay(4, 1), 1))
+		assert.NoError(t, f.service.CheckRequest(union, f.employee, time.Date(2025, 1, 6, 0, 0, 0, 0, time.UTC), 10))
+
+		// The request being checked and the one it amends are not counted
+		assert.NoError(t, f.service.CheckRequest(union, f.employee, leaveDay(3, 5), 4, second.ID))
+		assert.NoError(t, f.service.CheckRequest(union, f.employee, leaveDay(1, 10), 2, first.ID, second.ID))
+	})
+
+	t.Run("balances show eligibility and the days left", func(t *testing.T) {
+		f := setupLeavePolicyTest(t)
+		f.unionizedMale(t)
+		f.request(t, f.policies["UNION_COMMISSION"], leaveDay(2, 5), 6, models.RequestStatusApproved)
+		f.request(t, f.policies["UNION_COMMISSION"], leaveDay(3, 5), 3, models.RequestStatusPending)
+
+		balances, err := f.service.Balances(f.employee, leaveDay(6, 1))
+		require.NoError(t, err)
+		byCode := map[string]dtos.LeaveBalance{}
+		for _, balance := range balances.Leaves {
+			byCode[balance.Code] = balance
+		}
+		union := byCode["UNION_COMMISSION"]
+		assert.True(t, union.Eligible)
+		assert.Equal(t, 6.0, union.UsedDays)
+		assert.Equal(t, 3.0, union.PendingDays)
+		assert.Equal(t, 1.0, union.RemainingDays)
+		assert.False(t, byCode["MATERNITY"].Eligible)
+		assert.Zero(t, byCode["MATERNITY"].RemainingDays)
+	})
+
+	t.Run("required documents are attached before approval", func(t *testing.T) {
+		f := setupLeavePolicyTest(t)
+		f.unionizedMale(t)
+		paternity := f.policies["PATERNITY"]
+		leave := f.request(t, paternity, leaveDay(6, 3), 5, models.RequestStatusPending)
+		assert.ErrorContains(t, f.service.CheckEvidence(paternity, leave), "Acta de nacimiento")
+
+		incidence := &models.Incidence{EmployeeID: f.employee.ID, IncidenceTypeID: *paternity.IncidenceTypeID,
+			StartDate: leave.StartDate, EndDate: leave.EndDate, Quantity: 5, Status: "pending", AbsenceRequestID: &leave.ID}
+		require.NoError(t, f.db.Create(incidence).Error)
+		require.NoError(t, f.db.Create(&models.IncidenceEvidence{IncidenceID: incidence.ID, FileName: "acta.pdf", OriginalName: "acta.pdf",
+			ContentType: "application/pdf", FileSize: 10, FilePath: "/tmp/acta.pdf", UploadedBy: f.user.ID}).Error)
+		assert.NoError(t, f.service.CheckEvidence(paternity, leave))
+	})
+}
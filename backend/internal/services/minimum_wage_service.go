@@ -0,0 +1,305 @@
+/*
+Package services - Minimum Wage Service
+
+==============================================================================
+FILE: internal/services/minimum_wage_service.go
+==============================================================================
+
+DESCRIPTION:
+    Enforces the minimum wage (salario mínimo) per employee zone: general vs
+    Zona Libre de la Frontera Norte (ZLFN), plus CONASAMI professional minimum
+    wages. Runs the annual bulk raise when the new year's minimum exceeds
+    current salaries, recording SalaryHistory and IMSS salary modifications.
+
+USER PERSPECTIVE:
+    - Employees cannot be created, updated or imported below their minimum wage
+    - Each January HR previews and applies the minimum wage raise in one step
+    - Minimum-wage earners have no ISR withheld and their net pay is protected
+
+DEVELOPER GUIDELINES:
+    ✅  OK to modify: Reason texts, raise filters
+    ⚠️  CAUTION: LISR Art. 96 exempts only the general minimum wage (not the
+        professional one) from ISR withholding
+    ❌  DO NOT modify: Salary floors here (values live in official_values.json)
+    📝  A nil config disables enforcement (tests, tools without configs)
+
+SYNTAX EXPLANATION:
+    - MinimumFor: zone SMG, or professional SMG when higher
+    - IsMinimumWageEarner: DailySalary <= zone general SMG
+    - BulkRaise: DryRun returns the preview without touching the database
+
+==============================================================================
+*/
+package services
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"backend/internal/config"
+	config_payroll "backend/internal/config/payroll"
+	"backend/internal/dtos"
+	"backend/internal/models"
+)
+
+// ErrBelowMinimumWage is returned when a daily salary is below the applicable minimum wage
+var ErrBelowMinimumWage = errors.New("daily salary is below the minimum wage")
+
+// MinimumWageService enforces minimum wages and runs the annual bulk raise
+type MinimumWageService struct {
+	db     *gorm.DB
+	config *config_payroll.PayrollConfig
+}
+
+// NewMinimumWageService creates a new MinimumWageService
+func NewMinimumWageService(db *gorm.DB, appConfig *config.AppConfig) *MinimumWageService {
+	var payrollConfig *config_payroll.PayrollConfig
+	if appConfig != nil {
+		payrollConfig = appConfig.PayrollConfig
+	}
+	return &MinimumWageService{
+		db:     db,
+		config: payrollConfig,
+	}
+}
+
+// GetInfo returns the configured minimum wages
+func (s *MinimumWageService) GetInfo() *dtos.MinimumWageInfo {
+	if s == nil || s.config == nil {
+		return &dtos.MinimumWageInfo{DefaultZone: models.MinimumWageZoneGeneral}
+	}
+	wages := s.config.OfficialValues.MinimumWages
+	defaultZone := wages.DefaultZone
+	if defaultZone == "" {
+		defaultZone = models.MinimumWageZoneGeneral
+	}
+	return &dtos.MinimumWageInfo{
+		DefaultZone:            defaultZone,
+		General:                wages.General.DailyValue,
+		NorthernBorderFreeZone: wages.NorthernBorderFreeZone.DailyValue,
+		Year:                   wages.General.Year,
+		EffectiveDate:          wages.General.EffectiveDate,
+		ProfessionalGeneral:    wages.General.ProfessionalDaily,
+		ProfessionalFrontier:   wages.NorthernBorderFreeZone.ProfessionalDaily,
+	}
+}
+
+// ZoneMinimum returns the general minimum wage of a zone (0 when not configured)
+func (s *MinimumWageService) ZoneMinimum(zone string) float64 {
+	if s == nil || s.config == nil {
+		return 0
+	}
+	if zone == "" {
+		zone = s.GetInfo().DefaultZone
+	}
+	smg, err := s.config.GetSMGForZone(zone)
+	if err != nil {
+		return 0
+	}
+	return smg
+}
+
+// MinimumFor returns the salary floor for a zone and professional occupation (0 when not configured)
+func (s *MinimumWageService) MinimumFor(zone, occupation string) float64 {
+	if s == nil || s.config == nil {
+		return 0
+	}
+	if zone == "" {
+		zone = s.GetInfo().DefaultZone
+	}
+	minimum, err := s.config.GetMinimumWage(zone, occupation)
+	if err != nil {
+		return 0
+	}
+	return minimum
+}
+
+// ValidateDailySalary returns ErrBelowMinimumWage when dailySalary is below the employee's floor
+func (s *MinimumWageService) ValidateDailySalary(zone, occupation string, dailySalary float64) error {
+	minimum := s.MinimumFor(zone, occupation)
+	if minimum > 0 && roundCurrency(dailySalary) < roundCurrency(minimum) {
+		return fmt.Errorf("%w: %.2f < %.2f MXN (%s)", ErrBelowMinimumWage, dailySalary, minimum, zoneLabel(zone, occupation))
+	}
+	return nil
+}
+
+// ValidateEmployee validates the employee's daily salary against their minimum wage
+func (s *MinimumWageService) ValidateEmployee(employee *models.Employee) error {
+	return s.ValidateDailySalary(employee.MinimumWageZone, employee.ProfessionalOccupation, employee.DailySalary)
+}
+
+// IsMinimumWageEarner reports whether the employee earns the general minimum wage of their zone
+// (LISR Art. 96: no ISR withholding for those who only earn the minimum wage)
+func (s *MinimumWageService) IsMinimumWageEarner(employee *models.Employee) bool {
+	minimum := s.ZoneMinimum(employee.MinimumWageZone)
+	return minimum > 0 && roundCurrency(employee.DailySalary) <= roundCurrency(minimum)
+}
+
+// BulkRaise raises every active employee of the company below the new minimum wage.
+// With DryRun the result is only a preview.
+func (s *MinimumWageService) BulkRaise(companyID uuid.UUID, req dtos.MinimumWageBulkRaiseRequest, appliedBy *uuid.UUID) (*dtos.MinimumWageBulkRaiseResponse, error) {
+	info := s.GetInfo()
+	general := req.GeneralDailyValue
+	if general == 0 {
+		general = info.General
+	}
+	frontier := req.NorthernBorderFreeZone
+	if frontier == 0 {
+		frontier = info.NorthernBorderFreeZone
+	}
+	if general <= 0 || frontier <= 0 {
+		return nil, errors.New("minimum wage values are not configured")
+	}
+
+	effectiveDate := req.EffectiveDate.ToTime()
+	response := &dtos.MinimumWageBulkRaiseResponse{
+		EffectiveDate:          effectiveDate,
+		GeneralMinimum:         general,
+		NorthernBorderFreeZone: frontier,
+		Applied:                !req.DryRun,
+		Raises:                 []dtos.MinimumWageRaiseItem{},
+	}
+
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		var employees []models.Employee
+		if err := tx.Where("company_id = ? AND employment_status IN ?", companyID, []string{"active", "on_leave"}).
+			Order("employee_number").
+			Find(&employees).Error; err != nil {
+			return fmt.Errorf("error fetching employees: %w", err)
+		}
+		response.EmployeesEvaluated = len(employees)
+
+		for i := range employees {
+			employee := &employees[i]
+			zone := employee.MinimumWageZone
+			if zone == "" {
+				zone = info.DefaultZone
+			}
+
+			newMinimum := general
+			if zone == models.MinimumWageZoneNorthernBorderFreeZone {
+				newMinimum = frontier
+			}
+			if professional := s.professionalFor(zone, employee.ProfessionalOccupation); professional > newMinimum {
+				newMinimum = professional
+			}
+			if roundCurrency(employee.DailySalary) >= roundCurrency(newMinimum) {
+				continue
+			}
+
+			oldSalary := employee.DailySalary
+			oldSDI := employee.IntegratedDailySalary
+			employee.DailySalary = roundCurrency(newMinimum)
+			employee.IntegratedDailySalary = 0
+			employee.IntegratedDailySalary = employee.CalculateIntegratedDailySalary()
+
+			item := dtos.MinimumWageRaiseItem{
+				EmployeeID:             employee.ID,
+				EmployeeNumber:         employee.EmployeeNumber,
+				FullName:               strings.TrimSpace(fmt.Sprintf("%s %s %s", employee.FirstName, employee.LastName, employee.MotherLastName)),
+				MinimumWageZone:        zone,
+				ProfessionalOccupation: employee.ProfessionalOccupation,
+				OldDailySalary:         oldSalary,
+				NewDailySalary:         employee.DailySalary,
+				OldSDI:                 oldSDI,
+				NewSDI:                 employee.IntegratedDailySalary,
+			}
+
+			if !req.DryRun {
+				employee.UpdatedBy = appliedBy
+				if err := tx.Save(employee).Error; err != nil {
+					return fmt.Errorf("could not update employee %s: %w", employee.EmployeeNumber, err)
+				}
+				reason := fmt.Sprintf("Incremento al salario mínimo %d (%s)", effectiveDate.Year(), zoneLabel(zone, employee.ProfessionalOccupation))
+				history, movement, err := recordSalaryChange(tx, employee, oldSalary, oldSDI, effectiveDate, reason, appliedBy)
+				if err != nil {
+					return err
+				}
+				item.SalaryHistoryID = &history.ID
+				if movement != nil {
+					item.IMSSMovementID = &movement.ID
+				}
+			}
+
+			response.Raises = append(response.Raises, item)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	response.EmployeesRaised = len(response.Raises)
+	return response, nil
+}
+
+// professionalFor returns the professional minimum wage for an occupation (0 when none)
+func (s *MinimumWageService) professionalFor(zone, occupation string) float64 {
+	if s == nil || s.config == nil {
+		return 0
+	}
+	value, _ := s.config.GetProfessionalSMG(zone, occupation)
+	return value
+}
+
+// recordSalaryChange appends the SalaryHistory record and, when the SDI changed,
+// the IMSS salary modification to report through IDSE
+func recordSalaryChange(tx *gorm.DB, employee *models.Employee, oldSalary, oldSDI float64, effectiveDate time.Time, reason string, recordedBy *uuid.UUID) (*models.SalaryHistory, *models.IMSSMovement, error) {
+	history := &models.SalaryHistory{
+		EmployeeID:     employee.ID,
+		EffectiveDate:  effectiveDate,
+		OldDailySalary: oldSalary,
+		NewDailySalary: employee.DailySalary,
+		Reason:         reason,
+		RecordedBy:     recordedBy,
+	}
+	if err := tx.Create(history).Error; err != nil {
+		return nil, nil, fmt.Errorf("could not record salary history: %w", err)
+	}
+
+	if roundCurrency(oldSDI) == roundCurrency(employee.IntegratedDailySalary) {
+		return history, nil, nil
+	}
+
+	movement := &models.IMSSMovement{
+		EmployeeID:          employee.ID,
+		CompanyID:           employee.CompanyID,
+		MovementType:        models.IMSSMovementSalaryModification,
+		MovementCode:        models.IMSSMovementCodes[models.IMSSMovementSalaryModification],
+		EffectiveDate:       effectiveDate,
+		PreviousDailySalary: oldSalary,
+		NewDailySalary:      employee.DailySalary,
+		PreviousSDI:         oldSDI,
+		NewSDI:              employee.IntegratedDailySalary,
+		Reason:              reason,
+		Status:              "pending",
+		SalaryHistoryID:     &history.ID,
+		CreatedBy:           recordedBy,
+	}
+	if err := tx.Create(movement).Error; err != nil {
+		return nil, nil, fmt.Errorf("could not record IMSS salary modification: %w", err)
+	}
+	return history, movement, nil
+}
+
+// zoneLabel returns a readable label for error messages and reasons
+func zoneLabel(zone, occupation string) string {
+	label := "general"
+	if zone == models.MinimumWageZoneNorthernBorderFreeZone {
+		label = "ZLFN"
+	}
+	if occupation != "" {
+		label += ", profesional " + occupation
+	}
+	return label
+}
+
+// roundCurrency rounds to cents so floating point noise does not fail comparisons
+func roundCurrency(value float64) float64 {
+	return float64(int64(value*100+0.5)) / 100
+}
@@ -0,0 +1,119 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+
+	"backend/internal/config"
+	config_payroll "backend/internal/config/payroll"
+	"backend/internal/config/payroll/types"
+	"backend/internal/dtos"
+	"backend/internal/models"
+)
+
+// newTestMinimumWageService builds a service with 2025 minimum wages (general 278.80, ZLFN 419.88)
+func newTestMinimumWageService(db *gorm.DB) *MinimumWageService {
+	payrollConfig := &config_payroll.PayrollConfig{}
+	payrollConfig.OfficialValues.MinimumWages = types.MinimumWages{
+		DefaultZone: "general",
+		General: types.MinimumWageZone{
+			DailyValue:        278.80,
+			ProfessionalDaily: map[string]float64{"chofer": 320.00},
+		},
+		NorthernBorderFreeZone: types.MinimumWageZone{DailyValue: 419.88},
+	}
+	return NewMinimumWageService(db, &config.AppConfig{PayrollConfig: payrollConfig})
+}
+
+func TestMinimumWage_ValidateDailySalary(t *testing.T) {
+	service := newTestMinimumWageService(nil)
+
+	assert.NoError(t, service.ValidateDailySalary("general", "", 278.80))
+	assert.ErrorIs(t, service.ValidateDailySalary("general", "", 250.00), ErrBelowMinimumWage)
+	assert.ErrorIs(t, service.ValidateDailySalary("northern_border_free_zone", "", 300.00), ErrBelowMinimumWage)
+	assert.ErrorIs(t, service.ValidateDailySalary("general", "chofer", 300.00), ErrBelowMinimumWage)
+
+	// No config: enforcement disabled
+	assert.NoError(t, NewMinimumWageService(nil, nil).ValidateDailySalary("general", "", 1))
+}
+
+func TestMinimumWage_BulkRaise(t *testing.T) {
+	db := setupPayrollTestDB(t)
+	require.NoError(t, db.AutoMigrate(&models.SalaryHistory{}, &models.IMSSMovement{}))
+	company := createPayrollTestCompany(t, db)
+	service := newTestMinimumWageService(db)
+
+	below := createFixtureEmployee(t, db, company.ID, 1, "general", 248.93)
+	frontier := createFixtureEmployee(t, db, company.ID, 2, "northern_border_free_zone", 374.89)
+	createFixtureEmployee(t, db, company.ID, 3, "general", 500.00)
+
+	req := dtos.MinimumWageBulkRaiseRequest{
+		EffectiveDate: dtos.Date{Time: time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)},
+		DryRun:        true,
+	}
+	preview, err := service.BulkRaise(company.ID, req, nil)
+	require.NoError(t, err)
+	assert.Equal(t, 3, preview.EmployeesEvaluated)
+	assert.Equal(t, 2, preview.EmployeesRaised)
+	assert.False(t, preview.Applied)
+
+	var historyCount int64
+	db.Model(&models.SalaryHistory{}).Count(&historyCount)
+	assert.Equal(t, int64(0), historyCount, "dry run must not write")
+
+	req.DryRun = false
+	result, err := service.BulkRaise(company.ID, req, nil)
+	require.NoError(t, err)
+	assert.Equal(t, 2, result.EmployeesRaised)
+
+	var raisedGeneral, raisedFrontier models.Employee
+	require.NoError(t, db.First(&raisedGeneral, "id = ?", below.ID).Error)
+	assert.Equal(t, 278.80, raisedGeneral.DailySalary)
+	require.NoError(t, db.First(&raisedFrontier, "id = ?", frontier.ID).Error)
+	assert.Equal(t, 419.88, raisedFrontier.DailySalary)
+
+	db.Model(&models.SalaryHistory{}).Count(&historyCount)
+	assert.Equal(t, int64(2), historyCount)
+
+	var movements []models.IMSSMovement
+	require.NoError(t, db.Find(&movements).Error)
+	require.Len(t, movements, 2)
+	assert.Equal(t, "07", movements[0].MovementCode)
+	assert.Greater(t, movements[0].NewSDI, movements[0].PreviousSDI)
+
+	// Running again finds nobody below the minimum
+	again, err := service.BulkRaise(company.ID, req, nil)
+	require.NoError(t, err)
+	assert.Equal(t, 0, again.EmployeesRaised)
+}
+
+func TestPayroll_MinimumWageEarnerISRExemptAndNetProtected(t *testing.T) {
+	db := setupPayrollTestDB(t)
+	company := createPayrollTestCompany(t, db)
+	employee := createFixtureEmployee(t, db, company.ID, 1, "general", 278.80)
+	period := createPayrollTestPeriod(t, db, "weekly")
+
+	taxService, _ := NewTaxCalculationService("nonexistent")
+	service := &PayrollService{
+		db:             db,
+		taxCalcService: taxService,
+		minimumWage:    newTestMinimumWageService(db),
+	}
+
+	payrollCalc := &models.PayrollCalculation{RegularSalary: 278.80 * 7}
+	service.CalculateStatutoryDeductions(payrollCalc, employee, period)
+	assert.True(t, payrollCalc.MinimumWageEarner)
+	assert.Equal(t, 0.0, payrollCalc.ISRWithholding)
+
+	// A loan that would leave the employee below the minimum wage is partially deferred
+	payrollCalc.LoanDeductions = 1000.00
+	service.CalculateTotals(payrollCalc)
+	service.ApplyMinimumWageProtection(payrollCalc, employee, 7)
+
+	assert.InDelta(t, 278.80*7-payrollCalc.TotalStatutoryDeductions, payrollCalc.TotalNetPay, 0.01)
+	assert.InDelta(t, 1000.00, payrollCalc.LoanDeductions+payrollCalc.DeferredDeductions, 0.01)
+}
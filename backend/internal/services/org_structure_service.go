@@ -0,0 +1,670 @@
+/*
+Package services - Org Structure Service
+
+==============================================================================
+FILE: internal/services/org_structure_service.go
+==============================================================================
+
+DESCRIPTION:
+    Single source of truth for reporting lines. Keeps the effective-dated
+    manager history, detects cycles, maintains the EmployeeHierarchy closure
+    table for one-query subtree reads, builds the org chart and resolves who
+    approves for an employee (skipping managers who are on leave).
+
+USER PERSPECTIVE:
+    - Managers see their whole team in lists and the calendar
+    - Requests from the team of a manager on vacation go to the next manager up
+    - HR sees a nested org chart with headcount per manager
+
+DEVELOPER GUIDELINES:
+    ✅  OK to modify: Leave types that trigger delegation
+    ⚠️  CAUTION: Change managers through SetManager/SyncEmployeeManager so the
+        closure table, Employee.SupervisorID and User.SupervisorID stay aligned
+    ❌  DO NOT modify: employee_hierarchy rows by hand (use RebuildHierarchy)
+    📝  ApplyDueReportingLines runs in the scheduled_changes job (leader only); safe to repeat
+
+SYNTAX EXPLANATION:
+    - Moving a subtree: delete the links between the subtree and its old
+      ancestors, then link every new ancestor (manager included) with every
+      subtree node at depth = ancestor depth + node depth + 1
+    - A manager is "on leave" with an approved full-day absence request
+      (vacation, paid/unpaid leave, sick leave, personal) covering the date
+    - AbsenceRequest.EmployeeID holds a user ID; both IDs are matched
+
+==============================================================================
+*/
+package services
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"backend/internal/dtos"
+	"backend/internal/models"
+)
+
+// ErrReportingCycle is returned when a manager change would make an employee report to their own subtree
+var ErrReportingCycle = errors.New("reporting line would create a cycle")
+
+// leaveRequestTypes are the absence types that take a manager out of the approval chain
+var leaveRequestTypes = []models.RequestType{
+	models.RequestTypeVacation,
+	models.RequestTypePaidLeave,
+	models.RequestTypeUnpaidLeave,
+	models.RequestTypeSickLeave,
+	models.RequestTypePersonal,
+	models.RequestTypeLeave,
+}
+
+// OrgStructureService manages reporting lines and the org hierarchy
+type OrgStructureService struct {
+	db *gorm.DB
+}
+
+// NewOrgStructureService creates a new OrgStructureService
+func NewOrgStructureService(db *gorm.DB) *OrgStructureService {
+	return &OrgStructureService{db: db}
+}
+
+// =========================================================================
+// Reporting lines
+// =========================================================================
+
+// SetManager changes an employee's manager from a date. Dates up to today
+// are applied immediately; future dates stay pending.
+func (s *OrgStructureService) SetManager(companyID uuid.UUID, req dtos.ReportingLineRequest, createdBy uuid.UUID) (*models.ReportingLine, error) {
+	var employee models.Employee
+	if err := s.db.Where("id = ? AND company_id = ?", req.EmployeeID, companyID).First(&employee).Error; err != nil {
+		return nil, errors.New("employee not found")
+	}
+	if req.ManagerID != nil {
+		if *req.ManagerID == employee.ID {
+			return nil, errors.New("employee cannot be their own manager")
+		}
+		var count int64
+		if err := s.db.Model(&models.Employee{}).Where("id = ? AND company_id = ?", *req.ManagerID, companyID).Count(&count).Error; err != nil {
+			return nil, err
+		}
+		if count == 0 {
+			return nil, errors.New("manager not found")
+		}
+		cycle, err := s.isInSubtree(s.db, employee.ID, *req.ManagerID)
+		if err != nil {
+			return nil, err
+		}
+		if cycle {
+			return nil, ErrReportingCycle
+		}
+	}
+
+	line := &models.ReportingLine{
+		CompanyID:     companyID,
+		EmployeeID:    employee.ID,
+		ManagerID:     req.ManagerID,
+		EffectiveFrom: truncateToDate(req.EffectiveFrom.ToTime()),
+		Reason:        strings.TrimSpace(req.Reason),
+		CreatedBy:     &createdBy,
+	}
+
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		// A new line supersedes pending lines on or after its date
+		if err := tx.Where("employee_id = ? AND applied = ? AND effective_from >= ?", employee.ID, false, line.EffectiveFrom).
+			Delete(&models.ReportingLine{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Create(line).Error; err != nil {
+			return err
+		}
+		if line.EffectiveFrom.After(truncateToDate(time.Now())) {
+			return nil
+		}
+		return s.applyReportingLine(tx, line)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return line, nil
+}
+
+// ListReportingLines returns an employee's manager history, newest first
+func (s *OrgStructureService) ListReportingLines(employeeID, companyID uuid.UUID) ([]models.ReportingLine, error) {
+	var lines []models.ReportingLine
+	err := s.db.Preload("Manager").
+		Where("employee_id = ? AND company_id = ?", employeeID, companyID).
+		Order("effective_from DESC").
+		Find(&lines).Error
+	if err != nil {
+		return nil, fmt.Errorf("error fetching reporting lines: %w", err)
+	}
+	return lines, nil
+}
+
+// ApplyDueReportingLines applies pending reporting lines whose effective date has arrived
+func (s *OrgStructureService) ApplyDueReportingLines() (int, error) {
+	var due []models.ReportingLine
+	if err := s.db.Where("applied = ? AND effective_from <= ?", false, truncateToDate(time.Now())).
+		Order("effective_from").Find(&due).Error; err != nil {
+		return 0, err
+	}
+
+	applied := 0
+	for i := range due {
+		if err := s.db.Transaction(func(tx *gorm.DB) error {
+			return s.applyReportingLine(tx, &due[i])
+		}); err != nil {
+			return applied, fmt.Errorf("error applying reporting line %s: %w", due[i].ID, err)
+		}
+		applied++
+	}
+	return applied, nil
+}
+
+// SyncEmployeeManager records a manager set directly on the employee record
+// (create/update forms) as today's reporting line and updates the hierarchy
+func (s *OrgStructureService) SyncEmployeeManager(tx *gorm.DB, employee *models.Employee, createdBy *uuid.UUID) error {
+	if employee.SupervisorID != nil {
+		cycle, err := s.isInSubtree(tx, employee.ID, *employee.SupervisorID)
+		if err != nil {
+			return err
+		}
+		if cycle {
+			return ErrReportingCycle
+		}
+	}
+
+	today := truncateToDate(time.Now())
+	if err := tx.Model(&models.ReportingLine{}).
+		Where("employee_id = ? AND applied = ? AND effective_to IS NULL", employee.ID, true).
+		Update("effective_to", today.AddDate(0, 0, -1)).Error; err != nil {
+		return err
+	}
+	if err := tx.Create(&models.ReportingLine{
+		CompanyID:     employee.CompanyID,
+		EmployeeID:    employee.ID,
+		ManagerID:     employee.SupervisorID,
+		EffectiveFrom: today,
+		Applied:       true,
+		Reason:        "Actualización de datos del empleado",
+		CreatedBy:     createdBy,
+	}).Error; err != nil {
+		return err
+	}
+	if err := s.moveSubtree(tx, employee.CompanyID, employee.ID, employee.SupervisorID); err != nil {
+		return err
+	}
+	return syncUserSupervisor(tx, employee.ID, employee.SupervisorID)
+}
+
+// applyReportingLine closes the current line and moves the employee's subtree under the new manager
+func (s *OrgStructureService) applyReportingLine(tx *gorm.DB, line *models.ReportingLine) error {
+	if line.ManagerID != nil {
+		cycle, err := s.isInSubtree(tx, line.EmployeeID, *line.ManagerID)
+		if err != nil {
+			return err
+		}
+		if cycle {
+			return ErrReportingCycle
+		}
+	}
+
+	if err := tx.Model(&models.ReportingLine{}).
+		Where("employee_id = ? AND id <> ? AND applied = ? AND effective_to IS NULL", line.EmployeeID, line.ID, true).
+		Update("effective_to", line.EffectiveFrom.AddDate(0, 0, -1)).Error; err != nil {
+		return err
+	}
+	line.Applied = true
+	if err := tx.Model(line).Update("applied", true).Error; err != nil {
+		return err
+	}
+
+	// UpdateColumn skips Employee hooks; only the supervisor reference changes
+	if err := tx.Model(&models.Employee{}).Where("id = ?", line.EmployeeID).
+		UpdateColumn("supervisor_id", line.ManagerID).Error; err != nil {
+		return err
+	}
+	if err := s.moveSubtree(tx, line.CompanyID, line.EmployeeID, line.ManagerID); err != nil {
+		return err
+	}
+	return syncUserSupervisor(tx, line.EmployeeID, line.ManagerID)
+}
+
+// syncUserSupervisor mirrors the reporting line on the portal users (User.SupervisorID)
+func syncUserSupervisor(tx *gorm.DB, employeeID uuid.UUID, managerID *uuid.UUID) error {
+	var managerUserID *uuid.UUID
+	if managerID != nil {
+		var managerUser models.User
+		if err := tx.Where("employee_id = ?", *managerID).First(&managerUser).Error; err == nil {
+			managerUserID = &managerUser.ID
+		}
+	}
+	if managerID != nil && managerUserID == nil {
+		// Manager has no portal account; keep whatever supervisor the user had
+		return nil
+	}
+	return tx.Model(&models.User{}).Where("employee_id = ?", employeeID).
+		UpdateColumn("supervisor_id", managerUserID).Error
+}
+
+// =========================================================================
+// Closure table
+// =========================================================================
+
+// ensureNode makes sure the employee has its depth-0 row
+func ensureNode(tx *gorm.DB, companyID, employeeID uuid.UUID) error {
+	node := models.EmployeeHierarchy{AncestorID: employeeID, DescendantID: employeeID, CompanyID: companyID}
+	return tx.Where(models.EmployeeHierarchy{AncestorID: employeeID, DescendantID: employeeID}).
+		FirstOrCreate(&node).Error
+}
+
+// moveSubtree re-parents the employee (and everyone below) under newManagerID
+func (s *OrgStructureService) moveSubtree(tx *gorm.DB, companyID, employeeID uuid.UUID, newManagerID *uuid.UUID) error {
+	if err := ensureNode(tx, companyID, employeeID); err != nil {
+		return err
+	}
+
+	var subtree []models.EmployeeHierarchy
+	if err := tx.Where("ancestor_id = ?", employeeID).Find(&subtree).Error; err != nil {
+		return err
+	}
+	subtreeIDs := make([]uuid.UUID, len(subtree))
+	for i, row := range subtree {
+		subtreeIDs[i] = row.DescendantID
+	}
+
+	var oldAncestorIDs []uuid.UUID
+	if err := tx.Model(&models.EmployeeHierarchy{}).
+		Where("descendant_id = ? AND depth > 0", employeeID).
+		Pluck("ancestor_id", &oldAncestorIDs).Error; err != nil {
+		return err
+	}
+	if len(oldAncestorIDs) > 0 {
+		if err := tx.Where("descendant_id IN ? AND ancestor_id IN ?", subtreeIDs, oldAncestorIDs).
+			Delete(&models.EmployeeHierarchy{}).Error; err != nil {
+			return err
+		}
+	}
+	if newManagerID == nil {
+		return nil
+	}
+
+	if err := ensureNode(tx, companyID, *newManagerID); err != nil {
+		return err
+	}
+	var newAncestors []models.EmployeeHierarchy
+	if err := tx.Where("descendant_id = ?", *newManagerID).Find(&newAncestors).Error; err != nil {
+		return err
+	}
+
+	links := make([]models.EmployeeHierarchy, 0, len(newAncestors)*len(subtree))
+	for _, ancestor := range newAncestors {
+		for _, node := range subtree {
+			links = append(links, models.EmployeeHierarchy{
+				AncestorID:   ancestor.AncestorID,
+				DescendantID: node.DescendantID,
+				Depth:        ancestor.Depth + node.Depth + 1,
+				CompanyID:    companyID,
+			})
+		}
+	}
+	return tx.CreateInBatches(links, 500).Error
+}
+
+// RebuildHierarchy regenerates the company's closure table from Employee.SupervisorID.
+// Links that would close a cycle are ignored.
+func (s *OrgStructureService) RebuildHierarchy(companyID uuid.UUID) (int, error) {
+	type node struct {
+		ID           uuid.UUID
+		SupervisorID *uuid.UUID
+	}
+	var nodes []node
+	if err := s.db.Model(&models.Employee{}).Select("id, supervisor_id").
+		Where("company_id = ?", companyID).Scan(&nodes).Error; err != nil {
+		return 0, err
+	}
+	parent := make(map[uuid.UUID]uuid.UUID, len(nodes))
+	for _, n := range nodes {
+		if n.SupervisorID != nil {
+			parent[n.ID] = *n.SupervisorID
+		}
+	}
+
+	var rows []models.EmployeeHierarchy
+	for _, n := range nodes {
+		rows = append(rows, models.EmployeeHierarchy{AncestorID: n.ID, DescendantID: n.ID, CompanyID: companyID})
+		seen := map[uuid.UUID]bool{n.ID: true}
+		depth := 1
+		for current, ok := parent[n.ID]; ok && !seen[current]; current, ok = parent[current] {
+			seen[current] = true
+			rows = append(rows, models.EmployeeHierarchy{AncestorID: current, DescendantID: n.ID, Depth: depth, CompanyID: companyID})
+			depth++
+		}
+	}
+
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("company_id = ?", companyID).Delete(&models.EmployeeHierarchy{}).Error; err != nil {
+			return err
+		}
+		if len(rows) == 0 {
+			return nil
+		}
+		return tx.CreateInBatches(rows, 500).Error
+	})
+	if err != nil {
+		return 0, fmt.Errorf("error rebuilding hierarchy: %w", err)
+	}
+	return len(rows), nil
+}
+
+// EnsureHierarchies rebuilds the closure table of companies that have employees but no rows yet
+func (s *OrgStructureService) EnsureHierarchies() (int, error) {
+	var companyIDs []uuid.UUID
+	if err := s.db.Model(&models.Employee{}).Distinct("company_id").
+		Where("company_id NOT IN (?)", s.db.Model(&models.EmployeeHierarchy{}).Select("company_id")).
+		Pluck("company_id", &companyIDs).Error; err != nil {
+		return 0, err
+	}
+	for _, companyID := range companyIDs {
+		if _, err := s.RebuildHierarchy(companyID); err != nil {
+			return 0, err
+		}
+	}
+	return len(companyIDs), nil
+}
+
+// isInSubtree reports whether candidateID is rootID or one of its descendants
+func (s *OrgStructureService) isInSubtree(tx *gorm.DB, rootID, candidateID uuid.UUID) (bool, error) {
+	if rootID == candidateID {
+		return true, nil
+	}
+	var count int64
+	if err := tx.Model(&models.EmployeeHierarchy{}).
+		Where("ancestor_id = ? AND descendant_id = ?", rootID, candidateID).
+		Count(&count).Error; err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
+
+// =========================================================================
+// Queries
+// =========================================================================
+
+// SubordinateIDs returns the employees below a manager (direct reports only when directOnly)
+func (s *OrgStructureService) SubordinateIDs(managerID uuid.UUID, directOnly bool) ([]uuid.UUID, error) {
+	query := s.db.Model(&models.EmployeeHierarchy{}).Where("ancestor_id = ? AND depth > 0", managerID)
+	if directOnly {
+		query = query.Where("depth = 1")
+	}
+	var ids []uuid.UUID
+	if err := query.Order("depth").Pluck("descendant_id", &ids).Error; err != nil {
+		return nil, fmt.Errorf("error fetching subordinates: %w", err)
+	}
+	return ids, nil
+}
+
+// ManagerChain returns the employee's managers, nearest first
+func (s *OrgStructureService) ManagerChain(employeeID uuid.UUID) ([]uuid.UUID, error) {
+	var ids []uuid.UUID
+	if err := s.db.Model(&models.EmployeeHierarchy{}).
+		Where("descendant_id = ? AND depth > 0", employeeID).
+		Order("depth").Pluck("ancestor_id", &ids).Error; err != nil {
+		return nil, fmt.Errorf("error fetching managers: %w", err)
+	}
+	return ids, nil
+}
+
+// GetOrgChart returns the nested org chart of active employees, from rootID or from every top manager
+func (s *OrgStructureService) GetOrgChart(companyID uuid.UUID, rootID *uuid.UUID) ([]dtos.OrgChartNode, error) {
+	var employees []models.Employee
+	if err := s.db.Where("company_id = ? AND employment_status <> ?", companyID, "terminated").
+		Order("employee_number").Find(&employees).Error; err != nil {
+		return nil, fmt.Errorf("error fetching employees: %w", err)
+	}
+	active := make(map[uuid.UUID]bool, len(employees))
+	for _, e := range employees {
+		active[e.ID] = true
+	}
+
+	// Headcount per manager straight from the closure table
+	type headcountRow struct {
+		AncestorID uuid.UUID
+		Total      int
+	}
+	var counts []headcountRow
+	if err := s.db.Table("employee_hierarchy AS h").
+		Select("h.ancestor_id, COUNT(*) AS total").
+		Joins("JOIN employees e ON e.id = h.descendant_id").
+		Where("h.company_id = ? AND h.depth > 0 AND e.employment_status <> ? AND e.deleted_at IS NULL", companyID, "terminated").
+		Group("h.ancestor_id").
+		Scan(&counts).Error; err != nil {
+		return nil, fmt.Errorf("error counting headcount: %w", err)
+	}
+	headcount := make(map[uuid.UUID]int, len(counts))
+	for _, c := range counts {
+		headcount[c.AncestorID] = c.Total
+	}
+
+	positionTitles := make(map[uuid.UUID]string)
+	var positions []models.Position
+	if err := s.db.Where("company_id = ?", companyID).Find(&positions).Error; err != nil {
+		return nil, fmt.Errorf("error fetching positions: %w", err)
+	}
+	for _, p := range positions {
+		positionTitles[p.ID] = p.Title
+	}
+	departmentNames := make(map[uuid.UUID]string)
+	var departments []models.Department
+	if err := s.db.Where("company_id = ?", companyID).Find(&departments).Error; err != nil {
+		return nil, fmt.Errorf("error fetching departments: %w", err)
+	}
+	for _, d := range departments {
+		departmentNames[d.ID] = d.Name
+	}
+
+	children := make(map[uuid.UUID][]models.Employee)
+	var roots []models.Employee
+	for _, e := range employees {
+		if e.SupervisorID != nil && active[*e.SupervisorID] {
+			children[*e.SupervisorID] = append(children[*e.SupervisorID], e)
+		} else if rootID == nil {
+			roots = append(roots, e)
+		}
+		if rootID != nil && e.ID == *rootID {
+			roots = append(roots, e)
+		}
+	}
+	if rootID != nil && len(roots) == 0 {
+		return nil, errors.New("employee not found")
+	}
+
+	var build func(e models.Employee, visited map[uuid.UUID]bool) dtos.OrgChartNode
+	build = func(e models.Employee, visited map[uuid.UUID]bool) dtos.OrgChartNode {
+		visited[e.ID] = true
+		node := dtos.OrgChartNode{
+			EmployeeID:     e.ID,
+			EmployeeNumber: e.EmployeeNumber,
+			FullName:       strings.TrimSpace(fmt.Sprintf("%s %s %s", e.FirstName, e.LastName, e.MotherLastName)),
+			PositionID:     e.PositionID,
+			DepartmentID:   e.DepartmentID,
+			DirectReports:  len(children[e.ID]),
+			Headcount:      headcount[e.ID],
+		}
+		if e.PositionID != nil {
+			node.PositionTitle = positionTitles[*e.PositionID]
+		}
+		if e.DepartmentID != nil {
+			node.DepartmentName = departmentNames[*e.DepartmentID]
+		}
+		for _, child := range children[e.ID] {
+			if !visited[child.ID] {
+				node.Children = append(node.Children, build(child, visited))
+			}
+		}
+		return node
+	}
+
+	chart := make([]dtos.OrgChartNode, 0, len(roots))
+	visited := make(map[uuid.UUID]bool)
+	for _, root := range roots {
+		chart = append(chart, build(root, visited))
+	}
+	return chart, nil
+}
+
+// =========================================================================
+// Approver resolution
+// =========================================================================
+
+// ResolveApprover returns who approves for an employee on a date: the nearest
+// manager with a portal account who is not on leave
+func (s *OrgStructureService) ResolveApprover(employeeID uuid.UUID, date time.Time) (*dtos.ApproverResolution, error) {
+	chain, err := s.ManagerChain(employeeID)
+	if err != nil {
+		return nil, err
+	}
+	resolution := &dtos.ApproverResolution{EmployeeID: employeeID}
+	if len(chain) == 0 {
+		return resolution, nil
+	}
+	resolution.DirectManagerID = &chain[0]
+
+	usersByEmployee, err := s.userIDsByEmployee(chain)
+	if err != nil {
+		return nil, err
+	}
+	onLeave, err := s.onLeaveEmployees(chain, usersByEmployee, date)
+	if err != nil {
+		return nil, err
+	}
+	for i := range chain {
+		managerID := chain[i]
+		userID, hasUser := usersByEmployee[managerID]
+		if !hasUser || onLeave[managerID] {
+			resolution.SkippedManagerIDs = append(resolution.SkippedManagerIDs, managerID)
+			continue
+		}
+		resolution.ApproverEmployeeID = &chain[i]
+		resolution.ApproverUserID = &userID
+		break
+	}
+	resolution.Delegated = len(resolution.SkippedManagerIDs) > 0 && resolution.ApproverUserID != nil
+	return resolution, nil
+}
+
+// ResolveApproverForUser resolves the approver for a portal user (nil when the user has no employee record)
+func (s *OrgStructureService) ResolveApproverForUser(userID uuid.UUID, date time.Time) (*dtos.ApproverResolution, error) {
+	var user models.User
+	if err := s.db.Select("id, employee_id").First(&user, "id = ?", userID).Error; err != nil || user.EmployeeID == nil {
+		return nil, nil
+	}
+	return s.ResolveApprover(*user.EmployeeID, date)
+}
+
+// ApprovalScopeUserIDs returns the portal users whose requests this approver
+// handles on a date: their reports, plus the reports of managers below them
+// who are on leave (or have no portal account)
+func (s *OrgStructureService) ApprovalScopeUserIDs(approverUserID uuid.UUID, date time.Time) ([]uuid.UUID, error) {
+	var approver models.User
+	if err := s.db.Select("id, employee_id").First(&approver, "id = ?", approverUserID).Error; err != nil || approver.EmployeeID == nil {
+		return nil, nil
+	}
+	approverEmployeeID := *approver.EmployeeID
+
+	descendants, err := s.SubordinateIDs(approverEmployeeID, false)
+	if err != nil || len(descendants) == 0 {
+		return nil, err
+	}
+
+	type node struct {
+		ID           uuid.UUID
+		SupervisorID *uuid.UUID
+	}
+	var nodes []node
+	if err := s.db.Model(&models.Employee{}).Select("id, supervisor_id").Where("id IN ?", descendants).Scan(&nodes).Error; err != nil {
+		return nil, err
+	}
+	parent := make(map[uuid.UUID]uuid.UUID, len(nodes))
+	for _, n := range nodes {
+		if n.SupervisorID != nil {
+			parent[n.ID] = *n.SupervisorID
+		}
+	}
+	usersByEmployee, err := s.userIDsByEmployee(descendants)
+	if err != nil {
+		return nil, err
+	}
+	onLeave, err := s.onLeaveEmployees(descendants, usersByEmployee, date)
+	if err != nil {
+		return nil, err
+	}
+
+	var scope []uuid.UUID
+	for _, employeeID := range descendants {
+		userID, hasUser := usersByEmployee[employeeID]
+		if !hasUser {
+			continue
+		}
+		// Every manager between the approver and the employee must be unavailable
+		handled := true
+		for m, ok := parent[employeeID]; ok && m != approverEmployeeID; m, ok = parent[m] {
+			if _, managerHasUser := usersByEmployee[m]; managerHasUser && !onLeave[m] {
+				handled = false
+				break
+			}
+		}
+		if handled {
+			scope = append(scope, userID)
+		}
+	}
+	return scope, nil
+}
+
+// userIDsByEmployee maps employee IDs to their portal user IDs
+func (s *OrgStructureService) userIDsByEmployee(employeeIDs []uuid.UUID) (map[uuid.UUID]uuid.UUID, error) {
+	var users []models.User
+	if err := s.db.Select("id, employee_id").Where("employee_id IN ? AND is_active = ?", employeeIDs, true).Find(&users).Error; err != nil {
+		return nil, fmt.Errorf("error fetching portal users: %w", err)
+	}
+	result := make(map[uuid.UUID]uuid.UUID, len(users))
+	for _, u := range users {
+		if u.EmployeeID != nil {
+			result[*u.EmployeeID] = u.ID
+		}
+	}
+	return result, nil
+}
+
+// onLeaveEmployees returns which employees have an approved leave covering the date
+func (s *OrgStructureService) onLeaveEmployees(employeeIDs []uuid.UUID, usersByEmployee map[uuid.UUID]uuid.UUID, date time.Time) (map[uuid.UUID]bool, error) {
+	requesterIDs := append([]uuid.UUID{}, employeeIDs...)
+	employeeByUser := make(map[uuid.UUID]uuid.UUID, len(usersByEmployee))
+	for employeeID, userID := range usersByEmployee {
+		requesterIDs = append(requesterIDs, userID)
+		employeeByUser[userID] = employeeID
+	}
+
+	day := truncateToDate(date)
+	var requesters []uuid.UUID
+	if err := s.db.Model(&models.AbsenceRequest{}).
+		Where("employee_id IN ? AND status = ? AND request_type IN ? AND start_date < ? AND end_date >= ?",
+			requesterIDs, models.RequestStatusApproved, leaveRequestTypes, day.AddDate(0, 0, 1), day).
+		Scopes(timeOffRequests).
+		Distinct().Pluck("employee_id", &requesters).Error; err != nil {
+		return nil, fmt.Errorf("error fetching approved leaves: %w", err)
+	}
+
+	onLeave := make(map[uuid.UUID]bool, len(requesters))
+	for _, id := range requesters {
+		if employeeID, ok := employeeByUser[id]; ok {
+			onLeave[employeeID] = true
+		} else {
+			onLeave[id] = true
+		}
+	}
+	return onLeave, nil
+}
@@ -0,0 +1,89 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"backend/internal/dtos"
+	"backend/internal/models"
+)
+
+func TestOrgStructure_HierarchyAndDelegation(t *testing.T) {
+	db := setupOrganizationTestDB(t)
+	require.NoError(t, db.AutoMigrate(&models.User{}, &models.ReportingLine{}, &models.EmployeeHierarchy{}, &models.AbsenceRequest{}))
+	company := createPayrollTestCompany(t, db)
+	service := NewOrgStructureService(db)
+
+	director := createFixtureEmployee(t, db, company.ID, 1, "general", 900)
+	manager := createFixtureEmployee(t, db, company.ID, 2, "general", 600)
+	operator := createFixtureEmployee(t, db, company.ID, 3, "general", 400)
+	directorUser := createFixtureUser(t, db, director, "supervisor")
+	managerUser := createFixtureUser(t, db, manager, "supervisor")
+	operatorUser := createFixtureUser(t, db, operator, "employee")
+
+	today := dtos.Date{Time: time.Now()}
+	setManager := func(employee, manager *models.Employee) error {
+		_, err := service.SetManager(company.ID, dtos.ReportingLineRequest{
+			EmployeeID: employee.ID, ManagerID: &manager.ID, EffectiveFrom: today,
+		}, uuid.New())
+		return err
+	}
+	require.NoError(t, setManager(operator, manager))
+	require.NoError(t, setManager(manager, director))
+
+	// Closure table answers the whole subtree in one query
+	team, err := service.SubordinateIDs(director.ID, false)
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []uuid.UUID{manager.ID, operator.ID}, team)
+	direct, err := service.SubordinateIDs(director.ID, true)
+	require.NoError(t, err)
+	assert.Equal(t, []uuid.UUID{manager.ID}, direct)
+
+	// Director under the operator would close a loop
+	assert.ErrorIs(t, setManager(director, operator), ErrReportingCycle)
+
+	// Portal users follow the reporting line
+	require.NoError(t, db.First(operatorUser, "id = ?", operatorUser.ID).Error)
+	require.NotNil(t, operatorUser.SupervisorID)
+	assert.Equal(t, managerUser.ID, *operatorUser.SupervisorID)
+
+	chart, err := service.GetOrgChart(company.ID, nil)
+	require.NoError(t, err)
+	require.Len(t, chart, 1)
+	assert.Equal(t, director.ID, chart[0].EmployeeID)
+	assert.Equal(t, 2, chart[0].Headcount)
+	assert.Equal(t, 1, chart[0].Children[0].Headcount)
+
+	// Manager on vacation: the operator's requests go to the director
+	resolution, err := service.ResolveApprover(operator.ID, time.Now())
+	require.NoError(t, err)
+	assert.Equal(t, managerUser.ID, *resolution.ApproverUserID)
+	assert.False(t, resolution.Delegated)
+
+	require.NoError(t, db.Create(&models.AbsenceRequest{
+		EmployeeID:  managerUser.ID,
+		RequestType: models.RequestTypeVacation,
+		StartDate:   time.Now().AddDate(0, 0, -1),
+		EndDate:     time.Now().AddDate(0, 0, 3),
+		TotalDays:   5,
+		Status:      models.RequestStatusApproved,
+	}).Error)
+
+	resolution, err = service.ResolveApprover(operator.ID, time.Now())
+	require.NoError(t, err)
+	assert.Equal(t, directorUser.ID, *resolution.ApproverUserID)
+	assert.True(t, resolution.Delegated)
+
+	scope, err := service.ApprovalScopeUserIDs(directorUser.ID, time.Now())
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []uuid.UUID{managerUser.ID, operatorUser.ID}, scope)
+
+	// Rebuilding from Employee.SupervisorID gives the same closure
+	rows, err := service.RebuildHierarchy(company.ID)
+	require.NoError(t, err)
+	assert.Equal(t, 6, rows) // 3 self rows + manager→director + operator→manager + operator→director
+}
@@ -0,0 +1,745 @@
+/*
+Package services - Organization Service
+
+==============================================================================
+FILE: internal/services/organization_service.go
+==============================================================================
+
+DESCRIPTION:
+    Maintains the organizational catalog (department tree, salary grades,
+    positions), moves employees between departments/positions with
+    effective dating, checks salaries against the position's pay band and
+    builds the compa-ratio / range penetration report by department.
+
+USER PERSPECTIVE:
+    - HR builds the department tree and the position catalog
+    - Future-dated moves are applied automatically on their date
+    - Salary changes outside the band show a warning, or are rejected when
+      the grade's policy is "block"
+    - Compensation reviews pay equity per department
+
+DEVELOPER GUIDELINES:
+    ✅  OK to modify: Report groupings, headcount rules
+    ⚠️  CAUTION: Employee.DepartmentID/PositionID must follow the current
+        assignment; change them through AssignEmployee
+    ❌  DO NOT modify: Closed assignments
+    📝  ApplyDueAssignments runs in the scheduled_changes job (leader only); safe to repeat
+
+SYNTAX EXPLANATION:
+    - CheckPayBand returns nil when the employee has no graded position
+    - Closing an assignment sets EffectiveTo to the day before the new one
+    - Report groups by the employee's department (position's as fallback)
+
+==============================================================================
+*/
+package services
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"backend/internal/dtos"
+	"backend/internal/models"
+)
+
+// ErrOutsidePayBand is returned when a salary is outside a band whose policy is "block"
+var ErrOutsidePayBand = errors.New("daily salary is outside the position's pay band")
+
+// OrganizationService manages departments, positions, grades and assignments
+type OrganizationService struct {
+	db *gorm.DB
+}
+
+// NewOrganizationService creates a new OrganizationService
+func NewOrganizationService(db *gorm.DB) *OrganizationService {
+	return &OrganizationService{db: db}
+}
+
+// =========================================================================
+// Departments
+// =========================================================================
+
+// ListDepartmentTree returns the company's departments as a tree
+func (s *OrganizationService) ListDepartmentTree(companyID uuid.UUID) ([]dtos.DepartmentNode, error) {
+	var departments []models.Department
+	if err := s.db.Where("company_id = ?", companyID).Order("code").Find(&departments).Error; err != nil {
+		return nil, fmt.Errorf("error fetching departments: %w", err)
+	}
+
+	type deptCount struct {
+		DepartmentID uuid.UUID
+		Total        int
+	}
+	var counts []deptCount
+	if err := s.db.Model(&models.Employee{}).
+		Select("department_id, COUNT(*) AS total").
+		Where("company_id = ? AND department_id IS NOT NULL AND employment_status <> ?", companyID, "terminated").
+		Group("department_id").
+		Scan(&counts).Error; err != nil {
+		return nil, fmt.Errorf("error counting employees: %w", err)
+	}
+	employeesByDept := make(map[uuid.UUID]int, len(counts))
+	for _, c := range counts {
+		employeesByDept[c.DepartmentID] = c.Total
+	}
+
+	children := make(map[uuid.UUID][]models.Department)
+	var roots []models.Department
+	for _, d := range departments {
+		if d.ParentID == nil {
+			roots = append(roots, d)
+		} else {
+			children[*d.ParentID] = append(children[*d.ParentID], d)
+		}
+	}
+
+	var build func(d models.Department) dtos.DepartmentNode
+	build = func(d models.Department) dtos.DepartmentNode {
+		node := dtos.DepartmentNode{
+			ID:            d.ID,
+			Code:          d.Code,
+			Name:          d.Name,
+			Description:   d.Description,
+			ParentID:      d.ParentID,
+			CostCenterID:  d.CostCenterID,
+			ManagerID:     d.ManagerID,
+			IsActive:      d.IsActive,
+			EmployeeCount: employeesByDept[d.ID],
+		}
+		for _, child := range children[d.ID] {
+			node.Children = append(node.Children, build(child))
+		}
+		return node
+	}
+
+	tree := make([]dtos.DepartmentNode, 0, len(roots))
+	for _, root := range roots {
+		tree = append(tree, build(root))
+	}
+	return tree, nil
+}
+
+// CreateDepartment creates a department
+func (s *OrganizationService) CreateDepartment(companyID uuid.UUID, req dtos.DepartmentRequest) (*models.Department, error) {
+	department := &models.Department{CompanyID: companyID, IsActive: true}
+	if err := s.applyDepartmentRequest(department, req); err != nil {
+		return nil, err
+	}
+	if err := s.db.Create(department).Error; err != nil {
+		return nil, fmt.Errorf("error creating department: %w", err)
+	}
+	return department, nil
+}
+
+// UpdateDepartment updates a department
+func (s *OrganizationService) UpdateDepartment(id, companyID uuid.UUID, req dtos.DepartmentRequest) (*models.Department, error) {
+	var department models.Department
+	if err := s.db.Where("id = ? AND company_id = ?", id, companyID).First(&department).Error; err != nil {
+		return nil, errors.New("department not found")
+	}
+	if err := s.applyDepartmentRequest(&department, req); err != nil {
+		return nil, err
+	}
+	if err := s.db.Save(&department).Error; err != nil {
+		return nil, fmt.Errorf("error updating department: %w", err)
+	}
+	return &department, nil
+}
+
+// DeleteDepartment deletes a department that has no sub-departments, positions or employees
+func (s *OrganizationService) DeleteDepartment(id, companyID uuid.UUID) error {
+	var department models.Department
+	if err := s.db.Where("id = ? AND company_id = ?", id, companyID).First(&department).Error; err != nil {
+		return errors.New("department not found")
+	}
+
+	var count int64
+	if err := s.db.Model(&models.Department{}).Where("parent_id = ?", id).Count(&count).Error; err != nil {
+		return err
+	}
+	if count > 0 {
+		return errors.New("department cannot be deleted: it has sub-departments")
+	}
+	if err := s.db.Model(&models.Position{}).Where("department_id = ?", id).Count(&count).Error; err != nil {
+		return err
+	}
+	if count > 0 {
+		return errors.New("department cannot be deleted: it has positions")
+	}
+	if err := s.db.Model(&models.Employee{}).Where("department_id = ? AND employment_status <> ?", id, "terminated").Count(&count).Error; err != nil {
+		return err
+	}
+	if count > 0 {
+		return errors.New("department cannot be deleted: it has employees")
+	}
+	return s.db.Delete(&department).Error
+}
+
+// applyDepartmentRequest copies and validates the request into the department
+func (s *OrganizationService) applyDepartmentRequest(department *models.Department, req dtos.DepartmentRequest) error {
+	code := strings.ToUpper(strings.TrimSpace(req.Code))
+	var count int64
+	if err := s.db.Model(&models.Department{}).
+		Where("company_id = ? AND code = ? AND id <> ?", department.CompanyID, code, department.ID).
+		Count(&count).Error; err != nil {
+		return err
+	}
+	if count > 0 {
+		return fmt.Errorf("department code %s already exists", code)
+	}
+
+	if req.ParentID != nil {
+		if department.ID != uuid.Nil && *req.ParentID == department.ID {
+			return errors.New("department cannot be its own parent")
+		}
+		// Walk up from the new parent; reaching this department would create a cycle
+		current := *req.ParentID
+		for depth := 0; ; depth++ {
+			var parent models.Department
+			if err := s.db.Where("id = ? AND company_id = ?", current, department.CompanyID).First(&parent).Error; err != nil {
+				return errors.New("parent department not found")
+			}
+			if parent.ParentID == nil {
+				break
+			}
+			if *parent.ParentID == department.ID || depth > 100 {
+				return errors.New("parent department cannot be one of its sub-departments")
+			}
+			current = *parent.ParentID
+		}
+	}
+
+	department.Code = code
+	department.Name = strings.TrimSpace(req.Name)
+	department.Description = strings.TrimSpace(req.Description)
+	department.ParentID = req.ParentID
+	department.CostCenterID = req.CostCenterID
+	department.ManagerID = req.ManagerID
+	if req.IsActive != nil {
+		department.IsActive = *req.IsActive
+	}
+	return nil
+}
+
+// =========================================================================
+// Salary grades
+// =========================================================================
+
+// ListGrades returns the company's salary grades
+func (s *OrganizationService) ListGrades(companyID uuid.UUID) ([]models.SalaryGrade, error) {
+	var grades []models.SalaryGrade
+	if err := s.db.Where("company_id = ?", companyID).Order("level, code").Find(&grades).Error; err != nil {
+		return nil, fmt.Errorf("error fetching salary grades: %w", err)
+	}
+	return grades, nil
+}
+
+// CreateGrade creates a salary grade
+func (s *OrganizationService) CreateGrade(companyID uuid.UUID, req dtos.SalaryGradeRequest) (*models.SalaryGrade, error) {
+	grade := &models.SalaryGrade{CompanyID: companyID, IsActive: true}
+	if err := s.applyGradeRequest(grade, req); err != nil {
+		return nil, err
+	}
+	if err := s.db.Create(grade).Error; err != nil {
+		return nil, fmt.Errorf("error creating salary grade: %w", err)
+	}
+	return grade, nil
+}
+
+// UpdateGrade updates a salary grade
+func (s *OrganizationService) UpdateGrade(id, companyID uuid.UUID, req dtos.SalaryGradeRequest) (*models.SalaryGrade, error) {
+	var grade models.SalaryGrade
+	if err := s.db.Where("id = ? AND company_id = ?", id, companyID).First(&grade).Error; err != nil {
+		return nil, errors.New("salary grade not found")
+	}
+	if err := s.applyGradeRequest(&grade, req); err != nil {
+		return nil, err
+	}
+	if err := s.db.Save(&grade).Error; err != nil {
+		return nil, fmt.Errorf("error updating salary grade: %w", err)
+	}
+	return &grade, nil
+}
+
+// DeleteGrade deletes a salary grade not used by any position
+func (s *OrganizationService) DeleteGrade(id, companyID uuid.UUID) error {
+	var grade models.SalaryGrade
+	if err := s.db.Where("id = ? AND company_id = ?", id, companyID).First(&grade).Error; err != nil {
+		return errors.New("salary grade not found")
+	}
+	var count int64
+	if err := s.db.Model(&models.Position{}).Where("salary_grade_id = ?", id).Count(&count).Error; err != nil {
+		return err
+	}
+	if count > 0 {
+		return errors.New("salary grade cannot be deleted: it is used by positions")
+	}
+	return s.db.Delete(&grade).Error
+}
+
+// applyGradeRequest copies and validates the request into the grade
+func (s *OrganizationService) applyGradeRequest(grade *models.SalaryGrade, req dtos.SalaryGradeRequest) error {
+	code := strings.ToUpper(strings.TrimSpace(req.Code))
+	var count int64
+	if err := s.db.Model(&models.SalaryGrade{}).
+		Where("company_id = ? AND code = ? AND id <> ?", grade.CompanyID, code, grade.ID).
+		Count(&count).Error; err != nil {
+		return err
+	}
+	if count > 0 {
+		return fmt.Errorf("salary grade %s already exists", code)
+	}
+
+	mid := req.MidDailySalary
+	if mid == 0 {
+		mid = roundCurrency((req.MinDailySalary + req.MaxDailySalary) / 2)
+	}
+	if req.MinDailySalary > mid || mid > req.MaxDailySalary {
+		return errors.New("pay band must satisfy min <= mid <= max")
+	}
+
+	grade.Code = code
+	grade.Name = strings.TrimSpace(req.Name)
+	grade.Level = req.Level
+	grade.MinDailySalary = req.MinDailySalary
+	grade.MidDailySalary = mid
+	grade.MaxDailySalary = req.MaxDailySalary
+	grade.BandPolicy = models.PayBandPolicyWarn
+	if req.BandPolicy != "" {
+		grade.BandPolicy = req.BandPolicy
+	}
+	if req.IsActive != nil {
+		grade.IsActive = *req.IsActive
+	}
+	return nil
+}
+
+// =========================================================================
+// Positions
+// =========================================================================
+
+// ListPositions returns the company's positions, optionally for one department
+func (s *OrganizationService) ListPositions(companyID uuid.UUID, departmentID *uuid.UUID) ([]models.Position, error) {
+	query := s.db.Preload("Department").Preload("SalaryGrade").Where("company_id = ?", companyID)
+	if departmentID != nil {
+		query = query.Where("department_id = ?", *departmentID)
+	}
+	var positions []models.Position
+	if err := query.Order("job_code").Find(&positions).Error; err != nil {
+		return nil, fmt.Errorf("error fetching positions: %w", err)
+	}
+	return positions, nil
+}
+
+// CreatePosition creates a position
+func (s *OrganizationService) CreatePosition(companyID uuid.UUID, req dtos.PositionRequest) (*models.Position, error) {
+	position := &models.Position{CompanyID: companyID, IsActive: true}
+	if err := s.applyPositionRequest(position, req); err != nil {
+		return nil, err
+	}
+	if err := s.db.Create(position).Error; err != nil {
+		return nil, fmt.Errorf("error creating position: %w", err)
+	}
+	return position, nil
+}
+
+// UpdatePosition updates a position
+func (s *OrganizationService) UpdatePosition(id, companyID uuid.UUID, req dtos.PositionRequest) (*models.Position, error) {
+	var position models.Position
+	if err := s.db.Where("id = ? AND company_id = ?", id, companyID).First(&position).Error; err != nil {
+		return nil, errors.New("position not found")
+	}
+	if err := s.applyPositionRequest(&position, req); err != nil {
+		return nil, err
+	}
+	if err := s.db.Save(&position).Error; err != nil {
+		return nil, fmt.Errorf("error updating position: %w", err)
+	}
+	return &position, nil
+}
+
+// DeletePosition deletes a position no employee holds
+func (s *OrganizationService) DeletePosition(id, companyID uuid.UUID) error {
+	var position models.Position
+	if err := s.db.Where("id = ? AND company_id = ?", id, companyID).First(&position).Error; err != nil {
+		return errors.New("position not found")
+	}
+	var count int64
+	if err := s.db.Model(&models.Employee{}).Where("position_id = ? AND employment_status <> ?", id, "terminated").Count(&count).Error; err != nil {
+		return err
+	}
+	if count > 0 {
+		return errors.New("position cannot be deleted: it has employees")
+	}
+	return s.db.Delete(&position).Error
+}
+
+// applyPositionRequest copies and validates the request into the position
+func (s *OrganizationService) applyPositionRequest(position *models.Position, req dtos.PositionRequest) error {
+	code := strings.ToUpper(strings.TrimSpace(req.JobCode))
+	var count int64
+	if err := s.db.Model(&models.Position{}).
+		Where("company_id = ? AND job_code = ? AND id <> ?", position.CompanyID, code, position.ID).
+		Count(&count).Error; err != nil {
+		return err
+	}
+	if count > 0 {
+		return fmt.Errorf("job code %s already exists", code)
+	}
+	if req.DepartmentID != nil {
+		if err := s.db.Model(&models.Department{}).Where("id = ? AND company_id = ?", *req.DepartmentID, position.CompanyID).Count(&count).Error; err != nil {
+			return err
+		}
+		if count == 0 {
+			return errors.New("department not found")
+		}
+	}
+	if req.SalaryGradeID != nil {
+		if err := s.db.Model(&models.SalaryGrade{}).Where("id = ? AND company_id = ?", *req.SalaryGradeID, position.CompanyID).Count(&count).Error; err != nil {
+			return err
+		}
+		if count == 0 {
+			return errors.New("salary grade not found")
+		}
+	}
+
+	position.JobCode = code
+	position.Title = strings.TrimSpace(req.Title)
+	position.Description = strings.TrimSpace(req.Description)
+	position.DepartmentID = req.DepartmentID
+	position.SalaryGradeID = req.SalaryGradeID
+	position.CollarType = req.CollarType
+	position.Headcount = req.Headcount
+	if req.IsActive != nil {
+		position.IsActive = *req.IsActive
+	}
+	return nil
+}
+
+// =========================================================================
+// Employee assignments
+// =========================================================================
+
+// AssignEmployee moves an employee to a department/position from a date.
+// Dates up to today are applied immediately; future dates stay pending.
+func (s *OrganizationService) AssignEmployee(companyID uuid.UUID, req dtos.EmployeeAssignmentRequest, createdBy uuid.UUID) (*models.EmployeeAssignment, error) {
+	var employee models.Employee
+	if err := s.db.Where("id = ? AND company_id = ?", req.EmployeeID, companyID).First(&employee).Error; err != nil {
+		return nil, errors.New("employee not found")
+	}
+	if req.DepartmentID == nil && req.PositionID == nil {
+		return nil, errors.New("department or position is required")
+	}
+
+	departmentID := req.DepartmentID
+	if req.PositionID != nil {
+		var position models.Position
+		if err := s.db.Where("id = ? AND company_id = ?", *req.PositionID, companyID).First(&position).Error; err != nil {
+			return nil, errors.New("position not found")
+		}
+		if !position.IsActive {
+			return nil, errors.New("position is not active")
+		}
+		if position.Headcount > 0 {
+			var occupied int64
+			if err := s.db.Model(&models.Employee{}).
+				Where("position_id = ? AND id <> ? AND employment_status <> ?", position.ID, employee.ID, "terminated").
+				Count(&occupied).Error; err != nil {
+				return nil, err
+			}
+			if int(occupied) >= position.Headcount {
+				return nil, fmt.Errorf("position %s has no vacant seats (headcount %d)", position.JobCode, position.Headcount)
+			}
+		}
+		if departmentID == nil {
+			departmentID = position.DepartmentID
+		}
+	}
+	if departmentID != nil {
+		var count int64
+		if err := s.db.Model(&models.Department{}).Where("id = ? AND company_id = ?", *departmentID, companyID).Count(&count).Error; err != nil {
+			return nil, err
+		}
+		if count == 0 {
+			return nil, errors.New("department not found")
+		}
+	}
+
+	assignment := &models.EmployeeAssignment{
+		CompanyID:     companyID,
+		EmployeeID:    employee.ID,
+		DepartmentID:  departmentID,
+		PositionID:    req.PositionID,
+		EffectiveFrom: truncateToDate(req.EffectiveFrom.ToTime()),
+		Reason:        strings.TrimSpace(req.Reason),
+		CreatedBy:     &createdBy,
+	}
+
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		// A new move supersedes pending moves on or after its date
+		if err := tx.Where("employee_id = ? AND applied = ? AND effective_from >= ?", employee.ID, false, assignment.EffectiveFrom).
+			Delete(&models.EmployeeAssignment{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Create(assignment).Error; err != nil {
+			return err
+		}
+		if assignment.EffectiveFrom.After(truncateToDate(time.Now())) {
+			return nil
+		}
+		return applyAssignment(tx, assignment)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error assigning employee: %w", err)
+	}
+	return assignment, nil
+}
+
+// ListAssignments returns an employee's assignment history, newest first
+func (s *OrganizationService) ListAssignments(employeeID, companyID uuid.UUID) ([]models.EmployeeAssignment, error) {
+	var assignments []models.EmployeeAssignment
+	err := s.db.Preload("Department").Preload("Position").
+		Where("employee_id = ? AND company_id = ?", employeeID, companyID).
+		Order("effective_from DESC").
+		Find(&assignments).Error
+	if err != nil {
+		return nil, fmt.Errorf("error fetching assignments: %w", err)
+	}
+	return assignments, nil
+}
+
+// ApplyDueAssignments applies pending assignments whose effective date has arrived
+func (s *OrganizationService) ApplyDueAssignments() (int, error) {
+	var due []models.EmployeeAssignment
+	if err := s.db.Where("applied = ? AND effective_from <= ?", false, truncateToDate(time.Now())).
+		Order("effective_from").Find(&due).Error; err != nil {
+		return 0, err
+	}
+
+	applied := 0
+	for i := range due {
+		if err := s.db.Transaction(func(tx *gorm.DB) error {
+			return applyAssignment(tx, &due[i])
+		}); err != nil {
+			return applied, fmt.Errorf("error applying assignment %s: %w", due[i].ID, err)
+		}
+		applied++
+	}
+	return applied, nil
+}
+
+// applyAssignment closes the employee's current assignment and copies the new one to the employee
+func applyAssignment(tx *gorm.DB, assignment *models.EmployeeAssignment) error {
+	previousEnd := assignment.EffectiveFrom.AddDate(0, 0, -1)
+	if err := tx.Model(&models.EmployeeAssignment{}).
+		Where("employee_id = ? AND id <> ? AND applied = ? AND effective_to IS NULL", assignment.EmployeeID, assignment.ID, true).
+		Update("effective_to", previousEnd).Error; err != nil {
+		return err
+	}
+
+	assignment.Applied = true
+	if err := tx.Model(assignment).Update("applied", true).Error; err != nil {
+		return err
+	}
+
+	// UpdateColumns skips Employee hooks; only the two references change
+	return tx.Model(&models.Employee{}).Where("id = ?", assignment.EmployeeID).
+		UpdateColumns(map[string]interface{}{
+			"department_id": assignment.DepartmentID,
+			"position_id":   assignment.PositionID,
+		}).Error
+}
+
+// recordAssignmentChange keeps the assignment history when an employee's
+// department or position is edited directly on the employee record
+func recordAssignmentChange(tx *gorm.DB, employee *models.Employee, reason string, createdBy *uuid.UUID) error {
+	today := truncateToDate(time.Now())
+	if err := tx.Model(&models.EmployeeAssignment{}).
+		Where("employee_id = ? AND applied = ? AND effective_to IS NULL", employee.ID, true).
+		Update("effective_to", today.AddDate(0, 0, -1)).Error; err != nil {
+		return err
+	}
+	return tx.Create(&models.EmployeeAssignment{
+		CompanyID:     employee.CompanyID,
+		EmployeeID:    employee.ID,
+		DepartmentID:  employee.DepartmentID,
+		PositionID:    employee.PositionID,
+		EffectiveFrom: today,
+		Applied:       true,
+		Reason:        reason,
+		CreatedBy:     createdBy,
+	}).Error
+}
+
+// =========================================================================
+// Pay bands
+// =========================================================================
+
+// CheckPayBand compares a daily salary with the band of the employee's position.
+// It returns nil when the position has no grade, and ErrOutsidePayBand (with
+// the check) when the salary is outside a band whose policy is "block".
+func (s *OrganizationService) CheckPayBand(employee *models.Employee, dailySalary float64) (*dtos.PayBandCheck, error) {
+	if employee.PositionID == nil {
+		return nil, nil
+	}
+	var position models.Position
+	if err := s.db.Preload("SalaryGrade").First(&position, "id = ?", *employee.PositionID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("error fetching position: %w", err)
+	}
+	grade := position.SalaryGrade
+	if grade == nil {
+		return nil, nil
+	}
+
+	check := &dtos.PayBandCheck{
+		PositionID:       &position.ID,
+		GradeCode:        grade.Code,
+		MinDailySalary:   grade.MinDailySalary,
+		MidDailySalary:   grade.MidDailySalary,
+		MaxDailySalary:   grade.MaxDailySalary,
+		DailySalary:      dailySalary,
+		CompaRatio:       roundRatio(grade.CompaRatio(dailySalary)),
+		RangePenetration: roundRatio(grade.RangePenetration(dailySalary)),
+		WithinBand:       grade.Contains(dailySalary),
+		Policy:           grade.BandPolicy,
+	}
+	if check.WithinBand {
+		return check, nil
+	}
+
+	check.Warning = fmt.Sprintf("daily salary %.2f is outside grade %s band (%.2f - %.2f)",
+		dailySalary, grade.Code, grade.MinDailySalary, grade.MaxDailySalary)
+	if grade.BandPolicy == models.PayBandPolicyBlock {
+		return check, fmt.Errorf("%w: %s", ErrOutsidePayBand, check.Warning)
+	}
+	return check, nil
+}
+
+// =========================================================================
+// Reports
+// =========================================================================
+
+// CompaRatioReport builds the compa-ratio and range penetration report by department
+func (s *OrganizationService) CompaRatioReport(companyID uuid.UUID) (*dtos.CompaRatioReport, error) {
+	var employees []models.Employee
+	if err := s.db.Where("company_id = ? AND employment_status IN ?", companyID, []string{"active", "on_leave"}).
+		Order("employee_number").Find(&employees).Error; err != nil {
+		return nil, fmt.Errorf("error fetching employees: %w", err)
+	}
+
+	var positions []models.Position
+	if err := s.db.Preload("SalaryGrade").Where("company_id = ?", companyID).Find(&positions).Error; err != nil {
+		return nil, fmt.Errorf("error fetching positions: %w", err)
+	}
+	positionsByID := make(map[uuid.UUID]models.Position, len(positions))
+	for _, p := range positions {
+		positionsByID[p.ID] = p
+	}
+
+	var departments []models.Department
+	if err := s.db.Where("company_id = ?", companyID).Find(&departments).Error; err != nil {
+		return nil, fmt.Errorf("error fetching departments: %w", err)
+	}
+	departmentNames := make(map[uuid.UUID]string, len(departments))
+	for _, d := range departments {
+		departmentNames[d.ID] = d.Name
+	}
+
+	report := &dtos.CompaRatioReport{GeneratedAt: time.Now()}
+	groups := make(map[string]*dtos.CompaRatioDepartment)
+	var order []string
+	totalCompa, totalPenetration := 0.0, 0.0
+
+	for _, employee := range employees {
+		if employee.PositionID == nil {
+			report.UngradedCount++
+			continue
+		}
+		position, ok := positionsByID[*employee.PositionID]
+		if !ok || position.SalaryGrade == nil {
+			report.UngradedCount++
+			continue
+		}
+		grade := position.SalaryGrade
+
+		departmentID := employee.DepartmentID
+		if departmentID == nil {
+			departmentID = position.DepartmentID
+		}
+		key, name := "", "Sin departamento"
+		if departmentID != nil {
+			key = departmentID.String()
+			if n, ok := departmentNames[*departmentID]; ok {
+				name = n
+			}
+		}
+		group, ok := groups[key]
+		if !ok {
+			group = &dtos.CompaRatioDepartment{DepartmentID: departmentID, DepartmentName: name}
+			groups[key] = group
+			order = append(order, key)
+		}
+
+		line := dtos.CompaRatioEmployee{
+			EmployeeID:       employee.ID,
+			EmployeeNumber:   employee.EmployeeNumber,
+			FullName:         strings.TrimSpace(fmt.Sprintf("%s %s %s", employee.FirstName, employee.LastName, employee.MotherLastName)),
+			JobCode:          position.JobCode,
+			PositionTitle:    position.Title,
+			GradeCode:        grade.Code,
+			Gender:           employee.Gender,
+			DailySalary:      employee.DailySalary,
+			CompaRatio:       roundRatio(grade.CompaRatio(employee.DailySalary)),
+			RangePenetration: roundRatio(grade.RangePenetration(employee.DailySalary)),
+			BandStatus:       "within",
+		}
+		switch {
+		case employee.DailySalary < grade.MinDailySalary:
+			line.BandStatus = "below"
+			group.BelowBand++
+		case employee.DailySalary > grade.MaxDailySalary:
+			line.BandStatus = "above"
+			group.AboveBand++
+		default:
+			group.WithinBand++
+		}
+
+		group.Employees = append(group.Employees, line)
+		group.EmployeeCount++
+		group.AverageCompaRatio += line.CompaRatio
+		group.AverageRangePenetration += line.RangePenetration
+		totalCompa += line.CompaRatio
+		totalPenetration += line.RangePenetration
+		report.EmployeeCount++
+	}
+
+	for _, key := range order {
+		group := groups[key]
+		group.AverageCompaRatio = roundRatio(group.AverageCompaRatio / float64(group.EmployeeCount))
+		group.AverageRangePenetration = roundRatio(group.AverageRangePenetration / float64(group.EmployeeCount))
+		report.Departments = append(report.Departments, *group)
+	}
+	sort.Slice(report.Departments, func(i, j int) bool {
+		return report.Departments[i].DepartmentName < report.Departments[j].DepartmentName
+	})
+	if report.EmployeeCount > 0 {
+		report.AverageCompaRatio = roundRatio(totalCompa / float64(report.EmployeeCount))
+		report.AverageRangePenetration = roundRatio(totalPenetration / float64(report.EmployeeCount))
+	}
+	return report, nil
+}
+
+// roundRatio rounds a ratio to 4 decimals
+func roundRatio(value float64) float64 {
+	return math.Round(value*10000) / 10000
+}
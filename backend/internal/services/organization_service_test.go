@@ -0,0 +1,116 @@
+package services
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+
+	"backend/internal/dtos"
+	"backend/internal/models"
+)
+
+func setupOrganizationTestDB(t *testing.T) *gorm.DB {
+	db := setupPayrollTestDB(t)
+	require.NoError(t, db.AutoMigrate(
+		&models.Department{}, &models.SalaryGrade{}, &models.Position{}, &models.EmployeeAssignment{},
+	))
+	return db
+}
+
+func TestOrganization_DepartmentTreeRejectsCycles(t *testing.T) {
+	db := setupOrganizationTestDB(t)
+	company := createPayrollTestCompany(t, db)
+	service := NewOrganizationService(db)
+
+	ops, err := service.CreateDepartment(company.ID, dtos.DepartmentRequest{Code: "ops", Name: "Operaciones"})
+	require.NoError(t, err)
+	plant, err := service.CreateDepartment(company.ID, dtos.DepartmentRequest{Code: "PLT", Name: "Planta", ParentID: &ops.ID})
+	require.NoError(t, err)
+	assert.Equal(t, "OPS", ops.Code)
+
+	_, err = service.UpdateDepartment(ops.ID, company.ID, dtos.DepartmentRequest{Code: "OPS", Name: "Operaciones", ParentID: &plant.ID})
+	assert.Error(t, err)
+
+	tree, err := service.ListDepartmentTree(company.ID)
+	require.NoError(t, err)
+	require.Len(t, tree, 1)
+	require.Len(t, tree[0].Children, 1)
+	assert.Equal(t, "PLT", tree[0].Children[0].Code)
+
+	assert.Error(t, service.DeleteDepartment(ops.ID, company.ID), "has sub-departments")
+}
+
+func TestOrganization_PayBandAndAssignments(t *testing.T) {
+	db := setupOrganizationTestDB(t)
+	company := createPayrollTestCompany(t, db)
+	service := NewOrganizationService(db)
+
+	dept, err := service.CreateDepartment(company.ID, dtos.DepartmentRequest{Code: "PRD", Name: "Producción"})
+	require.NoError(t, err)
+	warnGrade, err := service.CreateGrade(company.ID, dtos.SalaryGradeRequest{Code: "G1", MinDailySalary: 400, MaxDailySalary: 600})
+	require.NoError(t, err)
+	assert.Equal(t, 500.00, warnGrade.MidDailySalary)
+	blockGrade, err := service.CreateGrade(company.ID, dtos.SalaryGradeRequest{Code: "G2", MinDailySalary: 600, MaxDailySalary: 900, BandPolicy: "block"})
+	require.NoError(t, err)
+
+	operator, err := service.CreatePosition(company.ID, dtos.PositionRequest{JobCode: "op-01", Title: "Operador", DepartmentID: &dept.ID, SalaryGradeID: &warnGrade.ID})
+	require.NoError(t, err)
+	lead, err := service.CreatePosition(company.ID, dtos.PositionRequest{JobCode: "SUP-01", Title: "Supervisor", DepartmentID: &dept.ID, SalaryGradeID: &blockGrade.ID, Headcount: 1})
+	require.NoError(t, err)
+
+	a := createFixtureEmployee(t, db, company.ID, 1, "general", 450)
+	b := createFixtureEmployee(t, db, company.ID, 2, "general", 650)
+
+	// Immediate assignment copies department/position to the employee
+	_, err = service.AssignEmployee(company.ID, dtos.EmployeeAssignmentRequest{
+		EmployeeID: a.ID, PositionID: &operator.ID, EffectiveFrom: dtos.Date{Time: time.Now().AddDate(0, 0, -10)},
+	}, uuid.New())
+	require.NoError(t, err)
+	require.NoError(t, db.First(a, "id = ?", a.ID).Error)
+	require.NotNil(t, a.DepartmentID)
+	assert.Equal(t, dept.ID, *a.DepartmentID)
+
+	// Warn policy: allowed, with a warning
+	check, err := service.CheckPayBand(a, 650)
+	require.NoError(t, err)
+	assert.False(t, check.WithinBand)
+	assert.NotEmpty(t, check.Warning)
+
+	// Future promotion stays pending, then the only seat is taken
+	_, err = service.AssignEmployee(company.ID, dtos.EmployeeAssignmentRequest{
+		EmployeeID: a.ID, PositionID: &lead.ID, EffectiveFrom: dtos.Date{Time: time.Now().AddDate(0, 0, 15)},
+	}, uuid.New())
+	require.NoError(t, err)
+	require.NoError(t, db.First(a, "id = ?", a.ID).Error)
+	assert.Equal(t, operator.ID, *a.PositionID)
+
+	_, err = service.AssignEmployee(company.ID, dtos.EmployeeAssignmentRequest{
+		EmployeeID: b.ID, PositionID: &lead.ID, EffectiveFrom: dtos.Date{Time: time.Now()},
+	}, uuid.New())
+	require.NoError(t, err)
+	require.NoError(t, db.First(b, "id = ?", b.ID).Error)
+
+	// Block policy: rejected
+	_, err = service.CheckPayBand(b, 950)
+	assert.True(t, errors.Is(err, ErrOutsidePayBand))
+
+	history, err := service.ListAssignments(a.ID, company.ID)
+	require.NoError(t, err)
+	require.Len(t, history, 2)
+	assert.False(t, history[0].Applied)
+	assert.Nil(t, history[1].EffectiveTo)
+
+	// a: 450 / 500 = 0.9, (450-400)/200 = 0.25; b: 650 / 750, (650-600)/300
+	report, err := service.CompaRatioReport(company.ID)
+	require.NoError(t, err)
+	require.Len(t, report.Departments, 1)
+	assert.Equal(t, 2, report.EmployeeCount)
+	assert.Equal(t, 2, report.Departments[0].WithinBand)
+	assert.InDelta(t, (0.9+0.8667)/2, report.AverageCompaRatio, 0.0001)
+	assert.InDelta(t, (0.25+0.1667)/2, report.Departments[0].AverageRangePenetration, 0.0001)
+}
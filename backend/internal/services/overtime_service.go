@@ -0,0 +1,677 @@
+/*
+Package services - Overtime Service
+
+==============================================================================
+FILE: internal/services/overtime_service.go
+==============================================================================
+
+DESCRIPTION:
+    Overtime pre-authorization. An employee, or their supervisor on their
+    behalf, requests overtime before it is worked; the request goes through
+    the same approval stages as absence requests. Once fully approved its
+    hours are the only overtime the attendance evaluation lets flow into
+    prenómina for that day (RequireOvertimeAuthorization); anything worked
+    beyond them is flagged for review. At period close the authorized hours
+    are reconciled against the closed clock records.
+
+USER PERSPECTIVE:
+    - A supervisor requests 2h of overtime for an operator on Thursday
+    - Requests over the weekly limits (3h a day, 3 days, 9h a week by
+      default) are refused when filed
+    - Overtime worked without an approved request is not paid until HR
+      reviews the day
+    - Payroll sees authorized vs. worked overtime per request at period close
+    - Overtime requested as "bank" is taken later as time off (tiempo por
+      tiempo) instead of being paid
+
+DEVELOPER GUIDELINES:
+    ✅  OK to modify: Who may file, notification texts
+    ⚠️  CAUTION: The stage order comes from nextApprovalStage, shared with
+        absence requests; change it there for both
+    ⚠️  CAUTION: Limits come from the employee's TimePolicy
+        (MaxDailyOvertimeHours, MaxOvertimeDaysPerWeek, WeeklyDoubleTimeHours)
+    📝  OvertimeRequest.EmployeeID is an employees.id (AbsenceRequest uses users.id)
+
+SYNTAX EXPLANATION:
+    - Chain: SUPERVISOR → MANAGER → HR / HR_BLUE_GRAY → GENERAL_MANAGER →
+      PAYROLL → approved. A request filed by the employee's manager starts
+      with the SUPERVISOR stage approved by them; a SUP_AND_GM supervisor
+      skips MANAGER too, as in absence requests
+    - SUPERVISOR stage: only a manager in the employee's reporting chain
+    - AuthorizedHours = EstimatedHours on final approval
+    - Reconcile: ActualHours = double + triple hours of the day's closed
+      ClockRecords (TimePolicyService); status becomes "completed"
+    - Compensation "bank": the request must fit under the policy's
+      TimeBankMaxHours when filed and approved; at reconciliation the
+      worked hours, up to AuthorizedHours, are deposited (TimeBankService)
+
+==============================================================================
+*/
+package services
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"backend/internal/dtos"
+	"backend/internal/models"
+	"backend/internal/models/enums"
+)
+
+// ErrOvertimeNotAllowed is returned when the user may not file or act on an overtime request
+var ErrOvertimeNotAllowed = errors.New("not allowed to act on this overtime request")
+
+// overtimeHRRoles may file and follow overtime for any employee of the company
+var overtimeHRRoles = []enums.UserRole{enums.RoleAdmin, enums.RoleHR, enums.RoleHRAndPR, enums.RoleHRBlueGray, enums.RoleHRWhite, enums.RolePayrollStaff}
+
+// overtimeStages are the approval stages in chain order
+var overtimeStages = []models.ApprovalStage{
+	models.ApprovalStageSupervisor, models.ApprovalStageManager, models.ApprovalStageHRBlueGray,
+	models.ApprovalStageHR, models.ApprovalStageGeneralManager, models.ApprovalStagePayroll,
+}
+
+// OvertimeService handles overtime pre-authorization
+type OvertimeService struct {
+	db           *gorm.DB
+	orgStructure *OrgStructureService
+	policies     *TimePolicyService
+	timeBank     *TimeBankService
+}
+
+// NewOvertimeService creates a new OvertimeService
+func NewOvertimeService(db *gorm.DB) *OvertimeService {
+	return &OvertimeService{
+		db:           db,
+		orgStructure: NewOrgStructureService(db),
+		policies:     NewTimePolicyService(db),
+		timeBank:     NewTimeBankService(db),
+	}
+}
+
+// =========================================================================
+// Requests
+// =========================================================================
+
+// CreateRequest files an overtime request for the requester or one of their reports
+func (s *OvertimeService) CreateRequest(companyID, requesterID uuid.UUID, req dtos.OvertimeRequestInput) (*models.OvertimeRequest, error) {
+	var requester models.User
+	if err := s.db.First(&requester, "id = ? AND company_id = ?", requesterID, companyID).Error; err != nil {
+		return nil, errors.New("user not found")
+	}
+	employeeID := req.EmployeeID
+	if employeeID == nil {
+		if requester.EmployeeID == nil {
+			return nil, errors.New("employee_id is required: your user has no employee record")
+		}
+		employeeID = requester.EmployeeID
+	}
+	var employee models.Employee
+	if err := s.db.First(&employee, "id = ? AND company_id = ?", *employeeID, companyID).Error; err != nil {
+		return nil, errors.New("employee not found")
+	}
+	if employee.EmploymentStatus != "active" {
+		return nil, errors.New("employee is not active")
+	}
+
+	isHR := hasUserRole(requester.Role, overtimeHRRoles)
+	isManager := s.managesEmployee(&requester, employee.ID)
+	isSelf := requester.EmployeeID != nil && *requester.EmployeeID == employee.ID
+	if !isSelf && !isManager && !isHR {
+		return nil, ErrOvertimeNotAllowed
+	}
+
+	date := attendanceDate(req.Date.Time)
+	if !isHR && date.Before(attendanceDate(time.Now())) {
+		return nil, errors.New("overtime must be requested before the day it is worked")
+	}
+	start, end, err := overtimeWindow(date, req.StartTime, req.EndTime)
+	if err != nil {
+		return nil, err
+	}
+	hours := roundHours(end.Sub(start).Hours())
+	if err := s.checkLimits(&employee, date, start, end, hours); err != nil {
+		return nil, err
+	}
+	compensation := models.OvertimeCompensationPay
+	if req.Compensation == models.OvertimeCompensationBank {
+		compensation = models.OvertimeCompensationBank
+		if err := s.timeBank.CheckDeposit(&employee, hours, nil); err != nil {
+			return nil, err
+		}
+	}
+
+	resolution, err := s.orgStructure.ResolveApprover(employee.ID, date)
+	if err != nil {
+		return nil, err
+	}
+	if resolution.ApproverUserID == nil {
+		return nil, errors.New("employee has no assigned supervisor - please contact HR to configure the reporting line")
+	}
+	var supervisor models.User
+	if err := s.db.First(&supervisor, "id = ?", *resolution.ApproverUserID).Error; err != nil {
+		return nil, errors.New("assigned supervisor not found in system - please contact HR")
+	}
+
+	request := &models.OvertimeRequest{
+		CompanyID:            companyID,
+		EmployeeID:           employee.ID,
+		RequestedByID:        &requester.ID,
+		RequestDate:          date,
+		StartTime:            start,
+		EndTime:              end,
+		EstimatedHours:       hours,
+		Reason:               strings.TrimSpace(req.Reason),
+		Compensation:         compensation,
+		ProjectID:            req.ProjectID,
+		Status:               models.OvertimeStatusPending,
+		CurrentApprovalStage: models.ApprovalStageSupervisor,
+	}
+	err = s.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(request).Error; err != nil {
+			return err
+		}
+		var auto []*models.OvertimeApproval
+		switch {
+		case supervisor.Role == enums.RoleSupAndGM:
+			auto = []*models.OvertimeApproval{
+				{ApproverID: supervisor.ID, ApprovalStage: models.ApprovalStageSupervisor, Comments: "Auto-approved (Supervisor is also General Manager)"},
+				{ApproverID: supervisor.ID, ApprovalStage: models.ApprovalStageManager, Comments: "Auto-approved (User is both Supervisor and General Manager)"},
+			}
+		case isManager:
+			auto = []*models.OvertimeApproval{
+				{ApproverID: requester.ID, ApprovalStage: models.ApprovalStageSupervisor, Comments: "Requested by the supervisor"},
+			}
+		}
+		for _, approval := range auto {
+			approval.RequestID = request.ID
+			approval.Action = models.ApprovalActionApproved
+			if err := tx.Create(approval).Error; err != nil {
+				return err
+			}
+			request.CurrentApprovalStage = nextApprovalStage(approval.ApprovalStage, isBlueOrGrayCollar(&employee))
+		}
+		if len(auto) > 0 {
+			if err := tx.Model(request).Update("current_approval_stage", request.CurrentApprovalStage).Error; err != nil {
+				return err
+			}
+		}
+		return s.notifyStage(tx, request, &employee, supervisor.ID)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error creating overtime request: %w", err)
+	}
+	return request, nil
+}
+
+// checkLimits refuses a request that would break the employee's weekly overtime limits
+func (s *OvertimeService) checkLimits(employee *models.Employee, date, start, end time.Time, hours float64) error {
+	policy, _, err := s.policies.ResolvePolicy(employee)
+	if err != nil {
+		return err
+	}
+	monday := policyWeekStart(date)
+	var existing []models.OvertimeRequest
+	if err := s.db.Where("employee_id = ? AND status IN ? AND request_date >= ? AND request_date < ?",
+		employee.ID, []string{models.OvertimeStatusPending, models.OvertimeStatusApproved, models.OvertimeStatusCompleted},
+		monday, monday.AddDate(0, 0, 7)).Find(&existing).Error; err != nil {
+		return fmt.Errorf("error fetching overtime requests: %w", err)
+	}
+
+	key := date.Format(attendanceDateKey)
+	dayHours, weekHours := hours, hours
+	days := map[string]bool{key: true}
+	for _, r := range existing {
+		if r.StartTime.Before(end) && start.Before(r.EndTime) {
+			return errors.New("an overtime request already exists for this time")
+		}
+		rKey := attendanceDate(r.RequestDate).Format(attendanceDateKey)
+		days[rKey] = true
+		weekHours += r.EstimatedHours
+		if rKey == key {
+			dayHours += r.EstimatedHours
+		}
+	}
+	switch {
+	case policy.MaxDailyOvertimeHours > 0 && dayHours > policy.MaxDailyOvertimeHours:
+		return fmt.Errorf("overtime must not exceed %.2f hours a day (%.2f requested for %s)", policy.MaxDailyOvertimeHours, dayHours, key)
+	case policy.MaxOvertimeDaysPerWeek > 0 && len(days) > policy.MaxOvertimeDaysPerWeek:
+		return fmt.Errorf("overtime must not be requested on more than %d days a week", policy.MaxOvertimeDaysPerWeek)
+	case policy.WeeklyDoubleTimeHours > 0 && weekHours > policy.WeeklyDoubleTimeHours:
+		return fmt.Errorf("overtime must not exceed %.2f hours a week (%.2f requested)", policy.WeeklyDoubleTimeHours, weekHours)
+	}
+	return nil
+}
+
+// overtimeWindow parses the planned "HH:MM" times on date; an end at or
+// before the start ends the next day
+func overtimeWindow(date time.Time, startTime, endTime string) (time.Time, time.Time, error) {
+	parse := func(value string) (time.Time, error) {
+		t, err := time.Parse("15:04", strings.TrimSpace(value))
+		if err != nil {
+			return time.Time{}, fmt.Errorf("time %q must be HH:MM", value)
+		}
+		return time.Date(date.Year(), date.Month(), date.Day(), t.Hour(), t.Minute(), 0, 0, date.Location()), nil
+	}
+	start, err := parse(startTime)
+	if err != nil {
+		return start, start, err
+	}
+	end, err := parse(endTime)
+	if err != nil {
+		return start, end, err
+	}
+	if !end.After(start) {
+		end = end.AddDate(0, 0, 1)
+	}
+	return start, end, nil
+}
+
+// managesEmployee reports whether the user is the employee's approver or a
+// manager above them in the reporting chain
+func (s *OvertimeService) managesEmployee(user *models.User, employeeID uuid.UUID) bool {
+	if user.EmployeeID == nil || *user.EmployeeID == employeeID {
+		return false
+	}
+	if resolution, err := s.orgStructure.ResolveApprover(employeeID, time.Now()); err == nil &&
+		resolution.ApproverUserID != nil && *resolution.ApproverUserID == user.ID {
+		return true
+	}
+	chain, err := s.orgStructure.ManagerChain(employeeID)
+	if err != nil {
+		return false
+	}
+	for _, id := range chain {
+		if id == *user.EmployeeID {
+			return true
+		}
+	}
+	return false
+}
+
+// Act approves or declines a request at its current stage
+func (s *OvertimeService) Act(requestID, companyID, approverID uuid.UUID, req dtos.OvertimeActionRequest) (*models.OvertimeRequest, error) {
+	var request models.OvertimeRequest
+	if err := s.db.Preload("Employee").First(&request, "id = ? AND company_id = ?", requestID, companyID).Error; err != nil {
+		return nil, errors.New("overtime request not found")
+	}
+	if request.Status != models.OvertimeStatusPending {
+		return nil, errors.New("overtime request must be pending to approve or decline it")
+	}
+	var approver models.User
+	if err := s.db.First(&approver, "id = ? AND company_id = ?", approverID, companyID).Error; err != nil {
+		return nil, errors.New("approver not found")
+	}
+	stage := request.CurrentApprovalStage
+	if !canApproveStage(approver.Role, stage) {
+		return nil, ErrOvertimeNotAllowed
+	}
+	if stage == models.ApprovalStageSupervisor && !s.managesEmployee(&approver, request.EmployeeID) {
+		return nil, ErrOvertimeNotAllowed
+	}
+
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		history := &models.OvertimeApproval{
+			RequestID:     request.ID,
+			ApproverID:    approver.ID,
+			ApprovalStage: stage,
+			Action:        req.Action,
+			Comments:      req.Comments,
+		}
+		if err := tx.Create(history).Error; err != nil {
+			return err
+		}
+
+		now := time.Now()
+		var next models.ApprovalStage
+		if req.Action == models.ApprovalActionApproved && request.Employee != nil {
+			next = nextApprovalStage(stage, isBlueOrGrayCollar(request.Employee))
+		}
+		switch {
+		case req.Action == models.ApprovalActionDeclined:
+			request.Status = models.OvertimeStatusRejected
+			request.CurrentApprovalStage = models.ApprovalStageCompleted
+			request.RejectedByID = &approver.ID
+			request.RejectedAt = &now
+			request.RejectionReason = req.Comments
+		case next == "":
+			// The bank may have filled up since the request was filed
+			if request.Compensation == models.OvertimeCompensationBank && request.Employee != nil {
+				if err := s.timeBank.withDB(tx).CheckDeposit(request.Employee, request.EstimatedHours, &request.ID); err != nil {
+					return err
+				}
+			}
+			request.Status = models.OvertimeStatusApproved
+			request.CurrentApprovalStage = models.ApprovalStageCompleted
+			request.ApprovedByID = &approver.ID
+			request.ApprovedAt = &now
+			request.ApprovalNotes = req.Comments
+			request.AuthorizedHours = request.EstimatedHours
+		default:
+			request.CurrentApprovalStage = next
+		}
+		if err := tx.Omit("Employee", "Approvals").Save(&request).Error; err != nil {
+			return err
+		}
+		return s.notifyStage(tx, &request, request.Employee, approver.ID)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error updating overtime request: %w", err)
+	}
+	return &request, nil
+}
+
+// Cancel withdraws a pending request, or an approved one before its day
+func (s *OvertimeService) Cancel(requestID, companyID, userID uuid.UUID) (*models.OvertimeRequest, error) {
+	var request models.OvertimeRequest
+	if err := s.db.First(&request, "id = ? AND company_id = ?", requestID, companyID).Error; err != nil {
+		return nil, errors.New("overtime request not found")
+	}
+	var user models.User
+	if err := s.db.First(&user, "id = ? AND company_id = ?", userID, companyID).Error; err != nil {
+		return nil, errors.New("user not found")
+	}
+	isOwner := sameUUID(request.RequestedByID, &user.ID) || sameUUID(user.EmployeeID, &request.EmployeeID)
+	if !isOwner && !hasUserRole(user.Role, overtimeHRRoles) {
+		return nil, ErrOvertimeNotAllowed
+	}
+	switch {
+	case request.Status == models.OvertimeStatusPending:
+	case request.Status == models.OvertimeStatusApproved && !attendanceDate(request.RequestDate).Before(attendanceDate(time.Now())):
+	default:
+		return nil, errors.New("overtime request cannot be cancelled once it is worked, declined or reconciled")
+	}
+	request.Status = models.OvertimeStatusCancelled
+	request.CurrentApprovalStage = models.ApprovalStageCompleted
+	request.AuthorizedHours = 0
+	if err := s.db.Save(&request).Error; err != nil {
+		return nil, fmt.Errorf("error cancelling overtime request: %w", err)
+	}
+	return &request, nil
+}
+
+// ListRequests returns the company's overtime requests, newest day first
+func (s *OvertimeService) ListRequests(companyID uuid.UUID, filter dtos.OvertimeRequestFilter) ([]models.OvertimeRequest, error) {
+	query := s.db.Preload("Employee").Preload("Approvals", func(db *gorm.DB) *gorm.DB {
+		return db.Order("created_at")
+	}).Where("company_id = ?", companyID)
+	if filter.EmployeeID != nil {
+		query = query.Where("employee_id = ?", *filter.EmployeeID)
+	}
+	if filter.Status != "" {
+		query = query.Where("status = ?", filter.Status)
+	}
+	if filter.StartDate != nil {
+		query = query.Where("request_date >= ?", attendanceDate(*filter.StartDate))
+	}
+	if filter.EndDate != nil {
+		query = query.Where("request_date < ?", attendanceDate(*filter.EndDate).AddDate(0, 0, 1))
+	}
+	var requests []models.OvertimeRequest
+	if err := query.Order("request_date DESC, created_at DESC").Find(&requests).Error; err != nil {
+		return nil, fmt.Errorf("error fetching overtime requests: %w", err)
+	}
+	return requests, nil
+}
+
+// MyRequests returns the requests for the user's employee record or filed by the user
+func (s *OvertimeService) MyRequests(companyID, userID uuid.UUID) ([]models.OvertimeRequest, error) {
+	var user models.User
+	if err := s.db.First(&user, "id = ? AND company_id = ?", userID, companyID).Error; err != nil {
+		return nil, errors.New("user not found")
+	}
+	query := s.db.Preload("Employee").Preload("Approvals").Where("company_id = ?", companyID)
+	if user.EmployeeID != nil {
+		query = query.Where("employee_id = ? OR requested_by_id = ?", *user.EmployeeID, user.ID)
+	} else {
+		query = query.Where("requested_by_id = ?", user.ID)
+	}
+	var requests []models.OvertimeRequest
+	if err := query.Order("request_date DESC").Find(&requests).Error; err != nil {
+		return nil, fmt.Errorf("error fetching overtime requests: %w", err)
+	}
+	return requests, nil
+}
+
+// PendingForApprover returns the pending requests the user can act on
+func (s *OvertimeService) PendingForApprover(companyID, approverID uuid.UUID) ([]models.OvertimeRequest, error) {
+	var approver models.User
+	if err := s.db.First(&approver, "id = ? AND company_id = ?", approverID, companyID).Error; err != nil {
+		return nil, errors.New("user not found")
+	}
+	var stages []models.ApprovalStage
+	for _, stage := range overtimeStages {
+		if stage != models.ApprovalStageSupervisor && canApproveStage(approver.Role, stage) {
+			stages = append(stages, stage)
+		}
+	}
+
+	var requests []models.OvertimeRequest
+	base := func() *gorm.DB {
+		return s.db.Preload("Employee").Preload("Approvals").
+			Where("company_id = ? AND status = ?", companyID, models.OvertimeStatusPending)
+	}
+	if len(stages) > 0 {
+		if err := base().Where("current_approval_stage IN ?", stages).Order("request_date").Find(&requests).Error; err != nil {
+			return nil, fmt.Errorf("error fetching overtime requests: %w", err)
+		}
+	}
+	if canApproveStage(approver.Role, models.ApprovalStageSupervisor) && approver.EmployeeID != nil {
+		team, err := s.orgStructure.SubordinateIDs(*approver.EmployeeID, false)
+		if err != nil {
+			return nil, err
+		}
+		if len(team) > 0 {
+			var supervised []models.OvertimeRequest
+			if err := base().Where("current_approval_stage = ? AND employee_id IN ?", models.ApprovalStageSupervisor, team).
+				Order("request_date").Find(&supervised).Error; err != nil {
+				return nil, fmt.Errorf("error fetching overtime requests: %w", err)
+			}
+			requests = append(supervised, requests...)
+		}
+	}
+	return requests, nil
+}
+
+// =========================================================================
+// Period close
+// =========================================================================
+
+// Reconcile compares the approved requests of a period with the overtime on
+// the closed clock records, completes them and lists unauthorized overtime
+func (s *OvertimeService) Reconcile(companyID uuid.UUID, req dtos.OvertimeReconcileRequest) (*dtos.OvertimeReconciliation, error) {
+	var start, end time.Time
+	switch {
+	case req.PayrollPeriodID != nil:
+		var period models.PayrollPeriod
+		if err := s.db.First(&period, "id = ?", *req.PayrollPeriodID).Error; err != nil {
+			return nil, errors.New("payroll period not found")
+		}
+		start, end = period.StartDate, period.EndDate
+	case req.StartDate != nil && req.EndDate != nil:
+		start, end = req.StartDate.Time, req.EndDate.Time
+	default:
+		return nil, errors.New("payroll_period_id or start_date and end_date are required")
+	}
+	start, end = attendanceDate(start), attendanceDate(end)
+
+	var requests []models.OvertimeRequest
+	if err := s.db.Preload("Employee").
+		Where("company_id = ? AND status IN ? AND request_date >= ? AND request_date < ?", companyID,
+			[]string{models.OvertimeStatusApproved, models.OvertimeStatusCompleted}, start, end.AddDate(0, 0, 1)).
+		Order("request_date").Find(&requests).Error; err != nil {
+		return nil, fmt.Errorf("error fetching overtime requests: %w", err)
+	}
+
+	report := &dtos.OvertimeReconciliation{
+		StartDate:    start,
+		EndDate:      end,
+		Lines:        []dtos.OvertimeReconciliationLine{},
+		Unauthorized: []dtos.UnauthorizedOvertimeDay{},
+	}
+	now := time.Now()
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		for i := range requests {
+			r := &requests[i]
+			actual, err := workedOvertime(tx, r.EmployeeID, attendanceDate(r.RequestDate))
+			if err != nil {
+				return err
+			}
+			if err := tx.Model(r).Updates(map[string]interface{}{
+				"actual_hours": actual,
+				"status":       models.OvertimeStatusCompleted,
+				"completed_at": now,
+			}).Error; err != nil {
+				return err
+			}
+			// Banked overtime is deposited instead of paid, up to what was authorized
+			banked := 0.0
+			if r.Compensation == models.OvertimeCompensationBank {
+				banked = roundHours(math.Min(actual, r.AuthorizedHours))
+				if err := s.timeBank.withDB(tx).Deposit(r, banked); err != nil {
+					return err
+				}
+			}
+			line := dtos.OvertimeReconciliationLine{
+				RequestID:       r.ID,
+				EmployeeID:      r.EmployeeID,
+				Date:            r.RequestDate,
+				AuthorizedHours: r.AuthorizedHours,
+				ActualHours:     actual,
+				ExcessHours:     roundHours(math.Max(actual-r.AuthorizedHours, 0)),
+				UnusedHours:     roundHours(math.Max(r.AuthorizedHours-actual, 0)),
+				BankedHours:     banked,
+			}
+			if r.Employee != nil {
+				line.EmployeeNumber = r.Employee.EmployeeNumber
+				line.EmployeeName = strings.TrimSpace(r.Employee.FirstName + " " + r.Employee.LastName)
+			}
+			report.Lines = append(report.Lines, line)
+			report.Reconciled++
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error reconciling overtime: %w", err)
+	}
+
+	var days []models.AttendanceDay
+	if err := s.db.Preload("Employee").
+		Where("company_id = ? AND work_date >= ? AND work_date < ? AND unauthorized_overtime_hours > 0", companyID, start, end.AddDate(0, 0, 1)).
+		Order("work_date").Find(&days).Error; err != nil {
+		return nil, fmt.Errorf("error fetching attendance days: %w", err)
+	}
+	for _, day := range days {
+		row := dtos.UnauthorizedOvertimeDay{
+			AttendanceDayID: day.ID,
+			EmployeeID:      day.EmployeeID,
+			Date:            day.WorkDate,
+			Hours:           day.UnauthorizedOvertimeHours,
+		}
+		if day.Employee != nil {
+			row.EmployeeNumber = day.Employee.EmployeeNumber
+			row.EmployeeName = strings.TrimSpace(day.Employee.FirstName + " " + day.Employee.LastName)
+		}
+		report.Unauthorized = append(report.Unauthorized, row)
+	}
+	return report, nil
+}
+
+// workedOvertime sums the double and triple hours of the closed clock records started on date
+func workedOvertime(db *gorm.DB, employeeID uuid.UUID, date time.Time) (float64, error) {
+	var total struct{ Hours float64 }
+	err := db.Model(&models.ClockRecord{}).
+		Select("COALESCE(SUM(double_time_hours + triple_time_hours), 0) AS hours").
+		Where("employee_id = ? AND is_complete = ? AND clock_in_time >= ? AND clock_in_time < ?",
+			employeeID, true, date, date.AddDate(0, 0, 1)).
+		Where("review_status IS NULL OR review_status <> ?", models.ClockReviewRejected).
+		Scan(&total).Error
+	return roundHours(total.Hours), err
+}
+
+// =========================================================================
+// Notifications
+// =========================================================================
+
+// notifyStage tells the employee about a decision, or the next approvers about a pending stage
+func (s *OvertimeService) notifyStage(tx *gorm.DB, request *models.OvertimeRequest, employee *models.Employee, actorID uuid.UUID) error {
+	name := ""
+	if employee != nil {
+		name = strings.TrimSpace(employee.FirstName + " " + employee.LastName)
+	}
+	day := request.RequestDate.Format("2006-01-02")
+
+	var targets []uuid.UUID
+	var notificationType models.NotificationType
+	var message string
+	switch request.Status {
+	case models.OvertimeStatusApproved, models.OvertimeStatusRejected:
+		if err := tx.Model(&models.User{}).Where("employee_id = ?", request.EmployeeID).Pluck("id", &targets).Error; err != nil {
+			return err
+		}
+		if request.RequestedByID != nil {
+			targets = append(targets, *request.RequestedByID)
+		}
+		notificationType = models.NotificationIncidenceApproved
+		message = fmt.Sprintf("Tiempo extra del %s (%.2f h) de %s autorizado", day, request.EstimatedHours, name)
+		if request.Status == models.OvertimeStatusRejected {
+			notificationType = models.NotificationIncidenceRejected
+			message = fmt.Sprintf("Tiempo extra del %s de %s rechazado", day, name)
+		}
+	case models.OvertimeStatusPending:
+		notificationType = models.NotificationIncidenceCreated
+		message = fmt.Sprintf("Solicitud de tiempo extra de %s para el %s (%.2f h) pendiente de aprobación", name, day, request.EstimatedHours)
+		if request.CurrentApprovalStage == models.ApprovalStageSupervisor {
+			if resolution, err := s.orgStructure.ResolveApprover(request.EmployeeID, time.Now()); err == nil && resolution.ApproverUserID != nil {
+				targets = append(targets, *resolution.ApproverUserID)
+			}
+		} else {
+			var users []models.User
+			if err := tx.Where("company_id = ? AND is_active = ?", request.CompanyID, true).Find(&users).Error; err != nil {
+				return err
+			}
+			for _, u := range users {
+				if canApproveStage(u.Role, request.CurrentApprovalStage) {
+					targets = append(targets, u.ID)
+				}
+			}
+		}
+	}
+
+	seen := make(map[uuid.UUID]bool)
+	for _, target := range targets {
+		if seen[target] {
+			continue
+		}
+		seen[target] = true
+		targetID := target
+		if err := tx.Create(&models.Notification{
+			CompanyID:    request.CompanyID,
+			ActorUserID:  actorID,
+			TargetUserID: &targetID,
+			Type:         notificationType,
+			Title:        "Tiempo Extra",
+			Message:      message,
+			ResourceType: "overtime_request",
+			ResourceID:   &request.ID,
+		}).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// hasUserRole reports whether role is one of roles
+func hasUserRole(role enums.UserRole, roles []enums.UserRole) bool {
+	for _, r := range roles {
+		if r == role {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,161 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"backend/internal/dtos"
+	"backend/internal/models"
+)
+
+func TestOvertime_PreAuthorizationChainAndReconciliation(t *testing.T) {
+	db := setupPayrollTestDB(t)
+	require.NoError(t, db.AutoMigrate(
+		&models.User{}, &models.ReportingLine{}, &models.EmployeeHierarchy{}, &models.AbsenceRequest{},
+		&models.CostCenter{}, &models.IncidenceType{}, &models.Incidence{}, &models.SickLeaveCertificate{}, &models.Holiday{}, &models.Shift{},
+		&models.EmployeeShiftBase{}, &models.ShiftException{}, &models.ClockRecord{}, &models.AttendanceDay{},
+		&models.TimePolicy{}, &models.TimePolicyViolation{}, &models.OvertimeRequest{}, &models.OvertimeApproval{},
+		&models.Notification{}, &models.RotationPattern{}, &models.RotationPatternDay{}, &models.RotationAssignment{},
+	))
+	company := createPayrollTestCompany(t, db)
+	service := NewOvertimeService(db)
+
+	director := createFixtureEmployee(t, db, company.ID, 1, "general", 900)
+	supervisor := createFixtureEmployee(t, db, company.ID, 2, "general", 600)
+	operator := createFixtureEmployee(t, db, company.ID, 3, "general", 400)
+	colleague := createFixtureEmployee(t, db, company.ID, 4, "general", 400)
+	directorUser := createFixtureUser(t, db, director, "manager")
+	supervisorUser := createFixtureUser(t, db, supervisor, "supervisor")
+	operatorUser := createFixtureUser(t, db, operator, "employee")
+	hrUser := createFixtureUser(t, db, colleague, "hr")
+	payrollUser := &models.User{CompanyID: company.ID, Email: "nomina@example.com", PasswordHash: "hashedpassword123",
+		Role: "payroll_staff", FullName: "Nómina", IsActive: true}
+	require.NoError(t, db.Create(payrollUser).Error)
+
+	orgStructure := NewOrgStructureService(db)
+	for employee, manager := range map[*models.Employee]*models.Employee{operator: supervisor, supervisor: director} {
+		_, err := orgStructure.SetManager(company.ID, dtos.ReportingLineRequest{
+			EmployeeID: employee.ID, ManagerID: &manager.ID, EffectiveFrom: dtos.Date{Time: time.Now()},
+		}, uuid.New())
+		require.NoError(t, err)
+	}
+
+	// Next week's Monday, so the requests are filed ahead of time
+	monday := policyWeekStart(attendanceDate(time.Now())).AddDate(0, 0, 7)
+	file := func(user *models.User, employee *models.Employee, day int, start, end string) (*models.OvertimeRequest, error) {
+		return service.CreateRequest(company.ID, user.ID, dtos.OvertimeRequestInput{
+			EmployeeID: &employee.ID, Date: dtos.Date{Time: monday.AddDate(0, 0, day)},
+			StartTime: start, EndTime: end, Reason: "Cierre de inventario",
+		})
+	}
+
+	// The supervisor files for the operator: their own stage is approved
+	mon, err := file(supervisorUser, operator, 0, "18:00", "20:00")
+	require.NoError(t, err)
+	assert.Equal(t, 2.0, mon.EstimatedHours)
+	assert.Equal(t, models.ApprovalStageManager, mon.CurrentApprovalStage)
+
+	_, err = file(supervisorUser, operator, 0, "19:00", "21:00")
+	assert.ErrorContains(t, err, "already exists")
+	_, err = file(supervisorUser, operator, 0, "20:00", "22:00")
+	assert.ErrorContains(t, err, "a day", "2h + 2h is over the 3 daily hours")
+	tue, err := file(supervisorUser, operator, 1, "18:00", "20:00")
+	require.NoError(t, err)
+	wed, err := file(operatorUser, operator, 2, "18:00", "20:00")
+	require.NoError(t, err)
+	assert.Equal(t, models.ApprovalStageSupervisor, wed.CurrentApprovalStage)
+	_, err = file(supervisorUser, operator, 3, "18:00", "19:00")
+	assert.ErrorContains(t, err, "days a week")
+
+	_, err = file(operatorUser, colleague, 0, "18:00", "19:00")
+	assert.ErrorIs(t, err, ErrOvertimeNotAllowed)
+	_, err = file(operatorUser, operator, -14, "18:00", "19:00")
+	assert.ErrorContains(t, err, "before the day")
+
+	pending, err := service.PendingForApprover(company.ID, supervisorUser.ID)
+	require.NoError(t, err)
+	require.Len(t, pending, 1)
+	assert.Equal(t, wed.ID, pending[0].ID)
+
+	// Manager → HR (blue collar) → general manager → payroll
+	approve := func(user *models.User, request *models.OvertimeRequest, action models.ApprovalAction) (*models.OvertimeRequest, error) {
+		return service.Act(request.ID, company.ID, user.ID, dtos.OvertimeActionRequest{Action: action})
+	}
+	_, err = approve(supervisorUser, mon, models.ApprovalActionApproved)
+	assert.ErrorIs(t, err, ErrOvertimeNotAllowed)
+	for _, step := range []struct {
+		user  *models.User
+		stage models.ApprovalStage
+	}{
+		{directorUser, models.ApprovalStageHRBlueGray},
+		{hrUser, models.ApprovalStageGeneralManager},
+		{directorUser, models.ApprovalStagePayroll},
+		{payrollUser, models.ApprovalStageCompleted},
+	} {
+		mon, err = approve(step.user, mon, models.ApprovalActionApproved)
+		require.NoError(t, err)
+		assert.Equal(t, step.stage, mon.CurrentApprovalStage)
+	}
+	assert.Equal(t, models.OvertimeStatusApproved, mon.Status)
+	assert.Equal(t, 2.0, mon.AuthorizedHours)
+
+	tue, err = approve(directorUser, tue, models.ApprovalActionDeclined)
+	require.NoError(t, err)
+	assert.Equal(t, models.OvertimeStatusRejected, tue.Status)
+	_, err = service.Cancel(tue.ID, company.ID, operatorUser.ID)
+	assert.ErrorContains(t, err, "cannot be cancelled")
+
+	// Monday 3h over the shift with 2h authorized; Wednesday 1h never approved
+	shift := &models.Shift{Name: "Matutino", Code: "MAT", StartTime: "09:00", EndTime: "18:00",
+		BreakMinutes: 60, WorkHoursPerDay: 8, WorkDays: "[1,2,3,4,5]", CompanyID: company.ID, IsActive: true}
+	require.NoError(t, db.Create(shift).Error)
+	require.NoError(t, db.Model(operator).UpdateColumn("shift_id", shift.ID).Error)
+	operator.ShiftID = &shift.ID
+	at := func(day, hour int) time.Time { return monday.AddDate(0, 0, day).Add(time.Duration(hour) * time.Hour) }
+	for _, record := range []struct {
+		day, in, out int
+		double       float64
+	}{{0, 9, 21, 3}, {2, 9, 19, 1}} {
+		out := at(record.day, record.out)
+		require.NoError(t, db.Create(&models.ClockRecord{CompanyID: company.ID, EmployeeID: operator.ID,
+			ClockInTime: at(record.day, record.in), ClockOutTime: &out, BreakMinutes: 60, IsComplete: true,
+			Status: "completed", DoubleTimeHours: record.double}).Error)
+	}
+
+	evaluator := NewAttendanceEvaluationService(db, nil)
+	evaluator.rules.RequireOvertimeAuthorization = true
+	days, err := evaluator.EvaluateRange(operator, monday, monday.AddDate(0, 0, 6), nil)
+	require.NoError(t, err)
+	summary := evaluator.Summarize(operator.ID, monday, monday.AddDate(0, 0, 6), days)
+	assert.Equal(t, 2.0, days[0].OvertimeHours)
+	assert.Equal(t, 1.0, days[0].UnauthorizedOvertimeHours)
+	assert.Equal(t, 0.0, days[2].OvertimeHours)
+	assert.Equal(t, 1.0, days[2].UnauthorizedOvertimeHours)
+	assert.Equal(t, 2.0, summary.UnauthorizedOvertimeHours)
+	assert.Equal(t, 2, summary.UnauthorizedOvertimeDays)
+
+	_, err = service.Cancel(wed.ID, company.ID, operatorUser.ID)
+	require.NoError(t, err)
+
+	report, err := service.Reconcile(company.ID, dtos.OvertimeReconcileRequest{
+		StartDate: &dtos.Date{Time: monday}, EndDate: &dtos.Date{Time: monday.AddDate(0, 0, 6)},
+	})
+	require.NoError(t, err)
+	require.Len(t, report.Lines, 1)
+	assert.Equal(t, 3.0, report.Lines[0].ActualHours)
+	assert.Equal(t, 1.0, report.Lines[0].ExcessHours)
+	assert.Len(t, report.Unauthorized, 2)
+
+	var completed models.OvertimeRequest
+	require.NoError(t, db.First(&completed, "id = ?", mon.ID).Error)
+	assert.Equal(t, models.OvertimeStatusCompleted, completed.Status)
+	assert.Equal(t, 3.0, completed.ActualHours)
+
+	var notifications int64
+	db.Model(&models.Notification{}).Where("resource_type = ?", "overtime_request").Count(&notifications)
+	assert.Positive(t, notifications)
+}
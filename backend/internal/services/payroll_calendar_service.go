@@ -0,0 +1,302 @@
+/*
+Package services - Payroll Calendar Service
+
+==============================================================================
+FILE: internal/services/payroll_calendar_service.go
+==============================================================================
+
+DESCRIPTION:
+    Generates the complete fiscal-year calendar of payroll periods for a pay
+    group (frequency), including prenómina cut-off dates and holiday-aware
+    payment dates. Complements GenerateCurrentPeriods, which only creates the
+    period containing today.
+
+USER PERSPECTIVE:
+    - Payroll staff create the whole year of periods at once
+    - Payment dates that fall on a weekend or holiday move to the previous
+      business day (employees are never paid late)
+    - Each period carries the prenómina cut-off date for incidence capture
+    - Employees see which days of a range they work (WorkCalendarService)
+
+DEVELOPER GUIDELINES:
+    ✅  OK to modify: Default pay weekday, default cut-off business days
+    ⚠️  CAUTION: Period numbering (must match the NumPeriodo reported to SAT)
+    ❌  DO NOT modify: Period code format (see models.PayrollPeriod.Validate)
+    📝  Existing periods are never overwritten; the generator is idempotent
+
+SYNTAX EXPLANATION:
+    - Weekly: one period per pay weekday in the year (52 or 53), the period
+      covers the 7 days ending the day before payment
+    - Biweekly: 24 quincenas (1-15 and 16-end of month), paid on the last day
+    - Monthly: 12 calendar months, paid on the last day
+    - Period numbers are sequential within the fiscal year (1..N)
+
+==============================================================================
+*/
+package services
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"backend/internal/config"
+	"backend/internal/dtos"
+	"backend/internal/models"
+	"backend/internal/repositories"
+)
+
+const (
+	defaultPaymentWeekday     = time.Friday
+	defaultCutoffBusinessDays = 2
+)
+
+// PayrollCalendarService generates annual payroll calendars
+type PayrollCalendarService struct {
+	db             *gorm.DB
+	holidayCatalog *HolidayCatalogService
+	workCalendar   *WorkCalendarService
+}
+
+// NewPayrollCalendarService creates a new PayrollCalendarService
+func NewPayrollCalendarService(db *gorm.DB, appConfig *config.AppConfig) *PayrollCalendarService {
+	return &PayrollCalendarService{
+		db:             db,
+		holidayCatalog: NewHolidayCatalogService(db, appConfig),
+		workCalendar:   NewWorkCalendarService(db, appConfig),
+	}
+}
+
+// calendarSpan is a period before payment-date adjustment
+type calendarSpan struct {
+	number         int
+	start          time.Time
+	end            time.Time
+	nominalPayment time.Time
+}
+
+// GenerateFiscalYear builds (and unless DryRun, persists) every period of the year for a frequency
+func (s *PayrollCalendarService) GenerateFiscalYear(companyID uuid.UUID, createdBy *uuid.UUID, req dtos.GeneratePayrollCalendarRequest) (*dtos.PayrollCalendarResponse, error) {
+	cutoffDays := req.CutoffBusinessDays
+	if cutoffDays <= 0 {
+		cutoffDays = defaultCutoffBusinessDays
+	}
+
+	var spans []calendarSpan
+	switch req.Frequency {
+	case "weekly":
+		weekday := defaultPaymentWeekday
+		if req.PaymentWeekday != nil {
+			if *req.PaymentWeekday < 0 || *req.PaymentWeekday > 6 {
+				return nil, fmt.Errorf("payment weekday must be between 0 (Sunday) and 6 (Saturday)")
+			}
+			weekday = time.Weekday(*req.PaymentWeekday)
+		}
+		spans = weeklySpans(req.Year, weekday)
+	case "biweekly":
+		spans = biweeklySpans(req.Year)
+	case "monthly":
+		spans = monthlySpans(req.Year)
+	default:
+		return nil, fmt.Errorf("invalid frequency: %s", req.Frequency)
+	}
+
+	// Holidays for the whole range, with margin for cut-offs at the start of the year
+	rangeStart := spans[0].start.AddDate(0, 0, -14)
+	rangeEnd := spans[len(spans)-1].nominalPayment
+	calc, err := s.holidayCatalog.BusinessDayCalculator(companyID, rangeStart, rangeEnd)
+	if err != nil {
+		return nil, fmt.Errorf("error loading holiday catalog: %w", err)
+	}
+	holidays, err := s.holidayCatalog.GetHolidays(companyID, time.Date(req.Year, 1, 1, 0, 0, 0, 0, time.Local), time.Date(req.Year, 12, 31, 0, 0, 0, 0, time.Local))
+	if err != nil {
+		return nil, fmt.Errorf("error loading holiday catalog: %w", err)
+	}
+	holidayNames := make(map[string]string, len(holidays))
+	for _, h := range holidays {
+		holidayNames[h.Date.Format("2006-01-02")] = h.Name
+	}
+
+	response := &dtos.PayrollCalendarResponse{
+		Year:      req.Year,
+		Frequency: req.Frequency,
+		Holidays:  holidays,
+	}
+
+	err = s.db.Transaction(func(tx *gorm.DB) error {
+		periodRepo := repositories.NewPayrollPeriodRepository(tx)
+
+		for i, span := range spans {
+			payment := calc.PreviousBusinessDay(span.nominalPayment)
+			cutoff := calc.SubtractBusinessDays(payment, cutoffDays)
+
+			entry := dtos.PayrollCalendarPeriod{
+				PeriodCode:          periodCodeFor(req.Frequency, req.Year, span.number),
+				PeriodNumber:        span.number,
+				StartDate:           span.start,
+				EndDate:             span.end,
+				NominalPaymentDate:  span.nominalPayment,
+				PaymentDate:         payment,
+				PrenominaCutoffDate: cutoff,
+				PaymentMoved:        !payment.Equal(span.nominalPayment),
+			}
+			if entry.PaymentMoved {
+				entry.MoveReason = paymentMoveReason(span.nominalPayment, holidayNames)
+			}
+
+			if existing, _ := periodRepo.FindByPeriodCode(entry.PeriodCode); existing != nil {
+				entry.PeriodID = &existing.ID
+				entry.Status = "existing"
+				response.Skipped++
+				response.Periods = append(response.Periods, entry)
+				continue
+			}
+
+			hasOverlap, err := periodRepo.HasOverlappingPeriod(req.Frequency, span.start, span.end)
+			if err != nil {
+				return fmt.Errorf("error checking for overlapping periods: %w", err)
+			}
+			if hasOverlap {
+				entry.Status = "overlap"
+				response.Skipped++
+				response.Periods = append(response.Periods, entry)
+				continue
+			}
+
+			if req.DryRun {
+				entry.Status = "preview"
+				response.Periods = append(response.Periods, entry)
+				continue
+			}
+
+			period := &models.PayrollPeriod{
+				PeriodCode:          entry.PeriodCode,
+				Year:                req.Year,
+				PeriodNumber:        span.number,
+				StartDate:           span.start,
+				EndDate:             span.end,
+				PaymentDate:         payment,
+				PrenominaCutoffDate: &cutoff,
+				Frequency:           req.Frequency,
+				PeriodType:          req.Frequency,
+				Description:         periodDescription(req.Frequency, span),
+				Status:              "open",
+				IsFiscalClosing:     i == len(spans)-1,
+				CreatedBy:           createdBy,
+			}
+			if err := periodRepo.Create(period); err != nil {
+				return fmt.Errorf("could not create period %s: %w", entry.PeriodCode, err)
+			}
+
+			entry.PeriodID = &period.ID
+			entry.Status = "created"
+			response.Created++
+			response.Periods = append(response.Periods, entry)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	response.TotalPeriods = len(response.Periods)
+	return response, nil
+}
+
+// GetHolidayCatalog returns the merged holiday catalog for a date range
+func (s *PayrollCalendarService) GetHolidayCatalog(companyID uuid.UUID, start, end time.Time) ([]dtos.HolidayCatalogEntry, error) {
+	return s.holidayCatalog.GetHolidays(companyID, start, end)
+}
+
+// GetWorkCalendar returns the days an employee of the company works in a range
+func (s *PayrollCalendarService) GetWorkCalendar(companyID, employeeID uuid.UUID, start, end time.Time) (*dtos.WorkCalendar, error) {
+	return s.workCalendar.Calendar(companyID, employeeID, start, end)
+}
+
+// GetWorkCalendarForUser returns the work calendar of the employee linked to a user
+func (s *PayrollCalendarService) GetWorkCalendarForUser(companyID, userID uuid.UUID, start, end time.Time) (*dtos.WorkCalendar, error) {
+	return s.workCalendar.CalendarForUser(companyID, userID, start, end)
+}
+
+// weeklySpans returns one span per pay weekday of the year (52 or 53)
+func weeklySpans(year int, weekday time.Weekday) []calendarSpan {
+	first := time.Date(year, 1, 1, 0, 0, 0, 0, time.Local)
+	first = first.AddDate(0, 0, (int(weekday)-int(first.Weekday())+7)%7)
+
+	var spans []calendarSpan
+	number := 1
+	for pay := first; pay.Year() == year; pay = pay.AddDate(0, 0, 7) {
+		spans = append(spans, calendarSpan{
+			number:         number,
+			start:          pay.AddDate(0, 0, -7),
+			end:            pay.AddDate(0, 0, -1),
+			nominalPayment: pay,
+		})
+		number++
+	}
+	return spans
+}
+
+// biweeklySpans returns the 24 quincenas of the year (1-15, 16-end of month)
+func biweeklySpans(year int) []calendarSpan {
+	spans := make([]calendarSpan, 0, 24)
+	for month := time.January; month <= time.December; month++ {
+		firstDay := time.Date(year, month, 1, 0, 0, 0, 0, time.Local)
+		fifteenth := time.Date(year, month, 15, 0, 0, 0, 0, time.Local)
+		lastDay := firstDay.AddDate(0, 1, -1)
+
+		spans = append(spans,
+			calendarSpan{number: len(spans) + 1, start: firstDay, end: fifteenth, nominalPayment: fifteenth},
+			calendarSpan{number: len(spans) + 2, start: fifteenth.AddDate(0, 0, 1), end: lastDay, nominalPayment: lastDay},
+		)
+	}
+	return spans
+}
+
+// monthlySpans returns the 12 calendar months of the year
+func monthlySpans(year int) []calendarSpan {
+	spans := make([]calendarSpan, 0, 12)
+	for month := time.January; month <= time.December; month++ {
+		firstDay := time.Date(year, month, 1, 0, 0, 0, 0, time.Local)
+		lastDay := firstDay.AddDate(0, 1, -1)
+		spans = append(spans, calendarSpan{number: int(month), start: firstDay, end: lastDay, nominalPayment: lastDay})
+	}
+	return spans
+}
+
+// periodCodeFor builds the period code in the format accepted by PayrollPeriod.Validate
+func periodCodeFor(frequency string, year, number int) string {
+	switch frequency {
+	case "weekly":
+		return fmt.Sprintf("%d-W%02d", year, number)
+	case "biweekly":
+		return fmt.Sprintf("%d-BW%02d", year, number)
+	default:
+		return fmt.Sprintf("%d-M%02d", year, number)
+	}
+}
+
+// periodDescription builds the Spanish description shown in "Periodos de Nomina"
+func periodDescription(frequency string, span calendarSpan) string {
+	label := "Mes"
+	switch frequency {
+	case "weekly":
+		label = "Semana"
+	case "biweekly":
+		label = "Quincena"
+	}
+	return fmt.Sprintf("%s %d - %s al %s", label, span.number, span.start.Format("02/01"), span.end.Format("02/01/2006"))
+}
+
+// paymentMoveReason explains why the nominal payment date was not a business day
+func paymentMoveReason(nominal time.Time, holidayNames map[string]string) string {
+	if name, ok := holidayNames[nominal.Format("2006-01-02")]; ok {
+		return fmt.Sprintf("Día festivo: %s", name)
+	}
+	if nominal.Weekday() == time.Saturday || nominal.Weekday() == time.Sunday {
+		return "Fin de semana"
+	}
+	return "Día inhábil"
+}
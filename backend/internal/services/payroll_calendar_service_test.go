@@ -0,0 +1,105 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+
+	"backend/internal/dtos"
+	"backend/internal/models"
+)
+
+func setupPayrollCalendarTestDB(t *testing.T) *gorm.DB {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	require.NoError(t, err)
+	require.NoError(t, db.AutoMigrate(&models.PayrollPeriod{}, &models.Holiday{}))
+	return db
+}
+
+func TestWeeklySpans_PeriodCount(t *testing.T) {
+	// 2026 has 52 Fridays, 2027 has 53 (Jan 1, 2027 is a Friday)
+	assert.Len(t, weeklySpans(2026, time.Friday), 52)
+	assert.Len(t, weeklySpans(2027, time.Friday), 53)
+
+	spans := weeklySpans(2026, time.Friday)
+	assert.Equal(t, 1, spans[0].number)
+	assert.Equal(t, time.Friday, spans[0].nominalPayment.Weekday())
+	assert.Equal(t, time.Thursday, spans[0].end.Weekday())
+	assert.Equal(t, 6, int(spans[0].end.Sub(spans[0].start).Hours()/24))
+}
+
+func TestBiweeklyAndMonthlySpans(t *testing.T) {
+	biweekly := biweeklySpans(2026)
+	assert.Len(t, biweekly, 24)
+	assert.Equal(t, 15, biweekly[0].end.Day())
+	assert.Equal(t, 16, biweekly[1].start.Day())
+	assert.Equal(t, 28, biweekly[3].end.Day()) // February 2026
+
+	monthly := monthlySpans(2026)
+	assert.Len(t, monthly, 12)
+	assert.Equal(t, 31, monthly[11].end.Day())
+}
+
+func TestGenerateFiscalYear_MovesPaymentBeforeHoliday(t *testing.T) {
+	db := setupPayrollCalendarTestDB(t)
+	service := NewPayrollCalendarService(db, nil)
+
+	result, err := service.GenerateFiscalYear(uuid.New(), nil, dtos.GeneratePayrollCalendarRequest{
+		Year:      2026,
+		Frequency: "biweekly",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, 24, result.Created)
+
+	// Quincena 1 is due Thursday Jan 15, 2026 (business day)
+	assert.False(t, result.Periods[0].PaymentMoved)
+
+	// Quincena 2 is due Saturday Jan 31, 2026 → paid Friday Jan 30
+	q2 := result.Periods[1]
+	assert.True(t, q2.PaymentMoved)
+	assert.Equal(t, "2026-01-30", q2.PaymentDate.Format("2006-01-02"))
+	assert.Equal(t, "2026-01-28", q2.PrenominaCutoffDate.Format("2006-01-02"))
+
+	// Last period closes the fiscal year
+	var last models.PayrollPeriod
+	require.NoError(t, db.First(&last, "period_code = ?", "2026-BW24").Error)
+	assert.True(t, last.IsFiscalClosing)
+	assert.Equal(t, "2026-12-31", last.PaymentDate.Format("2006-01-02"))
+}
+
+func TestGenerateFiscalYear_CompanyHolidayAndIdempotency(t *testing.T) {
+	db := setupPayrollCalendarTestDB(t)
+	service := NewPayrollCalendarService(db, nil)
+	companyID := uuid.New()
+
+	// Company holiday on Friday May 15, 2026 (Día del Maestro) moves Quincena 9
+	require.NoError(t, db.Create(&models.Holiday{
+		CompanyID:   companyID,
+		Name:        "Día del Maestro",
+		Date:        time.Date(2026, 5, 15, 0, 0, 0, 0, time.Local),
+		Year:        2026,
+		HolidayType: "company",
+		IsPaid:      true,
+		IsActive:    true,
+		IsRecurring: false,
+	}).Error)
+
+	req := dtos.GeneratePayrollCalendarRequest{Year: 2026, Frequency: "biweekly"}
+	result, err := service.GenerateFiscalYear(companyID, nil, req)
+	require.NoError(t, err)
+
+	q9 := result.Periods[8]
+	assert.Equal(t, "2026-05-14", q9.PaymentDate.Format("2006-01-02"))
+	assert.Contains(t, q9.MoveReason, "Día del Maestro")
+
+	// Second run creates nothing
+	again, err := service.GenerateFiscalYear(companyID, nil, req)
+	require.NoError(t, err)
+	assert.Equal(t, 0, again.Created)
+	assert.Equal(t, 24, again.Skipped)
+}
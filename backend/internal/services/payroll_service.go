@@ -29,7 +29,11 @@ SYNTAX EXPLANATION:
     - CalculatePayroll processes one employee using prenomina metrics
     - CalculatePayrollDirect skips prenomina (for simplified flow)
     - CalculateStatutoryDeductions uses ISR tables and IMSS rates
+    - IMSS quotas are paid on the contribution days: working days minus
+      the IMSS incapacidad days (SickLeaveService)
     - TotalNetPay = GrossIncome - StatutoryDeductions - OtherDeductions
+    - Minimum-wage earners: no ISR on the minimum wage (LISR Art. 96) and
+      discretionary deductions never push net pay below it (LFT Art. 97)
     - ApprovePayroll locks payroll for payment processing
     - ProcessPayment marks payroll as paid and updates period status
 
@@ -102,6 +106,8 @@ func (s *PayrollService) ConvertToPayrollResponse(
         TotalGrossIncome:    payrollCalc.TotalGrossIncome,
         TotalDeductions:     payrollCalc.TotalStatutoryDeductions + payrollCalc.TotalOtherDeductions, // Calculated from model fields
         TotalNetPay:         payrollCalc.TotalNetPay,
+        MinimumWageEarner:   payrollCalc.MinimumWageEarner,
+        DeferredDeductions:  payrollCalc.DeferredDeductions,
 
         // Employer contributions
         EmployerContributions: dtos.EmployerContributionResponse{
@@ -182,8 +188,15 @@ func (s *PayrollService) CalculateEmployerContributions(
         sdi = employee.DailySalary * integrationFactor
     }
 
+    // Days on IMSS incapacidad pay no IMSS quotas (LSS Art. 31); retirement,
+    // SAR and INFONAVIT are still paid for them
     workingDays := float64(period.GetWorkingDays())
     baseForContributions := sdi * workingDays
+    contributionDays, err := s.contributionDays(employee, period)
+    if err != nil {
+        return nil, err
+    }
+    baseForIMSS := sdi * float64(contributionDays)
 
     employerContrib := &models.EmployerContribution{
         PayrollCalculationID: payrollCalc.ID,
@@ -198,20 +211,20 @@ func (s *PayrollService) CalculateEmployerContributions(
     // - Current year rates published by IMSS
 
     // Disease & Maternity (Enfermedad y Maternidad): ~20.4% employer, 0.4% employee
-    employerContrib.IMSSDiseaseMaternity = baseForContributions * 0.204
+    employerContrib.IMSSDiseaseMaternity = baseForIMSS * 0.204
 
     // Work Risk (Riesgo de Trabajo): Varies by company risk class (0.5% to 15%)
     // Using Class I (lowest risk) as default
-    employerContrib.IMSSWorkRisk = baseForContributions * 0.00540
+    employerContrib.IMSSWorkRisk = baseForIMSS * 0.00540
 
     // Disability & Life (Invalidez y Vida): 1.75% employer, 0.625% employee
-    employerContrib.IMSSDisabilityLife = baseForContributions * 0.0175
+    employerContrib.IMSSDisabilityLife = baseForIMSS * 0.0175
 
     // Retirement (Retiro): 2% employer only
     employerContrib.IMSSRetirement = baseForContributions * 0.02
 
     // Childcare (Guardería y Prestaciones Sociales): 1% employer only
-    employerContrib.IMSSChildcare = baseForContributions * 0.01
+    employerContrib.IMSSChildcare = baseForIMSS * 0.01
 
     // INFONAVIT: 5% employer only
     employerContrib.InfonavitEmployer = baseForContributions * 0.05
@@ -293,6 +306,20 @@ func (s *PayrollService) CalculateOtherDeductions(
     // For now, rely on prenomina data or manual adjustments
 }
 
+// contributionDays returns the working days of the period minus the days the
+// employee was on IMSS incapacidad
+func (s *PayrollService) contributionDays(employee *models.Employee, period *models.PayrollPeriod) (int, error) {
+    sickLeave, err := s.sickLeave.forPeriod(employee.ID, period.StartDate, period.EndDate)
+    if err != nil {
+        return 0, err
+    }
+    days := period.GetWorkingDays() - sickLeave.Days()
+    if days < 0 {
+        days = 0
+    }
+    return days, nil
+}
+
 // CalculateStatutoryDeductions calculates all statutory deductions (e.g., ISR, IMSS) for a payroll.
 func (s *PayrollService) CalculateStatutoryDeductions(
     payrollCalc *models.PayrollCalculation,
@@ -315,6 +342,12 @@ func (s *PayrollService) CalculateStatutoryDeductions(
     // In a full implementation, this would exclude exempt amounts per Mexican tax law
     taxableIncome := payrollCalc.RegularSalary + payrollCalc.OvertimeAmount + payrollCalc.VacationPremium + payrollCalc.Aguinaldo + payrollCalc.OtherExtras
 
+    // LISR Art. 96: no ISR is withheld on the general minimum wage, only on what is paid on top of it
+    payrollCalc.MinimumWageEarner = s.minimumWage.IsMinimumWageEarner(employee)
+    if payrollCalc.MinimumWageEarner {
+        taxableIncome -= payrollCalc.RegularSalary
+    }
+
     // Calculate ISR using the tax calculation service with proper tax brackets
     if s.taxCalcService != nil {
         // Use net ISR calculation (after applying employment subsidy)
@@ -338,7 +371,12 @@ func (s *PayrollService) CalculateStatutoryDeductions(
             sdi = employee.DailySalary * integrationFactor
         }
         workingDays := period.GetWorkingDays()
-        payrollCalc.IMSSEmployee = s.taxCalcService.CalculateIMSSEmployee(sdi, workingDays)
+        contributionDays, err := s.contributionDays(employee, period)
+        if err != nil {
+            // Without the incapacidades the whole period is charged
+            contributionDays = workingDays
+        }
+        payrollCalc.IMSSEmployee = s.taxCalcService.CalculateIMSSEmployee(sdi, contributionDays)
 
         // Calculate INFONAVIT employee deduction (if applicable)
         // INFONAVIT deductions depend on whether employee has an active credit
@@ -356,6 +394,54 @@ func (s *PayrollService) CalculateStatutoryDeductions(
     }
 }
 
+// ApplyMinimumWageProtection keeps net pay at or above the minimum wage for the paid days
+// (LFT Art. 97). Discretionary deductions (other, advances, loans - in that order) that
+// would break the floor are reduced and the excess is reported as DeferredDeductions.
+// Statutory deductions are never reduced. Call after the deductions are known.
+func (s *PayrollService) ApplyMinimumWageProtection(
+    payrollCalc *models.PayrollCalculation,
+    employee *models.Employee,
+    paidDays float64,
+) {
+    payrollCalc.DeferredDeductions = 0
+    minimum := s.minimumWage.MinimumFor(employee.MinimumWageZone, employee.ProfessionalOccupation)
+    if minimum <= 0 || paidDays <= 0 {
+        return
+    }
+
+    floor := minimum * paidDays
+    if floor > payrollCalc.TotalGrossIncome {
+        floor = payrollCalc.TotalGrossIncome
+    }
+    statutory := payrollCalc.ISRWithholding + payrollCalc.IMSSEmployee + payrollCalc.InfonavitEmployee + payrollCalc.RetirementSavings
+    available := payrollCalc.TotalGrossIncome - statutory - floor
+    if available < 0 {
+        available = 0
+    }
+
+    discretionary := payrollCalc.LoanDeductions + payrollCalc.AdvanceDeductions + payrollCalc.OtherDeductions
+    excess := roundCurrency(discretionary - available)
+    if excess <= 0 {
+        return
+    }
+    payrollCalc.DeferredDeductions = excess
+
+    for _, deduction := range []*float64{&payrollCalc.OtherDeductions, &payrollCalc.AdvanceDeductions, &payrollCalc.LoanDeductions} {
+        if excess <= 0 {
+            break
+        }
+        cut := *deduction
+        if cut > excess {
+            cut = excess
+        }
+        *deduction -= cut
+        excess -= cut
+    }
+
+    payrollCalc.TotalOtherDeductions = payrollCalc.LoanDeductions + payrollCalc.AdvanceDeductions + payrollCalc.OtherDeductions
+    payrollCalc.TotalNetPay = payrollCalc.TotalGrossIncome - payrollCalc.TotalStatutoryDeductions - payrollCalc.TotalOtherDeductions
+}
+
 // CalculateIncomeComponents calculates all income-related components for a payroll.
 func (s *PayrollService) CalculateIncomeComponents(
     payrollCalc *models.PayrollCalculation,
@@ -379,6 +465,8 @@ type PayrollService struct {
 	taxConfig      *config_payroll.MexicanTaxConfig
 	taxCalcService *TaxCalculationService
 	cfdiService    *CfdiService
+	minimumWage    *MinimumWageService
+	sickLeave      *SickLeaveService
 	db             *gorm.DB
 }
 
@@ -404,6 +492,8 @@ func NewPayrollService(
 		taxConfig:      &appConfig.PayrollConfig.MexicanTaxConfig,
 		taxCalcService: taxCalcService,
 		cfdiService:    NewCfdiService("path/to/cert.cer", "path/to/key.key", "password"),
+		minimumWage:    NewMinimumWageService(db, appConfig),
+		sickLeave:      NewSickLeaveService(db),
 		db:             db,
 	}
 }
@@ -477,6 +567,7 @@ func (s *PayrollService) CalculatePayroll(
     s.CalculateOtherDeductions(payrollCalc, prenominaMetric)
     s.CalculateSubsidiesAndBenefits(payrollCalc, employee)
     s.CalculateTotals(payrollCalc)
+    s.ApplyMinimumWageProtection(payrollCalc, employee, float64(period.GetWorkingDays()))
     
     // Calculate employer contributions
     employerContrib, err := s.CalculateEmployerContributions(employee, payrollCalc, period)
@@ -699,6 +790,12 @@ func (s *PayrollService) CalculatePayrollDirect(
     // Get approved incidences for this employee and period
     incidences, _ := s.incidenceRepo.FindByEmployeeAndPeriod(employee.ID, period.ID)
 
+    // IMSS incapacidades: the IMSS pays the subsidized days, the company the waiting days
+    sickLeave, err := s.sickLeave.forPeriod(employee.ID, period.StartDate, period.EndDate)
+    if err != nil {
+        return nil, err
+    }
+
     // Calculate incidence effects on payroll
     incidenceDeductions := 0.0
     incidenceAdditions := 0.0
@@ -709,6 +806,9 @@ func (s *PayrollService) CalculatePayrollDirect(
         if incidence.Status != "approved" && incidence.Status != "processed" {
             continue
         }
+        if sickLeave.Covers(&incidence) {
+            continue
+        }
 
         // Load incidence type if not preloaded
         var incType models.IncidenceType
@@ -736,6 +836,11 @@ func (s *PayrollService) CalculatePayrollDirect(
                 absenceDays += incidence.Quantity
             }
         case "absence", "delay":
+            // Entry/exit passes and paid permits approved from an absence
+            // request do not reduce salary
+            if incidence.AbsenceRequestID != nil && incType.EffectType != "negative" {
+                break
+            }
             // Unpaid absences and delays reduce salary
             absenceDays += incidence.Quantity
             if incType.EffectType == "negative" {
@@ -766,6 +871,7 @@ func (s *PayrollService) CalculatePayrollDirect(
     }
 
     // Adjust salary for absence days
+    absenceDays += float64(sickLeave.SubsidizedDays)
     effectiveWorkingDays := float64(workingDays) - absenceDays
     if effectiveWorkingDays < 0 {
         effectiveWorkingDays = 0
@@ -795,6 +901,7 @@ func (s *PayrollService) CalculatePayrollDirect(
         payrollCalc.OtherDeductions
     payrollCalc.TotalNetPay = payrollCalc.TotalGrossIncome - payrollCalc.TotalStatutoryDeductions -
         payrollCalc.TotalOtherDeductions
+    s.ApplyMinimumWageProtection(payrollCalc, employee, effectiveWorkingDays)
 
     // Update status
     payrollCalc.CalculationStatus = "calculated"
@@ -983,7 +1090,11 @@ func (s *PayrollService) GeneratePayslip(
     case "pdf":
         return s.GeneratePDFPayslip(payroll)
     case "xml":
-        return s.cfdiService.GenerateCfdiXML(payroll)
+        incapacidades, err := s.sickLeave.PayrollIncapacidades(payroll.Employee, payroll.PayrollPeriod.StartDate, payroll.PayrollPeriod.EndDate)
+        if err != nil {
+            return nil, err
+        }
+        return s.cfdiService.GenerateCfdiXML(payroll, incapacidades)
     case "html":
         return s.GenerateHTMLPayslip(payroll)
     default:
@@ -27,6 +27,17 @@ SYNTAX EXPLANATION:
     - processIncidences converts HR incidences into payroll metrics
     - calculateDefaultMetrics sets base worked days and hours
     - WorkedDays = Period days - Absences - Sick days - Vacation days
+    - Sick days include the IMSS incapacidades (SickLeaveService); the
+      company pays their waiting days and ContributionDays excludes them
+    - Absences, delays, early exits, overtime and worked Sundays/rest days
+      also come from AttendanceEvaluationService (punches vs. shifts)
+    - RegularHours of hourly employees comes from their exported timesheets
+      when there are any (applyTimesheets), plus the hours of their approved
+      paid permits (PAID_LEAVE and TIME_FOR_TIME requests in hours), which
+      the timesheets do not show as worked
+    - Approved entry/exit passes (LATE_ENTRY, EARLY_EXIT) excuse the delay:
+      their incidences are not counted as delays and the attendance
+      evaluation measures punctuality against the permitted window
 
 ==============================================================================
 */
@@ -55,6 +66,8 @@ import (
         	    incidenceRepo  *repositories.IncidenceRepository
         	    config         *config_payroll.PayrollConfig
         		db             *gorm.DB
+        		attendance     *AttendanceEvaluationService
+        		sickLeave      *SickLeaveService
         	}
 // NewPrenominaService creates a new prenomina service
 func NewPrenominaService(
@@ -68,6 +81,8 @@ func NewPrenominaService(
         incidenceRepo: repositories.NewIncidenceRepository(db),
         config:        appConfig.PayrollConfig,
         db:            db,
+        attendance:    NewAttendanceEvaluationService(db, appConfig),
+        sickLeave:     NewSickLeaveService(db),
     }
 }
 
@@ -99,7 +114,11 @@ func (s *PrenominaService) CalculatePrenomina(
     if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
         return nil, fmt.Errorf("error fetching prenomina metrics: %w", err)
     }
-    
+    if errors.Is(err, gorm.ErrRecordNotFound) {
+        // The repository returns an empty struct, not nil, when nothing is found
+        metrics = nil
+    }
+
     var prenominaMetric *models.PrenominaMetric
     if metrics == nil {
         // Create new metrics
@@ -127,8 +146,30 @@ func (s *PrenominaService) CalculatePrenomina(
         return nil, fmt.Errorf("error fetching incidences: %w", err)
     }
     
+    // IMSS incapacidades replace the sick incidences of the requests they document
+    sickLeave, err := s.sickLeave.forPeriod(employeeID, period.StartDate, period.EndDate)
+    if err != nil {
+        return nil, err
+    }
+    
+    // Start from zero so a recalculation does not add incidences twice
+    s.resetDerivedMetrics(prenominaMetric)
+    
     // Process incidences to populate metrics
-    s.processIncidences(prenominaMetric, incidences, employee.DailySalary)
+    s.processIncidences(prenominaMetric, incidences, employee.DailySalary, sickLeave)
+    s.applySickLeave(prenominaMetric, sickLeave, period)
+    
+    // Evaluate punches against the scheduled shifts
+    attendance, err := s.attendance.EvaluatePeriod(employee, period)
+    if err != nil {
+        return nil, fmt.Errorf("error evaluating attendance: %w", err)
+    }
+    s.applyAttendance(prenominaMetric, attendance)
+    
+    // Hourly employees are paid the hours of their exported timesheets
+    if err := s.applyTimesheets(prenominaMetric, period, employee); err != nil {
+        return nil, fmt.Errorf("error applying timesheets: %w", err)
+    }
     
     // Calculate default metrics if not set by incidences
     s.calculateDefaultMetrics(prenominaMetric, period, employee)
@@ -154,16 +195,120 @@ func (s *PrenominaService) CalculatePrenomina(
     return s.convertToResponse(prenominaMetric, employee, period), nil
 }
 
+// resetDerivedMetrics clears every value CalculatePrenomina derives; loans,
+// advances and commissions are captured by hand and are kept
+func (s *PrenominaService) resetDerivedMetrics(metrics *models.PrenominaMetric) {
+    metrics.WorkedDays = 0
+    metrics.RegularHours = 0
+    metrics.OvertimeHours = 0
+    metrics.DoubleOvertimeHours = 0
+    metrics.TripleOvertimeHours = 0
+    metrics.UnauthorizedOvertimeHours = 0
+    metrics.PaidPermitHours = 0
+    metrics.AbsenceDays = 0
+    metrics.SickDays = 0
+    metrics.VacationDays = 0
+    metrics.UnpaidLeaveDays = 0
+    metrics.CompanyPaidSickDays = 0
+    metrics.SubsidizedSickDays = 0
+    metrics.ContributionDays = 0
+    metrics.DelaysCount = 0
+    metrics.DelayMinutes = 0
+    metrics.EarlyDeparturesCount = 0
+    metrics.SundaysWorked = 0
+    metrics.RestDaysWorked = 0
+    metrics.BonusAmount = 0
+    metrics.OtherDeduction = 0
+}
+
+// applyAttendance adds what the daily attendance evaluation found. Absences
+// already justified by an incidence, and delays/overtime already captured as
+// incidences, are excluded by the evaluator so nothing is counted twice.
+func (s *PrenominaService) applyAttendance(
+    metrics *models.PrenominaMetric,
+    attendance *dtos.AttendanceSummary,
+) {
+    if attendance == nil || !attendance.HasAttendanceData {
+        return
+    }
+    metrics.AbsenceDays += float64(attendance.AbsentDays)
+    metrics.DelaysCount += attendance.LateCount
+    metrics.DelayMinutes += attendance.LateMinutes
+    metrics.EarlyDeparturesCount += attendance.EarlyExitCount
+    // OvertimeHours is paid double and DoubleOvertimeHours triple (see calculateAmounts)
+    metrics.OvertimeHours += attendance.OvertimeDoubleHours
+    metrics.DoubleOvertimeHours += attendance.OvertimeTripleHours
+    // Overtime worked without an approved OvertimeRequest is flagged, not paid
+    metrics.UnauthorizedOvertimeHours = attendance.UnauthorizedOvertimeHours
+    metrics.SundaysWorked = attendance.SundaysWorked
+    metrics.RestDaysWorked = attendance.RestDaysWorked
+}
+
+// applyTimesheets sets RegularHours of hourly employees from the timesheets
+// exported for the period (TimesheetPeriodService.ExportToPrenomina).
+// Overtime keeps coming from the attendance evaluation so it is paid once.
+func (s *PrenominaService) applyTimesheets(
+    metrics *models.PrenominaMetric,
+    period *models.PayrollPeriod,
+    employee *models.Employee,
+) error {
+    if !isBlueOrGrayCollar(employee) {
+        return nil
+    }
+    var exported struct {
+        Count int64
+        Hours float64
+    }
+    err := s.db.Model(&models.Timesheet{}).
+        Select("COUNT(*) AS count, COALESCE(SUM(total_regular_hours), 0) AS hours").
+        Where("employee_id = ? AND payroll_period_id = ? AND status = ?",
+            employee.ID, period.ID, models.TimesheetStatusProcessed).
+        Scan(&exported).Error
+    if err != nil {
+        return err
+    }
+    if exported.Count > 0 {
+        permitHours, err := s.paidPermitHours(employee, period)
+        if err != nil {
+            return err
+        }
+        metrics.PaidPermitHours = permitHours
+        metrics.RegularHours = exported.Hours + permitHours
+    }
+    return nil
+}
+
+// paidPermitHours totals the approved paid permits in hours (partial-day
+// PAID_LEAVE and TIME_FOR_TIME requests) taken in the period. Absence
+// requests belong to the employee's user account.
+func (s *PrenominaService) paidPermitHours(employee *models.Employee, period *models.PayrollPeriod) (float64, error) {
+    var hours float64
+    err := s.db.Model(&models.AbsenceRequest{}).
+        Joins("JOIN users ON users.id = absence_requests.employee_id").
+        Where("users.employee_id = ? AND absence_requests.status = ?", employee.ID, models.RequestStatusApproved).
+        Where("absence_requests.request_type IN ?", []models.RequestType{models.RequestTypePaidLeave, models.RequestTypeTimeForTime}).
+        Where("absence_requests.start_time IS NOT NULL AND absence_requests.start_time <> ''").
+        Where("absence_requests.start_date BETWEEN ? AND ?", period.StartDate, period.EndDate).
+        Scopes(timeOffRequests).
+        Select("COALESCE(SUM(absence_requests.total_hours), 0)").
+        Scan(&hours).Error
+    return hours, err
+}
+
 // processIncidences processes incidences and populates metrics
 func (s *PrenominaService) processIncidences(
     metrics *models.PrenominaMetric,
     incidences []models.Incidence,
     dailySalary float64,
+    sickLeave *periodSickLeave,
 ) {
     for _, incidence := range incidences {
         if incidence.Status != "approved" && incidence.Status != "processed" {
             continue
         }
+        if sickLeave.Covers(&incidence) {
+            continue
+        }
         
         switch incidence.IncidenceType.Category {
         case "absence":
@@ -184,6 +329,10 @@ func (s *PrenominaService) processIncidences(
                 metrics.TripleOvertimeHours += incidence.Quantity
             }
         case "delay":
+            // An approved entry/exit pass excuses the delay
+            if incidence.AbsenceRequestID != nil {
+                continue
+            }
             metrics.DelaysCount++
             metrics.DelayMinutes += incidence.Quantity
         case "bonus":
@@ -194,6 +343,22 @@ func (s *PrenominaService) processIncidences(
     }
 }
 
+// applySickLeave adds the IMSS incapacidad days of the period and the days
+// that remain for the IMSS contributions
+func (s *PrenominaService) applySickLeave(
+    metrics *models.PrenominaMetric,
+    sickLeave *periodSickLeave,
+    period *models.PayrollPeriod,
+) {
+    metrics.SickDays += float64(sickLeave.Days())
+    metrics.CompanyPaidSickDays = float64(sickLeave.CompanyPaidDays)
+    metrics.SubsidizedSickDays = float64(sickLeave.SubsidizedDays)
+    metrics.ContributionDays = float64(period.CalculateDays() - sickLeave.Days())
+    if metrics.ContributionDays < 0 {
+        metrics.ContributionDays = 0
+    }
+}
+
 // calculateDefaultMetrics calculates default work metrics
 func (s *PrenominaService) calculateDefaultMetrics(
     metrics *models.PrenominaMetric,
@@ -217,9 +382,8 @@ func (s *PrenominaService) calculateDefaultMetrics(
         metrics.RegularHours = metrics.WorkedDays * 8
     }
     
-    // Calculate Sunday premium if applicable
-    // (In Mexico, working on Sunday typically adds 25% premium)
-    // This would require knowing which days were Sundays
+    // Sunday premium and worked rest days come from the attendance
+    // evaluation (SundaysWorked, RestDaysWorked) and are paid in calculateAmounts
 }
 
 // calculateAmounts calculates monetary amounts
@@ -230,8 +394,9 @@ func (s *PrenominaService) calculateAmounts(
     // Calculate regular salary
     hourlyRate := employee.DailySalary / 8
     
-    // Regular salary for worked hours
-    metrics.RegularSalary = metrics.RegularHours * hourlyRate
+    // Regular salary for worked hours plus the incapacidad waiting days the company pays
+    metrics.RegularSalary = metrics.RegularHours * hourlyRate +
+        metrics.CompanyPaidSickDays * employee.DailySalary
     
     // Overtime amounts
     metrics.OvertimeAmount = metrics.OvertimeHours * hourlyRate * 
@@ -242,6 +407,12 @@ func (s *PrenominaService) calculateAmounts(
     
     metrics.TripleOvertimeAmount = metrics.TripleOvertimeHours * hourlyRate * 3
     
+    // Prima dominical (LFT Art. 71) and worked rest days/holidays (Art. 73, 75)
+    metrics.SundayPremiumAmount = float64(metrics.SundaysWorked) * employee.DailySalary *
+        s.config.LaborConcepts.SundayPremium.Percentage
+    metrics.RestDayWorkedAmount = float64(metrics.RestDaysWorked) * employee.DailySalary *
+        s.attendance.Rules().RestDayPremiumFactor
+    
     // Delay deduction (proportional to minutes)
     if metrics.DelayMinutes > 0 {
         minuteRate := hourlyRate / 60
@@ -257,7 +428,9 @@ func (s *PrenominaService) calculateSummary(metrics *models.PrenominaMetric) {
         metrics.OtherExtraAmount +
         metrics.OvertimeAmount +
         metrics.DoubleOvertimeAmount +
-        metrics.TripleOvertimeAmount
+        metrics.TripleOvertimeAmount +
+        metrics.SundayPremiumAmount +
+        metrics.RestDayWorkedAmount
     
     // Total deductions
     metrics.TotalDeductions = metrics.LoanDeduction +
@@ -452,17 +625,26 @@ func (s *PrenominaService) convertToResponse(
         OvertimeHours:        metrics.OvertimeHours,
         DoubleOvertimeHours:  metrics.DoubleOvertimeHours,
         TripleOvertimeHours:  metrics.TripleOvertimeHours,
+        UnauthorizedOvertimeHours: metrics.UnauthorizedOvertimeHours,
+        PaidPermitHours:      metrics.PaidPermitHours,
         
         AbsenceDays:          metrics.AbsenceDays,
         SickDays:             metrics.SickDays,
         VacationDays:         metrics.VacationDays,
         UnpaidLeaveDays:      metrics.UnpaidLeaveDays,
+        CompanyPaidSickDays:  metrics.CompanyPaidSickDays,
+        SubsidizedSickDays:   metrics.SubsidizedSickDays,
+        ContributionDays:     metrics.ContributionDays,
         
         DelaysCount:          metrics.DelaysCount,
         DelayMinutes:         metrics.DelayMinutes,
         DelayDeduction:       metrics.DelayDeduction,
         EarlyDeparturesCount: metrics.EarlyDeparturesCount,
+        SundaysWorked:        metrics.SundaysWorked,
+        RestDaysWorked:       metrics.RestDaysWorked,
         
+        SundayPremiumAmount:  metrics.SundayPremiumAmount,
+        RestDayWorkedAmount:  metrics.RestDayWorkedAmount,
         BonusAmount:          metrics.BonusAmount,
         CommissionAmount:     metrics.CommissionAmount,
         OtherExtraAmount:     metrics.OtherExtraAmount,
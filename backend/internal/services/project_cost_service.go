@@ -0,0 +1,658 @@
+/*
+Package services - Project Cost Service
+
+==============================================================================
+FILE: internal/services/project_cost_service.go
+==============================================================================
+
+DESCRIPTION:
+    Computes what time-tracking projects cost. A project's burn is the cost
+    of its approved time entries, priced at the member's hourly rate or at
+    the employee's actual loaded payroll cost; it is compared with the
+    project and task budgets, and ProjectBudgetAlert records notify the
+    project manager at each threshold. Every month, each employee's payroll
+    cost is allocated across the projects and cost centers they logged time
+    to, and the allocation is exported as the accounting póliza.
+
+USER PERSPECTIVE:
+    - Project managers see budget vs. actual per task and are warned at 80%
+      and when the budget is exceeded
+    - Finance closes the month with the payroll cost spread by cost center
+      and project, ready to post
+
+DEVELOPER GUIDELINES:
+    ✅  OK to modify: Alert thresholds, póliza layout
+    ⚠️  CAUTION: Only entries of approved or exported timesheets count; a
+        draft timesheet does not burn budget yet
+    ⚠️  CAUTION: The allocation uses the payroll periods that END in the
+        month, so a biweekly period straddling two months is charged to the
+        month it closes in
+    📝  Loaded payroll cost = gross income + employer contributions
+        (EmployerContribution.TotalContributions, else the IMSS and
+        Infonavit employer amounts of the calculation)
+
+SYNTAX EXPLANATION:
+    - Basis "rate": hours × ProjectMember.HourlyRate (else the entry's own rate)
+    - Basis "payroll": hours × the employee's loaded cost per logged hour in
+      the month of the entry
+    - Cost center of an entry: its own CostCenterID, else the project's,
+      else the employee's
+    - Employees with payroll cost and no approved hours are charged whole to
+      their own cost center
+
+==============================================================================
+*/
+package services
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/xuri/excelize/v2"
+	"gorm.io/gorm"
+
+	"backend/internal/dtos"
+	"backend/internal/models"
+)
+
+// budgetAlertThresholds are the budget percentages that raise an alert
+var budgetAlertThresholds = []int{80, 100}
+
+// ProjectCostService tracks project budgets and allocates payroll cost
+type ProjectCostService struct {
+	db *gorm.DB
+}
+
+// NewProjectCostService creates a new project cost service
+func NewProjectCostService(db *gorm.DB) *ProjectCostService {
+	return &ProjectCostService{db: db}
+}
+
+// approvedTimesheetIDs selects the timesheets whose hours are final
+func approvedTimesheetIDs(db *gorm.DB) *gorm.DB {
+	return db.Model(&models.Timesheet{}).Select("id").
+		Where("status IN ?", []models.TimesheetStatus{models.TimesheetStatusApproved, models.TimesheetStatusProcessed})
+}
+
+// =========================================================================
+// Budgets
+// =========================================================================
+
+// SetTaskBudget sets the hours and amount budget of a project task
+func (s *ProjectCostService) SetTaskBudget(companyID, projectID, taskID uuid.UUID, req dtos.TaskBudgetRequest) (*models.ProjectTask, error) {
+	if _, err := s.companyProject(companyID, projectID); err != nil {
+		return nil, err
+	}
+	var task models.ProjectTask
+	if err := s.db.First(&task, "id = ? AND project_id = ?", taskID, projectID).Error; err != nil {
+		return nil, errors.New("project task not found")
+	}
+	if err := s.db.Model(&task).Updates(map[string]interface{}{
+		"budget_hours":  req.BudgetHours,
+		"budget_amount": req.BudgetAmount,
+	}).Error; err != nil {
+		return nil, fmt.Errorf("error updating task budget: %w", err)
+	}
+	return &task, nil
+}
+
+// companyProject loads a project of the company with its tasks
+func (s *ProjectCostService) companyProject(companyID, projectID uuid.UUID) (*models.Project, error) {
+	var project models.Project
+	if err := s.db.Preload("Tasks", func(db *gorm.DB) *gorm.DB { return db.Order("display_order, code") }).
+		First(&project, "id = ? AND company_id = ?", projectID, companyID).Error; err != nil {
+		return nil, errors.New("project not found")
+	}
+	return &project, nil
+}
+
+// ProjectBudget compares a project's and its tasks' budgets with the cost of approved hours
+func (s *ProjectCostService) ProjectBudget(companyID, projectID uuid.UUID, basis string) (*dtos.ProjectBudgetReport, error) {
+	if basis == "" {
+		basis = models.CostBasisRate
+	}
+	if basis != models.CostBasisRate && basis != models.CostBasisPayroll {
+		return nil, errors.New("basis must be rate or payroll")
+	}
+	project, err := s.companyProject(companyID, projectID)
+	if err != nil {
+		return nil, err
+	}
+	var entries []models.TimeEntry
+	if err := s.db.Where("project_id = ? AND timesheet_id IN (?)", project.ID, approvedTimesheetIDs(s.db)).
+		Find(&entries).Error; err != nil {
+		return nil, fmt.Errorf("error fetching time entries: %w", err)
+	}
+	costs, err := s.entryCosts(companyID, entries, basis)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &dtos.ProjectBudgetReport{
+		ProjectID: project.ID,
+		Basis:     basis,
+		Project: dtos.BudgetLine{
+			Code: project.Code, Name: project.Name,
+			BudgetHours: project.BudgetHours, BudgetAmount: project.BudgetAmount,
+		},
+		Tasks:      []dtos.BudgetLine{},
+		Unassigned: dtos.BudgetLine{Name: "Sin tarea"},
+	}
+	taskIndex := map[uuid.UUID]int{}
+	for _, task := range project.Tasks {
+		taskID := task.ID
+		taskIndex[task.ID] = len(report.Tasks)
+		report.Tasks = append(report.Tasks, dtos.BudgetLine{
+			TaskID: &taskID, Code: task.Code, Name: task.Name,
+			BudgetHours: task.BudgetHours, BudgetAmount: task.BudgetAmount,
+		})
+	}
+	for i, entry := range entries {
+		line := &report.Unassigned
+		if entry.TaskID != nil {
+			if idx, ok := taskIndex[*entry.TaskID]; ok {
+				line = &report.Tasks[idx]
+			}
+		}
+		line.ActualHours += entry.Hours
+		line.ActualCost += costs[i]
+		report.Project.ActualHours += entry.Hours
+		report.Project.ActualCost += costs[i]
+	}
+	finishBudgetLine(&report.Project)
+	finishBudgetLine(&report.Unassigned)
+	for i := range report.Tasks {
+		finishBudgetLine(&report.Tasks[i])
+	}
+	return report, nil
+}
+
+// finishBudgetLine rounds the actuals and sets the percentages and status
+func finishBudgetLine(line *dtos.BudgetLine) {
+	line.ActualHours = roundHours(line.ActualHours)
+	line.ActualCost = roundCurrency(line.ActualCost)
+	if line.BudgetHours > 0 {
+		line.HoursUsed = roundCurrency(line.ActualHours / line.BudgetHours * 100)
+	}
+	if line.BudgetAmount > 0 {
+		line.AmountUsed = roundCurrency(line.ActualCost / line.BudgetAmount * 100)
+	}
+	used := line.HoursUsed
+	if line.AmountUsed > used {
+		used = line.AmountUsed
+	}
+	switch {
+	case line.BudgetHours == 0 && line.BudgetAmount == 0:
+		line.Status = "no_budget"
+	case used > 100:
+		line.Status = "exceeded"
+	case used >= float64(budgetAlertThresholds[0]):
+		line.Status = "warning"
+	default:
+		line.Status = "on_track"
+	}
+}
+
+// entryCosts prices each entry on the given basis
+func (s *ProjectCostService) entryCosts(companyID uuid.UUID, entries []models.TimeEntry, basis string) ([]float64, error) {
+	costs := make([]float64, len(entries))
+	if basis == models.CostBasisPayroll {
+		rates := map[string]map[uuid.UUID]float64{}
+		for i, entry := range entries {
+			month := entry.EntryDate.Format("2006-01")
+			if rates[month] == nil {
+				monthRates, err := s.loadedHourlyCosts(companyID, entry.EntryDate.Year(), int(entry.EntryDate.Month()))
+				if err != nil {
+					return nil, err
+				}
+				rates[month] = monthRates
+			}
+			costs[i] = entry.Hours * rates[month][entry.EmployeeID]
+		}
+		return costs, nil
+	}
+
+	memberRates := map[[2]uuid.UUID]float64{}
+	projectIDs := map[uuid.UUID]bool{}
+	for _, entry := range entries {
+		if entry.ProjectID != nil {
+			projectIDs[*entry.ProjectID] = true
+		}
+	}
+	if len(projectIDs) > 0 {
+		ids := make([]uuid.UUID, 0, len(projectIDs))
+		for id := range projectIDs {
+			ids = append(ids, id)
+		}
+		var members []models.ProjectMember
+		if err := s.db.Where("project_id IN ?", ids).Find(&members).Error; err != nil {
+			return nil, fmt.Errorf("error fetching project members: %w", err)
+		}
+		for _, member := range members {
+			memberRates[[2]uuid.UUID{member.ProjectID, member.EmployeeID}] = member.HourlyRate
+		}
+	}
+	for i, entry := range entries {
+		rate := entry.HourlyRate
+		if entry.ProjectID != nil {
+			if memberRate := memberRates[[2]uuid.UUID{*entry.ProjectID, entry.EmployeeID}]; memberRate > 0 {
+				rate = memberRate
+			}
+		}
+		costs[i] = entry.Hours * rate
+	}
+	return costs, nil
+}
+
+// CheckBudgetAlerts raises the thresholds each active project and task has
+// crossed for the first time and notifies the project manager
+func (s *ProjectCostService) CheckBudgetAlerts(companyID, actorID uuid.UUID, basis string) (*dtos.BudgetAlertResult, error) {
+	var projects []models.Project
+	if err := s.db.Where("company_id = ? AND is_active = ?", companyID, true).Find(&projects).Error; err != nil {
+		return nil, fmt.Errorf("error fetching projects: %w", err)
+	}
+	result := &dtos.BudgetAlertResult{Projects: len(projects)}
+	for _, project := range projects {
+		report, err := s.ProjectBudget(companyID, project.ID, basis)
+		if err != nil {
+			return nil, err
+		}
+		managerUserID := s.projectManagerUser(&project)
+		lines := append([]dtos.BudgetLine{report.Project}, report.Tasks...)
+		for _, line := range lines {
+			for _, metric := range []string{models.BudgetMetricHours, models.BudgetMetricAmount} {
+				budget, actual, used := line.BudgetHours, line.ActualHours, line.HoursUsed
+				if metric == models.BudgetMetricAmount {
+					budget, actual, used = line.BudgetAmount, line.ActualCost, line.AmountUsed
+				}
+				if budget <= 0 {
+					continue
+				}
+				for _, threshold := range budgetAlertThresholds {
+					if used < float64(threshold) {
+						break
+					}
+					raised, err := s.raiseBudgetAlert(&project, line, metric, threshold, report.Basis, budget, actual, used, actorID, managerUserID)
+					if err != nil {
+						return nil, err
+					}
+					if raised {
+						result.Raised++
+					}
+				}
+			}
+		}
+	}
+	return result, nil
+}
+
+// raiseBudgetAlert records and notifies a threshold unless it was already raised
+func (s *ProjectCostService) raiseBudgetAlert(project *models.Project, line dtos.BudgetLine, metric string, threshold int,
+	basis string, budget, actual, used float64, actorID uuid.UUID, managerUserID *uuid.UUID) (bool, error) {
+	query := s.db.Model(&models.ProjectBudgetAlert{}).
+		Where("project_id = ? AND metric = ? AND threshold = ?", project.ID, metric, threshold)
+	if line.TaskID != nil {
+		query = query.Where("task_id = ?", *line.TaskID)
+	} else {
+		query = query.Where("task_id IS NULL")
+	}
+	var existing int64
+	if err := query.Count(&existing).Error; err != nil {
+		return false, err
+	}
+	if existing > 0 {
+		return false, nil
+	}
+
+	alert := &models.ProjectBudgetAlert{
+		CompanyID: project.CompanyID, ProjectID: project.ID, TaskID: line.TaskID,
+		Metric: metric, Threshold: threshold, Basis: basis,
+		Budget: budget, Actual: actual, PercentUsed: used, NotifiedUserID: managerUserID,
+	}
+	subject := fmt.Sprintf("el proyecto %s", project.Code)
+	if line.TaskID != nil {
+		subject = fmt.Sprintf("la tarea %s del proyecto %s", line.Name, project.Code)
+	}
+	measure := "horas"
+	if metric == models.BudgetMetricAmount {
+		measure = "monto"
+	}
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(alert).Error; err != nil {
+			return err
+		}
+		if managerUserID == nil {
+			return nil
+		}
+		return tx.Create(&models.Notification{
+			CompanyID:    project.CompanyID,
+			ActorUserID:  actorID,
+			TargetUserID: managerUserID,
+			Type:         models.NotificationProjectBudget,
+			Title:        "Presupuesto de proyecto",
+			Message: fmt.Sprintf("%s alcanzó el %.0f%% de su presupuesto de %s (%.2f de %.2f)",
+				strings.ToUpper(subject[:1])+subject[1:], used, measure, actual, budget),
+			ResourceType: "project",
+			ResourceID:   &project.ID,
+		}).Error
+	})
+	if err != nil {
+		return false, fmt.Errorf("error raising budget alert: %w", err)
+	}
+	return true, nil
+}
+
+// projectManagerUser is the portal user of the project manager, if any
+func (s *ProjectCostService) projectManagerUser(project *models.Project) *uuid.UUID {
+	if project.ProjectManagerID == nil {
+		return nil
+	}
+	var user models.User
+	if err := s.db.Where("(employee_id = ? OR id = ?) AND is_active = ?", *project.ProjectManagerID, *project.ProjectManagerID, true).
+		Limit(1).Find(&user).Error; err != nil || user.ID == uuid.Nil {
+		return nil
+	}
+	return &user.ID
+}
+
+// ListBudgetAlerts returns the alert history of a project
+func (s *ProjectCostService) ListBudgetAlerts(companyID, projectID uuid.UUID) ([]models.ProjectBudgetAlert, error) {
+	var alerts []models.ProjectBudgetAlert
+	if err := s.db.Preload("Task").Where("company_id = ? AND project_id = ?", companyID, projectID).
+		Order("created_at DESC").Find(&alerts).Error; err != nil {
+		return nil, fmt.Errorf("error fetching budget alerts: %w", err)
+	}
+	return alerts, nil
+}
+
+// =========================================================================
+// Payroll cost allocation
+// =========================================================================
+
+// monthRange is the first day of a month and of the next one
+func monthRange(year, month int) (time.Time, time.Time) {
+	start := time.Date(year, time.Month(month), 1, 0, 0, 0, 0, time.UTC)
+	return start, start.AddDate(0, 1, 0)
+}
+
+// monthlyPayrollCost is each employee's loaded payroll cost of the periods ending in the month
+func (s *ProjectCostService) monthlyPayrollCost(companyID uuid.UUID, year, month int) (map[uuid.UUID]float64, error) {
+	start, end := monthRange(year, month)
+	var rows []struct {
+		EmployeeID uuid.UUID
+		Cost       float64
+	}
+	err := s.db.Table("payroll_calculations").
+		Select("payroll_calculations.employee_id AS employee_id, "+
+			"SUM(payroll_calculations.total_gross_income + COALESCE(employer_contributions.total_contributions, "+
+			"payroll_calculations.imss_employer + payroll_calculations.infonavit_employer)) AS cost").
+		Joins("JOIN payroll_periods ON payroll_periods.id = payroll_calculations.payroll_period_id").
+		Joins("JOIN employees ON employees.id = payroll_calculations.employee_id").
+		Joins("LEFT JOIN employer_contributions ON employer_contributions.payroll_calculation_id = payroll_calculations.id AND employer_contributions.deleted_at IS NULL").
+		Where("employees.company_id = ? AND payroll_periods.end_date >= ? AND payroll_periods.end_date < ?", companyID, start, end).
+		Where("payroll_calculations.deleted_at IS NULL AND payroll_calculations.calculation_status <> ? AND payroll_calculations.payroll_status <> ?", "rejected", "cancelled").
+		Group("payroll_calculations.employee_id").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, fmt.Errorf("error fetching payroll cost: %w", err)
+	}
+	costs := make(map[uuid.UUID]float64, len(rows))
+	for _, row := range rows {
+		costs[row.EmployeeID] = row.Cost
+	}
+	return costs, nil
+}
+
+// approvedMonthEntries are the company's approved time entries of a month
+func (s *ProjectCostService) approvedMonthEntries(companyID uuid.UUID, year, month int) ([]models.TimeEntry, error) {
+	start, end := monthRange(year, month)
+	var entries []models.TimeEntry
+	if err := s.db.Where("company_id = ? AND entry_date >= ? AND entry_date < ? AND timesheet_id IN (?)",
+		companyID, start, end, approvedTimesheetIDs(s.db)).Find(&entries).Error; err != nil {
+		return nil, fmt.Errorf("error fetching time entries: %w", err)
+	}
+	return entries, nil
+}
+
+// loadedHourlyCosts is each employee's loaded payroll cost per approved hour logged in the month
+func (s *ProjectCostService) loadedHourlyCosts(companyID uuid.UUID, year, month int) (map[uuid.UUID]float64, error) {
+	costs, err := s.monthlyPayrollCost(companyID, year, month)
+	if err != nil {
+		return nil, err
+	}
+	entries, err := s.approvedMonthEntries(companyID, year, month)
+	if err != nil {
+		return nil, err
+	}
+	hours := map[uuid.UUID]float64{}
+	for _, entry := range entries {
+		hours[entry.EmployeeID] += entry.Hours
+	}
+	rates := make(map[uuid.UUID]float64, len(costs))
+	for employeeID, cost := range costs {
+		if hours[employeeID] > 0 {
+			rates[employeeID] = cost / hours[employeeID]
+		}
+	}
+	return rates, nil
+}
+
+// MonthlyAllocation spreads each employee's payroll cost of the month over
+// the projects and cost centers of their approved hours
+func (s *ProjectCostService) MonthlyAllocation(companyID uuid.UUID, year, month int) (*dtos.CostAllocationReport, error) {
+	if month < 1 || month > 12 {
+		return nil, errors.New("month must be between 1 and 12")
+	}
+	costs, err := s.monthlyPayrollCost(companyID, year, month)
+	if err != nil {
+		return nil, err
+	}
+	entries, err := s.approvedMonthEntries(companyID, year, month)
+	if err != nil {
+		return nil, err
+	}
+
+	employeeIDs := make([]uuid.UUID, 0, len(costs))
+	for id := range costs {
+		employeeIDs = append(employeeIDs, id)
+	}
+	var employees []models.Employee
+	if len(employeeIDs) > 0 {
+		if err := s.db.Where("id IN ?", employeeIDs).Find(&employees).Error; err != nil {
+			return nil, fmt.Errorf("error fetching employees: %w", err)
+		}
+	}
+	var projects []models.Project
+	if err := s.db.Where("company_id = ?", companyID).Find(&projects).Error; err != nil {
+		return nil, fmt.Errorf("error fetching projects: %w", err)
+	}
+	var costCenters []models.CostCenter
+	if err := s.db.Where("company_id = ?", companyID).Find(&costCenters).Error; err != nil {
+		return nil, fmt.Errorf("error fetching cost centers: %w", err)
+	}
+	projectByID := map[uuid.UUID]*models.Project{}
+	for i := range projects {
+		projectByID[projects[i].ID] = &projects[i]
+	}
+	centerNames := map[uuid.UUID]string{}
+	for _, center := range costCenters {
+		centerNames[center.ID] = center.Name
+	}
+
+	type bucket struct {
+		projectID    *uuid.UUID
+		costCenterID *uuid.UUID
+	}
+	bucketKey := func(b bucket) string {
+		key := ""
+		if b.projectID != nil {
+			key += b.projectID.String()
+		}
+		key += "|"
+		if b.costCenterID != nil {
+			key += b.costCenterID.String()
+		}
+		return key
+	}
+	byEmployee := map[uuid.UUID]map[string]*dtos.CostAllocationLine{}
+	employeeHours := map[uuid.UUID]float64{}
+	employeeByID := map[uuid.UUID]*models.Employee{}
+	for i := range employees {
+		employeeByID[employees[i].ID] = &employees[i]
+	}
+	for _, entry := range entries {
+		employee := employeeByID[entry.EmployeeID]
+		if employee == nil {
+			continue // no payroll cost this month
+		}
+		b := bucket{projectID: entry.ProjectID, costCenterID: entry.CostCenterID}
+		if b.costCenterID == nil && entry.ProjectID != nil && projectByID[*entry.ProjectID] != nil {
+			b.costCenterID = projectByID[*entry.ProjectID].CostCenterID
+		}
+		if b.costCenterID == nil {
+			b.costCenterID = employee.CostCenterID
+		}
+		if byEmployee[employee.ID] == nil {
+			byEmployee[employee.ID] = map[string]*dtos.CostAllocationLine{}
+		}
+		key := bucketKey(b)
+		line := byEmployee[employee.ID][key]
+		if line == nil {
+			line = &dtos.CostAllocationLine{ProjectID: b.projectID, CostCenterID: b.costCenterID}
+			byEmployee[employee.ID][key] = line
+		}
+		line.Hours += entry.Hours
+		employeeHours[employee.ID] += entry.Hours
+	}
+
+	report := &dtos.CostAllocationReport{
+		Year: year, Month: month,
+		Lines:        []dtos.CostAllocationLine{},
+		ByCostCenter: []dtos.CostCenterAllocation{},
+	}
+	for _, employee := range employees {
+		cost := roundCurrency(costs[employee.ID])
+		report.PayrollCost += cost
+		lines := make([]*dtos.CostAllocationLine, 0, len(byEmployee[employee.ID]))
+		for _, line := range byEmployee[employee.ID] {
+			lines = append(lines, line)
+		}
+		if employeeHours[employee.ID] == 0 {
+			lines = []*dtos.CostAllocationLine{{CostCenterID: employee.CostCenterID}}
+		}
+		sort.Slice(lines, func(i, j int) bool { return lines[i].Hours > lines[j].Hours })
+		remaining := cost
+		for i, line := range lines {
+			line.EmployeeID = employee.ID
+			line.EmployeeNumber = employee.EmployeeNumber
+			line.EmployeeName = strings.TrimSpace(employee.FirstName + " " + employee.LastName)
+			line.Share = 100
+			line.Amount = cost
+			if employeeHours[employee.ID] > 0 {
+				line.Share = roundCurrency(line.Hours / employeeHours[employee.ID] * 100)
+				line.Amount = roundCurrency(cost * line.Hours / employeeHours[employee.ID])
+			}
+			if i == len(lines)-1 {
+				line.Amount = roundCurrency(remaining) // rounding remainder goes to the last line
+			}
+			remaining -= line.Amount
+			line.Hours = roundHours(line.Hours)
+			if line.ProjectID != nil && projectByID[*line.ProjectID] != nil {
+				line.ProjectCode = projectByID[*line.ProjectID].Code
+			}
+			if line.CostCenterID != nil {
+				line.CostCenterName = centerNames[*line.CostCenterID]
+			}
+			report.Allocated += line.Amount
+			report.Lines = append(report.Lines, *line)
+		}
+	}
+	sort.SliceStable(report.Lines, func(i, j int) bool {
+		if report.Lines[i].EmployeeNumber != report.Lines[j].EmployeeNumber {
+			return report.Lines[i].EmployeeNumber < report.Lines[j].EmployeeNumber
+		}
+		return report.Lines[i].ProjectCode < report.Lines[j].ProjectCode
+	})
+
+	totals := map[string]*dtos.CostCenterAllocation{}
+	order := []string{}
+	for _, line := range report.Lines {
+		key := bucketKey(bucket{projectID: line.ProjectID, costCenterID: line.CostCenterID})
+		total := totals[key]
+		if total == nil {
+			total = &dtos.CostCenterAllocation{
+				CostCenterID: line.CostCenterID, CostCenterName: line.CostCenterName,
+				ProjectID: line.ProjectID, ProjectCode: line.ProjectCode,
+			}
+			if total.CostCenterName == "" {
+				total.CostCenterName = "Sin centro de costos"
+			}
+			totals[key] = total
+			order = append(order, key)
+		}
+		total.Hours = roundHours(total.Hours + line.Hours)
+		total.Amount = roundCurrency(total.Amount + line.Amount)
+	}
+	for _, key := range order {
+		report.ByCostCenter = append(report.ByCostCenter, *totals[key])
+	}
+	sort.SliceStable(report.ByCostCenter, func(i, j int) bool {
+		if report.ByCostCenter[i].CostCenterName != report.ByCostCenter[j].CostCenterName {
+			return report.ByCostCenter[i].CostCenterName < report.ByCostCenter[j].CostCenterName
+		}
+		return report.ByCostCenter[i].ProjectCode < report.ByCostCenter[j].ProjectCode
+	})
+	report.PayrollCost = roundCurrency(report.PayrollCost)
+	report.Allocated = roundCurrency(report.Allocated)
+	return report, nil
+}
+
+// ExportPoliza writes the month's allocation as an accounting journal entry:
+// one debit per cost center and project, one credit for the payroll payable
+func (s *ProjectCostService) ExportPoliza(companyID uuid.UUID, year, month int) ([]byte, error) {
+	report, err := s.MonthlyAllocation(companyID, year, month)
+	if err != nil {
+		return nil, err
+	}
+	_, end := monthRange(year, month)
+	period := fmt.Sprintf("%02d/%d", month, year)
+
+	f := excelize.NewFile()
+	defer f.Close()
+	sheet := "Póliza"
+	f.SetSheetName("Sheet1", sheet)
+	f.SetSheetRow(sheet, "A1", &[]interface{}{"Póliza de diario", "Costo de nómina " + period, "Fecha", end.AddDate(0, 0, -1).Format("2006-01-02")})
+	f.SetSheetRow(sheet, "A3", &[]interface{}{"Centro de costos", "Proyecto", "Concepto", "Cargo", "Abono"})
+	row := 4
+	for _, total := range report.ByCostCenter {
+		cell, _ := excelize.CoordinatesToCellName(1, row)
+		f.SetSheetRow(sheet, cell, &[]interface{}{
+			total.CostCenterName, total.ProjectCode, "Costo de nómina " + period, total.Amount, 0,
+		})
+		row++
+	}
+	cell, _ := excelize.CoordinatesToCellName(1, row)
+	f.SetSheetRow(sheet, cell, &[]interface{}{"", "", "Nómina y cuotas por pagar " + period, 0, report.Allocated})
+	cell, _ = excelize.CoordinatesToCellName(1, row+1)
+	f.SetSheetRow(sheet, cell, &[]interface{}{"", "", "Sumas iguales", report.Allocated, report.Allocated})
+
+	detail := "Detalle"
+	f.NewSheet(detail)
+	f.SetSheetRow(detail, "A1", &[]interface{}{"Empleado", "Nombre", "Proyecto", "Centro de costos", "Horas", "% horas", "Importe"})
+	for i, line := range report.Lines {
+		cell, _ := excelize.CoordinatesToCellName(1, i+2)
+		f.SetSheetRow(detail, cell, &[]interface{}{
+			line.EmployeeNumber, line.EmployeeName, line.ProjectCode, line.CostCenterName, line.Hours, line.Share, line.Amount,
+		})
+	}
+
+	buffer, err := f.WriteToBuffer()
+	if err != nil {
+		return nil, fmt.Errorf("failed to write Excel to buffer: %w", err)
+	}
+	return buffer.Bytes(), nil
+}
@@ -0,0 +1,136 @@
+package services
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/xuri/excelize/v2"
+
+	"backend/internal/dtos"
+	"backend/internal/models"
+)
+
+func TestProjectCost_BudgetAlertsAndAllocation(t *testing.T) {
+	db := setupPayrollTestDB(t)
+	require.NoError(t, db.AutoMigrate(
+		&models.User{}, &models.Notification{}, &models.CostCenter{},
+		&models.Project{}, &models.ProjectTask{}, &models.ProjectMember{}, &models.Timesheet{}, &models.TimeEntry{},
+		&models.ProjectBudgetAlert{},
+	))
+	company := createPayrollTestCompany(t, db)
+	service := NewProjectCostService(db)
+
+	engineering := &models.CostCenter{Name: "Ingeniería", CompanyID: company.ID}
+	operations := &models.CostCenter{Name: "Operaciones", CompanyID: company.ID}
+	engineering.ID, operations.ID = uuid.New(), uuid.New() // CostCenter's BeforeCreate does not assign one
+	require.NoError(t, db.Create(engineering).Error)
+	require.NoError(t, db.Create(operations).Error)
+
+	developer := createFixtureEmployee(t, db, company.ID, 1, "general", 400)
+	require.NoError(t, db.Model(developer).Update("cost_center_id", operations.ID).Error)
+	idle := createFixtureEmployee(t, db, company.ID, 2, "general", 400)
+	require.NoError(t, db.Model(idle).Update("cost_center_id", engineering.ID).Error)
+	managerEmployee := createFixtureEmployee(t, db, company.ID, 3, "general", 900)
+	manager := createFixtureUser(t, db, managerEmployee, "manager")
+	finance := createFixtureUser(t, db, createFixtureEmployee(t, db, company.ID, 4, "general", 900), "accountant")
+
+	project := &models.Project{CompanyID: company.ID, Name: "Portal clientes", Code: "PC-01", IsActive: true, IsBillable: true,
+		CostCenterID: &engineering.ID, ProjectManagerID: &managerEmployee.ID, BudgetHours: 20, BudgetAmount: 2000}
+	require.NoError(t, db.Create(project).Error)
+	design := &models.ProjectTask{ProjectID: project.ID, Name: "Diseño", Code: "DIS", IsActive: true, DisplayOrder: 1}
+	build := &models.ProjectTask{ProjectID: project.ID, Name: "Construcción", Code: "CON", IsActive: true, DisplayOrder: 2}
+	require.NoError(t, db.Create(design).Error)
+	require.NoError(t, db.Create(build).Error)
+	require.NoError(t, db.Create(&models.ProjectMember{ProjectID: project.ID, EmployeeID: developer.ID, HourlyRate: 100, IsActive: true}).Error)
+
+	_, err := service.SetTaskBudget(company.ID, project.ID, design.ID, dtos.TaskBudgetRequest{BudgetHours: 10, BudgetAmount: 1000})
+	require.NoError(t, err)
+	_, err = service.SetTaskBudget(uuid.New(), project.ID, design.ID, dtos.TaskBudgetRequest{BudgetHours: 1})
+	assert.ErrorContains(t, err, "not found")
+
+	day := func(d int) time.Time { return time.Date(2026, 10, d, 0, 0, 0, 0, time.UTC) }
+	timesheet := func(status models.TimesheetStatus) *models.Timesheet {
+		ts := &models.Timesheet{CompanyID: company.ID, EmployeeID: developer.ID, PeriodStart: day(1), PeriodEnd: day(15), Status: status}
+		require.NoError(t, db.Create(ts).Error)
+		return ts
+	}
+	approved, draft := timesheet(models.TimesheetStatusApproved), timesheet(models.TimesheetStatusDraft)
+	entry := func(ts *models.Timesheet, d int, hours float64, projectID, taskID *uuid.UUID) {
+		require.NoError(t, db.Create(&models.TimeEntry{CompanyID: company.ID, EmployeeID: developer.ID, TimesheetID: &ts.ID,
+			EntryDate: day(d), Hours: hours, ProjectID: projectID, TaskID: taskID, EntryType: models.TimeEntryTypeRegular}).Error)
+	}
+	entry(approved, 5, 9, &project.ID, &design.ID)
+	entry(approved, 6, 3, &project.ID, &build.ID)
+	entry(approved, 7, 4, nil, nil)
+	entry(draft, 8, 10, &project.ID, &design.ID) // not approved: no burn
+
+	// Rate basis: member rate × approved hours
+	report, err := service.ProjectBudget(company.ID, project.ID, models.CostBasisRate)
+	require.NoError(t, err)
+	assert.Equal(t, 12.0, report.Project.ActualHours)
+	assert.Equal(t, 1200.0, report.Project.ActualCost)
+	assert.Equal(t, 60.0, report.Project.HoursUsed)
+	assert.Equal(t, "on_track", report.Project.Status)
+	require.Len(t, report.Tasks, 2)
+	assert.Equal(t, "DIS", report.Tasks[0].Code)
+	assert.Equal(t, 90.0, report.Tasks[0].HoursUsed)
+	assert.Equal(t, 90.0, report.Tasks[0].AmountUsed)
+	assert.Equal(t, "warning", report.Tasks[0].Status)
+	assert.Equal(t, "no_budget", report.Tasks[1].Status)
+
+	// Alerts: once per threshold, to the project manager
+	alerts, err := service.CheckBudgetAlerts(company.ID, finance.ID, models.CostBasisRate)
+	require.NoError(t, err)
+	assert.Equal(t, 2, alerts.Raised) // design task: hours and amount at 80%
+	alerts, err = service.CheckBudgetAlerts(company.ID, finance.ID, models.CostBasisRate)
+	require.NoError(t, err)
+	assert.Equal(t, 0, alerts.Raised)
+	var notified int64
+	db.Model(&models.Notification{}).Where("type = ? AND target_user_id = ?", models.NotificationProjectBudget, manager.ID).Count(&notified)
+	assert.Equal(t, int64(2), notified)
+
+	// Payroll: developer 3,200 gross + 800 employer; idle 1,000 gross + 100 IMSS employer
+	period := &models.PayrollPeriod{PeriodCode: "2026-BW19", PeriodType: "biweekly", Frequency: "biweekly", Year: 2026, PeriodNumber: 19,
+		StartDate: day(1), EndDate: day(15), PaymentDate: day(15), Status: "closed"}
+	require.NoError(t, db.Create(period).Error)
+	developerCalc := &models.PayrollCalculation{EmployeeID: developer.ID, PayrollPeriodID: period.ID, TotalGrossIncome: 3200}
+	require.NoError(t, db.Create(developerCalc).Error)
+	require.NoError(t, db.Create(&models.EmployerContribution{PayrollCalculationID: developerCalc.ID, EmployeeID: developer.ID,
+		PayrollPeriodID: period.ID, TotalContributions: 800}).Error)
+	require.NoError(t, db.Create(&models.PayrollCalculation{EmployeeID: idle.ID, PayrollPeriodID: period.ID,
+		TotalGrossIncome: 1000, IMSSEmployer: 100}).Error)
+
+	// Payroll basis: 4,000 over 16 approved hours = 250 per hour
+	report, err = service.ProjectBudget(company.ID, project.ID, models.CostBasisPayroll)
+	require.NoError(t, err)
+	assert.Equal(t, 3000.0, report.Project.ActualCost)
+	assert.Equal(t, "exceeded", report.Project.Status)
+
+	allocation, err := service.MonthlyAllocation(company.ID, 2026, 10)
+	require.NoError(t, err)
+	assert.Equal(t, 5100.0, allocation.PayrollCost)
+	assert.Equal(t, 5100.0, allocation.Allocated)
+	require.Len(t, allocation.Lines, 3)
+	assert.Equal(t, "PC-01", allocation.Lines[1].ProjectCode)
+	assert.Equal(t, 75.0, allocation.Lines[1].Share)
+	assert.Equal(t, 3000.0, allocation.Lines[1].Amount)
+	assert.Equal(t, "Operaciones", allocation.Lines[0].CostCenterName)
+	assert.Equal(t, 1000.0, allocation.Lines[0].Amount)
+	require.Len(t, allocation.ByCostCenter, 3)
+	assert.Equal(t, dtos.CostCenterAllocation{CostCenterID: &engineering.ID, CostCenterName: "Ingeniería", Amount: 1100},
+		allocation.ByCostCenter[0])
+	assert.Equal(t, 3000.0, allocation.ByCostCenter[1].Amount)
+
+	file, err := service.ExportPoliza(company.ID, 2026, 10)
+	require.NoError(t, err)
+	f, err := excelize.OpenReader(bytes.NewReader(file))
+	require.NoError(t, err)
+	rows, err := f.GetRows("Póliza")
+	require.NoError(t, err)
+	last := rows[len(rows)-1]
+	assert.Equal(t, []string{"", "", "Sumas iguales", "5100", "5100"}, last)
+}
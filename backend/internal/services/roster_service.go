@@ -0,0 +1,1635 @@
+/*
+Package services - Roster Service
+
+==============================================================================
+FILE: internal/services/roster_service.go
+==============================================================================
+
+DESCRIPTION:
+    Rotating shift schedules and roster planning. Maintains rotation
+    patterns (N-day cycles anchored on a date) and their assignment to teams
+    or employees, generates the day-by-day roster of any date range with the
+    same schedule resolution the attendance evaluation uses, checks it
+    against the minimum-staffing rules of each production area, handles
+    shift swaps and exports the roster to Excel. Also keeps the leave
+    blackouts and checks absence requests against blackouts and staffing
+    rules (minimum headcount or maximum percentage absent) before they are
+    filed and approved.
+
+USER PERSPECTIVE:
+    - HR sets up "4x3" (four mornings, three rest days) and puts team A on
+      it, and team B on the same pattern four days later
+    - Planners see who works each day, who is on leave and where a
+      production area is below its minimum
+    - An operator trades Saturday with a colleague; once approved, both
+      rosters and the attendance evaluation follow the trade
+    - An operator offers a shift on the marketplace; a colleague of the same
+      area whose collar type the shift allows takes it, and the supervisor
+      approves the trade
+    - A vacation that would leave line 3 below four operators warns (or is
+      refused, for a blocking rule); nobody files vacation in inventory week
+
+DEVELOPER GUIDELINES:
+    ✅  OK to modify: Excel layout, coverage rule dimensions
+    ⚠️  CAUTION: The schedule of a day comes from
+        AttendanceEvaluationService.loadSchedulesWith so the roster and the
+        prenómina never disagree
+    ⚠️  CAUTION: Approved swaps are written as ShiftExceptions, which win
+        over every rotation
+    📝  Coverage counts every active employee of the company, whatever the
+        roster filter, and leaves out employees on approved leave
+    📝  Percentage-only rules (MinEmployees 0) apply to absence requests
+        but never show as roster gaps
+
+SYNTAX EXPLANATION:
+    - Cycle day = (calendar days since AnchorDate + OffsetDays) mod cycle
+      length; negative values wrap around, so dates before the anchor work
+    - An employee's own assignment wins over their team's; among several
+      assignments in effect the latest EffectiveFrom wins
+    - A rest day in a swap needs a shift flagged IsRestDay in the catalog
+    - Accepting and approving a swap check both employees keep 12 hours of
+      rest around the new shift and stay within StandardWeeklyHours of their
+      TimePolicy for the Monday-Sunday week
+    - An absence request breaks a staffing rule on a day the employee works
+      when colleagues still working < MinEmployees, or when
+      (absent colleagues + 1) / scheduled * 100 > MaxAbsentPercent; only
+      the first maxRosterDays days of a request are checked
+
+==============================================================================
+*/
+package services
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"github.com/xuri/excelize/v2"
+	"gorm.io/gorm"
+
+	"backend/internal/dtos"
+	"backend/internal/models"
+	"backend/internal/models/enums"
+)
+
+// ErrRosterNotAllowed is returned when the user may not request a swap for the employee
+var ErrRosterNotAllowed = errors.New("not allowed to change this employee's roster")
+
+// maxRosterDays limits the date range of a generated roster
+const maxRosterDays = 92
+
+// minRestBetweenShifts is the rest a swap must leave between two shifts
+const minRestBetweenShifts = 12 * time.Hour
+
+// activeSwapStatuses block a second swap of the same employee and day
+var activeSwapStatuses = []string{models.RosterSwapOpen, models.RosterSwapAccepted, models.RosterSwapPending}
+
+// rosterPlannerRoles may request swaps for anyone
+var rosterPlannerRoles = []enums.UserRole{
+	enums.RoleAdmin, enums.RoleHR, enums.RoleHRAndPR, enums.RoleHRBlueGray, enums.RoleHRWhite,
+	enums.RoleSupervisor, enums.RoleManager, enums.RoleSupAndGM,
+}
+
+// rosterWeekdays are the Spanish weekday abbreviations used in the export (0=Sunday)
+var rosterWeekdays = []string{"Dom", "Lun", "Mar", "Mié", "Jue", "Vie", "Sáb"}
+
+// RosterService handles rotations, roster generation and swaps
+type RosterService struct {
+	db           *gorm.DB
+	attendance   *AttendanceEvaluationService
+	policies     *TimePolicyService
+	orgStructure *OrgStructureService
+}
+
+// NewRosterService creates a new RosterService
+func NewRosterService(db *gorm.DB) *RosterService {
+	return &RosterService{
+		db:           db,
+		attendance:   NewAttendanceEvaluationService(db, nil),
+		policies:     NewTimePolicyService(db),
+		orgStructure: NewOrgStructureService(db),
+	}
+}
+
+// =========================================================================
+// Rotation resolution
+// =========================================================================
+
+// rotationResolver answers which rotation shift an employee has on a date
+type rotationResolver struct {
+	patterns   map[uuid.UUID]*models.RotationPattern
+	dayShifts  map[uuid.UUID]map[int]*models.Shift
+	byEmployee map[uuid.UUID][]models.RotationAssignment
+	byTeam     map[string][]models.RotationAssignment
+}
+
+// loadRotations loads the active patterns and the assignments of a company
+func loadRotations(db *gorm.DB, companyID uuid.UUID) (*rotationResolver, error) {
+	var patterns []models.RotationPattern
+	if err := db.Preload("Days.Shift").Where("company_id = ? AND is_active = ?", companyID, true).
+		Find(&patterns).Error; err != nil {
+		return nil, err
+	}
+	var assignments []models.RotationAssignment
+	if err := db.Where("company_id = ?", companyID).Find(&assignments).Error; err != nil {
+		return nil, err
+	}
+
+	r := &rotationResolver{
+		patterns:   make(map[uuid.UUID]*models.RotationPattern, len(patterns)),
+		dayShifts:  make(map[uuid.UUID]map[int]*models.Shift, len(patterns)),
+		byEmployee: make(map[uuid.UUID][]models.RotationAssignment),
+		byTeam:     make(map[string][]models.RotationAssignment),
+	}
+	for i := range patterns {
+		p := &patterns[i]
+		r.patterns[p.ID] = p
+		r.dayShifts[p.ID] = make(map[int]*models.Shift, len(p.Days))
+		for _, day := range p.Days {
+			r.dayShifts[p.ID][day.DayIndex] = day.Shift
+		}
+	}
+	for _, a := range assignments {
+		if a.EmployeeID != nil {
+			r.byEmployee[*a.EmployeeID] = append(r.byEmployee[*a.EmployeeID], a)
+		} else if key := rosterKey(a.TeamName); key != "" {
+			r.byTeam[key] = append(r.byTeam[key], a)
+		}
+	}
+	return r, nil
+}
+
+// shiftFor returns the rotation shift of the employee on date (nil on a
+// rest day); ok is false when no rotation applies
+func (r *rotationResolver) shiftFor(employee *models.Employee, date time.Time) (*models.Shift, bool) {
+	if r == nil {
+		return nil, false
+	}
+	assignment := activeAssignment(r.byEmployee[employee.ID], date)
+	if assignment == nil {
+		assignment = activeAssignment(r.byTeam[rosterKey(employee.TeamName)], date)
+	}
+	if assignment == nil {
+		return nil, false
+	}
+	pattern := r.patterns[assignment.PatternID]
+	if pattern == nil || pattern.CycleLengthDays <= 0 {
+		return nil, false
+	}
+	index := cycleDay(pattern, assignment.OffsetDays, date)
+	shift := r.dayShifts[pattern.ID][index]
+	if shift == nil || !shift.IsActive || shift.IsRestDay {
+		return nil, true
+	}
+	return shift, true
+}
+
+// activeAssignment picks the assignment in effect on date, latest start first
+func activeAssignment(assignments []models.RotationAssignment, date time.Time) *models.RotationAssignment {
+	day := civilDay(date)
+	var found *models.RotationAssignment
+	for i := range assignments {
+		a := &assignments[i]
+		if civilDay(a.EffectiveFrom) > day || (a.EffectiveTo != nil && civilDay(*a.EffectiveTo) < day) {
+			continue
+		}
+		if found == nil || a.EffectiveFrom.After(found.EffectiveFrom) {
+			found = a
+		}
+	}
+	return found
+}
+
+// cycleDay returns the index of date in the pattern's cycle
+func cycleDay(pattern *models.RotationPattern, offset int, date time.Time) int {
+	n := pattern.CycleLengthDays
+	days := civilDay(date) - civilDay(pattern.AnchorDate) + offset
+	return ((days % n) + n) % n
+}
+
+// civilDay numbers a calendar date, ignoring its time and location
+func civilDay(t time.Time) int {
+	return int(time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC).Unix() / 86400)
+}
+
+// rosterKey normalizes team and production area names for matching
+func rosterKey(name string) string {
+	return strings.ToLower(strings.TrimSpace(name))
+}
+
+// =========================================================================
+// Rotation patterns
+// =========================================================================
+
+// ListPatterns returns the company's rotation patterns with their cycle
+func (s *RosterService) ListPatterns(companyID uuid.UUID) ([]models.RotationPattern, error) {
+	var patterns []models.RotationPattern
+	if err := s.db.Preload("Days", func(db *gorm.DB) *gorm.DB {
+		return db.Order("day_index")
+	}).Preload("Days.Shift").Where("company_id = ?", companyID).Order("name").Find(&patterns).Error; err != nil {
+		return nil, fmt.Errorf("error fetching rotation patterns: %w", err)
+	}
+	return patterns, nil
+}
+
+// CreatePattern creates a rotation pattern
+func (s *RosterService) CreatePattern(companyID uuid.UUID, req dtos.RotationPatternRequest, createdBy uuid.UUID) (*models.RotationPattern, error) {
+	code := strings.ToUpper(strings.TrimSpace(req.Code))
+	var count int64
+	if err := s.db.Model(&models.RotationPattern{}).Where("company_id = ? AND code = ?", companyID, code).Count(&count).Error; err != nil {
+		return nil, err
+	}
+	if count > 0 {
+		return nil, errors.New("rotation pattern code already exists")
+	}
+	if err := s.checkShifts(companyID, req.Days); err != nil {
+		return nil, err
+	}
+
+	pattern := &models.RotationPattern{
+		CompanyID:       companyID,
+		Name:            strings.TrimSpace(req.Name),
+		Code:            code,
+		Description:     req.Description,
+		CycleLengthDays: len(req.Days),
+		AnchorDate:      truncateToDate(req.AnchorDate.Time),
+		IsActive:        req.IsActive == nil || *req.IsActive,
+		CreatedBy:       &createdBy,
+		Days:            patternDays(req.Days),
+	}
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(pattern).Error; err != nil {
+			return err
+		}
+		// is_active has a column default; an inactive pattern must be saved explicitly
+		if !pattern.IsActive {
+			return tx.Model(pattern).Update("is_active", false).Error
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error creating rotation pattern: %w", err)
+	}
+	return pattern, nil
+}
+
+// UpdatePattern replaces a rotation pattern and its cycle
+func (s *RosterService) UpdatePattern(id, companyID uuid.UUID, req dtos.RotationPatternRequest) (*models.RotationPattern, error) {
+	var pattern models.RotationPattern
+	if err := s.db.First(&pattern, "id = ? AND company_id = ?", id, companyID).Error; err != nil {
+		return nil, errors.New("rotation pattern not found")
+	}
+	code := strings.ToUpper(strings.TrimSpace(req.Code))
+	var count int64
+	if err := s.db.Model(&models.RotationPattern{}).Where("company_id = ? AND code = ? AND id <> ?", companyID, code, id).Count(&count).Error; err != nil {
+		return nil, err
+	}
+	if count > 0 {
+		return nil, errors.New("rotation pattern code already exists")
+	}
+	if err := s.checkShifts(companyID, req.Days); err != nil {
+		return nil, err
+	}
+
+	pattern.Name = strings.TrimSpace(req.Name)
+	pattern.Code = code
+	pattern.Description = req.Description
+	pattern.CycleLengthDays = len(req.Days)
+	pattern.AnchorDate = truncateToDate(req.AnchorDate.Time)
+	if req.IsActive != nil {
+		pattern.IsActive = *req.IsActive
+	}
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Save(&pattern).Error; err != nil {
+			return err
+		}
+		if err := tx.Unscoped().Where("pattern_id = ?", pattern.ID).Delete(&models.RotationPatternDay{}).Error; err != nil {
+			return err
+		}
+		pattern.Days = patternDays(req.Days)
+		for i := range pattern.Days {
+			pattern.Days[i].PatternID = pattern.ID
+		}
+		return tx.Create(&pattern.Days).Error
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error updating rotation pattern: %w", err)
+	}
+	return &pattern, nil
+}
+
+// DeletePattern deletes a rotation pattern no longer assigned
+func (s *RosterService) DeletePattern(id, companyID uuid.UUID) error {
+	var pattern models.RotationPattern
+	if err := s.db.First(&pattern, "id = ? AND company_id = ?", id, companyID).Error; err != nil {
+		return errors.New("rotation pattern not found")
+	}
+	var count int64
+	if err := s.db.Model(&models.RotationAssignment{}).
+		Where("pattern_id = ? AND (effective_to IS NULL OR effective_to >= ?)", id, truncateToDate(time.Now())).
+		Count(&count).Error; err != nil {
+		return err
+	}
+	if count > 0 {
+		return errors.New("rotation pattern cannot be deleted while it is assigned")
+	}
+	return s.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Unscoped().Where("pattern_id = ?", id).Delete(&models.RotationPatternDay{}).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&pattern).Error
+	})
+}
+
+// checkShifts verifies that the shifts of a cycle belong to the company
+func (s *RosterService) checkShifts(companyID uuid.UUID, days []dtos.RotationDayInput) error {
+	ids := make(map[uuid.UUID]bool)
+	for _, day := range days {
+		if day.ShiftID != nil {
+			ids[*day.ShiftID] = true
+		}
+	}
+	if len(ids) == 0 {
+		return errors.New("rotation pattern must have at least one working day")
+	}
+	list := make([]uuid.UUID, 0, len(ids))
+	for id := range ids {
+		list = append(list, id)
+	}
+	var count int64
+	if err := s.db.Model(&models.Shift{}).Where("company_id = ? AND id IN ?", companyID, list).Count(&count).Error; err != nil {
+		return err
+	}
+	if int(count) != len(list) {
+		return errors.New("shift not found")
+	}
+	return nil
+}
+
+// patternDays turns the request's cycle into pattern day rows
+func patternDays(days []dtos.RotationDayInput) []models.RotationPatternDay {
+	rows := make([]models.RotationPatternDay, len(days))
+	for i, day := range days {
+		rows[i] = models.RotationPatternDay{DayIndex: i, ShiftID: day.ShiftID}
+	}
+	return rows
+}
+
+// =========================================================================
+// Assignments
+// =========================================================================
+
+// ListAssignments returns the company's rotation assignments, newest first
+func (s *RosterService) ListAssignments(companyID uuid.UUID) ([]models.RotationAssignment, error) {
+	var assignments []models.RotationAssignment
+	if err := s.db.Preload("Pattern").Preload("Employee").Where("company_id = ?", companyID).
+		Order("effective_from DESC").Find(&assignments).Error; err != nil {
+		return nil, fmt.Errorf("error fetching rotation assignments: %w", err)
+	}
+	return assignments, nil
+}
+
+// CreateAssignment puts a team or an employee on a rotation; the open
+// assignment of the same team or employee ends the day before
+func (s *RosterService) CreateAssignment(companyID uuid.UUID, req dtos.RotationAssignmentRequest, createdBy uuid.UUID) (*models.RotationAssignment, error) {
+	team := strings.TrimSpace(req.TeamName)
+	if (team == "") == (req.EmployeeID == nil) {
+		return nil, errors.New("either team_name or employee_id is required")
+	}
+	var pattern models.RotationPattern
+	if err := s.db.First(&pattern, "id = ? AND company_id = ?", req.PatternID, companyID).Error; err != nil {
+		return nil, errors.New("rotation pattern not found")
+	}
+	if req.EmployeeID != nil {
+		var count int64
+		if err := s.db.Model(&models.Employee{}).Where("id = ? AND company_id = ?", *req.EmployeeID, companyID).Count(&count).Error; err != nil {
+			return nil, err
+		}
+		if count == 0 {
+			return nil, errors.New("employee not found")
+		}
+	}
+	from := truncateToDate(req.EffectiveFrom.Time)
+	var to *time.Time
+	if req.EffectiveTo != nil {
+		end := truncateToDate(req.EffectiveTo.Time)
+		if end.Before(from) {
+			return nil, errors.New("effective_to must be on or after effective_from")
+		}
+		to = &end
+	}
+
+	sameTarget := func(db *gorm.DB) *gorm.DB {
+		if req.EmployeeID != nil {
+			return db.Where("company_id = ? AND employee_id = ?", companyID, *req.EmployeeID)
+		}
+		return db.Where("company_id = ? AND employee_id IS NULL AND LOWER(team_name) = ?", companyID, rosterKey(team))
+	}
+	var later int64
+	if err := sameTarget(s.db.Model(&models.RotationAssignment{})).Where("effective_from >= ?", from).Count(&later).Error; err != nil {
+		return nil, err
+	}
+	if later > 0 {
+		return nil, errors.New("a rotation assignment already exists from this date - delete it first")
+	}
+
+	assignment := &models.RotationAssignment{
+		CompanyID:     companyID,
+		PatternID:     pattern.ID,
+		TeamName:      team,
+		EmployeeID:    req.EmployeeID,
+		OffsetDays:    req.OffsetDays,
+		EffectiveFrom: from,
+		EffectiveTo:   to,
+		CreatedBy:     &createdBy,
+	}
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		if err := sameTarget(tx.Model(&models.RotationAssignment{})).
+			Where("effective_from < ? AND (effective_to IS NULL OR effective_to >= ?)", from, from).
+			Update("effective_to", from.AddDate(0, 0, -1)).Error; err != nil {
+			return err
+		}
+		return tx.Create(assignment).Error
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error creating rotation assignment: %w", err)
+	}
+	assignment.Pattern = &pattern
+	return assignment, nil
+}
+
+// DeleteAssignment removes a rotation assignment
+func (s *RosterService) DeleteAssignment(id, companyID uuid.UUID) error {
+	result := s.db.Where("id = ? AND company_id = ?", id, companyID).Delete(&models.RotationAssignment{})
+	if result.Error != nil {
+		return fmt.Errorf("error deleting rotation assignment: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return errors.New("rotation assignment not found")
+	}
+	return nil
+}
+
+// =========================================================================
+// Staffing rules
+// =========================================================================
+
+// ListStaffingRules returns the company's minimum-staffing rules
+func (s *RosterService) ListStaffingRules(companyID uuid.UUID) ([]models.StaffingRule, error) {
+	var rules []models.StaffingRule
+	if err := s.db.Preload("Shift").Preload("Department").Where("company_id = ?", companyID).
+		Order("production_area, day_of_week").Find(&rules).Error; err != nil {
+		return nil, fmt.Errorf("error fetching staffing rules: %w", err)
+	}
+	return rules, nil
+}
+
+// CreateStaffingRule creates a minimum-staffing rule
+func (s *RosterService) CreateStaffingRule(companyID uuid.UUID, req dtos.StaffingRuleRequest) (*models.StaffingRule, error) {
+	rule := &models.StaffingRule{CompanyID: companyID}
+	if err := s.applyStaffingRule(rule, req); err != nil {
+		return nil, err
+	}
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(rule).Error; err != nil {
+			return err
+		}
+		if !rule.IsActive {
+			return tx.Model(rule).Update("is_active", false).Error
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error creating staffing rule: %w", err)
+	}
+	return rule, nil
+}
+
+// UpdateStaffingRule replaces a minimum-staffing rule
+func (s *RosterService) UpdateStaffingRule(id, companyID uuid.UUID, req dtos.StaffingRuleRequest) (*models.StaffingRule, error) {
+	var rule models.StaffingRule
+	if err := s.db.First(&rule, "id = ? AND company_id = ?", id, companyID).Error; err != nil {
+		return nil, errors.New("staffing rule not found")
+	}
+	if err := s.applyStaffingRule(&rule, req); err != nil {
+		return nil, err
+	}
+	if err := s.db.Save(&rule).Error; err != nil {
+		return nil, fmt.Errorf("error updating staffing rule: %w", err)
+	}
+	return &rule, nil
+}
+
+// DeleteStaffingRule deletes a minimum-staffing rule
+func (s *RosterService) DeleteStaffingRule(id, companyID uuid.UUID) error {
+	result := s.db.Where("id = ? AND company_id = ?", id, companyID).Delete(&models.StaffingRule{})
+	if result.Error != nil {
+		return fmt.Errorf("error deleting staffing rule: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return errors.New("staffing rule not found")
+	}
+	return nil
+}
+
+// applyStaffingRule copies a request onto a rule
+func (s *RosterService) applyStaffingRule(rule *models.StaffingRule, req dtos.StaffingRuleRequest) error {
+	if req.ShiftID != nil {
+		var count int64
+		if err := s.db.Model(&models.Shift{}).Where("id = ? AND company_id = ?", *req.ShiftID, rule.CompanyID).Count(&count).Error; err != nil {
+			return err
+		}
+		if count == 0 {
+			return errors.New("shift not found")
+		}
+	}
+	if err := s.checkDepartment(rule.CompanyID, req.DepartmentID); err != nil {
+		return err
+	}
+	area := strings.TrimSpace(req.ProductionArea)
+	if area == "" && req.DepartmentID == nil {
+		return errors.New("production area or department is required")
+	}
+	if req.MinEmployees <= 0 && req.MaxAbsentPercent <= 0 {
+		return errors.New("min employees or max absent percent is required")
+	}
+	rule.ProductionArea = area
+	rule.DepartmentID = req.DepartmentID
+	rule.ShiftID = req.ShiftID
+	rule.DayOfWeek = req.DayOfWeek
+	rule.MinEmployees = req.MinEmployees
+	rule.MaxAbsentPercent = req.MaxAbsentPercent
+	rule.Enforcement = models.CoverageWarn
+	if req.Enforcement != "" {
+		rule.Enforcement = req.Enforcement
+	}
+	rule.IsActive = req.IsActive == nil || *req.IsActive
+	return nil
+}
+
+// checkDepartment verifies an optional department belongs to the company
+func (s *RosterService) checkDepartment(companyID uuid.UUID, departmentID *uuid.UUID) error {
+	if departmentID == nil {
+		return nil
+	}
+	var count int64
+	if err := s.db.Model(&models.Department{}).Where("id = ? AND company_id = ?", *departmentID, companyID).Count(&count).Error; err != nil {
+		return err
+	}
+	if count == 0 {
+		return errors.New("department not found")
+	}
+	return nil
+}
+
+// staffingRuleCovers reports whether an employee of the area and department
+// counts toward a staffing rule
+func staffingRuleCovers(rule *models.StaffingRule, area string, departmentID *uuid.UUID) bool {
+	if rule.ProductionArea != "" && rosterKey(rule.ProductionArea) != rosterKey(area) {
+		return false
+	}
+	return rule.DepartmentID == nil || sameUUID(rule.DepartmentID, departmentID)
+}
+
+// =========================================================================
+// Blackout periods
+// =========================================================================
+
+// ListBlackouts returns the company's leave blackouts, latest first
+func (s *RosterService) ListBlackouts(companyID uuid.UUID) ([]models.BlackoutPeriod, error) {
+	var blackouts []models.BlackoutPeriod
+	if err := s.db.Preload("Department").Where("company_id = ?", companyID).
+		Order("start_date DESC").Find(&blackouts).Error; err != nil {
+		return nil, fmt.Errorf("error fetching blackout periods: %w", err)
+	}
+	return blackouts, nil
+}
+
+// CreateBlackout creates a leave blackout
+func (s *RosterService) CreateBlackout(companyID uuid.UUID, req dtos.BlackoutPeriodRequest, createdBy uuid.UUID) (*models.BlackoutPeriod, error) {
+	blackout := &models.BlackoutPeriod{CompanyID: companyID, CreatedBy: &createdBy}
+	if err := s.applyBlackout(blackout, req); err != nil {
+		return nil, err
+	}
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(blackout).Error; err != nil {
+			return err
+		}
+		if !blackout.IsActive {
+			return tx.Model(blackout).Update("is_active", false).Error
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error creating blackout period: %w", err)
+	}
+	return blackout, nil
+}
+
+// UpdateBlackout replaces a leave blackout
+func (s *RosterService) UpdateBlackout(id, companyID uuid.UUID, req dtos.BlackoutPeriodRequest) (*models.BlackoutPeriod, error) {
+	var blackout models.BlackoutPeriod
+	if err := s.db.First(&blackout, "id = ? AND company_id = ?", id, companyID).Error; err != nil {
+		return nil, errors.New("blackout period not found")
+	}
+	if err := s.applyBlackout(&blackout, req); err != nil {
+		return nil, err
+	}
+	if err := s.db.Save(&blackout).Error; err != nil {
+		return nil, fmt.Errorf("error updating blackout period: %w", err)
+	}
+	return &blackout, nil
+}
+
+// DeleteBlackout deletes a leave blackout
+func (s *RosterService) DeleteBlackout(id, companyID uuid.UUID) error {
+	result := s.db.Where("id = ? AND company_id = ?", id, companyID).Delete(&models.BlackoutPeriod{})
+	if result.Error != nil {
+		return fmt.Errorf("error deleting blackout period: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return errors.New("blackout period not found")
+	}
+	return nil
+}
+
+// applyBlackout copies a request onto a blackout
+func (s *RosterService) applyBlackout(blackout *models.BlackoutPeriod, req dtos.BlackoutPeriodRequest) error {
+	start, end := attendanceDate(req.StartDate.Time), attendanceDate(req.EndDate.Time)
+	if end.Before(start) {
+		return errors.New("end date must be on or after start date")
+	}
+	if err := s.checkDepartment(blackout.CompanyID, req.DepartmentID); err != nil {
+		return err
+	}
+	types := pq.StringArray{}
+	for _, requestType := range req.RequestTypes {
+		if !isLeaveRequestType(models.RequestType(requestType)) {
+			return fmt.Errorf("request type %s must be a leave type", requestType)
+		}
+		types = append(types, requestType)
+	}
+	blackout.Name = strings.TrimSpace(req.Name)
+	blackout.StartDate = start
+	blackout.EndDate = end
+	blackout.ProductionArea = strings.TrimSpace(req.ProductionArea)
+	blackout.DepartmentID = req.DepartmentID
+	blackout.RequestTypes = types
+	blackout.Enforcement = models.CoverageBlock
+	if req.Enforcement != "" {
+		blackout.Enforcement = req.Enforcement
+	}
+	blackout.Reason = req.Reason
+	blackout.IsActive = req.IsActive == nil || *req.IsActive
+	return nil
+}
+
+// blackoutApplies reports whether a blackout covers the employee and request type
+func blackoutApplies(blackout *models.BlackoutPeriod, employee *models.Employee, requestType models.RequestType) bool {
+	if blackout.ProductionArea != "" && rosterKey(blackout.ProductionArea) != rosterKey(employee.ProductionArea) {
+		return false
+	}
+	if blackout.DepartmentID != nil && !sameUUID(blackout.DepartmentID, employee.DepartmentID) {
+		return false
+	}
+	if len(blackout.RequestTypes) == 0 {
+		return requestType != models.RequestTypeSickLeave
+	}
+	for _, t := range blackout.RequestTypes {
+		if models.RequestType(t) == requestType {
+			return true
+		}
+	}
+	return false
+}
+
+// isLeaveRequestType reports whether a request type takes the employee off work
+func isLeaveRequestType(requestType models.RequestType) bool {
+	for _, t := range leaveRequestTypes {
+		if t == requestType {
+			return true
+		}
+	}
+	return false
+}
+
+// =========================================================================
+// Roster
+// =========================================================================
+
+// GenerateRoster builds the day-by-day roster of a date range and its coverage gaps
+func (s *RosterService) GenerateRoster(companyID uuid.UUID, filter dtos.RosterFilter) (*dtos.Roster, error) {
+	start, end := attendanceDate(filter.StartDate), attendanceDate(filter.EndDate)
+	if end.Before(start) {
+		return nil, errors.New("end date must be on or after start date")
+	}
+	if civilDay(end)-civilDay(start) >= maxRosterDays {
+		return nil, fmt.Errorf("date range must not exceed %d days", maxRosterDays)
+	}
+
+	var employees []models.Employee
+	if err := s.db.Where("company_id = ? AND employment_status = ?", companyID, "active").
+		Order("employee_number").Find(&employees).Error; err != nil {
+		return nil, fmt.Errorf("error fetching employees: %w", err)
+	}
+	rotations, err := loadRotations(s.db, companyID)
+	if err != nil {
+		return nil, fmt.Errorf("error loading rotations: %w", err)
+	}
+	var rules []models.StaffingRule
+	if err := s.db.Preload("Shift").Where("company_id = ? AND is_active = ?", companyID, true).
+		Order("production_area").Find(&rules).Error; err != nil {
+		return nil, fmt.Errorf("error fetching staffing rules: %w", err)
+	}
+
+	dayCount := civilDay(end) - civilDay(start) + 1
+	all := make([][]dtos.RosterEntry, dayCount)
+	roster := &dtos.Roster{StartDate: start, EndDate: end, Days: make([]dtos.RosterDay, dayCount), Gaps: []dtos.CoverageGap{}}
+	for i := range roster.Days {
+		roster.Days[i] = dtos.RosterDay{Date: start.AddDate(0, 0, i), Entries: []dtos.RosterEntry{}}
+	}
+
+	for i := range employees {
+		employee := &employees[i]
+		schedule, err := s.attendance.loadSchedulesWith(employee, start, end, rotations)
+		if err != nil {
+			return nil, fmt.Errorf("error loading schedule of %s: %w", employee.EmployeeNumber, err)
+		}
+		leave, _, err := s.attendance.loadJustifications(employee, start, end)
+		if err != nil {
+			return nil, fmt.Errorf("error loading leave of %s: %w", employee.EmployeeNumber, err)
+		}
+		included := rosterIncludes(employee, filter)
+		for d := 0; d < dayCount; d++ {
+			date := roster.Days[d].Date
+			entry := rosterEntry(employee, schedule(date))
+			entry.Leave = leave[date.Format(attendanceDateKey)]
+			all[d] = append(all[d], entry)
+			if included {
+				roster.Days[d].Entries = append(roster.Days[d].Entries, entry)
+			}
+		}
+	}
+
+	for d := 0; d < dayCount; d++ {
+		date := roster.Days[d].Date
+		weekday := (int(date.Weekday()) + 6) % 7
+		for _, rule := range rules {
+			if rule.MinEmployees <= 0 || (rule.DayOfWeek != nil && *rule.DayOfWeek != weekday) {
+				continue
+			}
+			if filter.ProductionArea != "" && rosterKey(rule.ProductionArea) != rosterKey(filter.ProductionArea) {
+				continue
+			}
+			scheduled := 0
+			for _, entry := range all[d] {
+				if entry.IsRestDay || entry.Leave != "" || entry.ShiftID == nil ||
+					!staffingRuleCovers(&rule, entry.ProductionArea, entry.DepartmentID) ||
+					(rule.ShiftID != nil && *rule.ShiftID != *entry.ShiftID) {
+					continue
+				}
+				scheduled++
+			}
+			if scheduled >= rule.MinEmployees {
+				continue
+			}
+			gap := dtos.CoverageGap{
+				Date:           date,
+				ProductionArea: rule.ProductionArea,
+				DepartmentID:   rule.DepartmentID,
+				ShiftID:        rule.ShiftID,
+				Required:       rule.MinEmployees,
+				Scheduled:      scheduled,
+				Missing:        rule.MinEmployees - scheduled,
+			}
+			if rule.Shift != nil {
+				gap.ShiftCode = rule.Shift.Code
+			}
+			roster.Gaps = append(roster.Gaps, gap)
+		}
+	}
+	return roster, nil
+}
+
+// rosterIncludes reports whether the employee matches the roster filter
+func rosterIncludes(employee *models.Employee, filter dtos.RosterFilter) bool {
+	if filter.EmployeeID != nil && *filter.EmployeeID != employee.ID {
+		return false
+	}
+	if filter.TeamName != "" && rosterKey(filter.TeamName) != rosterKey(employee.TeamName) {
+		return false
+	}
+	if filter.ProductionArea != "" && rosterKey(filter.ProductionArea) != rosterKey(employee.ProductionArea) {
+		return false
+	}
+	return true
+}
+
+// rosterEntry describes an employee's resolved schedule for a day
+func rosterEntry(employee *models.Employee, sched daySchedule) dtos.RosterEntry {
+	entry := dtos.RosterEntry{
+		EmployeeID:     employee.ID,
+		EmployeeNumber: employee.EmployeeNumber,
+		EmployeeName:   strings.TrimSpace(employee.FirstName + " " + employee.LastName),
+		TeamName:       employee.TeamName,
+		ProductionArea: employee.ProductionArea,
+		DepartmentID:   employee.DepartmentID,
+		IsRestDay:      sched.restDay,
+		Source:         sched.source,
+	}
+	if sched.shift != nil && !sched.restDay {
+		id := sched.shift.ID
+		entry.ShiftID = &id
+		entry.ShiftCode = sched.shift.Code
+		entry.ShiftName = sched.shift.Name
+		entry.StartTime = sched.shift.StartTime
+		entry.EndTime = sched.shift.EndTime
+	}
+	return entry
+}
+
+// ExportRoster writes the roster (one column per day) and its coverage gaps to Excel
+func (s *RosterService) ExportRoster(companyID uuid.UUID, filter dtos.RosterFilter) ([]byte, error) {
+	roster, err := s.GenerateRoster(companyID, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	f := excelize.NewFile()
+	defer f.Close()
+	sheet := "Rol de turnos"
+	f.SetSheetName("Sheet1", sheet)
+
+	headers := []string{"Empleado", "Nombre", "Equipo", "Área"}
+	for _, day := range roster.Days {
+		headers = append(headers, fmt.Sprintf("%s %s", rosterWeekdays[day.Date.Weekday()], day.Date.Format("02/01")))
+	}
+	for i, header := range headers {
+		cell, _ := excelize.CoordinatesToCellName(i+1, 1)
+		f.SetCellValue(sheet, cell, header)
+	}
+	if len(roster.Days) > 0 {
+		for row, entry := range roster.Days[0].Entries {
+			values := []interface{}{entry.EmployeeNumber, entry.EmployeeName, entry.TeamName, entry.ProductionArea}
+			for _, day := range roster.Days {
+				values = append(values, rosterCell(day.Entries[row]))
+			}
+			cell, _ := excelize.CoordinatesToCellName(1, row+2)
+			f.SetSheetRow(sheet, cell, &values)
+		}
+	}
+	f.SetPanes(sheet, &excelize.Panes{Freeze: true, XSplit: 4, YSplit: 1, TopLeftCell: "E2", ActivePane: "bottomRight"})
+
+	coverage := "Cobertura"
+	f.NewSheet(coverage)
+	f.SetSheetRow(coverage, "A1", &[]interface{}{"Fecha", "Área", "Turno", "Requeridos", "Programados", "Faltantes"})
+	for i, gap := range roster.Gaps {
+		cell, _ := excelize.CoordinatesToCellName(1, i+2)
+		f.SetSheetRow(coverage, cell, &[]interface{}{
+			gap.Date.Format("2006-01-02"), gap.ProductionArea, gap.ShiftCode, gap.Required, gap.Scheduled, gap.Missing,
+		})
+	}
+
+	buffer, err := f.WriteToBuffer()
+	if err != nil {
+		return nil, fmt.Errorf("failed to write Excel to buffer: %w", err)
+	}
+	return buffer.Bytes(), nil
+}
+
+// rosterCell is the text of one roster cell: leave, rest day or shift code
+func rosterCell(entry dtos.RosterEntry) string {
+	switch {
+	case entry.Leave != "":
+		return entry.Leave
+	case entry.IsRestDay:
+		return "DESC"
+	default:
+		return entry.ShiftCode
+	}
+}
+
+// =========================================================================
+// Absence coverage
+// =========================================================================
+
+// CheckAbsenceCoverage checks a leave request of the employee against the
+// blackouts and staffing rules of their area, department and shift. Each
+// staffing rule is evaluated on the days the employee is scheduled to
+// work, counting colleagues already on approved leave as absent; sick
+// leave is never blocked, only warned about
+func (s *RosterService) CheckAbsenceCoverage(employee *models.Employee, requestType models.RequestType, startDate, endDate time.Time) (*dtos.CoverageCheck, error) {
+	check := &dtos.CoverageCheck{Issues: []dtos.CoverageIssue{}}
+	start, end := attendanceDate(startDate), attendanceDate(endDate)
+	if !isLeaveRequestType(requestType) || end.Before(start) {
+		return check, nil
+	}
+	add := func(issue dtos.CoverageIssue) {
+		if requestType == models.RequestTypeSickLeave {
+			issue.Enforcement = models.CoverageWarn
+		}
+		if issue.Enforcement == models.CoverageBlock {
+			check.Blocked = true
+		}
+		check.Issues = append(check.Issues, issue)
+	}
+
+	var blackouts []models.BlackoutPeriod
+	if err := s.db.Where("company_id = ? AND is_active = ? AND start_date <= ? AND end_date >= ?",
+		employee.CompanyID, true, end, start).Order("start_date").Find(&blackouts).Error; err != nil {
+		return nil, fmt.Errorf("error fetching blackout periods: %w", err)
+	}
+	for i := range blackouts {
+		blackout := &blackouts[i]
+		if !blackoutApplies(blackout, employee, requestType) {
+			continue
+		}
+		from, to := attendanceDate(blackout.StartDate), attendanceDate(blackout.EndDate)
+		if from.Before(start) {
+			from = start
+		}
+		if to.After(end) {
+			to = end
+		}
+		message := fmt.Sprintf("%s: leave is closed from %s to %s", blackout.Name,
+			blackout.StartDate.Format(attendanceDateKey), blackout.EndDate.Format(attendanceDateKey))
+		if blackout.Reason != "" {
+			message += " (" + blackout.Reason + ")"
+		}
+		add(dtos.CoverageIssue{
+			Kind:           dtos.CoverageIssueBlackout,
+			Enforcement:    blackout.Enforcement,
+			Date:           from,
+			Days:           civilDay(to) - civilDay(from) + 1,
+			BlackoutID:     &blackout.ID,
+			Name:           blackout.Name,
+			ProductionArea: blackout.ProductionArea,
+			DepartmentID:   blackout.DepartmentID,
+			Message:        message,
+		})
+	}
+
+	var rules []models.StaffingRule
+	if err := s.db.Preload("Shift").Preload("Department").
+		Where("company_id = ? AND is_active = ?", employee.CompanyID, true).
+		Order("production_area").Find(&rules).Error; err != nil {
+		return nil, fmt.Errorf("error fetching staffing rules: %w", err)
+	}
+	applicable := rules[:0]
+	for _, rule := range rules {
+		if staffingRuleCovers(&rule, employee.ProductionArea, employee.DepartmentID) {
+			applicable = append(applicable, rule)
+		}
+	}
+	if len(applicable) == 0 {
+		return check, nil
+	}
+	if civilDay(end)-civilDay(start) >= maxRosterDays {
+		end = start.AddDate(0, 0, maxRosterDays-1)
+	}
+
+	rotations, err := loadRotations(s.db, employee.CompanyID)
+	if err != nil {
+		return nil, fmt.Errorf("error loading rotations: %w", err)
+	}
+	own, err := s.attendance.loadSchedulesWith(employee, start, end, rotations)
+	if err != nil {
+		return nil, fmt.Errorf("error loading schedule: %w", err)
+	}
+
+	var employees []models.Employee
+	if err := s.db.Where("company_id = ? AND employment_status = ? AND id <> ?", employee.CompanyID, "active", employee.ID).
+		Find(&employees).Error; err != nil {
+		return nil, fmt.Errorf("error fetching employees: %w", err)
+	}
+	type colleague struct {
+		employee *models.Employee
+		schedule func(time.Time) daySchedule
+		leave    map[string]string
+	}
+	var colleagues []colleague
+	for i := range employees {
+		other := &employees[i]
+		covered := false
+		for j := range applicable {
+			if staffingRuleCovers(&applicable[j], other.ProductionArea, other.DepartmentID) {
+				covered = true
+				break
+			}
+		}
+		if !covered {
+			continue
+		}
+		schedule, err := s.attendance.loadSchedulesWith(other, start, end, rotations)
+		if err != nil {
+			return nil, fmt.Errorf("error loading schedule of %s: %w", other.EmployeeNumber, err)
+		}
+		leave, _, err := s.attendance.loadJustifications(other, start, end)
+		if err != nil {
+			return nil, fmt.Errorf("error loading leave of %s: %w", other.EmployeeNumber, err)
+		}
+		colleagues = append(colleagues, colleague{employee: other, schedule: schedule, leave: leave})
+	}
+
+	for i := range applicable {
+		rule := &applicable[i]
+		var issue *dtos.CoverageIssue
+		for date := start; !date.After(end); date = date.AddDate(0, 0, 1) {
+			sched := own(date)
+			if !working(sched) || !staffingRuleShift(rule, sched) {
+				continue
+			}
+			if rule.DayOfWeek != nil && *rule.DayOfWeek != (int(date.Weekday())+6)%7 {
+				continue
+			}
+			// The employee is counted as absent along with colleagues on leave
+			scheduled, absent := 1, 1
+			for _, c := range colleagues {
+				theirs := c.schedule(date)
+				if !staffingRuleCovers(rule, c.employee.ProductionArea, c.employee.DepartmentID) ||
+					!working(theirs) || !staffingRuleShift(rule, theirs) {
+					continue
+				}
+				scheduled++
+				if c.leave[date.Format(attendanceDateKey)] != "" {
+					absent++
+				}
+			}
+			available := scheduled - absent
+			percent := float64(absent) * 100 / float64(scheduled)
+			short := rule.MinEmployees > 0 && available < rule.MinEmployees
+			over := rule.MaxAbsentPercent > 0 && percent > rule.MaxAbsentPercent
+			if !short && !over {
+				continue
+			}
+			if issue != nil {
+				issue.Days++
+				continue
+			}
+			issue = &dtos.CoverageIssue{
+				Kind:             dtos.CoverageIssueStaffing,
+				Enforcement:      rule.Enforcement,
+				Date:             date,
+				Days:             1,
+				StaffingRuleID:   &rule.ID,
+				ProductionArea:   rule.ProductionArea,
+				DepartmentID:     rule.DepartmentID,
+				Required:         rule.MinEmployees,
+				Available:        available,
+				AbsentPercent:    math.Round(percent*100) / 100,
+				MaxAbsentPercent: rule.MaxAbsentPercent,
+			}
+			if rule.Shift != nil {
+				issue.ShiftCode = rule.Shift.Code
+			}
+			if short {
+				issue.Message = fmt.Sprintf("%s: %d employees available on %s, minimum is %d",
+					staffingRuleLabel(rule), available, date.Format(attendanceDateKey), rule.MinEmployees)
+			} else {
+				issue.Message = fmt.Sprintf("%s: %.0f%% absent on %s, maximum is %.0f%%",
+					staffingRuleLabel(rule), percent, date.Format(attendanceDateKey), rule.MaxAbsentPercent)
+			}
+		}
+		if issue != nil {
+			if issue.Days > 1 {
+				issue.Message += fmt.Sprintf(" (%d days affected)", issue.Days)
+			}
+			add(*issue)
+		}
+	}
+	return check, nil
+}
+
+// staffingRuleShift reports whether a working day's shift falls under the rule
+func staffingRuleShift(rule *models.StaffingRule, sched daySchedule) bool {
+	return rule.ShiftID == nil || (sched.shift != nil && sched.shift.ID == *rule.ShiftID)
+}
+
+// staffingRuleLabel names the area, department and shift a rule covers
+func staffingRuleLabel(rule *models.StaffingRule) string {
+	var parts []string
+	if rule.ProductionArea != "" {
+		parts = append(parts, rule.ProductionArea)
+	}
+	if rule.Department != nil {
+		parts = append(parts, rule.Department.Name)
+	}
+	label := strings.Join(parts, " / ")
+	if rule.Shift != nil {
+		label += " (" + rule.Shift.Code + ")"
+	}
+	return label
+}
+
+// =========================================================================
+// Swaps
+// =========================================================================
+
+// RequestSwap files a shift change for one day
+func (s *RosterService) RequestSwap(companyID, requesterID uuid.UUID, req dtos.RosterSwapRequest) (*models.RosterSwap, error) {
+	if (req.CounterpartID == nil) == (req.NewShiftID == nil) {
+		return nil, errors.New("either counterpart_id or new_shift_id is required")
+	}
+	if req.CounterpartID != nil && *req.CounterpartID == req.EmployeeID {
+		return nil, errors.New("counterpart must be a different employee")
+	}
+	date := truncateToDate(req.Date.Time)
+	if attendanceDate(date).Before(attendanceDate(time.Now())) {
+		return nil, errors.New("swap date must not be in the past")
+	}
+
+	var requester models.User
+	if err := s.db.First(&requester, "id = ? AND company_id = ?", requesterID, companyID).Error; err != nil {
+		return nil, errors.New("user not found")
+	}
+	involved := []uuid.UUID{req.EmployeeID}
+	if req.CounterpartID != nil {
+		involved = append(involved, *req.CounterpartID)
+	}
+	if !hasUserRole(requester.Role, rosterPlannerRoles) {
+		own := false
+		for _, id := range involved {
+			own = own || sameUUID(requester.EmployeeID, &id)
+		}
+		if !own {
+			return nil, ErrRosterNotAllowed
+		}
+	}
+	var count int64
+	if err := s.db.Model(&models.Employee{}).Where("id IN ? AND company_id = ? AND employment_status = ?", involved, companyID, "active").Count(&count).Error; err != nil {
+		return nil, err
+	}
+	if int(count) != len(involved) {
+		return nil, errors.New("employee not found or not active")
+	}
+	if req.NewShiftID != nil {
+		if err := s.db.Model(&models.Shift{}).Where("id = ? AND company_id = ?", *req.NewShiftID, companyID).Count(&count).Error; err != nil {
+			return nil, err
+		}
+		if count == 0 {
+			return nil, errors.New("shift not found")
+		}
+	}
+	if err := s.db.Model(&models.RosterSwap{}).
+		Where("company_id = ? AND date = ? AND status IN ? AND (employee_id IN ? OR counterpart_id IN ?)",
+			companyID, date, activeSwapStatuses, involved, involved).
+		Count(&count).Error; err != nil {
+		return nil, err
+	}
+	if count > 0 {
+		return nil, errors.New("a swap request already exists for this day")
+	}
+
+	swap := &models.RosterSwap{
+		CompanyID:     companyID,
+		EmployeeID:    req.EmployeeID,
+		CounterpartID: req.CounterpartID,
+		Date:          date,
+		NewShiftID:    req.NewShiftID,
+		Reason:        req.Reason,
+		Status:        models.RosterSwapPending,
+		RequestedBy:   requesterID,
+	}
+	if err := s.db.Create(swap).Error; err != nil {
+		return nil, fmt.Errorf("error creating swap request: %w", err)
+	}
+	return swap, nil
+}
+
+// ListSwaps returns the company's swap requests, optionally by status
+func (s *RosterService) ListSwaps(companyID uuid.UUID, status string) ([]models.RosterSwap, error) {
+	query := s.db.Preload("Employee").Preload("Counterpart").Preload("NewShift").Preload("OfferedShift").
+		Where("company_id = ?", companyID)
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+	var swaps []models.RosterSwap
+	if err := query.Order("date DESC, created_at DESC").Find(&swaps).Error; err != nil {
+		return nil, fmt.Errorf("error fetching swap requests: %w", err)
+	}
+	return swaps, nil
+}
+
+// ReviewSwap approves or rejects a direct swap or an accepted offer;
+// approving re-checks the rest and weekly-hour rules and writes the ShiftExceptions
+func (s *RosterService) ReviewSwap(id, companyID, reviewerID uuid.UUID, req dtos.RosterSwapReviewRequest) (*models.RosterSwap, error) {
+	var swap models.RosterSwap
+	if err := s.db.First(&swap, "id = ? AND company_id = ?", id, companyID).Error; err != nil {
+		return nil, errors.New("swap request not found")
+	}
+	if swap.Status != models.RosterSwapPending && swap.Status != models.RosterSwapAccepted {
+		return nil, errors.New("swap request must be pending or accepted to review it")
+	}
+	var reviewer models.User
+	if err := s.db.First(&reviewer, "id = ? AND company_id = ?", reviewerID, companyID).Error; err != nil {
+		return nil, errors.New("user not found")
+	}
+	if sameUUID(reviewer.EmployeeID, &swap.EmployeeID) || sameUUID(reviewer.EmployeeID, swap.CounterpartID) {
+		return nil, errors.New("swap request cannot be reviewed by an employee it involves")
+	}
+
+	now := time.Now()
+	swap.ReviewedBy = &reviewerID
+	swap.ReviewedAt = &now
+	swap.ReviewNotes = req.Notes
+	if req.Action == "reject" {
+		swap.Status = models.RosterSwapRejected
+		if err := s.db.Save(&swap).Error; err != nil {
+			return nil, fmt.Errorf("error updating swap request: %w", err)
+		}
+		return &swap, nil
+	}
+
+	changes, err := s.swapChanges(&swap)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.checkSwapRules(swap.Date, changes); err != nil {
+		return nil, err
+	}
+	swap.Status = models.RosterSwapApproved
+	err = s.db.Transaction(func(tx *gorm.DB) error {
+		for employeeID, shiftID := range changes {
+			if err := setShiftException(tx, employeeID, swap.Date, shiftID, reviewerID); err != nil {
+				return err
+			}
+		}
+		return tx.Save(&swap).Error
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error approving swap request: %w", err)
+	}
+	return &swap, nil
+}
+
+// swapChanges returns the shift each involved employee gets on the swap date
+func (s *RosterService) swapChanges(swap *models.RosterSwap) (map[uuid.UUID]uuid.UUID, error) {
+	if swap.CounterpartID == nil {
+		return map[uuid.UUID]uuid.UUID{swap.EmployeeID: *swap.NewShiftID}, nil
+	}
+
+	rotations, err := loadRotations(s.db, swap.CompanyID)
+	if err != nil {
+		return nil, err
+	}
+	date := attendanceDate(swap.Date)
+	current := make(map[uuid.UUID]*models.Shift, 2)
+	for _, id := range []uuid.UUID{swap.EmployeeID, *swap.CounterpartID} {
+		var employee models.Employee
+		if err := s.db.First(&employee, "id = ?", id).Error; err != nil {
+			return nil, errors.New("employee not found")
+		}
+		schedule, err := s.attendance.loadSchedulesWith(&employee, date, date, rotations)
+		if err != nil {
+			return nil, err
+		}
+		sched := schedule(date)
+		switch {
+		case sched.restDay:
+			current[id] = nil
+		case sched.shift != nil:
+			current[id] = sched.shift
+		default:
+			return nil, fmt.Errorf("employee %s has no shift scheduled for the day", employee.EmployeeNumber)
+		}
+	}
+
+	var restShift *models.Shift
+	shiftID := func(shift *models.Shift) (uuid.UUID, error) {
+		if shift != nil {
+			return shift.ID, nil
+		}
+		if restShift == nil {
+			var found models.Shift
+			if err := s.db.Where("company_id = ? AND is_rest_day = ? AND is_active = ?", swap.CompanyID, true, true).
+				First(&found).Error; err != nil {
+				return uuid.Nil, errors.New("a rest-day shift (is_rest_day) is required to swap a rest day")
+			}
+			restShift = &found
+		}
+		return restShift.ID, nil
+	}
+	employeeShift, err := shiftID(current[*swap.CounterpartID])
+	if err != nil {
+		return nil, err
+	}
+	counterpartShift, err := shiftID(current[swap.EmployeeID])
+	if err != nil {
+		return nil, err
+	}
+	return map[uuid.UUID]uuid.UUID{swap.EmployeeID: employeeShift, *swap.CounterpartID: counterpartShift}, nil
+}
+
+// setShiftException creates or replaces an employee's shift exception for a date
+func setShiftException(tx *gorm.DB, employeeID uuid.UUID, date time.Time, shiftID, createdBy uuid.UUID) error {
+	date = attendanceDate(date)
+	var existing models.ShiftException
+	err := tx.Where("employee_id = ? AND date = ?", employeeID, date).First(&existing).Error
+	switch {
+	case err == nil:
+		existing.ShiftID = shiftID
+		existing.CreatedByID = createdBy
+		return tx.Save(&existing).Error
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		return tx.Create(&models.ShiftException{EmployeeID: employeeID, Date: date, ShiftID: shiftID, CreatedByID: createdBy}).Error
+	default:
+		return err
+	}
+}
+
+// checkSwapRules verifies that the shifts a swap gives each employee keep
+// 12 hours of rest around them and stay within the weekly hours of their TimePolicy
+func (s *RosterService) checkSwapRules(date time.Time, changes map[uuid.UUID]uuid.UUID) error {
+	date = attendanceDate(date)
+	monday := policyWeekStart(date)
+	from, to := monday, monday.AddDate(0, 0, 6)
+	if date.AddDate(0, 0, 1).After(to) {
+		to = date.AddDate(0, 0, 1)
+	}
+	from = from.AddDate(0, 0, -1)
+
+	var rotations *rotationResolver
+	for employeeID, shiftID := range changes {
+		var employee models.Employee
+		if err := s.db.First(&employee, "id = ?", employeeID).Error; err != nil {
+			return errors.New("employee not found")
+		}
+		if rotations == nil {
+			var err error
+			if rotations, err = loadRotations(s.db, employee.CompanyID); err != nil {
+				return err
+			}
+		}
+		var shift models.Shift
+		if err := s.db.First(&shift, "id = ?", shiftID).Error; err != nil {
+			return errors.New("shift not found")
+		}
+		schedule, err := s.attendance.loadSchedulesWith(&employee, from, to, rotations)
+		if err != nil {
+			return err
+		}
+		day := buildDaySchedule(date, &shift, models.ScheduleSourceException, true)
+		scheduleOn := func(d time.Time) daySchedule {
+			if d.Equal(date) {
+				return day
+			}
+			return schedule(d)
+		}
+
+		if working(day) {
+			if prev := scheduleOn(date.AddDate(0, 0, -1)); working(prev) && day.start.Sub(prev.end) < minRestBetweenShifts {
+				return fmt.Errorf("swap must leave at least %.0f hours of rest between shifts for %s (%.1f after the previous shift)",
+					minRestBetweenShifts.Hours(), employee.EmployeeNumber, day.start.Sub(prev.end).Hours())
+			}
+			if next := scheduleOn(date.AddDate(0, 0, 1)); working(next) && next.start.Sub(day.end) < minRestBetweenShifts {
+				return fmt.Errorf("swap must leave at least %.0f hours of rest between shifts for %s (%.1f before the next shift)",
+					minRestBetweenShifts.Hours(), employee.EmployeeNumber, next.start.Sub(day.end).Hours())
+			}
+		}
+
+		policy, _, err := s.policies.ResolvePolicy(&employee)
+		if err != nil {
+			return err
+		}
+		total := 0.0
+		for i := 0; i < 7; i++ {
+			if sched := scheduleOn(monday.AddDate(0, 0, i)); working(sched) {
+				total += sched.hours
+			}
+		}
+		if policy.StandardWeeklyHours > 0 && total > policy.StandardWeeklyHours {
+			return fmt.Errorf("swap must not schedule %s for more than %.0f hours in the week (%.2f)",
+				employee.EmployeeNumber, policy.StandardWeeklyHours, total)
+		}
+	}
+	return nil
+}
+
+// working reports whether a day schedule is a shift with known times
+func working(sched daySchedule) bool {
+	return !sched.restDay && !sched.start.IsZero()
+}
+
+// =========================================================================
+// Swap marketplace
+// =========================================================================
+
+// OfferShift puts the requester's shift of a day on the swap marketplace
+func (s *RosterService) OfferShift(companyID, requesterID uuid.UUID, req dtos.ShiftOfferRequest) (*models.RosterSwap, error) {
+	employee, err := s.userEmployee(companyID, requesterID)
+	if err != nil {
+		return nil, err
+	}
+	date := truncateToDate(req.Date.Time)
+	if attendanceDate(date).Before(attendanceDate(time.Now())) {
+		return nil, errors.New("offer date must not be in the past")
+	}
+	schedule, err := s.attendance.loadSchedules(employee, attendanceDate(date), attendanceDate(date))
+	if err != nil {
+		return nil, err
+	}
+	sched := schedule(attendanceDate(date))
+	if !working(sched) || sched.shift == nil {
+		return nil, errors.New("offered day must be a scheduled working day")
+	}
+	var count int64
+	if err := s.db.Model(&models.RosterSwap{}).
+		Where("company_id = ? AND date = ? AND status IN ? AND (employee_id = ? OR counterpart_id = ?)",
+			companyID, date, activeSwapStatuses, employee.ID, employee.ID).
+		Count(&count).Error; err != nil {
+		return nil, err
+	}
+	if count > 0 {
+		return nil, errors.New("a swap request already exists for this day")
+	}
+
+	offer := &models.RosterSwap{
+		CompanyID:      companyID,
+		EmployeeID:     employee.ID,
+		Date:           date,
+		IsOffer:        true,
+		OfferedShiftID: &sched.shift.ID,
+		Reason:         req.Reason,
+		Status:         models.RosterSwapOpen,
+		RequestedBy:    requesterID,
+	}
+	if err := s.db.Create(offer).Error; err != nil {
+		return nil, fmt.Errorf("error creating shift offer: %w", err)
+	}
+	offer.OfferedShift = sched.shift
+	return offer, nil
+}
+
+// ListOffers returns the upcoming offers: planners see all of them, employees
+// their own and the open ones they are eligible for
+func (s *RosterService) ListOffers(companyID, userID uuid.UUID) ([]models.RosterSwap, error) {
+	var user models.User
+	if err := s.db.First(&user, "id = ? AND company_id = ?", userID, companyID).Error; err != nil {
+		return nil, errors.New("user not found")
+	}
+	var offers []models.RosterSwap
+	if err := s.db.Preload("Employee").Preload("Counterpart").Preload("OfferedShift").
+		Where("company_id = ? AND is_offer = ? AND status IN ? AND date >= ?", companyID, true,
+			[]string{models.RosterSwapOpen, models.RosterSwapAccepted}, truncateToDate(time.Now())).
+		Order("date").Find(&offers).Error; err != nil {
+		return nil, fmt.Errorf("error fetching shift offers: %w", err)
+	}
+	if hasUserRole(user.Role, rosterPlannerRoles) {
+		return offers, nil
+	}
+	if user.EmployeeID == nil {
+		return []models.RosterSwap{}, nil
+	}
+	var colleague models.Employee
+	if err := s.db.First(&colleague, "id = ?", *user.EmployeeID).Error; err != nil {
+		return nil, errors.New("employee not found")
+	}
+	visible := make([]models.RosterSwap, 0, len(offers))
+	for _, offer := range offers {
+		own := offer.EmployeeID == colleague.ID || sameUUID(offer.CounterpartID, &colleague.ID)
+		if own || (offer.Status == models.RosterSwapOpen && offerEligibility(&offer, &colleague) == nil) {
+			visible = append(visible, offer)
+		}
+	}
+	return visible, nil
+}
+
+// AcceptOffer lets an eligible colleague take an open offer; the swap then waits for the supervisor
+func (s *RosterService) AcceptOffer(id, companyID, userID uuid.UUID) (*models.RosterSwap, error) {
+	var offer models.RosterSwap
+	if err := s.db.Preload("Employee").Preload("OfferedShift").
+		First(&offer, "id = ? AND company_id = ? AND is_offer = ?", id, companyID, true).Error; err != nil {
+		return nil, errors.New("shift offer not found")
+	}
+	if offer.Status != models.RosterSwapOpen {
+		return nil, errors.New("shift offer must be open to accept it")
+	}
+	if attendanceDate(offer.Date).Before(attendanceDate(time.Now())) {
+		return nil, errors.New("shift offer cannot be accepted after its day")
+	}
+	colleague, err := s.userEmployee(companyID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if err := offerEligibility(&offer, colleague); err != nil {
+		return nil, err
+	}
+	leave, _, err := s.attendance.loadJustifications(colleague, attendanceDate(offer.Date), attendanceDate(offer.Date))
+	if err != nil {
+		return nil, err
+	}
+	if leave[attendanceDate(offer.Date).Format(attendanceDateKey)] != "" {
+		return nil, errors.New("colleague must not be on leave that day")
+	}
+
+	offer.CounterpartID = &colleague.ID
+	changes, err := s.swapChanges(&offer)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.checkSwapRules(offer.Date, changes); err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	offer.Status = models.RosterSwapAccepted
+	offer.AcceptedAt = &now
+	resolution, _ := s.orgStructure.ResolveApprover(offer.EmployeeID, now)
+	err = s.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Omit("Employee", "OfferedShift").Save(&offer).Error; err != nil {
+			return err
+		}
+		if resolution != nil && resolution.ApproverUserID != nil {
+			if err := tx.Create(&models.Notification{
+				CompanyID:    companyID,
+				ActorUserID:  userID,
+				TargetUserID: resolution.ApproverUserID,
+				Type:         models.NotificationIncidenceCreated,
+				Title:        "Intercambio de turno",
+				Message: fmt.Sprintf("%s %s cubrirá el turno del %s de %s %s; pendiente de aprobación",
+					colleague.FirstName, colleague.LastName, offer.Date.Format("2006-01-02"),
+					offer.Employee.FirstName, offer.Employee.LastName),
+				ResourceType: "roster_swap",
+				ResourceID:   &offer.ID,
+			}).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error accepting shift offer: %w", err)
+	}
+	offer.Counterpart = colleague
+	return &offer, nil
+}
+
+// CancelOffer withdraws an offer before the supervisor reviews it
+func (s *RosterService) CancelOffer(id, companyID, userID uuid.UUID) (*models.RosterSwap, error) {
+	var offer models.RosterSwap
+	if err := s.db.First(&offer, "id = ? AND company_id = ? AND is_offer = ?", id, companyID, true).Error; err != nil {
+		return nil, errors.New("shift offer not found")
+	}
+	var user models.User
+	if err := s.db.First(&user, "id = ? AND company_id = ?", userID, companyID).Error; err != nil {
+		return nil, errors.New("user not found")
+	}
+	if !sameUUID(user.EmployeeID, &offer.EmployeeID) && !hasUserRole(user.Role, rosterPlannerRoles) {
+		return nil, ErrRosterNotAllowed
+	}
+	if offer.Status != models.RosterSwapOpen && offer.Status != models.RosterSwapAccepted {
+		return nil, errors.New("shift offer cannot be cancelled once reviewed")
+	}
+	offer.Status = models.RosterSwapCancelled
+	if err := s.db.Save(&offer).Error; err != nil {
+		return nil, fmt.Errorf("error cancelling shift offer: %w", err)
+	}
+	return &offer, nil
+}
+
+// offerEligibility checks that a colleague may take an offer: another
+// active employee of the same production area whose collar type the shift allows
+func offerEligibility(offer *models.RosterSwap, colleague *models.Employee) error {
+	if colleague.ID == offer.EmployeeID {
+		return errors.New("shift offer cannot be accepted by the employee who offered it")
+	}
+	if colleague.EmploymentStatus != "active" {
+		return errors.New("employee is not active")
+	}
+	if offer.Employee != nil && offer.Employee.ProductionArea != "" &&
+		rosterKey(offer.Employee.ProductionArea) != rosterKey(colleague.ProductionArea) {
+		return errors.New("colleague must work in the same production area")
+	}
+	if offer.OfferedShift != nil && offer.OfferedShift.CollarTypes != "" && offer.OfferedShift.CollarTypes != "[]" {
+		var collarTypes []string
+		if err := json.Unmarshal([]byte(offer.OfferedShift.CollarTypes), &collarTypes); err == nil &&
+			len(collarTypes) > 0 && !containsString(collarTypes, colleague.CollarType) {
+			return errors.New("colleague's collar type must be allowed on the shift")
+		}
+	}
+	return nil
+}
+
+// userEmployee returns the employee record of a portal user
+func (s *RosterService) userEmployee(companyID, userID uuid.UUID) (*models.Employee, error) {
+	var user models.User
+	if err := s.db.First(&user, "id = ? AND company_id = ?", userID, companyID).Error; err != nil {
+		return nil, errors.New("user not found")
+	}
+	if user.EmployeeID == nil {
+		return nil, errors.New("an employee record is required to swap shifts")
+	}
+	var employee models.Employee
+	if err := s.db.First(&employee, "id = ? AND company_id = ?", *user.EmployeeID, companyID).Error; err != nil {
+		return nil, errors.New("employee not found")
+	}
+	if employee.EmploymentStatus != "active" {
+		return nil, errors.New("employee is not active")
+	}
+	return &employee, nil
+}
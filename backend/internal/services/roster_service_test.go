@@ -0,0 +1,399 @@
+package services
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/xuri/excelize/v2"
+
+	"backend/internal/dtos"
+	"backend/internal/models"
+)
+
+func TestRoster_RotationsCoverageAndSwaps(t *testing.T) {
+	db := setupPayrollTestDB(t)
+	require.NoError(t, db.AutoMigrate(
+		&models.User{}, &models.AbsenceRequest{}, &models.IncidenceType{}, &models.Incidence{}, &models.SickLeaveCertificate{},
+		&models.Shift{}, &models.EmployeeShiftBase{}, &models.ShiftException{},
+		&models.RotationPattern{}, &models.RotationPatternDay{}, &models.RotationAssignment{},
+		&models.RosterSwap{}, &models.StaffingRule{}, &models.TimePolicy{},
+	))
+	company := createPayrollTestCompany(t, db)
+	service := NewRosterService(db)
+
+	shift := func(code, start, end string) *models.Shift {
+		s := &models.Shift{Name: code, Code: code, StartTime: start, EndTime: end, WorkHoursPerDay: 8,
+			CompanyID: company.ID, IsActive: true}
+		require.NoError(t, db.Create(s).Error)
+		return s
+	}
+	morning := shift("MAT", "06:00", "14:00")
+	afternoon := shift("VES", "14:00", "22:00")
+
+	employee := func(n int, team, area string) *models.Employee {
+		e := createFixtureEmployee(t, db, company.ID, n, "general", 400)
+		require.NoError(t, db.Model(e).Updates(map[string]interface{}{"team_name": team, "production_area": area}).Error)
+		e.TeamName, e.ProductionArea = team, area
+		return e
+	}
+	teamA := employee(1, "A", "Ensamble")
+	teamB := employee(2, "B", "Ensamble")
+	painter := employee(3, "A", "Pintura")
+	hr := createFixtureUser(t, db, employee(4, "", "Oficina"), "hr")
+	teamAUser := createFixtureUser(t, db, teamA, "employee")
+
+	// 4x3 anchored on next week's Monday
+	monday := policyWeekStart(attendanceDate(time.Now())).AddDate(0, 0, 7)
+	days := make([]dtos.RotationDayInput, 7)
+	for i := 0; i < 4; i++ {
+		days[i].ShiftID = &morning.ID
+	}
+	fourByThree, err := service.CreatePattern(company.ID, dtos.RotationPatternRequest{
+		Name: "4x3 matutino", Code: "4x3", AnchorDate: dtos.Date{Time: monday}, Days: days,
+	}, hr.ID)
+	require.NoError(t, err)
+	assert.Equal(t, 7, fourByThree.CycleLengthDays)
+	afternoons, err := service.CreatePattern(company.ID, dtos.RotationPatternRequest{
+		Name: "Vespertino", Code: "VES", AnchorDate: dtos.Date{Time: monday},
+		Days: []dtos.RotationDayInput{{ShiftID: &afternoon.ID}},
+	}, hr.ID)
+	require.NoError(t, err)
+
+	assign := func(req dtos.RotationAssignmentRequest) {
+		req.EffectiveFrom = dtos.Date{Time: monday}
+		_, err := service.CreateAssignment(company.ID, req, hr.ID)
+		require.NoError(t, err)
+	}
+	assign(dtos.RotationAssignmentRequest{PatternID: fourByThree.ID, TeamName: "A"})
+	assign(dtos.RotationAssignmentRequest{PatternID: fourByThree.ID, TeamName: "b", OffsetDays: 4})
+	assign(dtos.RotationAssignmentRequest{PatternID: afternoons.ID, EmployeeID: &painter.ID})
+	_, err = service.CreateAssignment(company.ID, dtos.RotationAssignmentRequest{
+		PatternID: fourByThree.ID, TeamName: "A", EffectiveFrom: dtos.Date{Time: monday},
+	}, hr.ID)
+	assert.ErrorContains(t, err, "already exists")
+	assert.ErrorContains(t, service.DeletePattern(fourByThree.ID, company.ID), "cannot be deleted")
+
+	_, err = service.CreateStaffingRule(company.ID, dtos.StaffingRuleRequest{ProductionArea: "Ensamble", MinEmployees: 2})
+	require.NoError(t, err)
+
+	// Team A on vacation on Thursday, the only day both teams overlap
+	thursday := monday.AddDate(0, 0, 3)
+	require.NoError(t, db.Create(&models.AbsenceRequest{EmployeeID: teamAUser.ID, RequestType: models.RequestTypeVacation,
+		StartDate: thursday, EndDate: thursday, TotalDays: 1, Status: models.RequestStatusApproved}).Error)
+
+	week := dtos.RosterFilter{StartDate: monday, EndDate: monday.AddDate(0, 0, 6)}
+	roster, err := service.GenerateRoster(company.ID, week)
+	require.NoError(t, err)
+	require.Len(t, roster.Days, 7)
+	codes := func(roster *dtos.Roster, employeeID uuid.UUID) []string {
+		var out []string
+		for _, day := range roster.Days {
+			for _, entry := range day.Entries {
+				if entry.EmployeeID == employeeID {
+					out = append(out, rosterCell(entry))
+				}
+			}
+		}
+		return out
+	}
+	assert.Equal(t, []string{"MAT", "MAT", "MAT", "VACATION", "DESC", "DESC", "DESC"}, codes(roster, teamA.ID))
+	assert.Equal(t, []string{"DESC", "DESC", "DESC", "MAT", "MAT", "MAT", "MAT"}, codes(roster, teamB.ID))
+	assert.Equal(t, []string{"VES", "VES", "VES", "VES", "VES", "VES", "VES"}, codes(roster, painter.ID), "own assignment wins over the team's")
+	assert.Equal(t, models.ScheduleSourceRotation, roster.Days[0].Entries[0].Source)
+	assert.Len(t, roster.Gaps, 7, "Ensamble has one operator a day")
+	assert.Equal(t, 1, roster.Gaps[3].Missing)
+
+	filtered, err := service.GenerateRoster(company.ID, dtos.RosterFilter{StartDate: monday, EndDate: monday, TeamName: "a"})
+	require.NoError(t, err)
+	assert.Len(t, filtered.Days[0].Entries, 2)
+	assert.Len(t, filtered.Gaps, 1, "coverage counts the whole company, not the filtered team")
+
+	// Team A trades Wednesday with team B's rest day; a rest-day shift is needed
+	wednesday := monday.AddDate(0, 0, 2)
+	swap, err := service.RequestSwap(company.ID, teamAUser.ID, dtos.RosterSwapRequest{
+		EmployeeID: teamA.ID, CounterpartID: &teamB.ID, Date: dtos.Date{Time: wednesday}, Reason: "Cita médica",
+	})
+	require.NoError(t, err)
+	_, err = service.RequestSwap(company.ID, teamAUser.ID, dtos.RosterSwapRequest{
+		EmployeeID: painter.ID, NewShiftID: &morning.ID, Date: dtos.Date{Time: wednesday}, Reason: "Apoyo",
+	})
+	assert.ErrorIs(t, err, ErrRosterNotAllowed)
+	_, err = service.ReviewSwap(swap.ID, company.ID, teamAUser.ID, dtos.RosterSwapReviewRequest{Action: "approve"})
+	assert.ErrorContains(t, err, "cannot be reviewed")
+	_, err = service.ReviewSwap(swap.ID, company.ID, hr.ID, dtos.RosterSwapReviewRequest{Action: "approve"})
+	assert.ErrorContains(t, err, "rest-day shift")
+
+	rest := &models.Shift{Name: "Descanso", Code: "DESC", StartTime: "00:00", EndTime: "00:00", CompanyID: company.ID,
+		IsActive: true, IsRestDay: true}
+	require.NoError(t, db.Create(rest).Error)
+	swap, err = service.ReviewSwap(swap.ID, company.ID, hr.ID, dtos.RosterSwapReviewRequest{Action: "approve"})
+	require.NoError(t, err)
+	assert.Equal(t, models.RosterSwapApproved, swap.Status)
+
+	roster, err = service.GenerateRoster(company.ID, week)
+	require.NoError(t, err)
+	assert.Equal(t, "DESC", codes(roster, teamA.ID)[2])
+	assert.Equal(t, "MAT", codes(roster, teamB.ID)[2])
+	assert.Equal(t, models.ScheduleSourceException, roster.Days[2].Entries[1].Source)
+
+	file, err := service.ExportRoster(company.ID, week)
+	require.NoError(t, err)
+	workbook, err := excelize.OpenReader(bytes.NewReader(file))
+	require.NoError(t, err)
+	header, _ := workbook.GetCellValue("Rol de turnos", "E1")
+	assert.Equal(t, "Lun "+monday.Format("02/01"), header)
+	first, _ := workbook.GetCellValue("Rol de turnos", "E2")
+	assert.Equal(t, "MAT", first)
+	gaps, _ := workbook.GetRows("Cobertura")
+	assert.Len(t, gaps, 1+len(roster.Gaps))
+}
+
+func TestRoster_ShiftOfferMarketplace(t *testing.T) {
+	db := setupPayrollTestDB(t)
+	require.NoError(t, db.AutoMigrate(
+		&models.User{}, &models.ReportingLine{}, &models.EmployeeHierarchy{}, &models.AbsenceRequest{},
+		&models.IncidenceType{}, &models.Incidence{}, &models.SickLeaveCertificate{}, &models.Shift{}, &models.EmployeeShiftBase{},
+		&models.ShiftException{}, &models.TimePolicy{}, &models.Notification{},
+		&models.RotationPattern{}, &models.RotationPatternDay{}, &models.RotationAssignment{},
+		&models.RosterSwap{}, &models.StaffingRule{},
+	))
+	company := createPayrollTestCompany(t, db)
+	service := NewRosterService(db)
+
+	shift := func(code, start, end string, restDay bool) *models.Shift {
+		s := &models.Shift{Name: code, Code: code, StartTime: start, EndTime: end, WorkHoursPerDay: 8,
+			CompanyID: company.ID, IsActive: true, IsRestDay: restDay, CollarTypes: `["blue_collar"]`}
+		require.NoError(t, db.Create(s).Error)
+		return s
+	}
+	morning := shift("MAT", "06:00", "14:00", false)
+	night := shift("NOC", "22:00", "06:00", false)
+	shift("DESC", "00:00", "00:00", true)
+
+	employee := func(n int, team, area string) *models.Employee {
+		e := createFixtureEmployee(t, db, company.ID, n, "general", 400)
+		require.NoError(t, db.Model(e).Updates(map[string]interface{}{"team_name": team, "production_area": area}).Error)
+		e.TeamName, e.ProductionArea = team, area
+		return e
+	}
+	offerer := employee(1, "A", "Ensamble")
+	colleague := employee(2, "B", "Ensamble")
+	nightOperator := employee(3, "N", "Ensamble")
+	sixDays := employee(4, "S", "Ensamble")
+	painter := employee(5, "A", "Pintura")
+	clerk := employee(6, "B", "Ensamble")
+	require.NoError(t, db.Model(clerk).Update("collar_type", "white_collar").Error)
+	supervisorEmployee := employee(7, "", "Ensamble")
+	supervisor := createFixtureUser(t, db, supervisorEmployee, "supervisor")
+	users := map[*models.Employee]*models.User{}
+	for _, e := range []*models.Employee{offerer, colleague, nightOperator, sixDays, painter, clerk} {
+		users[e] = createFixtureUser(t, db, e, "employee")
+	}
+	_, err := NewOrgStructureService(db).SetManager(company.ID, dtos.ReportingLineRequest{
+		EmployeeID: offerer.ID, ManagerID: &supervisorEmployee.ID, EffectiveFrom: dtos.Date{Time: time.Now().AddDate(0, -1, 0)},
+	}, supervisor.ID)
+	require.NoError(t, err)
+
+	// A: 4x3 mornings from Monday; B: the same four days later; N: nights every
+	// day; S: six mornings with Monday off
+	monday := policyWeekStart(attendanceDate(time.Now())).AddDate(0, 0, 7)
+	pattern := func(code string, days []*models.Shift) *models.RotationPattern {
+		input := make([]dtos.RotationDayInput, len(days))
+		for i, s := range days {
+			if s != nil {
+				input[i].ShiftID = &s.ID
+			}
+		}
+		p, err := service.CreatePattern(company.ID, dtos.RotationPatternRequest{
+			Name: code, Code: code, AnchorDate: dtos.Date{Time: monday}, Days: input,
+		}, supervisor.ID)
+		require.NoError(t, err)
+		return p
+	}
+	fourByThree := pattern("4x3", []*models.Shift{morning, morning, morning, morning, nil, nil, nil})
+	nights := pattern("NOC", []*models.Shift{night})
+	sixByOne := pattern("6x1", []*models.Shift{nil, morning, morning, morning, morning, morning, morning})
+	for _, req := range []dtos.RotationAssignmentRequest{
+		{PatternID: fourByThree.ID, TeamName: "A"},
+		{PatternID: fourByThree.ID, TeamName: "B", OffsetDays: 4},
+		{PatternID: nights.ID, TeamName: "N"},
+		{PatternID: sixByOne.ID, TeamName: "S"},
+	} {
+		req.EffectiveFrom = dtos.Date{Time: monday.AddDate(0, 0, -7)}
+		_, err := service.CreateAssignment(company.ID, req, supervisor.ID)
+		require.NoError(t, err)
+	}
+
+	_, err = service.OfferShift(company.ID, users[offerer].ID, dtos.ShiftOfferRequest{Date: dtos.Date{Time: monday.AddDate(0, 0, 5)}})
+	assert.ErrorContains(t, err, "scheduled working day")
+	offer, err := service.OfferShift(company.ID, users[offerer].ID, dtos.ShiftOfferRequest{Date: dtos.Date{Time: monday}, Reason: "Trámite"})
+	require.NoError(t, err)
+	assert.Equal(t, models.RosterSwapOpen, offer.Status)
+	assert.Equal(t, morning.ID, *offer.OfferedShiftID)
+	_, err = service.OfferShift(company.ID, users[offerer].ID, dtos.ShiftOfferRequest{Date: dtos.Date{Time: monday}})
+	assert.ErrorContains(t, err, "already exists")
+
+	visible, err := service.ListOffers(company.ID, users[colleague].ID)
+	require.NoError(t, err)
+	assert.Len(t, visible, 1)
+	visible, err = service.ListOffers(company.ID, users[painter].ID)
+	require.NoError(t, err)
+	assert.Empty(t, visible, "offers are only shown to eligible colleagues")
+
+	_, err = service.AcceptOffer(offer.ID, company.ID, users[painter].ID)
+	assert.ErrorContains(t, err, "same production area")
+	_, err = service.AcceptOffer(offer.ID, company.ID, users[clerk].ID)
+	assert.ErrorContains(t, err, "collar type")
+	_, err = service.AcceptOffer(offer.ID, company.ID, users[nightOperator].ID)
+	assert.ErrorContains(t, err, "12 hours of rest")
+	_, err = service.AcceptOffer(offer.ID, company.ID, users[sixDays].ID)
+	assert.ErrorContains(t, err, "more than 48 hours in the week")
+	_, err = service.CancelOffer(offer.ID, company.ID, users[colleague].ID)
+	assert.ErrorIs(t, err, ErrRosterNotAllowed)
+
+	accepted, err := service.AcceptOffer(offer.ID, company.ID, users[colleague].ID)
+	require.NoError(t, err)
+	assert.Equal(t, models.RosterSwapAccepted, accepted.Status)
+	assert.Equal(t, colleague.ID, *accepted.CounterpartID)
+	var notifications int64
+	db.Model(&models.Notification{}).Where("resource_type = ? AND target_user_id = ?", "roster_swap", supervisor.ID).Count(&notifications)
+	assert.Equal(t, int64(1), notifications, "the offerer's supervisor is asked to approve")
+	_, err = service.AcceptOffer(offer.ID, company.ID, users[sixDays].ID)
+	assert.ErrorContains(t, err, "must be open")
+
+	approved, err := service.ReviewSwap(offer.ID, company.ID, supervisor.ID, dtos.RosterSwapReviewRequest{Action: "approve"})
+	require.NoError(t, err)
+	assert.Equal(t, models.RosterSwapApproved, approved.Status)
+	var exceptions int64
+	db.Model(&models.ShiftException{}).Where("employee_id IN ?", []uuid.UUID{offerer.ID, colleague.ID}).Count(&exceptions)
+	assert.Equal(t, int64(2), exceptions)
+	roster, err := service.GenerateRoster(company.ID, dtos.RosterFilter{StartDate: monday, EndDate: monday})
+	require.NoError(t, err)
+	cells := map[uuid.UUID]string{}
+	for _, entry := range roster.Days[0].Entries {
+		cells[entry.EmployeeID] = rosterCell(entry)
+	}
+	assert.Equal(t, "DESC", cells[offerer.ID])
+	assert.Equal(t, "MAT", cells[colleague.ID])
+	_, err = service.CancelOffer(offer.ID, company.ID, users[offerer].ID)
+	assert.ErrorContains(t, err, "once reviewed")
+
+	tuesday, err := service.OfferShift(company.ID, users[offerer].ID, dtos.ShiftOfferRequest{Date: dtos.Date{Time: monday.AddDate(0, 0, 1)}})
+	require.NoError(t, err)
+	cancelled, err := service.CancelOffer(tuesday.ID, company.ID, users[offerer].ID)
+	require.NoError(t, err)
+	assert.Equal(t, models.RosterSwapCancelled, cancelled.Status)
+}
+
+func TestRoster_AbsenceCoverageRulesAndBlackouts(t *testing.T) {
+	db := setupPayrollTestDB(t)
+	require.NoError(t, db.AutoMigrate(
+		&models.User{}, &models.AbsenceRequest{}, &models.IncidenceType{}, &models.Incidence{}, &models.SickLeaveCertificate{}, &models.Department{},
+		&models.Shift{}, &models.EmployeeShiftBase{}, &models.ShiftException{},
+		&models.RotationPattern{}, &models.RotationPatternDay{}, &models.RotationAssignment{},
+		&models.StaffingRule{}, &models.BlackoutPeriod{}, &models.TimePolicy{},
+	))
+	company := createPayrollTestCompany(t, db)
+	service := NewRosterService(db)
+
+	assembly := &models.Department{CompanyID: company.ID, Code: "ENS", Name: "Ensamble final"}
+	require.NoError(t, db.Create(assembly).Error)
+	morning := &models.Shift{Name: "Matutino", Code: "MAT", StartTime: "06:00", EndTime: "14:00", WorkHoursPerDay: 8,
+		CompanyID: company.ID, IsActive: true}
+	require.NoError(t, db.Create(morning).Error)
+
+	// Four operators of line 3 working mornings every day
+	var operators []*models.Employee
+	for n := 1; n <= 4; n++ {
+		e := createFixtureEmployee(t, db, company.ID, n, "general", 400)
+		require.NoError(t, db.Model(e).Updates(map[string]interface{}{
+			"team_name": "L3", "production_area": "Línea 3", "department_id": assembly.ID,
+		}).Error)
+		e.TeamName, e.ProductionArea, e.DepartmentID = "L3", "Línea 3", &assembly.ID
+		operators = append(operators, e)
+	}
+	hr := createFixtureUser(t, db, createFixtureEmployee(t, db, company.ID, 5, "general", 400), "hr")
+	monday := policyWeekStart(attendanceDate(time.Now())).AddDate(0, 0, 7)
+	daily, err := service.CreatePattern(company.ID, dtos.RotationPatternRequest{
+		Name: "Diario", Code: "D", AnchorDate: dtos.Date{Time: monday}, Days: []dtos.RotationDayInput{{ShiftID: &morning.ID}},
+	}, hr.ID)
+	require.NoError(t, err)
+	_, err = service.CreateAssignment(company.ID, dtos.RotationAssignmentRequest{
+		PatternID: daily.ID, TeamName: "L3", EffectiveFrom: dtos.Date{Time: monday},
+	}, hr.ID)
+	require.NoError(t, err)
+
+	_, err = service.CreateStaffingRule(company.ID, dtos.StaffingRuleRequest{MinEmployees: 2})
+	assert.ErrorContains(t, err, "production area or department is required")
+	_, err = service.CreateStaffingRule(company.ID, dtos.StaffingRuleRequest{ProductionArea: "Línea 3"})
+	assert.ErrorContains(t, err, "max absent percent is required")
+	_, err = service.CreateStaffingRule(company.ID, dtos.StaffingRuleRequest{ProductionArea: "línea 3", MinEmployees: 3})
+	require.NoError(t, err)
+	percent, err := service.CreateStaffingRule(company.ID, dtos.StaffingRuleRequest{
+		DepartmentID: &assembly.ID, ShiftID: &morning.ID, MaxAbsentPercent: 40, Enforcement: models.CoverageBlock,
+	})
+	require.NoError(t, err)
+	rules, err := service.ListStaffingRules(company.ID)
+	require.NoError(t, err)
+	require.Len(t, rules, 2)
+	assert.Equal(t, models.CoverageWarn, rules[1].Enforcement, "rules warn by default")
+
+	// One operator off still leaves three of four at work
+	check, err := service.CheckAbsenceCoverage(operators[0], models.RequestTypeVacation, monday, monday)
+	require.NoError(t, err)
+	assert.False(t, check.Blocked)
+	assert.Empty(t, check.Issues)
+
+	// With a colleague already on approved vacation both rules break
+	colleague := createFixtureUser(t, db, operators[1], "employee")
+	require.NoError(t, db.Create(&models.AbsenceRequest{EmployeeID: colleague.ID, RequestType: models.RequestTypeVacation,
+		StartDate: monday, EndDate: monday.AddDate(0, 0, 1), TotalDays: 2, Status: models.RequestStatusApproved}).Error)
+	check, err = service.CheckAbsenceCoverage(operators[0], models.RequestTypeVacation, monday, monday.AddDate(0, 0, 2))
+	require.NoError(t, err)
+	assert.True(t, check.Blocked)
+	require.Len(t, check.Issues, 2)
+	assert.Equal(t, percent.ID, *check.Issues[0].StaffingRuleID)
+	assert.Equal(t, float64(50), check.Issues[0].AbsentPercent)
+	assert.Equal(t, models.CoverageBlock, check.Issues[0].Enforcement)
+	assert.Equal(t, dtos.CoverageIssueStaffing, check.Issues[1].Kind)
+	assert.Equal(t, 2, check.Issues[1].Available)
+	assert.Equal(t, 2, check.Issues[1].Days, "Wednesday has three operators again")
+	assert.Equal(t, models.CoverageWarn, check.Issues[1].Enforcement)
+
+	// Sick leave is warned about, never blocked
+	check, err = service.CheckAbsenceCoverage(operators[0], models.RequestTypeSickLeave, monday, monday)
+	require.NoError(t, err)
+	assert.False(t, check.Blocked)
+	assert.Len(t, check.Issues, 2)
+
+	// Percentage-only rules stay out of the roster gaps
+	roster, err := service.GenerateRoster(company.ID, dtos.RosterFilter{StartDate: monday, EndDate: monday})
+	require.NoError(t, err)
+	assert.Empty(t, roster.Gaps)
+
+	// Inventory week closes vacations of line 3 from Thursday
+	_, err = service.CreateBlackout(company.ID, dtos.BlackoutPeriodRequest{
+		Name: "Inventario", StartDate: dtos.Date{Time: monday}, EndDate: dtos.Date{Time: monday}, RequestTypes: []string{"LATE_ENTRY"},
+	}, hr.ID)
+	assert.ErrorContains(t, err, "must be a leave type")
+	inventory, err := service.CreateBlackout(company.ID, dtos.BlackoutPeriodRequest{
+		Name: "Inventario", StartDate: dtos.Date{Time: monday.AddDate(0, 0, 3)}, EndDate: dtos.Date{Time: monday.AddDate(0, 0, 6)},
+		ProductionArea: "Línea 3", RequestTypes: []string{"VACATION"}, Reason: "Conteo físico",
+	}, hr.ID)
+	require.NoError(t, err)
+	assert.Equal(t, models.CoverageBlock, inventory.Enforcement)
+	check, err = service.CheckAbsenceCoverage(operators[2], models.RequestTypeVacation, monday.AddDate(0, 0, 2), monday.AddDate(0, 0, 4))
+	require.NoError(t, err)
+	assert.True(t, check.Blocked)
+	require.Len(t, check.Issues, 1)
+	assert.Equal(t, dtos.CoverageIssueBlackout, check.Issues[0].Kind)
+	assert.Equal(t, 2, check.Issues[0].Days)
+	assert.Contains(t, check.Issues[0].Message, "Conteo físico")
+	check, err = service.CheckAbsenceCoverage(operators[2], models.RequestTypePersonal, monday.AddDate(0, 0, 3), monday.AddDate(0, 0, 3))
+	require.NoError(t, err)
+	assert.Empty(t, check.Issues, "the blackout only closes vacations")
+}
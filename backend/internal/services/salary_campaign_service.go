@@ -0,0 +1,693 @@
+/*
+Package services - Salary Campaign Service
+
+==============================================================================
+FILE: internal/services/salary_campaign_service.go
+==============================================================================
+
+DESCRIPTION:
+    Mass salary increase campaigns (annual increases, union contract
+    revisions). Selects the population, computes every new salary, routes the
+    campaign for approval and applies it on its effective date. Past effective
+    dates generate the retroactive difference (retroactivo) for periods that
+    were already paid, captured as an approved incidence in the next open
+    period of each employee.
+
+USER PERSPECTIVE:
+    - HR builds the campaign and reviews the per-employee preview
+    - A different user approves it; future dates stay "scheduled"
+    - Retroactive pay shows up in the next payroll as "Retroactivo salarial"
+
+DEVELOPER GUIDELINES:
+    ✅  OK to modify: Adjustment rules, population filters
+    ⚠️  CAUTION: Applying writes SalaryHistory and IMSS salary modifications
+    ❌  DO NOT modify: Items after the campaign is applied
+    📝  ApplyDueCampaigns runs in the scheduled_changes job (leader only); safe to repeat
+
+SYNTAX EXPLANATION:
+    - Settled periods (approved, paid, closed) are the ones that get retroactive
+      pay; "calculated" periods are simply recalculated with the new salary
+    - Retroactive per period = (new - old) * paid days * share of the period
+      on or after the effective date; paid days come from RegularSalary / old
+    - Employees whose salary changed after approval are skipped, not applied
+
+==============================================================================
+*/
+package services
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"backend/internal/config"
+	"backend/internal/dtos"
+	"backend/internal/models"
+)
+
+// retroIncidenceTypeName is the incidence type used to pay retroactive salary differences
+const retroIncidenceTypeName = "Retroactivo salarial"
+
+// settledPeriodStatuses are periods whose payroll was already paid (or about to be)
+var settledPeriodStatuses = []string{"approved", "paid", "closed"}
+
+// SalaryCampaignService manages salary review campaigns
+type SalaryCampaignService struct {
+	db           *gorm.DB
+	minimumWage  *MinimumWageService
+	organization *OrganizationService
+}
+
+// NewSalaryCampaignService creates a new SalaryCampaignService
+func NewSalaryCampaignService(db *gorm.DB, appConfig *config.AppConfig) *SalaryCampaignService {
+	return &SalaryCampaignService{
+		db:           db,
+		minimumWage:  NewMinimumWageService(db, appConfig),
+		organization: NewOrganizationService(db),
+	}
+}
+
+// CreateCampaign creates a draft campaign and computes its items
+func (s *SalaryCampaignService) CreateCampaign(companyID, createdBy uuid.UUID, req dtos.SalaryCampaignRequest) (*dtos.SalaryCampaignResponse, error) {
+	campaign := &models.SalaryCampaign{
+		CompanyID: companyID,
+		Status:    models.SalaryCampaignDraft,
+		CreatedBy: &createdBy,
+	}
+	if err := applyCampaignRequest(campaign, req); err != nil {
+		return nil, err
+	}
+
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(campaign).Error; err != nil {
+			return fmt.Errorf("could not create campaign: %w", err)
+		}
+		return s.rebuildItems(tx, campaign)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return s.GetCampaign(campaign.ID, companyID)
+}
+
+// UpdateCampaign changes a draft (or rejected) campaign and recomputes its items
+func (s *SalaryCampaignService) UpdateCampaign(id, companyID uuid.UUID, req dtos.SalaryCampaignRequest) (*dtos.SalaryCampaignResponse, error) {
+	campaign, err := s.findCampaign(id, companyID)
+	if err != nil {
+		return nil, err
+	}
+	if !campaign.IsEditable() {
+		return nil, fmt.Errorf("campaign in status %s cannot be modified", campaign.Status)
+	}
+	if err := applyCampaignRequest(campaign, req); err != nil {
+		return nil, err
+	}
+	campaign.Status = models.SalaryCampaignDraft
+	campaign.RejectionReason = ""
+
+	err = s.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Save(campaign).Error; err != nil {
+			return fmt.Errorf("could not update campaign: %w", err)
+		}
+		return s.rebuildItems(tx, campaign)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return s.GetCampaign(campaign.ID, companyID)
+}
+
+// GetCampaign returns a campaign with its items
+func (s *SalaryCampaignService) GetCampaign(id, companyID uuid.UUID) (*dtos.SalaryCampaignResponse, error) {
+	var campaign models.SalaryCampaign
+	err := s.db.Preload("Items.Employee").
+		Where("id = ? AND company_id = ?", id, companyID).
+		First(&campaign).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("campaign not found")
+		}
+		return nil, err
+	}
+	return toSalaryCampaignResponse(&campaign, true), nil
+}
+
+// ListCampaigns lists the company campaigns, optionally filtered by status
+func (s *SalaryCampaignService) ListCampaigns(companyID uuid.UUID, status string) ([]dtos.SalaryCampaignResponse, error) {
+	query := s.db.Where("company_id = ?", companyID)
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+	var campaigns []models.SalaryCampaign
+	if err := query.Order("effective_date DESC, created_at DESC").Find(&campaigns).Error; err != nil {
+		return nil, err
+	}
+	responses := make([]dtos.SalaryCampaignResponse, 0, len(campaigns))
+	for i := range campaigns {
+		responses = append(responses, *toSalaryCampaignResponse(&campaigns[i], false))
+	}
+	return responses, nil
+}
+
+// SubmitCampaign routes a draft campaign for approval
+func (s *SalaryCampaignService) SubmitCampaign(id, companyID, userID uuid.UUID) (*dtos.SalaryCampaignResponse, error) {
+	campaign, err := s.findCampaign(id, companyID)
+	if err != nil {
+		return nil, err
+	}
+	if !campaign.IsEditable() {
+		return nil, fmt.Errorf("campaign in status %s cannot be submitted", campaign.Status)
+	}
+	if campaign.EmployeeCount == 0 {
+		return nil, errors.New("campaign has no employees")
+	}
+
+	now := time.Now()
+	campaign.Status = models.SalaryCampaignPendingApproval
+	campaign.SubmittedBy = &userID
+	campaign.SubmittedAt = &now
+	campaign.RejectionReason = ""
+	if err := s.db.Save(campaign).Error; err != nil {
+		return nil, err
+	}
+	return s.GetCampaign(id, companyID)
+}
+
+// ApproveCampaign approves a pending campaign. It is applied immediately when the
+// effective date has already arrived, otherwise it stays scheduled.
+func (s *SalaryCampaignService) ApproveCampaign(id, companyID, userID uuid.UUID) (*dtos.SalaryCampaignResponse, error) {
+	campaign, err := s.findCampaign(id, companyID)
+	if err != nil {
+		return nil, err
+	}
+	if campaign.Status != models.SalaryCampaignPendingApproval {
+		return nil, fmt.Errorf("campaign in status %s cannot be approved", campaign.Status)
+	}
+	if campaign.SubmittedBy != nil && *campaign.SubmittedBy == userID {
+		return nil, errors.New("campaign must be approved by a different user than the one who submitted it")
+	}
+
+	now := time.Now()
+	campaign.Status = models.SalaryCampaignScheduled
+	campaign.ApprovedBy = &userID
+	campaign.ApprovedAt = &now
+
+	err = s.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Save(campaign).Error; err != nil {
+			return err
+		}
+		if !campaign.EffectiveDate.After(now) {
+			return s.applyCampaign(tx, campaign, &userID)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return s.GetCampaign(id, companyID)
+}
+
+// RejectCampaign sends a pending campaign back to HR
+func (s *SalaryCampaignService) RejectCampaign(id, companyID, userID uuid.UUID, reason string) (*dtos.SalaryCampaignResponse, error) {
+	campaign, err := s.findCampaign(id, companyID)
+	if err != nil {
+		return nil, err
+	}
+	if campaign.Status != models.SalaryCampaignPendingApproval {
+		return nil, fmt.Errorf("campaign in status %s cannot be rejected", campaign.Status)
+	}
+	campaign.Status = models.SalaryCampaignRejected
+	campaign.RejectionReason = strings.TrimSpace(reason)
+	campaign.ApprovedBy = &userID
+	if err := s.db.Save(campaign).Error; err != nil {
+		return nil, err
+	}
+	return s.GetCampaign(id, companyID)
+}
+
+// CancelCampaign cancels a campaign that has not been applied
+func (s *SalaryCampaignService) CancelCampaign(id, companyID uuid.UUID) error {
+	campaign, err := s.findCampaign(id, companyID)
+	if err != nil {
+		return err
+	}
+	if campaign.Status == models.SalaryCampaignApplied || campaign.Status == models.SalaryCampaignCancelled {
+		return fmt.Errorf("campaign in status %s cannot be cancelled", campaign.Status)
+	}
+	campaign.Status = models.SalaryCampaignCancelled
+	return s.db.Save(campaign).Error
+}
+
+// ApplyDueCampaigns applies every scheduled campaign whose effective date has arrived
+func (s *SalaryCampaignService) ApplyDueCampaigns() (int, error) {
+	var campaigns []models.SalaryCampaign
+	if err := s.db.Where("status = ? AND effective_date <= ?", models.SalaryCampaignScheduled, time.Now()).
+		Find(&campaigns).Error; err != nil {
+		return 0, err
+	}
+
+	applied := 0
+	for i := range campaigns {
+		campaign := &campaigns[i]
+		err := s.db.Transaction(func(tx *gorm.DB) error {
+			return s.applyCampaign(tx, campaign, campaign.ApprovedBy)
+		})
+		if err != nil {
+			return applied, fmt.Errorf("could not apply campaign %s: %w", campaign.Name, err)
+		}
+		applied++
+	}
+	return applied, nil
+}
+
+// applyCampaign updates salaries, writes the audit trail and creates retroactive incidences
+func (s *SalaryCampaignService) applyCampaign(tx *gorm.DB, campaign *models.SalaryCampaign, appliedBy *uuid.UUID) error {
+	var items []models.SalaryCampaignItem
+	if err := tx.Where("campaign_id = ? AND status = ?", campaign.ID, "pending").Find(&items).Error; err != nil {
+		return err
+	}
+
+	var retroType *models.IncidenceType
+	totalRetro := 0.0
+	reason := fmt.Sprintf("Campaña salarial: %s", campaign.Name)
+
+	for i := range items {
+		item := &items[i]
+		var employee models.Employee
+		if err := tx.First(&employee, "id = ?", item.EmployeeID).Error; err != nil {
+			item.Status = "skipped"
+			item.SkipReason = "employee not found"
+			if err := tx.Save(item).Error; err != nil {
+				return err
+			}
+			continue
+		}
+		if roundCurrency(employee.DailySalary) != roundCurrency(item.OldDailySalary) {
+			item.Status = "skipped"
+			item.SkipReason = fmt.Sprintf("salary changed after approval (%.2f)", employee.DailySalary)
+			if err := tx.Save(item).Error; err != nil {
+				return err
+			}
+			continue
+		}
+		if err := s.minimumWage.ValidateDailySalary(employee.MinimumWageZone, employee.ProfessionalOccupation, item.NewDailySalary); err != nil {
+			item.Status = "skipped"
+			item.SkipReason = err.Error()
+			if err := tx.Save(item).Error; err != nil {
+				return err
+			}
+			continue
+		}
+		if _, err := s.organization.CheckPayBand(&employee, item.NewDailySalary); err != nil {
+			item.Status = "skipped"
+			item.SkipReason = err.Error()
+			if err := tx.Save(item).Error; err != nil {
+				return err
+			}
+			continue
+		}
+
+		oldSDI := employee.IntegratedDailySalary
+		employee.DailySalary = item.NewDailySalary
+		employee.IntegratedDailySalary = 0
+		employee.IntegratedDailySalary = employee.CalculateIntegratedDailySalary()
+		employee.UpdatedBy = appliedBy
+		if err := tx.Save(&employee).Error; err != nil {
+			return fmt.Errorf("could not update employee %s: %w", employee.EmployeeNumber, err)
+		}
+
+		history, _, err := recordSalaryChange(tx, &employee, item.OldDailySalary, oldSDI, campaign.EffectiveDate, reason, appliedBy)
+		if err != nil {
+			return err
+		}
+		item.SalaryHistoryID = &history.ID
+
+		// Retroactive difference for periods already paid with the old salary
+		retro, periods, err := s.retroactiveDifference(tx, &employee, item.OldDailySalary, item.NewDailySalary, campaign.EffectiveDate)
+		if err != nil {
+			return err
+		}
+		item.RetroactiveAmount = retro
+		item.RetroactivePeriods = periods
+		if retro > 0 {
+			if retroType == nil {
+				if retroType, err = findOrCreateRetroIncidenceType(tx); err != nil {
+					return err
+				}
+			}
+			incidence, err := createRetroIncidence(tx, &employee, retroType, retro, periods, campaign, appliedBy)
+			if err != nil {
+				return err
+			}
+			if incidence != nil {
+				item.RetroIncidenceID = &incidence.ID
+			}
+			totalRetro += retro
+		}
+
+		item.Status = "applied"
+		if err := tx.Save(item).Error; err != nil {
+			return err
+		}
+	}
+
+	now := time.Now()
+	campaign.Status = models.SalaryCampaignApplied
+	campaign.AppliedAt = &now
+	campaign.TotalRetroactiveAmount = roundCurrency(totalRetro)
+	return tx.Save(campaign).Error
+}
+
+// retroactiveDifference sums the salary difference owed for settled periods on or after the effective date
+func (s *SalaryCampaignService) retroactiveDifference(tx *gorm.DB, employee *models.Employee, oldSalary, newSalary float64, effectiveDate time.Time) (float64, int, error) {
+	if newSalary <= oldSalary || oldSalary <= 0 {
+		return 0, 0, nil
+	}
+
+	var periods []models.PayrollPeriod
+	if err := tx.Where("frequency = ? AND status IN ? AND end_date >= ?", employee.PayFrequency, settledPeriodStatuses, effectiveDate).
+		Order("start_date").
+		Find(&periods).Error; err != nil {
+		return 0, 0, err
+	}
+
+	total := 0.0
+	count := 0
+	for _, period := range periods {
+		var calc models.PayrollCalculation
+		if err := tx.Where("employee_id = ? AND payroll_period_id = ?", employee.ID, period.ID).First(&calc).Error; err != nil {
+			continue // not paid in this period
+		}
+		paidDays := calc.RegularSalary / oldSalary
+		share := periodShareFrom(period, effectiveDate)
+		total += (newSalary - oldSalary) * paidDays * share
+		count++
+	}
+	return roundCurrency(total), count, nil
+}
+
+// periodShareFrom returns the fraction of the period's days on or after date
+func periodShareFrom(period models.PayrollPeriod, date time.Time) float64 {
+	totalDays := period.CalculateDays()
+	if totalDays <= 0 {
+		return 0
+	}
+	if !date.After(period.StartDate) {
+		return 1
+	}
+	covered := int(math.Round(period.EndDate.Sub(truncateToDate(date)).Hours()/24)) + 1
+	if covered <= 0 {
+		return 0
+	}
+	return float64(covered) / float64(totalDays)
+}
+
+// findOrCreateRetroIncidenceType returns the "Retroactivo salarial" incidence type
+func findOrCreateRetroIncidenceType(tx *gorm.DB) (*models.IncidenceType, error) {
+	var incType models.IncidenceType
+	err := tx.Where("name = ?", retroIncidenceTypeName).First(&incType).Error
+	if err == nil {
+		return &incType, nil
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+	incType = models.IncidenceType{
+		Name:              retroIncidenceTypeName,
+		Category:          "bonus",
+		EffectType:        "positive",
+		IsCalculated:      false,
+		CalculationMethod: "fixed_amount",
+		Description:       "Diferencia de salario de periodos pagados antes de aplicar un aumento",
+	}
+	if err := tx.Create(&incType).Error; err != nil {
+		return nil, fmt.Errorf("could not create retroactive incidence type: %w", err)
+	}
+	return &incType, nil
+}
+
+// createRetroIncidence captures the retroactive amount in the employee's next open period
+func createRetroIncidence(tx *gorm.DB, employee *models.Employee, incType *models.IncidenceType, amount float64, periods int, campaign *models.SalaryCampaign, approvedBy *uuid.UUID) (*models.Incidence, error) {
+	var period models.PayrollPeriod
+	err := tx.Where("frequency = ? AND status = ? AND end_date >= ?", employee.PayFrequency, "open", truncateToDate(time.Now())).
+		Order("start_date").
+		First(&period).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil // captured later by HR; the amount stays on the campaign item
+		}
+		return nil, err
+	}
+
+	now := time.Now()
+	incidence := &models.Incidence{
+		EmployeeID:       employee.ID,
+		PayrollPeriodID:  period.ID,
+		IncidenceTypeID:  incType.ID,
+		StartDate:        period.StartDate,
+		EndDate:          period.StartDate,
+		Quantity:         float64(periods),
+		CalculatedAmount: amount,
+		Comments:         fmt.Sprintf("Retroactivo de %s desde %s", campaign.Name, campaign.EffectiveDate.Format("02/01/2006")),
+		Status:           "approved",
+		ApprovedBy:       approvedBy,
+		ApprovedAt:       &now,
+	}
+	if err := tx.Create(incidence).Error; err != nil {
+		return nil, fmt.Errorf("could not create retroactive incidence: %w", err)
+	}
+	return incidence, nil
+}
+
+// rebuildItems replaces the campaign items with the current population and salaries
+func (s *SalaryCampaignService) rebuildItems(tx *gorm.DB, campaign *models.SalaryCampaign) error {
+	if err := tx.Where("campaign_id = ?", campaign.ID).Delete(&models.SalaryCampaignItem{}).Error; err != nil {
+		return err
+	}
+
+	employees, err := s.selectPopulation(tx, campaign)
+	if err != nil {
+		return err
+	}
+
+	matrix := campaign.GetGradeMatrix()
+	var gradeCodes map[uuid.UUID]string
+	if campaign.AdjustmentType == models.SalaryAdjustmentGradeMatrix {
+		if gradeCodes, err = positionGradeCodes(tx, campaign.CompanyID); err != nil {
+			return err
+		}
+	}
+	totalIncrease := 0.0
+	count := 0
+	for _, employee := range employees {
+		newSalary := newSalaryFor(campaign, matrix, gradeCodes, &employee)
+		if newSalary <= employee.DailySalary {
+			continue
+		}
+		item := &models.SalaryCampaignItem{
+			CampaignID:      campaign.ID,
+			EmployeeID:      employee.ID,
+			OldDailySalary:  employee.DailySalary,
+			NewDailySalary:  newSalary,
+			IncreasePercent: math.Round((newSalary-employee.DailySalary)/employee.DailySalary*10000) / 100,
+			Status:          "pending",
+		}
+		if err := tx.Create(item).Error; err != nil {
+			return err
+		}
+		totalIncrease += newSalary - employee.DailySalary
+		count++
+	}
+
+	campaign.EmployeeCount = count
+	campaign.TotalDailyIncrease = roundCurrency(totalIncrease)
+	return tx.Model(campaign).Updates(map[string]interface{}{
+		"employee_count":       campaign.EmployeeCount,
+		"total_daily_increase": campaign.TotalDailyIncrease,
+	}).Error
+}
+
+// selectPopulation returns the active employees matching the campaign filters
+func (s *SalaryCampaignService) selectPopulation(tx *gorm.DB, campaign *models.SalaryCampaign) ([]models.Employee, error) {
+	query := tx.Where("company_id = ? AND employment_status IN ?", campaign.CompanyID, []string{"active", "on_leave"})
+
+	if ids := decodeStringList(campaign.FilterDepartmentIDs); len(ids) > 0 {
+		query = query.Where("department_id IN ?", ids)
+	}
+	if ids := decodeStringList(campaign.FilterPositionIDs); len(ids) > 0 {
+		query = query.Where("position_id IN ?", ids)
+	}
+	if collars := decodeStringList(campaign.FilterCollarTypes); len(collars) > 0 {
+		query = query.Where("collar_type IN ?", collars)
+	}
+	if campaign.FilterSindicalizado != nil {
+		query = query.Where("is_sindicalizado = ?", *campaign.FilterSindicalizado)
+	}
+
+	var employees []models.Employee
+	if err := query.Order("employee_number").Find(&employees).Error; err != nil {
+		return nil, fmt.Errorf("error selecting employees: %w", err)
+	}
+	return employees, nil
+}
+
+// newSalaryFor applies the campaign adjustment to an employee's current salary
+func newSalaryFor(campaign *models.SalaryCampaign, matrix map[string]float64, gradeCodes map[uuid.UUID]string, employee *models.Employee) float64 {
+	switch campaign.AdjustmentType {
+	case models.SalaryAdjustmentFixedAmount:
+		return roundCurrency(employee.DailySalary + campaign.AdjustmentValue)
+	case models.SalaryAdjustmentGradeMatrix:
+		pct, ok := 0.0, false
+		if employee.PositionID != nil {
+			pct, ok = matrix[employee.PositionID.String()]
+			if code := gradeCodes[*employee.PositionID]; !ok && code != "" {
+				pct, ok = matrix[code]
+			}
+		}
+		if !ok {
+			pct, ok = matrix[employee.CollarType]
+		}
+		if !ok {
+			pct, ok = matrix["default"]
+		}
+		if !ok {
+			return employee.DailySalary
+		}
+		return roundCurrency(employee.DailySalary * (1 + pct/100))
+	default:
+		return roundCurrency(employee.DailySalary * (1 + campaign.AdjustmentValue/100))
+	}
+}
+
+// positionGradeCodes maps each company position to its salary grade code
+func positionGradeCodes(tx *gorm.DB, companyID uuid.UUID) (map[uuid.UUID]string, error) {
+	var positions []models.Position
+	if err := tx.Preload("SalaryGrade").Where("company_id = ? AND salary_grade_id IS NOT NULL", companyID).Find(&positions).Error; err != nil {
+		return nil, fmt.Errorf("error fetching positions: %w", err)
+	}
+	codes := make(map[uuid.UUID]string, len(positions))
+	for _, p := range positions {
+		if p.SalaryGrade != nil {
+			codes[p.ID] = p.SalaryGrade.Code
+		}
+	}
+	return codes, nil
+}
+
+// applyCampaignRequest copies and validates the request into the campaign
+func applyCampaignRequest(campaign *models.SalaryCampaign, req dtos.SalaryCampaignRequest) error {
+	switch req.AdjustmentType {
+	case models.SalaryAdjustmentPercentage, models.SalaryAdjustmentFixedAmount:
+		if req.AdjustmentValue <= 0 {
+			return errors.New("adjustment value must be positive")
+		}
+	case models.SalaryAdjustmentGradeMatrix:
+		if len(req.GradeMatrix) == 0 {
+			return errors.New("grade matrix is required for grade_matrix adjustments")
+		}
+	default:
+		return fmt.Errorf("invalid adjustment type: %s", req.AdjustmentType)
+	}
+
+	campaign.Name = strings.TrimSpace(req.Name)
+	campaign.Description = strings.TrimSpace(req.Description)
+	campaign.CampaignType = req.CampaignType
+	if campaign.CampaignType == "" {
+		campaign.CampaignType = "annual_increase"
+	}
+	campaign.AdjustmentType = req.AdjustmentType
+	campaign.AdjustmentValue = req.AdjustmentValue
+	campaign.EffectiveDate = req.EffectiveDate.ToTime()
+	campaign.FilterSindicalizado = req.IsSindicalizado
+	campaign.GradeMatrix = mustJSON(req.GradeMatrix)
+	campaign.FilterDepartmentIDs = mustJSON(req.DepartmentIDs)
+	campaign.FilterPositionIDs = mustJSON(req.PositionIDs)
+	campaign.FilterCollarTypes = mustJSON(req.CollarTypes)
+	return nil
+}
+
+// findCampaign loads a campaign of the company
+func (s *SalaryCampaignService) findCampaign(id, companyID uuid.UUID) (*models.SalaryCampaign, error) {
+	var campaign models.SalaryCampaign
+	if err := s.db.Where("id = ? AND company_id = ?", id, companyID).First(&campaign).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("campaign not found")
+		}
+		return nil, err
+	}
+	return &campaign, nil
+}
+
+// toSalaryCampaignResponse converts a campaign (and optionally its items) to a DTO
+func toSalaryCampaignResponse(campaign *models.SalaryCampaign, withItems bool) *dtos.SalaryCampaignResponse {
+	response := &dtos.SalaryCampaignResponse{
+		ID:                     campaign.ID,
+		Name:                   campaign.Name,
+		Description:            campaign.Description,
+		CampaignType:           campaign.CampaignType,
+		AdjustmentType:         campaign.AdjustmentType,
+		AdjustmentValue:        campaign.AdjustmentValue,
+		EffectiveDate:          campaign.EffectiveDate,
+		Status:                 campaign.Status,
+		EmployeeCount:          campaign.EmployeeCount,
+		TotalDailyIncrease:     campaign.TotalDailyIncrease,
+		TotalRetroactiveAmount: campaign.TotalRetroactiveAmount,
+		RejectionReason:        campaign.RejectionReason,
+		SubmittedAt:            campaign.SubmittedAt,
+		ApprovedAt:             campaign.ApprovedAt,
+		AppliedAt:              campaign.AppliedAt,
+		CreatedAt:              campaign.CreatedAt,
+	}
+	if matrix := campaign.GetGradeMatrix(); len(matrix) > 0 {
+		response.GradeMatrix = matrix
+	}
+	if !withItems {
+		return response
+	}
+
+	for _, item := range campaign.Items {
+		itemResponse := dtos.SalaryCampaignItemResponse{
+			ID:                 item.ID,
+			EmployeeID:         item.EmployeeID,
+			OldDailySalary:     item.OldDailySalary,
+			NewDailySalary:     item.NewDailySalary,
+			IncreasePercent:    item.IncreasePercent,
+			RetroactiveAmount:  item.RetroactiveAmount,
+			RetroactivePeriods: item.RetroactivePeriods,
+			RetroIncidenceID:   item.RetroIncidenceID,
+			Status:             item.Status,
+			SkipReason:         item.SkipReason,
+		}
+		if item.Employee != nil {
+			itemResponse.EmployeeNumber = item.Employee.EmployeeNumber
+			itemResponse.EmployeeName = strings.TrimSpace(fmt.Sprintf("%s %s %s", item.Employee.FirstName, item.Employee.LastName, item.Employee.MotherLastName))
+			itemResponse.CollarType = item.Employee.CollarType
+		}
+		response.Items = append(response.Items, itemResponse)
+	}
+	return response
+}
+
+// mustJSON encodes v as JSON; nil and empty values become nil
+func mustJSON(v interface{}) []byte {
+	data, err := json.Marshal(v)
+	if err != nil || string(data) == "null" || string(data) == "[]" || string(data) == "{}" {
+		return nil
+	}
+	return data
+}
+
+// decodeStringList decodes a JSON array of strings (UUIDs are encoded as strings)
+func decodeStringList(data []byte) []string {
+	var values []string
+	if len(data) > 0 {
+		_ = json.Unmarshal(data, &values)
+	}
+	return values
+}
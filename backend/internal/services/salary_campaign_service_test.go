@@ -0,0 +1,82 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"backend/internal/dtos"
+	"backend/internal/models"
+)
+
+func TestSalaryCampaign_RetroactiveIncrease(t *testing.T) {
+	db := setupPayrollTestDB(t)
+	require.NoError(t, db.AutoMigrate(
+		&models.SalaryHistory{}, &models.IMSSMovement{},
+		&models.SalaryCampaign{}, &models.SalaryCampaignItem{},
+		&models.Incidence{}, &models.IncidenceType{},
+	))
+	company := createPayrollTestCompany(t, db)
+	service := NewSalaryCampaignService(db, nil)
+
+	union := createFixtureEmployee(t, db, company.ID, 1, "general", 400.00)
+	db.Model(union).Update("is_sindicalizado", true)
+	createFixtureEmployee(t, db, company.ID, 2, "general", 400.00) // not unionized
+
+	// A paid weekly period after the effective date, and the next open period
+	today := truncateToDate(time.Now())
+	paidStart := today.AddDate(0, 0, -21)
+	paid := &models.PayrollPeriod{
+		PeriodCode: "2025-W40", Year: 2025, PeriodNumber: 40, Frequency: "weekly", PeriodType: "weekly",
+		StartDate: paidStart, EndDate: paidStart.AddDate(0, 0, 6), PaymentDate: paidStart.AddDate(0, 0, 7), Status: "paid",
+	}
+	require.NoError(t, db.Create(paid).Error)
+	open := &models.PayrollPeriod{
+		PeriodCode: "2025-W43", Year: 2025, PeriodNumber: 43, Frequency: "weekly", PeriodType: "weekly",
+		StartDate: today, EndDate: today.AddDate(0, 0, 6), PaymentDate: today.AddDate(0, 0, 7), Status: "open",
+	}
+	require.NoError(t, db.Create(open).Error)
+	calc := &models.PayrollCalculation{EmployeeID: union.ID, PayrollPeriodID: paid.ID, RegularSalary: 400.00 * 7}
+	require.NoError(t, db.Create(calc).Error)
+
+	unionOnly := true
+	created, err := service.CreateCampaign(company.ID, uuid.New(), dtos.SalaryCampaignRequest{
+		Name:            "Revisión contrato colectivo",
+		CampaignType:    "union_revision",
+		AdjustmentType:  "percentage",
+		AdjustmentValue: 5,
+		EffectiveDate:   dtos.Date{Time: paidStart},
+		IsSindicalizado: &unionOnly,
+	})
+	require.NoError(t, err)
+	require.Equal(t, 1, created.EmployeeCount)
+	assert.Equal(t, 420.00, created.Items[0].NewDailySalary)
+
+	submitter, approver := uuid.New(), uuid.New()
+	_, err = service.SubmitCampaign(created.ID, company.ID, submitter)
+	require.NoError(t, err)
+	_, err = service.ApproveCampaign(created.ID, company.ID, submitter)
+	assert.Error(t, err, "submitter cannot approve")
+
+	applied, err := service.ApproveCampaign(created.ID, company.ID, approver)
+	require.NoError(t, err)
+	assert.Equal(t, models.SalaryCampaignApplied, applied.Status)
+
+	// 20 MXN/day * 7 paid days
+	item := applied.Items[0]
+	assert.Equal(t, "applied", item.Status)
+	assert.InDelta(t, 140.00, item.RetroactiveAmount, 0.01)
+	require.NotNil(t, item.RetroIncidenceID)
+
+	var incidence models.Incidence
+	require.NoError(t, db.First(&incidence, "id = ?", *item.RetroIncidenceID).Error)
+	assert.Equal(t, open.ID, incidence.PayrollPeriodID)
+	assert.Equal(t, "approved", incidence.Status)
+
+	var employee models.Employee
+	require.NoError(t, db.First(&employee, "id = ?", union.ID).Error)
+	assert.Equal(t, 420.00, employee.DailySalary)
+}
@@ -0,0 +1,240 @@
+/*
+Package services - IRIS Payroll System Business Logic
+
+==============================================================================
+FILE: internal/services/scheduled_jobs.go
+==============================================================================
+
+DESCRIPTION:
+    Catalog of the periodic jobs run by SchedulerService: approval
+    escalation and SLA reminders, scheduled organization and salary
+    changes, payroll period generation, the vacation ledger runs, the
+    time bank expiry, timesheet generation and reminders, and project
+    budget alerts.
+
+USER PERSPECTIVE:
+    - Approvers are reminded before a request escalates
+    - Anniversary grants, expiries and timesheet reminders happen on time
+      even when nobody opens the corresponding screen
+
+DEVELOPER GUIDELINES:
+    ✅  OK to modify: Add jobs, change intervals
+    ⚠️  CAUTION: Every job must be safe to run twice
+    📝  Per-company jobs keep going when one company fails and report all
+        failures together; notifications they send are attributed to the
+        company's first active admin (or HR) user
+
+==============================================================================
+*/
+package services
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"backend/internal/config"
+	"backend/internal/dtos"
+	"backend/internal/models"
+	"backend/internal/models/enums"
+)
+
+// DefaultScheduledJobs returns the periodic jobs of the API server
+func DefaultScheduledJobs(db *gorm.DB, cfg *config.AppConfig) []ScheduledJob {
+	escalations := NewEscalationService(db)
+	ledger := NewVacationLedgerService(db, cfg)
+	timeBank := NewTimeBankService(db)
+	timesheets := NewTimesheetPeriodService(db)
+	projectCosts := NewProjectCostService(db)
+
+	return []ScheduledJob{
+		{
+			Name:        "approval_escalation",
+			Description: "Escalate absence requests waiting past the SLA of their approval stage",
+			Interval:    time.Hour,
+			Run: func(now time.Time) (string, error) {
+				escalated, err := escalations.EscalateDue(now)
+				return fmt.Sprintf("%d requests escalated", escalated), err
+			},
+		},
+		{
+			Name:        "approval_reminders",
+			Description: "Remind approvers of requests about to reach their SLA",
+			Interval:    time.Hour,
+			Run: func(now time.Time) (string, error) {
+				reminded, err := escalations.SendSLAReminders(now)
+				return fmt.Sprintf("%d requests reminded", reminded), err
+			},
+		},
+		{
+			Name:        "scheduled_changes",
+			Description: "Apply salary campaigns, assignments and reporting lines whose effective date arrived, building missing org hierarchies",
+			Interval:    time.Hour,
+			Run: func(now time.Time) (string, error) {
+				campaigns, err := NewSalaryCampaignService(db, cfg).ApplyDueCampaigns()
+				if err != nil {
+					return "", fmt.Errorf("salary campaigns: %w", err)
+				}
+				assignments, err := NewOrganizationService(db).ApplyDueAssignments()
+				if err != nil {
+					return "", fmt.Errorf("assignments: %w", err)
+				}
+				orgStructure := NewOrgStructureService(db)
+				if _, err := orgStructure.EnsureHierarchies(); err != nil {
+					return "", fmt.Errorf("org hierarchy: %w", err)
+				}
+				lines, err := orgStructure.ApplyDueReportingLines()
+				if err != nil {
+					return "", fmt.Errorf("reporting lines: %w", err)
+				}
+				return fmt.Sprintf("%d campaigns, %d assignments, %d reporting lines applied", campaigns, assignments, lines), nil
+			},
+		},
+		{
+			Name:        "payroll_periods",
+			Description: "Generate the current payroll periods",
+			Interval:    24 * time.Hour,
+			Run: func(now time.Time) (string, error) {
+				periods, err := NewPayrollPeriodService(db).GenerateCurrentPeriods()
+				return fmt.Sprintf("%d periods generated/verified", len(periods)), err
+			},
+		},
+		{
+			Name:        "vacation_ledger",
+			Description: "Post vacation anniversary grants and expire prescribed balances",
+			Interval:    24 * time.Hour,
+			Run: func(now time.Time) (string, error) {
+				total := &dtos.VacationLedgerRunResult{}
+				err := forEachCompany(db, func(company *models.Company) error {
+					grants, err := ledger.PostAnniversaryGrants(company.ID, now)
+					if err != nil {
+						return err
+					}
+					expiries, err := ledger.ExpireBalances(company.ID, now)
+					if err != nil {
+						return err
+					}
+					total.Grants += grants.Grants
+					total.DaysGranted += grants.DaysGranted
+					total.Expiries += expiries.Expiries
+					total.DaysExpired += expiries.DaysExpired
+					return nil
+				})
+				return fmt.Sprintf("%d grants (%.2f days), %d expiries (%.2f days)",
+					total.Grants, total.DaysGranted, total.Expiries, total.DaysExpired), err
+			},
+		},
+		{
+			Name:        "time_bank_expiry",
+			Description: "Expire banked overtime hours past their expiry date",
+			Interval:    24 * time.Hour,
+			Run: func(now time.Time) (string, error) {
+				total := &dtos.TimeBankRunResult{}
+				err := forEachCompany(db, func(company *models.Company) error {
+					result, err := timeBank.ExpireBalances(company.ID, now)
+					if err != nil {
+						return err
+					}
+					total.Employees += result.Employees
+					total.Expiries += result.Expiries
+					total.HoursExpired += result.HoursExpired
+					return nil
+				})
+				return fmt.Sprintf("%d expiries (%.2f hours)", total.Expiries, total.HoursExpired), err
+			},
+		},
+		{
+			Name:        "timesheet_generation",
+			Description: "Create the timesheets of the open payroll periods",
+			Interval:    24 * time.Hour,
+			Run: func(now time.Time) (string, error) {
+				created := 0
+				err := forEachCompany(db, func(company *models.Company) error {
+					result, err := timesheets.GenerateTimesheets(company.ID, dtos.TimesheetGenerateRequest{Date: &dtos.Date{Time: now}})
+					if err != nil {
+						return err
+					}
+					created += result.Created
+					return nil
+				})
+				return fmt.Sprintf("%d timesheets created", created), err
+			},
+		},
+		{
+			Name:        "timesheet_reminders",
+			Description: "Remind employees of draft timesheets about to be due",
+			Interval:    24 * time.Hour,
+			Run: func(now time.Time) (string, error) {
+				reminded := 0
+				err := forEachCompany(db, func(company *models.Company) error {
+					actorID, ok, err := systemActor(db, company.ID)
+					if err != nil || !ok {
+						return err
+					}
+					result, err := timesheets.SendReminders(company.ID, actorID, now)
+					if err != nil {
+						return err
+					}
+					reminded += result.Reminded
+					return nil
+				})
+				return fmt.Sprintf("%d employees reminded", reminded), err
+			},
+		},
+		{
+			Name:        "project_budget_alerts",
+			Description: "Raise project budget thresholds crossed since the last check",
+			Interval:    24 * time.Hour,
+			Run: func(now time.Time) (string, error) {
+				raised := 0
+				err := forEachCompany(db, func(company *models.Company) error {
+					actorID, ok, err := systemActor(db, company.ID)
+					if err != nil || !ok {
+						return err
+					}
+					result, err := projectCosts.CheckBudgetAlerts(company.ID, actorID, models.CostBasisRate)
+					if err != nil {
+						return err
+					}
+					raised += result.Raised
+					return nil
+				})
+				return fmt.Sprintf("%d alerts raised", raised), err
+			},
+		},
+	}
+}
+
+// forEachCompany applies fn to every active company and joins their errors
+func forEachCompany(db *gorm.DB, fn func(*models.Company) error) error {
+	var companies []models.Company
+	if err := db.Where("is_active = ?", true).Order("name").Find(&companies).Error; err != nil {
+		return fmt.Errorf("error fetching companies: %w", err)
+	}
+	var errs []error
+	for i := range companies {
+		if err := fn(&companies[i]); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", companies[i].Name, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// systemActor returns the user scheduled notifications of a company are
+// attributed to: its first active admin, else its first active HR user
+func systemActor(db *gorm.DB, companyID uuid.UUID) (uuid.UUID, bool, error) {
+	for _, roles := range [][]enums.UserRole{{enums.RoleAdmin}, {enums.RoleHR, enums.RoleHRAndPR}} {
+		var user models.User
+		if err := db.Where("company_id = ? AND role IN ? AND is_active = ?", companyID, roles, true).
+			Order("created_at").Limit(1).Find(&user).Error; err != nil {
+			return uuid.Nil, false, err
+		}
+		if user.ID != uuid.Nil {
+			return user.ID, true, nil
+		}
+	}
+	return uuid.Nil, false, nil
+}
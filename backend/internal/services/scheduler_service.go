@@ -0,0 +1,331 @@
+/*
+Package services - IRIS Payroll System Business Logic
+
+==============================================================================
+FILE: internal/services/scheduler_service.go
+==============================================================================
+
+DESCRIPTION:
+    In-process job scheduler of the API server. Every minute each replica
+    tries to take (or renew) the leadership lease in scheduler_locks; the
+    one holding it runs the registered jobs that are due, recording each
+    execution in job_runs. The job catalog lives in scheduled_jobs.go.
+
+USER PERSPECTIVE:
+    - Escalations, SLA reminders, vacation grants, timesheet reminders and
+      the other periodic tasks happen without anyone pressing a button
+    - Admins see the run history and can run a job on demand
+
+DEVELOPER GUIDELINES:
+    ✅  OK to modify: Tick and lease durations, job catalog
+    ⚠️  CAUTION: Jobs must be idempotent; after a crash the next leader
+        runs whatever was due, possibly a second time
+    ❌  DO NOT modify: The lease conditions without checking two replicas
+        cannot both believe they lead
+    📝  A job is due when its last run (any status or trigger) started
+        at least Interval ago; a job that never ran is due immediately
+
+SYNTAX EXPLANATION:
+    - Leader election: insert the lock row if missing, else take it over
+      only when it is ours or its lease expired (one UPDATE, atomic in
+      PostgreSQL and SQLite)
+    - The lease is renewed before every job, so a long job list never lets
+      it lapse between jobs
+    - Run panics are recovered and recorded as failed runs
+
+==============================================================================
+*/
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"runtime/debug"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"backend/internal/dtos"
+	"backend/internal/models"
+)
+
+const (
+	schedulerLockName = "job_scheduler"
+	schedulerTick     = time.Minute
+	schedulerLease    = 3 * time.Minute
+	// A run still marked running after this long is assumed dead (its
+	// replica crashed) and no longer blocks manual runs
+	staleJobRunAfter = 6 * time.Hour
+)
+
+// ScheduledJob is a periodic task; Run returns a one-line summary of what it did
+type ScheduledJob struct {
+	Name        string
+	Description string
+	Interval    time.Duration
+	Run         func(now time.Time) (string, error)
+}
+
+// SchedulerService elects a leader among API replicas and runs the due jobs
+type SchedulerService struct {
+	db         *gorm.DB
+	instanceID string
+	jobs       []ScheduledJob
+	tick       time.Duration
+	lease      time.Duration
+	running    sync.Mutex // One job at a time per instance
+}
+
+// NewSchedulerService creates a scheduler identified as instanceID among replicas
+func NewSchedulerService(db *gorm.DB, instanceID string) *SchedulerService {
+	return &SchedulerService{db: db, instanceID: instanceID, tick: schedulerTick, lease: schedulerLease}
+}
+
+// Register adds jobs to the scheduler
+func (s *SchedulerService) Register(jobs ...ScheduledJob) {
+	s.jobs = append(s.jobs, jobs...)
+}
+
+// Start ticks until ctx is cancelled, then gives up the leadership
+func (s *SchedulerService) Start(ctx context.Context) {
+	go func() {
+		ticker := time.NewTicker(s.tick)
+		defer ticker.Stop()
+		for {
+			if _, err := s.Tick(time.Now()); err != nil {
+				log.Printf("Scheduler tick failed: %v", err)
+			}
+			select {
+			case <-ctx.Done():
+				s.releaseLeadership()
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+// Tick runs the due jobs if this instance is (or becomes) the leader
+func (s *SchedulerService) Tick(now time.Time) ([]models.JobRun, error) {
+	var runs []models.JobRun
+	started := time.Now()
+	for _, job := range s.jobs {
+		leader, err := s.acquireLeadership(now.Add(time.Since(started)))
+		if err != nil || !leader {
+			return runs, err
+		}
+		due, err := s.isDue(job, now)
+		if err != nil {
+			return runs, err
+		}
+		if !due {
+			continue
+		}
+		run, err := s.execute(job, models.JobTriggerSchedule, nil, now)
+		if err != nil {
+			return runs, err
+		}
+		runs = append(runs, *run)
+	}
+	return runs, nil
+}
+
+// RunJob runs a job now on behalf of an admin, whoever the leader is
+func (s *SchedulerService) RunJob(name string, triggeredBy uuid.UUID) (*models.JobRun, error) {
+	job := s.job(name)
+	if job == nil {
+		return nil, errors.New("job not found")
+	}
+	now := time.Now()
+	var running int64
+	if err := s.db.Model(&models.JobRun{}).
+		Where("job_name = ? AND status = ? AND started_at > ?", name, models.JobRunRunning, now.Add(-staleJobRunAfter)).
+		Count(&running).Error; err != nil {
+		return nil, err
+	}
+	if running > 0 {
+		return nil, errors.New("job is already running and cannot be started again")
+	}
+	return s.execute(*job, models.JobTriggerManual, &triggeredBy, now)
+}
+
+// Status lists the registered jobs with their last run and the current leader
+func (s *SchedulerService) Status() (*dtos.SchedulerStatus, error) {
+	status := &dtos.SchedulerStatus{Jobs: make([]dtos.ScheduledJobStatus, 0, len(s.jobs))}
+	var lock models.SchedulerLock
+	if err := s.db.Where("name = ?", schedulerLockName).Limit(1).Find(&lock).Error; err != nil {
+		return nil, err
+	}
+	if lock.ID != uuid.Nil && lock.LeaseExpiresAt.After(time.Now()) {
+		status.Leader = lock.Holder
+		status.LeaseExpiresAt = &lock.LeaseExpiresAt
+	}
+
+	for _, job := range s.jobs {
+		item := dtos.ScheduledJobStatus{
+			Name:            job.Name,
+			Description:     job.Description,
+			IntervalMinutes: int(job.Interval / time.Minute),
+			NextRunAt:       time.Now(),
+		}
+		last, err := s.lastRun(job.Name)
+		if err != nil {
+			return nil, err
+		}
+		if last != nil {
+			item.LastRun = last
+			if next := last.StartedAt.Add(job.Interval); next.After(item.NextRunAt) {
+				item.NextRunAt = next
+			}
+		}
+		status.Jobs = append(status.Jobs, item)
+	}
+	return status, nil
+}
+
+// ListRuns returns the latest runs, optionally of one job
+func (s *SchedulerService) ListRuns(jobName string, limit int) ([]models.JobRun, error) {
+	if limit <= 0 || limit > 500 {
+		limit = 100
+	}
+	query := s.db.Order("started_at DESC").Limit(limit)
+	if jobName != "" {
+		query = query.Where("job_name = ?", jobName)
+	}
+	var runs []models.JobRun
+	if err := query.Find(&runs).Error; err != nil {
+		return nil, fmt.Errorf("error fetching job runs: %w", err)
+	}
+	return runs, nil
+}
+
+// execute runs a job and records its outcome
+func (s *SchedulerService) execute(job ScheduledJob, trigger string, triggeredBy *uuid.UUID, now time.Time) (*models.JobRun, error) {
+	s.running.Lock()
+	defer s.running.Unlock()
+
+	run := &models.JobRun{
+		JobName:     job.Name,
+		Trigger:     trigger,
+		Instance:    s.instanceID,
+		StartedAt:   now,
+		Status:      models.JobRunRunning,
+		TriggeredBy: triggeredBy,
+	}
+	if err := s.db.Create(run).Error; err != nil {
+		return nil, fmt.Errorf("error recording job run: %w", err)
+	}
+
+	started := time.Now()
+	summary, err := s.safeRun(job, now)
+	elapsed := time.Since(started)
+	finished := now.Add(elapsed)
+	run.FinishedAt = &finished
+	run.DurationMs = elapsed.Milliseconds()
+	run.Summary = summary
+	run.Status = models.JobRunSucceeded
+	if err != nil {
+		run.Status = models.JobRunFailed
+		run.Error = err.Error()
+		log.Printf("Job %s failed: %v", job.Name, err)
+	}
+	if err := s.db.Save(run).Error; err != nil {
+		return nil, fmt.Errorf("error recording job run: %w", err)
+	}
+	return run, nil
+}
+
+// safeRun runs a job, turning a panic into an error
+func (s *SchedulerService) safeRun(job ScheduledJob, now time.Time) (summary string, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			log.Printf("Job %s panicked: %v\n%s", job.Name, r, debug.Stack())
+			err = fmt.Errorf("panic: %v", r)
+		}
+	}()
+	return job.Run(now)
+}
+
+// isDue reports whether the job's last run started at least Interval ago
+func (s *SchedulerService) isDue(job ScheduledJob, now time.Time) (bool, error) {
+	last, err := s.lastRun(job.Name)
+	if err != nil {
+		return false, err
+	}
+	return last == nil || !now.Before(last.StartedAt.Add(job.Interval)), nil
+}
+
+// lastRun returns the most recent run of a job, nil when it never ran
+func (s *SchedulerService) lastRun(name string) (*models.JobRun, error) {
+	var run models.JobRun
+	if err := s.db.Where("job_name = ?", name).Order("started_at DESC").Limit(1).Find(&run).Error; err != nil {
+		return nil, err
+	}
+	if run.ID == uuid.Nil {
+		return nil, nil
+	}
+	return &run, nil
+}
+
+// job returns a registered job by name
+func (s *SchedulerService) job(name string) *ScheduledJob {
+	for i := range s.jobs {
+		if s.jobs[i].Name == name {
+			return &s.jobs[i]
+		}
+	}
+	return nil
+}
+
+// acquireLeadership creates, renews or takes over the lease; true when this
+// instance leads until now + lease
+func (s *SchedulerService) acquireLeadership(now time.Time) (bool, error) {
+	lock := &models.SchedulerLock{
+		Name:           schedulerLockName,
+		Holder:         s.instanceID,
+		AcquiredAt:     now,
+		LeaseExpiresAt: now.Add(s.lease),
+	}
+	created := s.db.Clauses(clause.OnConflict{Columns: []clause.Column{{Name: "name"}}, DoNothing: true}).Create(lock)
+	if created.Error != nil {
+		return false, fmt.Errorf("error acquiring scheduler lock: %w", created.Error)
+	}
+	if created.RowsAffected == 1 {
+		return true, nil
+	}
+
+	renewed := s.db.Model(&models.SchedulerLock{}).
+		Where("name = ? AND holder = ?", schedulerLockName, s.instanceID).
+		Update("lease_expires_at", now.Add(s.lease))
+	if renewed.Error != nil {
+		return false, fmt.Errorf("error renewing scheduler lock: %w", renewed.Error)
+	}
+	if renewed.RowsAffected == 1 {
+		return true, nil
+	}
+
+	takenOver := s.db.Model(&models.SchedulerLock{}).
+		Where("name = ? AND lease_expires_at < ?", schedulerLockName, now).
+		Updates(map[string]interface{}{"holder": s.instanceID, "acquired_at": now, "lease_expires_at": now.Add(s.lease)})
+	if takenOver.Error != nil {
+		return false, fmt.Errorf("error taking over scheduler lock: %w", takenOver.Error)
+	}
+	if takenOver.RowsAffected == 1 {
+		log.Printf("Scheduler leadership acquired by %s", s.instanceID)
+	}
+	return takenOver.RowsAffected == 1, nil
+}
+
+// releaseLeadership expires our lease so another replica takes over at once
+func (s *SchedulerService) releaseLeadership() {
+	if err := s.db.Model(&models.SchedulerLock{}).
+		Where("name = ? AND holder = ?", schedulerLockName, s.instanceID).
+		Update("lease_expires_at", time.Now().Add(-time.Second)).Error; err != nil {
+		log.Printf("Scheduler could not release the leadership of %s: %v", s.instanceID, err)
+	}
+}
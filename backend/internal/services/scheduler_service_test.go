@@ -0,0 +1,87 @@
+package services
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"backend/internal/models"
+)
+
+func TestScheduler_LeaderElectionDueJobsAndHistory(t *testing.T) {
+	db := setupTestDB(t)
+	require.NoError(t, db.AutoMigrate(&models.SchedulerLock{}, &models.JobRun{}))
+
+	calls := map[string]int{}
+	jobs := []ScheduledJob{
+		{Name: "hourly", Interval: time.Hour, Run: func(now time.Time) (string, error) {
+			calls["hourly"]++
+			return "done", nil
+		}},
+		{Name: "broken", Interval: 24 * time.Hour, Run: func(now time.Time) (string, error) {
+			calls["broken"]++
+			if calls["broken"] == 1 {
+				panic("boom")
+			}
+			return "", errors.New("still failing")
+		}},
+	}
+	first := NewSchedulerService(db, "replica-a")
+	first.Register(jobs...)
+	second := NewSchedulerService(db, "replica-b")
+	second.Register(jobs...)
+
+	// Only the leader runs the jobs; a panic is recorded as a failed run
+	now := time.Now()
+	runs, err := first.Tick(now)
+	require.NoError(t, err)
+	require.Len(t, runs, 2)
+	assert.Equal(t, models.JobRunSucceeded, runs[0].Status)
+	assert.Equal(t, "done", runs[0].Summary)
+	assert.Equal(t, models.JobRunFailed, runs[1].Status)
+	assert.Equal(t, "panic: boom", runs[1].Error)
+
+	runs, err = second.Tick(now.Add(time.Minute))
+	require.NoError(t, err)
+	assert.Empty(t, runs)
+	assert.Equal(t, 1, calls["hourly"])
+
+	// Jobs run again once their interval has passed
+	runs, err = first.Tick(now.Add(30 * time.Minute))
+	require.NoError(t, err)
+	assert.Empty(t, runs)
+	runs, err = first.Tick(now.Add(61 * time.Minute))
+	require.NoError(t, err)
+	require.Len(t, runs, 1)
+	assert.Equal(t, "hourly", runs[0].JobName)
+
+	// A stopped leader hands over once its lease expires
+	runs, err = second.Tick(now.Add(3 * time.Hour))
+	require.NoError(t, err)
+	require.Len(t, runs, 1)
+	assert.Equal(t, "replica-b", runs[0].Instance)
+	status, err := second.Status()
+	require.NoError(t, err)
+	require.Len(t, status.Jobs, 2)
+	assert.Equal(t, "hourly", status.Jobs[0].Name)
+	assert.Equal(t, "replica-b", status.Jobs[0].LastRun.Instance)
+
+	// Manual runs are recorded with the admin who triggered them
+	adminID := uuid.New()
+	run, err := first.RunJob("broken", adminID)
+	require.NoError(t, err)
+	assert.Equal(t, models.JobTriggerManual, run.Trigger)
+	assert.Equal(t, "still failing", run.Error)
+	assert.Equal(t, adminID, *run.TriggeredBy)
+	_, err = first.RunJob("missing", adminID)
+	assert.ErrorContains(t, err, "not found")
+
+	history, err := first.ListRuns("broken", 0)
+	require.NoError(t, err)
+	require.Len(t, history, 2)
+	assert.Equal(t, models.JobTriggerManual, history[0].Trigger)
+}
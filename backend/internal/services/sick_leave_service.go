@@ -0,0 +1,555 @@
+/*
+Package services - Sick Leave (Incapacidades) Service
+
+==============================================================================
+FILE: internal/services/sick_leave_service.go
+==============================================================================
+
+DESCRIPTION:
+    Registers IMSS incapacidad certificates and splits their days between
+    the ones the company pays and the ones the IMSS subsidizes. The
+    prenómina, the IMSS contribution days of the payroll and the CFDI
+    Incapacidades node read the days of a period from here, so an
+    incapacidad is captured once and never as a plain sick incidence.
+
+USER PERSPECTIVE:
+    - HR captures folio, branch, type and days and attaches the scan
+    - A subsequent certificate closes the one it continues
+    - HR sees the open incapacidades and the days per employee in a range
+    - The payslip shows the incapacidad days and the salary discounted
+
+DEVELOPER GUIDELINES:
+    ✅  OK to modify: Subsidy rates, report columns
+    ⚠️  CAUTION: splitSickLeaveDays decides what the company pays; the
+        waiting days (LSS Art. 96) only apply to the initial certificate
+        of general illness
+    📝  Incidences created from an absence request linked to a certificate
+        are skipped by the prenómina so the days are not counted twice
+
+SYNTAX EXPLANATION:
+    - EndDate = StartDate + DaysAuthorized - 1
+    - ClosedOn: alta, first day back at work; days from it on are not
+      incapacidad
+    - Company-paid days are the first days of the certificate, the rest are
+      subsidized (60% of the SBC for general illness, 100% otherwise)
+    - periodSickLeave clips every certificate to a date range
+
+==============================================================================
+*/
+package services
+
+import (
+	"errors"
+	"fmt"
+	"mime/multipart"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"backend/internal/dtos"
+	"backend/internal/models"
+)
+
+// Incapacidad subsidy rules (LSS Art. 58, 96 and 101)
+const (
+	sickLeaveWaitingDays        = 3    // General illness days paid before the subsidy starts
+	sickLeaveGeneralIllnessRate = 0.60 // Share of the SBC subsidized for general illness
+	sickLeaveFullSubsidyRate    = 1.00 // Work risk and maternity
+)
+
+// SickLeaveService manages IMSS incapacidad certificates
+type SickLeaveService struct {
+	db *gorm.DB
+}
+
+// NewSickLeaveService creates a new sick leave service
+func NewSickLeaveService(db *gorm.DB) *SickLeaveService {
+	return &SickLeaveService{db: db}
+}
+
+// splitSickLeaveDays returns the company-paid days, the subsidized days and
+// the subsidy rate of a certificate
+func splitSickLeaveDays(branch, certificateType string, days int) (int, int, float64) {
+	if branch != models.SickLeaveBranchGeneralIllness {
+		return 0, days, sickLeaveFullSubsidyRate
+	}
+	companyPaid := 0
+	if certificateType == models.SickLeaveTypeInitial {
+		companyPaid = sickLeaveWaitingDays
+		if companyPaid > days {
+			companyPaid = days
+		}
+	}
+	return companyPaid, days - companyPaid, sickLeaveGeneralIllnessRate
+}
+
+// sickLeaveTakenDays returns the days of a certificate up to its alta
+func sickLeaveTakenDays(cert *models.SickLeaveCertificate) int {
+	days := cert.DaysAuthorized
+	if cert.ClosedOn != nil {
+		if taken := civilDay(*cert.ClosedOn) - civilDay(cert.StartDate); taken < days {
+			days = taken
+		}
+	}
+	if days < 0 {
+		return 0
+	}
+	return days
+}
+
+// applySplit recalculates the company-paid and subsidized days of a certificate
+func applySplit(cert *models.SickLeaveCertificate) {
+	companyPaid, subsidized, rate := splitSickLeaveDays(cert.Branch, cert.Type, sickLeaveTakenDays(cert))
+	cert.CompanyPaidDays = companyPaid
+	cert.SubsidizedDays = subsidized
+	cert.SubsidyRate = rate
+	cert.EstimatedSubsidy = roundTo2(float64(subsidized) * cert.DailyBaseSalary * rate)
+}
+
+// roundTo2 rounds an amount to cents
+func roundTo2(amount float64) float64 {
+	return float64(int64(amount*100+0.5)) / 100
+}
+
+// List returns the company's certificates matching the filter
+func (s *SickLeaveService) List(companyID uuid.UUID, filter dtos.SickLeaveFilter) ([]models.SickLeaveCertificate, error) {
+	query := s.db.Preload("Employee").Where("company_id = ?", companyID)
+	if filter.EmployeeID != nil {
+		query = query.Where("employee_id = ?", *filter.EmployeeID)
+	}
+	if filter.Status != "" {
+		query = query.Where("status = ?", filter.Status)
+	}
+	if filter.Branch != "" {
+		query = query.Where("branch = ?", filter.Branch)
+	}
+	var certificates []models.SickLeaveCertificate
+	if err := query.Order("start_date DESC").Find(&certificates).Error; err != nil {
+		return nil, fmt.Errorf("error fetching incapacidades: %w", err)
+	}
+	return certificates, nil
+}
+
+// ListForUser returns the certificates of the employee linked to a user
+func (s *SickLeaveService) ListForUser(userID uuid.UUID) ([]models.SickLeaveCertificate, error) {
+	var user models.User
+	if err := s.db.First(&user, "id = ?", userID).Error; err != nil {
+		return nil, errors.New("user not found")
+	}
+	if user.EmployeeID == nil {
+		return []models.SickLeaveCertificate{}, nil
+	}
+	var certificates []models.SickLeaveCertificate
+	if err := s.db.Where("employee_id = ? AND status <> ?", *user.EmployeeID, models.SickLeaveCancelled).
+		Order("start_date DESC").Find(&certificates).Error; err != nil {
+		return nil, fmt.Errorf("error fetching incapacidades: %w", err)
+	}
+	return certificates, nil
+}
+
+// Get returns a certificate of the company
+func (s *SickLeaveService) Get(companyID, id uuid.UUID) (*models.SickLeaveCertificate, error) {
+	var cert models.SickLeaveCertificate
+	if err := s.db.Preload("Employee").Where("company_id = ?", companyID).First(&cert, "id = ?", id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("incapacidad not found")
+		}
+		return nil, err
+	}
+	return &cert, nil
+}
+
+// Create registers a certificate; a subsequent certificate closes the one it continues
+func (s *SickLeaveService) Create(companyID, userID uuid.UUID, req dtos.SickLeaveCertificateRequest) (*models.SickLeaveCertificate, error) {
+	cert := &models.SickLeaveCertificate{CompanyID: companyID, Status: models.SickLeaveOpen, CreatedBy: &userID}
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		previous, err := s.apply(tx, cert, req)
+		if err != nil {
+			return err
+		}
+		if err := tx.Create(cert).Error; err != nil {
+			return fmt.Errorf("error saving incapacidad: %w", err)
+		}
+		if previous != nil && req.Type == models.SickLeaveTypeSubsequent && previous.Status == models.SickLeaveOpen {
+			return closeSickLeave(tx, previous, cert.StartDate)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return cert, nil
+}
+
+// Update corrects an open certificate
+func (s *SickLeaveService) Update(companyID, id uuid.UUID, req dtos.SickLeaveCertificateRequest) (*models.SickLeaveCertificate, error) {
+	cert, err := s.Get(companyID, id)
+	if err != nil {
+		return nil, err
+	}
+	if cert.Status != models.SickLeaveOpen {
+		return nil, errors.New("closed or cancelled incapacidades cannot be modified")
+	}
+	err = s.db.Transaction(func(tx *gorm.DB) error {
+		if _, err := s.apply(tx, cert, req); err != nil {
+			return err
+		}
+		return tx.Omit("Employee").Save(cert).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return cert, nil
+}
+
+// apply validates a request and copies it onto cert; it returns the
+// certificate a subsequent or relapse continues
+func (s *SickLeaveService) apply(tx *gorm.DB, cert *models.SickLeaveCertificate, req dtos.SickLeaveCertificateRequest) (*models.SickLeaveCertificate, error) {
+	folio := strings.ToUpper(strings.TrimSpace(req.Folio))
+	if folio == "" {
+		return nil, errors.New("folio is required")
+	}
+	var employee models.Employee
+	if err := tx.Where("company_id = ?", cert.CompanyID).First(&employee, "id = ?", req.EmployeeID).Error; err != nil {
+		return nil, errors.New("employee not found")
+	}
+	var duplicates int64
+	if err := tx.Model(&models.SickLeaveCertificate{}).
+		Where("company_id = ? AND folio = ? AND id <> ?", cert.CompanyID, folio, cert.ID).
+		Count(&duplicates).Error; err != nil {
+		return nil, err
+	}
+	if duplicates > 0 {
+		return nil, fmt.Errorf("an incapacidad with folio %s already exists", folio)
+	}
+
+	var previous *models.SickLeaveCertificate
+	if req.Type != models.SickLeaveTypeInitial {
+		if req.PreviousID == nil {
+			return nil, errors.New("previous incapacidad is required for subsequent and relapse certificates")
+		}
+		previous = &models.SickLeaveCertificate{}
+		if err := tx.Where("company_id = ? AND employee_id = ? AND id <> ?", cert.CompanyID, employee.ID, cert.ID).
+			First(previous, "id = ?", *req.PreviousID).Error; err != nil {
+			return nil, errors.New("previous incapacidad not found")
+		}
+		if previous.Branch != req.Branch {
+			return nil, errors.New("previous incapacidad must be of the same branch")
+		}
+		if previous.Status == models.SickLeaveCancelled {
+			return nil, errors.New("previous incapacidad cannot be cancelled")
+		}
+	}
+
+	if req.AbsenceRequestID != nil {
+		var request models.AbsenceRequest
+		// AbsenceRequest.EmployeeID holds the requesting user's ID
+		if err := tx.Joins("JOIN users ON users.id = absence_requests.employee_id").
+			Where("users.employee_id = ? AND absence_requests.request_type = ?", employee.ID, models.RequestTypeSickLeave).
+			First(&request, "absence_requests.id = ?", *req.AbsenceRequestID).Error; err != nil {
+			return nil, errors.New("sick leave request not found")
+		}
+	}
+
+	start := attendanceDate(req.StartDate.Time)
+	if previous != nil && start.Before(previous.StartDate) {
+		return nil, errors.New("start date must not be before the previous incapacidad")
+	}
+	cert.EmployeeID = employee.ID
+	cert.Folio = folio
+	cert.Branch = req.Branch
+	cert.Type = req.Type
+	cert.PreviousID = req.PreviousID
+	if req.Type == models.SickLeaveTypeInitial {
+		cert.PreviousID = nil
+	}
+	cert.AbsenceRequestID = req.AbsenceRequestID
+	cert.StartDate = start
+	cert.EndDate = start.AddDate(0, 0, req.DaysAuthorized-1)
+	cert.DaysAuthorized = req.DaysAuthorized
+	cert.Diagnosis = strings.TrimSpace(req.Diagnosis)
+	cert.Notes = strings.TrimSpace(req.Notes)
+	cert.DailyBaseSalary = employee.IntegratedDailySalary
+	if cert.DailyBaseSalary == 0 {
+		cert.DailyBaseSalary = employee.DailySalary
+	}
+	applySplit(cert)
+	return previous, nil
+}
+
+// closeSickLeave records the alta of a certificate
+func closeSickLeave(tx *gorm.DB, cert *models.SickLeaveCertificate, closedOn time.Time) error {
+	closedOn = attendanceDate(closedOn)
+	cert.Status = models.SickLeaveClosed
+	cert.ClosedOn = &closedOn
+	applySplit(cert)
+	return tx.Model(cert).Updates(map[string]interface{}{
+		"status":            cert.Status,
+		"closed_on":         cert.ClosedOn,
+		"company_paid_days": cert.CompanyPaidDays,
+		"subsidized_days":   cert.SubsidizedDays,
+		"estimated_subsidy": cert.EstimatedSubsidy,
+	}).Error
+}
+
+// Close records the alta of an open certificate
+func (s *SickLeaveService) Close(companyID, id uuid.UUID, req dtos.SickLeaveCloseRequest) (*models.SickLeaveCertificate, error) {
+	cert, err := s.Get(companyID, id)
+	if err != nil {
+		return nil, err
+	}
+	if cert.Status != models.SickLeaveOpen {
+		return nil, errors.New("incapacidad must be open to be closed")
+	}
+	if attendanceDate(req.ClosedOn.Time).Before(cert.StartDate) {
+		return nil, errors.New("alta date must not be before the start date")
+	}
+	if notes := strings.TrimSpace(req.Notes); notes != "" {
+		cert.Notes = strings.TrimSpace(cert.Notes + "\n" + notes)
+		if err := s.db.Model(cert).Update("notes", cert.Notes).Error; err != nil {
+			return nil, err
+		}
+	}
+	if err := closeSickLeave(s.db, cert, req.ClosedOn.Time); err != nil {
+		return nil, err
+	}
+	return cert, nil
+}
+
+// Cancel voids a certificate captured by mistake
+func (s *SickLeaveService) Cancel(companyID, id uuid.UUID) (*models.SickLeaveCertificate, error) {
+	cert, err := s.Get(companyID, id)
+	if err != nil {
+		return nil, err
+	}
+	if cert.Status == models.SickLeaveCancelled {
+		return nil, errors.New("incapacidad cannot be cancelled again")
+	}
+	var continued int64
+	if err := s.db.Model(&models.SickLeaveCertificate{}).
+		Where("previous_id = ? AND status <> ?", cert.ID, models.SickLeaveCancelled).
+		Count(&continued).Error; err != nil {
+		return nil, err
+	}
+	if continued > 0 {
+		return nil, errors.New("incapacidad continued by another certificate cannot be cancelled")
+	}
+	cert.Status = models.SickLeaveCancelled
+	if err := s.db.Model(cert).Update("status", cert.Status).Error; err != nil {
+		return nil, err
+	}
+	return cert, nil
+}
+
+// UploadDocument attaches the scanned certificate, replacing the previous one
+func (s *SickLeaveService) UploadDocument(companyID, id uuid.UUID, fileHeader *multipart.FileHeader) (*models.SickLeaveCertificate, error) {
+	cert, err := s.Get(companyID, id)
+	if err != nil {
+		return nil, err
+	}
+	_, filePath, contentType, err := NewUploadService(s.db).StoreFile("incapacidad_"+cert.ID.String(), fileHeader)
+	if err != nil {
+		return nil, err
+	}
+	oldPath := cert.DocumentPath
+	cert.DocumentName = fileHeader.Filename
+	cert.DocumentPath = filePath
+	cert.DocumentType = contentType
+	if err := s.db.Model(cert).Updates(map[string]interface{}{
+		"document_name": cert.DocumentName,
+		"document_path": cert.DocumentPath,
+		"document_type": cert.DocumentType,
+	}).Error; err != nil {
+		os.Remove(filePath)
+		return nil, err
+	}
+	if oldPath != "" {
+		os.Remove(oldPath)
+	}
+	return cert, nil
+}
+
+// OpenCertificates returns the open incapacidades with the days their chain has run
+func (s *SickLeaveService) OpenCertificates(companyID uuid.UUID) ([]dtos.OpenSickLeave, error) {
+	certificates, err := s.List(companyID, dtos.SickLeaveFilter{Status: models.SickLeaveOpen})
+	if err != nil {
+		return nil, err
+	}
+	open := make([]dtos.OpenSickLeave, 0, len(certificates))
+	for _, cert := range certificates {
+		chainDays := cert.DaysAuthorized
+		previousID := cert.PreviousID
+		// A chain is short; the guard stops a malformed loop
+		for hops := 0; previousID != nil && hops < 100; hops++ {
+			var previous models.SickLeaveCertificate
+			if err := s.db.First(&previous, "id = ?", *previousID).Error; err != nil {
+				break
+			}
+			chainDays += sickLeaveTakenDays(&previous)
+			previousID = previous.PreviousID
+		}
+		open = append(open, dtos.OpenSickLeave{SickLeaveCertificate: cert, ChainDays: chainDays})
+	}
+	return open, nil
+}
+
+// Summary returns the incapacidad days of each employee between from and to
+func (s *SickLeaveService) Summary(companyID uuid.UUID, from, to time.Time) ([]dtos.SickLeaveEmployeeSummary, error) {
+	from, to = attendanceDate(from), attendanceDate(to)
+	if to.Before(from) {
+		return nil, errors.New("end date must not be before start date")
+	}
+	var certificates []models.SickLeaveCertificate
+	if err := s.db.Preload("Employee").
+		Where("company_id = ? AND status <> ? AND start_date <= ? AND end_date >= ?",
+			companyID, models.SickLeaveCancelled, to, from).
+		Find(&certificates).Error; err != nil {
+		return nil, fmt.Errorf("error fetching incapacidades: %w", err)
+	}
+
+	byEmployee := make(map[uuid.UUID]*dtos.SickLeaveEmployeeSummary)
+	for i := range certificates {
+		cert := &certificates[i]
+		summary := byEmployee[cert.EmployeeID]
+		if summary == nil {
+			summary = &dtos.SickLeaveEmployeeSummary{EmployeeID: cert.EmployeeID, DaysByBranch: make(map[string]int)}
+			if cert.Employee != nil {
+				summary.EmployeeNumber = cert.Employee.EmployeeNumber
+				summary.EmployeeName = cert.Employee.FirstName + " " + cert.Employee.LastName
+			}
+			byEmployee[cert.EmployeeID] = summary
+		}
+		companyPaid, subsidized := sickLeaveDaysBetween(cert, from, to)
+		summary.Certificates++
+		if cert.Status == models.SickLeaveOpen {
+			summary.OpenCertificates++
+		}
+		summary.TotalDays += companyPaid + subsidized
+		summary.CompanyPaidDays += companyPaid
+		summary.SubsidizedDays += subsidized
+		summary.DaysByBranch[cert.Branch] += companyPaid + subsidized
+		summary.EstimatedSubsidy = roundTo2(summary.EstimatedSubsidy +
+			float64(subsidized)*cert.DailyBaseSalary*cert.SubsidyRate)
+	}
+
+	summaries := make([]dtos.SickLeaveEmployeeSummary, 0, len(byEmployee))
+	for _, summary := range byEmployee {
+		summaries = append(summaries, *summary)
+	}
+	sort.Slice(summaries, func(i, j int) bool {
+		if summaries[i].TotalDays != summaries[j].TotalDays {
+			return summaries[i].TotalDays > summaries[j].TotalDays
+		}
+		return summaries[i].EmployeeNumber < summaries[j].EmployeeNumber
+	})
+	return summaries, nil
+}
+
+// sickLeaveDaysBetween returns the company-paid and subsidized days of a
+// certificate that fall between from and to
+func sickLeaveDaysBetween(cert *models.SickLeaveCertificate, from, to time.Time) (int, int) {
+	companyPaid, subsidized := 0, 0
+	start := civilDay(cert.StartDate)
+	first, last := civilDay(from)-start, civilDay(to)-start
+	if first < 0 {
+		first = 0
+	}
+	if taken := sickLeaveTakenDays(cert); last >= taken {
+		last = taken - 1
+	}
+	for offset := first; offset <= last; offset++ {
+		if offset < cert.CompanyPaidDays {
+			companyPaid++
+		} else {
+			subsidized++
+		}
+	}
+	return companyPaid, subsidized
+}
+
+// periodSickLeave is the incapacidad of an employee within a date range
+type periodSickLeave struct {
+	CompanyPaidDays  int
+	SubsidizedDays   int
+	DaysBySATType    map[string]int
+	SubsidizedByType map[string]int
+	// AbsenceRequestIDs are the requests documented by a certificate; the
+	// incidences created from them are not counted again
+	AbsenceRequestIDs map[uuid.UUID]bool
+}
+
+// Days returns every incapacidad day of the range
+func (p *periodSickLeave) Days() int {
+	if p == nil {
+		return 0
+	}
+	return p.CompanyPaidDays + p.SubsidizedDays
+}
+
+// Covers reports whether an incidence comes from a request documented by a certificate
+func (p *periodSickLeave) Covers(incidence *models.Incidence) bool {
+	return p != nil && incidence.AbsenceRequestID != nil && p.AbsenceRequestIDs[*incidence.AbsenceRequestID]
+}
+
+// forPeriod returns the incapacidad of an employee between start and end
+func (s *SickLeaveService) forPeriod(employeeID uuid.UUID, start, end time.Time) (*periodSickLeave, error) {
+	result := &periodSickLeave{
+		DaysBySATType:     make(map[string]int),
+		SubsidizedByType:  make(map[string]int),
+		AbsenceRequestIDs: make(map[uuid.UUID]bool),
+	}
+	if s == nil {
+		return result, nil
+	}
+	start, end = attendanceDate(start), attendanceDate(end)
+	var certificates []models.SickLeaveCertificate
+	if err := s.db.Where("employee_id = ? AND status <> ? AND start_date <= ? AND end_date >= ?",
+		employeeID, models.SickLeaveCancelled, end, start).
+		Find(&certificates).Error; err != nil {
+		return nil, fmt.Errorf("error fetching incapacidades: %w", err)
+	}
+	for i := range certificates {
+		cert := &certificates[i]
+		if cert.AbsenceRequestID != nil {
+			result.AbsenceRequestIDs[*cert.AbsenceRequestID] = true
+		}
+		companyPaid, subsidized := sickLeaveDaysBetween(cert, start, end)
+		result.CompanyPaidDays += companyPaid
+		result.SubsidizedDays += subsidized
+		result.DaysBySATType[cert.SATIncapacityType()] += companyPaid + subsidized
+		result.SubsidizedByType[cert.SATIncapacityType()] += subsidized
+	}
+	return result, nil
+}
+
+// PayrollIncapacidades returns the CFDI Incapacidad nodes of an employee's
+// period; ImporteMonetario is the salary not paid for the subsidized days
+func (s *SickLeaveService) PayrollIncapacidades(employee *models.Employee, start, end time.Time) ([]*models.Incapacidad, error) {
+	sickLeave, err := s.forPeriod(employee.ID, start, end)
+	if err != nil {
+		return nil, err
+	}
+	satTypes := make([]string, 0, len(sickLeave.DaysBySATType))
+	for satType, days := range sickLeave.DaysBySATType {
+		if days > 0 {
+			satTypes = append(satTypes, satType)
+		}
+	}
+	sort.Strings(satTypes)
+	incapacidades := make([]*models.Incapacidad, 0, len(satTypes))
+	for _, satType := range satTypes {
+		node := &models.Incapacidad{
+			TipoIncapacidad: satType,
+			DiasIncapacidad: fmt.Sprintf("%d", sickLeave.DaysBySATType[satType]),
+		}
+		if subsidized := sickLeave.SubsidizedByType[satType]; subsidized > 0 {
+			node.ImporteMonetario = fmt.Sprintf("%.2f", float64(subsidized)*employee.DailySalary)
+		}
+		incapacidades = append(incapacidades, node)
+	}
+	return incapacidades, nil
+}
@@ -0,0 +1,124 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"backend/internal/dtos"
+	"backend/internal/models"
+)
+
+func TestSickLeave_SplitChainPeriodsAndSummary(t *testing.T) {
+	db := setupPayrollTestDB(t)
+	require.NoError(t, db.AutoMigrate(&models.User{}, &models.AbsenceRequest{}, &models.SickLeaveCertificate{}))
+	company := createPayrollTestCompany(t, db)
+	service := NewSickLeaveService(db)
+	employee := createFixtureEmployee(t, db, company.ID, 1, "general", 400)
+	other := createFixtureEmployee(t, db, company.ID, 2, "general", 500)
+	hrID := createFixtureUser(t, db, createFixtureEmployee(t, db, company.ID, 3, "general", 400), "hr").ID
+	// The subsidy is paid on the SBC
+	sbc, otherSBC := employee.IntegratedDailySalary, other.IntegratedDailySalary
+	require.Greater(t, sbc, 400.0)
+	day := func(m time.Month, d int) time.Time { return time.Date(2025, m, d, 0, 0, 0, 0, time.Local) }
+	certificate := func(emp *models.Employee, folio, branch, certType string, start time.Time, days int, previous *models.SickLeaveCertificate) dtos.SickLeaveCertificateRequest {
+		req := dtos.SickLeaveCertificateRequest{EmployeeID: emp.ID, Folio: folio, Branch: branch, Type: certType,
+			StartDate: dtos.Date{Time: start}, DaysAuthorized: days}
+		if previous != nil {
+			req.PreviousID = &previous.ID
+		}
+		return req
+	}
+
+	// Enfermedad general: the company pays the 3 waiting days, the IMSS 60% of the rest
+	initial, err := service.Create(company.ID, hrID, certificate(employee, "ab123456", models.SickLeaveBranchGeneralIllness,
+		models.SickLeaveTypeInitial, day(1, 10), 7, nil))
+	require.NoError(t, err)
+	assert.Equal(t, "AB123456", initial.Folio)
+	assert.Equal(t, day(1, 16), initial.EndDate)
+	assert.Equal(t, 3, initial.CompanyPaidDays)
+	assert.Equal(t, 4, initial.SubsidizedDays)
+	assert.InDelta(t, roundTo2(4*sbc*0.60), initial.EstimatedSubsidy, 0.001)
+
+	_, err = service.Create(company.ID, hrID, certificate(other, "AB123456", models.SickLeaveBranchWorkRisk,
+		models.SickLeaveTypeInitial, day(1, 10), 5, nil))
+	assert.ErrorContains(t, err, "already exists")
+	_, err = service.Create(company.ID, hrID, certificate(employee, "AB123457", models.SickLeaveBranchGeneralIllness,
+		models.SickLeaveTypeSubsequent, day(1, 17), 7, nil))
+	assert.ErrorContains(t, err, "previous incapacidad is required")
+
+	// The subsequent certificate is subsidized from its first day and closes the initial one
+	subsequent, err := service.Create(company.ID, hrID, certificate(employee, "AB123457", models.SickLeaveBranchGeneralIllness,
+		models.SickLeaveTypeSubsequent, day(1, 17), 7, initial))
+	require.NoError(t, err)
+	assert.Equal(t, 0, subsequent.CompanyPaidDays)
+	assert.Equal(t, 7, subsequent.SubsidizedDays)
+	initial, err = service.Get(company.ID, initial.ID)
+	require.NoError(t, err)
+	assert.Equal(t, models.SickLeaveClosed, initial.Status)
+	assert.Equal(t, 4, initial.SubsidizedDays)
+
+	// Riesgo de trabajo is subsidized at 100% from day one
+	workRisk, err := service.Create(company.ID, hrID, certificate(other, "RT000001", models.SickLeaveBranchWorkRisk,
+		models.SickLeaveTypeInitial, day(1, 14), 4, nil))
+	require.NoError(t, err)
+	assert.Equal(t, 0, workRisk.CompanyPaidDays)
+	assert.InDelta(t, roundTo2(4*otherSBC), workRisk.EstimatedSubsidy, 0.001)
+
+	// A certificate is split across payroll periods, waiting days first
+	first, err := service.forPeriod(employee.ID, day(1, 1), day(1, 15))
+	require.NoError(t, err)
+	assert.Equal(t, 3, first.CompanyPaidDays)
+	assert.Equal(t, 3, first.SubsidizedDays)
+	second, err := service.forPeriod(employee.ID, day(1, 16), day(1, 31))
+	require.NoError(t, err)
+	assert.Equal(t, 0, second.CompanyPaidDays)
+	assert.Equal(t, 8, second.SubsidizedDays)
+
+	period := &models.PayrollPeriod{StartDate: day(1, 1), EndDate: day(1, 15)}
+	payroll := &PayrollService{db: db, sickLeave: service}
+	contributionDays, err := payroll.contributionDays(employee, period)
+	require.NoError(t, err)
+	assert.Equal(t, 9, contributionDays)
+
+	incapacidades, err := service.PayrollIncapacidades(employee, period.StartDate, period.EndDate)
+	require.NoError(t, err)
+	require.Len(t, incapacidades, 1)
+	assert.Equal(t, "02", incapacidades[0].TipoIncapacidad)
+	assert.Equal(t, "6", incapacidades[0].DiasIncapacidad)
+	assert.Equal(t, "1200.00", incapacidades[0].ImporteMonetario)
+	xml, err := NewCfdiService("", "", "").GenerateCfdiXML(&models.PayrollCalculation{Employee: employee, PayrollPeriod: period}, incapacidades)
+	require.NoError(t, err)
+	assert.Contains(t, string(xml), `TipoIncapacidad="02" DiasIncapacidad="6" ImporteMonetario="1200.00"`)
+
+	open, err := service.OpenCertificates(company.ID)
+	require.NoError(t, err)
+	require.Len(t, open, 2)
+	for _, cert := range open {
+		if cert.ID == subsequent.ID {
+			assert.Equal(t, 14, cert.ChainDays)
+		}
+	}
+
+	// The alta shortens the certificate; a continued certificate cannot be cancelled
+	subsequent, err = service.Close(company.ID, subsequent.ID, dtos.SickLeaveCloseRequest{ClosedOn: dtos.Date{Time: day(1, 20)}})
+	require.NoError(t, err)
+	assert.Equal(t, 3, subsequent.SubsidizedDays)
+	_, err = service.Close(company.ID, subsequent.ID, dtos.SickLeaveCloseRequest{ClosedOn: dtos.Date{Time: day(1, 20)}})
+	assert.ErrorContains(t, err, "must be open")
+	_, err = service.Cancel(company.ID, initial.ID)
+	assert.ErrorContains(t, err, "cannot be cancelled")
+
+	summary, err := service.Summary(company.ID, day(1, 1), day(1, 31))
+	require.NoError(t, err)
+	require.Len(t, summary, 2)
+	assert.Equal(t, employee.ID, summary[0].EmployeeID)
+	assert.Equal(t, 10, summary[0].TotalDays)
+	assert.Equal(t, 3, summary[0].CompanyPaidDays)
+	assert.Equal(t, 7, summary[0].SubsidizedDays)
+	assert.Equal(t, 0, summary[0].OpenCertificates)
+	assert.InDelta(t, roundTo2(7*sbc*0.60), summary[0].EstimatedSubsidy, 0.01)
+	assert.Equal(t, 4, summary[1].DaysByBranch[models.SickLeaveBranchWorkRisk])
+}
@@ -0,0 +1,594 @@
+/*
+Package services - Time Bank Service
+
+==============================================================================
+FILE: internal/services/time_bank_service.go
+==============================================================================
+
+DESCRIPTION:
+    Keeps the time-for-time bank (tiempo por tiempo) of each employee as a
+    ledger of TimeOffAccrual movements with time_off_type "time_for_time",
+    like the vacation ledger. Overtime requested with compensation "bank"
+    is not paid: once reconciled against the clock records its hours are
+    deposited. Approved TIME_FOR_TIME absence requests consume the hours,
+    the deposit that expires first is used first; cancelling them gives the
+    hours back. Deposits still unused after the time policy's
+    TimeBankExpiryDays expire, and TimeBankMaxHours caps the balance when
+    overtime is requested for the bank.
+
+USER PERSPECTIVE:
+    - An operator works 3 extra hours on Thursday and takes them off on
+      Monday afternoon instead of being paid for them
+    - Employees see the hours they banked and when they expire
+    - HR corrects a balance with a reason instead of editing it
+
+DEVELOPER GUIDELINES:
+    ✅  OK to modify: Statement content, consumption order
+    ⚠️  CAUTION: Never update or delete ledger rows; post a reversal or an
+        adjustment instead
+    ⚠️  CAUTION: Posting is idempotent per overtime request (deposits) and
+        per absence request (consumption), so reconciliations and runs can
+        be repeated safely
+    📝  The service runs inside the caller's transaction through withDB
+    📝  Limits come from the employee's TimePolicy; TimeBankMaxHours 0 means
+        the employee cannot bank overtime
+
+SYNTAX EXPLANATION:
+    - Deposit: a bank_deposit movement (or a positive adjustment); every
+      other movement points at the deposit it charges through DepositID
+    - Hours are signed: deposits and reversals add, consumption and
+      expiry subtract
+    - Usable hours on a date: what is left of the deposits not expired by
+      then, minus the TIME_FOR_TIME requests still pending
+
+==============================================================================
+*/
+package services
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"backend/internal/dtos"
+	"backend/internal/models"
+)
+
+// timeOffTimeBank is the TimeOffAccrual.TimeOffType of the time-for-time bank
+const timeOffTimeBank = "time_for_time"
+
+// TimeBankService posts and reports time-for-time bank movements
+type TimeBankService struct {
+	db       *gorm.DB
+	policies *TimePolicyService
+}
+
+// NewTimeBankService creates a new TimeBankService
+func NewTimeBankService(db *gorm.DB) *TimeBankService {
+	return &TimeBankService{db: db, policies: NewTimePolicyService(db)}
+}
+
+// withDB returns a copy of the service that reads and posts through db
+func (s *TimeBankService) withDB(db *gorm.DB) *TimeBankService {
+	return &TimeBankService{db: db, policies: s.policies.withDB(db)}
+}
+
+// === Posting ===
+
+// post writes one time bank movement for the employee
+func (s *TimeBankService) post(employee *models.Employee, entry *models.TimeOffAccrual) error {
+	entry.CompanyID = employee.CompanyID
+	entry.EmployeeID = employee.ID
+	entry.TimeOffType = timeOffTimeBank
+	entry.Hours = roundHours(entry.Hours)
+	return s.db.Create(entry).Error
+}
+
+// entries returns the employee's time bank movements in posting order
+func (s *TimeBankService) entries(employeeID uuid.UUID) ([]models.TimeOffAccrual, error) {
+	var entries []models.TimeOffAccrual
+	err := s.db.Where("employee_id = ? AND time_off_type = ?", employeeID, timeOffTimeBank).
+		Order("accrual_date, created_at").Find(&entries).Error
+	return entries, err
+}
+
+// deposits returns what is left of the employee's deposits, first to expire first
+func (s *TimeBankService) deposits(employeeID uuid.UUID) ([]dtos.TimeBankDeposit, []models.TimeOffAccrual, error) {
+	entries, err := s.entries(employeeID)
+	if err != nil {
+		return nil, nil, err
+	}
+	return timeBankDeposits(entries), entries, nil
+}
+
+// timeBankExpiry returns the expiry of a deposit made on date under the policy
+func timeBankExpiry(policy *models.TimePolicy, date time.Time) *time.Time {
+	if policy.TimeBankExpiryDays <= 0 {
+		return nil
+	}
+	expiry := truncateToDate(date).AddDate(0, 0, policy.TimeBankExpiryDays)
+	return &expiry
+}
+
+// CheckDeposit refuses overtime for the bank that the employee's policy does
+// not allow or that would take the balance over TimeBankMaxHours
+func (s *TimeBankService) CheckDeposit(employee *models.Employee, hours float64, excludeRequestID *uuid.UUID) error {
+	policy, _, err := s.policies.ResolvePolicy(employee)
+	if err != nil {
+		return err
+	}
+	if policy.TimeBankMaxHours <= 0 {
+		return errors.New("overtime cannot be banked under the employee's time policy")
+	}
+
+	entries, err := s.entries(employee.ID)
+	if err != nil {
+		return err
+	}
+	balance := 0.0
+	for _, entry := range entries {
+		balance += entry.Hours
+	}
+
+	// Overtime requested for the bank and not reconciled yet
+	query := s.db.Model(&models.OvertimeRequest{}).
+		Select("COALESCE(SUM(estimated_hours), 0) AS hours").
+		Where("employee_id = ? AND compensation = ? AND status IN ?", employee.ID, models.OvertimeCompensationBank,
+			[]string{models.OvertimeStatusPending, models.OvertimeStatusApproved})
+	if excludeRequestID != nil {
+		query = query.Where("id <> ?", *excludeRequestID)
+	}
+	var pending struct{ Hours float64 }
+	if err := query.Scan(&pending).Error; err != nil {
+		return err
+	}
+
+	if total := roundHours(balance + pending.Hours + hours); total > policy.TimeBankMaxHours {
+		return fmt.Errorf("time bank must not exceed %g hours: %g banked or requested, %g more requested",
+			policy.TimeBankMaxHours, roundHours(balance+pending.Hours), hours)
+	}
+	return nil
+}
+
+// Deposit banks the reconciled hours of an overtime request. Requests paid
+// as overtime, without hours, or already deposited are ignored.
+func (s *TimeBankService) Deposit(request *models.OvertimeRequest, hours float64) error {
+	if request.Compensation != models.OvertimeCompensationBank || hours <= 0 {
+		return nil
+	}
+	var count int64
+	if err := s.db.Model(&models.TimeOffAccrual{}).
+		Where("overtime_request_id = ? AND time_off_type = ? AND reason = ?", request.ID, timeOffTimeBank, models.AccrualBankDeposit).
+		Count(&count).Error; err != nil {
+		return err
+	}
+	if count > 0 {
+		return nil
+	}
+
+	var employee models.Employee
+	if err := s.db.First(&employee, "id = ?", request.EmployeeID).Error; err != nil {
+		return errors.New("employee not found")
+	}
+	policy, _, err := s.policies.ResolvePolicy(&employee)
+	if err != nil {
+		return err
+	}
+	workedOn := truncateToDate(request.RequestDate)
+	return s.post(&employee, &models.TimeOffAccrual{
+		AccrualDate:       workedOn,
+		Reason:            models.AccrualBankDeposit,
+		Hours:             hours,
+		ExpiresOn:         timeBankExpiry(policy, workedOn),
+		OvertimeRequestID: &request.ID,
+		CreatedByID:       request.ApprovedByID,
+		Notes:             fmt.Sprintf("Tiempo extra del %s", workedOn.Format("2006-01-02")),
+	})
+}
+
+// CheckConsumption refuses a TIME_FOR_TIME request for more hours than the
+// employee can use on the day it is taken
+func (s *TimeBankService) CheckConsumption(employee *models.Employee, hours float64, takenOn time.Time, excludeRequestID *uuid.UUID) error {
+	deposits, _, err := s.deposits(employee.ID)
+	if err != nil {
+		return err
+	}
+	pending, err := s.pendingHours(employee.ID, excludeRequestID)
+	if err != nil {
+		return err
+	}
+	usable := roundHours(usableHours(deposits, truncateToDate(takenOn)) - pending)
+	if hours > usable {
+		return fmt.Errorf("time bank has %g hours available on %s, %g requested",
+			math.Max(usable, 0), takenOn.Format("2006-01-02"), hours)
+	}
+	return nil
+}
+
+// Consume charges an approved TIME_FOR_TIME request to the deposits usable on
+// its date, first to expire first. A request already charged is ignored.
+func (s *TimeBankService) Consume(request *models.AbsenceRequest, employee *models.Employee, actorID *uuid.UUID) error {
+	if request.RequestType != models.RequestTypeTimeForTime || request.TotalHours <= 0 {
+		return nil
+	}
+	deposits, entries, err := s.deposits(employee.ID)
+	if err != nil {
+		return err
+	}
+	charged := 0.0
+	for _, entry := range entries {
+		if sameUUID(entry.AbsenceRequestID, &request.ID) &&
+			(entry.Reason == models.AccrualConsumption || entry.Reason == models.AccrualReversal) {
+			charged += entry.Hours
+		}
+	}
+	if charged < 0 {
+		return nil
+	}
+
+	takenOn := truncateToDate(request.StartDate)
+	if usable := usableHours(deposits, takenOn); request.TotalHours > usable {
+		return fmt.Errorf("time bank has %g hours available on %s, %g requested",
+			usable, takenOn.Format("2006-01-02"), request.TotalHours)
+	}
+	remaining := request.TotalHours
+	for _, deposit := range deposits {
+		if remaining <= 0 {
+			break
+		}
+		if deposit.Remaining <= 0 || !depositUsableOn(deposit, takenOn) {
+			continue
+		}
+		taken := math.Min(remaining, deposit.Remaining)
+		depositID := deposit.ID
+		if err := s.post(employee, &models.TimeOffAccrual{
+			AccrualDate:      takenOn,
+			Reason:           models.AccrualConsumption,
+			Hours:            -taken,
+			DepositID:        &depositID,
+			AbsenceRequestID: &request.ID,
+			CreatedByID:      actorID,
+			Notes:            fmt.Sprintf("Tiempo por tiempo del %s", takenOn.Format("2006-01-02")),
+		}); err != nil {
+			return err
+		}
+		remaining = roundHours(remaining - taken)
+	}
+	return nil
+}
+
+// ReverseConsumption gives back the hours charged for a TIME_FOR_TIME request
+// that was cancelled after its approval
+func (s *TimeBankService) ReverseConsumption(request *models.AbsenceRequest, employee *models.Employee, actorID *uuid.UUID, now time.Time) error {
+	var movements []models.TimeOffAccrual
+	if err := s.db.Where("absence_request_id = ? AND time_off_type = ? AND reason IN ?", request.ID, timeOffTimeBank,
+		[]string{models.AccrualConsumption, models.AccrualReversal}).Find(&movements).Error; err != nil {
+		return err
+	}
+	reversed := make(map[uuid.UUID]bool)
+	for _, movement := range movements {
+		if movement.ReversesID != nil {
+			reversed[*movement.ReversesID] = true
+		}
+	}
+	for _, movement := range movements {
+		if movement.Reason != models.AccrualConsumption || reversed[movement.ID] {
+			continue
+		}
+		movementID := movement.ID
+		if err := s.post(employee, &models.TimeOffAccrual{
+			AccrualDate:      truncateToDate(now),
+			Reason:           models.AccrualReversal,
+			Hours:            -movement.Hours,
+			DepositID:        movement.DepositID,
+			AbsenceRequestID: &request.ID,
+			ReversesID:       &movementID,
+			CreatedByID:      actorID,
+			Notes:            "Cancelación del tiempo por tiempo",
+		}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Adjust posts a manual correction: positive hours are a new deposit,
+// negative hours are charged to the deposits first to expire
+func (s *TimeBankService) Adjust(companyID, employeeID, actorID uuid.UUID, req dtos.TimeBankAdjustmentRequest, now time.Time) (*dtos.TimeBankStatement, error) {
+	reason := strings.TrimSpace(req.Reason)
+	if reason == "" {
+		return nil, errors.New("reason is required")
+	}
+	if req.Hours == 0 {
+		return nil, errors.New("hours must not be zero")
+	}
+	employee, err := s.companyEmployee(companyID, employeeID)
+	if err != nil {
+		return nil, err
+	}
+	policy, _, err := s.policies.ResolvePolicy(employee)
+	if err != nil {
+		return nil, err
+	}
+	today := truncateToDate(now)
+
+	err = s.db.Transaction(func(tx *gorm.DB) error {
+		bank := s.withDB(tx)
+		if req.Hours > 0 {
+			return bank.post(employee, &models.TimeOffAccrual{
+				AccrualDate: today,
+				Reason:      models.AccrualAdjustment,
+				Hours:       req.Hours,
+				ExpiresOn:   timeBankExpiry(policy, today),
+				Notes:       reason,
+				IsManual:    true,
+				CreatedByID: &actorID,
+			})
+		}
+
+		deposits, _, err := bank.deposits(employee.ID)
+		if err != nil {
+			return err
+		}
+		remaining := -req.Hours
+		if usable := usableHours(deposits, today); remaining > usable {
+			return fmt.Errorf("adjustment must not exceed the %g hours available", usable)
+		}
+		for _, deposit := range deposits {
+			if remaining <= 0 {
+				break
+			}
+			if deposit.Remaining <= 0 || !depositUsableOn(deposit, today) {
+				continue
+			}
+			taken := math.Min(remaining, deposit.Remaining)
+			depositID := deposit.ID
+			if err := bank.post(employee, &models.TimeOffAccrual{
+				AccrualDate: today,
+				Reason:      models.AccrualAdjustment,
+				Hours:       -taken,
+				DepositID:   &depositID,
+				Notes:       reason,
+				IsManual:    true,
+				CreatedByID: &actorID,
+			}); err != nil {
+				return err
+			}
+			remaining = roundHours(remaining - taken)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return s.buildStatement(employee, now)
+}
+
+// expireDue expires what is left of every deposit whose expiry has been reached
+func (s *TimeBankService) expireDue(employee *models.Employee, asOf time.Time) (int, float64, error) {
+	deposits, _, err := s.deposits(employee.ID)
+	if err != nil {
+		return 0, 0, err
+	}
+	expiries, hours := 0, 0.0
+	for _, deposit := range deposits {
+		if deposit.Remaining <= 0 || deposit.ExpiresOn == nil || deposit.ExpiresOn.After(truncateToDate(asOf)) {
+			continue
+		}
+		depositID := deposit.ID
+		if err := s.post(employee, &models.TimeOffAccrual{
+			AccrualDate: *deposit.ExpiresOn,
+			Reason:      models.AccrualExpiry,
+			Hours:       -deposit.Remaining,
+			DepositID:   &depositID,
+			Notes:       fmt.Sprintf("Horas depositadas el %s no utilizadas", deposit.DepositedOn.Format("2006-01-02")),
+		}); err != nil {
+			return expiries, hours, err
+		}
+		expiries++
+		hours += deposit.Remaining
+	}
+	return expiries, hours, nil
+}
+
+// ExpireBalances expires the unused hours of every deposit past its expiry
+func (s *TimeBankService) ExpireBalances(companyID uuid.UUID, now time.Time) (*dtos.TimeBankRunResult, error) {
+	var employees []models.Employee
+	if err := s.db.Where("company_id = ? AND employment_status = ?", companyID, "active").
+		Where("id IN (?)", s.db.Model(&models.TimeOffAccrual{}).Select("employee_id").Where("time_off_type = ?", timeOffTimeBank)).
+		Order("employee_number").Find(&employees).Error; err != nil {
+		return nil, err
+	}
+
+	result := &dtos.TimeBankRunResult{}
+	for i := range employees {
+		expiries, hours, err := s.expireDue(&employees[i], now)
+		if err != nil {
+			return nil, fmt.Errorf("employee %s: %w", employees[i].EmployeeNumber, err)
+		}
+		result.Employees++
+		result.Expiries += expiries
+		result.HoursExpired += hours
+	}
+	result.HoursExpired = roundHours(result.HoursExpired)
+	return result, nil
+}
+
+// pendingHours sums the TIME_FOR_TIME requests of the employee waiting for approval
+func (s *TimeBankService) pendingHours(employeeID uuid.UUID, excludeRequestID *uuid.UUID) (float64, error) {
+	// AbsenceRequest.EmployeeID holds the requesting user's ID
+	query := s.db.Model(&models.AbsenceRequest{}).
+		Joins("JOIN users ON users.id = absence_requests.employee_id").
+		Where("users.employee_id = ? AND absence_requests.request_type = ? AND absence_requests.status = ?",
+			employeeID, models.RequestTypeTimeForTime, models.RequestStatusPending).
+		Scopes(timeOffRequests)
+	if excludeRequestID != nil {
+		query = query.Where("absence_requests.id <> ?", *excludeRequestID)
+	}
+	var pending struct{ Hours float64 }
+	err := query.Select("COALESCE(SUM(absence_requests.total_hours), 0) AS hours").Scan(&pending).Error
+	return roundHours(pending.Hours), err
+}
+
+// === Statement ===
+
+// Statement expires the deposits due for the employee and returns the statement
+func (s *TimeBankService) Statement(companyID, employeeID uuid.UUID, now time.Time) (*dtos.TimeBankStatement, error) {
+	employee, err := s.companyEmployee(companyID, employeeID)
+	if err != nil {
+		return nil, err
+	}
+	if _, _, err := s.expireDue(employee, now); err != nil {
+		return nil, err
+	}
+	return s.buildStatement(employee, now)
+}
+
+// StatementForUser returns the statement of the employee linked to a user
+func (s *TimeBankService) StatementForUser(companyID, userID uuid.UUID, now time.Time) (*dtos.TimeBankStatement, error) {
+	var user models.User
+	if err := s.db.Select("id", "employee_id").Limit(1).Find(&user, "id = ?", userID).Error; err != nil {
+		return nil, err
+	}
+	if user.EmployeeID == nil {
+		return nil, errors.New("employee not found")
+	}
+	return s.Statement(companyID, *user.EmployeeID, now)
+}
+
+// companyEmployee loads an employee of the company
+func (s *TimeBankService) companyEmployee(companyID, employeeID uuid.UUID) (*models.Employee, error) {
+	var employee models.Employee
+	if err := s.db.Where("id = ? AND company_id = ?", employeeID, companyID).Limit(1).Find(&employee).Error; err != nil {
+		return nil, err
+	}
+	if employee.ID == uuid.Nil {
+		return nil, errors.New("employee not found")
+	}
+	return &employee, nil
+}
+
+// buildStatement summarizes the ledger as of now without posting anything
+func (s *TimeBankService) buildStatement(employee *models.Employee, now time.Time) (*dtos.TimeBankStatement, error) {
+	policy, _, err := s.policies.ResolvePolicy(employee)
+	if err != nil {
+		return nil, err
+	}
+	deposits, entries, err := s.deposits(employee.ID)
+	if err != nil {
+		return nil, err
+	}
+	statement := &dtos.TimeBankStatement{
+		EmployeeID:     employee.ID,
+		EmployeeNumber: employee.EmployeeNumber,
+		EmployeeName:   strings.TrimSpace(employee.FirstName + " " + employee.LastName),
+		MaxHours:       policy.TimeBankMaxHours,
+		ExpiryDays:     policy.TimeBankExpiryDays,
+		Deposits:       deposits,
+		Movements:      make([]dtos.TimeBankMovement, 0, len(entries)),
+	}
+
+	balance := 0.0
+	for _, entry := range entries {
+		balance = roundHours(balance + entry.Hours)
+		statement.Movements = append(statement.Movements, dtos.TimeBankMovement{
+			ID:                entry.ID,
+			Date:              entry.AccrualDate,
+			Type:              entry.Reason,
+			Hours:             entry.Hours,
+			Balance:           balance,
+			DepositID:         entry.DepositID,
+			OvertimeRequestID: entry.OvertimeRequestID,
+			AbsenceRequestID:  entry.AbsenceRequestID,
+			Notes:             entry.Notes,
+			IsManual:          entry.IsManual,
+			CreatedByID:       entry.CreatedByID,
+		})
+	}
+
+	pending, err := s.pendingHours(employee.ID, nil)
+	if err != nil {
+		return nil, err
+	}
+	statement.Balance = balance
+	statement.PendingHours = pending
+	statement.Available = roundHours(usableHours(deposits, truncateToDate(now)) - pending)
+	return statement, nil
+}
+
+// timeBankDeposits groups movements by the deposit they charge, first to expire first
+func timeBankDeposits(entries []models.TimeOffAccrual) []dtos.TimeBankDeposit {
+	var deposits []dtos.TimeBankDeposit
+	index := make(map[uuid.UUID]int)
+	for _, entry := range entries {
+		if entry.DepositID == nil && entry.Hours > 0 {
+			index[entry.ID] = len(deposits)
+			deposits = append(deposits, dtos.TimeBankDeposit{
+				ID:                entry.ID,
+				DepositedOn:       entry.AccrualDate,
+				ExpiresOn:         entry.ExpiresOn,
+				OvertimeRequestID: entry.OvertimeRequestID,
+				Deposited:         entry.Hours,
+				Remaining:         entry.Hours,
+			})
+		}
+	}
+	for _, entry := range entries {
+		if entry.DepositID == nil {
+			continue
+		}
+		i, ok := index[*entry.DepositID]
+		if !ok {
+			continue
+		}
+		deposit := &deposits[i]
+		if entry.Reason == models.AccrualExpiry {
+			deposit.Expired -= entry.Hours
+		} else {
+			deposit.Used -= entry.Hours
+		}
+		deposit.Remaining += entry.Hours
+	}
+
+	for i := range deposits {
+		deposit := &deposits[i]
+		deposit.Used = roundHours(deposit.Used)
+		deposit.Expired = roundHours(deposit.Expired)
+		deposit.Remaining = roundHours(deposit.Remaining)
+	}
+	sort.SliceStable(deposits, func(i, j int) bool {
+		a, b := deposits[i].ExpiresOn, deposits[j].ExpiresOn
+		switch {
+		case a != nil && b != nil && !a.Equal(*b):
+			return a.Before(*b)
+		case a != nil && b == nil:
+			return true
+		case a == nil && b != nil:
+			return false
+		}
+		return deposits[i].DepositedOn.Before(deposits[j].DepositedOn)
+	})
+	return deposits
+}
+
+// depositUsableOn reports whether a deposit was made by date and has not expired
+func depositUsableOn(deposit dtos.TimeBankDeposit, date time.Time) bool {
+	return !deposit.DepositedOn.After(date) && (deposit.ExpiresOn == nil || deposit.ExpiresOn.After(date))
+}
+
+// usableHours sums what is left of the deposits not expired by date
+func usableHours(deposits []dtos.TimeBankDeposit, date time.Time) float64 {
+	total := 0.0
+	for _, deposit := range deposits {
+		if deposit.Remaining > 0 && depositUsableOn(deposit, date) {
+			total += deposit.Remaining
+		}
+	}
+	return roundHours(total)
+}
@@ -0,0 +1,219 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+
+	"backend/internal/dtos"
+	"backend/internal/models"
+)
+
+// timeBankTest is an employee of a company and an HR user
+type timeBankTest struct {
+	db       *gorm.DB
+	service  *TimeBankService
+	company  *models.Company
+	employee *models.Employee
+	user     *models.User
+	hrID     uuid.UUID
+}
+
+func setupTimeBankTest(t *testing.T) *timeBankTest {
+	db := setupPayrollTestDB(t)
+	require.NoError(t, db.AutoMigrate(
+		&models.User{}, &models.AbsenceRequest{}, &models.TimePolicy{}, &models.OvertimeRequest{}, &models.TimeOffAccrual{},
+		&models.Holiday{}, &models.Shift{}, &models.EmployeeShiftBase{}, &models.ShiftException{},
+		&models.RotationPattern{}, &models.RotationPatternDay{}, &models.RotationAssignment{},
+	))
+	company := createPayrollTestCompany(t, db)
+	employee := createFixtureEmployee(t, db, company.ID, 1, "general", 400)
+	return &timeBankTest{
+		db:       db,
+		service:  NewTimeBankService(db),
+		company:  company,
+		employee: employee,
+		user:     createFixtureUser(t, db, employee, "employee"),
+		hrID:     createFixtureUser(t, db, createFixtureEmployee(t, db, company.ID, 2, "general", 400), "hr").ID,
+	}
+}
+
+// allowBanking adds a default policy banking up to 10 hours for 30 days
+func (f *timeBankTest) allowBanking(t *testing.T) {
+	require.NoError(t, f.db.Create(&models.TimePolicy{CompanyID: f.company.ID, Name: "Planta", IsDefault: true, IsActive: true,
+		TimeBankMaxHours: 10, TimeBankExpiryDays: 30}).Error)
+}
+
+// overtime records completed bank overtime of the employee
+func (f *timeBankTest) overtime(t *testing.T, date time.Time, hours float64) *models.OvertimeRequest {
+	request := &models.OvertimeRequest{CompanyID: f.company.ID, EmployeeID: f.employee.ID, RequestDate: date,
+		StartTime: date.Add(18 * time.Hour), EndTime: date.Add(21 * time.Hour), EstimatedHours: hours, Reason: "Inventario",
+		Compensation: models.OvertimeCompensationBank, Status: models.OvertimeStatusCompleted}
+	require.NoError(t, f.db.Create(request).Error)
+	return request
+}
+
+// deposit banks 4 hours worked on January 6 and 3 on January 20
+func (f *timeBankTest) deposit(t *testing.T) {
+	f.allowBanking(t)
+	require.NoError(t, f.service.Deposit(f.overtime(t, bankDay(1, 6), 4), 4))
+	require.NoError(t, f.service.Deposit(f.overtime(t, bankDay(1, 20), 3), 3))
+}
+
+// permit records an approved 5-hour time for time on January 27
+func (f *timeBankTest) permit(t *testing.T) *models.AbsenceRequest {
+	permit := &models.AbsenceRequest{EmployeeID: f.user.ID, RequestType: models.RequestTypeTimeForTime,
+		StartDate: bankDay(1, 27), EndDate: bankDay(1, 27), StartTime: "13:00", EndTime: "18:00", TotalHours: 5,
+		TotalDays: 0.63, Reason: "Trámite", Status: models.RequestStatusApproved}
+	require.NoError(t, f.db.Create(permit).Error)
+	return permit
+}
+
+func (f *timeBankTest) statement(t *testing.T, now time.Time) *dtos.TimeBankStatement {
+	statement, err := f.service.Statement(f.company.ID, f.employee.ID, now)
+	require.NoError(t, err)
+	return statement
+}
+
+func bankDay(m time.Month, d int) time.Time {
+	return time.Date(2025, m, d, 0, 0, 0, 0, time.Local)
+}
+
+func TestTimeBank(t *testing.T) {
+	t.Run("banking needs a policy that allows it", func(t *testing.T) {
+		f := setupTimeBankTest(t)
+		assert.ErrorContains(t, f.service.CheckDeposit(f.employee, 2, nil), "cannot be banked")
+		f.allowBanking(t)
+		require.NoError(t, f.service.CheckDeposit(f.employee, 2, nil))
+	})
+
+	t.Run("overtime is deposited once and expires 30 days after the work date", func(t *testing.T) {
+		f := setupTimeBankTest(t)
+		f.allowBanking(t)
+		january := f.overtime(t, bankDay(1, 6), 4)
+		require.NoError(t, f.service.Deposit(january, 4))
+		require.NoError(t, f.service.Deposit(january, 4))
+		require.NoError(t, f.service.Deposit(f.overtime(t, bankDay(1, 20), 3), 3))
+
+		statement := f.statement(t, bankDay(1, 27))
+		assert.Equal(t, 7.0, statement.Balance)
+		require.Len(t, statement.Deposits, 2)
+		assert.Equal(t, "2025-02-05", statement.Deposits[0].ExpiresOn.Format("2006-01-02"))
+	})
+
+	t.Run("the balance is capped by the policy", func(t *testing.T) {
+		f := setupTimeBankTest(t)
+		f.deposit(t)
+		assert.ErrorContains(t, f.service.CheckDeposit(f.employee, 4, nil), "must not exceed 10 hours")
+	})
+
+	t.Run("time for time uses the deposit that expires first", func(t *testing.T) {
+		f := setupTimeBankTest(t)
+		f.deposit(t)
+		permit := f.permit(t)
+		assert.ErrorContains(t, f.service.CheckConsumption(f.employee, 8, bankDay(1, 27), nil), "7 hours available")
+		require.NoError(t, f.service.Consume(permit, f.employee, &f.hrID))
+		require.NoError(t, f.service.Consume(permit, f.employee, &f.hrID))
+
+		statement := f.statement(t, bankDay(1, 27))
+		assert.Equal(t, 2.0, statement.Balance)
+		assert.Equal(t, 0.0, statement.Deposits[0].Remaining)
+		assert.Equal(t, 2.0, statement.Deposits[1].Remaining)
+	})
+
+	t.Run("reversal gives the hours back to the same deposits", func(t *testing.T) {
+		f := setupTimeBankTest(t)
+		f.deposit(t)
+		permit := f.permit(t)
+		require.NoError(t, f.service.Consume(permit, f.employee, &f.hrID))
+		require.NoError(t, f.service.ReverseConsumption(permit, f.employee, &f.hrID, bankDay(1, 28)))
+		require.NoError(t, f.service.ReverseConsumption(permit, f.employee, &f.hrID, bankDay(1, 28)))
+
+		statement := f.statement(t, bankDay(1, 28))
+		assert.Equal(t, 7.0, statement.Balance)
+		assert.Equal(t, 4.0, statement.Deposits[0].Remaining)
+		assert.Equal(t, 3.0, statement.Deposits[1].Remaining)
+	})
+
+	t.Run("deposits expire once", func(t *testing.T) {
+		f := setupTimeBankTest(t)
+		f.deposit(t)
+		result, err := f.service.ExpireBalances(f.company.ID, bankDay(2, 10))
+		require.NoError(t, err)
+		assert.Equal(t, 1, result.Expiries)
+		assert.Equal(t, 4.0, result.HoursExpired)
+		result, err = f.service.ExpireBalances(f.company.ID, bankDay(2, 10))
+		require.NoError(t, err)
+		assert.Zero(t, result.Expiries)
+
+		statement := f.statement(t, bankDay(2, 10))
+		assert.Equal(t, 3.0, statement.Balance)
+		assert.Equal(t, 4.0, statement.Deposits[0].Expired)
+	})
+
+	t.Run("adjustments need a reason and cannot take more than is usable", func(t *testing.T) {
+		f := setupTimeBankTest(t)
+		f.deposit(t)
+		_, err := f.service.ExpireBalances(f.company.ID, bankDay(2, 10))
+		require.NoError(t, err)
+
+		_, err = f.service.Adjust(f.company.ID, f.employee.ID, f.hrID, dtos.TimeBankAdjustmentRequest{Hours: -1}, bankDay(2, 10))
+		assert.ErrorContains(t, err, "reason is required")
+		_, err = f.service.Adjust(f.company.ID, f.employee.ID, f.hrID, dtos.TimeBankAdjustmentRequest{Hours: -5, Reason: "Corrección"}, bankDay(2, 10))
+		assert.ErrorContains(t, err, "must not exceed the 3 hours available")
+		statement, err := f.service.Adjust(f.company.ID, f.employee.ID, f.hrID, dtos.TimeBankAdjustmentRequest{Hours: 2, Reason: "Evento"}, bankDay(2, 10))
+		require.NoError(t, err)
+		assert.Equal(t, 5.0, statement.Balance)
+		assert.Len(t, statement.Deposits, 3)
+	})
+}
+
+func TestTimeBank_RequestsInHours(t *testing.T) {
+	f := setupTimeBankTest(t)
+	shift := &models.Shift{Name: "Matutino", Code: "MAT", StartTime: "09:00", EndTime: "18:00",
+		BreakMinutes: 60, WorkHoursPerDay: 8, WorkDays: "[1,2,3,4,5]", CompanyID: f.company.ID, IsActive: true}
+	require.NoError(t, f.db.Create(shift).Error)
+	f.employee.ShiftID = &shift.ID
+	requests := NewAbsenceRequestService(f.db, nil)
+	resolve := func(requestType models.RequestType, start, end time.Time, from, to string) (*CreateAbsenceRequestInput, requestWindow, error) {
+		input := &CreateAbsenceRequestInput{EmployeeID: f.user.ID, RequestType: requestType, StartDate: start, EndDate: end,
+			TotalDays: 1, StartTime: from, EndTime: to}
+		window, err := requests.resolveRequestTime(f.employee, input)
+		return input, window, err
+	}
+
+	t.Run("a late entry starts when the shift starts", func(t *testing.T) {
+		input, window, err := resolve(models.RequestTypeLateEntry, bankDay(2, 10), bankDay(2, 10), "9:00", "11:00")
+		require.NoError(t, err)
+		assert.Equal(t, "09:00", window.startTime)
+		assert.Equal(t, 2.0, window.hours)
+		assert.Equal(t, 0.25, input.TotalDays)
+
+		_, _, err = resolve(models.RequestTypeLateEntry, bankDay(2, 10), bankDay(2, 10), "10:00", "11:00")
+		assert.ErrorContains(t, err, "must start when the shift starts")
+		assert.ErrorIs(t, err, ErrInvalidAbsenceRequest)
+	})
+
+	t.Run("hours lie within the shift of a single day", func(t *testing.T) {
+		_, _, err := resolve(models.RequestTypeEarlyExit, bankDay(2, 10), bankDay(2, 10), "", "")
+		assert.ErrorContains(t, err, "times are required")
+		_, _, err = resolve(models.RequestTypePaidLeave, bankDay(2, 10), bankDay(2, 10), "17:00", "19:00")
+		assert.ErrorContains(t, err, "within the shift")
+		_, _, err = resolve(models.RequestTypePaidLeave, bankDay(2, 10), bankDay(2, 11), "10:00", "12:00")
+		assert.ErrorContains(t, err, "same day")
+		_, _, err = resolve(models.RequestTypeVacation, bankDay(2, 10), bankDay(2, 10), "10:00", "12:00")
+		assert.ErrorContains(t, err, "cannot be filed in hours")
+		_, _, err = resolve(models.RequestTypePersonal, bankDay(2, 15), bankDay(2, 15), "10:00", "12:00")
+		assert.ErrorContains(t, err, "no shift is scheduled")
+	})
+
+	t.Run("a whole-day time for time takes the scheduled hours of the work days", func(t *testing.T) {
+		_, window, err := resolve(models.RequestTypeTimeForTime, bankDay(2, 14), bankDay(2, 17), "", "")
+		require.NoError(t, err)
+		assert.Equal(t, 16.0, window.hours)
+	})
+}
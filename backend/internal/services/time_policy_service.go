@@ -0,0 +1,737 @@
+/*
+Package services - Time Policy Service
+
+==============================================================================
+FILE: internal/services/time_policy_service.go
+==============================================================================
+
+DESCRIPTION:
+    Applies the employee's TimePolicy to worked time. When a ClockRecord is
+    closed (web/app clock-out or terminal) the punches are rounded, a
+    missing required break is deducted and the worked hours are split into
+    regular, double and triple time. Timesheet totals get the same split
+    from their regular/overtime entries. Breaches of the policy are stored
+    as TimePolicyViolations for the dashboard. Also maintains the policy
+    catalog.
+
+USER PERSPECTIVE:
+    - Punches are rounded to the policy's interval (e.g. 07:58 -> 08:00)
+    - Working 6h+ without a 30 min break deducts the break and flags it
+    - Overtime is paid double for the first 9 hours of the week and
+      triple after that (LFT Art. 66-68)
+    - HR sees more than 3h of overtime a day, overtime on more than 3 days
+      a week and triple time on the violations dashboard
+
+DEVELOPER GUIDELINES:
+    ✅  OK to modify: Violation rules, policy matching
+    ⚠️  CAUTION: A record is classified against the records closed before it
+        in the same week; re-apply later records if an earlier one changes
+    ⚠️  CAUTION: Re-applying replaces the record's unresolved violations only
+    📝  Days and weeks (Monday start) are read in the company's time zone
+
+SYNTAX EXPLANATION:
+    - Policy match: a policy lists collar types and/or roles (empty list =
+      any); a collar match outranks a role match, both outrank either one.
+      Then the company default (IsDefault), then builtInTimePolicy
+    - Role: the role of the user linked to the employee (users.employee_id)
+    - Rounding: to the nearest RoundingIntervalMinutes of the local clock
+    - Regular = up to OvertimeThresholdDaily a day and
+      OvertimeThresholdWeekly a week; overtime beyond that is double up to
+      WeeklyDoubleTimeHours a week, triple after
+    - Timesheet entries of type regular and overtime both count as worked
+      hours; the policy decides the split
+
+==============================================================================
+*/
+package services
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"backend/internal/dtos"
+	"backend/internal/models"
+)
+
+// builtInTimePolicy is applied when the company has no matching or default
+// policy: the LFT 8h day / 48h week, 9 weekly hours of double time
+func builtInTimePolicy(companyID uuid.UUID) *models.TimePolicy {
+	return &models.TimePolicy{
+		CompanyID:               companyID,
+		Name:                    "LFT",
+		StandardWeeklyHours:     48,
+		StandardDailyHours:      8,
+		OvertimeThresholdDaily:  8,
+		OvertimeThresholdWeekly: 48,
+		OvertimeMultiplier:      2,
+		DoubleTimeMultiplier:    2,
+		WeeklyDoubleTimeHours:   9,
+		TripleTimeMultiplier:    3,
+		MaxDailyOvertimeHours:   3,
+		MaxOvertimeDaysPerWeek:  3,
+		RequiredBreakAfterHours: 6,
+		MinBreakMinutes:         30,
+		IsActive:                true,
+	}
+}
+
+// TimePolicyService applies time policies and manages the policy catalog
+type TimePolicyService struct {
+	db *gorm.DB
+}
+
+// NewTimePolicyService creates a new TimePolicyService
+func NewTimePolicyService(db *gorm.DB) *TimePolicyService {
+	return &TimePolicyService{db: db}
+}
+
+// withDB returns a copy that reads and writes through db (e.g. a transaction)
+func (s *TimePolicyService) withDB(db *gorm.DB) *TimePolicyService {
+	return &TimePolicyService{db: db}
+}
+
+// =========================================================================
+// Policy resolution
+// =========================================================================
+
+// ResolvePolicy returns the policy that applies to an employee and how it matched
+func (s *TimePolicyService) ResolvePolicy(employee *models.Employee) (*models.TimePolicy, string, error) {
+	var policies []models.TimePolicy
+	if err := s.db.Where("company_id = ? AND is_active = ?", employee.CompanyID, true).
+		Order("created_at").Find(&policies).Error; err != nil {
+		return nil, "", fmt.Errorf("error fetching time policies: %w", err)
+	}
+	role, err := s.employeeRole(employee.ID)
+	if err != nil {
+		return nil, "", err
+	}
+
+	var best, fallback *models.TimePolicy
+	bestRank, matchedBy := 0, ""
+	for i := range policies {
+		p := &policies[i]
+		if p.IsDefault && fallback == nil {
+			fallback = p
+		}
+		if len(p.ApplicableCollarTypes) == 0 && len(p.ApplicableRoles) == 0 {
+			continue
+		}
+		if len(p.ApplicableCollarTypes) > 0 && !containsString(p.ApplicableCollarTypes, employee.CollarType) {
+			continue
+		}
+		if len(p.ApplicableRoles) > 0 && (role == "" || !containsString(p.ApplicableRoles, role)) {
+			continue
+		}
+		rank, by := 1, "role"
+		switch {
+		case len(p.ApplicableCollarTypes) > 0 && len(p.ApplicableRoles) > 0:
+			rank, by = 3, "collar_and_role"
+		case len(p.ApplicableCollarTypes) > 0:
+			rank, by = 2, "collar_type"
+		}
+		if rank > bestRank {
+			best, bestRank, matchedBy = p, rank, by
+		}
+	}
+	switch {
+	case best != nil:
+		return best, matchedBy, nil
+	case fallback != nil:
+		return fallback, "default", nil
+	}
+	return builtInTimePolicy(employee.CompanyID), "built_in", nil
+}
+
+// employeeRole returns the role of the user linked to the employee, if any
+func (s *TimePolicyService) employeeRole(employeeID uuid.UUID) (string, error) {
+	var roles []string
+	if err := s.db.Model(&models.User{}).Where("employee_id = ?", employeeID).Limit(1).Pluck("role", &roles).Error; err != nil {
+		return "", fmt.Errorf("error fetching employee role: %w", err)
+	}
+	if len(roles) == 0 {
+		return "", nil
+	}
+	return roles[0], nil
+}
+
+// EffectivePolicy returns the policy in effect for an employee
+func (s *TimePolicyService) EffectivePolicy(companyID, employeeID uuid.UUID) (*dtos.EffectiveTimePolicyResponse, error) {
+	var employee models.Employee
+	if err := s.db.Where("id = ? AND company_id = ?", employeeID, companyID).First(&employee).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("employee not found")
+		}
+		return nil, err
+	}
+	policy, matchedBy, err := s.ResolvePolicy(&employee)
+	if err != nil {
+		return nil, err
+	}
+	role, err := s.employeeRole(employee.ID)
+	if err != nil {
+		return nil, err
+	}
+	return &dtos.EffectiveTimePolicyResponse{
+		EmployeeID: employee.ID,
+		CollarType: employee.CollarType,
+		Role:       role,
+		MatchedBy:  matchedBy,
+		Policy:     policy,
+	}, nil
+}
+
+// =========================================================================
+// Classification
+// =========================================================================
+
+// hoursSplit is worked time split by pay rate
+type hoursSplit struct {
+	Regular float64
+	Double  float64
+	Triple  float64
+}
+
+func (h hoursSplit) overtime() float64 {
+	return h.Double + h.Triple
+}
+
+// policyViolation is a breach found while classifying, before it is stored
+type policyViolation struct {
+	Type     string
+	Severity string
+	Message  string
+	Value    float64
+}
+
+// overtimeTally accumulates a week's classified hours so each new block of
+// work is split against what was already worked that day and week
+type overtimeTally struct {
+	policy      *models.TimePolicy
+	week        string
+	dayRegular  map[string]float64
+	dayOvertime map[string]float64
+	weekRegular float64
+	weekDouble  float64
+}
+
+func newOvertimeTally(policy *models.TimePolicy) *overtimeTally {
+	return &overtimeTally{policy: policy}
+}
+
+// startWeek resets the tally when day falls in a different week
+func (t *overtimeTally) startWeek(day time.Time) {
+	week := policyWeekStart(day).Format("2006-01-02")
+	if week == t.week {
+		return
+	}
+	t.week = week
+	t.dayRegular = make(map[string]float64)
+	t.dayOvertime = make(map[string]float64)
+	t.weekRegular, t.weekDouble = 0, 0
+}
+
+// seed records hours that were already classified
+func (t *overtimeTally) seed(day time.Time, split hoursSplit) {
+	t.startWeek(day)
+	key := day.Format("2006-01-02")
+	t.dayRegular[key] += split.Regular
+	t.dayOvertime[key] += split.overtime()
+	t.weekRegular += split.Regular
+	t.weekDouble += split.Double
+}
+
+// add classifies worked hours on a day and returns the overtime limits they breach
+func (t *overtimeTally) add(day time.Time, worked float64) (hoursSplit, []policyViolation) {
+	t.startWeek(day)
+	key := day.Format("2006-01-02")
+	p := t.policy
+
+	regular := math.Min(worked, remainingHours(p.OvertimeThresholdDaily, t.dayRegular[key]))
+	regular = math.Min(regular, remainingHours(p.OvertimeThresholdWeekly, t.weekRegular))
+	overtime := worked - regular
+	double := math.Min(overtime, remainingHours(p.WeeklyDoubleTimeHours, t.weekDouble))
+	split := hoursSplit{Regular: roundHours(regular), Double: roundHours(double), Triple: roundHours(overtime - double)}
+	t.seed(day, split)
+
+	var violations []policyViolation
+	if split.overtime() == 0 {
+		return split, violations
+	}
+	if dayOvertime := roundHours(t.dayOvertime[key]); p.MaxDailyOvertimeHours > 0 && dayOvertime > p.MaxDailyOvertimeHours {
+		violations = append(violations, policyViolation{
+			Type:     models.ViolationDailyOvertimeLimit,
+			Severity: models.ViolationSeverityWarning,
+			Message:  fmt.Sprintf("%.2f h of overtime on %s, limit is %.2f h", dayOvertime, key, p.MaxDailyOvertimeHours),
+			Value:    dayOvertime,
+		})
+	}
+	days := 0
+	for _, hours := range t.dayOvertime {
+		if hours > 0 {
+			days++
+		}
+	}
+	if p.MaxOvertimeDaysPerWeek > 0 && days > p.MaxOvertimeDaysPerWeek {
+		violations = append(violations, policyViolation{
+			Type:     models.ViolationOvertimeDaysLimit,
+			Severity: models.ViolationSeverityWarning,
+			Message:  fmt.Sprintf("Overtime on %d days in the week of %s, limit is %d", days, t.week, p.MaxOvertimeDaysPerWeek),
+			Value:    float64(days),
+		})
+	}
+	if split.Triple > 0 {
+		violations = append(violations, policyViolation{
+			Type:     models.ViolationWeeklyOvertimeLimit,
+			Severity: models.ViolationSeverityCritical,
+			Message:  fmt.Sprintf("Overtime beyond %.2f h in the week of %s, %.2f h paid triple", p.WeeklyDoubleTimeHours, t.week, split.Triple),
+			Value:    roundHours(t.weekDouble + split.Triple),
+		})
+	}
+	return split, violations
+}
+
+// ApplyToClockRecord rounds a closed record's punches, deducts a missing
+// break, splits its hours into regular/double/triple and stores violations
+func (s *TimePolicyService) ApplyToClockRecord(record *models.ClockRecord) error {
+	if record.ClockOutTime == nil {
+		return errors.New("clock record is not closed")
+	}
+	var employee models.Employee
+	if err := s.db.First(&employee, "id = ?", record.EmployeeID).Error; err != nil {
+		return fmt.Errorf("error fetching employee: %w", err)
+	}
+	policy, _, err := s.ResolvePolicy(&employee)
+	if err != nil {
+		return err
+	}
+	loc := s.companyLocation(record.CompanyID)
+
+	in := roundToInterval(record.ClockInTime, policy.RoundingIntervalMinutes, loc)
+	out := roundToInterval(*record.ClockOutTime, policy.RoundingIntervalMinutes, loc)
+	if out.Before(in) {
+		out = in
+	}
+	span := out.Sub(in).Hours()
+
+	var violations []policyViolation
+	autoBreak := 0
+	if policy.RequiredBreakAfterHours > 0 && policy.MinBreakMinutes > 0 &&
+		span >= policy.RequiredBreakAfterHours && record.BreakMinutes < policy.MinBreakMinutes {
+		message := fmt.Sprintf("Break of %d min after %.2f h worked, %d min required", record.BreakMinutes, span, policy.MinBreakMinutes)
+		if !policy.DisableAutoBreak {
+			autoBreak = policy.MinBreakMinutes - record.BreakMinutes
+			message += fmt.Sprintf("; %d min deducted", autoBreak)
+		}
+		violations = append(violations, policyViolation{
+			Type:     models.ViolationMissingBreak,
+			Severity: models.ViolationSeverityWarning,
+			Message:  message,
+			Value:    float64(record.BreakMinutes),
+		})
+	}
+	worked := roundHours(math.Max(span-float64(record.BreakMinutes+autoBreak)/60, 0))
+
+	workDate := policyDay(in, loc)
+	tally := newOvertimeTally(policy)
+	tally.startWeek(workDate)
+	monday := policyWeekStart(workDate)
+	weekStart := time.Date(monday.Year(), monday.Month(), monday.Day(), 0, 0, 0, 0, loc)
+	var earlier []models.ClockRecord
+	if err := s.db.Where("employee_id = ? AND is_complete = ? AND id <> ? AND clock_in_time >= ? AND clock_in_time < ?",
+		record.EmployeeID, true, record.ID, weekStart, record.ClockInTime).
+		Order("clock_in_time").Find(&earlier).Error; err != nil {
+		return fmt.Errorf("error fetching clock records: %w", err)
+	}
+	for _, e := range earlier {
+		split := hoursSplit{Regular: e.RegularHours, Double: e.DoubleTimeHours, Triple: e.TripleTimeHours}
+		if e.TimePolicyID == nil && split.Regular+split.overtime() == 0 {
+			// Closed before policies were applied
+			split.Regular = e.WorkedHours
+		}
+		start := e.ClockInTime
+		if e.RoundedClockIn != nil {
+			start = *e.RoundedClockIn
+		}
+		tally.seed(policyDay(start, loc), split)
+	}
+	split, overtimeViolations := tally.add(workDate, worked)
+	violations = append(violations, overtimeViolations...)
+
+	record.RoundedClockIn = &in
+	record.RoundedClockOut = &out
+	record.AutoBreakMinutes = autoBreak
+	record.WorkedHours = worked
+	record.RegularHours = split.Regular
+	record.DoubleTimeHours = split.Double
+	record.TripleTimeHours = split.Triple
+	record.TimePolicyID = nil
+	if policy.ID != uuid.Nil {
+		record.TimePolicyID = &policy.ID
+	}
+	if err := s.db.Model(record).Select("rounded_clock_in", "rounded_clock_out", "auto_break_minutes", "worked_hours",
+		"regular_hours", "double_time_hours", "triple_time_hours", "time_policy_id").Updates(record).Error; err != nil {
+		return fmt.Errorf("error saving clock record: %w", err)
+	}
+
+	if err := s.db.Where("clock_record_id = ? AND is_resolved = ?", record.ID, false).
+		Delete(&models.TimePolicyViolation{}).Error; err != nil {
+		return err
+	}
+	return s.saveViolations(record.CompanyID, record.EmployeeID, record.TimePolicyID, workDate, violations,
+		func(v *models.TimePolicyViolation) { v.ClockRecordID = &record.ID })
+}
+
+// ClassifyTimesheet sets a timesheet's regular/overtime totals from its
+// worked entries and stores the overtime limits it breaches
+func (s *TimePolicyService) ClassifyTimesheet(timesheet *models.Timesheet, entries []models.TimeEntry) error {
+	var employee models.Employee
+	if err := s.db.First(&employee, "id = ?", timesheet.EmployeeID).Error; err != nil {
+		return fmt.Errorf("error fetching employee: %w", err)
+	}
+	policy, _, err := s.ResolvePolicy(&employee)
+	if err != nil {
+		return err
+	}
+
+	workedByDay := make(map[string]float64)
+	days := make(map[string]time.Time)
+	for _, entry := range entries {
+		if entry.EntryType != models.TimeEntryTypeRegular && entry.EntryType != models.TimeEntryTypeOvertime {
+			continue
+		}
+		day := time.Date(entry.EntryDate.Year(), entry.EntryDate.Month(), entry.EntryDate.Day(), 0, 0, 0, 0, time.UTC)
+		key := day.Format("2006-01-02")
+		workedByDay[key] += entry.Hours
+		days[key] = day
+	}
+	keys := make([]string, 0, len(days))
+	for key := range days {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var policyID *uuid.UUID
+	if policy.ID != uuid.Nil {
+		policyID = &policy.ID
+	}
+	if timesheet.ID != uuid.Nil {
+		if err := s.db.Where("timesheet_id = ? AND is_resolved = ?", timesheet.ID, false).
+			Delete(&models.TimePolicyViolation{}).Error; err != nil {
+			return err
+		}
+	}
+
+	var total hoursSplit
+	tally := newOvertimeTally(policy)
+	for _, key := range keys {
+		split, violations := tally.add(days[key], workedByDay[key])
+		total.Regular += split.Regular
+		total.Double += split.Double
+		total.Triple += split.Triple
+		if timesheet.ID == uuid.Nil {
+			continue
+		}
+		if err := s.saveViolations(timesheet.CompanyID, timesheet.EmployeeID, policyID, days[key], violations,
+			func(v *models.TimePolicyViolation) { v.TimesheetID = &timesheet.ID }); err != nil {
+			return err
+		}
+	}
+
+	timesheet.TotalRegularHours = roundHours(total.Regular)
+	timesheet.TotalOvertimeHours = roundHours(total.overtime())
+	timesheet.TotalDoubleTimeHours = roundHours(total.Double)
+	timesheet.TotalTripleTimeHours = roundHours(total.Triple)
+	return nil
+}
+
+// saveViolations stores the violations found for one workday
+func (s *TimePolicyService) saveViolations(companyID, employeeID uuid.UUID, policyID *uuid.UUID, workDate time.Time,
+	violations []policyViolation, source func(*models.TimePolicyViolation)) error {
+	for _, v := range violations {
+		violation := models.TimePolicyViolation{
+			CompanyID:    companyID,
+			EmployeeID:   employeeID,
+			TimePolicyID: policyID,
+			WorkDate:     workDate,
+			Type:         v.Type,
+			Severity:     v.Severity,
+			Message:      v.Message,
+			Value:        v.Value,
+		}
+		source(&violation)
+		if err := s.db.Create(&violation).Error; err != nil {
+			return fmt.Errorf("error saving time policy violation: %w", err)
+		}
+	}
+	return nil
+}
+
+// companyLocation returns the company's time zone
+func (s *TimePolicyService) companyLocation(companyID uuid.UUID) *time.Location {
+	var company models.Company
+	if err := s.db.Select("id", "timezone").First(&company, "id = ?", companyID).Error; err != nil {
+		return time.Local
+	}
+	return company.Location()
+}
+
+// roundToInterval rounds t to the nearest interval of the local clock
+func roundToInterval(t time.Time, minutes int, loc *time.Location) time.Time {
+	if minutes <= 0 {
+		return t
+	}
+	local := t.In(loc)
+	midnight := time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, loc)
+	return midnight.Add(local.Sub(midnight).Round(time.Duration(minutes) * time.Minute))
+}
+
+// policyDay is the calendar day of t in loc, as a UTC date
+func policyDay(t time.Time, loc *time.Location) time.Time {
+	local := t.In(loc)
+	return time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+// policyWeekStart returns the Monday of day's week
+func policyWeekStart(day time.Time) time.Time {
+	offset := (int(day.Weekday()) + 6) % 7
+	return day.AddDate(0, 0, -offset)
+}
+
+// remainingHours is what is left of a limit (0 = no limit)
+func remainingHours(limit, used float64) float64 {
+	if limit <= 0 {
+		return math.Inf(1)
+	}
+	return math.Max(limit-used, 0)
+}
+
+func roundHours(hours float64) float64 {
+	return math.Round(hours*100) / 100
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if strings.EqualFold(strings.TrimSpace(v), value) {
+			return true
+		}
+	}
+	return false
+}
+
+// =========================================================================
+// Policy catalog
+// =========================================================================
+
+// ListPolicies returns the company's time policies
+func (s *TimePolicyService) ListPolicies(companyID uuid.UUID) ([]models.TimePolicy, error) {
+	var policies []models.TimePolicy
+	if err := s.db.Where("company_id = ?", companyID).Order("is_default DESC, name").Find(&policies).Error; err != nil {
+		return nil, fmt.Errorf("error fetching time policies: %w", err)
+	}
+	return policies, nil
+}
+
+// CreatePolicy creates a time policy
+func (s *TimePolicyService) CreatePolicy(companyID uuid.UUID, req dtos.TimePolicyRequest, createdBy uuid.UUID) (*models.TimePolicy, error) {
+	policy := &models.TimePolicy{CompanyID: companyID, IsActive: true, CreatedByID: &createdBy}
+	applyTimePolicyRequest(policy, req)
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		if err := clearDefaultPolicy(tx, policy); err != nil {
+			return err
+		}
+		if err := tx.Create(policy).Error; err != nil {
+			return err
+		}
+		// Create replaces zero values (no rounding, no limit) with the column
+		// defaults; write the request back as sent
+		applyTimePolicyRequest(policy, req)
+		return tx.Save(policy).Error
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error creating time policy: %w", err)
+	}
+	return policy, nil
+}
+
+// UpdatePolicy updates a time policy
+func (s *TimePolicyService) UpdatePolicy(id, companyID uuid.UUID, req dtos.TimePolicyRequest) (*models.TimePolicy, error) {
+	var policy models.TimePolicy
+	if err := s.db.Where("id = ? AND company_id = ?", id, companyID).First(&policy).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("time policy not found")
+		}
+		return nil, err
+	}
+	applyTimePolicyRequest(&policy, req)
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		if err := clearDefaultPolicy(tx, &policy); err != nil {
+			return err
+		}
+		return tx.Save(&policy).Error
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error updating time policy: %w", err)
+	}
+	return &policy, nil
+}
+
+// DeletePolicy deletes a time policy; records keep their classification
+func (s *TimePolicyService) DeletePolicy(id, companyID uuid.UUID) error {
+	result := s.db.Where("id = ? AND company_id = ?", id, companyID).Delete(&models.TimePolicy{})
+	if result.Error != nil {
+		return fmt.Errorf("error deleting time policy: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return errors.New("time policy not found")
+	}
+	return nil
+}
+
+func applyTimePolicyRequest(policy *models.TimePolicy, req dtos.TimePolicyRequest) {
+	policy.Name = strings.TrimSpace(req.Name)
+	policy.Description = req.Description
+	policy.StandardWeeklyHours = req.StandardWeeklyHours
+	policy.StandardDailyHours = req.StandardDailyHours
+	policy.OvertimeThresholdDaily = req.OvertimeThresholdDaily
+	policy.OvertimeThresholdWeekly = req.OvertimeThresholdWeekly
+	policy.OvertimeMultiplier = req.OvertimeMultiplier
+	policy.DoubleTimeMultiplier = req.OvertimeMultiplier
+	policy.TripleTimeMultiplier = req.TripleTimeMultiplier
+	policy.WeeklyDoubleTimeHours = req.WeeklyDoubleTimeHours
+	policy.MaxDailyOvertimeHours = req.MaxDailyOvertimeHours
+	policy.MaxOvertimeDaysPerWeek = req.MaxOvertimeDaysPerWeek
+	policy.RequiredBreakAfterHours = req.RequiredBreakAfterHours
+	policy.MinBreakMinutes = req.MinBreakMinutes
+	policy.DisableAutoBreak = req.DisableAutoBreak
+	policy.RoundingIntervalMinutes = req.RoundingIntervalMinutes
+	policy.TimeBankMaxHours = req.TimeBankMaxHours
+	policy.TimeBankExpiryDays = req.TimeBankExpiryDays
+	policy.ApplicableRoles = req.ApplicableRoles
+	policy.ApplicableCollarTypes = req.ApplicableCollarTypes
+	policy.IsDefault = req.IsDefault
+	if req.IsActive != nil {
+		policy.IsActive = *req.IsActive
+	}
+}
+
+// clearDefaultPolicy keeps a single default policy per company
+func clearDefaultPolicy(tx *gorm.DB, policy *models.TimePolicy) error {
+	if !policy.IsDefault {
+		return nil
+	}
+	return tx.Model(&models.TimePolicy{}).
+		Where("company_id = ? AND is_default = ? AND id <> ?", policy.CompanyID, true, policy.ID).
+		Update("is_default", false).Error
+}
+
+// =========================================================================
+// Violations
+// =========================================================================
+
+// ListViolations returns the company's violations, newest first
+func (s *TimePolicyService) ListViolations(companyID uuid.UUID, filter dtos.TimePolicyViolationFilter) ([]models.TimePolicyViolation, error) {
+	query := s.db.Preload("Employee").Where("company_id = ?", companyID)
+	if filter.EmployeeID != nil {
+		query = query.Where("employee_id = ?", *filter.EmployeeID)
+	}
+	if filter.Type != "" {
+		query = query.Where("type = ?", filter.Type)
+	}
+	if filter.Resolved != nil {
+		query = query.Where("is_resolved = ?", *filter.Resolved)
+	}
+	if filter.StartDate != nil {
+		query = query.Where("work_date >= ?", *filter.StartDate)
+	}
+	if filter.EndDate != nil {
+		query = query.Where("work_date < ?", filter.EndDate.AddDate(0, 0, 1))
+	}
+	var violations []models.TimePolicyViolation
+	if err := query.Order("work_date DESC, created_at DESC").Find(&violations).Error; err != nil {
+		return nil, fmt.Errorf("error fetching time policy violations: %w", err)
+	}
+	return violations, nil
+}
+
+// Dashboard summarizes the violations of a period
+func (s *TimePolicyService) Dashboard(companyID uuid.UUID, start, end time.Time) (*dtos.TimePolicyDashboard, error) {
+	violations, err := s.ListViolations(companyID, dtos.TimePolicyViolationFilter{StartDate: &start, EndDate: &end})
+	if err != nil {
+		return nil, err
+	}
+	dashboard := &dtos.TimePolicyDashboard{
+		StartDate:    start,
+		EndDate:      end,
+		Total:        len(violations),
+		ByType:       make(map[string]int),
+		BySeverity:   make(map[string]int),
+		TopEmployees: []dtos.TimePolicyEmployeeViolations{},
+		Recent:       []models.TimePolicyViolation{},
+	}
+	byEmployee := make(map[uuid.UUID]*dtos.TimePolicyEmployeeViolations)
+	for _, v := range violations {
+		dashboard.ByType[v.Type]++
+		dashboard.BySeverity[v.Severity]++
+		row, ok := byEmployee[v.EmployeeID]
+		if !ok {
+			row = &dtos.TimePolicyEmployeeViolations{EmployeeID: v.EmployeeID}
+			if v.Employee != nil {
+				row.EmployeeNumber = v.Employee.EmployeeNumber
+				row.EmployeeName = strings.TrimSpace(v.Employee.FirstName + " " + v.Employee.LastName)
+			}
+			byEmployee[v.EmployeeID] = row
+		}
+		row.Violations++
+		if !v.IsResolved {
+			row.Unresolved++
+			dashboard.Unresolved++
+			if len(dashboard.Recent) < 20 {
+				dashboard.Recent = append(dashboard.Recent, v)
+			}
+		}
+	}
+	for _, row := range byEmployee {
+		dashboard.TopEmployees = append(dashboard.TopEmployees, *row)
+	}
+	sort.Slice(dashboard.TopEmployees, func(i, j int) bool {
+		a, b := dashboard.TopEmployees[i], dashboard.TopEmployees[j]
+		if a.Violations != b.Violations {
+			return a.Violations > b.Violations
+		}
+		return a.EmployeeNumber < b.EmployeeNumber
+	})
+	if len(dashboard.TopEmployees) > 10 {
+		dashboard.TopEmployees = dashboard.TopEmployees[:10]
+	}
+	return dashboard, nil
+}
+
+// ResolveViolation closes a violation with HR's notes
+func (s *TimePolicyService) ResolveViolation(id, companyID uuid.UUID, notes string, resolvedBy uuid.UUID) (*models.TimePolicyViolation, error) {
+	var violation models.TimePolicyViolation
+	if err := s.db.Where("id = ? AND company_id = ?", id, companyID).First(&violation).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("violation not found")
+		}
+		return nil, err
+	}
+	if violation.IsResolved {
+		return nil, errors.New("violation cannot be resolved again")
+	}
+	now := time.Now()
+	violation.IsResolved = true
+	violation.ResolvedBy = &resolvedBy
+	violation.ResolvedAt = &now
+	violation.ResolutionNotes = notes
+	if err := s.db.Save(&violation).Error; err != nil {
+		return nil, fmt.Errorf("error resolving violation: %w", err)
+	}
+	return &violation, nil
+}
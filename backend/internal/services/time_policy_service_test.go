@@ -0,0 +1,113 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"backend/internal/dtos"
+	"backend/internal/models"
+)
+
+func TestTimePolicy_RoundingBreaksAndMexicanOvertime(t *testing.T) {
+	db := setupPayrollTestDB(t)
+	require.NoError(t, db.AutoMigrate(&models.User{}, &models.ClockRecord{}, &models.TimePolicy{}, &models.TimePolicyViolation{}))
+	company := createPayrollTestCompany(t, db)
+	loc, err := time.LoadLocation("America/Mexico_City")
+	require.NoError(t, err)
+	service := NewTimePolicyService(db)
+
+	operator := createFixtureEmployee(t, db, company.ID, 1, "general", 400)
+	analyst := createFixtureEmployee(t, db, company.ID, 2, "general", 900)
+	require.NoError(t, db.Model(analyst).Update("collar_type", "white_collar").Error)
+	createFixtureUser(t, db, analyst, "employee")
+
+	base := dtos.TimePolicyRequest{
+		StandardWeeklyHours: 48, StandardDailyHours: 8, OvertimeThresholdDaily: 8, OvertimeThresholdWeekly: 48,
+		OvertimeMultiplier: 2, TripleTimeMultiplier: 3, WeeklyDoubleTimeHours: 9, MaxDailyOvertimeHours: 3,
+		MaxOvertimeDaysPerWeek: 3, RequiredBreakAfterHours: 6, MinBreakMinutes: 30,
+	}
+	plant := base
+	plant.Name, plant.RoundingIntervalMinutes, plant.ApplicableCollarTypes = "Planta", 15, []string{"blue_collar"}
+	_, err = service.CreatePolicy(company.ID, plant, analyst.ID)
+	require.NoError(t, err)
+	office := base
+	office.Name, office.IsDefault = "Oficina", true
+	_, err = service.CreatePolicy(company.ID, office, analyst.ID)
+	require.NoError(t, err)
+
+	effective, err := service.EffectivePolicy(company.ID, operator.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "collar_type", effective.MatchedBy)
+	assert.Equal(t, 15, effective.Policy.RoundingIntervalMinutes)
+	effective, err = service.EffectivePolicy(company.ID, analyst.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "default", effective.MatchedBy)
+	assert.Equal(t, 0, effective.Policy.RoundingIntervalMinutes, "zero rounding must not fall back to the column default")
+
+	closeRecord := func(employee *models.Employee, day, inH, inM, outH, outM, breakMinutes int) models.ClockRecord {
+		out := time.Date(2025, 1, day, outH, outM, 0, 0, loc)
+		record := models.ClockRecord{
+			CompanyID: company.ID, EmployeeID: employee.ID, ClockInTime: time.Date(2025, 1, day, inH, inM, 0, 0, loc),
+			ClockOutTime: &out, BreakMinutes: breakMinutes, IsComplete: true, Status: "completed",
+		}
+		require.NoError(t, db.Create(&record).Error)
+		require.NoError(t, service.ApplyToClockRecord(&record))
+		return record
+	}
+
+	// Operator, Mon-Fri 07:58-19:07 with a 1h break: rounded to 08:00-19:00, 10h a day
+	var week []models.ClockRecord
+	for day := 6; day <= 10; day++ {
+		week = append(week, closeRecord(operator, day, 7, 58, 19, 7, 60))
+	}
+	assert.Equal(t, time.Date(2025, 1, 6, 8, 0, 0, 0, loc), week[0].RoundedClockIn.In(loc))
+	assert.Equal(t, time.Date(2025, 1, 6, 19, 0, 0, 0, loc), week[0].RoundedClockOut.In(loc))
+	for i, record := range week[:4] {
+		assert.Equal(t, 10.0, record.WorkedHours)
+		assert.Equal(t, 8.0, record.RegularHours)
+		assert.Equal(t, 2.0, record.DoubleTimeHours, "day %d", i)
+	}
+	// Friday goes past the 9 weekly hours of double time
+	assert.Equal(t, 1.0, week[4].DoubleTimeHours)
+	assert.Equal(t, 1.0, week[4].TripleTimeHours)
+
+	// Analyst works 09:00-16:00 with a 10-minute break: 20 minutes are deducted
+	short := closeRecord(analyst, 6, 9, 0, 16, 0, 10)
+	assert.Equal(t, 20, short.AutoBreakMinutes)
+	assert.Equal(t, 6.5, short.WorkedHours)
+
+	// Re-applying a record replaces its violations instead of duplicating them
+	require.NoError(t, service.ApplyToClockRecord(&week[4]))
+
+	dashboard, err := service.Dashboard(company.ID, time.Date(2025, 1, 6, 0, 0, 0, 0, time.UTC), time.Date(2025, 1, 12, 0, 0, 0, 0, time.UTC))
+	require.NoError(t, err)
+	assert.Equal(t, 1, dashboard.ByType[models.ViolationMissingBreak])
+	assert.Equal(t, 2, dashboard.ByType[models.ViolationOvertimeDaysLimit]) // Thursday and Friday
+	assert.Equal(t, 1, dashboard.ByType[models.ViolationWeeklyOvertimeLimit])
+	assert.Equal(t, 0, dashboard.ByType[models.ViolationDailyOvertimeLimit])
+	assert.Equal(t, 1, dashboard.BySeverity[models.ViolationSeverityCritical])
+	require.NotEmpty(t, dashboard.TopEmployees)
+	assert.Equal(t, operator.EmployeeNumber, dashboard.TopEmployees[0].EmployeeNumber)
+
+	violation, err := service.ResolveViolation(dashboard.Recent[0].ID, company.ID, "Autorizado por gerencia", analyst.ID)
+	require.NoError(t, err)
+	assert.True(t, violation.IsResolved)
+	_, err = service.ResolveViolation(violation.ID, company.ID, "otra vez", analyst.ID)
+	assert.Error(t, err)
+
+	// Timesheet totals: five 11h days are 40 regular, 9 double and 6 triple
+	timesheet := &models.Timesheet{CompanyID: company.ID, EmployeeID: operator.ID}
+	var entries []models.TimeEntry
+	for day := 13; day <= 17; day++ {
+		entries = append(entries, models.TimeEntry{EntryDate: time.Date(2025, 1, day, 0, 0, 0, 0, time.UTC), Hours: 11, EntryType: models.TimeEntryTypeRegular})
+	}
+	entries = append(entries, models.TimeEntry{EntryDate: time.Date(2025, 1, 17, 0, 0, 0, 0, time.UTC), Hours: 8, EntryType: models.TimeEntryTypePTO})
+	require.NoError(t, service.ClassifyTimesheet(timesheet, entries))
+	assert.Equal(t, 40.0, timesheet.TotalRegularHours)
+	assert.Equal(t, 15.0, timesheet.TotalOvertimeHours)
+	assert.Equal(t, 9.0, timesheet.TotalDoubleTimeHours)
+	assert.Equal(t, 6.0, timesheet.TotalTripleTimeHours)
+}
@@ -0,0 +1,423 @@
+/*
+Package services - Timesheet Period Service
+
+==============================================================================
+FILE: internal/services/timesheet_period_service.go
+==============================================================================
+
+DESCRIPTION:
+    Runs the timesheet cycle on the payroll calendar. Generates one timesheet
+    per project-tracked employee for each open payroll period of their pay
+    frequency, reminds employees whose timesheet is still a draft as the
+    prenómina cutoff approaches, reports billable vs. non-billable
+    utilization per Project and ProjectMember rate, and sends the approved
+    hours of hourly employees to prenómina.
+
+USER PERSPECTIVE:
+    - Nobody creates timesheets by hand: they appear when the period opens,
+      with the hours already captured in the period attached
+    - Two days before the cutoff, employees with a draft get a reminder
+    - Approved timesheets are locked; new hours go to the next period
+    - Payroll exports the approved hours and prenómina pays them
+
+DEVELOPER GUIDELINES:
+    ✅  OK to modify: Reminder window, utilization columns
+    ⚠️  CAUTION: Exported timesheets (status processed) set RegularHours in
+        PrenominaService for hourly employees; overtime keeps coming from
+        the attendance evaluation so it is not paid twice
+    ⚠️  CAUTION: GenerateTimesheets and SendReminders are idempotent and are
+        meant to be called daily by a scheduler or by hand
+    📝  Hourly employees are the blue- and gray-collar ones (isBlueOrGrayCollar)
+
+SYNTAX EXPLANATION:
+    - Project-tracked: active member of an active project of the company
+      whose membership dates overlap the period
+    - SubmissionDeadline: the period's PrenominaCutoffDate, else its EndDate
+    - Utilization: billable / (billable + non-billable) hours, in percent;
+      an entry is billable when both the entry and its project are
+    - Billable amount: hours × ProjectMember.HourlyRate (the entry's own
+      HourlyRate when the member has none)
+
+==============================================================================
+*/
+package services
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"backend/internal/dtos"
+	"backend/internal/models"
+)
+
+// timesheetReminderDays is how many days before the deadline reminders start
+const timesheetReminderDays = 2
+
+// TimesheetPeriodService generates, reminds, reports and exports timesheets by payroll period
+type TimesheetPeriodService struct {
+	db           *gorm.DB
+	timeTracking *TimeTrackingService
+}
+
+// NewTimesheetPeriodService creates a new timesheet period service
+func NewTimesheetPeriodService(db *gorm.DB) *TimesheetPeriodService {
+	return &TimesheetPeriodService{db: db, timeTracking: NewTimeTrackingService(db)}
+}
+
+// GenerateTimesheets creates the missing timesheets of the selected payroll periods
+func (s *TimesheetPeriodService) GenerateTimesheets(companyID uuid.UUID, req dtos.TimesheetGenerateRequest) (*dtos.TimesheetGenerationResult, error) {
+	var periods []models.PayrollPeriod
+	if req.PayrollPeriodID != nil {
+		var period models.PayrollPeriod
+		if err := s.db.First(&period, "id = ?", *req.PayrollPeriodID).Error; err != nil {
+			return nil, errors.New("payroll period not found")
+		}
+		if !period.IsOpen() {
+			return nil, errors.New("payroll period must be open to generate timesheets")
+		}
+		periods = append(periods, period)
+	} else {
+		date := truncateToDate(time.Now())
+		if req.Date != nil {
+			date = truncateToDate(req.Date.Time)
+		}
+		if err := s.db.Where("status = ? AND start_date <= ? AND end_date >= ?", "open", date, date).
+			Order("start_date").Find(&periods).Error; err != nil {
+			return nil, fmt.Errorf("error fetching payroll periods: %w", err)
+		}
+	}
+
+	result := &dtos.TimesheetGenerationResult{Periods: len(periods)}
+	for i := range periods {
+		if err := s.generateForPeriod(companyID, &periods[i], result); err != nil {
+			return nil, err
+		}
+	}
+	return result, nil
+}
+
+// generateForPeriod creates one period's timesheets for its project-tracked employees
+func (s *TimesheetPeriodService) generateForPeriod(companyID uuid.UUID, period *models.PayrollPeriod, result *dtos.TimesheetGenerationResult) error {
+	start, end := truncateToDate(period.StartDate), truncateToDate(period.EndDate)
+	var memberIDs []uuid.UUID
+	if err := s.db.Model(&models.ProjectMember{}).
+		Joins("JOIN time_projects ON time_projects.id = time_project_members.project_id").
+		Where("time_projects.company_id = ? AND time_projects.is_active = ? AND time_project_members.is_active = ?", companyID, true, true).
+		Where("time_project_members.start_date IS NULL OR time_project_members.start_date <= ?", end).
+		Where("time_project_members.end_date IS NULL OR time_project_members.end_date >= ?", start).
+		Distinct().Pluck("time_project_members.employee_id", &memberIDs).Error; err != nil {
+		return fmt.Errorf("error fetching project members: %w", err)
+	}
+	if len(memberIDs) == 0 {
+		return nil
+	}
+	var employees []models.Employee
+	if err := s.db.Where("id IN ? AND company_id = ? AND employment_status = ? AND pay_frequency = ?",
+		memberIDs, companyID, "active", period.Frequency).Find(&employees).Error; err != nil {
+		return fmt.Errorf("error fetching employees: %w", err)
+	}
+
+	deadline := end
+	if period.PrenominaCutoffDate != nil {
+		deadline = truncateToDate(*period.PrenominaCutoffDate)
+	}
+	var attachedTo []*models.Timesheet
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		for _, employee := range employees {
+			var existing models.Timesheet
+			err := tx.Where("employee_id = ? AND period_start = ? AND period_end = ?", employee.ID, start, end).First(&existing).Error
+			if err == nil {
+				if existing.PayrollPeriodID == nil {
+					if err := tx.Model(&existing).Updates(map[string]interface{}{"payroll_period_id": period.ID, "submission_deadline": deadline}).Error; err != nil {
+						return err
+					}
+				}
+				result.Existing++
+				continue
+			}
+			if !errors.Is(err, gorm.ErrRecordNotFound) {
+				return err
+			}
+
+			timesheet := &models.Timesheet{
+				CompanyID:          companyID,
+				EmployeeID:         employee.ID,
+				PeriodStart:        start,
+				PeriodEnd:          end,
+				PayrollPeriodID:    &period.ID,
+				Status:             models.TimesheetStatusDraft,
+				AutoGenerated:      true,
+				SubmissionDeadline: &deadline,
+			}
+			if err := tx.Create(timesheet).Error; err != nil {
+				return fmt.Errorf("error creating timesheet: %w", err)
+			}
+			attached := tx.Model(&models.TimeEntry{}).
+				Where("employee_id = ? AND timesheet_id IS NULL AND entry_date >= ? AND entry_date < ?", employee.ID, start, end.AddDate(0, 0, 1)).
+				Update("timesheet_id", timesheet.ID)
+			if attached.Error != nil {
+				return attached.Error
+			}
+			if attached.RowsAffected > 0 {
+				attachedTo = append(attachedTo, timesheet)
+			}
+			result.Created++
+			result.Entries += int(attached.RowsAffected)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	// Totals are computed once the attached entries are committed
+	for _, timesheet := range attachedTo {
+		s.timeTracking.calculateTimesheetTotals(timesheet)
+		if err := s.db.Save(timesheet).Error; err != nil {
+			return fmt.Errorf("error updating timesheet totals: %w", err)
+		}
+	}
+	return nil
+}
+
+// SendReminders notifies employees whose draft timesheet is due within
+// timesheetReminderDays, at most once a day
+func (s *TimesheetPeriodService) SendReminders(companyID, actorID uuid.UUID, now time.Time) (*dtos.TimesheetReminderResult, error) {
+	var timesheets []models.Timesheet
+	if err := s.db.Preload("Employee").
+		Where("company_id = ? AND status = ? AND submission_deadline IS NOT NULL", companyID, models.TimesheetStatusDraft).
+		Find(&timesheets).Error; err != nil {
+		return nil, fmt.Errorf("error fetching timesheets: %w", err)
+	}
+
+	today := now.Format("2006-01-02")
+	windowEnd := now.AddDate(0, 0, timesheetReminderDays).Format("2006-01-02")
+	result := &dtos.TimesheetReminderResult{}
+	for i := range timesheets {
+		ts := &timesheets[i]
+		due := ts.SubmissionDeadline.Format("2006-01-02")
+		if due < today || due > windowEnd {
+			continue
+		}
+		if ts.ReminderSentAt != nil && ts.ReminderSentAt.Format("2006-01-02") == today {
+			continue
+		}
+		var user models.User
+		if err := s.db.Where("employee_id = ? AND is_active = ?", ts.EmployeeID, true).
+			Limit(1).Find(&user).Error; err != nil || user.ID == uuid.Nil {
+			result.WithoutUser++
+			continue
+		}
+		userID := user.ID
+		err := s.db.Transaction(func(tx *gorm.DB) error {
+			if err := tx.Create(&models.Notification{
+				CompanyID:    companyID,
+				ActorUserID:  actorID,
+				TargetUserID: &userID,
+				Type:         models.NotificationTimesheetReminder,
+				Title:        "Hoja de horas pendiente",
+				Message: fmt.Sprintf("Envía tu hoja de horas del %s al %s antes del %s",
+					ts.PeriodStart.Format("02/01"), ts.PeriodEnd.Format("02/01"), ts.SubmissionDeadline.Format("02/01/2006")),
+				ResourceType: "timesheet",
+				ResourceID:   &ts.ID,
+			}).Error; err != nil {
+				return err
+			}
+			return tx.Model(ts).Update("reminder_sent_at", now).Error
+		})
+		if err != nil {
+			return nil, fmt.Errorf("error sending timesheet reminder: %w", err)
+		}
+		result.Reminded++
+	}
+	return result, nil
+}
+
+// UtilizationReport sums billable and non-billable project hours per project and member
+func (s *TimesheetPeriodService) UtilizationReport(companyID uuid.UUID, filter dtos.UtilizationFilter) (*dtos.UtilizationReport, error) {
+	start, end := truncateToDate(filter.StartDate), truncateToDate(filter.EndDate)
+	if end.Before(start) {
+		return nil, errors.New("end_date must not be before start_date")
+	}
+	query := s.db.Preload("Employee").
+		Where("company_id = ? AND project_id IS NOT NULL AND entry_date >= ? AND entry_date < ?", companyID, start, end.AddDate(0, 0, 1))
+	if filter.ProjectID != nil {
+		query = query.Where("project_id = ?", *filter.ProjectID)
+	}
+	if filter.ApprovedOnly {
+		query = query.Where("timesheet_id IN (?)", approvedTimesheetIDs(s.db))
+	}
+	var entries []models.TimeEntry
+	if err := query.Find(&entries).Error; err != nil {
+		return nil, fmt.Errorf("error fetching time entries: %w", err)
+	}
+
+	projectIDs := make([]uuid.UUID, 0)
+	seen := map[uuid.UUID]bool{}
+	for _, e := range entries {
+		if !seen[*e.ProjectID] {
+			seen[*e.ProjectID] = true
+			projectIDs = append(projectIDs, *e.ProjectID)
+		}
+	}
+	var projects []models.Project
+	var members []models.ProjectMember
+	if len(projectIDs) > 0 {
+		if err := s.db.Where("id IN ?", projectIDs).Find(&projects).Error; err != nil {
+			return nil, fmt.Errorf("error fetching projects: %w", err)
+		}
+		if err := s.db.Where("project_id IN ?", projectIDs).Find(&members).Error; err != nil {
+			return nil, fmt.Errorf("error fetching project members: %w", err)
+		}
+	}
+	memberOf := map[[2]uuid.UUID]*models.ProjectMember{}
+	for i := range members {
+		memberOf[[2]uuid.UUID{members[i].ProjectID, members[i].EmployeeID}] = &members[i]
+	}
+
+	rows := map[uuid.UUID]*dtos.ProjectUtilization{}
+	for _, p := range projects {
+		rows[p.ID] = &dtos.ProjectUtilization{
+			ProjectID: p.ID, Code: p.Code, Name: p.Name, ClientName: p.ClientName,
+			BudgetHours: p.BudgetHours, BudgetAmount: p.BudgetAmount, Members: []dtos.MemberUtilization{},
+		}
+	}
+	billableProject := map[uuid.UUID]bool{}
+	for _, p := range projects {
+		billableProject[p.ID] = p.IsBillable
+	}
+	memberRows := map[[2]uuid.UUID]*dtos.MemberUtilization{}
+	for _, e := range entries {
+		row := rows[*e.ProjectID]
+		if row == nil {
+			continue
+		}
+		key := [2]uuid.UUID{*e.ProjectID, e.EmployeeID}
+		m := memberRows[key]
+		if m == nil {
+			m = &dtos.MemberUtilization{EmployeeID: e.EmployeeID}
+			if e.Employee != nil {
+				m.EmployeeNumber = e.Employee.EmployeeNumber
+				m.EmployeeName = strings.TrimSpace(e.Employee.FirstName + " " + e.Employee.LastName)
+			}
+			if member := memberOf[key]; member != nil {
+				m.Role, m.HourlyRate = member.Role, member.HourlyRate
+			}
+			memberRows[key] = m
+		}
+		if e.IsBillable && billableProject[*e.ProjectID] {
+			rate := m.HourlyRate
+			if rate == 0 {
+				rate = e.HourlyRate
+			}
+			m.BillableHours += e.Hours
+			m.BillableAmount += e.Hours * rate
+		} else {
+			m.NonBillableHours += e.Hours
+		}
+	}
+
+	report := &dtos.UtilizationReport{StartDate: start, EndDate: end, Projects: []dtos.ProjectUtilization{}}
+	for key, m := range memberRows {
+		m.BillableHours, m.NonBillableHours = roundHours(m.BillableHours), roundHours(m.NonBillableHours)
+		m.BillableAmount = roundCurrency(m.BillableAmount)
+		m.Utilization = utilizationPercent(m.BillableHours, m.NonBillableHours)
+		row := rows[key[0]]
+		row.BillableHours += m.BillableHours
+		row.NonBillableHours += m.NonBillableHours
+		row.BillableAmount += m.BillableAmount
+		row.Members = append(row.Members, *m)
+	}
+	for _, row := range rows {
+		row.BillableHours, row.NonBillableHours = roundHours(row.BillableHours), roundHours(row.NonBillableHours)
+		row.BillableAmount = roundCurrency(row.BillableAmount)
+		row.TotalHours = roundHours(row.BillableHours + row.NonBillableHours)
+		row.Utilization = utilizationPercent(row.BillableHours, row.NonBillableHours)
+		sort.Slice(row.Members, func(i, j int) bool { return row.Members[i].EmployeeNumber < row.Members[j].EmployeeNumber })
+		report.BillableHours += row.BillableHours
+		report.NonBillableHours += row.NonBillableHours
+		report.BillableAmount += row.BillableAmount
+		report.Projects = append(report.Projects, *row)
+	}
+	sort.Slice(report.Projects, func(i, j int) bool { return report.Projects[i].Code < report.Projects[j].Code })
+	report.BillableHours, report.NonBillableHours = roundHours(report.BillableHours), roundHours(report.NonBillableHours)
+	report.BillableAmount = roundCurrency(report.BillableAmount)
+	report.Utilization = utilizationPercent(report.BillableHours, report.NonBillableHours)
+	return report, nil
+}
+
+// utilizationPercent is the billable share of the hours, in percent
+func utilizationPercent(billable, nonBillable float64) float64 {
+	if billable+nonBillable == 0 {
+		return 0
+	}
+	return roundCurrency(billable / (billable + nonBillable) * 100)
+}
+
+// ExportToPrenomina marks the approved timesheets of hourly employees in a
+// payroll period as processed, so the prenómina pays their hours
+func (s *TimesheetPeriodService) ExportToPrenomina(companyID uuid.UUID, req dtos.TimesheetExportRequest) (*dtos.TimesheetExportResult, error) {
+	var period models.PayrollPeriod
+	if err := s.db.First(&period, "id = ?", req.PayrollPeriodID).Error; err != nil {
+		return nil, errors.New("payroll period not found")
+	}
+	if !period.IsOpen() && period.Status != "calculated" {
+		return nil, errors.New("payroll period must be open to export timesheets")
+	}
+	var timesheets []models.Timesheet
+	if err := s.db.Preload("Employee").Where("company_id = ? AND payroll_period_id = ?", companyID, period.ID).
+		Order("period_start").Find(&timesheets).Error; err != nil {
+		return nil, fmt.Errorf("error fetching timesheets: %w", err)
+	}
+
+	result := &dtos.TimesheetExportResult{
+		PayrollPeriodID: period.ID,
+		Exported:        []dtos.TimesheetExportLine{},
+		Skipped:         []dtos.TimesheetExportLine{},
+	}
+	now := time.Now()
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		for i := range timesheets {
+			ts := &timesheets[i]
+			line := dtos.TimesheetExportLine{
+				TimesheetID:   ts.ID,
+				EmployeeID:    ts.EmployeeID,
+				RegularHours:  ts.TotalRegularHours,
+				OvertimeHours: ts.TotalOvertimeHours,
+			}
+			if ts.Employee != nil {
+				line.EmployeeNumber = ts.Employee.EmployeeNumber
+				line.EmployeeName = strings.TrimSpace(ts.Employee.FirstName + " " + ts.Employee.LastName)
+			}
+			switch {
+			case ts.Status != models.TimesheetStatusApproved && ts.Status != models.TimesheetStatusProcessed:
+				line.Reason = "timesheet is not approved"
+			case ts.Employee == nil || !isBlueOrGrayCollar(ts.Employee):
+				line.Reason = "salaried employee: pay does not come from timesheet hours"
+			}
+			if line.Reason != "" {
+				result.Skipped = append(result.Skipped, line)
+				continue
+			}
+			if ts.Status == models.TimesheetStatusApproved {
+				if err := tx.Model(ts).Updates(map[string]interface{}{
+					"status":       models.TimesheetStatusProcessed,
+					"processed_at": now,
+				}).Error; err != nil {
+					return err
+				}
+			}
+			result.Exported = append(result.Exported, line)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error exporting timesheets: %w", err)
+	}
+	return result, nil
+}
@@ -0,0 +1,138 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"backend/internal/dtos"
+	"backend/internal/models"
+)
+
+func TestTimesheetPeriod_GenerateRemindLockAndExport(t *testing.T) {
+	db := setupPayrollTestDB(t)
+	require.NoError(t, db.AutoMigrate(
+		&models.User{}, &models.Notification{}, &models.TimePolicy{}, &models.TimePolicyViolation{},
+		&models.Project{}, &models.ProjectMember{}, &models.Timesheet{}, &models.TimeEntry{},
+	))
+	company := createPayrollTestCompany(t, db)
+	service := NewTimesheetPeriodService(db)
+	timeTracking := service.timeTracking
+
+	technician := createFixtureEmployee(t, db, company.ID, 1, "general", 400)
+	analyst := createFixtureEmployee(t, db, company.ID, 2, "general", 900)
+	require.NoError(t, db.Model(analyst).Update("collar_type", "white_collar").Error)
+	createFixtureEmployee(t, db, company.ID, 3, "general", 400) // not on any project
+	technicianUser := createFixtureUser(t, db, technician, "employee")
+	payroll := createFixtureUser(t, db, createFixtureEmployee(t, db, company.ID, 4, "general", 400), "payroll_staff")
+
+	project := &models.Project{CompanyID: company.ID, Name: "Implementación", Code: "IMP-01", IsBillable: true, IsActive: true}
+	require.NoError(t, db.Create(project).Error)
+	require.NoError(t, db.Create(&models.ProjectMember{ProjectID: project.ID, EmployeeID: technician.ID, Role: "developer", HourlyRate: 150, IsActive: true}).Error)
+	require.NoError(t, db.Create(&models.ProjectMember{ProjectID: project.ID, EmployeeID: analyst.ID, Role: "analyst", IsActive: true}).Error)
+
+	day := func(d int) time.Time { return time.Date(2026, 10, d, 0, 0, 0, 0, time.UTC) }
+	cutoff := day(17)
+	period := &models.PayrollPeriod{
+		PeriodCode: "2026-W42", PeriodType: "weekly", Frequency: "weekly", Year: 2026, PeriodNumber: 42,
+		StartDate: day(12), EndDate: day(18), PaymentDate: day(20), PrenominaCutoffDate: &cutoff, Status: "open",
+	}
+	require.NoError(t, db.Create(period).Error)
+
+	entry := func(employeeID uuid.UUID, d int, hours float64, billable bool, rate float64) (*models.TimeEntry, error) {
+		created, err := timeTracking.CreateTimeEntry(CreateTimeEntryDTO{
+			CompanyID: company.ID, EmployeeID: employeeID, EntryDate: day(d), Hours: hours,
+			IsBillable: billable, ProjectID: &project.ID,
+		})
+		if err == nil {
+			// is_billable defaults to true on insert
+			db.Model(created).Updates(map[string]interface{}{"is_billable": billable, "hourly_rate": rate})
+		}
+		return created, err
+	}
+
+	// Hours captured before the period's timesheet exists are attached on generation
+	_, err := entry(technician.ID, 13, 8, true, 0)
+	require.NoError(t, err)
+	_, err = entry(technician.ID, 14, 2, false, 0)
+	require.NoError(t, err)
+	_, err = entry(analyst.ID, 13, 5, true, 200)
+	require.NoError(t, err)
+
+	result, err := service.GenerateTimesheets(company.ID, dtos.TimesheetGenerateRequest{Date: &dtos.Date{Time: day(14)}})
+	require.NoError(t, err)
+	assert.Equal(t, 1, result.Periods)
+	assert.Equal(t, 2, result.Created)
+	assert.Equal(t, 3, result.Entries)
+	again, err := service.GenerateTimesheets(company.ID, dtos.TimesheetGenerateRequest{PayrollPeriodID: &period.ID})
+	require.NoError(t, err)
+	assert.Equal(t, 0, again.Created)
+	assert.Equal(t, 2, again.Existing)
+
+	var techSheet, analystSheet models.Timesheet
+	require.NoError(t, db.First(&techSheet, "employee_id = ?", technician.ID).Error)
+	require.NoError(t, db.First(&analystSheet, "employee_id = ?", analyst.ID).Error)
+	assert.True(t, techSheet.AutoGenerated)
+	assert.Equal(t, period.ID, *techSheet.PayrollPeriodID)
+	assert.Equal(t, "2026-10-17", techSheet.SubmissionDeadline.Format("2006-01-02"))
+	assert.Equal(t, 10.0, techSheet.TotalHours)
+
+	// New hours join the open timesheet of their date
+	later, err := entry(technician.ID, 15, 3, true, 0)
+	require.NoError(t, err)
+	require.NotNil(t, later.TimesheetID)
+	assert.Equal(t, techSheet.ID, *later.TimesheetID)
+
+	// Reminders: once a day, only to employees with a portal account
+	reminders, err := service.SendReminders(company.ID, payroll.ID, time.Date(2026, 10, 15, 9, 0, 0, 0, time.UTC))
+	require.NoError(t, err)
+	assert.Equal(t, 1, reminders.Reminded)
+	assert.Equal(t, 1, reminders.WithoutUser)
+	reminders, err = service.SendReminders(company.ID, payroll.ID, time.Date(2026, 10, 15, 18, 0, 0, 0, time.UTC))
+	require.NoError(t, err)
+	assert.Equal(t, 0, reminders.Reminded)
+	var notifications []models.Notification
+	require.NoError(t, db.Where("type = ?", models.NotificationTimesheetReminder).Find(&notifications).Error)
+	require.Len(t, notifications, 1)
+	assert.Equal(t, technicianUser.ID, *notifications[0].TargetUserID)
+
+	// Approved timesheets are locked
+	for _, id := range []uuid.UUID{techSheet.ID, analystSheet.ID} {
+		_, err = timeTracking.SubmitTimesheet(id, "")
+		require.NoError(t, err)
+		_, err = timeTracking.ApproveTimesheet(id, payroll.ID, "")
+		require.NoError(t, err)
+	}
+	_, err = entry(technician.ID, 16, 4, true, 0)
+	assert.ErrorIs(t, err, ErrTimesheetLocked)
+	_, err = timeTracking.CreateTimeEntry(CreateTimeEntryDTO{CompanyID: company.ID, EmployeeID: technician.ID,
+		TimesheetID: &techSheet.ID, EntryDate: day(12), Hours: 1})
+	assert.ErrorIs(t, err, ErrTimesheetLocked)
+
+	// Utilization: member rate first, then the entry's own rate
+	report, err := service.UtilizationReport(company.ID, dtos.UtilizationFilter{StartDate: day(12), EndDate: day(18), ApprovedOnly: true})
+	require.NoError(t, err)
+	require.Len(t, report.Projects, 1)
+	row := report.Projects[0]
+	assert.Equal(t, 16.0, row.BillableHours)
+	assert.Equal(t, 2.0, row.NonBillableHours)
+	assert.Equal(t, 88.89, row.Utilization)
+	assert.Equal(t, 2650.0, row.BillableAmount)
+	require.Len(t, row.Members, 2)
+	assert.Equal(t, 1650.0, row.Members[0].BillableAmount)
+	assert.Equal(t, 1000.0, row.Members[1].BillableAmount)
+
+	// Export: hourly employees go to prenómina, salaried ones are left out
+	exported, err := service.ExportToPrenomina(company.ID, dtos.TimesheetExportRequest{PayrollPeriodID: period.ID})
+	require.NoError(t, err)
+	require.Len(t, exported.Exported, 1)
+	assert.Equal(t, technician.ID, exported.Exported[0].EmployeeID)
+	require.Len(t, exported.Skipped, 1)
+	assert.Contains(t, exported.Skipped[0].Reason, "salaried")
+	require.NoError(t, db.First(&techSheet, "id = ?", techSheet.ID).Error)
+	assert.Equal(t, models.TimesheetStatusProcessed, techSheet.Status)
+	assert.NotNil(t, techSheet.ProcessedAt)
+}
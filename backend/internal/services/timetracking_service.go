@@ -9,6 +9,21 @@ DESCRIPTION:
     Business logic for Time Tracking including timesheets, time entries,
     projects, and time-off tracking.
 
+    Closed clock records and timesheet totals go through TimePolicyService
+    (rounding, break deduction, regular / double / triple split).
+
+    Web/app clock-in and clock-out go through WorkSiteService.checkPunch:
+    the punch is placed against the employee's work site and trusted device
+    and flagged for review (or rejected) when it does not match.
+
+    Approved and exported timesheets are locked: CreateTimeEntry rejects
+    entries for them with ErrTimesheetLocked, and entries created without a
+    timesheet join the employee's open timesheet covering their date.
+
+    Vacation balances are not edited here: they are refreshed from the
+    vacation ledger (VacationLedgerService), and UpdateTimeOffBalance
+    refuses the "vacation" type with ErrVacationBalanceManaged.
+
 ==============================================================================
 */
 package services
@@ -16,6 +31,7 @@ package services
 import (
 	"backend/internal/models"
 	"errors"
+	"log"
 	"math"
 	"time"
 
@@ -23,14 +39,22 @@ import (
 	"gorm.io/gorm"
 )
 
+// ErrTimesheetLocked is returned when hours are added to an approved or exported timesheet
+var ErrTimesheetLocked = errors.New("timesheet is locked: approved hours cannot be edited")
+
+// ErrVacationBalanceManaged is returned when a vacation balance is edited by hand
+var ErrVacationBalanceManaged = errors.New("vacation balances come from the vacation ledger: post an adjustment instead")
+
 // TimeTrackingService provides business logic for time tracking
 type TimeTrackingService struct {
-	db *gorm.DB
+	db       *gorm.DB
+	policies *TimePolicyService
+	sites    *WorkSiteService
 }
 
 // NewTimeTrackingService creates a new TimeTrackingService
 func NewTimeTrackingService(db *gorm.DB) *TimeTrackingService {
-	return &TimeTrackingService{db: db}
+	return &TimeTrackingService{db: db, policies: NewTimePolicyService(db), sites: NewWorkSiteService(db)}
 }
 
 // === Project DTOs ===
@@ -390,6 +414,11 @@ func (s *TimeTrackingService) calculateTimesheetTotals(timesheet *models.Timeshe
 	timesheet.TotalRegularHours = regular
 	timesheet.TotalOvertimeHours = overtime
 	timesheet.TotalDoubleTimeHours = doubleTime
+	timesheet.TotalTripleTimeHours = 0
+	// Overrides the entry-type totals with the employee's policy split
+	if err := s.policies.ClassifyTimesheet(timesheet, entries); err != nil {
+		log.Printf("time policy not applied to timesheet %s: %v", timesheet.ID, err)
+	}
 	timesheet.TotalPTOHours = pto
 	timesheet.TotalHolidayHours = holiday
 	timesheet.TotalBillableHours = billable
@@ -456,6 +485,10 @@ func (s *TimeTrackingService) CreateTimeEntry(dto CreateTimeEntryDTO) (*models.T
 		entry.Hours = duration.Hours() - float64(entry.BreakMinutes)/60
 	}
 
+	if err := s.placeTimeEntry(entry); err != nil {
+		return nil, err
+	}
+
 	if err := s.db.Create(entry).Error; err != nil {
 		return nil, err
 	}
@@ -472,6 +505,32 @@ func (s *TimeTrackingService) CreateTimeEntry(dto CreateTimeEntryDTO) (*models.T
 	return entry, nil
 }
 
+// placeTimeEntry rejects entries for locked timesheets and attaches unlinked
+// entries to the employee's timesheet covering their date
+func (s *TimeTrackingService) placeTimeEntry(entry *models.TimeEntry) error {
+	var timesheet models.Timesheet
+	if entry.TimesheetID != nil {
+		if err := s.db.First(&timesheet, "id = ?", *entry.TimesheetID).Error; err != nil {
+			return errors.New("timesheet not found")
+		}
+	} else {
+		day := time.Date(entry.EntryDate.Year(), entry.EntryDate.Month(), entry.EntryDate.Day(), 0, 0, 0, 0, entry.EntryDate.Location())
+		err := s.db.Where("employee_id = ? AND period_start <= ? AND period_end >= ?", entry.EmployeeID, day, day).
+			Order("period_start DESC").Limit(1).Find(&timesheet).Error
+		if err != nil {
+			return err
+		}
+		if timesheet.ID == uuid.Nil {
+			return nil
+		}
+	}
+	if timesheet.Status == models.TimesheetStatusApproved || timesheet.Status == models.TimesheetStatusProcessed {
+		return ErrTimesheetLocked
+	}
+	entry.TimesheetID = &timesheet.ID
+	return nil
+}
+
 // GetTimeEntryByID retrieves a time entry by ID
 func (s *TimeTrackingService) GetTimeEntryByID(id uuid.UUID) (*models.TimeEntry, error) {
 	var entry models.TimeEntry
@@ -500,8 +559,20 @@ func (s *TimeTrackingService) GetEmployeeTimeEntries(employeeID uuid.UUID, start
 
 // === Clock In/Out Methods ===
 
+// ClockPunchDTO is what the portal or the app reports with a clock-in or clock-out
+type ClockPunchDTO struct {
+	Source    string
+	Location  string
+	IP        string
+	Notes     string
+	Latitude  *float64
+	Longitude *float64
+	DeviceID  string
+	Photo     string // Base64 JPEG/PNG selfie
+}
+
 // ClockIn clocks in an employee
-func (s *TimeTrackingService) ClockIn(companyID, employeeID uuid.UUID, source, location, ip string, notes string) (*models.ClockRecord, error) {
+func (s *TimeTrackingService) ClockIn(companyID, employeeID uuid.UUID, punch ClockPunchDTO) (*models.ClockRecord, error) {
 	// Check if already clocked in
 	var existing models.ClockRecord
 	err := s.db.Where("employee_id = ? AND is_complete = ?", employeeID, false).First(&existing).Error
@@ -513,10 +584,10 @@ func (s *TimeTrackingService) ClockIn(companyID, employeeID uuid.UUID, source, l
 		CompanyID:       companyID,
 		EmployeeID:      employeeID,
 		ClockInTime:     time.Now(),
-		ClockInSource:   source,
-		ClockInLocation: location,
-		ClockInIP:       ip,
-		ClockInNotes:    notes,
+		ClockInSource:   punch.Source,
+		ClockInLocation: punch.Location,
+		ClockInIP:       punch.IP,
+		ClockInNotes:    punch.Notes,
 		Status:          "active",
 		IsComplete:      false,
 	}
@@ -524,6 +595,10 @@ func (s *TimeTrackingService) ClockIn(companyID, employeeID uuid.UUID, source, l
 	if record.ClockInSource == "" {
 		record.ClockInSource = "app"
 	}
+	punch.Source = record.ClockInSource
+	if err := s.sites.checkPunch(companyID, employeeID, &punch, record, true); err != nil {
+		return nil, err
+	}
 
 	if err := s.db.Create(record).Error; err != nil {
 		return nil, err
@@ -533,19 +608,22 @@ func (s *TimeTrackingService) ClockIn(companyID, employeeID uuid.UUID, source, l
 }
 
 // ClockOut clocks out an employee
-func (s *TimeTrackingService) ClockOut(employeeID uuid.UUID, source, location, ip string, notes string) (*models.ClockRecord, error) {
+func (s *TimeTrackingService) ClockOut(employeeID uuid.UUID, punch ClockPunchDTO) (*models.ClockRecord, error) {
 	var record models.ClockRecord
 	err := s.db.Where("employee_id = ? AND is_complete = ?", employeeID, false).First(&record).Error
 	if err != nil {
 		return nil, errors.New("not clocked in")
 	}
+	if err := s.sites.checkPunch(record.CompanyID, employeeID, &punch, &record, false); err != nil {
+		return nil, err
+	}
 
 	now := time.Now()
 	record.ClockOutTime = &now
-	record.ClockOutSource = source
-	record.ClockOutLocation = location
-	record.ClockOutIP = ip
-	record.ClockOutNotes = notes
+	record.ClockOutSource = punch.Source
+	record.ClockOutLocation = punch.Location
+	record.ClockOutIP = punch.IP
+	record.ClockOutNotes = punch.Notes
 	record.IsComplete = true
 	record.Status = "completed"
 
@@ -556,6 +634,9 @@ func (s *TimeTrackingService) ClockOut(employeeID uuid.UUID, source, location, i
 	if err := s.db.Save(&record).Error; err != nil {
 		return nil, err
 	}
+	if err := s.policies.ApplyToClockRecord(&record); err != nil {
+		return nil, err
+	}
 
 	return &record, nil
 }
@@ -639,6 +720,10 @@ func (s *TimeTrackingService) GetTimeOffBalance(employeeID uuid.UUID, year int)
 
 // UpdateTimeOffBalance updates time off balance
 func (s *TimeTrackingService) UpdateTimeOffBalance(employeeID uuid.UUID, year int, balanceType string, hours float64, isUsage bool) (*models.TimeOffBalance, error) {
+	if balanceType == "vacation" {
+		return nil, ErrVacationBalanceManaged
+	}
+
 	balance, err := s.GetTimeOffBalance(employeeID, year)
 	if err != nil {
 		return nil, err
@@ -659,13 +744,6 @@ func (s *TimeTrackingService) UpdateTimeOffBalance(employeeID uuid.UUID, year in
 	}
 
 	switch balanceType {
-	case "vacation":
-		if isUsage {
-			balance.VacationUsed += hours
-		} else {
-			balance.VacationEntitled += hours
-		}
-		balance.VacationBalance = balance.VacationEntitled + balance.VacationCarryOver - balance.VacationUsed - balance.VacationPending
 	case "sick":
 		if isUsage {
 			balance.SickUsed += hours
@@ -26,6 +26,8 @@ SYNTAX EXPLANATION:
     - AllowedMimeTypes: PDF, images (JPG, PNG, GIF), Word docs, plain text
     - MaxFileSize: 10MB limit for uploads
     - Files renamed with UUID to prevent collisions and path traversal
+    - StoreFile does the validation and storage for other attachments
+      (IMSS incapacidad certificates)
     - MIME type validated using http.DetectContentType (first 512 bytes)
     - Physical file deleted when evidence record is removed
 
@@ -112,26 +114,54 @@ func (s *UploadService) UploadEvidence(incidenceID, userID uuid.UUID, fileHeader
 		return nil, err
 	}
 
+	storedName, filePath, contentType, err := s.StoreFile(incidenceID.String(), fileHeader)
+	if err != nil {
+		return nil, err
+	}
+
+	// Create evidence record
+	evidence := &models.IncidenceEvidence{
+		IncidenceID:  incidenceID,
+		FileName:     storedName,
+		OriginalName: fileHeader.Filename,
+		ContentType:  contentType,
+		FileSize:     fileHeader.Size,
+		FilePath:     filePath,
+		UploadedBy:   userID,
+	}
+
+	if err := s.db.Create(evidence).Error; err != nil {
+		// Clean up file on database error
+		os.Remove(filePath)
+		return nil, err
+	}
+
+	return evidence, nil
+}
+
+// StoreFile validates an uploaded file and saves it in UploadDir as
+// <prefix>_<uuid><ext>; it returns the stored name, path and content type
+func (s *UploadService) StoreFile(prefix string, fileHeader *multipart.FileHeader) (string, string, string, error) {
 	// Validate file
 	if fileHeader == nil {
-		return nil, ErrNoFile
+		return "", "", "", ErrNoFile
 	}
 
 	// Check file size
 	if fileHeader.Size > MaxFileSize {
-		return nil, ErrFileTooLarge
+		return "", "", "", ErrFileTooLarge
 	}
 
 	// Validate file extension
 	ext := strings.ToLower(filepath.Ext(fileHeader.Filename))
 	if !AllowedExtensions[ext] {
-		return nil, ErrInvalidExtension
+		return "", "", "", ErrInvalidExtension
 	}
 
 	// Open the file to validate MIME type
 	file, err := fileHeader.Open()
 	if err != nil {
-		return nil, err
+		return "", "", "", err
 	}
 	defer file.Close()
 
@@ -139,7 +169,7 @@ func (s *UploadService) UploadEvidence(incidenceID, userID uuid.UUID, fileHeader
 	buffer := make([]byte, 512)
 	n, err := file.Read(buffer)
 	if err != nil && err != io.EOF {
-		return nil, err
+		return "", "", "", err
 	}
 	contentType := http.DetectContentType(buffer[:n])
 
@@ -147,7 +177,7 @@ func (s *UploadService) UploadEvidence(incidenceID, userID uuid.UUID, fileHeader
 	if !AllowedMimeTypes[contentType] {
 		// Some files may have generic types, check extension as fallback
 		if !strings.HasPrefix(contentType, "application/octet-stream") || !AllowedExtensions[ext] {
-			return nil, ErrInvalidFileType
+			return "", "", "", ErrInvalidFileType
 		}
 		// Override with extension-based type for allowed extensions
 		switch ext {
@@ -164,13 +194,13 @@ func (s *UploadService) UploadEvidence(incidenceID, userID uuid.UUID, fileHeader
 	file.Seek(0, 0)
 
 	// Generate unique filename
-	newFileName := fmt.Sprintf("%s_%s%s", incidenceID.String(), uuid.New().String(), ext)
+	newFileName := fmt.Sprintf("%s_%s%s", prefix, uuid.New().String(), ext)
 	filePath := filepath.Join(UploadDir, newFileName)
 
 	// Create destination file
 	dst, err := os.Create(filePath)
 	if err != nil {
-		return nil, err
+		return "", "", "", err
 	}
 	defer dst.Close()
 
@@ -178,27 +208,10 @@ func (s *UploadService) UploadEvidence(incidenceID, userID uuid.UUID, fileHeader
 	if _, err = io.Copy(dst, file); err != nil {
 		// Clean up on failure
 		os.Remove(filePath)
-		return nil, err
-	}
-
-	// Create evidence record
-	evidence := &models.IncidenceEvidence{
-		IncidenceID:  incidenceID,
-		FileName:     newFileName,
-		OriginalName: fileHeader.Filename,
-		ContentType:  contentType,
-		FileSize:     fileHeader.Size,
-		FilePath:     filePath,
-		UploadedBy:   userID,
-	}
-
-	if err := s.db.Create(evidence).Error; err != nil {
-		// Clean up file on database error
-		os.Remove(filePath)
-		return nil, err
+		return "", "", "", err
 	}
 
-	return evidence, nil
+	return newFileName, filePath, contentType, nil
 }
 
 // GetEvidenceByIncidence retrieves all evidence files for an incidence
@@ -0,0 +1,738 @@
+/*
+Package services - Vacation Ledger Service
+
+==============================================================================
+FILE: internal/services/vacation_ledger_service.go
+==============================================================================
+
+DESCRIPTION:
+    Keeps each employee's vacation days as a ledger of TimeOffAccrual
+    movements instead of recomputing them. On every work anniversary the
+    days of the LFT table (configs/tables/vacations_2025.json, 2023 reform)
+    are granted; approved vacation incidences consume them, oldest grant
+    first; cancelling the incidence reverses the consumption; the days a
+    grant still has 18 months after the anniversary expire; HR posts manual
+    adjustments with a reason. The statement lists every movement with its
+    running balance and one bucket per anniversary, and TimeOffBalance's
+    vacation fields are refreshed from it after each posting.
+
+USER PERSPECTIVE:
+    - Employees see the days earned at each anniversary, what they carry
+      over and when it expires
+    - HR no longer edits balances by hand: every change is a movement
+      with who posted it and why
+    - Cancelling approved vacations gives the days back
+
+DEVELOPER GUIDELINES:
+    ✅  OK to modify: Carry-over policy, statement content
+    ⚠️  CAUTION: Never update or delete ledger rows; post a reversal or an
+        adjustment instead
+    ⚠️  CAUTION: Posting is idempotent per anniversary (grants) and per
+        incidence (consumption), so the runs can be repeated safely
+    📝  Grants already prescribed when the ledger first sees an employee
+        are not posted; they would expire the same day
+    📝  The service runs inside the caller's transaction through withDB
+
+SYNTAX EXPLANATION:
+    - ServiceYear: the anniversary a movement belongs to (1 = first year)
+    - Prescription: LFT Art. 81 gives 6 months after the anniversary to
+      take the days and Art. 516 one more year to claim them; 18 months in
+      labor_concepts.vacations.prescription_months
+    - Carry-over: days of earlier anniversaries stay available until their
+      own expiry; labor_concepts.vacations.max_carry_over_days (0 = no cap)
+      expires the excess at the next anniversary
+    - Days taken before they are earned are charged to the next anniversary
+
+==============================================================================
+*/
+package services
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"backend/internal/config"
+	"backend/internal/config/payroll"
+	"backend/internal/dtos"
+	"backend/internal/models"
+)
+
+// defaultVacationPrescriptionMonths is used when the labor concepts do not set one
+const defaultVacationPrescriptionMonths = 18
+
+// timeOffVacation is the TimeOffAccrual.TimeOffType of the vacation ledger
+const timeOffVacation = "vacation"
+
+// VacationLedgerService posts and reports vacation ledger movements
+type VacationLedgerService struct {
+	db           *gorm.DB
+	payroll      *payroll.PayrollConfig
+	prescription int
+	maxCarryOver float64
+	dailyHours   float64
+}
+
+// NewVacationLedgerService creates a new vacation ledger service. Without a
+// payroll config the built-in LFT table and an 18-month prescription apply.
+func NewVacationLedgerService(db *gorm.DB, appConfig *config.AppConfig) *VacationLedgerService {
+	payrollConfig := payroll.NewPayrollConfig()
+	if appConfig != nil && appConfig.PayrollConfig != nil {
+		payrollConfig = appConfig.PayrollConfig
+	}
+
+	rules := payrollConfig.LaborConcepts.Vacations
+	prescription := rules.PrescriptionMonths
+	if prescription <= 0 {
+		prescription = defaultVacationPrescriptionMonths
+	}
+	dailyHours := payrollConfig.LaborConcepts.WorkSchedule.DailyHours
+	if dailyHours <= 0 {
+		dailyHours = 8
+	}
+
+	return &VacationLedgerService{
+		db:           db,
+		payroll:      payrollConfig,
+		prescription: prescription,
+		maxCarryOver: rules.MaxCarryOverDays,
+		dailyHours:   dailyHours,
+	}
+}
+
+// withDB returns a copy of the service that reads and posts through db
+func (s *VacationLedgerService) withDB(db *gorm.DB) *VacationLedgerService {
+	copied := *s
+	copied.db = db
+	return &copied
+}
+
+// workAnniversary returns the date an employee completes n years of service
+func workAnniversary(hireDate time.Time, n int) time.Time {
+	return truncateToDate(hireDate).AddDate(n, 0, 0)
+}
+
+// completedServiceYears returns the anniversaries reached by asOf
+func completedServiceYears(hireDate, asOf time.Time) int {
+	years := asOf.Year() - hireDate.Year()
+	if workAnniversary(hireDate, years).After(asOf) {
+		years--
+	}
+	if years < 0 {
+		return 0
+	}
+	return years
+}
+
+// === Posting ===
+
+// post writes one vacation movement for the employee
+func (s *VacationLedgerService) post(employee *models.Employee, entry models.TimeOffAccrual) error {
+	entry.CompanyID = employee.CompanyID
+	entry.EmployeeID = employee.ID
+	entry.TimeOffType = timeOffVacation
+	entry.Days = roundHours(entry.Days)
+	entry.Hours = roundHours(entry.Days * s.dailyHours)
+	return s.db.Create(&entry).Error
+}
+
+// entries returns the employee's vacation movements in posting order
+func (s *VacationLedgerService) entries(employeeID uuid.UUID) ([]models.TimeOffAccrual, error) {
+	var entries []models.TimeOffAccrual
+	err := s.db.Where("employee_id = ? AND time_off_type = ?", employeeID, timeOffVacation).
+		Order("accrual_date, created_at").Find(&entries).Error
+	return entries, err
+}
+
+// postGrants posts the grant of every anniversary reached by asOf that is not in the ledger yet
+func (s *VacationLedgerService) postGrants(employee *models.Employee, asOf time.Time) (int, float64, error) {
+	var posted []int
+	if err := s.db.Model(&models.TimeOffAccrual{}).
+		Where("employee_id = ? AND time_off_type = ? AND reason = ?", employee.ID, timeOffVacation, models.AccrualAnnualGrant).
+		Pluck("service_year", &posted).Error; err != nil {
+		return 0, 0, err
+	}
+	done := make(map[int]bool, len(posted))
+	for _, year := range posted {
+		done[year] = true
+	}
+
+	grants, days := 0, 0.0
+	for n := 1; !workAnniversary(employee.HireDate, n).After(asOf); n++ {
+		if done[n] {
+			continue
+		}
+		grantedOn := workAnniversary(employee.HireDate, n)
+		expiresOn := grantedOn.AddDate(0, s.prescription, 0)
+		if !expiresOn.After(asOf) {
+			continue
+		}
+
+		if err := s.capCarryOver(employee, grantedOn); err != nil {
+			return grants, days, err
+		}
+
+		granted := float64(s.payroll.GetVacationDaysForYears(n))
+		if err := s.post(employee, models.TimeOffAccrual{
+			AccrualDate: grantedOn,
+			Reason:      models.AccrualAnnualGrant,
+			Days:        granted,
+			ServiceYear: n,
+			ExpiresOn:   &expiresOn,
+			Notes:       fmt.Sprintf("Aniversario %d", n),
+		}); err != nil {
+			return grants, days, err
+		}
+		grants++
+		days += granted
+	}
+	return grants, days, nil
+}
+
+// capCarryOver expires, oldest grant first, the days of earlier anniversaries above max_carry_over_days
+func (s *VacationLedgerService) capCarryOver(employee *models.Employee, anniversary time.Time) error {
+	if s.maxCarryOver <= 0 {
+		return nil
+	}
+	entries, err := s.entries(employee.ID)
+	if err != nil {
+		return err
+	}
+	buckets := vacationBuckets(entries)
+
+	carried := 0.0
+	for _, bucket := range buckets {
+		if bucket.Remaining > 0 {
+			carried += bucket.Remaining
+		}
+	}
+	excess := roundHours(carried - s.maxCarryOver)
+	for _, bucket := range buckets {
+		if excess <= 0 {
+			break
+		}
+		if bucket.Remaining <= 0 {
+			continue
+		}
+		expired := math.Min(excess, bucket.Remaining)
+		if err := s.post(employee, models.TimeOffAccrual{
+			AccrualDate: anniversary,
+			Reason:      models.AccrualExpiry,
+			Days:        -expired,
+			ServiceYear: bucket.ServiceYear,
+			Notes:       fmt.Sprintf("Excede el máximo de %g días acumulables", s.maxCarryOver),
+		}); err != nil {
+			return err
+		}
+		excess -= expired
+	}
+	return nil
+}
+
+// expireDue expires what is left of every grant whose prescription date has been reached
+func (s *VacationLedgerService) expireDue(employee *models.Employee, asOf time.Time) (int, float64, error) {
+	entries, err := s.entries(employee.ID)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	expiries, days := 0, 0.0
+	for _, bucket := range vacationBuckets(entries) {
+		if bucket.ExpiresOn == nil || bucket.ExpiresOn.After(asOf) || bucket.Remaining <= 0 {
+			continue
+		}
+		if err := s.post(employee, models.TimeOffAccrual{
+			AccrualDate: *bucket.ExpiresOn,
+			Reason:      models.AccrualExpiry,
+			Days:        -bucket.Remaining,
+			ServiceYear: bucket.ServiceYear,
+			Notes:       fmt.Sprintf("Días no disfrutados, prescritos a los %d meses del aniversario", s.prescription),
+		}); err != nil {
+			return expiries, days, err
+		}
+		expiries++
+		days += bucket.Remaining
+	}
+	return expiries, days, nil
+}
+
+// isVacationIncidence reports whether the incidence's type is in the vacation category
+func (s *VacationLedgerService) isVacationIncidence(incidence *models.Incidence) bool {
+	if incidence.IncidenceType != nil {
+		return incidence.IncidenceType.Category == "vacation"
+	}
+	var incidenceType models.IncidenceType
+	if err := s.db.Select("category").Limit(1).Find(&incidenceType, "id = ?", incidence.IncidenceTypeID).Error; err != nil {
+		return false
+	}
+	return incidenceType.Category == "vacation"
+}
+
+// PostConsumption charges an approved vacation incidence to the employee's
+// grants, oldest first. Other categories and incidences already charged are ignored.
+func (s *VacationLedgerService) PostConsumption(incidence *models.Incidence, now time.Time) error {
+	if incidence.Status != "approved" && incidence.Status != "processed" {
+		return nil
+	}
+	if !s.isVacationIncidence(incidence) {
+		return nil
+	}
+
+	var employee models.Employee
+	if err := s.db.First(&employee, "id = ?", incidence.EmployeeID).Error; err != nil {
+		return errors.New("employee not found")
+	}
+
+	if err := s.consume(&employee, incidence); err != nil {
+		return err
+	}
+	_, err := s.refresh(&employee, now)
+	return err
+}
+
+// consume posts the consumption of one incidence unless it is already charged
+func (s *VacationLedgerService) consume(employee *models.Employee, incidence *models.Incidence) error {
+	var charged struct{ Days float64 }
+	if err := s.db.Model(&models.TimeOffAccrual{}).
+		Select("COALESCE(SUM(days), 0) AS days").
+		Where("incidence_id = ? AND reason IN ?", incidence.ID, []string{models.AccrualConsumption, models.AccrualReversal}).
+		Scan(&charged).Error; err != nil {
+		return err
+	}
+	if charged.Days < 0 {
+		return nil
+	}
+
+	takenOn := truncateToDate(incidence.StartDate)
+	entries, err := s.entries(employee.ID)
+	if err != nil {
+		return err
+	}
+
+	remaining := incidence.Quantity
+	lastYear := 0
+	for _, bucket := range vacationBuckets(entries) {
+		if bucket.GrantedOn == nil || bucket.GrantedOn.After(takenOn) {
+			continue
+		}
+		lastYear = bucket.ServiceYear
+		if remaining <= 0 || bucket.Remaining <= 0 || (bucket.ExpiresOn != nil && !bucket.ExpiresOn.After(takenOn)) {
+			continue
+		}
+		taken := math.Min(remaining, bucket.Remaining)
+		if err := s.post(employee, models.TimeOffAccrual{
+			AccrualDate:     takenOn,
+			Reason:          models.AccrualConsumption,
+			Days:            -taken,
+			ServiceYear:     bucket.ServiceYear,
+			IncidenceID:     &incidence.ID,
+			PayrollPeriodID: &incidence.PayrollPeriodID,
+			CreatedByID:     incidence.ApprovedBy,
+		}); err != nil {
+			return err
+		}
+		remaining -= taken
+	}
+
+	// Days not covered by a posted grant belong to the next anniversary's
+	if remaining > 0 {
+		return s.post(employee, models.TimeOffAccrual{
+			AccrualDate:     takenOn,
+			Reason:          models.AccrualConsumption,
+			Days:            -remaining,
+			ServiceYear:     lastYear + 1,
+			IncidenceID:     &incidence.ID,
+			PayrollPeriodID: &incidence.PayrollPeriodID,
+			CreatedByID:     incidence.ApprovedBy,
+			Notes:           "Días anticipados a cuenta del siguiente aniversario",
+		})
+	}
+	return nil
+}
+
+// ReverseConsumption gives back the days charged for an incidence that was
+// cancelled, rejected or deleted after its approval
+func (s *VacationLedgerService) ReverseConsumption(incidence *models.Incidence, actorID *uuid.UUID, now time.Time) error {
+	var movements []models.TimeOffAccrual
+	if err := s.db.Where("incidence_id = ? AND reason IN ?", incidence.ID,
+		[]string{models.AccrualConsumption, models.AccrualReversal}).Find(&movements).Error; err != nil {
+		return err
+	}
+
+	reversed := make(map[uuid.UUID]bool)
+	for _, movement := range movements {
+		if movement.ReversesID != nil {
+			reversed[*movement.ReversesID] = true
+		}
+	}
+
+	var employee models.Employee
+	posted := false
+	for _, movement := range movements {
+		if movement.Reason != models.AccrualConsumption || reversed[movement.ID] {
+			continue
+		}
+		if !posted {
+			if err := s.db.First(&employee, "id = ?", incidence.EmployeeID).Error; err != nil {
+				return errors.New("employee not found")
+			}
+		}
+		if err := s.post(&employee, models.TimeOffAccrual{
+			AccrualDate: truncateToDate(now),
+			Reason:      models.AccrualReversal,
+			Days:        -movement.Days,
+			ServiceYear: movement.ServiceYear,
+			IncidenceID: &incidence.ID,
+			ReversesID:  &movement.ID,
+			CreatedByID: actorID,
+			Notes:       "Cancelación de la incidencia de vacaciones",
+		}); err != nil {
+			return err
+		}
+		posted = true
+	}
+
+	if !posted {
+		return nil
+	}
+	_, err := s.refresh(&employee, now)
+	return err
+}
+
+// Adjust posts a manual correction; the reason is shown on the statement
+func (s *VacationLedgerService) Adjust(companyID, employeeID, actorID uuid.UUID, req dtos.VacationAdjustmentRequest, now time.Time) (*dtos.VacationStatement, error) {
+	if strings.TrimSpace(req.Reason) == "" {
+		return nil, errors.New("reason is required")
+	}
+	if req.Days == 0 {
+		return nil, errors.New("days must not be zero")
+	}
+	if req.ServiceYear < 0 {
+		return nil, errors.New("service year must be zero or positive")
+	}
+
+	employee, err := s.companyEmployee(companyID, employeeID)
+	if err != nil {
+		return nil, err
+	}
+	if _, _, err := s.postGrants(employee, now); err != nil {
+		return nil, err
+	}
+
+	serviceYear := req.ServiceYear
+	if serviceYear == 0 {
+		serviceYear = completedServiceYears(employee.HireDate, now)
+	}
+	if err := s.post(employee, models.TimeOffAccrual{
+		AccrualDate: truncateToDate(now),
+		Reason:      models.AccrualAdjustment,
+		Days:        req.Days,
+		ServiceYear: serviceYear,
+		Notes:       strings.TrimSpace(req.Reason),
+		IsManual:    true,
+		CreatedByID: &actorID,
+	}); err != nil {
+		return nil, err
+	}
+	return s.refresh(employee, now)
+}
+
+// === Runs ===
+
+// PostAnniversaryGrants posts the grants of every active employee whose
+// anniversary has been reached, and charges approved vacations not yet in the ledger
+func (s *VacationLedgerService) PostAnniversaryGrants(companyID uuid.UUID, now time.Time) (*dtos.VacationLedgerRunResult, error) {
+	return s.run(companyID, now, func(employee *models.Employee, result *dtos.VacationLedgerRunResult) error {
+		grants, days, err := s.postGrants(employee, now)
+		if err != nil {
+			return err
+		}
+		result.Grants += grants
+		result.DaysGranted += days
+		return s.chargeUnposted(employee)
+	})
+}
+
+// ExpireBalances expires the days of every grant past its prescription
+func (s *VacationLedgerService) ExpireBalances(companyID uuid.UUID, now time.Time) (*dtos.VacationLedgerRunResult, error) {
+	return s.run(companyID, now, func(employee *models.Employee, result *dtos.VacationLedgerRunResult) error {
+		expiries, days, err := s.expireDue(employee, now)
+		if err != nil {
+			return err
+		}
+		result.Expiries += expiries
+		result.DaysExpired += days
+		return nil
+	})
+}
+
+// run applies step to every active employee of the company and refreshes their balance
+func (s *VacationLedgerService) run(companyID uuid.UUID, now time.Time,
+	step func(*models.Employee, *dtos.VacationLedgerRunResult) error) (*dtos.VacationLedgerRunResult, error) {
+	var employees []models.Employee
+	if err := s.db.Where("company_id = ? AND employment_status = ?", companyID, "active").
+		Order("employee_number").Find(&employees).Error; err != nil {
+		return nil, err
+	}
+
+	result := &dtos.VacationLedgerRunResult{}
+	for i := range employees {
+		employee := &employees[i]
+		if err := step(employee, result); err != nil {
+			return nil, fmt.Errorf("employee %s: %w", employee.EmployeeNumber, err)
+		}
+		if _, err := s.refresh(employee, now); err != nil {
+			return nil, fmt.Errorf("employee %s: %w", employee.EmployeeNumber, err)
+		}
+		result.Employees++
+	}
+	result.DaysGranted = roundHours(result.DaysGranted)
+	result.DaysExpired = roundHours(result.DaysExpired)
+	return result, nil
+}
+
+// chargeUnposted charges the approved vacation incidences taken since the
+// oldest grant in the ledger that have no movement yet (e.g. approved before the ledger)
+func (s *VacationLedgerService) chargeUnposted(employee *models.Employee) error {
+	var oldest models.TimeOffAccrual
+	if err := s.db.Where("employee_id = ? AND time_off_type = ? AND reason = ?", employee.ID, timeOffVacation, models.AccrualAnnualGrant).
+		Order("accrual_date").Limit(1).Find(&oldest).Error; err != nil {
+		return err
+	}
+	if oldest.ID == uuid.Nil {
+		return nil
+	}
+
+	var incidences []models.Incidence
+	if err := s.db.Joins("JOIN incidence_types ON incidence_types.id = incidences.incidence_type_id").
+		Where("incidences.employee_id = ? AND incidence_types.category = ?", employee.ID, "vacation").
+		Where("incidences.status IN ? AND incidences.start_date >= ?", []string{"approved", "processed"}, oldest.AccrualDate).
+		Where("incidences.id NOT IN (?)", s.db.Model(&models.TimeOffAccrual{}).Select("incidence_id").Where("incidence_id IS NOT NULL")).
+		Order("incidences.start_date").Find(&incidences).Error; err != nil {
+		return err
+	}
+
+	for i := range incidences {
+		if err := s.consume(employee, &incidences[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// === Statement ===
+
+// Statement brings the employee's ledger up to date and returns it
+func (s *VacationLedgerService) Statement(companyID, employeeID uuid.UUID, now time.Time) (*dtos.VacationStatement, error) {
+	employee, err := s.companyEmployee(companyID, employeeID)
+	if err != nil {
+		return nil, err
+	}
+	return s.EmployeeStatement(employee, now)
+}
+
+// StatementForUser returns the statement of the employee linked to a user
+func (s *VacationLedgerService) StatementForUser(companyID, userID uuid.UUID, now time.Time) (*dtos.VacationStatement, error) {
+	var user models.User
+	if err := s.db.Select("id", "employee_id").Limit(1).Find(&user, "id = ?", userID).Error; err != nil {
+		return nil, err
+	}
+	if user.EmployeeID == nil {
+		return nil, errors.New("employee not found")
+	}
+	return s.Statement(companyID, *user.EmployeeID, now)
+}
+
+// EmployeeStatement posts the grants and expiries due for the employee and returns the statement
+func (s *VacationLedgerService) EmployeeStatement(employee *models.Employee, now time.Time) (*dtos.VacationStatement, error) {
+	if _, _, err := s.postGrants(employee, now); err != nil {
+		return nil, err
+	}
+	if err := s.chargeUnposted(employee); err != nil {
+		return nil, err
+	}
+	if _, _, err := s.expireDue(employee, now); err != nil {
+		return nil, err
+	}
+	return s.refresh(employee, now)
+}
+
+// companyEmployee loads an employee of the company
+func (s *VacationLedgerService) companyEmployee(companyID, employeeID uuid.UUID) (*models.Employee, error) {
+	var employee models.Employee
+	if err := s.db.Where("id = ? AND company_id = ?", employeeID, companyID).Limit(1).Find(&employee).Error; err != nil {
+		return nil, err
+	}
+	if employee.ID == uuid.Nil {
+		return nil, errors.New("employee not found")
+	}
+	return &employee, nil
+}
+
+// refresh builds the statement and stores it in the employee's TimeOffBalance of the year
+func (s *VacationLedgerService) refresh(employee *models.Employee, now time.Time) (*dtos.VacationStatement, error) {
+	statement, err := s.buildStatement(employee, now)
+	if err != nil {
+		return nil, err
+	}
+
+	var balance models.TimeOffBalance
+	if err := s.db.Where("employee_id = ? AND year = ?", employee.ID, now.Year()).Limit(1).Find(&balance).Error; err != nil {
+		return nil, err
+	}
+	balance.CompanyID = employee.CompanyID
+	balance.EmployeeID = employee.ID
+	balance.Year = now.Year()
+	balance.VacationEntitled = statement.CurrentGrant
+	balance.VacationCarryOver = statement.CarriedOver
+	balance.VacationUsed = 0
+	for _, bucket := range statement.Buckets {
+		if bucket.ServiceYear == statement.YearsOfService {
+			balance.VacationUsed = bucket.Used
+		}
+	}
+	balance.VacationPending = statement.PendingDays
+	balance.VacationBalance = statement.Available
+	calculatedAt := now
+	balance.LastCalculatedAt = &calculatedAt
+
+	if balance.ID == uuid.Nil {
+		err = s.db.Create(&balance).Error
+	} else {
+		err = s.db.Save(&balance).Error
+	}
+	if err != nil {
+		return nil, err
+	}
+	return statement, nil
+}
+
+// buildStatement summarizes the ledger as of now without posting anything
+func (s *VacationLedgerService) buildStatement(employee *models.Employee, now time.Time) (*dtos.VacationStatement, error) {
+	entries, err := s.entries(employee.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	years := completedServiceYears(employee.HireDate, now)
+	statement := &dtos.VacationStatement{
+		EmployeeID:      employee.ID,
+		EmployeeNumber:  employee.EmployeeNumber,
+		EmployeeName:    strings.TrimSpace(employee.FirstName + " " + employee.LastName),
+		HireDate:        employee.HireDate,
+		YearsOfService:  years,
+		NextAnniversary: workAnniversary(employee.HireDate, years+1),
+		NextGrantDays:   s.payroll.GetVacationDaysForYears(years + 1),
+		Movements:       make([]dtos.VacationMovement, 0, len(entries)),
+	}
+
+	balance := 0.0
+	for _, entry := range entries {
+		balance = roundHours(balance + entry.Days)
+		statement.Movements = append(statement.Movements, dtos.VacationMovement{
+			ID:          entry.ID,
+			Date:        entry.AccrualDate,
+			Type:        entry.Reason,
+			ServiceYear: entry.ServiceYear,
+			Days:        entry.Days,
+			Balance:     balance,
+			IncidenceID: entry.IncidenceID,
+			Notes:       entry.Notes,
+			IsManual:    entry.IsManual,
+			CreatedByID: entry.CreatedByID,
+		})
+	}
+
+	statement.Buckets = vacationBuckets(entries)
+	for i := range statement.Buckets {
+		bucket := &statement.Buckets[i]
+		switch {
+		case bucket.ServiceYear == years:
+			bucket.Status = "current"
+			statement.CurrentGrant = bucket.Granted
+		case bucket.ServiceYear > years:
+			bucket.Status = "advance"
+		case bucket.Remaining > 0:
+			bucket.Status = "carried_over"
+			statement.CarriedOver += bucket.Remaining
+		case bucket.Expired > 0:
+			bucket.Status = "expired"
+		default:
+			bucket.Status = "exhausted"
+		}
+	}
+
+	pending, err := s.pendingDays(employee.ID)
+	if err != nil {
+		return nil, err
+	}
+	statement.CarriedOver = roundHours(statement.CarriedOver)
+	statement.Balance = balance
+	statement.PendingDays = pending
+	statement.Available = roundHours(balance - pending)
+	return statement, nil
+}
+
+// pendingDays sums the vacation incidences waiting for approval. The initial
+// incidence of an absence request stays pending after the request's own
+// approved incidence is created, so those are left out.
+func (s *VacationLedgerService) pendingDays(employeeID uuid.UUID) (float64, error) {
+	approvedRequests := s.db.Model(&models.Incidence{}).Select("absence_request_id").
+		Where("absence_request_id IS NOT NULL AND status IN ?", []string{"approved", "processed"})
+
+	var pending struct{ Days float64 }
+	err := s.db.Model(&models.Incidence{}).
+		Joins("JOIN incidence_types ON incidence_types.id = incidences.incidence_type_id").
+		Where("incidences.employee_id = ? AND incidence_types.category = ? AND incidences.status = ?", employeeID, "vacation", "pending").
+		Where("incidences.excluded_from_payroll = ?", false).
+		Where("(incidences.absence_request_id IS NULL OR incidences.absence_request_id NOT IN (?))", approvedRequests).
+		Select("COALESCE(SUM(incidences.quantity), 0) AS days").
+		Scan(&pending).Error
+	return roundHours(pending.Days), err
+}
+
+// vacationBuckets groups movements by service year, in anniversary order
+func vacationBuckets(entries []models.TimeOffAccrual) []dtos.VacationBucket {
+	var buckets []dtos.VacationBucket
+	index := make(map[int]int)
+	for _, entry := range entries {
+		i, ok := index[entry.ServiceYear]
+		if !ok {
+			i = len(buckets)
+			index[entry.ServiceYear] = i
+			buckets = append(buckets, dtos.VacationBucket{ServiceYear: entry.ServiceYear})
+		}
+		bucket := &buckets[i]
+
+		switch entry.Reason {
+		case models.AccrualAnnualGrant:
+			grantedOn := entry.AccrualDate
+			bucket.GrantedOn = &grantedOn
+			bucket.ExpiresOn = entry.ExpiresOn
+			bucket.Granted += entry.Days
+		case models.AccrualConsumption, models.AccrualReversal:
+			bucket.Used -= entry.Days
+		case models.AccrualExpiry:
+			bucket.Expired -= entry.Days
+		default:
+			bucket.Adjusted += entry.Days
+		}
+		bucket.Remaining += entry.Days
+	}
+
+	for i := range buckets {
+		bucket := &buckets[i]
+		bucket.Used = roundHours(bucket.Used)
+		bucket.Expired = roundHours(bucket.Expired)
+		bucket.Adjusted = roundHours(bucket.Adjusted)
+		bucket.Remaining = roundHours(bucket.Remaining)
+	}
+	sort.Slice(buckets, func(i, j int) bool { return buckets[i].ServiceYear < buckets[j].ServiceYear })
+	return buckets
+}
@@ -0,0 +1,192 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+
+	"backend/internal/dtos"
+	"backend/internal/models"
+)
+
+// vacationLedgerTest is a company with an employee hired 2022-03-01
+// (anniversaries 2023-03-01 with 12 days and 2024-03-01 with 14 days) and
+// an HR user hired the same day
+type vacationLedgerTest struct {
+	db         *gorm.DB
+	service    *VacationLedgerService
+	incidences *IncidenceService
+	company    *models.Company
+	employee   *models.Employee
+	hr         *models.User
+	vacation   *models.IncidenceType
+}
+
+func setupVacationLedgerTest(t *testing.T) *vacationLedgerTest {
+	db := setupPayrollTestDB(t)
+	require.NoError(t, db.AutoMigrate(
+		&models.User{}, &models.IncidenceCategory{}, &models.IncidenceType{}, &models.Incidence{},
+		&models.TimeOffBalance{}, &models.TimeOffAccrual{},
+	))
+	company := createPayrollTestCompany(t, db)
+	vacation := &models.IncidenceType{Name: "Vacaciones", Category: "vacation", EffectType: "neutral"}
+	require.NoError(t, db.Create(vacation).Error)
+	return &vacationLedgerTest{
+		db:         db,
+		service:    NewVacationLedgerService(db, nil),
+		incidences: NewIncidenceService(db, nil),
+		company:    company,
+		employee:   createFixtureEmployee(t, db, company.ID, 1, "general", 400),
+		hr:         createFixtureUser(t, db, createFixtureEmployee(t, db, company.ID, 2, "general", 400), "hr"),
+		vacation:   vacation,
+	}
+}
+
+// grant posts the anniversary grants due by mid-June 2024
+func (f *vacationLedgerTest) grant(t *testing.T) {
+	_, err := f.service.PostAnniversaryGrants(f.company.ID, ledgerDay(2024, 6, 15))
+	require.NoError(t, err)
+}
+
+// incidence records vacation days of the employee
+func (f *vacationLedgerTest) incidence(t *testing.T, start time.Time, days float64, status string) *models.Incidence {
+	inc := &models.Incidence{EmployeeID: f.employee.ID, PayrollPeriodID: uuid.New(), IncidenceTypeID: f.vacation.ID,
+		StartDate: start, EndDate: start.AddDate(0, 0, int(days)-1), Quantity: days, Status: status}
+	require.NoError(t, f.db.Create(inc).Error)
+	return inc
+}
+
+// approved records vacation days of the employee and approves them
+func (f *vacationLedgerTest) approved(t *testing.T, start time.Time, days float64) *models.Incidence {
+	inc := f.incidence(t, start, days, "pending")
+	_, err := f.incidences.ApproveIncidence(inc.ID, f.hr.ID)
+	require.NoError(t, err)
+	return inc
+}
+
+func ledgerDay(y int, m time.Month, d int) time.Time {
+	return time.Date(y, m, d, 0, 0, 0, 0, time.UTC)
+}
+
+func TestVacationLedger(t *testing.T) {
+	t.Run("grants each anniversary once", func(t *testing.T) {
+		f := setupVacationLedgerTest(t)
+		run, err := f.service.PostAnniversaryGrants(f.company.ID, ledgerDay(2024, 6, 15))
+		require.NoError(t, err)
+		assert.Equal(t, 2, run.Employees)
+		assert.Equal(t, 4, run.Grants)
+
+		run, err = f.service.PostAnniversaryGrants(f.company.ID, ledgerDay(2024, 6, 15))
+		require.NoError(t, err)
+		assert.Equal(t, 0, run.Grants)
+	})
+
+	t.Run("consumption takes the oldest grant first", func(t *testing.T) {
+		f := setupVacationLedgerTest(t)
+		f.grant(t)
+		f.approved(t, ledgerDay(2024, 7, 1), 5)
+		august := f.incidence(t, ledgerDay(2024, 8, 1), 10, "approved")
+		require.NoError(t, f.service.PostConsumption(august, ledgerDay(2024, 6, 20)))
+		require.NoError(t, f.service.PostConsumption(august, ledgerDay(2024, 6, 20))) // idempotent
+
+		statement, err := f.service.Statement(f.company.ID, f.employee.ID, ledgerDay(2024, 6, 20))
+		require.NoError(t, err)
+		require.Len(t, statement.Buckets, 2)
+		assert.Equal(t, 0.0, statement.Buckets[0].Remaining)
+		assert.Equal(t, 11.0, statement.Buckets[1].Remaining)
+		assert.Equal(t, 11.0, statement.Balance)
+	})
+
+	t.Run("un-approving gives the days back to their grants", func(t *testing.T) {
+		f := setupVacationLedgerTest(t)
+		f.grant(t)
+		f.approved(t, ledgerDay(2024, 7, 1), 5)
+		august := f.approved(t, ledgerDay(2024, 8, 1), 10)
+
+		_, err := f.incidences.UpdateIncidence(august.ID, UpdateIncidenceRequest{Status: "rejected"})
+		require.NoError(t, err)
+
+		statement, err := f.service.Statement(f.company.ID, f.employee.ID, ledgerDay(2024, 6, 20))
+		require.NoError(t, err)
+		require.Len(t, statement.Buckets, 2)
+		assert.Equal(t, 7.0, statement.Buckets[0].Remaining)
+		assert.Equal(t, 14.0, statement.Buckets[1].Remaining)
+		assert.Equal(t, 21.0, statement.Balance)
+	})
+
+	t.Run("adjustments need a reason", func(t *testing.T) {
+		f := setupVacationLedgerTest(t)
+		f.grant(t)
+		_, err := f.service.Adjust(f.company.ID, f.employee.ID, f.hr.ID, dtos.VacationAdjustmentRequest{Days: 1.5}, ledgerDay(2024, 6, 20))
+		assert.ErrorContains(t, err, "reason is required")
+
+		_, err = f.service.Adjust(f.company.ID, f.employee.ID, f.hr.ID,
+			dtos.VacationAdjustmentRequest{Days: 1.5, Reason: "Día de descanso trabajado en inventario"}, ledgerDay(2024, 6, 20))
+		require.NoError(t, err)
+		statement, err := f.service.Statement(f.company.ID, f.employee.ID, ledgerDay(2024, 6, 20))
+		require.NoError(t, err)
+		assert.Equal(t, 1.5, statement.Buckets[1].Adjusted)
+		assert.Equal(t, 27.5, statement.Balance)
+	})
+
+	t.Run("grants prescribe 18 months after their anniversary", func(t *testing.T) {
+		f := setupVacationLedgerTest(t)
+		f.grant(t)
+		f.approved(t, ledgerDay(2024, 7, 1), 5)
+		_, err := f.service.Adjust(f.company.ID, f.employee.ID, f.hr.ID,
+			dtos.VacationAdjustmentRequest{Days: 1.5, Reason: "Día de descanso trabajado en inventario"}, ledgerDay(2024, 6, 20))
+		require.NoError(t, err)
+		f.incidence(t, ledgerDay(2024, 12, 20), 2, "pending")
+
+		run, err := f.service.ExpireBalances(f.company.ID, ledgerDay(2024, 9, 2))
+		require.NoError(t, err)
+		assert.Equal(t, 19.0, run.DaysExpired) // 7 left of the employee's first grant, 12 of the HR user's
+
+		statement, err := f.service.Statement(f.company.ID, f.employee.ID, ledgerDay(2024, 9, 2))
+		require.NoError(t, err)
+		assert.Equal(t, 2, statement.YearsOfService)
+		assert.Equal(t, 16, statement.NextGrantDays)
+		assert.Equal(t, "expired", statement.Buckets[0].Status)
+		assert.Equal(t, 5.0, statement.Buckets[0].Used)
+		assert.Equal(t, "current", statement.Buckets[1].Status)
+		assert.Equal(t, 15.5, statement.Balance)
+		assert.Equal(t, 2.0, statement.PendingDays)
+		assert.Equal(t, 13.5, statement.Available)
+		require.Len(t, statement.Movements, 5)
+		last := statement.Movements[len(statement.Movements)-1]
+		assert.Equal(t, models.AccrualExpiry, last.Type)
+		assert.Equal(t, -7.0, last.Days)
+		assert.Equal(t, 15.5, last.Balance)
+
+		var balance models.TimeOffBalance
+		require.NoError(t, f.db.Where("employee_id = ? AND year = ?", f.employee.ID, 2024).First(&balance).Error)
+		assert.Equal(t, 14.0, balance.VacationEntitled)
+		assert.Equal(t, 13.5, balance.VacationBalance)
+	})
+
+	t.Run("the ledger owns the vacation balance", func(t *testing.T) {
+		f := setupVacationLedgerTest(t)
+		f.grant(t)
+		_, err := NewTimeTrackingService(f.db).UpdateTimeOffBalance(f.employee.ID, 2024, "vacation", 8, false)
+		assert.ErrorIs(t, err, ErrVacationBalanceManaged)
+	})
+
+	t.Run("days before the first anniversary are charged to it", func(t *testing.T) {
+		f := setupVacationLedgerTest(t)
+		newcomer := createFixtureEmployee(t, f.db, f.company.ID, 3, "general", 400)
+		require.NoError(t, f.db.Model(newcomer).Update("hire_date", ledgerDay(2024, 1, 10)).Error)
+		advance := &models.Incidence{EmployeeID: newcomer.ID, PayrollPeriodID: uuid.New(), IncidenceTypeID: f.vacation.ID,
+			StartDate: ledgerDay(2024, 6, 3), EndDate: ledgerDay(2024, 6, 5), Quantity: 3, Status: "approved"}
+		require.NoError(t, f.db.Create(advance).Error)
+		require.NoError(t, f.service.PostConsumption(advance, ledgerDay(2024, 6, 1)))
+
+		statement, err := f.service.Statement(f.company.ID, newcomer.ID, ledgerDay(2025, 1, 10))
+		require.NoError(t, err)
+		require.Len(t, statement.Buckets, 1)
+		assert.Equal(t, 9.0, statement.Buckets[0].Remaining)
+	})
+}
@@ -0,0 +1,179 @@
+/*
+Package services - Work Calendar Service
+
+==============================================================================
+FILE: internal/services/work_calendar_service.go
+==============================================================================
+
+DESCRIPTION:
+    Tells which days an employee works: the holiday catalog (federal, state
+    and local holidays of the regional config, company holidays) merged
+    with the employee's schedule (shift exceptions, rotations, weekly
+    pattern or default shift with its work days and rest days). Every
+    absence that is counted in days is counted here, so a vacation over a
+    holiday or a rest day does not consume balance.
+
+USER PERSPECTIVE:
+    - A vacation from Friday to Tuesday over a holiday Monday takes 2 days,
+      not 5
+    - A Monday-to-Saturday operator spends a vacation day on Saturday; an
+      office employee does not
+    - Employees see how many days a request will take before filing it
+
+DEVELOPER GUIDELINES:
+    ✅  OK to modify: Add day details for the portal
+    ⚠️  CAUTION: Payroll still pays every day of the period: rest days and
+        holidays are paid days (LFT Art. 69, 74); only absences are
+        counted in work days
+    ⚠️  CAUTION: IMSS incapacidades and leaves counted in calendar days
+        (e.g. maternity) must not go through WorkDays
+    📝  Schedules come from AttendanceEvaluationService.loadSchedules;
+        without any shift the employee works Monday to Friday
+
+SYNTAX EXPLANATION:
+    - Work day: scheduled (or Monday-Friday without a schedule), not a
+      rest day and not a holiday of the catalog
+    - Dates are compared by calendar day (attendanceDate)
+    - WorkDayQuantity is the day count of absence requests and of vacation
+      incidences entered by HR
+
+==============================================================================
+*/
+package services
+
+import (
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"backend/internal/config"
+	"backend/internal/dtos"
+	"backend/internal/models"
+)
+
+// WorkCalendarService merges holidays and shifts into the days an employee works
+type WorkCalendarService struct {
+	db         *gorm.DB
+	holidays   *HolidayCatalogService
+	attendance *AttendanceEvaluationService
+}
+
+// NewWorkCalendarService creates a new WorkCalendarService
+func NewWorkCalendarService(db *gorm.DB, appConfig *config.AppConfig) *WorkCalendarService {
+	return &WorkCalendarService{
+		db:         db,
+		holidays:   NewHolidayCatalogService(db, appConfig),
+		attendance: NewAttendanceEvaluationService(db, appConfig),
+	}
+}
+
+// Days returns every date between start and end (inclusive) with whether
+// the employee works it
+func (s *WorkCalendarService) Days(employee *models.Employee, start, end time.Time) ([]dtos.WorkCalendarDay, error) {
+	start, end = attendanceDate(start), attendanceDate(end)
+	if end.Before(start) {
+		return nil, errors.New("end date must be on or after start date")
+	}
+
+	schedules, err := s.attendance.loadSchedules(employee, start, end)
+	if err != nil {
+		return nil, err
+	}
+	holidays, err := s.holidays.GetHolidays(employee.CompanyID, start, end)
+	if err != nil {
+		return nil, err
+	}
+	byDate := make(map[string]dtos.HolidayCatalogEntry, len(holidays))
+	for _, h := range holidays {
+		byDate[h.Date.Format(attendanceDateKey)] = h
+	}
+
+	var days []dtos.WorkCalendarDay
+	for date := start; !date.After(end); date = date.AddDate(0, 0, 1) {
+		sched := schedules(date)
+		day := dtos.WorkCalendarDay{Date: date, ScheduleSource: sched.source}
+		if sched.source == models.ScheduleSourceNone {
+			day.IsRestDay = date.Weekday() == time.Saturday || date.Weekday() == time.Sunday
+		} else {
+			day.IsRestDay = sched.restDay
+		}
+		if holiday, ok := byDate[date.Format(attendanceDateKey)]; ok {
+			day.Holiday = holiday.Name
+			day.HolidaySource = holiday.Source
+		}
+		day.IsWorkDay = !day.IsRestDay && day.Holiday == ""
+		days = append(days, day)
+	}
+	return days, nil
+}
+
+// WorkDays counts the days the employee works between start and end
+func (s *WorkCalendarService) WorkDays(employee *models.Employee, start, end time.Time) (float64, error) {
+	days, err := s.Days(employee, start, end)
+	if err != nil {
+		return 0, err
+	}
+	return countWorkDays(days), nil
+}
+
+// WorkDayQuantity is what a whole-day absence of `requested` days between
+// start and end takes: its work days, except that a single work day keeps a
+// smaller share (a half day). Zero means the range has no work days.
+func (s *WorkCalendarService) WorkDayQuantity(employee *models.Employee, start, end time.Time, requested float64) (float64, error) {
+	days, err := s.WorkDays(employee, start, end)
+	if err != nil || days == 0 {
+		return 0, err
+	}
+	if days == 1 && requested > 0 && requested < days {
+		return requested, nil
+	}
+	return days, nil
+}
+
+// countWorkDays counts the work days of a calendar
+func countWorkDays(days []dtos.WorkCalendarDay) float64 {
+	count := 0.0
+	for _, day := range days {
+		if day.IsWorkDay {
+			count++
+		}
+	}
+	return count
+}
+
+// Calendar returns the work calendar of an employee of the company
+func (s *WorkCalendarService) Calendar(companyID, employeeID uuid.UUID, start, end time.Time) (*dtos.WorkCalendar, error) {
+	var employee models.Employee
+	if err := s.db.Where("id = ? AND company_id = ?", employeeID, companyID).Limit(1).Find(&employee).Error; err != nil {
+		return nil, err
+	}
+	if employee.ID == uuid.Nil {
+		return nil, errors.New("employee not found")
+	}
+
+	days, err := s.Days(&employee, start, end)
+	if err != nil {
+		return nil, err
+	}
+	return &dtos.WorkCalendar{
+		EmployeeID: employee.ID,
+		StartDate:  attendanceDate(start),
+		EndDate:    attendanceDate(end),
+		WorkDays:   countWorkDays(days),
+		Days:       days,
+	}, nil
+}
+
+// CalendarForUser returns the work calendar of the employee linked to a user
+func (s *WorkCalendarService) CalendarForUser(companyID, userID uuid.UUID, start, end time.Time) (*dtos.WorkCalendar, error) {
+	var user models.User
+	if err := s.db.Select("id", "employee_id").Limit(1).Find(&user, "id = ?", userID).Error; err != nil {
+		return nil, err
+	}
+	if user.EmployeeID == nil {
+		return nil, errors.New("employee not found")
+	}
+	return s.Calendar(companyID, *user.EmployeeID, start, end)
+}
@@ -0,0 +1,178 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+
+	"backend/internal/models"
+)
+
+// workCalendarTest is an employee without a shift in March 2025: Monday
+// March 17 is a federal holiday and Wednesday March 19 a company holiday
+type workCalendarTest struct {
+	db       *gorm.DB
+	service  *WorkCalendarService
+	company  *models.Company
+	employee *models.Employee
+	user     *models.User
+}
+
+func setupWorkCalendarTest(t *testing.T) *workCalendarTest {
+	db := setupPayrollTestDB(t)
+	require.NoError(t, db.AutoMigrate(
+		&models.User{}, &models.AbsenceRequest{}, &models.Holiday{}, &models.Shift{}, &models.EmployeeShiftBase{},
+		&models.ShiftException{}, &models.RotationPattern{}, &models.RotationPatternDay{}, &models.RotationAssignment{},
+	))
+	company := createPayrollTestCompany(t, db)
+	require.NoError(t, db.Create(&models.Holiday{CompanyID: company.ID, Name: "Aniversario", Date: calendarDay(19),
+		Year: 2025, HolidayType: "company", IsPaid: true, IsActive: true}).Error)
+	employee := createFixtureEmployee(t, db, company.ID, 1, "general", 400)
+	return &workCalendarTest{
+		db:       db,
+		service:  NewWorkCalendarService(db, nil),
+		company:  company,
+		employee: employee,
+		user:     createFixtureUser(t, db, employee, "employee"),
+	}
+}
+
+// mondayToSaturday assigns the employee a shift that also works Saturdays
+func (f *workCalendarTest) mondayToSaturday(t *testing.T) {
+	shift := &models.Shift{Name: "Operación", Code: "OPE", StartTime: "07:00", EndTime: "15:00", BreakMinutes: 30,
+		WorkHoursPerDay: 8, WorkDays: "[1,2,3,4,5,6]", CompanyID: f.company.ID, IsActive: true}
+	require.NoError(t, f.db.Create(shift).Error)
+	require.NoError(t, f.db.Model(f.employee).UpdateColumn("shift_id", shift.ID).Error)
+	f.employee.ShiftID = &shift.ID
+}
+
+// vacation is a whole-day vacation request of the employee
+func (f *workCalendarTest) vacation(start, end time.Time, days float64) *CreateAbsenceRequestInput {
+	return &CreateAbsenceRequestInput{EmployeeID: f.user.ID, RequestType: models.RequestTypeVacation,
+		StartDate: start, EndDate: end, TotalDays: days}
+}
+
+func calendarDay(d int) time.Time {
+	return time.Date(2025, 3, d, 0, 0, 0, 0, time.Local)
+}
+
+func TestWorkCalendar(t *testing.T) {
+	t.Run("without a shift the employee works Monday to Friday except holidays", func(t *testing.T) {
+		f := setupWorkCalendarTest(t)
+		days, err := f.service.Days(f.employee, calendarDay(14), calendarDay(19))
+		require.NoError(t, err)
+		require.Len(t, days, 6)
+		assert.True(t, days[0].IsWorkDay)
+		assert.True(t, days[1].IsRestDay)
+		assert.False(t, days[3].IsWorkDay)
+		assert.NotEmpty(t, days[3].Holiday)
+		assert.Equal(t, "Aniversario", days[5].Holiday)
+		assert.Equal(t, 2.0, countWorkDays(days))
+	})
+
+	t.Run("the shift decides the rest days", func(t *testing.T) {
+		f := setupWorkCalendarTest(t)
+		f.mondayToSaturday(t)
+		workDays, err := f.service.WorkDays(f.employee, calendarDay(14), calendarDay(19))
+		require.NoError(t, err)
+		assert.Equal(t, 3.0, workDays)
+
+		calendar, err := f.service.CalendarForUser(f.company.ID, f.user.ID, calendarDay(14), calendarDay(19))
+		require.NoError(t, err)
+		assert.Equal(t, 3.0, calendar.WorkDays)
+		assert.Len(t, calendar.Days, 6)
+	})
+
+	t.Run("a single work day keeps a half-day share", func(t *testing.T) {
+		f := setupWorkCalendarTest(t)
+		days, err := f.service.WorkDayQuantity(f.employee, calendarDay(14), calendarDay(14), 0.5)
+		require.NoError(t, err)
+		assert.Equal(t, 0.5, days)
+		days, err = f.service.WorkDayQuantity(f.employee, calendarDay(14), calendarDay(19), 0.5)
+		require.NoError(t, err)
+		assert.Equal(t, 2.0, days)
+		days, err = f.service.WorkDayQuantity(f.employee, calendarDay(15), calendarDay(16), 2)
+		require.NoError(t, err)
+		assert.Zero(t, days)
+	})
+}
+
+func TestWorkCalendar_AbsenceRequestDays(t *testing.T) {
+	t.Run("vacation requests take the work days they cover", func(t *testing.T) {
+		f := setupWorkCalendarTest(t)
+		f.mondayToSaturday(t)
+		requests := NewAbsenceRequestService(f.db, nil)
+		input := f.vacation(calendarDay(14), calendarDay(19), 6)
+		require.NoError(t, requests.countRequestDays(f.employee, input))
+		assert.Equal(t, 3.0, input.TotalDays)
+
+		input = f.vacation(calendarDay(16), calendarDay(17), 2)
+		assert.ErrorIs(t, requests.countRequestDays(f.employee, input), ErrInvalidAbsenceRequest)
+	})
+
+	t.Run("paid and unpaid days are split over the counted days", func(t *testing.T) {
+		f := setupWorkCalendarTest(t)
+		requests := NewAbsenceRequestService(f.db, nil)
+		paid, unpaid := 5.0, 1.0
+		input := f.vacation(calendarDay(10), calendarDay(16), 7) // 5 work days
+		input.RequestType = models.RequestTypePaidLeave
+		input.PaidDays, input.UnpaidDays = &paid, &unpaid
+		require.NoError(t, requests.countRequestDays(f.employee, input))
+		assert.Equal(t, 5.0, input.TotalDays)
+		assert.Equal(t, 5.0, *input.PaidDays)
+		assert.Equal(t, 0.0, *input.UnpaidDays)
+
+		onlyUnpaid := 1.0
+		input = f.vacation(calendarDay(14), calendarDay(18), 5) // Friday and Tuesday
+		input.UnpaidDays = &onlyUnpaid
+		require.NoError(t, requests.countRequestDays(f.employee, input))
+		assert.Equal(t, 2.0, input.TotalDays)
+		assert.Equal(t, 1.0, *input.PaidDays)
+		assert.Equal(t, 1.0, *input.UnpaidDays)
+	})
+
+	t.Run("only whole-day absences counted in work days", func(t *testing.T) {
+		assert.True(t, countsWorkDays(models.RequestTypeVacation, nil))
+		assert.False(t, countsWorkDays(models.RequestTypeSickLeave, nil))
+		assert.False(t, countsWorkDays(models.RequestTypeLeave, &models.LeavePolicy{}))
+		assert.True(t, countsWorkDays(models.RequestTypeLeave, &models.LeavePolicy{CountsWorkDays: true}))
+	})
+}
+
+func TestWorkCalendar_VacationIncidences(t *testing.T) {
+	f := setupWorkCalendarTest(t)
+	require.NoError(t, f.db.AutoMigrate(
+		&models.IncidenceCategory{}, &models.IncidenceType{}, &models.Incidence{}, &models.TimeOffBalance{}, &models.TimeOffAccrual{},
+	))
+	incidences := NewIncidenceService(f.db, nil)
+	vacation := &models.IncidenceType{Name: "Vacaciones", Category: "vacation", EffectType: "neutral"}
+	absence := &models.IncidenceType{Name: "Falta", Category: "absence", EffectType: "negative"}
+	require.NoError(t, f.db.Create(vacation).Error)
+	require.NoError(t, f.db.Create(absence).Error)
+	create := func(incidenceType *models.IncidenceType, start, end string, quantity float64) (*models.Incidence, error) {
+		return incidences.CreateIncidence(CreateIncidenceRequest{EmployeeID: f.employee.ID.String(), PayrollPeriodID: f.employee.ID.String(),
+			IncidenceTypeID: incidenceType.ID.String(), StartDate: start, EndDate: end, Quantity: quantity})
+	}
+
+	t.Run("HR vacation incidences charge the work days of their range", func(t *testing.T) {
+		incidence, err := create(vacation, "2025-03-14", "2025-03-19", 6)
+		require.NoError(t, err)
+		assert.Equal(t, 2.0, incidence.Quantity)
+
+		incidence, err = incidences.UpdateIncidence(incidence.ID, UpdateIncidenceRequest{EndDate: "2025-03-21"})
+		require.NoError(t, err)
+		assert.Equal(t, 4.0, incidence.Quantity)
+
+		_, err = create(vacation, "2025-03-15", "2025-03-17", 3)
+		assert.ErrorContains(t, err, "no work days")
+	})
+
+	t.Run("other incidences keep the quantity entered", func(t *testing.T) {
+		incidence, err := create(absence, "2025-03-14", "2025-03-19", 6)
+		require.NoError(t, err)
+		assert.Equal(t, 6.0, incidence.Quantity)
+	})
+}
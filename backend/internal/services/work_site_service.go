@@ -0,0 +1,584 @@
+/*
+Package services - Work Site Service
+
+==============================================================================
+FILE: internal/services/work_site_service.go
+==============================================================================
+
+DESCRIPTION:
+    Geofenced and device-bound clock-in for the portal and the mobile app.
+    Maintains work sites (GPS geofence plus allowed IP ranges), assigns
+    employees to them and registers one trusted device per employee.
+    TimeTrackingService.ClockIn/ClockOut call checkPunch, which places the
+    punch against the employee's site, checks the device, stores the selfie
+    and flags out-of-zone and unknown-device punches for review.
+
+USER PERSPECTIVE:
+    - A field technician clocks in at the client's site; the punch shows
+      how far from the site center it was made
+    - A punch from home or from a colleague's phone is recorded but lands in
+      the supervisor's review queue
+    - Sites that must not accept remote punches reject them outright
+
+DEVELOPER GUIDELINES:
+    ✅  OK to modify: Photo size limit, review scope
+    ⚠️  CAUTION: Coordinates, IP and device id come from the client; the
+        checks deter casual misuse, they do not prove presence
+    ⚠️  CAUTION: Manual punches (source "manual") are entered by HR and are
+        not checked; terminal punches never go through ClockIn
+    📝  Employees without a site are not geofenced, only device-checked
+
+SYNTAX EXPLANATION:
+    - Distance: haversine over a 6,371 km sphere, in meters
+    - In zone: within RadiusMeters of the center OR from an AllowedIPRanges
+      network; without coordinates only the IP can place the punch
+    - Photo: base64 JPEG or PNG (a data: URL is accepted), up to 5 MB,
+      stored under ClockPhotoDir as <employee>_<uuid>.<ext>
+    - ReviewStatus: set to pending whenever a punch is flagged; supervisors
+      see their reports' flagged punches, HR sees the whole company
+
+==============================================================================
+*/
+package services
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"log"
+	"math"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"gorm.io/gorm"
+
+	"backend/internal/dtos"
+	"backend/internal/models"
+	"backend/internal/models/enums"
+)
+
+// ClockPhotoDir is where clock-in selfies are stored
+const ClockPhotoDir = "uploads/clock-photos"
+
+const (
+	maxClockPhotoSize = 5 * 1024 * 1024
+	earthRadiusMeters = 6371000.0
+)
+
+// Punch check errors
+var (
+	ErrPunchOutOfZone     = errors.New("punch is outside the assigned work site")
+	ErrPunchPhotoRequired = errors.New("a photo is required to clock in at this work site")
+	ErrClockNotAllowed    = errors.New("not allowed to manage this employee's clock-in")
+)
+
+// siteManagerRoles maintain sites and devices and review every flagged punch
+var siteManagerRoles = []enums.UserRole{
+	enums.RoleAdmin, enums.RoleHR, enums.RoleHRAndPR, enums.RoleHRBlueGray, enums.RoleHRWhite,
+}
+
+// WorkSiteService manages work sites, trusted devices and flagged punches
+type WorkSiteService struct {
+	db           *gorm.DB
+	orgStructure *OrgStructureService
+	photoDir     string
+}
+
+// NewWorkSiteService creates a new work site service
+func NewWorkSiteService(db *gorm.DB) *WorkSiteService {
+	return &WorkSiteService{db: db, orgStructure: NewOrgStructureService(db), photoDir: ClockPhotoDir}
+}
+
+// =========================================================================
+// Sites
+// =========================================================================
+
+// ListSites returns the company's work sites
+func (s *WorkSiteService) ListSites(companyID uuid.UUID) ([]models.WorkSite, error) {
+	var sites []models.WorkSite
+	if err := s.db.Where("company_id = ?", companyID).Order("name").Find(&sites).Error; err != nil {
+		return nil, fmt.Errorf("error fetching work sites: %w", err)
+	}
+	return sites, nil
+}
+
+// CreateSite creates a work site
+func (s *WorkSiteService) CreateSite(companyID uuid.UUID, req dtos.WorkSiteRequest) (*models.WorkSite, error) {
+	site := &models.WorkSite{CompanyID: companyID, IsActive: true}
+	if err := s.applySite(site, req); err != nil {
+		return nil, err
+	}
+	var count int64
+	if err := s.db.Model(&models.WorkSite{}).Where("company_id = ? AND code = ?", companyID, site.Code).Count(&count).Error; err != nil {
+		return nil, err
+	}
+	if count > 0 {
+		return nil, errors.New("a work site with this code already exists")
+	}
+	if err := s.db.Create(site).Error; err != nil {
+		return nil, fmt.Errorf("error creating work site: %w", err)
+	}
+	// Zero values of defaulted columns are replaced on create
+	if err := s.db.Model(site).Select("block_out_of_zone", "require_trusted_device", "require_photo", "is_active").
+		Updates(site).Error; err != nil {
+		return nil, fmt.Errorf("error creating work site: %w", err)
+	}
+	return site, nil
+}
+
+// UpdateSite updates a work site
+func (s *WorkSiteService) UpdateSite(id, companyID uuid.UUID, req dtos.WorkSiteRequest) (*models.WorkSite, error) {
+	var site models.WorkSite
+	if err := s.db.First(&site, "id = ? AND company_id = ?", id, companyID).Error; err != nil {
+		return nil, errors.New("work site not found")
+	}
+	if err := s.applySite(&site, req); err != nil {
+		return nil, err
+	}
+	var count int64
+	if err := s.db.Model(&models.WorkSite{}).Where("company_id = ? AND code = ? AND id <> ?", companyID, site.Code, id).Count(&count).Error; err != nil {
+		return nil, err
+	}
+	if count > 0 {
+		return nil, errors.New("a work site with this code already exists")
+	}
+	if err := s.db.Save(&site).Error; err != nil {
+		return nil, fmt.Errorf("error updating work site: %w", err)
+	}
+	return &site, nil
+}
+
+// DeleteSite deletes a work site nobody is assigned to
+func (s *WorkSiteService) DeleteSite(id, companyID uuid.UUID) error {
+	var site models.WorkSite
+	if err := s.db.First(&site, "id = ? AND company_id = ?", id, companyID).Error; err != nil {
+		return errors.New("work site not found")
+	}
+	var count int64
+	if err := s.db.Model(&models.EmployeeWorkSite{}).Where("work_site_id = ?", id).Count(&count).Error; err != nil {
+		return err
+	}
+	if count > 0 {
+		return errors.New("work site cannot be deleted while employees are assigned to it")
+	}
+	return s.db.Delete(&site).Error
+}
+
+// applySite copies a request onto a site, validating the IP ranges
+func (s *WorkSiteService) applySite(site *models.WorkSite, req dtos.WorkSiteRequest) error {
+	ranges := make(pq.StringArray, 0, len(req.AllowedIPRanges))
+	for _, raw := range req.AllowedIPRanges {
+		raw = strings.TrimSpace(raw)
+		if raw == "" {
+			continue
+		}
+		if _, _, err := net.ParseCIDR(raw); err != nil && net.ParseIP(raw) == nil {
+			return fmt.Errorf("allowed IP range %q must be an IP or CIDR block", raw)
+		}
+		ranges = append(ranges, raw)
+	}
+	site.Name = strings.TrimSpace(req.Name)
+	site.Code = strings.ToUpper(strings.TrimSpace(req.Code))
+	site.Address = req.Address
+	site.Latitude = req.Latitude
+	site.Longitude = req.Longitude
+	site.RadiusMeters = req.RadiusMeters
+	site.AllowedIPRanges = ranges
+	site.BlockOutOfZone = req.BlockOutOfZone
+	site.RequireTrustedDevice = req.RequireTrustedDevice
+	site.RequirePhoto = req.RequirePhoto
+	if req.IsActive != nil {
+		site.IsActive = *req.IsActive
+	}
+	return nil
+}
+
+// ListSiteEmployees returns the assignments of a site
+func (s *WorkSiteService) ListSiteEmployees(id, companyID uuid.UUID) ([]models.EmployeeWorkSite, error) {
+	var assignments []models.EmployeeWorkSite
+	if err := s.db.Preload("Employee").Where("work_site_id = ? AND company_id = ?", id, companyID).
+		Find(&assignments).Error; err != nil {
+		return nil, fmt.Errorf("error fetching site employees: %w", err)
+	}
+	return assignments, nil
+}
+
+// AssignEmployees puts employees on a site, moving them from their previous one
+func (s *WorkSiteService) AssignEmployees(id, companyID uuid.UUID, req dtos.WorkSiteAssignRequest, userID uuid.UUID) (int, error) {
+	var site models.WorkSite
+	if err := s.db.First(&site, "id = ? AND company_id = ?", id, companyID).Error; err != nil {
+		return 0, errors.New("work site not found")
+	}
+	var count int64
+	if err := s.db.Model(&models.Employee{}).Where("id IN ? AND company_id = ?", req.EmployeeIDs, companyID).Count(&count).Error; err != nil {
+		return 0, err
+	}
+	if int(count) != len(req.EmployeeIDs) {
+		return 0, errors.New("employee not found")
+	}
+
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		for _, employeeID := range req.EmployeeIDs {
+			var assignment models.EmployeeWorkSite
+			err := tx.Where("employee_id = ?", employeeID).First(&assignment).Error
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				assignment = models.EmployeeWorkSite{CompanyID: companyID, EmployeeID: employeeID}
+			} else if err != nil {
+				return err
+			}
+			assignment.WorkSiteID = site.ID
+			assignment.AssignedBy = &userID
+			if err := tx.Save(&assignment).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, fmt.Errorf("error assigning employees: %w", err)
+	}
+	return len(req.EmployeeIDs), nil
+}
+
+// UnassignEmployee removes an employee from a site; their punches are no longer geofenced
+func (s *WorkSiteService) UnassignEmployee(siteID, employeeID, companyID uuid.UUID) error {
+	result := s.db.Where("work_site_id = ? AND employee_id = ? AND company_id = ?", siteID, employeeID, companyID).
+		Delete(&models.EmployeeWorkSite{})
+	if result.Error != nil {
+		return fmt.Errorf("error removing site assignment: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return errors.New("site assignment not found")
+	}
+	return nil
+}
+
+// =========================================================================
+// Trusted devices
+// =========================================================================
+
+// RegisterDevice binds a device to an employee. Employees register their
+// own; HR can register for anyone. A second device needs the first revoked.
+func (s *WorkSiteService) RegisterDevice(companyID, userID uuid.UUID, req dtos.TrustedDeviceRequest) (*models.TrustedDevice, error) {
+	var user models.User
+	if err := s.db.First(&user, "id = ? AND company_id = ?", userID, companyID).Error; err != nil {
+		return nil, errors.New("user not found")
+	}
+	employeeID := req.EmployeeID
+	if employeeID == nil {
+		employeeID = user.EmployeeID
+	}
+	if employeeID == nil {
+		return nil, errors.New("employee record is required to register a device")
+	}
+	if !sameUUID(employeeID, user.EmployeeID) && !hasUserRole(user.Role, siteManagerRoles) {
+		return nil, ErrClockNotAllowed
+	}
+	var employee models.Employee
+	if err := s.db.First(&employee, "id = ? AND company_id = ?", *employeeID, companyID).Error; err != nil {
+		return nil, errors.New("employee not found")
+	}
+
+	deviceID := strings.TrimSpace(req.DeviceID)
+	var current models.TrustedDevice
+	if err := s.db.Where("employee_id = ? AND is_active = ?", employee.ID, true).First(&current).Error; err == nil {
+		if current.DeviceID == deviceID {
+			return &current, nil
+		}
+		return nil, errors.New("a trusted device already exists for this employee; it must be revoked first")
+	}
+
+	device := &models.TrustedDevice{
+		CompanyID:    companyID,
+		EmployeeID:   employee.ID,
+		DeviceID:     deviceID,
+		Name:         req.Name,
+		Platform:     req.Platform,
+		IsActive:     true,
+		RegisteredBy: userID,
+	}
+	if err := s.db.Create(device).Error; err != nil {
+		return nil, fmt.Errorf("error registering device: %w", err)
+	}
+	return device, nil
+}
+
+// ListDevices returns the company's devices, optionally of one employee
+func (s *WorkSiteService) ListDevices(companyID uuid.UUID, employeeID *uuid.UUID, activeOnly bool) ([]models.TrustedDevice, error) {
+	query := s.db.Preload("Employee").Where("company_id = ?", companyID)
+	if employeeID != nil {
+		query = query.Where("employee_id = ?", *employeeID)
+	}
+	if activeOnly {
+		query = query.Where("is_active = ?", true)
+	}
+	var devices []models.TrustedDevice
+	if err := query.Order("created_at DESC").Find(&devices).Error; err != nil {
+		return nil, fmt.Errorf("error fetching devices: %w", err)
+	}
+	return devices, nil
+}
+
+// RevokeDevice deactivates a trusted device so the employee can register another
+func (s *WorkSiteService) RevokeDevice(id, companyID, userID uuid.UUID) (*models.TrustedDevice, error) {
+	var device models.TrustedDevice
+	if err := s.db.First(&device, "id = ? AND company_id = ?", id, companyID).Error; err != nil {
+		return nil, errors.New("device not found")
+	}
+	if !device.IsActive {
+		return nil, errors.New("device is not active")
+	}
+	now := time.Now()
+	device.IsActive = false
+	device.RevokedBy = &userID
+	device.RevokedAt = &now
+	if err := s.db.Save(&device).Error; err != nil {
+		return nil, fmt.Errorf("error revoking device: %w", err)
+	}
+	return &device, nil
+}
+
+// =========================================================================
+// Punch checks
+// =========================================================================
+
+// checkPunch places a web/app punch against the employee's site and trusted
+// device, stores its photo and flags the record when something is off
+func (s *WorkSiteService) checkPunch(companyID, employeeID uuid.UUID, punch *ClockPunchDTO, record *models.ClockRecord, clockIn bool) error {
+	if punch.Source == "manual" {
+		return nil
+	}
+
+	var assignment models.EmployeeWorkSite
+	var site *models.WorkSite
+	if err := s.db.Preload("WorkSite").Where("employee_id = ?", employeeID).First(&assignment).Error; err == nil &&
+		assignment.WorkSite != nil && assignment.WorkSite.IsActive {
+		site = assignment.WorkSite
+	}
+
+	var distance *float64
+	outOfZone := false
+	if site != nil {
+		record.WorkSiteID = &site.ID
+		if punch.Latitude != nil && punch.Longitude != nil {
+			d := math.Round(haversineMeters(site.Latitude, site.Longitude, *punch.Latitude, *punch.Longitude))
+			distance = &d
+		}
+		inZone := (distance != nil && *distance <= site.RadiusMeters) || ipAllowed(punch.IP, site.AllowedIPRanges)
+		if !inZone {
+			if site.BlockOutOfZone {
+				return ErrPunchOutOfZone
+			}
+			outOfZone = true
+		}
+		if site.RequirePhoto && strings.TrimSpace(punch.Photo) == "" {
+			return ErrPunchPhotoRequired
+		}
+	}
+
+	unknownDevice := false
+	var device models.TrustedDevice
+	if err := s.db.Where("employee_id = ? AND is_active = ?", employeeID, true).First(&device).Error; err == nil {
+		if strings.TrimSpace(punch.DeviceID) != device.DeviceID {
+			unknownDevice = true
+		} else {
+			// Bookkeeping only: a failed timestamp must not reject the punch
+			if err := s.db.Model(&device).Update("last_used_at", time.Now()).Error; err != nil {
+				log.Printf("Error updating last use of trusted device %s: %v", device.ID, err)
+			}
+		}
+	} else if site != nil && site.RequireTrustedDevice {
+		unknownDevice = true
+	}
+
+	photo := ""
+	if strings.TrimSpace(punch.Photo) != "" {
+		name, err := s.savePunchPhoto(employeeID, punch.Photo)
+		if err != nil {
+			return err
+		}
+		photo = name
+	}
+
+	if clockIn {
+		record.ClockInLatitude, record.ClockInLongitude = punch.Latitude, punch.Longitude
+		record.ClockInDistanceM = distance
+		record.ClockInDeviceID = punch.DeviceID
+		record.ClockInPhoto = photo
+	} else {
+		record.ClockOutLatitude, record.ClockOutLongitude = punch.Latitude, punch.Longitude
+		record.ClockOutDistanceM = distance
+		record.ClockOutDeviceID = punch.DeviceID
+		record.ClockOutPhoto = photo
+	}
+	if outOfZone || unknownDevice {
+		record.OutOfZone = record.OutOfZone || outOfZone
+		record.UnknownDevice = record.UnknownDevice || unknownDevice
+		record.ReviewStatus = models.ClockReviewPending
+	}
+	return nil
+}
+
+// savePunchPhoto decodes a base64 JPEG/PNG and stores it, returning the file name
+func (s *WorkSiteService) savePunchPhoto(employeeID uuid.UUID, encoded string) (string, error) {
+	if i := strings.Index(encoded, ";base64,"); strings.HasPrefix(encoded, "data:") && i > 0 {
+		encoded = encoded[i+len(";base64,"):]
+	}
+	data, err := base64.StdEncoding.DecodeString(strings.TrimSpace(encoded))
+	if err != nil {
+		return "", errors.New("photo must be base64 encoded")
+	}
+	if len(data) > maxClockPhotoSize {
+		return "", errors.New("photo must not exceed 5 MB")
+	}
+	ext := ""
+	switch http.DetectContentType(data) {
+	case "image/jpeg":
+		ext = ".jpg"
+	case "image/png":
+		ext = ".png"
+	default:
+		return "", errors.New("photo must be a JPEG or PNG image")
+	}
+
+	if err := os.MkdirAll(s.photoDir, 0755); err != nil {
+		return "", fmt.Errorf("error storing photo: %w", err)
+	}
+	name := fmt.Sprintf("%s_%s%s", employeeID, uuid.New(), ext)
+	if err := os.WriteFile(filepath.Join(s.photoDir, name), data, 0644); err != nil {
+		return "", fmt.Errorf("error storing photo: %w", err)
+	}
+	return name, nil
+}
+
+// PunchPhotoPath returns the stored photo of a record's clock-in or clock-out
+func (s *WorkSiteService) PunchPhotoPath(recordID, companyID uuid.UUID, side string) (string, error) {
+	var record models.ClockRecord
+	if err := s.db.First(&record, "id = ? AND company_id = ?", recordID, companyID).Error; err != nil {
+		return "", errors.New("clock record not found")
+	}
+	name := record.ClockInPhoto
+	if side == "out" {
+		name = record.ClockOutPhoto
+	}
+	if name == "" {
+		return "", errors.New("photo not found")
+	}
+	return filepath.Join(s.photoDir, filepath.Base(name)), nil
+}
+
+// haversineMeters is the great-circle distance between two coordinates
+func haversineMeters(lat1, lon1, lat2, lon2 float64) float64 {
+	rad := math.Pi / 180
+	dLat := (lat2 - lat1) * rad
+	dLon := (lon2 - lon1) * rad
+	a := math.Sin(dLat/2)*math.Sin(dLat/2) +
+		math.Cos(lat1*rad)*math.Cos(lat2*rad)*math.Sin(dLon/2)*math.Sin(dLon/2)
+	return 2 * earthRadiusMeters * math.Asin(math.Min(1, math.Sqrt(a)))
+}
+
+// ipAllowed reports whether an IP belongs to one of the ranges
+func ipAllowed(raw string, ranges []string) bool {
+	ip := net.ParseIP(strings.TrimSpace(raw))
+	if ip == nil {
+		return false
+	}
+	for _, r := range ranges {
+		if _, network, err := net.ParseCIDR(r); err == nil {
+			if network.Contains(ip) {
+				return true
+			}
+		} else if allowed := net.ParseIP(r); allowed != nil && allowed.Equal(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+// =========================================================================
+// Review
+// =========================================================================
+
+// ListFlagged returns the flagged punches the user reviews: their reports'
+// for supervisors, the whole company's for HR. Status defaults to pending.
+func (s *WorkSiteService) ListFlagged(companyID, userID uuid.UUID, status string) ([]models.ClockRecord, error) {
+	var user models.User
+	if err := s.db.First(&user, "id = ? AND company_id = ?", userID, companyID).Error; err != nil {
+		return nil, errors.New("user not found")
+	}
+	if status == "" {
+		status = models.ClockReviewPending
+	}
+	query := s.db.Preload("Employee").Where("company_id = ? AND review_status = ?", companyID, status)
+	if !hasUserRole(user.Role, siteManagerRoles) {
+		if user.EmployeeID == nil {
+			return []models.ClockRecord{}, nil
+		}
+		reports, err := s.orgStructure.SubordinateIDs(*user.EmployeeID, false)
+		if err != nil {
+			return nil, err
+		}
+		if len(reports) == 0 {
+			return []models.ClockRecord{}, nil
+		}
+		query = query.Where("employee_id IN ?", reports)
+	}
+	var records []models.ClockRecord
+	if err := query.Order("clock_in_time DESC").Find(&records).Error; err != nil {
+		return nil, fmt.Errorf("error fetching flagged punches: %w", err)
+	}
+	return records, nil
+}
+
+// ReviewPunch accepts or rejects a flagged punch
+func (s *WorkSiteService) ReviewPunch(id, companyID, reviewerID uuid.UUID, req dtos.ClockReviewRequest) (*models.ClockRecord, error) {
+	var record models.ClockRecord
+	if err := s.db.First(&record, "id = ? AND company_id = ?", id, companyID).Error; err != nil {
+		return nil, errors.New("clock record not found")
+	}
+	if record.ReviewStatus != models.ClockReviewPending {
+		return nil, errors.New("clock record must be pending review")
+	}
+	var reviewer models.User
+	if err := s.db.First(&reviewer, "id = ? AND company_id = ?", reviewerID, companyID).Error; err != nil {
+		return nil, errors.New("user not found")
+	}
+	if sameUUID(reviewer.EmployeeID, &record.EmployeeID) {
+		return nil, ErrClockNotAllowed
+	}
+	if !hasUserRole(reviewer.Role, siteManagerRoles) {
+		if reviewer.EmployeeID == nil {
+			return nil, ErrClockNotAllowed
+		}
+		chain, err := s.orgStructure.ManagerChain(record.EmployeeID)
+		if err != nil {
+			return nil, err
+		}
+		manages := false
+		for _, managerID := range chain {
+			manages = manages || managerID == *reviewer.EmployeeID
+		}
+		if !manages {
+			return nil, ErrClockNotAllowed
+		}
+	}
+
+	now := time.Now()
+	record.ReviewStatus = models.ClockReviewAccepted
+	if req.Action == "reject" {
+		record.ReviewStatus = models.ClockReviewRejected
+	}
+	record.ReviewedByID = &reviewerID
+	record.ReviewedAt = &now
+	record.ReviewNotes = req.Notes
+	if err := s.db.Save(&record).Error; err != nil {
+		return nil, fmt.Errorf("error reviewing clock record: %w", err)
+	}
+	return &record, nil
+}
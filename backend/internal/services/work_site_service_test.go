@@ -0,0 +1,132 @@
+package services
+
+import (
+	"encoding/base64"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"backend/internal/dtos"
+	"backend/internal/models"
+)
+
+func TestWorkSite_GeofenceDevicesAndReview(t *testing.T) {
+	db := setupPayrollTestDB(t)
+	require.NoError(t, db.AutoMigrate(
+		&models.User{}, &models.ReportingLine{}, &models.EmployeeHierarchy{}, &models.ClockRecord{},
+		&models.TimePolicy{}, &models.TimePolicyViolation{},
+		&models.WorkSite{}, &models.EmployeeWorkSite{}, &models.TrustedDevice{},
+	))
+	company := createPayrollTestCompany(t, db)
+	timeTracking := NewTimeTrackingService(db)
+	timeTracking.sites.photoDir = t.TempDir()
+	service := timeTracking.sites
+
+	technician := createFixtureEmployee(t, db, company.ID, 1, "general", 400)
+	supervisorEmployee := createFixtureEmployee(t, db, company.ID, 2, "general", 400)
+	otherSupervisorEmployee := createFixtureEmployee(t, db, company.ID, 3, "general", 400)
+	technicianUser := createFixtureUser(t, db, technician, "employee")
+	supervisor := createFixtureUser(t, db, supervisorEmployee, "supervisor")
+	otherSupervisor := createFixtureUser(t, db, otherSupervisorEmployee, "supervisor")
+	hr := createFixtureUser(t, db, createFixtureEmployee(t, db, company.ID, 4, "general", 400), "hr")
+	_, err := NewOrgStructureService(db).SetManager(company.ID, dtos.ReportingLineRequest{
+		EmployeeID: technician.ID, ManagerID: &supervisorEmployee.ID, EffectiveFrom: dtos.Date{Time: time.Now().AddDate(0, -1, 0)},
+	}, hr.ID)
+	require.NoError(t, err)
+
+	_, err = service.CreateSite(company.ID, dtos.WorkSiteRequest{Name: "Bad", Code: "BAD", RadiusMeters: 100,
+		AllowedIPRanges: []string{"10.0.0.0/33"}})
+	assert.ErrorContains(t, err, "must be an IP or CIDR block")
+	site, err := service.CreateSite(company.ID, dtos.WorkSiteRequest{Name: "Almacén cliente", Code: "alm",
+		Latitude: 19.4326, Longitude: -99.1332, RadiusMeters: 200, AllowedIPRanges: []string{"10.0.0.0/8", "201.150.10.7"}})
+	require.NoError(t, err)
+	assert.Equal(t, "ALM", site.Code)
+	assert.False(t, site.BlockOutOfZone)
+	assigned, err := service.AssignEmployees(site.ID, company.ID, dtos.WorkSiteAssignRequest{EmployeeIDs: []uuid.UUID{technician.ID}}, hr.ID)
+	require.NoError(t, err)
+	assert.Equal(t, 1, assigned)
+	assert.ErrorContains(t, service.DeleteSite(site.ID, company.ID), "cannot be deleted")
+
+	// One trusted device per employee; colleagues cannot register for each other
+	phone, err := service.RegisterDevice(company.ID, technicianUser.ID, dtos.TrustedDeviceRequest{DeviceID: "phone-0001", Platform: "android"})
+	require.NoError(t, err)
+	_, err = service.RegisterDevice(company.ID, technicianUser.ID, dtos.TrustedDeviceRequest{DeviceID: "phone-0002"})
+	assert.ErrorContains(t, err, "already exists")
+	_, err = service.RegisterDevice(company.ID, otherSupervisor.ID, dtos.TrustedDeviceRequest{EmployeeID: &technician.ID, DeviceID: "phone-0003"})
+	assert.ErrorIs(t, err, ErrClockNotAllowed)
+
+	at := func(lat float64) (*float64, *float64) {
+		lon := -99.1332
+		return &lat, &lon
+	}
+	clock := func(in bool, lat float64, ip, device string) (*models.ClockRecord, error) {
+		latitude, longitude := at(lat)
+		punch := ClockPunchDTO{Source: "app", IP: ip, Latitude: latitude, Longitude: longitude, DeviceID: device}
+		if in {
+			return timeTracking.ClockIn(company.ID, technician.ID, punch)
+		}
+		return timeTracking.ClockOut(technician.ID, punch)
+	}
+
+	// 50 m from the center on the registered phone; clock-out placed by the office network
+	record, err := clock(true, 19.43305, "187.1.1.1", "phone-0001")
+	require.NoError(t, err)
+	assert.InDelta(t, 50, *record.ClockInDistanceM, 1)
+	assert.Empty(t, record.ReviewStatus)
+	record, err = clock(false, 19.4776, "10.20.30.40", "phone-0001")
+	require.NoError(t, err)
+	assert.False(t, record.OutOfZone)
+	assert.InDelta(t, 5000, *record.ClockOutDistanceM, 10)
+
+	// 5 km away from a home network and another phone
+	record, err = clock(true, 19.4776, "187.1.1.1", "tablet-9999")
+	require.NoError(t, err)
+	assert.True(t, record.OutOfZone)
+	assert.True(t, record.UnknownDevice)
+	assert.Equal(t, models.ClockReviewPending, record.ReviewStatus)
+	_, err = clock(false, 19.4326, "201.150.10.7", "phone-0001")
+	require.NoError(t, err)
+
+	flagged, err := service.ListFlagged(company.ID, supervisor.ID, "")
+	require.NoError(t, err)
+	require.Len(t, flagged, 1)
+	assert.Equal(t, record.ID, flagged[0].ID)
+	flagged, err = service.ListFlagged(company.ID, otherSupervisor.ID, "")
+	require.NoError(t, err)
+	assert.Empty(t, flagged, "supervisors only see their reports")
+	_, err = service.ReviewPunch(record.ID, company.ID, otherSupervisor.ID, dtos.ClockReviewRequest{Action: "accept"})
+	assert.ErrorIs(t, err, ErrClockNotAllowed)
+	reviewed, err := service.ReviewPunch(record.ID, company.ID, supervisor.ID, dtos.ClockReviewRequest{Action: "reject", Notes: "Fuera de sitio"})
+	require.NoError(t, err)
+	assert.Equal(t, models.ClockReviewRejected, reviewed.ReviewStatus)
+	assert.True(t, reviewed.IsComplete, "the clock-out was kept")
+
+	// Strict site: out-of-zone punches are refused and a selfie is required
+	_, err = service.UpdateSite(site.ID, company.ID, dtos.WorkSiteRequest{Name: site.Name, Code: site.Code,
+		Latitude: site.Latitude, Longitude: site.Longitude, RadiusMeters: 200, BlockOutOfZone: true, RequirePhoto: true})
+	require.NoError(t, err)
+	_, err = clock(true, 19.4776, "10.20.30.40", "phone-0001")
+	assert.ErrorIs(t, err, ErrPunchOutOfZone, "the IP ranges were removed")
+	_, err = clock(true, 19.4326, "187.1.1.1", "phone-0001")
+	assert.ErrorIs(t, err, ErrPunchPhotoRequired)
+
+	png := append([]byte("\x89PNG\r\n\x1a\n"), make([]byte, 64)...)
+	latitude, longitude := at(19.4326)
+	record, err = timeTracking.ClockIn(company.ID, technician.ID, ClockPunchDTO{Source: "web", Latitude: latitude, Longitude: longitude,
+		DeviceID: "phone-0001", Photo: "data:image/png;base64," + base64.StdEncoding.EncodeToString(png)})
+	require.NoError(t, err)
+	path, err := service.PunchPhotoPath(record.ID, company.ID, "in")
+	require.NoError(t, err)
+	stored, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, png, stored)
+
+	_, err = service.RevokeDevice(phone.ID, company.ID, hr.ID)
+	require.NoError(t, err)
+	_, err = service.RegisterDevice(company.ID, technicianUser.ID, dtos.TrustedDeviceRequest{DeviceID: "phone-0002"})
+	assert.NoError(t, err, "a new device can be registered once the old one is revoked")
+}
@@ -1,6 +1,7 @@
 package utils
 
 import (
+	"fmt"
 	"time"
 )
 
@@ -59,44 +60,44 @@ func (c *BusinessDayCalculator) isHoliday(date time.Time) bool {
 // loadMexicanHolidays loads Mexican federal holidays for the given year
 // Based on Mexican labor law (Ley Federal del Trabajo)
 func (c *BusinessDayCalculator) loadMexicanHolidays(year int) {
-	// Fixed holidays (Article 74 of Mexican Labor Law)
-	fixedHolidays := []time.Time{
-		time.Date(year, 1, 1, 0, 0, 0, 0, time.UTC),   // New Year's Day (Año Nuevo)
-		time.Date(year, 5, 1, 0, 0, 0, 0, time.UTC),   // Labor Day (Día del Trabajo)
-		time.Date(year, 9, 16, 0, 0, 0, 0, time.UTC),  // Independence Day (Independencia de México)
-		time.Date(year, 12, 25, 0, 0, 0, 0, time.UTC), // Christmas (Navidad)
+	for date := range MexicanFederalHolidays(year) {
+		c.holidays[date] = true
 	}
+}
+
+// LoadYear adds the federal holidays of another year to the calculator
+// Needed when a date range crosses a year boundary (e.g. a December payment calendar)
+func (c *BusinessDayCalculator) LoadYear(year int) {
+	c.loadMexicanHolidays(year)
+}
 
-	// Add fixed holidays to map
-	for _, holiday := range fixedHolidays {
-		c.holidays[holiday.Format("2006-01-02")] = true
+// MexicanFederalHolidays returns the mandatory rest days of Article 74 LFT for a year
+// Map of "YYYY-MM-DD" → holiday name
+func MexicanFederalHolidays(year int) map[string]string {
+	holidays := map[string]string{
+		// Fixed holidays (Article 74 of Mexican Labor Law)
+		fmt.Sprintf("%d-01-01", year): "Año Nuevo",
+		fmt.Sprintf("%d-05-01", year): "Día del Trabajo",
+		fmt.Sprintf("%d-09-16", year): "Día de la Independencia",
+		fmt.Sprintf("%d-12-25", year): "Navidad",
 	}
 
 	// Moveable holidays (observed on specific Mondays)
-	c.addFirstMondayOf(year, time.February)  // Constitution Day (Día de la Constitución) - 1st Monday of February
-	c.addThirdMondayOf(year, time.March)     // Benito Juárez's Birthday - 3rd Monday of March
-	c.addThirdMondayOf(year, time.November)  // Revolution Day (Revolución Mexicana) - 3rd Monday of November
-
-	// President's Inauguration Day (only on inauguration years - every 6 years, e.g., 2024, 2030)
-	// December 1st on inauguration years - for simplicity, not adding special logic here
-	// Can be added if needed: if year%6 == 0 { c.holidays["YYYY-12-01"] = true }
-}
+	holidays[nthWeekdayOfMonth(year, time.February, time.Monday, 1).Format("2006-01-02")] = "Día de la Constitución"
+	holidays[nthWeekdayOfMonth(year, time.March, time.Monday, 3).Format("2006-01-02")] = "Natalicio de Benito Juárez"
+	holidays[nthWeekdayOfMonth(year, time.November, time.Monday, 3).Format("2006-01-02")] = "Día de la Revolución"
 
-// addFirstMondayOf adds the first Monday of a given month as a holiday
-func (c *BusinessDayCalculator) addFirstMondayOf(year int, month time.Month) {
-	monday := c.findNthWeekdayOfMonth(year, month, time.Monday, 1)
-	c.holidays[monday.Format("2006-01-02")] = true
-}
+	// Transmisión del Poder Ejecutivo Federal (October 1st every six years since 2024)
+	if year >= 2024 && (year-2024)%6 == 0 {
+		holidays[fmt.Sprintf("%d-10-01", year)] = "Transmisión del Poder Ejecutivo Federal"
+	}
 
-// addThirdMondayOf adds the third Monday of a given month as a holiday
-func (c *BusinessDayCalculator) addThirdMondayOf(year int, month time.Month) {
-	monday := c.findNthWeekdayOfMonth(year, month, time.Monday, 3)
-	c.holidays[monday.Format("2006-01-02")] = true
+	return holidays
 }
 
-// findNthWeekdayOfMonth finds the Nth occurrence of a weekday in a month
+// nthWeekdayOfMonth finds the Nth occurrence of a weekday in a month
 // Example: 3rd Monday of March 2024
-func (c *BusinessDayCalculator) findNthWeekdayOfMonth(year int, month time.Month, weekday time.Weekday, n int) time.Time {
+func nthWeekdayOfMonth(year int, month time.Month, weekday time.Weekday, n int) time.Time {
 	// Start at the 1st of the month
 	current := time.Date(year, month, 1, 0, 0, 0, 0, time.UTC)
 
@@ -139,3 +140,27 @@ func (c *BusinessDayCalculator) GetHolidays() []string {
 func (c *BusinessDayCalculator) IsBusinessDay(date time.Time) bool {
 	return !c.isWeekend(date) && !c.isHoliday(date)
 }
+
+// PreviousBusinessDay returns the date itself if it is a business day, otherwise
+// the closest earlier business day. Used to move payment dates that fall on a
+// weekend or holiday (LFT Art. 88: pay before, never after, the due date)
+func (c *BusinessDayCalculator) PreviousBusinessDay(date time.Time) time.Time {
+	current := date
+	for !c.IsBusinessDay(current) {
+		current = current.AddDate(0, 0, -1)
+	}
+	return current
+}
+
+// SubtractBusinessDays moves back n business days from the given date
+// Example: 2 business days before Monday = previous Thursday
+func (c *BusinessDayCalculator) SubtractBusinessDays(date time.Time, n int) time.Time {
+	current := date
+	for n > 0 {
+		current = current.AddDate(0, 0, -1)
+		if c.IsBusinessDay(current) {
+			n--
+		}
+	}
+	return current
+}
@@ -232,3 +232,55 @@ func TestGetHolidays(t *testing.T) {
 
 	t.Logf("2025 Mexican federal holidays: %v", holidays)
 }
+
+func TestMexicanFederalHolidays_ExecutiveTransition(t *testing.T) {
+	// October 1st is a rest day only in years of presidential transition (2024, 2030, ...)
+	if _, ok := MexicanFederalHolidays(2030)["2030-10-01"]; !ok {
+		t.Errorf("2030-10-01 should be a federal holiday")
+	}
+	if _, ok := MexicanFederalHolidays(2025)["2025-10-01"]; ok {
+		t.Errorf("2025-10-01 should not be a federal holiday")
+	}
+}
+
+func TestPreviousBusinessDay(t *testing.T) {
+	calc := NewBusinessDayCalculator(2025)
+
+	testCases := []struct {
+		date     time.Time
+		expected time.Time
+		reason   string
+	}{
+		{time.Date(2025, 1, 6, 0, 0, 0, 0, time.UTC), time.Date(2025, 1, 6, 0, 0, 0, 0, time.UTC), "Monday stays"},
+		{time.Date(2025, 3, 15, 0, 0, 0, 0, time.UTC), time.Date(2025, 3, 14, 0, 0, 0, 0, time.UTC), "Saturday moves to Friday"},
+		{time.Date(2025, 3, 17, 0, 0, 0, 0, time.UTC), time.Date(2025, 3, 14, 0, 0, 0, 0, time.UTC), "Benito Juárez Monday moves to Friday"},
+		{time.Date(2025, 12, 25, 0, 0, 0, 0, time.UTC), time.Date(2025, 12, 24, 0, 0, 0, 0, time.UTC), "Christmas moves to Dec 24"},
+	}
+
+	for _, tc := range testCases {
+		result := calc.PreviousBusinessDay(tc.date)
+		if !result.Equal(tc.expected) {
+			t.Errorf("%s: expected %s, got %s", tc.reason, tc.expected.Format("2006-01-02"), result.Format("2006-01-02"))
+		}
+	}
+}
+
+func TestSubtractBusinessDays_SkipsWeekendAndHoliday(t *testing.T) {
+	calc := NewBusinessDayCalculator(2025)
+
+	// 2 business days before Tuesday Mar 18, 2025: Mon 17 is a holiday → Fri 14, Thu 13
+	result := calc.SubtractBusinessDays(time.Date(2025, 3, 18, 0, 0, 0, 0, time.UTC), 2)
+	expected := time.Date(2025, 3, 13, 0, 0, 0, 0, time.UTC)
+	if !result.Equal(expected) {
+		t.Errorf("Expected %s, got %s", expected.Format("2006-01-02"), result.Format("2006-01-02"))
+	}
+}
+
+func TestLoadYear_CrossYearRange(t *testing.T) {
+	calc := NewBusinessDayCalculator(2025)
+	calc.LoadYear(2026)
+
+	if calc.IsBusinessDay(time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("2026-01-01 should be a holiday after loading 2026")
+	}
+}